@@ -12,6 +12,7 @@ import (
 	"github.com/starter-kit-fe/admin/constant"
 	"github.com/starter-kit-fe/admin/internal/config"
 	jobsvc "github.com/starter-kit-fe/admin/internal/system/job/service"
+	"github.com/starter-kit-fe/admin/internal/system/settings"
 )
 
 type Options struct {
@@ -20,12 +21,13 @@ type Options struct {
 
 type App struct {
 	// 配置及服务依赖，生命周期绑定在 App 内
-	cfg    *config.Config
-	logger *slog.Logger
-	server *http.Server
-	db     *gorm.DB
-	cache  *redis.Client
-	jobs   *jobsvc.Service
+	cfg      *config.Config
+	logger   *slog.Logger
+	server   *http.Server
+	db       *gorm.DB
+	cache    *redis.Client
+	jobs     *jobsvc.Service
+	settings *settings.Service
 }
 
 func New(ctx context.Context, opts Options) (*App, error) {
@@ -49,18 +51,35 @@ func New(ctx context.Context, opts Options) (*App, error) {
 		return nil, err
 	}
 
-	modules := buildModuleSet(cfg, sqlDB, redisCache, appLogger)
-	throttleMW := buildThrottle(cfg, appLogger)
-	engine := buildRouterEngine(cfg, appLogger, modules, throttleMW)
+	fileStorage, err := initStorage(cfg, appLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring, err := initConfigKeyring(cfg, appLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := buildModuleSet(cfg, sqlDB, redisCache, fileStorage, keyring, appLogger)
+	throttle := buildThrottle(cfg, appLogger)
+	watchRateLimit(cfg, modules.settingsService, throttle, appLogger)
+	engine := buildRouterEngine(cfg, appLogger, modules, throttle.Handler())
 	server := buildHTTPServer(cfg, engine)
 
 	appInstance := &App{
-		cfg:    cfg,
-		logger: appLogger,
-		server: server,
-		db:     sqlDB,
-		cache:  redisCache,
-		jobs:   modules.jobService,
+		cfg:      cfg,
+		logger:   appLogger,
+		server:   server,
+		db:       sqlDB,
+		cache:    redisCache,
+		jobs:     modules.jobService,
+		settings: modules.settingsService,
+	}
+
+	if err := appInstance.settings.Start(ctx); err != nil {
+		appInstance.closeResources()
+		return nil, err
 	}
 
 	if appInstance.jobs != nil {
@@ -132,6 +151,7 @@ func (a *App) closeResources() {
 	if a.jobs != nil {
 		a.jobs.Stop()
 	}
+	a.settings.Stop()
 	// 关闭数据库连接池
 	if a.db != nil {
 		if raw, err := a.db.DB(); err == nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/starter-kit-fe/admin/internal/system/loginlog"
 	"github.com/starter-kit-fe/admin/internal/system/operlog"
 	"github.com/starter-kit-fe/admin/internal/system/user"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
 )
 
 type operationLoggerAdapter struct {
@@ -74,6 +75,8 @@ func (r userResolverAdapter) Resolve(ctx context.Context, userID uint) (*audit.U
 	if r.repo == nil {
 		return nil, nil
 	}
+	// 操作人可能是切换进当前租户的平台管理员
+	ctx = tenant.WithoutScope(ctx)
 	record, err := r.repo.GetUser(ctx, int64(userID))
 	if err != nil {
 		return nil, err
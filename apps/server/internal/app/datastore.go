@@ -40,6 +40,9 @@ func initDatabase(ctx context.Context, cfg *config.Config, logger *slog.Logger)
 	if err := db.SeedDefaults(ctx, sqlDB, logger); err != nil {
 		return nil, fmt.Errorf("seed defaults: %w", err)
 	}
+	if err := db.RegisterTenantScope(sqlDB); err != nil {
+		return nil, fmt.Errorf("register tenant scope: %w", err)
+	}
 	logger.Info("connected to postgres")
 
 	return sqlDB, nil
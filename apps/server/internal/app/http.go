@@ -57,13 +57,22 @@ func buildRouterEngine(cfg *config.Config, logger *slog.Logger, modules moduleSe
 		DocsHandler:        modules.docsHandler,
 		AuthHandler:        modules.authHandler,
 		UserHandler:        modules.userHandler,
+		UserAttrHandler:    modules.userAttrHandler,
 		RoleHandler:        modules.roleHandler,
 		MenuHandler:        modules.menuHandler,
 		DeptHandler:        modules.deptHandler,
 		PostHandler:        modules.postHandler,
 		DictHandler:        modules.dictHandler,
+		I18nHandler:        modules.i18nHandler,
 		ConfigHandler:      modules.configHandler,
 		NoticeHandler:      modules.noticeHandler,
+		PermissionHandler:  modules.permissionHandler,
+		FileHandler:        modules.fileHandler,
+		RecycleHandler:     modules.recycleHandler,
+		ManifestHandler:    modules.manifestHandler,
+		SCIMHandler:        modules.scimHandler,
+		TenantHandler:      modules.tenantHandler,
+		HistoryHandler:     modules.historyHandler,
 		OperLogHandler:     modules.operLogHandler,
 		LoginLogHandler:    modules.loginLogHandler,
 		JobHandler:         modules.jobHandler,
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	sysconfig "github.com/starter-kit-fe/admin/internal/system/config"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+	"github.com/starter-kit-fe/admin/internal/system/history"
+	"github.com/starter-kit-fe/admin/internal/system/manifest"
+	"github.com/starter-kit-fe/admin/internal/system/settings"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+// ManageManifest 仅初始化数据库连接，将权限清单服务交给回调，用于命令行导出与同步清单。
+func ManageManifest(ctx context.Context, opts Options, fn func(context.Context, *manifest.Service) error) error {
+	if opts.Config == nil {
+		return errors.New("config is required")
+	}
+	if fn == nil {
+		return errors.New("manifest callback is required")
+	}
+	ctx = ensureContext(ctx)
+
+	cfg := opts.Config
+	cfg.Normalize()
+
+	appLogger := setupLogger(cfg)
+
+	sqlDB, err := initDatabase(ctx, cfg, appLogger)
+	if err != nil {
+		return err
+	}
+	if sqlDB == nil {
+		return errors.New("database is not configured")
+	}
+
+	// 同步后经 Redis 清除参数与字典缓存并通知运行中的服务
+	redisCache, err := initCache(ctx, cfg, appLogger)
+	if err != nil {
+		return err
+	}
+
+	appInstance := &App{cfg: cfg, logger: appLogger, db: sqlDB, cache: redisCache}
+	defer appInstance.closeResources()
+
+	keyring, err := initConfigKeyring(cfg, appLogger)
+	if err != nil {
+		return err
+	}
+
+	historySvc := history.NewService(history.NewRepository(sqlDB), appLogger)
+	settingsSvc := settings.NewService(settings.NewRepository(sqlDB), redisCache, settings.Options{Logger: appLogger, Keyring: keyring})
+	configSvc := sysconfig.NewService(sysconfig.NewRepository(sqlDB), historySvc, settingsSvc, redisCache, keyring)
+	dictSvc := dict.NewService(dict.NewRepository(sqlDB), historySvc, redisCache)
+
+	// 命令行清单操作作用于默认租户的角色、字典与参数
+	return fn(tenant.WithID(ctx, tenant.DefaultID), manifest.NewService(manifest.NewRepository(sqlDB), configSvc, dictSvc))
+}
@@ -16,54 +16,77 @@ import (
 	"github.com/starter-kit-fe/admin/internal/system/dept"
 	"github.com/starter-kit-fe/admin/internal/system/dict"
 	"github.com/starter-kit-fe/admin/internal/system/docs"
+	"github.com/starter-kit-fe/admin/internal/system/file"
 	"github.com/starter-kit-fe/admin/internal/system/health"
+	"github.com/starter-kit-fe/admin/internal/system/history"
+	"github.com/starter-kit-fe/admin/internal/system/i18n"
 	jobexec    "github.com/starter-kit-fe/admin/internal/system/job/executor"
 	jobhandler "github.com/starter-kit-fe/admin/internal/system/job/handler"
 	jobrepo    "github.com/starter-kit-fe/admin/internal/system/job/repository"
 	jobsvc     "github.com/starter-kit-fe/admin/internal/system/job/service"
 	"github.com/starter-kit-fe/admin/internal/system/loginlog"
+	"github.com/starter-kit-fe/admin/internal/system/manifest"
 	"github.com/starter-kit-fe/admin/internal/system/menu"
 	"github.com/starter-kit-fe/admin/internal/system/notice"
 	"github.com/starter-kit-fe/admin/internal/system/online"
 	"github.com/starter-kit-fe/admin/internal/system/operlog"
+	"github.com/starter-kit-fe/admin/internal/system/permission"
 	"github.com/starter-kit-fe/admin/internal/system/post"
+	"github.com/starter-kit-fe/admin/internal/system/recycle"
 	"github.com/starter-kit-fe/admin/internal/system/role"
+	"github.com/starter-kit-fe/admin/internal/system/scim"
 	"github.com/starter-kit-fe/admin/internal/system/server"
+	"github.com/starter-kit-fe/admin/internal/system/settings"
+	"github.com/starter-kit-fe/admin/internal/system/tenant"
 	"github.com/starter-kit-fe/admin/internal/system/user"
+	"github.com/starter-kit-fe/admin/internal/system/userattr"
+	"github.com/starter-kit-fe/admin/pkg/envelope"
+	"github.com/starter-kit-fe/admin/pkg/storage"
 )
 
 type moduleSet struct {
-	healthHandler   *health.Handler
-	docsHandler     *docs.Handler
-	captchaHandler  *captcha.Handler
-	authHandler     *auth.Handler
-	userHandler     *user.Handler
-	roleHandler     *role.Handler
-	menuHandler     *menu.Handler
-	deptHandler     *dept.Handler
-	postHandler     *post.Handler
-	dictHandler     *dict.Handler
-	configHandler   *sysconfig.Handler
-	noticeHandler   *notice.Handler
-	operLogHandler  *operlog.Handler
-	loginLogHandler *loginlog.Handler
-	operLogService  *operlog.Service
-	loginLogService *loginlog.Service
-	jobHandler      *jobhandler.Handler
-	jobService      *jobsvc.Service
-	onlineHandler   *online.Handler
-	onlineService   *online.Service
-	serverHandler   *server.Handler
-	serverService   *server.Service
-	cacheHandler    *cache.Handler
-	cacheService    *cache.Service
-	userRepo        *user.Repository
+	healthHandler     *health.Handler
+	docsHandler       *docs.Handler
+	captchaHandler    *captcha.Handler
+	authHandler       *auth.Handler
+	userHandler       *user.Handler
+	userAttrHandler   *userattr.Handler
+	roleHandler       *role.Handler
+	menuHandler       *menu.Handler
+	deptHandler       *dept.Handler
+	postHandler       *post.Handler
+	dictHandler       *dict.Handler
+	i18nHandler       *i18n.Handler
+	configHandler     *sysconfig.Handler
+	noticeHandler     *notice.Handler
+	permissionHandler *permission.Handler
+	permissionService *permission.Service
+	fileHandler       *file.Handler
+	recycleHandler    *recycle.Handler
+	manifestHandler   *manifest.Handler
+	scimHandler       *scim.Handler
+	tenantHandler     *tenant.Handler
+	historyHandler    *history.Handler
+	operLogHandler    *operlog.Handler
+	loginLogHandler   *loginlog.Handler
+	operLogService    *operlog.Service
+	loginLogService   *loginlog.Service
+	jobHandler        *jobhandler.Handler
+	jobService        *jobsvc.Service
+	onlineHandler     *online.Handler
+	onlineService     *online.Service
+	serverHandler     *server.Handler
+	serverService     *server.Service
+	cacheHandler      *cache.Handler
+	cacheService      *cache.Service
+	settingsService   *settings.Service
+	userRepo          *user.Repository
 
 	permissionProvider middleware.PermissionProvider
 	sessionValidator   middleware.SessionValidator
 }
 
-func buildModuleSet(cfg *config.Config, sqlDB *gorm.DB, redisCache *redis.Client, logger *slog.Logger) moduleSet {
+func buildModuleSet(cfg *config.Config, sqlDB *gorm.DB, redisCache *redis.Client, fileStorage storage.Driver, keyring *envelope.Keyring, logger *slog.Logger) moduleSet {
 	healthSvc := health.New(sqlDB, redisCache)
 	healthHandler := health.NewHandler(healthSvc)
 
@@ -75,6 +98,10 @@ func buildModuleSet(cfg *config.Config, sqlDB *gorm.DB, redisCache *redis.Client
 	})
 	captchaHandler := captcha.NewHandler(captchaSvc)
 
+	// 运行时参数读取 sys_config，修改后经 Redis 通知所有副本
+	settingsRepo := settings.NewRepository(sqlDB)
+	settingsSvc := settings.NewService(settingsRepo, redisCache, settings.Options{Logger: logger, Keyring: keyring})
+
 	authRepo := auth.NewRepository(sqlDB)
 	sessionStore := auth.NewSessionStore(redisCache, auth.SessionStoreOptions{
 		KeyPrefix:      "auth",
@@ -126,6 +153,11 @@ func buildModuleSet(cfg *config.Config, sqlDB *gorm.DB, redisCache *redis.Client
 	cacheSvc := cache.NewService(redisCache)
 	cacheHandler := cache.NewHandler(cacheSvc)
 
+	// 多语言译文用于菜单名称与字典标签
+	i18nRepo := i18n.NewRepository(sqlDB)
+	i18nSvc := i18n.NewService(i18nRepo, redisCache)
+	i18nHandler := i18n.NewHandler(i18nSvc)
+
 	authHandler := auth.NewHandler(authRepo, captchaSvc, auth.AuthOptions{
 		Secret:          cfg.Auth.Secret,
 		TokenDuration:   cfg.Auth.TokenDuration,
@@ -138,35 +170,66 @@ func buildModuleSet(cfg *config.Config, sqlDB *gorm.DB, redisCache *redis.Client
 		CookieSecure:    cfg.Auth.CookieSecure,
 		CookieHTTPOnly:  cfg.Auth.CookieHTTPOnly,
 		CookieSameSite:  cfg.Auth.CookieSameSite,
-	}, onlineSvc, sessionStore)
+	}, onlineSvc, sessionStore, settingsSvc, i18nSvc)
+
+	fileRepo := file.NewRepository(sqlDB)
+	fileSvc := file.NewService(fileRepo, fileStorage, file.ServiceOptions{
+		MaxSize:       int64(cfg.Storage.MaxSizeMB) << 20,
+		AllowedTypes:  cfg.Storage.AllowedTypes,
+		PresignTTL:    cfg.Storage.PresignTTL,
+		SigningKey:    cfg.Auth.Secret,
+		SignedURLPath: "/api/v1/files",
+		PublicModules: []string{user.AvatarModule},
+		PublicURLPath: "/api/v1/public/files",
+	})
+	fileHandler := file.NewHandler(fileSvc)
+
+	// 变更历史由各系统模块在写操作后记录
+	historyRepo := history.NewRepository(sqlDB)
+	historySvc := history.NewService(historyRepo, logger)
+	historyHandler := history.NewHandler(historySvc)
+
+	// 字典服务同时为各模块提供状态、类型等取值校验
+	dictRepo := dict.NewRepository(sqlDB)
+	dictSvc := dict.NewService(dictRepo, historySvc, redisCache)
+	dictHandler := dict.NewHandler(dictSvc, i18nSvc)
+
+	userAttrRepo := userattr.NewRepository(sqlDB)
+	userAttrSvc := userattr.NewService(userAttrRepo)
+	userAttrHandler := userattr.NewHandler(userAttrSvc)
 
 	userRepo := user.NewRepository(sqlDB)
-	userSvc := user.NewService(userRepo)
-	userHandler := user.NewHandler(userSvc, onlineSvc)
+	userSvc := user.NewService(userRepo, fileSvc, authRepo, userAttrSvc, historySvc, dictSvc)
+	userHandler := user.NewHandler(userSvc, onlineSvc, i18nSvc)
 
 	menuRepo := menu.NewRepository(sqlDB)
-	menuSvc := menu.NewService(menuRepo)
+	menuSvc := menu.NewService(menuRepo, historySvc)
 	menuHandler := menu.NewHandler(menuSvc)
 
 	deptRepo := dept.NewRepository(sqlDB)
-	deptSvc := dept.NewService(deptRepo)
+	deptSvc := dept.NewService(deptRepo, historySvc, dictSvc)
 	deptHandler := dept.NewHandler(deptSvc)
 
 	postRepo := post.NewRepository(sqlDB)
-	postSvc := post.NewService(postRepo)
+	postSvc := post.NewService(postRepo, dictSvc)
 	postHandler := post.NewHandler(postSvc)
 
-	dictRepo := dict.NewRepository(sqlDB)
-	dictSvc := dict.NewService(dictRepo)
-	dictHandler := dict.NewHandler(dictSvc)
-
 	configRepo := sysconfig.NewRepository(sqlDB)
-	configSvc := sysconfig.NewService(configRepo)
+	configSvc := sysconfig.NewService(configRepo, historySvc, settingsSvc, redisCache, keyring)
 	configHandler := sysconfig.NewHandler(configSvc)
 
 	noticeRepo := notice.NewRepository(sqlDB)
-	noticeSvc := notice.NewService(noticeRepo)
+	noticeSvc := notice.NewService(noticeRepo, dictSvc, authRepo)
 	noticeHandler := notice.NewHandler(noticeSvc)
+	if noticeSvc != nil {
+		if err := jobSvc.RegisterExecutorWithDesc(
+			"notice.schedule",
+			"公告定时发布与过期",
+			jobexec.NewNoticeScheduleExecutor(noticeSvc),
+		); err != nil {
+			logger.Error("register notice schedule executor failed", "error", err)
+		}
+	}
 
 	operLogRepo := operlog.NewRepository(sqlDB)
 	operLogSvc := operlog.NewService(operLogRepo)
@@ -177,22 +240,66 @@ func buildModuleSet(cfg *config.Config, sqlDB *gorm.DB, redisCache *redis.Client
 	loginLogHandler := loginlog.NewHandler(loginLogSvc)
 
 	roleRepo := role.NewRepository(sqlDB)
-	roleSvc := role.NewService(roleRepo, menuRepo)
+	roleSvc := role.NewService(roleRepo, menuRepo, historySvc, dictSvc)
 	roleHandler := role.NewHandler(roleSvc)
 
+	permissionRepo := permission.NewRepository(sqlDB)
+	permissionSvc := permission.NewService(permissionRepo, routeRegistryAdapter{})
+	permissionHandler := permission.NewHandler(permissionSvc)
+
+	recycleRepo := recycle.NewRepository(sqlDB)
+	recycleSvc := recycle.NewService(recycleRepo)
+	// 恢复或彻底删除参数、字典后清除运行时参数与字典选项缓存
+	recycleSvc.OnChange("config", configSvc.InvalidateKeys)
+	recycleSvc.OnChange("dict_type", dictSvc.InvalidateOptions)
+	recycleSvc.OnChange("dict_data", dictSvc.InvalidateOptions)
+	recycleHandler := recycle.NewHandler(recycleSvc)
+	if recycleSvc != nil {
+		if err := jobSvc.RegisterExecutorWithDesc(
+			"recycle.purge",
+			"回收站清理",
+			jobexec.NewRecyclePurgeExecutor(recycleSvc),
+		); err != nil {
+			logger.Error("register recycle purge executor failed", "error", err)
+		}
+	}
+
+	manifestRepo := manifest.NewRepository(sqlDB)
+	manifestSvc := manifest.NewService(manifestRepo, configSvc, dictSvc)
+	manifestHandler := manifest.NewHandler(manifestSvc)
+
+	// 未配置 SCIM 令牌时 scimHandler 为 nil，接口不对外开放
+	scimRepo := scim.NewRepository(sqlDB)
+	scimSvc := scim.NewService(scimRepo, userSvc, roleSvc, sessionStore)
+	scimHandler := scim.NewHandler(scimSvc, cfg.SCIM.Tokens)
+
+	tenantRepo := tenant.NewRepository(sqlDB)
+	tenantSvc := tenant.NewService(tenantRepo)
+	tenantHandler := tenant.NewHandler(tenantSvc)
+
 	return moduleSet{
 		healthHandler:      healthHandler,
 		docsHandler:        docsHandler,
 		captchaHandler:     captchaHandler,
 		authHandler:        authHandler,
 		userHandler:        userHandler,
+		userAttrHandler:    userAttrHandler,
 		roleHandler:        roleHandler,
 		menuHandler:        menuHandler,
 		deptHandler:        deptHandler,
 		postHandler:        postHandler,
 		dictHandler:        dictHandler,
+		i18nHandler:        i18nHandler,
 		configHandler:      configHandler,
 		noticeHandler:      noticeHandler,
+		permissionHandler:  permissionHandler,
+		permissionService:  permissionSvc,
+		fileHandler:        fileHandler,
+		recycleHandler:     recycleHandler,
+		manifestHandler:    manifestHandler,
+		scimHandler:        scimHandler,
+		tenantHandler:      tenantHandler,
+		historyHandler:     historyHandler,
 		operLogHandler:     operLogHandler,
 		operLogService:     operLogSvc,
 		loginLogHandler:    loginLogHandler,
@@ -205,6 +312,7 @@ func buildModuleSet(cfg *config.Config, sqlDB *gorm.DB, redisCache *redis.Client
 		serverService:      serverSvc,
 		cacheHandler:       cacheHandler,
 		cacheService:       cacheSvc,
+		settingsService:    settingsSvc,
 		userRepo:           userRepo,
 		permissionProvider: authRepo,
 		sessionValidator:   newSessionValidator(sessionStore, onlineSvc),
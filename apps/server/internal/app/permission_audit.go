@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/starter-kit-fe/admin/internal/config"
+	"github.com/starter-kit-fe/admin/internal/router"
+	"github.com/starter-kit-fe/admin/internal/system/permission"
+)
+
+type routeRegistryAdapter struct{}
+
+func (routeRegistryAdapter) Routes() []permission.Route {
+	defs := router.RegisteredRoutes()
+	routes := make([]permission.Route, 0, len(defs))
+	for _, def := range defs {
+		routes = append(routes, permission.Route{
+			Method:      def.Method,
+			Path:        def.Path,
+			Permissions: def.Permissions,
+			Description: def.Description,
+		})
+	}
+	return routes
+}
+
+// InspectPermissions 初始化依赖并构建路由（不启动 HTTP 服务与任务调度），
+// 将填充好路由注册表的权限服务交给回调，用于命令行查看与核对权限。
+func InspectPermissions(ctx context.Context, opts Options, fn func(context.Context, *permission.Service) error) error {
+	if opts.Config == nil {
+		return errors.New("config is required")
+	}
+	if fn == nil {
+		return errors.New("inspect callback is required")
+	}
+	ctx = ensureContext(ctx)
+
+	cfg := opts.Config
+	cfg.Normalize()
+
+	appLogger := setupLogger(cfg)
+
+	sqlDB, err := initDatabase(ctx, cfg, appLogger)
+	if err != nil {
+		return err
+	}
+	if sqlDB == nil {
+		return errors.New("database is not configured")
+	}
+
+	redisCache, err := initCache(ctx, cfg, appLogger)
+	if err != nil {
+		return err
+	}
+
+	appInstance := &App{cfg: cfg, logger: appLogger, db: sqlDB, cache: redisCache}
+	defer appInstance.closeResources()
+
+	fileStorage, err := initStorage(cfg, appLogger)
+	if err != nil {
+		return err
+	}
+
+	keyring, err := initConfigKeyring(cfg, appLogger)
+	if err != nil {
+		return err
+	}
+
+	modules := buildModuleSet(cfg, sqlDB, redisCache, fileStorage, keyring, appLogger)
+	if err := buildRoutes(cfg, appLogger, modules); err != nil {
+		return err
+	}
+
+	return fn(ctx, modules.permissionService)
+}
+
+// buildRoutes 构建路由引擎以填充路由注册表，缺少依赖的处理器会触发 panic
+func buildRoutes(cfg *config.Config, logger *slog.Logger, modules moduleSet) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("build routes: %v", recovered)
+		}
+	}()
+	buildRouterEngine(cfg, logger, modules, nil)
+	return nil
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/starter-kit-fe/admin/internal/config"
+	sysconfig "github.com/starter-kit-fe/admin/internal/system/config"
+	"github.com/starter-kit-fe/admin/pkg/envelope"
+)
+
+// initConfigKeyring 解析 secret 参数的加密密钥，未配置时不支持 secret 类型参数
+func initConfigKeyring(cfg *config.Config, logger *slog.Logger) (*envelope.Keyring, error) {
+	if len(cfg.Security.ConfigKeys) == 0 {
+		logger.Info("config encryption keys not configured, secret configs are disabled")
+		return nil, nil
+	}
+	keyring, err := envelope.ParseKeyring(cfg.Security.ConfigKeys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SECURITY_CONFIG_KEYS: %w", err)
+	}
+	return keyring, nil
+}
+
+// RotateConfigSecrets 使用当前首个密钥重新封装所有租户 secret 参数的数据密钥，返回更新的参数数量。
+func RotateConfigSecrets(ctx context.Context, opts Options) (int, error) {
+	if opts.Config == nil {
+		return 0, errors.New("config is required")
+	}
+	ctx = ensureContext(ctx)
+
+	cfg := opts.Config
+	cfg.Normalize()
+
+	appLogger := setupLogger(cfg)
+
+	keyring, err := initConfigKeyring(cfg, appLogger)
+	if err != nil {
+		return 0, err
+	}
+	if keyring == nil {
+		return 0, errors.New("SECURITY_CONFIG_KEYS is not configured")
+	}
+
+	sqlDB, err := initDatabase(ctx, cfg, appLogger)
+	if err != nil {
+		return 0, err
+	}
+	if sqlDB == nil {
+		return 0, errors.New("database is not configured")
+	}
+
+	appInstance := &App{cfg: cfg, logger: appLogger, db: sqlDB}
+	defer appInstance.closeResources()
+
+	svc := sysconfig.NewService(sysconfig.NewRepository(sqlDB), nil, nil, nil, keyring)
+	return svc.RotateSecrets(ctx)
+}
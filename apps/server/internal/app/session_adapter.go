@@ -28,7 +28,7 @@ func (a *sessionValidatorAdapter) GetSession(ctx context.Context, sessionID stri
 	if err != nil {
 		return middleware.SessionMetadata{}, err
 	}
-	return middleware.SessionMetadata{UserID: session.UserID, Revoked: session.Revoked}, nil
+	return middleware.SessionMetadata{UserID: session.UserID, TenantID: session.TenantID, Revoked: session.Revoked}, nil
 }
 
 func (a *sessionValidatorAdapter) UpdateLastSeen(ctx context.Context, sessionID string) error {
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/starter-kit-fe/admin/internal/config"
+	"github.com/starter-kit-fe/admin/pkg/storage"
+)
+
+// initStorage 根据配置创建文件存储驱动，S3 驱动复用备份任务的 S3 配置
+func initStorage(cfg *config.Config, logger *slog.Logger) (storage.Driver, error) {
+	switch cfg.Storage.Driver {
+	case storage.DriverS3:
+		driver, err := storage.NewS3Driver(storage.S3Options{
+			Endpoint:     cfg.S3.Endpoint,
+			AccessKey:    cfg.S3.AccessKey,
+			SecretKey:    cfg.S3.SecretKey,
+			Bucket:       cfg.S3.Bucket,
+			Region:       cfg.S3.Region,
+			UsePathStyle: cfg.S3.UsePathStyle,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("init s3 storage: %w", err)
+		}
+		logger.Info("file storage ready", "driver", driver.Name(), "bucket", cfg.S3.Bucket)
+		return driver, nil
+	default:
+		driver, err := storage.NewLocalDriver(cfg.Storage.LocalDir)
+		if err != nil {
+			return nil, fmt.Errorf("init local storage: %w", err)
+		}
+		logger.Info("file storage ready", "driver", driver.Name(), "dir", cfg.Storage.LocalDir)
+		return driver, nil
+	}
+}
@@ -1,19 +1,79 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"strconv"
+	"strings"
 
-	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
 
 	"github.com/starter-kit-fe/admin/internal/config"
+	"github.com/starter-kit-fe/admin/internal/system/settings"
 	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
 )
 
-func buildThrottle(cfg *config.Config, logger *slog.Logger) gin.HandlerFunc {
+// rateLimitSettingKey 以 "次数/周期" 覆盖配置文件中的限流阈值，例如 "100/1m"，留空时使用配置文件；
+// 阈值在限流器构造时复制进内存，因此必须订阅变更才能在不重启的情况下生效
+const rateLimitSettingKey = "sys.security.rateLimit"
+
+func buildThrottle(cfg *config.Config, logger *slog.Logger) *middleware.Throttle {
+	limit, burst := fileRateLimit(cfg)
+	return middleware.NewThrottle(limit, burst, nil, logger)
+}
+
+func fileRateLimit(cfg *config.Config) (rate.Limit, int) {
 	var limit rate.Limit
 	if cfg.Security.RateLimit.Requests > 0 && cfg.Security.RateLimit.Period > 0 {
 		limit = rate.Limit(float64(cfg.Security.RateLimit.Requests) / cfg.Security.RateLimit.Period.Seconds())
 	}
-	return middleware.NewThrottleMiddleware(limit, cfg.Security.RateLimit.Burst, nil, logger)
+	return limit, cfg.Security.RateLimit.Burst
+}
+
+// watchRateLimit 应用平台租户的限流参数，并在参数修改后即时调整
+func watchRateLimit(cfg *config.Config, settingsSvc *settings.Service, throttle *middleware.Throttle, logger *slog.Logger) {
+	if settingsSvc == nil || throttle == nil {
+		return
+	}
+
+	apply := func(ctx context.Context) {
+		limit, burst := fileRateLimit(cfg)
+		if raw := settingsSvc.GetString(ctx, rateLimitSettingKey, ""); raw != "" {
+			parsedLimit, parsedBurst, err := parseRateLimit(raw)
+			if err != nil {
+				logger.Warn("invalid rate limit setting, using config file", "value", raw, "error", err)
+			} else {
+				limit, burst = parsedLimit, parsedBurst
+			}
+		}
+		throttle.SetLimit(limit, burst)
+	}
+
+	settingsSvc.Subscribe(rateLimitSettingKey, func(ctx context.Context, change settings.Change) {
+		// 限流器为全局共享，只接受平台租户的参数
+		if change.TenantID != tenant.DefaultID {
+			return
+		}
+		apply(ctx)
+	})
+	apply(tenant.WithID(context.Background(), tenant.DefaultID))
+}
+
+// parseRateLimit 解析 "次数/周期"，突发上限与次数相同
+func parseRateLimit(value string) (rate.Limit, int, error) {
+	countPart, periodPart, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, 0, errors.New("expected requests/period")
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(countPart))
+	if err != nil || requests <= 0 {
+		return 0, 0, errors.New("requests must be a positive integer")
+	}
+	period, err := settings.ParseDuration(periodPart)
+	if err != nil || period <= 0 {
+		return 0, 0, errors.New("period must be a positive duration")
+	}
+	return rate.Limit(float64(requests) / period.Seconds()), requests, nil
 }
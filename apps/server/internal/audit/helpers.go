@@ -2,14 +2,28 @@ package audit
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/starter-kit-fe/admin/pkg/tenant"
 )
 
+// detachedContext 脱离请求生命周期供异步写日志使用，只保留请求所属租户
+func detachedContext(ctx *gin.Context) context.Context {
+	if ctx == nil || ctx.Request == nil {
+		return context.Background()
+	}
+	if tenantID, ok := tenant.FromContext(ctx.Request.Context()); ok {
+		return tenant.WithID(context.Background(), tenantID)
+	}
+	return context.Background()
+}
+
 func shortHandlerName(name string) string {
 	if name == "" {
 		return ""
@@ -44,6 +58,10 @@ func isMutationMethod(method string) bool {
 	}
 }
 
+func isMultipartRequest(req *http.Request) bool {
+	return strings.HasPrefix(strings.ToLower(req.Header.Get("Content-Type")), "multipart/")
+}
+
 func mapMethodToBusinessType(method string) int {
 	switch strings.ToUpper(method) {
 	case http.MethodPost:
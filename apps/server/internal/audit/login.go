@@ -1,7 +1,6 @@
 package audit
 
 import (
-	"context"
 	"net/http"
 	"time"
 
@@ -55,8 +54,9 @@ func NewLoginMiddleware(logger LoginLogger, opts LoginOptions) gin.HandlerFunc {
 			OccurredAt: unixMillis(time.Now()),
 		}
 
+		logCtx := detachedContext(ctx)
 		go func(payload LoginEntry) {
-			if err := logger.RecordLogin(context.Background(), payload); err != nil && slogger != nil {
+			if err := logger.RecordLogin(logCtx, payload); err != nil && slogger != nil {
 				slogger.Error("record login log failed", "error", err)
 			}
 		}(entry)
@@ -1,7 +1,7 @@
 package audit
 
 import (
-	"context"
+	"bytes"
 	"net/http"
 	"time"
 
@@ -44,7 +44,11 @@ func NewOperationMiddleware(logger OperationLogger, resolver UserResolver, opts
 			}
 		}
 
-		bodyBuf := attachBodyRecorder(ctx.Request, bodyLimit)
+		// 文件上传的二进制内容不写入操作日志
+		var bodyBuf *bytes.Buffer
+		if !isMultipartRequest(ctx.Request) {
+			bodyBuf = attachBodyRecorder(ctx.Request, bodyLimit)
+		}
 		recorder := newResponseRecorder(ctx.Writer, resultLimit)
 		ctx.Writer = recorder
 
@@ -72,7 +76,7 @@ func NewOperationMiddleware(logger OperationLogger, resolver UserResolver, opts
 			IP:            clientIP,
 			Status:        status,
 			ErrorMessage:  deriveErrorMessage(ctx),
-			RequestBody:   bufferString(bodyBuf),
+			RequestBody:   redactBody(ctx, bufferString(bodyBuf)),
 			ResponseBody:  recorder.String(),
 			CostMillis:    duration.Milliseconds(),
 			OccurredAt:    unixMillis(time.Now()),
@@ -82,8 +86,9 @@ func NewOperationMiddleware(logger OperationLogger, resolver UserResolver, opts
 			entry.DeptName = identity.DeptName
 		}
 
+		logCtx := detachedContext(ctx)
 		go func(payload OperationEntry) {
-			if err := logger.RecordOperation(context.Background(), payload); err != nil && slogger != nil {
+			if err := logger.RecordOperation(logCtx, payload); err != nil && slogger != nil {
 				slogger.Error("record operation log failed", "error", err, "path", payload.URL)
 			}
 		}(entry)
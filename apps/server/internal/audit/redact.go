@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	redactedFieldsKey = "audit.redactedFields"
+	redactedValue     = "******"
+)
+
+// Redact 标记请求体中不得写入操作日志的字段，由处理器在读取请求体后调用
+func Redact(ctx *gin.Context, fields ...string) {
+	if ctx == nil || len(fields) == 0 {
+		return
+	}
+	existing, _ := ctx.Get(redactedFieldsKey)
+	list, _ := existing.([]string)
+	ctx.Set(redactedFieldsKey, append(list, fields...))
+}
+
+// redactBody 替换请求体顶层的敏感字段；请求体被截断或不是 JSON 对象时无法定位字段，整体隐去
+func redactBody(ctx *gin.Context, body string) string {
+	value, ok := ctx.Get(redactedFieldsKey)
+	fields, _ := value.([]string)
+	if !ok || len(fields) == 0 || body == "" {
+		return body
+	}
+
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		return redactedValue
+	}
+	masked, _ := json.Marshal(redactedValue)
+	for _, field := range fields {
+		if _, exists := payload[field]; exists {
+			payload[field] = masked
+		}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return redactedValue
+	}
+	return string(data)
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/starter-kit-fe/admin/internal/app"
+	"github.com/starter-kit-fe/admin/internal/config"
+	"github.com/starter-kit-fe/admin/internal/system/manifest"
+)
+
+// ErrManifestDrift 表示数据库与清单存在差异
+var ErrManifestDrift = errors.New("database does not match the manifest")
+
+func NewManifestCommand(rootOpts *RootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Export or sync menus, roles, dictionaries and configs as code",
+	}
+
+	cmd.AddCommand(newManifestExportCommand(rootOpts))
+	cmd.AddCommand(newManifestSyncCommand(rootOpts))
+
+	return cmd
+}
+
+func newManifestExportCommand(rootOpts *RootOptions) *cobra.Command {
+	var (
+		format string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the current database as a manifest",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// 未指定格式时根据输出文件扩展名推断，默认 YAML
+			if format == "" && strings.HasSuffix(strings.ToLower(output), ".json") {
+				format = manifest.FormatJSON
+			}
+			if format == "" {
+				format = manifest.FormatYAML
+			}
+			if _, err := manifest.NormalizeFormat(format); err != nil {
+				return err
+			}
+
+			return runManifest(cmd, rootOpts, func(ctx context.Context, svc *manifest.Service) error {
+				m, err := svc.Export(ctx)
+				if err != nil {
+					return err
+				}
+				data, err := manifest.Encode(m, format)
+				if err != nil {
+					return err
+				}
+				if output == "" || output == "-" {
+					_, err = cmd.OutOrStdout().Write(data)
+					return err
+				}
+				return os.WriteFile(output, data, 0o644)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "Manifest format: yaml or json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the manifest to this file instead of stdout")
+
+	return cmd
+}
+
+func newManifestSyncCommand(rootOpts *RootOptions) *cobra.Command {
+	var (
+		file       string
+		format     string
+		dryRun     bool
+		prune      bool
+		strict     bool
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Diff a manifest against the database and apply the plan in one transaction",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				return errors.New("--file is required")
+			}
+
+			var (
+				data []byte
+				err  error
+			)
+			if file == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(file)
+			}
+			if err != nil {
+				return fmt.Errorf("read manifest: %w", err)
+			}
+			if format == "" && strings.HasSuffix(strings.ToLower(file), ".json") {
+				format = manifest.FormatJSON
+			}
+			m, err := manifest.Decode(data, format)
+			if err != nil {
+				return err
+			}
+
+			return runManifest(cmd, rootOpts, func(ctx context.Context, svc *manifest.Service) error {
+				plan, err := svc.Sync(ctx, m, manifest.SyncOptions{DryRun: dryRun, Prune: prune})
+				if err != nil {
+					return err
+				}
+
+				out := cmd.OutOrStdout()
+				if jsonOutput {
+					if err := writeJSON(out, plan); err != nil {
+						return err
+					}
+				} else {
+					printManifestPlan(out, plan)
+				}
+
+				// --strict 配合 --dry-run 时存在差异返回非零退出码，便于在 CI 中检查漂移
+				if strict && dryRun && !plan.Empty() {
+					return ErrManifestDrift
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Manifest file to sync, - for stdin")
+	cmd.Flags().StringVar(&format, "format", "", "Manifest format: yaml or json (detected when omitted)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only print the plan without applying it")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete records not declared in the manifest sections present")
+	cmd.Flags().BoolVar(&strict, "strict", false, "With --dry-run, exit with an error when the plan is not empty")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the plan as JSON")
+
+	return cmd
+}
+
+func runManifest(cmd *cobra.Command, rootOpts *RootOptions, fn func(context.Context, *manifest.Service) error) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cfg, err := config.Load(rootOpts.EnvFiles...)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	return app.ManageManifest(ctx, app.Options{Config: cfg}, fn)
+}
+
+func printManifestPlan(out io.Writer, plan *manifest.Plan) {
+	if plan.Empty() {
+		fmt.Fprintln(out, "No changes. The database matches the manifest.")
+		return
+	}
+
+	counts := make(map[string]int, 3)
+	for _, change := range plan.Changes {
+		counts[change.Action]++
+		symbol := "~"
+		switch change.Action {
+		case manifest.ActionCreate:
+			symbol = "+"
+		case manifest.ActionDelete:
+			symbol = "-"
+		}
+		line := fmt.Sprintf("%s %s %s", symbol, change.Kind, change.Key)
+		if len(change.Fields) > 0 {
+			line += " (" + strings.Join(change.Fields, ", ") + ")"
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	status := "applied"
+	if !plan.Applied {
+		status = "not applied (dry run)"
+	}
+	fmt.Fprintf(out, "\nPlan: %d to create, %d to update, %d to delete; %s.\n",
+		counts[manifest.ActionCreate], counts[manifest.ActionUpdate], counts[manifest.ActionDelete], status)
+}
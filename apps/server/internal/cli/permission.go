@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/starter-kit-fe/admin/internal/app"
+	"github.com/starter-kit-fe/admin/internal/config"
+	"github.com/starter-kit-fe/admin/internal/system/permission"
+)
+
+// ErrPermissionDrift 表示路由权限与菜单权限不一致
+var ErrPermissionDrift = errors.New("route and menu permissions are inconsistent")
+
+func NewPermissionCommand(rootOpts *RootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "permissions",
+		Short: "Inspect route permissions and audit them against menus",
+	}
+
+	cmd.AddCommand(newPermissionRoutesCommand(rootOpts))
+	cmd.AddCommand(newPermissionAuditCommand(rootOpts))
+
+	return cmd
+}
+
+func newPermissionRoutesCommand(rootOpts *RootOptions) *cobra.Command {
+	var (
+		permissionFlag string
+		pathFlag       string
+		jsonOutput     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "routes",
+		Short: "List routes registered with permission metadata",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPermissionInspection(cmd, rootOpts, func(ctx context.Context, svc *permission.Service) error {
+				routes, err := svc.ListRoutes(ctx, permission.RouteQuery{
+					Permission: permissionFlag,
+					Path:       pathFlag,
+				})
+				if err != nil {
+					return err
+				}
+				out := cmd.OutOrStdout()
+				if jsonOutput {
+					return writeJSON(out, routes)
+				}
+
+				w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+				fmt.Fprintln(w, "METHOD\tPATH\tPERMISSIONS\tDESCRIPTION")
+				for _, route := range routes {
+					perms := strings.Join(route.Permissions, ",")
+					if perms == "" {
+						perms = "-"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", route.Method, route.Path, perms, route.Description)
+				}
+				return w.Flush()
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&permissionFlag, "permission", "", "Only list routes requiring this permission")
+	cmd.Flags().StringVar(&pathFlag, "path", "", "Only list routes whose path contains this keyword")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print output as JSON")
+
+	return cmd
+}
+
+func newPermissionAuditCommand(rootOpts *RootOptions) *cobra.Command {
+	var (
+		jsonOutput bool
+		strict     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Report drift between route permissions and menu perms",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPermissionInspection(cmd, rootOpts, func(ctx context.Context, svc *permission.Service) error {
+				report, err := svc.Audit(ctx)
+				if err != nil {
+					return err
+				}
+
+				out := cmd.OutOrStdout()
+				if jsonOutput {
+					if err := writeJSON(out, report); err != nil {
+						return err
+					}
+				} else {
+					printAuditReport(out, report)
+				}
+
+				// --strict 时存在差异返回非零退出码，便于在 CI 中使用
+				if strict && !report.Consistent() {
+					return ErrPermissionDrift
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print output as JSON")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Exit with an error when drift is detected")
+
+	return cmd
+}
+
+func runPermissionInspection(cmd *cobra.Command, rootOpts *RootOptions, fn func(context.Context, *permission.Service) error) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cfg, err := config.Load(rootOpts.EnvFiles...)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	return app.InspectPermissions(ctx, app.Options{Config: cfg}, fn)
+}
+
+func printAuditReport(out io.Writer, report *permission.AuditReport) {
+	fmt.Fprintf(out, "routes: %d, route permissions: %d, menu permissions: %d\n",
+		report.RouteCount, report.RoutePermissions, report.MenuPermissions)
+
+	fmt.Fprintf(out, "\nPermissions required by routes but missing from menus (%d):\n", len(report.MissingInMenus))
+	for _, item := range report.MissingInMenus {
+		fmt.Fprintf(out, "  %s\n", item.Permission)
+		for _, route := range item.Routes {
+			fmt.Fprintf(out, "    %s %s\n", route.Method, route.Path)
+		}
+	}
+
+	fmt.Fprintf(out, "\nMenu permissions protecting no route (%d):\n", len(report.UnusedByRoutes))
+	for _, item := range report.UnusedByRoutes {
+		fmt.Fprintf(out, "  %s\n", item.Permission)
+		for _, menu := range item.Menus {
+			fmt.Fprintf(out, "    #%d %s (%s)\n", menu.MenuID, menu.MenuName, menu.MenuType)
+		}
+	}
+
+	fmt.Fprintf(out, "\nRoutes without permission checks (%d):\n", len(report.Unprotected))
+	for _, route := range report.Unprotected {
+		fmt.Fprintf(out, "  %s %s\n", route.Method, route.Path)
+	}
+}
+
+func writeJSON(out io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
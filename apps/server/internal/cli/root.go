@@ -20,9 +20,12 @@ func NewRootCommand() *cobra.Command {
 	// 支持通过 --env-file 指定额外的 dotenv 文件
 	cmd.PersistentFlags().StringSliceVar(&opts.EnvFiles, "env-file", nil, "Additional dotenv file(s) to load")
 
-	// 注册子命令：启动服务、查看版本信息
+	// 注册子命令：启动服务、查看版本信息、权限核对、权限清单、参数密钥管理
 	cmd.AddCommand(NewStartCommand(opts))
 	cmd.AddCommand(NewVersionCommand())
+	cmd.AddCommand(NewPermissionCommand(opts))
+	cmd.AddCommand(NewManifestCommand(opts))
+	cmd.AddCommand(NewSecretsCommand(opts))
 
 	return cmd
 }
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/starter-kit-fe/admin/internal/app"
+	"github.com/starter-kit-fe/admin/internal/config"
+	"github.com/starter-kit-fe/admin/pkg/envelope"
+)
+
+func NewSecretsCommand(rootOpts *RootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage the keys that encrypt secret configs",
+	}
+
+	cmd.AddCommand(newSecretsGenerateKeyCommand())
+	cmd.AddCommand(newSecretsRotateCommand(rootOpts))
+
+	return cmd
+}
+
+func newSecretsGenerateKeyCommand() *cobra.Command {
+	var id string
+
+	cmd := &cobra.Command{
+		Use:   "generate-key",
+		Short: "Print a new key entry for SECURITY_CONFIG_KEYS",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := envelope.GenerateKey()
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s:%s\n", id, key)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "k1", "Key id, stored with every value the key encrypts")
+
+	return cmd
+}
+
+func newSecretsRotateCommand(rootOpts *RootOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate",
+		Short: "Re-wrap secret configs with the first key in SECURITY_CONFIG_KEYS",
+		Long: `Re-wrap the data key of every secret config with the first (active) key in
+SECURITY_CONFIG_KEYS. To rotate, prepend a new key to the list, deploy, run this
+command, and remove the old key once the rotation has finished and running
+instances have been restarted with the new list.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			cfg, err := config.Load(rootOpts.EnvFiles...)
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			count, err := app.RotateConfigSecrets(ctx, app.Options{Config: cfg})
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Re-wrapped %d secret config(s).\n", count)
+			return err
+		},
+	}
+}
@@ -15,6 +15,13 @@ import (
 	"github.com/starter-kit-fe/admin/constant"
 )
 
+const (
+	defaultPresignTTL          = 15 * time.Minute
+	defaultStorageAllowedTypes = "image/*,application/pdf,text/plain,text/csv,application/zip," +
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet," +
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
 type Config struct {
 	App      AppConfig
 	HTTP     HTTPConfig
@@ -24,7 +31,9 @@ type Config struct {
 	Auth     AuthConfig
 	Security SecurityConfig
 	S3       S3Config
+	Storage  StorageConfig
 	Backup   BackupConfig
+	SCIM     SCIMConfig
 }
 
 type AppConfig struct {
@@ -65,6 +74,8 @@ type AuthConfig struct {
 
 type SecurityConfig struct {
 	RateLimit RateLimitConfig
+	// ConfigKeys 加密 secret 类型参数的密钥，格式为 "id:base64密钥"，第一个用于加密，其余仅在轮换期间用于解密
+	ConfigKeys []string
 }
 
 type RateLimitConfig struct {
@@ -82,11 +93,24 @@ type S3Config struct {
 	UsePathStyle bool
 }
 
+type StorageConfig struct {
+	Driver       string
+	LocalDir     string
+	MaxSizeMB    int
+	AllowedTypes []string
+	PresignTTL   time.Duration
+}
+
 type BackupConfig struct {
 	RetentionDays int
 	TempDir       string
 }
 
+// SCIMConfig 身份源通过 SCIM 同步账号时使用的独立令牌，未配置时不开放 SCIM 接口
+type SCIMConfig struct {
+	Tokens []string
+}
+
 func Load(envFiles ...string) (*Config, error) {
 	if len(envFiles) == 1 && strings.TrimSpace(envFiles[0]) == "" {
 		envFiles = nil
@@ -136,6 +160,7 @@ func Load(envFiles ...string) (*Config, error) {
 				Requests: v.GetInt("security.rate_limit.requests"),
 				Burst:    v.GetInt("security.rate_limit.burst"),
 			},
+			ConfigKeys: splitList(v.GetString("security.config_keys")),
 		},
 		S3: S3Config{
 			Endpoint:     strings.TrimSpace(v.GetString("s3.endpoint")),
@@ -145,10 +170,20 @@ func Load(envFiles ...string) (*Config, error) {
 			Region:       strings.TrimSpace(v.GetString("s3.region")),
 			UsePathStyle: v.GetBool("s3.use_path_style"),
 		},
+		Storage: StorageConfig{
+			Driver:       strings.TrimSpace(v.GetString("storage.driver")),
+			LocalDir:     strings.TrimSpace(v.GetString("storage.local_dir")),
+			MaxSizeMB:    v.GetInt("storage.max_size_mb"),
+			AllowedTypes: splitList(v.GetString("storage.allowed_types")),
+			PresignTTL:   parseDurationOrDefault(strings.TrimSpace(v.GetString("storage.presign_ttl")), defaultPresignTTL),
+		},
 		Backup: BackupConfig{
 			RetentionDays: v.GetInt("backup.retention_days"),
 			TempDir:       strings.TrimSpace(v.GetString("backup.temp_dir")),
 		},
+		SCIM: SCIMConfig{
+			Tokens: splitList(v.GetString("scim.tokens")),
+		},
 	}
 
 	if cfg.HTTP.Addr == "" {
@@ -257,6 +292,25 @@ func (c *Config) Normalize() {
 		c.S3.Region = "us-east-1"
 	}
 
+	// 文件存储配置规范化，未知驱动回退到本地磁盘
+	c.Storage.Driver = strings.ToLower(strings.TrimSpace(c.Storage.Driver))
+	if c.Storage.Driver != "s3" {
+		c.Storage.Driver = "local"
+	}
+	c.Storage.LocalDir = strings.TrimSpace(c.Storage.LocalDir)
+	if c.Storage.LocalDir == "" {
+		c.Storage.LocalDir = "data/uploads"
+	}
+	if c.Storage.MaxSizeMB <= 0 {
+		c.Storage.MaxSizeMB = 20
+	}
+	if len(c.Storage.AllowedTypes) == 0 {
+		c.Storage.AllowedTypes = splitList(defaultStorageAllowedTypes)
+	}
+	if c.Storage.PresignTTL <= 0 {
+		c.Storage.PresignTTL = defaultPresignTTL
+	}
+
 	// 备份配置规范化
 	if c.Backup.RetentionDays <= 0 {
 		c.Backup.RetentionDays = 7
@@ -265,6 +319,9 @@ func (c *Config) Normalize() {
 	if c.Backup.TempDir == "" {
 		c.Backup.TempDir = "/tmp/backups"
 	}
+
+	// SCIM 令牌去除空白与空值
+	c.SCIM.Tokens = splitList(strings.Join(c.SCIM.Tokens, ","))
 }
 
 // Validate checks that required runtime configuration is present and well-formed.
@@ -284,6 +341,17 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+func splitList(value string) []string {
+	parts := strings.Split(value, ",")
+	items := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
 func normalizeAddr(addr string) string {
 	addr = strings.TrimSpace(addr)
 	if addr == "" {
@@ -360,14 +428,21 @@ func newViper() *viper.Viper {
 	v.SetDefault("security.rate_limit.requests", 60)
 	v.SetDefault("security.rate_limit.burst", 60)
 	v.SetDefault("security.rate_limit.period", "1m")
+	v.SetDefault("security.config_keys", "")
 	v.SetDefault("s3.endpoint", "")
 	v.SetDefault("s3.access_key", "")
 	v.SetDefault("s3.secret_key", "")
 	v.SetDefault("s3.bucket", "")
 	v.SetDefault("s3.region", "us-east-1")
 	v.SetDefault("s3.use_path_style", false)
+	v.SetDefault("storage.driver", "local")
+	v.SetDefault("storage.local_dir", "data/uploads")
+	v.SetDefault("storage.max_size_mb", 20)
+	v.SetDefault("storage.allowed_types", defaultStorageAllowedTypes)
+	v.SetDefault("storage.presign_ttl", defaultPresignTTL.String())
 	v.SetDefault("backup.retention_days", 7)
 	v.SetDefault("backup.temp_dir", "/tmp/backups")
+	v.SetDefault("scim.tokens", "")
 
 	_ = v.BindEnv("app.name", "APP_NAME")
 	_ = v.BindEnv("app.mode", "APP_MODE", "GIN_MODE")
@@ -391,14 +466,21 @@ func newViper() *viper.Viper {
 	_ = v.BindEnv("security.rate_limit.requests", "SECURITY_RATE_LIMIT_REQUESTS")
 	_ = v.BindEnv("security.rate_limit.burst", "SECURITY_RATE_LIMIT_BURST")
 	_ = v.BindEnv("security.rate_limit.period", "SECURITY_RATE_LIMIT_PERIOD")
+	_ = v.BindEnv("security.config_keys", "SECURITY_CONFIG_KEYS")
 	_ = v.BindEnv("s3.endpoint", "S3_ENDPOINT")
 	_ = v.BindEnv("s3.access_key", "S3_ACCESS_KEY")
 	_ = v.BindEnv("s3.secret_key", "S3_SECRET_KEY")
 	_ = v.BindEnv("s3.bucket", "S3_BUCKET")
 	_ = v.BindEnv("s3.region", "S3_REGION")
 	_ = v.BindEnv("s3.use_path_style", "S3_USE_PATH_STYLE")
+	_ = v.BindEnv("storage.driver", "STORAGE_DRIVER")
+	_ = v.BindEnv("storage.local_dir", "STORAGE_LOCAL_DIR")
+	_ = v.BindEnv("storage.max_size_mb", "STORAGE_MAX_SIZE_MB")
+	_ = v.BindEnv("storage.allowed_types", "STORAGE_ALLOWED_TYPES")
+	_ = v.BindEnv("storage.presign_ttl", "STORAGE_PRESIGN_TTL")
 	_ = v.BindEnv("backup.retention_days", "BACKUP_RETENTION_DAYS")
 	_ = v.BindEnv("backup.temp_dir", "BACKUP_TEMP_DIR")
+	_ = v.BindEnv("scim.tokens", "SCIM_TOKENS", "SCIM_TOKEN")
 
 	return v
 }
@@ -9,6 +9,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
 )
 
 // AutoMigrate automatically migrates the schema for all models.
@@ -27,6 +28,8 @@ func AutoMigrate(db *gorm.DB) error {
 		&model.SysRoleMenu{},
 		&model.SysRoleDept{},
 		&model.SysUserPost{},
+		&model.SysDeptRole{},
+		&model.SysPostRole{},
 		&model.SysDictType{},
 		&model.SysDictData{},
 		&model.SysConfig{},
@@ -34,6 +37,14 @@ func AutoMigrate(db *gorm.DB) error {
 		&model.SysJobLog{},
 		&model.SysJobLogStep{},
 		&model.SysNotice{},
+		&model.SysNoticeTarget{},
+		&model.SysNoticeRead{},
+		&model.SysFile{},
+		&model.SysTenant{},
+		&model.SysChangeLog{},
+		&model.SysUserAttrDef{},
+		&model.SysUserAttr{},
+		&model.SysTranslation{},
 	}
 
 	if db.Dialector.Name() != "postgres" {
@@ -54,6 +65,38 @@ func AutoMigrate(db *gorm.DB) error {
 	return ensureUserPostCompositePrimaryKey(db)
 }
 
+// TenantModels lists the models whose rows belong to a tenant.
+func TenantModels() []interface{} {
+	return []interface{}{
+		&model.SysDept{},
+		&model.SysUser{},
+		&model.SysPost{},
+		&model.SysRole{},
+		&model.SysDictType{},
+		&model.SysDictData{},
+		&model.SysConfig{},
+		&model.SysNotice{},
+		&model.SysNoticeTarget{},
+		&model.SysNoticeRead{},
+		&model.SysFile{},
+		&model.SysOperLog{},
+		&model.SysLogininfor{},
+		&model.SysChangeLog{},
+		&model.SysUserAttrDef{},
+		&model.SysUserAttr{},
+		&model.SysTranslation{},
+	}
+}
+
+// RegisterTenantScope limits statements on tenant models to the tenant bound
+// to the statement context.
+func RegisterTenantScope(db *gorm.DB) error {
+	if db == nil {
+		return errors.New("gorm db is nil")
+	}
+	return tenant.Register(db, TenantModels()...)
+}
+
 func ensureLogTables(db *gorm.DB) error {
 	if db.Dialector.Name() != "postgres" {
 		return nil
@@ -71,6 +114,11 @@ func ensureLogTables(db *gorm.DB) error {
 		if err := db.Exec(stmt).Error; err != nil {
 			return err
 		}
+		// 旧版本创建的日志表缺少租户列
+		alter := fmt.Sprintf(`ALTER TABLE %q ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 1`, spec.TableName)
+		if err := db.Exec(alter).Error; err != nil {
+			return err
+		}
 
 		// 2. Ensure indexes (BRIN etc)
 		for _, idx := range spec.Indexes {
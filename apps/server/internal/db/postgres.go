@@ -21,7 +21,9 @@ func LoadPostgres(url string, mode string) (*gorm.DB, error) {
 			SingularTable: true,
 		},
 		DryRun: false,
-		Logger: newGormLogger(mode),
+		// 将唯一约束冲突等方言错误转换为 gorm.ErrDuplicatedKey，供各模块统一判断
+		TranslateError: true,
+		Logger:         newGormLogger(mode),
 	}
 	db, err := gorm.Open(postgres.Open(url), config)
 	if err != nil {
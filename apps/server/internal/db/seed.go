@@ -91,6 +91,10 @@ func SeedDefaults(ctx context.Context, db *gorm.DB, logger *slog.Logger) error {
 		logger.Info("seeded default data", "statements", statementCount)
 	}
 
+	if err := ensureDefaultTenant(ctx, db); err != nil {
+		return fmt.Errorf("seed default tenant: %w", err)
+	}
+
 	if err := syncSequences(ctx, db, prefix, logger); err != nil {
 		return fmt.Errorf("sync sequences: %w", err)
 	}
@@ -324,6 +328,7 @@ func syncSequences(ctx context.Context, db *gorm.DB, prefix string, logger *slog
 		"sys_job":        "job_name",
 		"sys_job_log":    "id",
 		"sys_notice":     "id",
+		"sys_tenant":     "id",
 	}
 
 	for table, column := range sequenceTargets {
@@ -27,6 +27,11 @@ insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame
 insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('113', '缓存监控', '2', '5', 'cache', '', '1', '0', 'C', '0', '0', 'monitor:cache:list', 'Database', 'admin', CURRENT_TIMESTAMP, '1', null, '缓存监控菜单');
 insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('114', '缓存列表', '2', '6', 'cacheList', '', '1', '0', 'C', '0', '0', 'monitor:cache:list', 'DatabaseZap', 'admin', CURRENT_TIMESTAMP, '1', null, '缓存列表菜单');
 insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('117', '系统接口', '3', '3', 'swagger', '', '1', '0', 'C', '0', '0', 'tool:swagger:list', 'FileCode2', 'admin', CURRENT_TIMESTAMP, '1', null, '系统接口菜单');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('118', '文件管理', '1', '9', 'file', '', '1', '0', 'C', '0', '0', 'system:file:list', 'FolderOpen', 'admin', CURRENT_TIMESTAMP, '1', null, '文件管理菜单');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('119', '回收站', '1', '10', 'recycle', '', '1', '0', 'C', '0', '0', 'system:recycle:list', 'Trash2', 'admin', CURRENT_TIMESTAMP, '1', null, '回收站菜单');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('120', '权限清单', '1', '11', 'manifest', '', '1', '0', 'C', '0', '0', 'system:manifest:export', 'FileCode', 'admin', CURRENT_TIMESTAMP, '1', null, '权限清单菜单');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('121', '租户管理', '1', '12', 'tenant', '', '1', '0', 'C', '0', '0', 'system:tenant:list', 'Building', 'admin', CURRENT_TIMESTAMP, '1', null, '租户管理菜单');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('122', '多语言', '1', '13', 'i18n', '', '1', '0', 'C', '0', '0', 'system:i18n:list', 'Languages', 'admin', CURRENT_TIMESTAMP, '1', null, '多语言译文菜单');
 insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('500', '操作日志', '108', '1', 'operlog', '', '1', '0', 'C', '0', '0', 'monitor:operlog:list', 'ClipboardList', 'admin', CURRENT_TIMESTAMP, '1', null, '操作日志菜单');
 insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('501', '登录日志', '108', '2', 'logininfor', '', '1', '0', 'C', '0', '0', 'monitor:logininfor:list', 'LogIn', 'admin', CURRENT_TIMESTAMP, '1', null, '登录日志菜单');
 insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1000', '用户查询', '100', '1', '', '', '1', '0', 'F', '0', '0', 'system:user:query', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
@@ -86,6 +91,30 @@ insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame
 insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1054', '任务导出', '110', '6', '#', '', '1', '0', 'F', '0', '0', 'monitor:job:export', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
 insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1055', '立即执行', '110', '7', '#', '', '1', '0', 'F', '0', '0', 'monitor:job:run', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '手动触发任务');
 insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1056', '清空日志', '110', '8', '#', '', '1', '0', 'F', '0', '0', 'monitor:job:remove', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '清除该任务的执行日志');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1057', '路由权限', '102', '5', '#', '', '1', '0', 'F', '0', '0', 'system:permission:list', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '查看路由权限清单');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1058', '权限核对', '102', '6', '#', '', '1', '0', 'F', '0', '0', 'system:permission:audit', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '核对路由与菜单权限');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1059', '部门导出', '103', '5', '#', '', '1', '0', 'F', '0', '0', 'system:dept:export', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1060', '文件查询', '118', '1', '#', '', '1', '0', 'F', '0', '0', 'system:file:list', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1061', '文件上传', '118', '2', '#', '', '1', '0', 'F', '0', '0', 'system:file:upload', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1062', '文件下载', '118', '3', '#', '', '1', '0', 'F', '0', '0', 'system:file:download', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1063', '文件删除', '118', '4', '#', '', '1', '0', 'F', '0', '0', 'system:file:remove', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1064', '部门合并', '103', '6', '#', '', '1', '0', 'F', '0', '0', 'system:dept:merge', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '合并部门并迁移用户与下级部门');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1065', '回收站查询', '119', '1', '#', '', '1', '0', 'F', '0', '0', 'system:recycle:list', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1066', '记录恢复', '119', '2', '#', '', '1', '0', 'F', '0', '0', 'system:recycle:restore', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1067', '彻底删除', '119', '3', '#', '', '1', '0', 'F', '0', '0', 'system:recycle:remove', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1068', '清单导出', '120', '1', '#', '', '1', '0', 'F', '0', '0', 'system:manifest:export', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1069', '清单同步', '120', '2', '#', '', '1', '0', 'F', '0', '0', 'system:manifest:sync', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1070', '租户查询', '121', '1', '#', '', '1', '0', 'F', '0', '0', 'system:tenant:query', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1071', '租户新增', '121', '2', '#', '', '1', '0', 'F', '0', '0', 'system:tenant:add', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1072', '租户修改', '121', '3', '#', '', '1', '0', 'F', '0', '0', 'system:tenant:edit', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1073', '租户删除', '121', '4', '#', '', '1', '0', 'F', '0', '0', 'system:tenant:remove', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1074', '切换租户', '121', '5', '#', '', '1', '0', 'F', '0', '0', 'system:tenant:switch', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1075', '用户属性', '100', '8', '', '', '1', '0', 'F', '0', '0', 'system:user:attr', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1076', '字典导入', '105', '6', '#', '', '1', '0', 'F', '0', '0', 'system:dict:import', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1077', '译文修改', '122', '1', '#', '', '1', '0', 'F', '0', '0', 'system:i18n:edit', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1078', '译文删除', '122', '2', '#', '', '1', '0', 'F', '0', '0', 'system:i18n:remove', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1079', '语言包导出', '122', '3', '#', '', '1', '0', 'F', '0', '0', 'system:i18n:export', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
+insert into sys_menu (id, menu_name, parent_id, order_num, path, query, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, created_at, update_by, updated_at, remark) values('1080', '语言包导入', '122', '4', '#', '', '1', '0', 'F', '0', '0', 'system:i18n:import', '#', 'admin', CURRENT_TIMESTAMP, 'admin', null, '');
 insert into sys_dict_type (id, dict_name, dict_type, status, create_by, created_at, update_by, updated_at, remark) values(1,  '用户性别', 'sys_user_sex',        '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '用户性别列表');
 insert into sys_dict_type (id, dict_name, dict_type, status, create_by, created_at, update_by, updated_at, remark) values(2,  '菜单状态', 'sys_show_hide',       '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '菜单状态列表');
 insert into sys_dict_type (id, dict_name, dict_type, status, create_by, created_at, update_by, updated_at, remark) values(3,  '系统开关', 'sys_normal_disable',  '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '系统开关列表');
@@ -96,6 +125,7 @@ insert into sys_dict_type (id, dict_name, dict_type, status, create_by, created_
 insert into sys_dict_type (id, dict_name, dict_type, status, create_by, created_at, update_by, updated_at, remark) values(8,  '通知状态', 'sys_notice_status',   '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '通知状态列表');
 insert into sys_dict_type (id, dict_name, dict_type, status, create_by, created_at, update_by, updated_at, remark) values(9,  '操作类型', 'sys_oper_type',       '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '操作类型列表');
 insert into sys_dict_type (id, dict_name, dict_type, status, create_by, created_at, update_by, updated_at, remark) values(10, '系统状态', 'sys_common_status',   '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '登录状态列表');
+insert into sys_dict_type (id, dict_name, dict_type, status, create_by, created_at, update_by, updated_at, remark) values(11, '数据范围', 'sys_data_scope',      '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '角色数据范围列表');
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(1,  1,  '男',       '0',       'sys_user_sex',        '',   '',        'Y', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '性别男');
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(2,  2,  '女',       '1',       'sys_user_sex',        '',   '',        'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '性别女');
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(3,  3,  '未知',     '2',       'sys_user_sex',        '',   '',        'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '性别未知');
@@ -113,6 +143,8 @@ insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(15, 2,  '公告',     '2',       'sys_notice_type',     '',   'success', 'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '公告');
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(16, 1,  '正常',     '0',       'sys_notice_status',   '',   'primary', 'Y', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '正常状态');
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(17, 2,  '关闭',     '1',       'sys_notice_status',   '',   'danger',  'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '关闭状态');
+insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(35, 3,  '待发布',   '2',       'sys_notice_status',   '',   'warning', 'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '定时发布未到时间');
+insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(36, 4,  '已过期',   '3',       'sys_notice_status',   '',   'info',    'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '超过过期时间');
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(18, 99, '其他',     '0',       'sys_oper_type',       '',   'info',    'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '其他操作');
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(19, 1,  '新增',     '1',       'sys_oper_type',       '',   'info',    'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '新增操作');
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(20, 2,  '修改',     '2',       'sys_oper_type',       '',   'info',    'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '修改操作');
@@ -125,9 +157,18 @@ insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(27, 9,  '清空数据', '9',       'sys_oper_type',       '',   'danger',  'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '清空操作');
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(28, 1,  '成功',     '0',       'sys_common_status',   '',   'primary', 'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '正常状态');
 insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(29, 2,  '失败',     '1',       'sys_common_status',   '',   'danger',  'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '停用状态');
-insert into sys_config (id, config_name, config_key, config_value, config_type, create_by, created_at, update_by, updated_at, remark) values(2, '用户管理-账号初始密码',         'sys.user.initPassword',            '123456',        'Y', 'admin', CURRENT_TIMESTAMP, 'admin', null, '初始化密码 123456' );
-insert into sys_config (id, config_name, config_key, config_value, config_type, create_by, created_at, update_by, updated_at, remark) values(4, '账号自助-验证码开关',           'sys.account.captchaEnabled',       'true',          'Y', 'admin', CURRENT_TIMESTAMP, 'admin', null, '是否开启验证码功能（true开启，false关闭）');
-insert into sys_config (id, config_name, config_key, config_value, config_type, create_by, created_at, update_by, updated_at, remark) values(5, '账号自助-是否开启用户注册功能', 'sys.account.registerUser',         'false',         'Y', 'admin', CURRENT_TIMESTAMP, 'admin', null, '是否开启注册用户功能（true开启，false关闭）');
-insert into sys_config (id, config_name, config_key, config_value, config_type, create_by, created_at, update_by, updated_at, remark) values(6, '用户登录-黑名单列表',           'sys.login.blackIPList',            '',              'Y', 'admin', CURRENT_TIMESTAMP, 'admin', null, '设置登录IP黑名单限制，多个匹配项以;分隔，支持匹配（*通配、网段）');
-insert into sys_config (id, config_name, config_key, config_value, config_type, create_by, created_at, update_by, updated_at, remark) values(7, '用户管理-初始密码修改策略',     'sys.account.initPasswordModify',   '1',             'Y', 'admin', CURRENT_TIMESTAMP, 'admin', null, '0：初始密码修改策略关闭，没有任何提示，1：提醒用户，如果未修改初始密码，则在登录时就会提醒修改密码对话框');
-insert into sys_config (id, config_name, config_key, config_value, config_type, create_by, created_at, update_by, updated_at, remark) values(8, '用户管理-账号密码更新周期',     'sys.account.passwordValidateDays', '0',             'Y', 'admin', CURRENT_TIMESTAMP, 'admin', null, '密码更新周期（填写数字，数据初始化值为0不限制，若修改必须为大于0小于365的正整数），如果超过这个周期登录系统时，则在登录时就会提醒修改密码对话框');
+insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(30, 1,  '全部数据权限', '1',       'sys_data_scope',      '',   '',        'Y', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '全部数据权限');
+insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(31, 2,  '自定数据权限', '2',       'sys_data_scope',      '',   '',        'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '自定数据权限');
+insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(32, 3,  '本部门数据权限', '3',       'sys_data_scope',      '',   '',        'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '本部门数据权限');
+insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(33, 4,  '本部门及以下数据权限', '4',       'sys_data_scope',      '',   '',        'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '本部门及以下数据权限');
+insert into sys_dict_data (id, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, created_at, update_by, updated_at, remark) values(34, 5,  '仅本人数据权限', '5',       'sys_data_scope',      '',   '',        'N', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '仅本人数据权限');
+insert into sys_config (id, config_name, config_key, config_value, config_type, value_type, value_schema, default_value, is_public, create_by, created_at, update_by, updated_at, remark) values(2, '用户管理-账号初始密码',         'sys.user.initPassword',            '123456',        'Y', 'string',   null,        null,    false, 'admin', CURRENT_TIMESTAMP, 'admin', null, '初始化密码 123456' );
+insert into sys_config (id, config_name, config_key, config_value, config_type, value_type, value_schema, default_value, is_public, create_by, created_at, update_by, updated_at, remark) values(4, '账号自助-验证码开关',           'sys.account.captchaEnabled',       'true',          'Y', 'bool',     null,        'true',  true,  'admin', CURRENT_TIMESTAMP, 'admin', null, '是否开启验证码功能（true开启，false关闭）');
+insert into sys_config (id, config_name, config_key, config_value, config_type, value_type, value_schema, default_value, is_public, create_by, created_at, update_by, updated_at, remark) values(5, '账号自助-是否开启用户注册功能', 'sys.account.registerUser',         'false',         'Y', 'bool',     null,        'false', true,  'admin', CURRENT_TIMESTAMP, 'admin', null, '是否开启注册用户功能（true开启，false关闭）');
+insert into sys_config (id, config_name, config_key, config_value, config_type, value_type, value_schema, default_value, is_public, create_by, created_at, update_by, updated_at, remark) values(6, '用户登录-黑名单列表',           'sys.login.blackIPList',            '',              'Y', 'string',   null,        null,    false, 'admin', CURRENT_TIMESTAMP, 'admin', null, '设置登录IP黑名单限制，多个匹配项以;分隔，支持匹配（*通配、网段）');
+insert into sys_config (id, config_name, config_key, config_value, config_type, value_type, value_schema, default_value, is_public, create_by, created_at, update_by, updated_at, remark) values(7, '用户管理-初始密码修改策略',     'sys.account.initPasswordModify',   '1',             'Y', 'enum',     '["0","1"]', null,    false, 'admin', CURRENT_TIMESTAMP, 'admin', null, '0：初始密码修改策略关闭，没有任何提示，1：提醒用户，如果未修改初始密码，则在登录时就会提醒修改密码对话框');
+insert into sys_config (id, config_name, config_key, config_value, config_type, value_type, value_schema, default_value, is_public, create_by, created_at, update_by, updated_at, remark) values(8, '用户管理-账号密码更新周期',     'sys.account.passwordValidateDays', '0',             'Y', 'int',      null,        '0',     false, 'admin', CURRENT_TIMESTAMP, 'admin', null, '密码更新周期（填写数字，数据初始化值为0不限制，若修改必须为大于0小于365的正整数），如果超过这个周期登录系统时，则在登录时就会提醒修改密码对话框');
+insert into sys_config (id, config_name, config_key, config_value, config_type, value_type, value_schema, default_value, is_public, create_by, created_at, update_by, updated_at, remark) values(9, '安全设置-接口限流阈值',         'sys.security.rateLimit',           '',              'Y', 'string',   null,        null,    false, 'admin', CURRENT_TIMESTAMP, 'admin', null, '格式为 次数/周期，如 100/1m，修改后即时生效；留空使用配置文件中的限流设置');
+insert into sys_config (id, config_name, config_key, config_value, config_type, value_type, value_schema, default_value, is_public, create_by, created_at, update_by, updated_at, remark) values(10, '站点设置-站点名称',             'sys.site.name',                    'Admin Template', 'Y', 'string',   null,        null,    true,  'admin', CURRENT_TIMESTAMP, 'admin', null, '登录页与浏览器标题中展示的站点名称');
+insert into sys_config (id, config_name, config_key, config_value, config_type, value_type, value_schema, default_value, is_public, create_by, created_at, update_by, updated_at, remark) values(11, '站点设置-站点Logo',             'sys.site.logo',                    '',              'Y', 'url',      null,        '/pwa-192x192.png', true, 'admin', CURRENT_TIMESTAMP, 'admin', null, '登录页展示的 Logo 地址，支持 http(s) 链接或站内路径，留空使用默认图标');
+insert into sys_job (job_name, job_group, invoke_target, invoke_params, cron_expression, misfire_policy, concurrent, status, create_by, created_at, update_by, updated_at, remark) values('公告定时发布与过期', 'SYSTEM', 'notice.schedule', '', '* * * * *', '3', '1', '0', 'admin', CURRENT_TIMESTAMP, 'admin', null, '每分钟将到达发布时间的公告置为正常、到期的公告置为已过期；收件箱按发布窗口实时过滤，本任务只同步公告状态');
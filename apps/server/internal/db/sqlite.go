@@ -19,6 +19,8 @@ func LoadSqlite(dsn string) (*gorm.DB, error) {
 			SingularTable: true,               // Use singular table name
 		},
 		DryRun: false,
+		// 将唯一约束冲突等方言错误转换为 gorm.ErrDuplicatedKey，供各模块统一判断
+		TranslateError: true,
 		Logger: logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags),
 			logger.Config{
 				LogLevel: logger.Info,
@@ -0,0 +1,284 @@
+package db
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/constant"
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+// tenantSeedTables 每个租户独立持有一份的基础数据
+var tenantSeedTables = map[string]struct{}{
+	"sys_post":      {},
+	"sys_dict_type": {},
+	"sys_dict_data": {},
+	"sys_config":    {},
+}
+
+// platformPermPrefixes 作用于整个部署的功能，不授予租户管理员
+var platformPermPrefixes = []string{
+	"system:tenant:",
+	"system:menu:",
+	"system:permission:",
+	"system:manifest:",
+	"monitor:job:",
+	"monitor:server:",
+	"monitor:cache:",
+	"monitor:online:",
+	"tool:swagger:",
+}
+
+// TenantSeed 描述新租户的初始化参数
+type TenantSeed struct {
+	TenantID      int64
+	TenantName    string
+	AdminUserName string
+	// AdminPassword 为空时随机生成
+	AdminPassword string
+	Operator      string
+}
+
+// ensureDefaultTenant 保证平台默认租户存在，已有数据都归属于它
+func ensureDefaultTenant(ctx context.Context, db *gorm.DB) error {
+	var count int64
+	if err := db.WithContext(ctx).Model(&model.SysTenant{}).Unscoped().Where("id = ?", tenant.DefaultID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	record := model.SysTenant{
+		TenantCode: "default",
+		TenantName: "默认租户",
+		Status:     "0",
+		CreateBy:   "system",
+	}
+	record.ID = uint(tenant.DefaultID)
+	return db.WithContext(ctx).Create(&record).Error
+}
+
+// SeedTenant 为新租户写入岗位、字典、参数等默认数据，并创建根部门、管理员角色与管理员账号。
+// 返回管理员的初始密码。
+func SeedTenant(ctx context.Context, db *gorm.DB, seed TenantSeed, logger *slog.Logger) (string, error) {
+	if db == nil {
+		return "", errors.New("gorm db is nil")
+	}
+	if seed.TenantID <= 0 {
+		return "", errors.New("tenant id is required")
+	}
+
+	userName := strings.TrimSpace(seed.AdminUserName)
+	if userName == "" {
+		userName = "admin"
+	}
+	password := seed.AdminPassword
+	if password == "" {
+		generated, err := generatePassword(16)
+		if err != nil {
+			return "", fmt.Errorf("generate admin password: %w", err)
+		}
+		password = generated
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hash admin password: %w", err)
+	}
+	operator := strings.TrimSpace(seed.Operator)
+	if operator == "" {
+		operator = "system"
+	}
+
+	ctx = tenant.WithoutScope(ctx)
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range buildTenantSeedStatements(constant.DB_PREFIX, seed.TenantID) {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("execute tenant seed statement: %w", err)
+			}
+		}
+
+		dept := model.SysDept{
+			TenantID:  seed.TenantID,
+			ParentID:  0,
+			Ancestors: "0",
+			DeptName:  strings.TrimSpace(seed.TenantName),
+			OrderNum:  0,
+			Status:    "0",
+			CreateBy:  operator,
+		}
+		if err := tx.Create(&dept).Error; err != nil {
+			return fmt.Errorf("create root dept: %w", err)
+		}
+
+		remark := "租户管理员"
+		role := model.SysRole{
+			TenantID:          seed.TenantID,
+			RoleName:          "租户管理员",
+			RoleKey:           "admin",
+			RoleSort:          1,
+			DataScope:         "1",
+			MenuCheckStrictly: true,
+			DeptCheckStrictly: true,
+			Status:            "0",
+			CreateBy:          operator,
+			Remark:            &remark,
+		}
+		if err := tx.Create(&role).Error; err != nil {
+			return fmt.Errorf("create admin role: %w", err)
+		}
+		if err := grantTenantMenus(tx, int64(role.ID)); err != nil {
+			return fmt.Errorf("grant admin menus: %w", err)
+		}
+		if err := tx.Create(&model.SysRoleDept{RoleID: int64(role.ID), DeptID: int64(dept.ID)}).Error; err != nil {
+			return fmt.Errorf("grant admin dept: %w", err)
+		}
+
+		now := time.Now()
+		deptID := int64(dept.ID)
+		userRemark := "租户初始化管理员"
+		user := model.SysUser{
+			TenantID:      seed.TenantID,
+			DeptID:        &deptID,
+			UserName:      userName,
+			NickName:      "租户管理员",
+			UserType:      "00",
+			Sex:           "1",
+			Password:      string(hashed),
+			Status:        "0",
+			PwdUpdateDate: &now,
+			CreateBy:      operator,
+			Remark:        &userRemark,
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return fmt.Errorf("create admin user: %w", err)
+		}
+		if err := tx.Create(&model.SysUserRole{UserID: int64(user.ID), RoleID: int64(role.ID)}).Error; err != nil {
+			return fmt.Errorf("assign admin role: %w", err)
+		}
+
+		var posts []model.SysPost
+		if err := tx.Where("tenant_id = ? AND post_code = ?", seed.TenantID, "ceo").Limit(1).Find(&posts).Error; err != nil {
+			return fmt.Errorf("load admin post: %w", err)
+		}
+		if len(posts) > 0 {
+			if err := tx.Create(&model.SysUserPost{UserID: int64(user.ID), PostID: int64(posts[0].ID)}).Error; err != nil {
+				return fmt.Errorf("assign admin post: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if logger != nil {
+		logger.Info("seeded tenant defaults", "tenant_id", seed.TenantID, "admin", userName)
+	}
+	return password, nil
+}
+
+// grantTenantMenus 授予租户管理员除平台功能外的全部菜单，并补齐这些菜单的上级目录
+func grantTenantMenus(tx *gorm.DB, roleID int64) error {
+	var menus []model.SysMenu
+	if err := tx.Select("id", "parent_id", "menu_type", "perms").Find(&menus).Error; err != nil {
+		return err
+	}
+
+	parents := make(map[int64]int64, len(menus))
+	granted := make(map[int64]struct{}, len(menus))
+	for _, menu := range menus {
+		parents[int64(menu.ID)] = menu.ParentID
+		if menu.MenuType == "M" || isPlatformPerm(menu.Perms) {
+			continue
+		}
+		granted[int64(menu.ID)] = struct{}{}
+	}
+	for id := range granted {
+		for parent := parents[id]; parent > 0; parent = parents[parent] {
+			if _, ok := granted[parent]; ok {
+				break
+			}
+			granted[parent] = struct{}{}
+		}
+	}
+	if len(granted) == 0 {
+		return nil
+	}
+
+	rows := make([]model.SysRoleMenu, 0, len(granted))
+	for id := range granted {
+		rows = append(rows, model.SysRoleMenu{RoleID: roleID, MenuID: id})
+	}
+	return tx.CreateInBatches(rows, 200).Error
+}
+
+func isPlatformPerm(perms *string) bool {
+	if perms == nil {
+		return false
+	}
+	value := strings.TrimSpace(*perms)
+	for _, prefix := range platformPermPrefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildTenantSeedStatements 复用默认种子中的租户级数据，去掉固定 ID 并写入租户 ID
+func buildTenantSeedStatements(prefix string, tenantID int64) []string {
+	scanner := bufio.NewScanner(strings.NewReader(seedSQL))
+	scanner.Buffer(make([]byte, 0, 1024), 1024*1024)
+
+	var statements []string
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lower := strings.ToLower(line)
+		if !strings.HasPrefix(lower, "insert into ") {
+			continue
+		}
+
+		open := strings.Index(line, "(")
+		valuesIdx := strings.Index(lower, ") values")
+		if open == -1 || valuesIdx == -1 || valuesIdx < open {
+			continue
+		}
+		tableName := strings.TrimSpace(line[len("insert into "):open])
+		if _, ok := tenantSeedTables[tableName]; !ok {
+			continue
+		}
+
+		valueSection := strings.TrimSpace(line[valuesIdx+len(") values"):])
+		valueSection = strings.TrimSuffix(valueSection, ";")
+		valueSection = strings.TrimSpace(valueSection)
+		if !strings.HasPrefix(valueSection, "(") || !strings.HasSuffix(valueSection, ")") {
+			continue
+		}
+
+		columns := splitSQLValues(line[open+1 : valuesIdx])
+		values := splitSQLValues(valueSection[1 : len(valueSection)-1])
+		if len(columns) != len(values) || len(columns) == 0 || !strings.EqualFold(columns[0], "id") {
+			continue
+		}
+
+		columns = append([]string{"tenant_id"}, columns[1:]...)
+		values = append([]string{fmt.Sprintf("%d", tenantID)}, values[1:]...)
+
+		statement := fmt.Sprintf("INSERT INTO %s%s (%s) VALUES (%s)",
+			prefix, tableName, strings.Join(columns, ", "), strings.Join(values, ", "))
+		statement = strings.ReplaceAll(statement, "sysdate()", "NOW()")
+		statement = strings.ReplaceAll(statement, "\\'", "''")
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
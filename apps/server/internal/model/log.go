@@ -3,6 +3,7 @@ package model
 // SysOperLog 操作日志
 type SysOperLog struct {
 	BaseModel
+	TenantID      int64  `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
 	Title         string `gorm:"column:title;type:varchar(255)" json:"title"`
 	BusinessType  int    `gorm:"column:business_type;index" json:"business_type"`
 	Method        string `gorm:"column:method;type:varchar(255)" json:"method"`
@@ -27,6 +28,7 @@ func (SysOperLog) TableName() string {
 // SysLogininfor 登录日志
 type SysLogininfor struct {
 	BaseModel
+	TenantID      int64  `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
 	UserName      string `gorm:"column:user_name;type:varchar(64);index" json:"user_name"`
 	IPAddr        string `gorm:"column:ipaddr;type:varchar(64)" json:"ipaddr"`
 	LoginLocation string `gorm:"column:login_location;type:varchar(255)" json:"login_location"`
@@ -64,13 +66,14 @@ func OperLogTableSpec() LogTableSpec {
 		TimeColumn: "created_at",
 		IDColumn:   "id",
 		Columns: []string{
-			"id", "title", "business_type", "method", "request_method",
+			"id", "tenant_id", "title", "business_type", "method", "request_method",
 			"operator_type", "oper_name", "dept_name", "oper_url", "oper_ip",
 			"oper_location", "oper_param", "json_result", "status", "error_msg",
 			"created_at", "cost_time", "updated_at", "deleted_at",
 		},
 		ColumnsSQL: `
             id             BIGINT GENERATED ALWAYS AS IDENTITY,
+            tenant_id      BIGINT NOT NULL DEFAULT 1,
             title          VARCHAR(255) NOT NULL DEFAULT '',
             business_type  SMALLINT NOT NULL DEFAULT 0,
             method         VARCHAR(255) NOT NULL DEFAULT '',
@@ -94,6 +97,7 @@ func OperLogTableSpec() LogTableSpec {
 			{Name: tableName + "_created_at_brin", Using: "BRIN", Columns: []string{"created_at"}},
 			{Name: tableName + "_status_business_idx", Columns: []string{"status", "business_type"}},
 			{Name: tableName + "_oper_name_idx", Columns: []string{"oper_name"}},
+			{Name: tableName + "_tenant_idx", Columns: []string{"tenant_id"}},
 		},
 	}
 }
@@ -105,10 +109,11 @@ func LoginLogTableSpec() LogTableSpec {
 		TimeColumn: "created_at",
 		IDColumn:   "id",
 		Columns: []string{
-			"id", "user_name", "ipaddr", "login_location", "browser", "os", "status", "msg", "created_at", "updated_at", "deleted_at",
+			"id", "tenant_id", "user_name", "ipaddr", "login_location", "browser", "os", "status", "msg", "created_at", "updated_at", "deleted_at",
 		},
 		ColumnsSQL: `
             id             BIGINT GENERATED ALWAYS AS IDENTITY,
+            tenant_id      BIGINT NOT NULL DEFAULT 1,
             user_name      VARCHAR(64) NOT NULL DEFAULT '',
             ipaddr         VARCHAR(64) NOT NULL DEFAULT '',
             login_location VARCHAR(255) NOT NULL DEFAULT '',
@@ -124,6 +129,7 @@ func LoginLogTableSpec() LogTableSpec {
 			{Name: tableName + "_created_at_brin", Using: "BRIN", Columns: []string{"created_at"}},
 			{Name: tableName + "_user_name_idx", Columns: []string{"user_name"}},
 			{Name: tableName + "_status_idx", Columns: []string{"status"}},
+			{Name: tableName + "_tenant_idx", Columns: []string{"tenant_id"}},
 		},
 	}
 }
@@ -5,6 +5,7 @@ import (
 )
 
 type SysDept struct {
+	TenantID  int64   `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
 	ParentID  int64   `gorm:"column:parent_id" json:"parent_id"`
 	Ancestors string  `gorm:"column:ancestors" json:"ancestors"`
 	DeptName  string  `gorm:"column:dept_name" json:"dept_name"`
@@ -25,6 +26,7 @@ func (SysDept) TableName() string {
 }
 
 type SysUser struct {
+	TenantID    int64  `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
 	DeptID      *int64 `gorm:"column:dept_id" json:"dept_id,omitempty"`
 	UserName    string `gorm:"column:user_name" json:"user_name"`
 	NickName    string `gorm:"column:nick_name" json:"nick_name"`
@@ -35,6 +37,10 @@ type SysUser struct {
 	Avatar      string `gorm:"column:avatar" json:"avatar"`
 	Password    string `gorm:"column:password" json:"password"`
 	Status      string `gorm:"column:status" json:"status"`
+	// ExternalID 身份源中的账号标识，由 SCIM 同步写入
+	ExternalID *string `gorm:"column:external_id;size:255;index" json:"external_id,omitempty"`
+	// Locale 界面语言偏好，为空时按请求的 Accept-Language 协商
+	Locale string `gorm:"column:locale;type:varchar(16);not null;default:''" json:"locale"`
 
 	LoginIP       string     `gorm:"column:login_ip" json:"login_ip"`
 	LoginDate     *time.Time `gorm:"column:login_date" json:"login_date,omitempty"`
@@ -50,6 +56,7 @@ func (SysUser) TableName() string {
 }
 
 type SysPost struct {
+	TenantID int64  `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
 	PostCode string `gorm:"column:post_code" json:"post_code"`
 	PostName string `gorm:"column:post_name" json:"post_name"`
 	PostSort int    `gorm:"column:post_sort" json:"post_sort"`
@@ -65,9 +72,12 @@ func (SysPost) TableName() string {
 }
 
 type SysRole struct {
-	RoleName          string `gorm:"column:role_name" json:"role_name"`
-	RoleKey           string `gorm:"column:role_key" json:"role_key"`
-	RoleSort          int    `gorm:"column:role_sort" json:"role_sort"`
+	TenantID int64  `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
+	RoleName string `gorm:"column:role_name" json:"role_name"`
+	RoleKey  string `gorm:"column:role_key" json:"role_key"`
+	RoleSort int    `gorm:"column:role_sort" json:"role_sort"`
+	// ParentID 上级角色，角色自动拥有所有上级角色的菜单权限
+	ParentID          int64  `gorm:"column:parent_id;not null;default:0;index" json:"parent_id"`
 	DataScope         string `gorm:"column:data_scope" json:"data_scope"`
 	MenuCheckStrictly bool   `gorm:"column:menu_check_strictly" json:"menu_check_strictly"`
 	DeptCheckStrictly bool   `gorm:"column:dept_check_strictly" json:"dept_check_strictly"`
@@ -142,9 +152,32 @@ func (SysUserPost) TableName() string {
 	return tableName("sys_user_post")
 }
 
+// SysDeptRole 部门绑定的角色，部门内用户自动继承；IncludeChildren 为真时下级部门用户同样继承
+type SysDeptRole struct {
+	DeptID          int64 `gorm:"column:dept_id;primaryKey" json:"dept_id"`
+	RoleID          int64 `gorm:"column:role_id;primaryKey;index" json:"role_id"`
+	IncludeChildren bool  `gorm:"column:include_children;not null;default:false" json:"include_children"`
+}
+
+func (SysDeptRole) TableName() string {
+	return tableName("sys_dept_role")
+}
+
+// SysPostRole 岗位绑定的角色，担任该岗位的用户自动继承
+type SysPostRole struct {
+	PostID int64 `gorm:"column:post_id;primaryKey" json:"post_id"`
+	RoleID int64 `gorm:"column:role_id;primaryKey;index" json:"role_id"`
+}
+
+func (SysPostRole) TableName() string {
+	return tableName("sys_post_role")
+}
+
 type SysDictType struct {
+	// 字典类型在租户内唯一
+	TenantID int64  `gorm:"column:tenant_id;not null;default:1;index;uniqueIndex:idx_sys_dict_type_tenant,priority:1" json:"tenant_id"`
 	DictName string `gorm:"column:dict_name" json:"dict_name"`
-	DictType string `gorm:"column:dict_type;unique" json:"dict_type"`
+	DictType string `gorm:"column:dict_type;uniqueIndex:idx_sys_dict_type_tenant,priority:2" json:"dict_type"`
 	Status   string `gorm:"column:status" json:"status"`
 	BaseModel
 	CreateBy string  `gorm:"column:create_by" json:"create_by"`
@@ -157,10 +190,13 @@ func (SysDictType) TableName() string {
 }
 
 type SysDictData struct {
-	DictSort  int     `gorm:"column:dict_sort" json:"dict_sort"`
-	DictLabel string  `gorm:"column:dict_label" json:"dict_label"`
-	DictValue string  `gorm:"column:dict_value" json:"dict_value"`
-	DictType  string  `gorm:"column:dict_type" json:"dict_type"`
+	TenantID  int64  `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
+	DictSort  int    `gorm:"column:dict_sort" json:"dict_sort"`
+	DictLabel string `gorm:"column:dict_label" json:"dict_label"`
+	DictValue string `gorm:"column:dict_value" json:"dict_value"`
+	DictType  string `gorm:"column:dict_type" json:"dict_type"`
+	// ParentID 上级字典数据，0 表示顶级；上下级属于同一字典类型，用于级联选项
+	ParentID  int64   `gorm:"column:parent_id;not null;default:0;index" json:"parent_id"`
 	CSSClass  *string `gorm:"column:css_class" json:"css_class,omitempty"`
 	ListClass *string `gorm:"column:list_class" json:"list_class,omitempty"`
 	IsDefault string  `gorm:"column:is_default" json:"is_default"`
@@ -176,10 +212,19 @@ func (SysDictData) TableName() string {
 }
 
 type SysConfig struct {
+	TenantID    int64  `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
 	ConfigName  string `gorm:"column:config_name" json:"config_name"`
 	ConfigKey   string `gorm:"column:config_key" json:"config_key"`
 	ConfigValue string `gorm:"column:config_value" json:"config_value"`
 	ConfigType  string `gorm:"column:config_type" json:"config_type"`
+	// ValueType 决定参数值的校验与公开接口中的返回类型
+	ValueType string `gorm:"column:value_type;type:varchar(16);not null;default:'string'" json:"value_type"`
+	// ValueSchema enum 类型为可选值组成的 JSON 数组，json 类型为 JSON Schema
+	ValueSchema *string `gorm:"column:value_schema;type:text" json:"value_schema,omitempty"`
+	// DefaultValue 参数值为空时生效
+	DefaultValue *string `gorm:"column:default_value;type:varchar(500)" json:"default_value,omitempty"`
+	// IsPublic 为 true 的参数无需登录即可读取，供登录页等使用
+	IsPublic bool `gorm:"column:is_public;not null;default:false" json:"is_public"`
 	BaseModel
 	CreateBy string  `gorm:"column:create_by" json:"create_by"`
 	UpdateBy string  `gorm:"column:update_by" json:"update_by"`
@@ -246,10 +291,19 @@ func (SysJobLogStep) TableName() string {
 }
 
 type SysNotice struct {
+	TenantID      int64  `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
 	NoticeTitle   string `gorm:"column:notice_title" json:"notice_title"`
 	NoticeType    string `gorm:"column:notice_type" json:"notice_type"`
 	NoticeContent []byte `gorm:"column:notice_content" json:"notice_content"`
 	Status        string `gorm:"column:status" json:"status"`
+	// AudienceType 接收范围：all 全员，users/roles/depts 为 SysNoticeTarget 中指定的用户、角色或部门（含下级）
+	AudienceType string `gorm:"column:audience_type;type:varchar(8);not null;default:'all'" json:"audience_type"`
+	// PublishAt 定时发布时间，为空表示保存后立即发布；ExpireAt 过期时间，为空表示长期有效
+	PublishAt *time.Time `gorm:"column:publish_at;index" json:"publish_at,omitempty"`
+	ExpireAt  *time.Time `gorm:"column:expire_at;index" json:"expire_at,omitempty"`
+	// Pinned 置顶公告排在最前，同组内按 Priority 从高到低排列
+	Pinned   bool `gorm:"column:pinned;not null;default:false" json:"pinned"`
+	Priority int  `gorm:"column:priority;not null;default:0" json:"priority"`
 	BaseModel
 	CreateBy string  `gorm:"column:create_by" json:"create_by"`
 	UpdateBy string  `gorm:"column:update_by" json:"update_by"`
@@ -259,3 +313,135 @@ type SysNotice struct {
 func (SysNotice) TableName() string {
 	return tableName("sys_notice")
 }
+
+// SysNoticeTarget 公告的接收对象，TargetID 按公告的 AudienceType 指向用户、角色或部门
+type SysNoticeTarget struct {
+	TenantID int64 `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
+	NoticeID int64 `gorm:"column:notice_id;primaryKey" json:"notice_id"`
+	TargetID int64 `gorm:"column:target_id;primaryKey" json:"target_id"`
+}
+
+func (SysNoticeTarget) TableName() string {
+	return tableName("sys_notice_target")
+}
+
+// SysNoticeRead 用户的公告已读记录
+type SysNoticeRead struct {
+	TenantID int64     `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
+	NoticeID int64     `gorm:"column:notice_id;primaryKey" json:"notice_id"`
+	UserID   int64     `gorm:"column:user_id;primaryKey;index" json:"user_id"`
+	ReadAt   time.Time `gorm:"column:read_at;not null" json:"read_at"`
+}
+
+func (SysNoticeRead) TableName() string {
+	return tableName("sys_notice_read")
+}
+
+// SysFile 上传文件元数据，文件内容由存储驱动保存
+type SysFile struct {
+	TenantID     int64  `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
+	OriginalName string `gorm:"column:original_name;type:varchar(255);not null" json:"original_name"`
+	StorageKey   string `gorm:"column:storage_key;type:varchar(512);not null;uniqueIndex" json:"storage_key"`
+	Driver       string `gorm:"column:driver;type:varchar(16);not null" json:"driver"`
+	Size         int64  `gorm:"column:size;not null" json:"size"`
+	ContentType  string `gorm:"column:content_type;type:varchar(128)" json:"content_type"`
+	Checksum     string `gorm:"column:checksum;type:varchar(64);index" json:"checksum"`
+	OwnerID      int64  `gorm:"column:owner_id;index" json:"owner_id"`
+	Module       string `gorm:"column:module;type:varchar(64);index:idx_sys_file_module_ref" json:"module"`
+	RefID        string `gorm:"column:ref_id;type:varchar(64);index:idx_sys_file_module_ref" json:"ref_id"`
+	BaseModel
+	CreateBy string `gorm:"column:create_by" json:"create_by"`
+	UpdateBy string `gorm:"column:update_by" json:"update_by"`
+}
+
+func (SysFile) TableName() string {
+	return tableName("sys_file")
+}
+
+// SysTenant 租户，业务数据通过 tenant_id 归属到租户
+type SysTenant struct {
+	TenantCode string `gorm:"column:tenant_code;type:varchar(64);not null;uniqueIndex" json:"tenant_code"`
+	TenantName string `gorm:"column:tenant_name;type:varchar(128);not null" json:"tenant_name"`
+	Status     string `gorm:"column:status;type:varchar(1);not null;default:'0'" json:"status"`
+	BaseModel
+	CreateBy string  `gorm:"column:create_by" json:"create_by"`
+	UpdateBy string  `gorm:"column:update_by" json:"update_by"`
+	Remark   *string `gorm:"column:remark" json:"remark,omitempty"`
+}
+
+func (SysTenant) TableName() string {
+	return tableName("sys_tenant")
+}
+
+// SysChangeLog 实体的字段级变更记录，Changes 为 JSON 编码的字段差异
+type SysChangeLog struct {
+	TenantID  int64  `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
+	Module    string `gorm:"column:module;type:varchar(32);not null;index:idx_sys_change_log_entity,priority:1" json:"module"`
+	EntityID  int64  `gorm:"column:entity_id;not null;index:idx_sys_change_log_entity,priority:2" json:"entity_id"`
+	Action    string `gorm:"column:action;type:varchar(16);not null" json:"action"`
+	Changes   string `gorm:"column:changes;type:text" json:"changes"`
+	Operator  string `gorm:"column:operator;type:varchar(64)" json:"operator"`
+	RequestID string `gorm:"column:request_id;type:varchar(64);index" json:"request_id"`
+	// RollbackTo 回滚操作恢复到的变更记录ID
+	RollbackTo *int64 `gorm:"column:rollback_to" json:"rollback_to,omitempty"`
+	BaseModel
+}
+
+func (SysChangeLog) TableName() string {
+	return tableName("sys_change_log")
+}
+
+// SysUserAttrDef 管理员定义的用户扩展属性，select 类型的可选项取自 DictType 对应的字典数据
+type SysUserAttrDef struct {
+	// 属性键在租户内唯一，创建后不可修改
+	TenantID  int64    `gorm:"column:tenant_id;not null;default:1;index;uniqueIndex:idx_sys_user_attr_def_key,priority:1" json:"tenant_id"`
+	AttrKey   string   `gorm:"column:attr_key;type:varchar(64);not null;uniqueIndex:idx_sys_user_attr_def_key,priority:2" json:"attr_key"`
+	AttrLabel string   `gorm:"column:attr_label;type:varchar(100);not null" json:"attr_label"`
+	AttrType  string   `gorm:"column:attr_type;type:varchar(16);not null" json:"attr_type"`
+	Required  bool     `gorm:"column:required;not null;default:false" json:"required"`
+	Pattern   *string  `gorm:"column:pattern;type:varchar(255)" json:"pattern,omitempty"`
+	MaxLength int      `gorm:"column:max_length;not null;default:0" json:"max_length"`
+	MinValue  *float64 `gorm:"column:min_value" json:"min_value,omitempty"`
+	MaxValue  *float64 `gorm:"column:max_value" json:"max_value,omitempty"`
+	DictType  *string  `gorm:"column:dict_type;type:varchar(100)" json:"dict_type,omitempty"`
+	SortOrder int      `gorm:"column:sort_order;not null;default:0" json:"sort_order"`
+	Status    string   `gorm:"column:status;type:varchar(1);not null;default:'0'" json:"status"`
+	BaseModel
+	CreateBy string  `gorm:"column:create_by" json:"create_by"`
+	UpdateBy string  `gorm:"column:update_by" json:"update_by"`
+	Remark   *string `gorm:"column:remark" json:"remark,omitempty"`
+}
+
+func (SysUserAttrDef) TableName() string {
+	return tableName("sys_user_attr_def")
+}
+
+// SysUserAttr 用户扩展属性取值，值统一按规范化后的字符串存储
+type SysUserAttr struct {
+	TenantID  int64  `gorm:"column:tenant_id;not null;default:1;index" json:"tenant_id"`
+	UserID    int64  `gorm:"column:user_id;primaryKey" json:"user_id"`
+	AttrKey   string `gorm:"column:attr_key;type:varchar(64);primaryKey;index:idx_sys_user_attr_value,priority:1" json:"attr_key"`
+	AttrValue string `gorm:"column:attr_value;type:varchar(500);not null;index:idx_sys_user_attr_value,priority:2" json:"attr_value"`
+}
+
+func (SysUserAttr) TableName() string {
+	return tableName("sys_user_attr")
+}
+
+// SysTranslation 字典标签、菜单名称等的多语言译文，未翻译时使用原始名称。
+// 字典译文以字典类型为 Scope、字典键值为 ItemKey；菜单译文 Scope 为空、ItemKey 为菜单ID
+type SysTranslation struct {
+	TenantID int64  `gorm:"column:tenant_id;not null;default:1;index;uniqueIndex:idx_sys_translation_key,priority:1" json:"tenant_id"`
+	Resource string `gorm:"column:resource;type:varchar(16);not null;uniqueIndex:idx_sys_translation_key,priority:2" json:"resource"`
+	Scope    string `gorm:"column:scope;type:varchar(100);not null;default:'';uniqueIndex:idx_sys_translation_key,priority:3" json:"scope"`
+	ItemKey  string `gorm:"column:item_key;type:varchar(100);not null;uniqueIndex:idx_sys_translation_key,priority:4" json:"item_key"`
+	Locale   string `gorm:"column:locale;type:varchar(16);not null;uniqueIndex:idx_sys_translation_key,priority:5" json:"locale"`
+	Text     string `gorm:"column:text;type:varchar(255);not null" json:"text"`
+	BaseModel
+	CreateBy string `gorm:"column:create_by" json:"create_by"`
+	UpdateBy string `gorm:"column:update_by" json:"update_by"`
+}
+
+func (SysTranslation) TableName() string {
+	return tableName("sys_translation")
+}
@@ -0,0 +1,73 @@
+package router
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RouteDefinition describes a route registered through registerRouteWithPermissions.
+type RouteDefinition struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Permissions []string `json:"permissions"`
+	Description string   `json:"description"`
+}
+
+type routeRegistry struct {
+	mu     sync.RWMutex
+	routes map[string]RouteDefinition
+}
+
+// 路由注册表为进程级单例，多次构建引擎（如测试）时按 method+path 去重
+var registeredRoutes = &routeRegistry{routes: make(map[string]RouteDefinition)}
+
+func (r *routeRegistry) record(method, fullPath string, permissions []string, description string) {
+	perms := make([]string, 0, len(permissions))
+	for _, perm := range permissions {
+		if trimmed := strings.TrimSpace(perm); trimmed != "" {
+			perms = append(perms, trimmed)
+		}
+	}
+
+	def := RouteDefinition{
+		Method:      strings.ToUpper(method),
+		Path:        fullPath,
+		Permissions: perms,
+		Description: description,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[def.Method+" "+def.Path] = def
+}
+
+func (r *routeRegistry) list() []RouteDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]RouteDefinition, 0, len(r.routes))
+	for _, def := range r.routes {
+		def.Permissions = append([]string(nil), def.Permissions...)
+		items = append(items, def)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Path == items[j].Path {
+			return items[i].Method < items[j].Method
+		}
+		return items[i].Path < items[j].Path
+	})
+	return items
+}
+
+// RegisteredRoutes returns every route registered with permission metadata, sorted by path and method.
+func RegisteredRoutes() []RouteDefinition {
+	return registeredRoutes.list()
+}
+
+func joinRoutePath(basePath, relativePath string) string {
+	if relativePath == "" {
+		return basePath
+	}
+	return strings.TrimRight(basePath, "/") + "/" + strings.TrimLeft(relativePath, "/")
+}
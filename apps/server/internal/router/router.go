@@ -29,17 +29,26 @@ import (
 	"github.com/starter-kit-fe/admin/internal/system/dept"
 	"github.com/starter-kit-fe/admin/internal/system/dict"
 	"github.com/starter-kit-fe/admin/internal/system/docs"
+	"github.com/starter-kit-fe/admin/internal/system/file"
 	"github.com/starter-kit-fe/admin/internal/system/health"
+	"github.com/starter-kit-fe/admin/internal/system/history"
+	"github.com/starter-kit-fe/admin/internal/system/i18n"
 	jobhandler "github.com/starter-kit-fe/admin/internal/system/job/handler"
 	"github.com/starter-kit-fe/admin/internal/system/loginlog"
+	"github.com/starter-kit-fe/admin/internal/system/manifest"
 	"github.com/starter-kit-fe/admin/internal/system/menu"
 	"github.com/starter-kit-fe/admin/internal/system/notice"
 	"github.com/starter-kit-fe/admin/internal/system/online"
 	"github.com/starter-kit-fe/admin/internal/system/operlog"
+	"github.com/starter-kit-fe/admin/internal/system/permission"
 	"github.com/starter-kit-fe/admin/internal/system/post"
+	"github.com/starter-kit-fe/admin/internal/system/recycle"
 	"github.com/starter-kit-fe/admin/internal/system/role"
+	"github.com/starter-kit-fe/admin/internal/system/scim"
 	"github.com/starter-kit-fe/admin/internal/system/server"
+	"github.com/starter-kit-fe/admin/internal/system/tenant"
 	"github.com/starter-kit-fe/admin/internal/system/user"
+	"github.com/starter-kit-fe/admin/internal/system/userattr"
 	"github.com/starter-kit-fe/admin/middleware"
 	"github.com/starter-kit-fe/admin/pkg/resp"
 )
@@ -51,13 +60,22 @@ type Options struct {
 	DocsHandler        *docs.Handler
 	AuthHandler        *auth.Handler
 	UserHandler        *user.Handler
+	UserAttrHandler    *userattr.Handler
 	RoleHandler        *role.Handler
 	MenuHandler        *menu.Handler
 	DeptHandler        *dept.Handler
 	PostHandler        *post.Handler
 	DictHandler        *dict.Handler
+	I18nHandler        *i18n.Handler
 	ConfigHandler      *sysconfig.Handler
 	NoticeHandler      *notice.Handler
+	PermissionHandler  *permission.Handler
+	FileHandler        *file.Handler
+	RecycleHandler     *recycle.Handler
+	ManifestHandler    *manifest.Handler
+	SCIMHandler        *scim.Handler
+	TenantHandler      *tenant.Handler
+	HistoryHandler     *history.Handler
 	OperLogHandler     *operlog.Handler
 	LoginLogHandler    *loginlog.Handler
 	JobHandler         *jobhandler.Handler
@@ -83,6 +101,7 @@ func New(opts Options) *gin.Engine {
 
 	engine := gin.New()
 	engine.Use(gin.Recovery())
+	engine.Use(middleware.RequestID())
 	engine.Use(middleware.RequestLogger(opts.Logger))
 
 	frontend := newFrontendHandler(opts.FrontendDir)
@@ -134,6 +153,7 @@ func registerAPIRoutes(engine *gin.Engine, opts Options) {
 	api := engine.Group(apiRootPrefix)
 	registerHealthRoutes(api, opts)
 	registerDocsRoutes(api, opts)
+	registerSCIMRoutes(api, opts)
 	versionedAPI := api.Group(apiVersionPrefix)
 	registerPublicRoutes(versionedAPI, opts)
 	registerProtectedRoutes(versionedAPI, opts)
@@ -171,6 +191,7 @@ func registerRouteWithPermissions(
 	}
 	handlers = append(handlers, handler)
 	group.Handle(method, relativePath, handlers...)
+	registeredRoutes.record(method, joinRoutePath(group.BasePath(), relativePath), permissions, description)
 }
 
 func requireHandler(name string, handler interface{}) {
@@ -192,6 +213,37 @@ func registerDocsRoutes(group *gin.RouterGroup, opts Options) {
 	group.GET("/docs", opts.DocsHandler.SwaggerUI)
 }
 
+// registerSCIMRoutes 注册身份源同步接口，使用独立的 Bearer 令牌鉴权，不经过用户登录与权限校验
+func registerSCIMRoutes(api *gin.RouterGroup, opts Options) {
+	if opts.SCIMHandler == nil {
+		return
+	}
+	group := api.Group(strings.TrimPrefix(scim.BasePath, apiRootPrefix))
+	for _, mw := range opts.PublicMWs {
+		if mw != nil {
+			group.Use(mw)
+		}
+	}
+	group.Use(opts.SCIMHandler.Authenticate)
+
+	group.GET("/ServiceProviderConfig", opts.SCIMHandler.ServiceProviderConfig)
+	group.GET("/ResourceTypes", opts.SCIMHandler.ResourceTypes)
+
+	group.GET("/Users", opts.SCIMHandler.ListUsers)
+	group.POST("/Users", opts.SCIMHandler.CreateUser)
+	group.GET("/Users/:id", opts.SCIMHandler.GetUser)
+	group.PUT("/Users/:id", opts.SCIMHandler.ReplaceUser)
+	group.PATCH("/Users/:id", opts.SCIMHandler.PatchUser)
+	group.DELETE("/Users/:id", opts.SCIMHandler.DeleteUser)
+
+	group.GET("/Groups", opts.SCIMHandler.ListGroups)
+	group.POST("/Groups", opts.SCIMHandler.CreateGroup)
+	group.GET("/Groups/:id", opts.SCIMHandler.GetGroup)
+	group.PUT("/Groups/:id", opts.SCIMHandler.ReplaceGroup)
+	group.PATCH("/Groups/:id", opts.SCIMHandler.PatchGroup)
+	group.DELETE("/Groups/:id", opts.SCIMHandler.DeleteGroup)
+}
+
 func isAPIRoute(pathname string) bool {
 	if pathname == apiRootPrefix || strings.HasPrefix(pathname, apiRootPrefix+"/") {
 		return true
@@ -309,6 +361,9 @@ func registerPublicRoutes(api *gin.RouterGroup, opts Options) {
 
 	registerAuthRoutes(public, opts)
 	registerCaptchaRoutes(public, opts)
+	registerPublicFileRoutes(public, opts)
+	registerPublicConfigRoutes(public, opts)
+	registerPublicNoticeRoutes(public, opts)
 }
 
 func registerAuthRoutes(group *gin.RouterGroup, opts Options) {
@@ -334,6 +389,32 @@ func registerCaptchaRoutes(group *gin.RouterGroup, opts Options) {
 	group.POST("/auth/captcha/verify", opts.CaptchaHandler.Verify)
 }
 
+func registerPublicFileRoutes(group *gin.RouterGroup, opts Options) {
+	if opts.FileHandler == nil {
+		return
+	}
+	// 签名链接自带鉴权参数，供本地存储驱动的限时下载使用
+	group.GET("/files/:id", opts.FileHandler.SignedDownload)
+	// 头像等公开模块的文件
+	group.GET("/public/files/:id", opts.FileHandler.PublicDownload)
+}
+
+func registerPublicConfigRoutes(group *gin.RouterGroup, opts Options) {
+	if opts.ConfigHandler == nil {
+		return
+	}
+	// 登录页在登录前读取站点名称、验证码开关等公开参数
+	group.GET("/public/configs", opts.ConfigHandler.Public)
+}
+
+func registerPublicNoticeRoutes(group *gin.RouterGroup, opts Options) {
+	if opts.NoticeHandler == nil {
+		return
+	}
+	// 登录页展示面向全员的有效公告
+	group.GET("/public/notices", opts.NoticeHandler.Public)
+}
+
 func registerProtectedRoutes(api *gin.RouterGroup, opts Options) {
 	protected := api.Group("")
 	protected.Use(middleware.NewJWTAuthMiddleware(middleware.JWTAuthOptions{
@@ -371,8 +452,15 @@ func registerSystemRoutes(group *gin.RouterGroup, opts Options) {
 	requireHandler("DeptHandler", opts.DeptHandler)
 	requireHandler("PostHandler", opts.PostHandler)
 	requireHandler("DictHandler", opts.DictHandler)
+	requireHandler("I18nHandler", opts.I18nHandler)
 	requireHandler("ConfigHandler", opts.ConfigHandler)
 	requireHandler("NoticeHandler", opts.NoticeHandler)
+	requireHandler("PermissionHandler", opts.PermissionHandler)
+	requireHandler("FileHandler", opts.FileHandler)
+	requireHandler("RecycleHandler", opts.RecycleHandler)
+	requireHandler("ManifestHandler", opts.ManifestHandler)
+	requireHandler("TenantHandler", opts.TenantHandler)
+	requireHandler("HistoryHandler", opts.HistoryHandler)
 
 	system := group.Group("/system")
 
@@ -382,15 +470,18 @@ func registerSystemRoutes(group *gin.RouterGroup, opts Options) {
 	registerRouteWithPermissions(profile, http.MethodGet, "", nil, opts.UserHandler.GetProfile, "get profile")
 	registerRouteWithPermissions(profile, http.MethodPut, "", nil, opts.UserHandler.UpdateProfile, "update profile")
 	registerRouteWithPermissions(profile, http.MethodPut, "/password", nil, opts.UserHandler.ChangePassword, "change password")
+	registerRouteWithPermissions(profile, http.MethodPost, "/avatar", nil, opts.UserHandler.UploadAvatar, "upload avatar")
 	registerRouteWithPermissions(profile, http.MethodGet, "/sessions", nil, opts.UserHandler.ListSelfSessions, "list own sessions")
 	registerRouteWithPermissions(profile, http.MethodPost, "/sessions/:id/force-logout", nil, opts.UserHandler.ForceLogoutSelfSession, "force logout own session")
 
 	roles := system.Group("/roles")
 	registerRouteWithPermissions(roles, http.MethodGet, "", []string{"system:role:list"}, opts.RoleHandler.List, "list roles")
 	registerRouteWithPermissions(roles, http.MethodPost, "", []string{"system:role:add"}, opts.RoleHandler.Create, "create role")
+	registerRouteWithPermissions(roles, http.MethodGet, "/export", []string{"system:role:export"}, opts.RoleHandler.Export, "export roles")
 	registerRouteWithPermissions(roles, http.MethodGet, "/:id", []string{"system:role:query"}, opts.RoleHandler.Get, "get role")
 	registerRouteWithPermissions(roles, http.MethodPut, "/:id", []string{"system:role:edit"}, opts.RoleHandler.Update, "update role")
 	registerRouteWithPermissions(roles, http.MethodDelete, "/:id", []string{"system:role:remove"}, opts.RoleHandler.Delete, "delete role")
+	registerRouteWithPermissions(roles, http.MethodGet, "/:id/history", []string{"system:role:query"}, opts.HistoryHandler.List(history.ModuleRole, "id"), "list role history")
 
 	menus := system.Group("/menus")
 	registerRouteWithPermissions(menus, http.MethodGet, "/tree", []string{"system:menu:list"}, opts.MenuHandler.Tree, "list menu tree")
@@ -399,19 +490,25 @@ func registerSystemRoutes(group *gin.RouterGroup, opts Options) {
 	registerRouteWithPermissions(menus, http.MethodPut, "/:id", []string{"system:menu:edit"}, opts.MenuHandler.Update, "update menu")
 	registerRouteWithPermissions(menus, http.MethodPut, "/reorder", []string{"system:menu:edit"}, opts.MenuHandler.Reorder, "reorder menus")
 	registerRouteWithPermissions(menus, http.MethodDelete, "/:id", []string{"system:menu:remove"}, opts.MenuHandler.Delete, "delete menu")
+	registerRouteWithPermissions(menus, http.MethodGet, "/:id/history", []string{"system:menu:query"}, opts.HistoryHandler.List(history.ModuleMenu, "id"), "list menu history")
 
 	departments := system.Group("/departments")
 	registerRouteWithPermissions(departments, http.MethodGet, "/tree", []string{"system:dept:list"}, opts.DeptHandler.Tree, "list department tree")
 	registerRouteWithPermissions(departments, http.MethodGet, "", []string{"system:dept:list"}, opts.DeptHandler.List, "list departments")
 	registerRouteWithPermissions(departments, http.MethodPost, "", []string{"system:dept:add"}, opts.DeptHandler.Create, "create department")
+	registerRouteWithPermissions(departments, http.MethodGet, "/export", []string{"system:dept:export"}, opts.DeptHandler.Export, "export departments")
 	registerRouteWithPermissions(departments, http.MethodGet, "/:id", []string{"system:dept:query"}, opts.DeptHandler.Get, "get department")
 	registerRouteWithPermissions(departments, http.MethodPut, "/:id", []string{"system:dept:edit"}, opts.DeptHandler.Update, "update department")
 	registerRouteWithPermissions(departments, http.MethodDelete, "/:id", []string{"system:dept:remove"}, opts.DeptHandler.Delete, "delete department")
+	registerRouteWithPermissions(departments, http.MethodGet, "/:id/history", []string{"system:dept:query"}, opts.HistoryHandler.List(history.ModuleDept, "id"), "list department history")
+	registerRouteWithPermissions(departments, http.MethodGet, "/:id/merge/preview", []string{"system:dept:merge"}, opts.DeptHandler.MergePreview, "preview department merge")
+	registerRouteWithPermissions(departments, http.MethodPost, "/:id/merge", []string{"system:dept:merge"}, opts.DeptHandler.Merge, "merge department")
 
 	posts := system.Group("/posts")
 	registerRouteWithPermissions(posts, http.MethodGet, "", []string{"system:post:list"}, opts.PostHandler.List, "list posts")
 	registerRouteWithPermissions(posts, http.MethodPost, "", []string{"system:post:add"}, opts.PostHandler.Create, "create post")
-	registerRouteWithPermissions(posts, http.MethodGet, "/:id", []string{"system:post:query"}, notImplemented("get post"), "get post")
+	registerRouteWithPermissions(posts, http.MethodGet, "/export", []string{"system:post:export"}, opts.PostHandler.Export, "export posts")
+	registerRouteWithPermissions(posts, http.MethodGet, "/:id", []string{"system:post:query"}, opts.PostHandler.Get, "get post")
 	registerRouteWithPermissions(posts, http.MethodPut, "/:id", []string{"system:post:edit"}, opts.PostHandler.Update, "update post")
 	registerRouteWithPermissions(posts, http.MethodDelete, "/:id", []string{"system:post:remove"}, opts.PostHandler.Delete, "delete post")
 
@@ -421,10 +518,31 @@ func registerSystemRoutes(group *gin.RouterGroup, opts Options) {
 	registerRouteWithPermissions(dicts, http.MethodGet, "/:id", []string{"system:dict:query"}, opts.DictHandler.Get, "get dictionary")
 	registerRouteWithPermissions(dicts, http.MethodPut, "/:id", []string{"system:dict:edit"}, opts.DictHandler.Update, "update dictionary")
 	registerRouteWithPermissions(dicts, http.MethodDelete, "/:id", []string{"system:dict:remove"}, opts.DictHandler.Delete, "delete dictionary")
+	registerRouteWithPermissions(dicts, http.MethodGet, "/:id/history", []string{"system:dict:query"}, opts.HistoryHandler.List(history.ModuleDict, "id"), "list dictionary history")
 	registerRouteWithPermissions(dicts, http.MethodGet, "/:id/data", []string{"system:dict:list"}, opts.DictHandler.ListData, "list dictionary data")
 	registerRouteWithPermissions(dicts, http.MethodPost, "/:id/data", []string{"system:dict:add"}, opts.DictHandler.CreateData, "create dictionary data")
+	registerRouteWithPermissions(dicts, http.MethodPost, "/:id/data/import", []string{"system:dict:import"}, opts.DictHandler.ImportData, "import dictionary data")
+	registerRouteWithPermissions(dicts, http.MethodGet, "/:id/data/:itemId", []string{"system:dict:query"}, opts.DictHandler.GetData, "get dictionary data")
 	registerRouteWithPermissions(dicts, http.MethodPut, "/:id/data/:itemId", []string{"system:dict:edit"}, opts.DictHandler.UpdateData, "update dictionary data")
 	registerRouteWithPermissions(dicts, http.MethodDelete, "/:id/data/:itemId", []string{"system:dict:remove"}, opts.DictHandler.DeleteData, "delete dictionary data")
+	registerRouteWithPermissions(dicts, http.MethodGet, "/:id/data/:itemId/history", []string{"system:dict:query"}, opts.HistoryHandler.List(history.ModuleDictData, "itemId"), "list dictionary data history")
+
+	// 字典选项供各页面下拉框等组件使用，登录即可读取
+	options := group.Group("/dicts/types")
+	registerRouteWithPermissions(options, http.MethodGet, "", nil, opts.DictHandler.BatchOptions, "lookup dictionary options")
+	registerRouteWithPermissions(options, http.MethodGet, "/:type", nil, opts.DictHandler.Options, "get dictionary options")
+	registerRouteWithPermissions(options, http.MethodGet, "/:type/tree", nil, opts.DictHandler.TreeOptions, "get dictionary option tree")
+	registerRouteWithPermissions(options, http.MethodGet, "/:type/children", nil, opts.DictHandler.ChildOptions, "get child dictionary options")
+
+	translations := system.Group("/translations")
+	registerRouteWithPermissions(translations, http.MethodGet, "", []string{"system:i18n:list"}, opts.I18nHandler.List, "list translations")
+	registerRouteWithPermissions(translations, http.MethodPut, "", []string{"system:i18n:edit"}, opts.I18nHandler.Save, "save translation")
+	registerRouteWithPermissions(translations, http.MethodGet, "/export", []string{"system:i18n:export"}, opts.I18nHandler.Export, "export translation bundle")
+	registerRouteWithPermissions(translations, http.MethodPost, "/import", []string{"system:i18n:import"}, opts.I18nHandler.Import, "import translation bundle")
+	registerRouteWithPermissions(translations, http.MethodDelete, "/:id", []string{"system:i18n:remove"}, opts.I18nHandler.Delete, "delete translation")
+
+	// 可选语言供语言切换组件使用，登录即可读取
+	registerRouteWithPermissions(group, http.MethodGet, "/i18n/locales", nil, opts.I18nHandler.Locales, "list locales")
 
 	configs := system.Group("/configs")
 	registerRouteWithPermissions(configs, http.MethodGet, "", []string{"system:config:list"}, opts.ConfigHandler.List, "list configs")
@@ -432,6 +550,8 @@ func registerSystemRoutes(group *gin.RouterGroup, opts Options) {
 	registerRouteWithPermissions(configs, http.MethodGet, "/:id", []string{"system:config:query"}, opts.ConfigHandler.Get, "get config")
 	registerRouteWithPermissions(configs, http.MethodPut, "/:id", []string{"system:config:edit"}, opts.ConfigHandler.Update, "update config")
 	registerRouteWithPermissions(configs, http.MethodDelete, "/:id", []string{"system:config:remove"}, opts.ConfigHandler.Delete, "delete config")
+	registerRouteWithPermissions(configs, http.MethodGet, "/:id/history", []string{"system:config:query"}, opts.HistoryHandler.List(history.ModuleConfig, "id"), "list config history")
+	registerRouteWithPermissions(configs, http.MethodPost, "/:id/history/:revisionId/rollback", []string{"system:config:edit"}, opts.ConfigHandler.Rollback, "roll back config")
 
 	notices := system.Group("/notices")
 	registerRouteWithPermissions(notices, http.MethodGet, "", []string{"system:notice:list"}, opts.NoticeHandler.List, "list notices")
@@ -439,10 +559,51 @@ func registerSystemRoutes(group *gin.RouterGroup, opts Options) {
 	registerRouteWithPermissions(notices, http.MethodGet, "/:id", []string{"system:notice:query"}, opts.NoticeHandler.Get, "get notice")
 	registerRouteWithPermissions(notices, http.MethodPut, "/:id", []string{"system:notice:edit"}, opts.NoticeHandler.Update, "update notice")
 	registerRouteWithPermissions(notices, http.MethodDelete, "/:id", []string{"system:notice:remove"}, opts.NoticeHandler.Delete, "delete notice")
+	registerRouteWithPermissions(notices, http.MethodGet, "/:id/reads", []string{"system:notice:query"}, opts.NoticeHandler.ReadStats, "get notice read statistics")
+
+	// 个人公告收件箱，登录即可访问
+	inbox := group.Group("/notices")
+	registerRouteWithPermissions(inbox, http.MethodGet, "/inbox", nil, opts.NoticeHandler.Inbox, "list my notices")
+	registerRouteWithPermissions(inbox, http.MethodGet, "/unread-count", nil, opts.NoticeHandler.UnreadCount, "count my unread notices")
+	registerRouteWithPermissions(inbox, http.MethodPost, "/read-all", nil, opts.NoticeHandler.MarkAllRead, "mark all notices as read")
+	registerRouteWithPermissions(inbox, http.MethodPost, "/:id/read", nil, opts.NoticeHandler.MarkRead, "mark notice as read")
+
+	files := system.Group("/files")
+	registerRouteWithPermissions(files, http.MethodGet, "", []string{"system:file:list"}, opts.FileHandler.List, "list files")
+	registerRouteWithPermissions(files, http.MethodPost, "", []string{"system:file:upload"}, opts.FileHandler.Upload, "upload file")
+	registerRouteWithPermissions(files, http.MethodGet, "/:id", []string{"system:file:list"}, opts.FileHandler.Get, "get file")
+	registerRouteWithPermissions(files, http.MethodGet, "/:id/download", []string{"system:file:download"}, opts.FileHandler.Download, "download file")
+	registerRouteWithPermissions(files, http.MethodGet, "/:id/presign", []string{"system:file:download"}, opts.FileHandler.Presign, "presign file download")
+	registerRouteWithPermissions(files, http.MethodDelete, "/:id", []string{"system:file:remove"}, opts.FileHandler.Delete, "delete file")
+
+	recycleBin := system.Group("/recycle-bin")
+	registerRouteWithPermissions(recycleBin, http.MethodGet, "", []string{"system:recycle:list"}, opts.RecycleHandler.Summary, "summarize recycle bin")
+	registerRouteWithPermissions(recycleBin, http.MethodGet, "/:resource", []string{"system:recycle:list"}, opts.RecycleHandler.List, "list deleted records")
+	registerRouteWithPermissions(recycleBin, http.MethodPost, "/:resource/:id/restore", []string{"system:recycle:restore"}, opts.RecycleHandler.Restore, "restore deleted record")
+	registerRouteWithPermissions(recycleBin, http.MethodDelete, "/:resource/:id", []string{"system:recycle:remove"}, opts.RecycleHandler.Purge, "purge deleted record")
+
+	manifests := system.Group("/manifest")
+	registerRouteWithPermissions(manifests, http.MethodGet, "/export", []string{"system:manifest:export"}, opts.ManifestHandler.Export, "export rbac manifest")
+	registerRouteWithPermissions(manifests, http.MethodPost, "/sync", []string{"system:manifest:sync"}, opts.ManifestHandler.Sync, "sync rbac manifest")
+
+	tenants := system.Group("/tenants")
+	registerRouteWithPermissions(tenants, http.MethodGet, "", []string{"system:tenant:list"}, opts.TenantHandler.List, "list tenants")
+	registerRouteWithPermissions(tenants, http.MethodPost, "", []string{"system:tenant:add"}, opts.TenantHandler.Create, "create tenant")
+	registerRouteWithPermissions(tenants, http.MethodGet, "/:id", []string{"system:tenant:query"}, opts.TenantHandler.Get, "get tenant")
+	registerRouteWithPermissions(tenants, http.MethodPut, "/:id", []string{"system:tenant:edit"}, opts.TenantHandler.Update, "update tenant")
+	registerRouteWithPermissions(tenants, http.MethodDelete, "/:id", []string{"system:tenant:remove"}, opts.TenantHandler.Delete, "delete tenant")
+	if opts.AuthHandler != nil {
+		registerRouteWithPermissions(tenants, http.MethodPost, "/:id/switch", []string{"system:tenant:switch"}, opts.AuthHandler.SwitchTenant, "switch tenant")
+	}
+
+	permissions := system.Group("/permissions")
+	registerRouteWithPermissions(permissions, http.MethodGet, "/routes", []string{"system:permission:list"}, opts.PermissionHandler.ListRoutes, "list route permissions")
+	registerRouteWithPermissions(permissions, http.MethodGet, "/audit", []string{"system:permission:audit"}, opts.PermissionHandler.Audit, "audit route and menu permissions")
 }
 
 func registerSystemUserRoutes(system *gin.RouterGroup, opts Options) {
 	requireHandler("UserHandler", opts.UserHandler)
+	requireHandler("UserAttrHandler", opts.UserAttrHandler)
 
 	users := system.Group("/users")
 	registerRouteWithPermissions(users, http.MethodGet, "", []string{"system:user:list"}, opts.UserHandler.List, "list users")
@@ -453,7 +614,19 @@ func registerSystemUserRoutes(system *gin.RouterGroup, opts Options) {
 	registerRouteWithPermissions(users, http.MethodGet, "/options/departments", []string{"system:user:list"}, opts.UserHandler.ListDepartmentOptions, "list department options")
 	registerRouteWithPermissions(users, http.MethodGet, "/options/roles", []string{"system:user:list"}, opts.UserHandler.ListRoleOptions, "list role options")
 	registerRouteWithPermissions(users, http.MethodGet, "/options/posts", []string{"system:user:list"}, opts.UserHandler.ListPostOptions, "list post options")
+	registerRouteWithPermissions(users, http.MethodGet, "/export", []string{"system:user:export"}, opts.UserHandler.Export, "export users")
+	registerRouteWithPermissions(users, http.MethodPost, "/import", []string{"system:user:import"}, opts.UserHandler.Import, "import users")
+	registerRouteWithPermissions(users, http.MethodGet, "/import/template", []string{"system:user:import"}, opts.UserHandler.ImportTemplate, "download user import template")
 	registerRouteWithPermissions(users, http.MethodPost, "/:id/reset-password", []string{"system:user:resetPwd"}, opts.UserHandler.ResetPassword, "reset user password")
+	registerRouteWithPermissions(users, http.MethodPost, "/:id/avatar", []string{"system:user:edit"}, opts.UserHandler.UploadUserAvatar, "upload user avatar")
+	registerRouteWithPermissions(users, http.MethodGet, "/:id/history", []string{"system:user:query"}, opts.HistoryHandler.List(history.ModuleUser, "id"), "list user history")
+
+	attributes := system.Group("/user-attributes")
+	registerRouteWithPermissions(attributes, http.MethodGet, "", []string{"system:user:list"}, opts.UserAttrHandler.List, "list user attributes")
+	registerRouteWithPermissions(attributes, http.MethodPost, "", []string{"system:user:attr"}, opts.UserAttrHandler.Create, "create user attribute")
+	registerRouteWithPermissions(attributes, http.MethodGet, "/:id", []string{"system:user:query"}, opts.UserAttrHandler.Get, "get user attribute")
+	registerRouteWithPermissions(attributes, http.MethodPut, "/:id", []string{"system:user:attr"}, opts.UserAttrHandler.Update, "update user attribute")
+	registerRouteWithPermissions(attributes, http.MethodDelete, "/:id", []string{"system:user:attr"}, opts.UserAttrHandler.Delete, "delete user attribute")
 }
 
 func registerMonitorRoutes(group *gin.RouterGroup, opts Options) {
@@ -16,11 +16,14 @@ import (
 	"github.com/starter-kit-fe/admin/middleware"
 	"github.com/starter-kit-fe/admin/internal/model"
 	"github.com/starter-kit-fe/admin/internal/system/captcha"
+	"github.com/starter-kit-fe/admin/internal/system/i18n"
 	"github.com/starter-kit-fe/admin/internal/system/online"
+	"github.com/starter-kit-fe/admin/internal/system/settings"
 	jwtpkg "github.com/starter-kit-fe/admin/pkg/jwt"
 	"github.com/starter-kit-fe/admin/pkg/netutil"
 	"github.com/starter-kit-fe/admin/pkg/resp"
 	"github.com/starter-kit-fe/admin/pkg/security"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
 )
 
 type Handler struct {
@@ -40,6 +43,8 @@ type Handler struct {
 	cookieSameSite  http.SameSite
 	onlineService   *online.Service
 	sessions        *SessionStore
+	settings        *settings.Service
+	translations    *i18n.Service
 }
 
 type AuthOptions struct {
@@ -64,6 +69,8 @@ type LoginRequest struct {
 	Captcha   string `json:"captcha,omitempty" example:"8"`
 	UUID      string `json:"uuid,omitempty" example:"edbf2c533e8e44e4986b98f785bd40a4"`
 	CaptchaID string `json:"captcha_id,omitempty" example:"edbf2c533e8e44e4986b98f785bd40a4"`
+	// Tenant 租户编码，为空时登录默认租户
+	Tenant string `json:"tenant,omitempty" example:"default"`
 }
 
 type RefreshRequest struct {
@@ -122,7 +129,12 @@ type MenuNode struct {
 	Children   []*MenuNode `json:"children,omitempty"`
 }
 
-func NewHandler(repo *Repository, captcha *captcha.Service, opts AuthOptions, onlineSvc *online.Service, sessions *SessionStore) *Handler {
+// captchaEnabledKey 控制登录是否校验验证码；每次登录都经 settings 缓存读取，
+// 参数保存后的失效广播会让各副本立即生效，广播丢失时最迟在本地缓存 TTL（默认 30s）后生效，因此无需订阅
+const captchaEnabledKey = "sys.account.captchaEnabled"
+
+// NewHandler 创建认证处理器；translations 可选，用于按用户语言返回菜单名称
+func NewHandler(repo *Repository, captcha *captcha.Service, opts AuthOptions, onlineSvc *online.Service, sessions *SessionStore, settingsSvc *settings.Service, translations *i18n.Service) *Handler {
 	opts.Secret = strings.TrimSpace(opts.Secret)
 	if repo == nil || sessions == nil || opts.Secret == "" {
 		return nil
@@ -179,6 +191,8 @@ func NewHandler(repo *Repository, captcha *captcha.Service, opts AuthOptions, on
 		cookieSameSite:  cookieSameSite,
 		onlineService:   onlineSvc,
 		sessions:        sessions,
+		settings:        settingsSvc,
+		translations:    translations,
 	}
 }
 
@@ -215,7 +229,7 @@ func (h *Handler) Login(ctx *gin.Context) {
 		return
 	}
 
-	if h.captchaService != nil {
+	if h.captchaService != nil && h.settings.GetBool(ctx.Request.Context(), captchaEnabledKey, true) {
 		answer := strings.TrimSpace(payload.Code)
 		if answer == "" {
 			answer = strings.TrimSpace(payload.Captcha)
@@ -232,6 +246,21 @@ func (h *Handler) Login(ctx *gin.Context) {
 		}
 	}
 
+	tenantID, err := h.resolveLoginTenant(ctx, payload.Tenant)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrRepositoryUnavailable):
+			resp.ServiceUnavailable(ctx, resp.WithMessage("authentication service unavailable"))
+		case errors.Is(err, ErrTenantUnavailable):
+			resp.Forbidden(ctx, resp.WithMessage("tenant unavailable"))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to authenticate"))
+		}
+		return
+	}
+	// 之后的用户查询与登录日志都归属到该租户
+	ctx.Request = ctx.Request.WithContext(tenant.WithID(ctx.Request.Context(), tenantID))
+
 	user, err := h.repo.GetUserByUsername(ctx.Request.Context(), username)
 	if err != nil {
 		switch {
@@ -255,12 +284,12 @@ func (h *Handler) Login(ctx *gin.Context) {
 		return
 	}
 
-	session, refreshToken, err := h.sessions.Create(ctx.Request.Context(), uint(user.ID))
+	session, refreshToken, err := h.sessions.Create(ctx.Request.Context(), uint(user.ID), tenantID)
 	if err != nil {
 		resp.InternalServerError(ctx, resp.WithMessage("failed to create session"))
 		return
 	}
-	accessToken, expiresAt, err := h.issueAccessToken(uint(user.ID), session.SessionID)
+	accessToken, expiresAt, err := h.issueAccessToken(uint(user.ID), session.SessionID, tenantID)
 	if err != nil {
 		resp.InternalServerError(ctx, resp.WithMessage("failed to issue token"))
 		return
@@ -279,6 +308,7 @@ func (h *Handler) Login(ctx *gin.Context) {
 // @Failure 400 {object} resp.Response
 // @Failure 401 {object} resp.Response
 // @Failure 402 {object} resp.Response
+// @Failure 403 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Router /v1/auth/refresh [post]
 func (h *Handler) Refresh(ctx *gin.Context) {
@@ -325,7 +355,18 @@ func (h *Handler) Refresh(ctx *gin.Context) {
 		}
 		return
 	}
-	accessToken, expiresAt, err := h.issueAccessToken(session.UserID, session.SessionID)
+	if session.TenantID > tenant.DefaultID {
+		if _, err := h.repo.GetActiveTenant(ctx.Request.Context(), session.TenantID); err != nil {
+			if errors.Is(err, ErrTenantUnavailable) {
+				_ = h.sessions.Revoke(ctx.Request.Context(), session)
+				resp.Forbidden(ctx, resp.WithMessage("tenant unavailable"))
+			} else {
+				resp.InternalServerError(ctx, resp.WithMessage("failed to validate refresh token"))
+			}
+			return
+		}
+	}
+	accessToken, expiresAt, err := h.issueAccessToken(session.UserID, session.SessionID, session.TenantID)
 	if err != nil {
 		resp.InternalServerError(ctx, resp.WithMessage("failed to issue token"))
 		return
@@ -456,12 +497,18 @@ func (h *Handler) GetInfo(ctx *gin.Context) {
 		roles = []string{"common"}
 	}
 
+	tenantID, ok := middleware.GetTenantID(ctx)
+	if !ok {
+		tenantID = user.TenantID
+	}
+
 	ctx.JSON(200, gin.H{
 		"code": 200,
 		"msg":  "操作成功",
 		"data": gin.H{
 			"permissions": permissions,
 			"roles":       roles,
+			"tenantId":    tenantID,
 			"user": gin.H{
 				"userId":      int64(user.ID),
 				"deptId":      user.DeptID,
@@ -480,10 +527,11 @@ func (h *Handler) GetInfo(ctx *gin.Context) {
 
 // GetMenus godoc
 // @Summary 获取当前用户可访问的菜单树
-// @Description 根据当前登录用户角色返回可访问的菜单树
+// @Description 根据当前登录用户角色返回可访问的菜单树，菜单名称按用户语言偏好或 Accept-Language 翻译，未翻译时使用原始名称
 // @Tags Auth
 // @Produce json
 // @Security BearerAuth
+// @Param Accept-Language header string false "界面语言"
 // @Success 200 {object} resp.Response
 // @Failure 401 {object} resp.Response
 // @Failure 500 {object} resp.Response
@@ -510,6 +558,12 @@ func (h *Handler) GetMenus(ctx *gin.Context) {
 		return
 	}
 
+	if h.translations != nil {
+		localizer := h.translations.Localizer(ctx.Request.Context(), i18n.ResourceMenu, h.translations.RequestLocale(ctx))
+		for i := range menus {
+			menus[i].MenuName = localizer.Text("", i18n.MenuKey(int64(menus[i].ID)), menus[i].MenuName)
+		}
+	}
 	nodes := buildMenuTree(menus)
 
 	ctx.JSON(200, gin.H{
@@ -712,7 +766,7 @@ func stringPtr(value string) *string {
 	return &cp
 }
 
-func (h *Handler) issueAccessToken(userID uint, sessionID string) (string, time.Time, error) {
+func (h *Handler) issueAccessToken(userID uint, sessionID string, tenantID int64) (string, time.Time, error) {
 	if h == nil || h.jwtMaker == nil {
 		return "", time.Time{}, errors.New("jwt maker unavailable")
 	}
@@ -720,13 +774,26 @@ func (h *Handler) issueAccessToken(userID uint, sessionID string) (string, time.
 	if dur <= 0 {
 		dur = constant.JWT_EXP
 	}
-	token, err := h.jwtMaker.CreateToken(userID, sessionID, h.secret, dur)
+	token, err := h.jwtMaker.CreateToken(userID, sessionID, tenantID, h.secret, dur)
 	if err != nil {
 		return "", time.Time{}, err
 	}
 	return token, time.Now().Add(dur), nil
 }
 
+// resolveLoginTenant 按租户编码确定登录租户，未提供编码时使用默认租户
+func (h *Handler) resolveLoginTenant(ctx *gin.Context, code string) (int64, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return tenant.DefaultID, nil
+	}
+	record, err := h.repo.GetTenantByCode(ctx.Request.Context(), code)
+	if err != nil {
+		return 0, err
+	}
+	return int64(record.ID), nil
+}
+
 func (h *Handler) respondWithTokens(ctx *gin.Context, sessionID, accessToken, refreshToken string, expiresAt time.Time) {
 	if netutil.IsBrowserRequest(ctx.Request) {
 		h.setCookie(ctx, h.cookieName, accessToken, h.cookieMaxAge(h.tokenDuration))
@@ -9,10 +9,13 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
 )
 
 var (
 	ErrRepositoryUnavailable = errors.New("auth repository is not initialized")
+	// ErrTenantUnavailable 租户不存在或已停用
+	ErrTenantUnavailable = errors.New("tenant unavailable")
 )
 
 type Repository struct {
@@ -30,18 +33,26 @@ func (r *Repository) LoadPermissions(ctx context.Context, userID uint) ([]string
 	if r == nil || r.db == nil {
 		return nil, ErrRepositoryUnavailable
 	}
+	// 平台管理员可能已切换到其他租户，按用户读取的数据始终来自其所属租户
+	ctx = tenant.WithoutScope(ctx)
+
+	roleIDs, err := r.resolveMenuRoleIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(roleIDs) == 0 {
+		return []string{}, nil
+	}
 
 	var permissions []string
 	menuTable := model.SysMenu{}.TableName()
 	roleMenuTable := model.SysRoleMenu{}.TableName()
-	userRoleTable := model.SysUserRole{}.TableName()
 
 	query := r.db.WithContext(ctx).
 		Table(menuTable).
 		Select(fmt.Sprintf("DISTINCT %s.perms", menuTable)).
 		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.menu_id", roleMenuTable, menuTable, roleMenuTable)).
-		Joins(fmt.Sprintf("JOIN %s ON %s.role_id = %s.role_id", userRoleTable, roleMenuTable, userRoleTable)).
-		Where(fmt.Sprintf("%s.user_id = ? AND %s.perms IS NOT NULL AND %s.perms <> ''", userRoleTable, menuTable, menuTable), userID)
+		Where(fmt.Sprintf("%s.role_id IN ? AND %s.perms IS NOT NULL AND %s.perms <> ''", roleMenuTable, menuTable, menuTable), roleIDs)
 
 	if err := query.Pluck(fmt.Sprintf("%s.perms", menuTable), &permissions).Error; err != nil {
 		return nil, err
@@ -79,6 +90,7 @@ func (r *Repository) GetUserByID(ctx context.Context, userID uint) (*model.SysUs
 	if r == nil || r.db == nil {
 		return nil, ErrRepositoryUnavailable
 	}
+	ctx = tenant.WithoutScope(ctx)
 
 	var user model.SysUser
 	err := r.db.WithContext(ctx).
@@ -96,16 +108,23 @@ func (r *Repository) GetRoles(ctx context.Context, userID uint) ([]string, error
 	if r == nil || r.db == nil {
 		return nil, ErrRepositoryUnavailable
 	}
+	ctx = tenant.WithoutScope(ctx)
+
+	roleIDs, err := r.resolveRoleIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(roleIDs) == 0 {
+		return []string{}, nil
+	}
 
 	roleTable := model.SysRole{}.TableName()
-	userRoleTable := model.SysUserRole{}.TableName()
 
 	var roles []string
-	err := r.db.WithContext(ctx).
+	err = r.db.WithContext(ctx).
 		Table(roleTable).
 		Select(fmt.Sprintf("%s.role_key", roleTable)).
-		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.role_id", userRoleTable, roleTable, userRoleTable)).
-		Where(fmt.Sprintf("%s.user_id = ?", userRoleTable), userID).
+		Where(fmt.Sprintf("%s.id IN ?", roleTable), roleIDs).
 		Pluck(fmt.Sprintf("%s.role_key", roleTable), &roles).
 		Error
 	if err != nil {
@@ -119,10 +138,10 @@ func (r *Repository) GetMenus(ctx context.Context, userID uint) ([]model.SysMenu
 	if r == nil || r.db == nil {
 		return nil, ErrRepositoryUnavailable
 	}
+	ctx = tenant.WithoutScope(ctx)
 
 	menuTable := model.SysMenu{}.TableName()
 	roleMenuTable := model.SysRoleMenu{}.TableName()
-	userRoleTable := model.SysUserRole{}.TableName()
 
 	baseQuery := r.db.WithContext(ctx).
 		Table(menuTable).
@@ -130,10 +149,16 @@ func (r *Repository) GetMenus(ctx context.Context, userID uint) ([]model.SysMenu
 		Order(fmt.Sprintf("%s.parent_id ASC, %s.order_num ASC, %s.id ASC", menuTable, menuTable, menuTable))
 
 	if userID != 0 {
+		roleIDs, err := r.resolveMenuRoleIDs(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(roleIDs) == 0 {
+			return []model.SysMenu{}, nil
+		}
 		baseQuery = baseQuery.
 			Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.menu_id", roleMenuTable, menuTable, roleMenuTable)).
-			Joins(fmt.Sprintf("JOIN %s ON %s.role_id = %s.role_id", userRoleTable, roleMenuTable, userRoleTable)).
-			Where(fmt.Sprintf("%s.user_id = ?", userRoleTable), userID)
+			Where(fmt.Sprintf("%s.role_id IN ?", roleMenuTable), roleIDs)
 	}
 
 	baseQuery = baseQuery.
@@ -148,3 +173,34 @@ func (r *Repository) GetMenus(ctx context.Context, userID uint) ([]model.SysMenu
 
 	return menus, nil
 }
+
+// GetTenantByCode 按编码查询启用中的租户
+func (r *Repository) GetTenantByCode(ctx context.Context, code string) (*model.SysTenant, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	return r.findActiveTenant(ctx, "tenant_code = ?", strings.TrimSpace(code))
+}
+
+// GetActiveTenant 按 ID 查询启用中的租户
+func (r *Repository) GetActiveTenant(ctx context.Context, tenantID int64) (*model.SysTenant, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	return r.findActiveTenant(ctx, "id = ?", tenantID)
+}
+
+func (r *Repository) findActiveTenant(ctx context.Context, query string, arg interface{}) (*model.SysTenant, error) {
+	var record model.SysTenant
+	err := r.db.WithContext(ctx).Where(query, arg).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTenantUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.Status != "0" {
+		return nil, ErrTenantUnavailable
+	}
+	return &record, nil
+}
@@ -0,0 +1,301 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+// 角色来源类型
+const (
+	RoleSourceUser = "user" // 直接分配给用户
+	RoleSourceDept = "dept" // 由所在部门或上级部门继承
+	RoleSourcePost = "post" // 由担任的岗位继承
+)
+
+// RoleSource 描述用户获得某个角色的途径
+type RoleSource struct {
+	Type string `json:"type"`
+	// ID 与 Name 为继承来源的部门或岗位，直接分配时为空
+	ID   int64  `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// RoleGrant 用户拥有的角色及其全部来源
+type RoleGrant struct {
+	RoleID  int64        `json:"roleId"`
+	Sources []RoleSource `json:"sources"`
+}
+
+// ResolveRoleGrants 返回用户直接分配与通过部门、岗位继承的全部角色。
+// 部门绑定仅作用于本部门，开启包含下级后作用于整个子树；已删除的部门与停用或删除的岗位不再授予角色。
+func (r *Repository) ResolveRoleGrants(ctx context.Context, userID uint) ([]RoleGrant, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	ctx = tenant.WithoutScope(ctx)
+	db := r.db.WithContext(ctx)
+	grants := make([]RoleGrant, 0)
+	index := make(map[int64]int)
+	add := func(roleID int64, source RoleSource) {
+		if i, ok := index[roleID]; ok {
+			grants[i].Sources = append(grants[i].Sources, source)
+			return
+		}
+		index[roleID] = len(grants)
+		grants = append(grants, RoleGrant{RoleID: roleID, Sources: []RoleSource{source}})
+	}
+
+	var direct []int64
+	if err := db.Model(&model.SysUserRole{}).
+		Where("user_id = ?", userID).
+		Order("role_id ASC").
+		Pluck("role_id", &direct).Error; err != nil {
+		return nil, err
+	}
+	for _, roleID := range direct {
+		add(roleID, RoleSource{Type: RoleSourceUser})
+	}
+
+	var users []model.SysUser
+	if err := db.Select("id", "dept_id").Where("id = ?", userID).Limit(1).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return grants, nil
+	}
+
+	if deptID := users[0].DeptID; deptID != nil && *deptID > 0 {
+		if err := r.resolveDeptGrants(ctx, *deptID, add); err != nil {
+			return nil, err
+		}
+	}
+
+	postTable := model.SysPost{}.TableName()
+	postRoleTable := model.SysPostRole{}.TableName()
+	userPostTable := model.SysUserPost{}.TableName()
+
+	var postRows []struct {
+		RoleID   int64
+		PostID   int64
+		PostName string
+	}
+	if err := db.Table(postRoleTable).
+		Select(fmt.Sprintf("%s.role_id, %s.post_id, %s.post_name", postRoleTable, postRoleTable, postTable)).
+		Joins(fmt.Sprintf("JOIN %s ON %s.post_id = %s.post_id", userPostTable, userPostTable, postRoleTable)).
+		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.post_id AND %s.deleted_at IS NULL AND %s.status = ?",
+			postTable, postTable, postRoleTable, postTable, postTable), "0").
+		Where(fmt.Sprintf("%s.user_id = ?", userPostTable), userID).
+		Order(fmt.Sprintf("%s.post_id ASC, %s.role_id ASC", postRoleTable, postRoleTable)).
+		Scan(&postRows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range postRows {
+		add(row.RoleID, RoleSource{Type: RoleSourcePost, ID: row.PostID, Name: row.PostName})
+	}
+
+	return grants, nil
+}
+
+// resolveDeptGrants 收集用户所在部门及其上级部门绑定的角色
+func (r *Repository) resolveDeptGrants(ctx context.Context, deptID int64, add func(int64, RoleSource)) error {
+	db := r.db.WithContext(ctx)
+
+	var depts []model.SysDept
+	if err := db.Select("id", "ancestors").Where("id = ?", deptID).Limit(1).Find(&depts).Error; err != nil {
+		return err
+	}
+	if len(depts) == 0 {
+		return nil
+	}
+
+	candidates := append(parseAncestors(depts[0].Ancestors), deptID)
+
+	deptTable := model.SysDept{}.TableName()
+	deptRoleTable := model.SysDeptRole{}.TableName()
+
+	var rows []struct {
+		RoleID          int64
+		DeptID          int64
+		IncludeChildren bool
+		DeptName        string
+	}
+	if err := db.Table(deptRoleTable).
+		Select(fmt.Sprintf("%s.role_id, %s.dept_id, %s.include_children, %s.dept_name",
+			deptRoleTable, deptRoleTable, deptRoleTable, deptTable)).
+		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.dept_id AND %s.deleted_at IS NULL",
+			deptTable, deptTable, deptRoleTable, deptTable)).
+		Where(fmt.Sprintf("%s.dept_id IN ?", deptRoleTable), candidates).
+		Order(fmt.Sprintf("%s.dept_id ASC, %s.role_id ASC", deptRoleTable, deptRoleTable)).
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		// 上级部门的绑定只有开启包含下级时才向下继承
+		if row.DeptID != deptID && !row.IncludeChildren {
+			continue
+		}
+		add(row.RoleID, RoleSource{Type: RoleSourceDept, ID: row.DeptID, Name: row.DeptName})
+	}
+	return nil
+}
+
+// ListRoleMemberIDs 返回直接或通过部门、岗位继承拥有任一角色的用户 ID，继承规则与 ResolveRoleGrants 一致。
+// 结果未过滤用户状态，由调用方按需筛选
+func (r *Repository) ListRoleMemberIDs(ctx context.Context, roleIDs []int64) ([]int64, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	if len(roleIDs) == 0 {
+		return []int64{}, nil
+	}
+
+	ctx = tenant.WithoutScope(ctx)
+	db := r.db.WithContext(ctx)
+	seen := make(map[int64]struct{})
+	members := make([]int64, 0)
+	collect := func(ids []int64) {
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			members = append(members, id)
+		}
+	}
+
+	var direct []int64
+	if err := db.Model(&model.SysUserRole{}).
+		Where("role_id IN ?", roleIDs).
+		Pluck("user_id", &direct).Error; err != nil {
+		return nil, err
+	}
+	collect(direct)
+
+	deptTable := model.SysDept{}.TableName()
+	deptRoleTable := model.SysDeptRole{}.TableName()
+
+	var bindings []struct {
+		DeptID          int64
+		IncludeChildren bool
+		Ancestors       string
+	}
+	if err := db.Table(deptRoleTable).
+		Select(fmt.Sprintf("%s.dept_id, %s.include_children, %s.ancestors", deptRoleTable, deptRoleTable, deptTable)).
+		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.dept_id AND %s.deleted_at IS NULL",
+			deptTable, deptTable, deptRoleTable, deptTable)).
+		Where(fmt.Sprintf("%s.role_id IN ?", deptRoleTable), roleIDs).
+		Scan(&bindings).Error; err != nil {
+		return nil, err
+	}
+	if len(bindings) > 0 {
+		deptIDs := make([]int64, 0, len(bindings))
+		query := db.Model(&model.SysDept{})
+		clause := db.Where("1 = 0")
+		for _, binding := range bindings {
+			deptIDs = append(deptIDs, binding.DeptID)
+			if binding.IncludeChildren {
+				path := fmt.Sprintf("%s,%d", binding.Ancestors, binding.DeptID)
+				clause = clause.Or("ancestors = ? OR ancestors LIKE ?", path, path+",%")
+			}
+		}
+		var descendants []int64
+		if err := query.Where(clause).Pluck("id", &descendants).Error; err != nil {
+			return nil, err
+		}
+		deptIDs = append(deptIDs, descendants...)
+
+		var deptMembers []int64
+		if err := db.Model(&model.SysUser{}).
+			Where("dept_id IN ?", deptIDs).
+			Pluck("id", &deptMembers).Error; err != nil {
+			return nil, err
+		}
+		collect(deptMembers)
+	}
+
+	postTable := model.SysPost{}.TableName()
+	postRoleTable := model.SysPostRole{}.TableName()
+	userPostTable := model.SysUserPost{}.TableName()
+
+	var postMembers []int64
+	if err := db.Table(userPostTable).
+		Joins(fmt.Sprintf("JOIN %s ON %s.post_id = %s.post_id", postRoleTable, postRoleTable, userPostTable)).
+		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.post_id AND %s.deleted_at IS NULL AND %s.status = ?",
+			postTable, postTable, userPostTable, postTable, postTable), "0").
+		Where(fmt.Sprintf("%s.role_id IN ?", postRoleTable), roleIDs).
+		Pluck(fmt.Sprintf("%s.user_id", userPostTable), &postMembers).Error; err != nil {
+		return nil, err
+	}
+	collect(postMembers)
+
+	return members, nil
+}
+
+// resolveRoleIDs 返回用户直接分配与继承的角色 ID
+func (r *Repository) resolveRoleIDs(ctx context.Context, userID uint) ([]int64, error) {
+	grants, err := r.ResolveRoleGrants(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(grants))
+	for _, grant := range grants {
+		ids = append(ids, grant.RoleID)
+	}
+	return ids, nil
+}
+
+// resolveMenuRoleIDs 在用户角色基础上补充全部上级角色，用于解析菜单与权限
+func (r *Repository) resolveMenuRoleIDs(ctx context.Context, userID uint) ([]int64, error) {
+	roleIDs, err := r.resolveRoleIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]struct{}, len(roleIDs))
+	for _, id := range roleIDs {
+		seen[id] = struct{}{}
+	}
+
+	frontier := roleIDs
+	for len(frontier) > 0 {
+		var parents []int64
+		if err := r.db.WithContext(ctx).
+			Model(&model.SysRole{}).
+			Where("id IN ? AND parent_id > 0", frontier).
+			Pluck("parent_id", &parents).Error; err != nil {
+			return nil, err
+		}
+
+		frontier = frontier[:0:0]
+		for _, id := range parents {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			roleIDs = append(roleIDs, id)
+			frontier = append(frontier, id)
+		}
+	}
+	return roleIDs, nil
+}
+
+func parseAncestors(ancestors string) []int64 {
+	parts := strings.Split(ancestors, ",")
+	ids := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || id <= 0 {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -31,6 +31,7 @@ var (
 type Session struct {
 	SessionID        string    `json:"session_id"`
 	UserID           uint      `json:"user_id"`
+	TenantID         int64     `json:"tenant_id,omitempty"`
 	RefreshTokenHash string    `json:"refresh_token_hash"`
 	CreatedAt        time.Time `json:"created_at"`
 	LastSeen         time.Time `json:"last_seen"`
@@ -75,9 +76,9 @@ func NewSessionStore(cache *redis.Client, opts SessionStoreOptions) *SessionStor
 	}
 }
 
-// Create registers a brand-new session and returns the persisted record alongside
-// a plaintext refresh token the caller must return to the client.
-func (s *SessionStore) Create(ctx context.Context, userID uint) (*Session, string, error) {
+// Create registers a brand-new session bound to the tenant and returns the persisted
+// record alongside a plaintext refresh token the caller must return to the client.
+func (s *SessionStore) Create(ctx context.Context, userID uint, tenantID int64) (*Session, string, error) {
 	if s == nil || s.cache == nil {
 		return nil, "", errors.New("session store unavailable")
 	}
@@ -88,6 +89,7 @@ func (s *SessionStore) Create(ctx context.Context, userID uint) (*Session, strin
 	session := &Session{
 		SessionID:        sessionID,
 		UserID:           userID,
+		TenantID:         tenantID,
 		RefreshTokenHash: refreshHash,
 		CreatedAt:        now,
 		LastSeen:         now,
@@ -157,6 +159,15 @@ func (s *SessionStore) UpdateLastSeen(ctx context.Context, session *Session) err
 	return s.persistSession(ctx, session)
 }
 
+// SwitchTenant rebinds the session to another tenant so refreshed tokens keep it.
+func (s *SessionStore) SwitchTenant(ctx context.Context, session *Session, tenantID int64) error {
+	if s == nil || session == nil {
+		return errors.New("session store unavailable")
+	}
+	session.TenantID = tenantID
+	return s.persistSession(ctx, session)
+}
+
 // Revoke marks the session as revoked and removes it from helper indexes.
 func (s *SessionStore) Revoke(ctx context.Context, session *Session) error {
 	if s == nil || session == nil {
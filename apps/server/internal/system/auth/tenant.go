@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/netutil"
+	"github.com/starter-kit-fe/admin/pkg/resp"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+// SwitchTenant godoc
+// @Summary 切换租户
+// @Description 平台管理员切换当前会话访问的租户，返回新的访问令牌
+// @Tags Tenant
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "租户ID"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 401 {object} resp.Response
+// @Failure 403 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Router /v1/system/tenants/{id}/switch [post]
+func (h *Handler) SwitchTenant(ctx *gin.Context) {
+	if h == nil || h.repo == nil || h.sessions == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("authentication service unavailable"))
+		return
+	}
+
+	tenantID, err := strconv.ParseInt(strings.TrimSpace(ctx.Param("id")), 10, 64)
+	if err != nil || tenantID <= 0 {
+		resp.BadRequest(ctx, resp.WithMessage("invalid tenant id"))
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		resp.Unauthorized(ctx, resp.WithMessage("invalid token"))
+		return
+	}
+	sessionID, ok := middleware.GetSessionID(ctx)
+	if !ok || sessionID == "" {
+		resp.Unauthorized(ctx, resp.WithMessage("invalid token"))
+		return
+	}
+
+	user, err := h.repo.GetUserByID(ctx.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.Unauthorized(ctx, resp.WithMessage("user not found"))
+		} else {
+			resp.InternalServerError(ctx, resp.WithMessage("failed to load user profile"))
+		}
+		return
+	}
+	// 只有平台租户的用户可以进入其他租户
+	if user.TenantID != tenant.DefaultID {
+		resp.Forbidden(ctx, resp.WithMessage("only platform administrators can switch tenants"))
+		return
+	}
+
+	if _, err := h.repo.GetActiveTenant(ctx.Request.Context(), tenantID); err != nil {
+		if errors.Is(err, ErrTenantUnavailable) {
+			resp.NotFound(ctx, resp.WithMessage("tenant unavailable"))
+		} else {
+			resp.InternalServerError(ctx, resp.WithMessage("failed to load tenant"))
+		}
+		return
+	}
+
+	session, err := h.sessions.Get(ctx.Request.Context(), sessionID)
+	if err != nil {
+		resp.Unauthorized(ctx, resp.WithMessage("session invalid"))
+		return
+	}
+	if err := h.sessions.SwitchTenant(ctx.Request.Context(), session, tenantID); err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to switch tenant"))
+		return
+	}
+
+	accessToken, expiresAt, err := h.issueAccessToken(userID, session.SessionID, tenantID)
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to issue token"))
+		return
+	}
+	h.recordOnlineSession(ctx, accessToken, user, session.SessionID)
+
+	if netutil.IsBrowserRequest(ctx.Request) {
+		h.setCookie(ctx, h.cookieName, accessToken, h.cookieMaxAge(h.tokenDuration))
+		resp.Success(ctx, gin.H{
+			"tenant_id":  tenantID,
+			"expires_at": expiresAt.Unix(),
+		})
+		return
+	}
+	resp.Success(ctx, gin.H{
+		"tenant_id":    tenantID,
+		"access_token": accessToken,
+		"expires_at":   expiresAt.Unix(),
+	})
+}
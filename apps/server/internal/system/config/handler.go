@@ -7,8 +7,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"github.com/starter-kit-fe/admin/internal/audit"
+	"github.com/starter-kit-fe/admin/internal/system/history"
 	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 	"github.com/starter-kit-fe/admin/pkg/resp"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
 )
 
 type listConfigQuery struct {
@@ -18,19 +22,33 @@ type listConfigQuery struct {
 }
 
 type createConfigRequest struct {
-	ConfigName  string  `json:"configName" binding:"required"`
-	ConfigKey   string  `json:"configKey" binding:"required"`
-	ConfigValue string  `json:"configValue" binding:"required"`
-	ConfigType  string  `json:"configType"`
-	Remark      *string `json:"remark"`
+	ConfigName  string `json:"configName" binding:"required"`
+	ConfigKey   string `json:"configKey" binding:"required"`
+	ConfigValue string `json:"configValue"`
+	ConfigType  string `json:"configType"`
+	// ValueType 可选 string、int、bool、duration、enum、json、url、email、secret，默认 string
+	ValueType string `json:"valueType"`
+	// ValueSchema enum 类型为可选值 JSON 数组，json 类型为 JSON Schema
+	ValueSchema  *string `json:"valueSchema"`
+	DefaultValue *string `json:"defaultValue"`
+	IsPublic     bool    `json:"isPublic"`
+	Remark       *string `json:"remark"`
 }
 
 type updateConfigRequest struct {
-	ConfigName  *string `json:"configName"`
-	ConfigKey   *string `json:"configKey"`
-	ConfigValue *string `json:"configValue"`
-	ConfigType  *string `json:"configType"`
-	Remark      *string `json:"remark"`
+	ConfigName   *string `json:"configName"`
+	ConfigKey    *string `json:"configKey"`
+	ConfigValue  *string `json:"configValue"`
+	ConfigType   *string `json:"configType"`
+	ValueType    *string `json:"valueType"`
+	ValueSchema  *string `json:"valueSchema"`
+	DefaultValue *string `json:"defaultValue"`
+	IsPublic     *bool   `json:"isPublic"`
+	Remark       *string `json:"remark"`
+}
+
+type publicConfigQuery struct {
+	Tenant string `form:"tenant"`
 }
 
 type Handler struct {
@@ -91,6 +109,7 @@ func (h *Handler) List(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "配置ID"
 // @Success 200 {object} resp.Response
+// @Header 200 {string} ETag "参数版本，修改或删除时通过 If-Match 回传"
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 500 {object} resp.Response
@@ -118,6 +137,7 @@ func (h *Handler) Get(ctx *gin.Context) {
 		return
 	}
 
+	etag.Set(ctx, *item.UpdatedAt)
 	resp.OK(ctx, resp.WithData(item))
 }
 
@@ -146,25 +166,31 @@ func (h *Handler) Create(ctx *gin.Context) {
 		resp.BadRequest(ctx, resp.WithMessage("invalid config payload"))
 		return
 	}
+	if isSecretType(payload.ValueType) {
+		audit.Redact(ctx, "configValue", "defaultValue")
+	}
 
 	operator := resolveOperator(ctx)
 	item, err := h.service.CreateConfig(ctx.Request.Context(), CreateConfigInput{
-		ConfigName:  payload.ConfigName,
-		ConfigKey:   payload.ConfigKey,
-		ConfigValue: payload.ConfigValue,
-		ConfigType:  payload.ConfigType,
-		Remark:      payload.Remark,
-		Operator:    operator,
+		ConfigName:   payload.ConfigName,
+		ConfigKey:    payload.ConfigKey,
+		ConfigValue:  payload.ConfigValue,
+		ConfigType:   payload.ConfigType,
+		ValueType:    payload.ValueType,
+		ValueSchema:  payload.ValueSchema,
+		DefaultValue: payload.DefaultValue,
+		IsPublic:     payload.IsPublic,
+		Remark:       payload.Remark,
+		Operator:     operator,
 	})
 	if err != nil {
 		switch {
-		case errors.Is(err, ErrConfigNameRequired),
-			errors.Is(err, ErrConfigKeyRequired),
-			errors.Is(err, ErrConfigValueRequired),
-			errors.Is(err, ErrInvalidConfigType):
+		case isValidationError(err):
 			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
 		case errors.Is(err, ErrDuplicateConfigKey):
 			resp.Conflict(ctx, resp.WithMessage(err.Error()))
+		case errors.Is(err, ErrSecretsUnavailable):
+			resp.ServiceUnavailable(ctx, resp.WithMessage(err.Error()))
 		default:
 			resp.InternalServerError(ctx, resp.WithMessage("failed to create config"))
 		}
@@ -183,10 +209,13 @@ func (h *Handler) Create(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "配置ID"
 // @Param request body updateConfigRequest true "配置参数"
+// @Param If-Match header string true "获取参数时返回的 ETag"
 // @Success 200 {object} resp.Response
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 409 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/configs/{id} [put]
@@ -207,28 +236,39 @@ func (h *Handler) Update(ctx *gin.Context) {
 		resp.BadRequest(ctx, resp.WithMessage("invalid config payload"))
 		return
 	}
+	h.redactSecretValue(ctx, id, payload.ValueType)
+
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
 
 	operator := resolveOperator(ctx)
 	item, err := h.service.UpdateConfig(ctx.Request.Context(), UpdateConfigInput{
-		ID:          id,
-		ConfigName:  payload.ConfigName,
-		ConfigKey:   payload.ConfigKey,
-		ConfigValue: payload.ConfigValue,
-		ConfigType:  payload.ConfigType,
-		Remark:      payload.Remark,
-		Operator:    operator,
+		ID:           id,
+		ConfigName:   payload.ConfigName,
+		ConfigKey:    payload.ConfigKey,
+		ConfigValue:  payload.ConfigValue,
+		ConfigType:   payload.ConfigType,
+		ValueType:    payload.ValueType,
+		ValueSchema:  payload.ValueSchema,
+		DefaultValue: payload.DefaultValue,
+		IsPublic:     payload.IsPublic,
+		Remark:       payload.Remark,
+		Operator:     operator,
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("config not found"))
-		case errors.Is(err, ErrConfigNameRequired),
-			errors.Is(err, ErrConfigKeyRequired),
-			errors.Is(err, ErrConfigValueRequired),
-			errors.Is(err, ErrInvalidConfigType):
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("config has been modified, reload and retry"))
+		case isValidationError(err):
 			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
 		case errors.Is(err, ErrDuplicateConfigKey):
 			resp.Conflict(ctx, resp.WithMessage(err.Error()))
+		case errors.Is(err, ErrSecretsUnavailable):
+			resp.ServiceUnavailable(ctx, resp.WithMessage(err.Error()))
 		default:
 			resp.InternalServerError(ctx, resp.WithMessage("failed to update config"))
 		}
@@ -245,9 +285,12 @@ func (h *Handler) Update(ctx *gin.Context) {
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "配置ID"
+// @Param If-Match header string true "获取参数时返回的 ETag"
 // @Success 204 {object} nil
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/configs/{id} [delete]
@@ -263,11 +306,20 @@ func (h *Handler) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteConfig(ctx.Request.Context(), id); err != nil {
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	if err := h.service.DeleteConfig(ctx.Request.Context(), id, resolveOperator(ctx)); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			resp.NotFound(ctx, resp.WithMessage("config not found"))
 			return
 		}
+		if errors.Is(err, etag.ErrPreconditionFailed) {
+			resp.PreconditionFailed(ctx, resp.WithMessage("config has been modified, reload and retry"))
+			return
+		}
 		resp.InternalServerError(ctx, resp.WithMessage("failed to delete config"))
 		return
 	}
@@ -275,6 +327,147 @@ func (h *Handler) Delete(ctx *gin.Context) {
 	resp.NoContent(ctx)
 }
 
+// Rollback godoc
+// @Summary 回滚参数配置
+// @Description 将参数恢复到指定变更记录之后的状态，回滚同样记入变更历史；secret 参数值无法从历史恢复
+// @Tags System/Config
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "配置ID"
+// @Param revisionId path int true "变更记录ID"
+// @Param If-Match header string true "获取参数时返回的 ETag"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 409 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/configs/{id}/history/{revisionId}/rollback [post]
+func (h *Handler) Rollback(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("config service unavailable"))
+		return
+	}
+
+	id, err := parseConfigID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid config id"))
+		return
+	}
+	revisionID, err := strconv.ParseInt(ctx.Param("revisionId"), 10, 64)
+	if err != nil || revisionID <= 0 {
+		resp.BadRequest(ctx, resp.WithMessage("invalid revision id"))
+		return
+	}
+
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	item, err := h.service.RollbackConfig(ctx.Request.Context(), RollbackConfigInput{
+		ID:         id,
+		RevisionID: revisionID,
+		Operator:   resolveOperator(ctx),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			resp.NotFound(ctx, resp.WithMessage("config not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("config has been modified, reload and retry"))
+		case errors.Is(err, history.ErrRevisionNotFound):
+			resp.NotFound(ctx, resp.WithMessage(err.Error()))
+		case errors.Is(err, ErrSecretRollback), errors.Is(err, ErrDuplicateConfigKey):
+			resp.Conflict(ctx, resp.WithMessage(err.Error()))
+		case isValidationError(err):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		case errors.Is(err, ErrHistoryUnavailable), errors.Is(err, ErrSecretsUnavailable):
+			resp.ServiceUnavailable(ctx, resp.WithMessage(err.Error()))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to roll back config"))
+		}
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(item))
+}
+
+// Public godoc
+// @Summary 获取公开参数
+// @Description 返回标记为公开的参数（如站点名称、Logo、验证码开关），无需登录，值按参数类型返回
+// @Tags System/Config
+// @Produce json
+// @Param tenant query string false "租户编码，默认为平台租户"
+// @Success 200 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/public/configs [get]
+func (h *Handler) Public(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("config service unavailable"))
+		return
+	}
+
+	var query publicConfigQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	tenantID, err := h.service.ResolveTenant(ctx.Request.Context(), query.Tenant)
+	if err != nil {
+		if errors.Is(err, ErrTenantUnavailable) {
+			resp.NotFound(ctx, resp.WithMessage("tenant not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load configs"))
+		return
+	}
+
+	items, err := h.service.PublicConfigs(tenant.WithID(ctx.Request.Context(), tenantID))
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load configs"))
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=60")
+	resp.OK(ctx, resp.WithData(items))
+}
+
+// redactSecretValue 新类型或原类型为 secret 时，参数值不写入操作日志
+func (h *Handler) redactSecretValue(ctx *gin.Context, id int64, valueType *string) {
+	if valueType != nil && isSecretType(*valueType) {
+		audit.Redact(ctx, "configValue", "defaultValue")
+		return
+	}
+	current, err := h.service.GetConfig(ctx.Request.Context(), id)
+	if err == nil && current.ValueType == ValueTypeSecret {
+		audit.Redact(ctx, "configValue", "defaultValue")
+	}
+}
+
+func isSecretType(valueType string) bool {
+	normalized, err := normalizeValueType(valueType)
+	return err == nil && normalized == ValueTypeSecret
+}
+
+func isValidationError(err error) bool {
+	return errors.Is(err, ErrConfigNameRequired) ||
+		errors.Is(err, ErrConfigKeyRequired) ||
+		errors.Is(err, ErrConfigValueRequired) ||
+		errors.Is(err, ErrInvalidConfigType) ||
+		errors.Is(err, ErrInvalidValueType) ||
+		errors.Is(err, ErrInvalidValueSchema) ||
+		errors.Is(err, ErrInvalidConfigValue) ||
+		errors.Is(err, ErrInvalidDefaultValue) ||
+		errors.Is(err, ErrSecretNotPublic) ||
+		errors.Is(err, ErrSecretValueRequired)
+}
+
 func parseConfigID(value string) (int64, error) {
 	return strconv.ParseInt(value, 10, 64)
 }
@@ -8,6 +8,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 )
 
 var (
@@ -66,6 +67,54 @@ func (r *Repository) GetConfig(ctx context.Context, id int64) (*model.SysConfig,
 	return &record, nil
 }
 
+// ListPublicConfigs 查询可匿名读取的参数
+func (r *Repository) ListPublicConfigs(ctx context.Context) ([]model.SysConfig, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	var records []model.SysConfig
+	if err := r.db.WithContext(ctx).Where("is_public = ?", true).Order("id ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// ListSecretConfigs 查询 secret 类型参数，调用方决定租户范围
+func (r *Repository) ListSecretConfigs(ctx context.Context) ([]model.SysConfig, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	var records []model.SysConfig
+	if err := r.db.WithContext(ctx).Where("value_type = ?", "secret").Order("id ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// UpdateStoredValue 仅替换存储的参数值，不更新修改时间与修改人，用于密钥轮换
+func (r *Repository) UpdateStoredValue(ctx context.Context, id int64, value string) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	return r.db.WithContext(ctx).Model(&model.SysConfig{}).Where("id = ?", id).UpdateColumn("config_value", value).Error
+}
+
+// FindActiveTenantID 按编码查询启用中的租户
+func (r *Repository) FindActiveTenantID(ctx context.Context, code string) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, ErrRepositoryUnavailable
+	}
+	var record model.SysTenant
+	err := r.db.WithContext(ctx).Select("id").Where("tenant_code = ? AND status = ?", code, "0").First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrTenantUnavailable
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int64(record.ID), nil
+}
+
 func (r *Repository) ExistsByKey(ctx context.Context, key string, excludeID int64) (bool, error) {
 	if r == nil || r.db == nil {
 		return false, ErrRepositoryUnavailable
@@ -99,20 +148,38 @@ func (r *Repository) SaveConfig(ctx context.Context, record *model.SysConfig) er
 	if record == nil {
 		return errors.New("config record is required")
 	}
-	return r.db.WithContext(ctx).Save(record).Error
-}
-
-func (r *Repository) DeleteConfig(ctx context.Context, id int64) error {
-	if r == nil || r.db == nil {
-		return ErrRepositoryUnavailable
-	}
-
-	result := r.db.WithContext(ctx).Delete(&model.SysConfig{}, id)
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Model(record)).Select("*").Updates(record)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+		return etag.Missed(db, &model.SysConfig{}, record.ID)
 	}
 	return nil
 }
+
+func (r *Repository) DeleteConfig(ctx context.Context, id int64, operator string) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 记录删除人，供回收站展示；If-Match 在这一步校验
+		marked := etag.Guard(tx.Model(&model.SysConfig{}).Where("id = ?", id)).Update("update_by", operator)
+		if marked.Error != nil {
+			return marked.Error
+		}
+		if marked.RowsAffected == 0 {
+			return etag.Missed(tx, &model.SysConfig{}, id)
+		}
+		result := tx.Delete(&model.SysConfig{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
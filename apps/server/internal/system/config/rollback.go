@@ -0,0 +1,133 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/history"
+)
+
+var (
+	ErrHistoryUnavailable = errors.New("config history is not available")
+	ErrSecretRollback     = errors.New("secret values are not kept in history, set the value again instead of rolling back")
+)
+
+type RollbackConfigInput struct {
+	ID         int64
+	RevisionID int64
+	Operator   string
+}
+
+// RollbackConfig 将参数恢复到指定变更记录之后的状态。
+// 以当前状态为起点逐条撤销之后的变更，再按普通修改校验并保存，回滚本身也记入变更历史。
+func (s *Service) RollbackConfig(ctx context.Context, input RollbackConfigInput) (*Config, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	if s.history == nil {
+		return nil, ErrHistoryUnavailable
+	}
+
+	record, err := s.repo.GetConfig(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.history.ChangesAfter(ctx, history.ModuleConfig, input.ID, input.RevisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := configSnapshot(historyView(record))
+	if err != nil {
+		return nil, err
+	}
+	target, err := configSnapshot(historyView(record))
+	if err != nil {
+		return nil, err
+	}
+	history.Revert(target, changes)
+
+	update, changed, err := rollbackInput(current, target)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return configFromModel(record), nil
+	}
+	update.ID = input.ID
+	update.Operator = input.Operator
+	return s.updateConfig(ctx, record, update, input.RevisionID)
+}
+
+// rollbackInput 只提交与当前状态不同的字段，未出现在快照中的字段视为空值
+func rollbackInput(current, target map[string]interface{}) (UpdateConfigInput, bool, error) {
+	var input UpdateConfigInput
+	changed := false
+
+	text := func(field string) *string {
+		before, _ := current[field].(string)
+		after, _ := target[field].(string)
+		if before == after {
+			return nil
+		}
+		changed = true
+		return &after
+	}
+
+	input.ConfigName = text("configName")
+	input.ConfigKey = text("configKey")
+	input.ConfigType = text("configType")
+	input.ValueType = text("valueType")
+	input.ValueSchema = text("valueSchema")
+	input.DefaultValue = text("defaultValue")
+	input.Remark = text("remark")
+	if value := text("configValue"); value != nil {
+		// 历史中的 secret 参数值只有指纹，无法还原为明文
+		if strings.HasPrefix(*value, SecretMask) {
+			return input, false, ErrSecretRollback
+		}
+		input.ConfigValue = value
+	}
+
+	before, _ := current["isPublic"].(bool)
+	after, _ := target["isPublic"].(bool)
+	if before != after {
+		changed = true
+		input.IsPublic = &after
+	}
+	return input, changed, nil
+}
+
+func configSnapshot(view *Config) (map[string]interface{}, error) {
+	raw, err := json.Marshal(view)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// historyView 变更历史使用的参数视图：secret 参数值记为掩码加密文指纹，
+// 既不泄露明文，又能在历史中看出值是否被修改过
+func historyView(record *model.SysConfig) *Config {
+	view := configFromModel(record)
+	if view == nil || record.ValueType != ValueTypeSecret || record.ConfigValue == "" {
+		return view
+	}
+	// 密钥轮换只重新封装数据密钥，取密文部分计算指纹使轮换前后一致
+	ciphertext := record.ConfigValue
+	if idx := strings.LastIndex(ciphertext, ":"); idx >= 0 {
+		ciphertext = ciphertext[idx+1:]
+	}
+	sum := sha256.Sum256([]byte(ciphertext))
+	view.ConfigValue = SecretMask + "#" + hex.EncodeToString(sum[:4])
+	return view
+}
@@ -2,10 +2,20 @@ package config
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/history"
+	"github.com/starter-kit-fe/admin/internal/system/settings"
+	"github.com/starter-kit-fe/admin/pkg/envelope"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
 )
 
 var (
@@ -16,6 +26,19 @@ var (
 	ErrConfigValueRequired = errors.New("config value is required")
 	ErrInvalidConfigType   = errors.New("invalid config type")
 	ErrDuplicateConfigKey  = errors.New("duplicate config key")
+	ErrInvalidValueType    = errors.New("invalid config value type")
+	ErrInvalidValueSchema  = errors.New("invalid config value schema")
+	ErrInvalidConfigValue  = errors.New("invalid config value")
+	ErrInvalidDefaultValue = errors.New("invalid config default value")
+	ErrTenantUnavailable   = errors.New("tenant unavailable")
+	ErrSecretsUnavailable  = errors.New("secret configs require SECURITY_CONFIG_KEYS to be configured")
+	ErrSecretNotPublic     = errors.New("secret configs cannot be public")
+	ErrSecretValueRequired = errors.New("a new value is required when changing a secret config to another type")
+)
+
+const (
+	publicCacheKeyPrefix = "config:public:"
+	publicCacheTTL       = 10 * time.Minute
 )
 
 var validConfigTypes = map[string]struct{}{
@@ -24,42 +47,61 @@ var validConfigTypes = map[string]struct{}{
 }
 
 type Service struct {
-	repo *Repository
+	repo     *Repository
+	history  *history.Service
+	settings *settings.Service
+	cache    *redis.Client
+	keyring  *envelope.Keyring
 }
 
-func NewService(repo *Repository) *Service {
+// NewService 创建参数服务；settings 可选，存在时参数写入后同步失效运行时缓存；
+// cache 可选，用于缓存公开参数；keyring 可选，未配置时不能保存 secret 类型参数
+func NewService(repo *Repository, history *history.Service, settings *settings.Service, cache *redis.Client, keyring *envelope.Keyring) *Service {
 	if repo == nil {
 		return nil
 	}
-	return &Service{repo: repo}
+	return &Service{repo: repo, history: history, settings: settings, cache: cache, keyring: keyring}
 }
 
 type Config struct {
-	ConfigID    int64   `json:"configId"`
-	ConfigName  string  `json:"configName"`
-	ConfigKey   string  `json:"configKey"`
-	ConfigValue string  `json:"configValue"`
-	ConfigType  string  `json:"configType"`
-	Remark      *string `json:"remark,omitempty"`
+	ConfigID     int64      `json:"configId"`
+	ConfigName   string     `json:"configName"`
+	ConfigKey    string     `json:"configKey"`
+	ConfigValue  string     `json:"configValue"`
+	ConfigType   string     `json:"configType"`
+	ValueType    string     `json:"valueType"`
+	ValueSchema  *string    `json:"valueSchema,omitempty"`
+	DefaultValue *string    `json:"defaultValue,omitempty"`
+	IsPublic     bool       `json:"isPublic"`
+	Remark       *string    `json:"remark,omitempty"`
+	UpdatedAt    *time.Time `json:"updatedAt,omitempty"`
 }
 
 type CreateConfigInput struct {
-	ConfigName  string
-	ConfigKey   string
-	ConfigValue string
-	ConfigType  string
-	Remark      *string
-	Operator    string
+	ConfigName   string
+	ConfigKey    string
+	ConfigValue  string
+	ConfigType   string
+	ValueType    string
+	ValueSchema  *string
+	DefaultValue *string
+	IsPublic     bool
+	Remark       *string
+	Operator     string
 }
 
 type UpdateConfigInput struct {
-	ID          int64
-	ConfigName  *string
-	ConfigKey   *string
-	ConfigValue *string
-	ConfigType  *string
-	Remark      *string
-	Operator    string
+	ID           int64
+	ConfigName   *string
+	ConfigKey    *string
+	ConfigValue  *string
+	ConfigType   *string
+	ValueType    *string
+	ValueSchema  *string
+	DefaultValue *string
+	IsPublic     *bool
+	Remark       *string
+	Operator     string
 }
 
 func (s *Service) ListConfigs(ctx context.Context, opts ListOptions) ([]Config, error) {
@@ -106,16 +148,36 @@ func (s *Service) CreateConfig(ctx context.Context, input CreateConfigInput) (*C
 		return nil, ErrConfigKeyRequired
 	}
 
-	value := strings.TrimSpace(input.ConfigValue)
-	if value == "" {
-		return nil, ErrConfigValueRequired
-	}
-
 	cfgType := normalizeConfigType(input.ConfigType)
 	if _, ok := validConfigTypes[cfgType]; !ok {
 		return nil, ErrInvalidConfigType
 	}
 
+	valueType, err := normalizeValueType(input.ValueType)
+	if err != nil {
+		return nil, err
+	}
+	spec, schema, err := compileValueSpec(valueType, input.ValueSchema)
+	if err != nil {
+		return nil, err
+	}
+	defaultValue, err := normalizeDefaultValue(spec, input.DefaultValue)
+	if err != nil {
+		return nil, err
+	}
+	value, err := normalizeConfigValue(spec, input.ConfigValue, defaultValue)
+	if err != nil {
+		return nil, err
+	}
+	if valueType == ValueTypeSecret {
+		if err := checkSecretOptions(defaultValue, input.IsPublic); err != nil {
+			return nil, err
+		}
+		if value, err = s.seal(value); err != nil {
+			return nil, err
+		}
+	}
+
 	if exists, err := s.repo.ExistsByKey(ctx, key, 0); err != nil {
 		return nil, err
 	} else if exists {
@@ -125,20 +187,38 @@ func (s *Service) CreateConfig(ctx context.Context, input CreateConfigInput) (*C
 	operator := sanitizeOperator(input.Operator)
 
 	record := &model.SysConfig{
-		ConfigName:  name,
-		ConfigKey:   key,
-		ConfigValue: value,
-		ConfigType:  cfgType,
-		Remark:      normalizeRemark(input.Remark),
-		CreateBy:    operator,
-		UpdateBy:    operator,
+		ConfigName:   name,
+		ConfigKey:    key,
+		ConfigValue:  value,
+		ConfigType:   cfgType,
+		ValueType:    valueType,
+		ValueSchema:  schema,
+		DefaultValue: defaultValue,
+		IsPublic:     input.IsPublic,
+		Remark:       normalizeRemark(input.Remark),
+		CreateBy:     operator,
+		UpdateBy:     operator,
 	}
 
 	if err := s.repo.CreateConfig(ctx, record); err != nil {
 		return nil, err
 	}
 
-	return configFromModel(record), nil
+	// 新增前该键可能已作为不存在的参数被缓存
+	s.settings.Invalidate(ctx, record.ConfigKey)
+	if record.IsPublic {
+		s.invalidatePublic(ctx)
+	}
+
+	created := configFromModel(record)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleConfig,
+		EntityID: created.ConfigID,
+		Action:   history.ActionCreate,
+		Operator: operator,
+		After:    historyView(record),
+	})
+	return created, nil
 }
 
 func (s *Service) UpdateConfig(ctx context.Context, input UpdateConfigInput) (*Config, error) {
@@ -150,6 +230,14 @@ func (s *Service) UpdateConfig(ctx context.Context, input UpdateConfigInput) (*C
 	if err != nil {
 		return nil, err
 	}
+	return s.updateConfig(ctx, record, input, 0)
+}
+
+// updateConfig 合并修改并保存；rollbackTo 大于 0 时按回滚记录变更历史
+func (s *Service) updateConfig(ctx context.Context, record *model.SysConfig, input UpdateConfigInput, rollbackTo int64) (*Config, error) {
+	// 下方直接修改 record，需先留存修改前的视图
+	before := configFromModel(record)
+	beforeHistory := historyView(record)
 
 	if input.ConfigName != nil {
 		name := strings.TrimSpace(*input.ConfigName)
@@ -174,12 +262,17 @@ func (s *Service) UpdateConfig(ctx context.Context, input UpdateConfigInput) (*C
 		record.ConfigKey = key
 	}
 
-	if input.ConfigValue != nil {
-		value := strings.TrimSpace(*input.ConfigValue)
-		if value == "" {
-			return nil, ErrConfigValueRequired
+	if err := s.applyValueChanges(record, input); err != nil {
+		return nil, err
+	}
+
+	if input.IsPublic != nil {
+		record.IsPublic = *input.IsPublic
+	}
+	if record.ValueType == ValueTypeSecret {
+		if err := checkSecretOptions(record.DefaultValue, record.IsPublic); err != nil {
+			return nil, err
 		}
-		record.ConfigValue = value
 	}
 
 	if input.ConfigType != nil {
@@ -200,14 +293,357 @@ func (s *Service) UpdateConfig(ctx context.Context, input UpdateConfigInput) (*C
 		return nil, err
 	}
 
+	s.settings.Invalidate(ctx, before.ConfigKey, record.ConfigKey)
+	if before.IsPublic || record.IsPublic {
+		s.invalidatePublic(ctx)
+	}
+
+	action := history.ActionUpdate
+	if rollbackTo > 0 {
+		action = history.ActionRollback
+	}
+	s.history.Record(ctx, history.Entry{
+		Module:     history.ModuleConfig,
+		EntityID:   int64(record.ID),
+		Action:     action,
+		Operator:   record.UpdateBy,
+		Before:     beforeHistory,
+		After:      historyView(record),
+		RollbackTo: rollbackTo,
+	})
 	return configFromModel(record), nil
 }
 
-func (s *Service) DeleteConfig(ctx context.Context, id int64) error {
+func (s *Service) DeleteConfig(ctx context.Context, id int64, operator string) error {
 	if s == nil || s.repo == nil {
 		return ErrServiceUnavailable
 	}
-	return s.repo.DeleteConfig(ctx, id)
+
+	var before *Config
+	if s.history != nil || s.settings != nil || s.cache != nil {
+		if record, err := s.repo.GetConfig(ctx, id); err == nil {
+			before = historyView(record)
+		}
+	}
+
+	operator = strings.TrimSpace(operator)
+	if err := s.repo.DeleteConfig(ctx, id, operator); err != nil {
+		return err
+	}
+	if before != nil {
+		s.settings.Invalidate(ctx, before.ConfigKey)
+		if before.IsPublic {
+			s.invalidatePublic(ctx)
+		}
+	}
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleConfig,
+		EntityID: id,
+		Action:   history.ActionDelete,
+		Operator: operator,
+		Before:   before,
+	})
+	return nil
+}
+
+// PublicConfigs 返回当前租户标记为公开的参数，值按参数类型转换且已应用默认值
+func (s *Service) PublicConfigs(ctx context.Context) (map[string]interface{}, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	// 缓存读写失败时直接读库，缓存最迟在过期后与数据库一致
+	cacheKey := s.publicCacheKey(ctx)
+	if s.cache != nil {
+		if cached, err := s.cache.Get(ctx, cacheKey).Bytes(); err == nil {
+			var result map[string]interface{}
+			if json.Unmarshal(cached, &result) == nil {
+				return result, nil
+			}
+		}
+	}
+
+	records, err := s.repo.ListPublicConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]interface{}, len(records))
+	for i := range records {
+		record := &records[i]
+		if record.ValueType == ValueTypeSecret {
+			continue
+		}
+		result[record.ConfigKey] = typedValue(record.ValueType, effectiveValue(record))
+	}
+
+	if s.cache != nil {
+		if payload, err := json.Marshal(result); err == nil {
+			_ = s.cache.Set(ctx, cacheKey, payload, publicCacheTTL).Err()
+		}
+	}
+	return result, nil
+}
+
+// ResolveTenant 按租户编码查找启用中的租户，未提供编码时为默认租户
+func (s *Service) ResolveTenant(ctx context.Context, code string) (int64, error) {
+	if s == nil || s.repo == nil {
+		return 0, ErrServiceUnavailable
+	}
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return tenant.DefaultID, nil
+	}
+	return s.repo.FindActiveTenantID(ctx, code)
+}
+
+// InvalidateKeys 清除参数的运行时缓存与公开参数缓存，供清单同步等绕过本服务的写入调用
+func (s *Service) InvalidateKeys(ctx context.Context, keys ...string) {
+	if s == nil {
+		return
+	}
+	s.settings.Invalidate(ctx, keys...)
+	s.invalidatePublic(ctx)
+}
+
+// RecordExternalChange 在参数被直接写入后刷新缓存并记录变更历史；新增时 before 为空，删除时 after 为空
+func (s *Service) RecordExternalChange(ctx context.Context, before, after *model.SysConfig, operator string) {
+	if s == nil || (before == nil && after == nil) {
+		return
+	}
+	entry := history.Entry{Module: history.ModuleConfig, Action: history.ActionUpdate, Operator: operator}
+	var keys []string
+	if before != nil {
+		entry.EntityID = int64(before.ID)
+		entry.Before = historyView(before)
+		keys = append(keys, before.ConfigKey)
+	} else {
+		entry.Action = history.ActionCreate
+	}
+	if after != nil {
+		entry.EntityID = int64(after.ID)
+		entry.After = historyView(after)
+		keys = append(keys, after.ConfigKey)
+	} else {
+		entry.Action = history.ActionDelete
+	}
+	s.InvalidateKeys(ctx, keys...)
+	s.history.Record(ctx, entry)
+}
+
+func (s *Service) invalidatePublic(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	_ = s.cache.Del(ctx, s.publicCacheKey(ctx)).Err()
+}
+
+func (s *Service) publicCacheKey(ctx context.Context) string {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		tenantID = tenant.DefaultID
+	}
+	return publicCacheKeyPrefix + strconv.FormatInt(tenantID, 10)
+}
+
+// applyValueChanges 合并类型、约束、默认值与参数值的修改，并按合并后的类型重新校验
+func (s *Service) applyValueChanges(record *model.SysConfig, input UpdateConfigInput) error {
+	if input.ValueType == nil && input.ValueSchema == nil && input.DefaultValue == nil && input.ConfigValue == nil {
+		return nil
+	}
+
+	valueType := record.ValueType
+	if input.ValueType != nil {
+		normalized, err := normalizeValueType(*input.ValueType)
+		if err != nil {
+			return err
+		}
+		valueType = normalized
+	} else if valueType == "" {
+		valueType = ValueTypeString
+	}
+
+	schema := record.ValueSchema
+	if input.ValueSchema != nil {
+		schema = input.ValueSchema
+	} else if input.ValueType != nil && valueType != record.ValueType && valueType != ValueTypeEnum && valueType != ValueTypeJSON {
+		// 切换到不使用约束的类型时丢弃原约束
+		schema = nil
+	}
+	spec, schema, err := compileValueSpec(valueType, schema)
+	if err != nil {
+		return err
+	}
+
+	defaultValue := record.DefaultValue
+	if input.DefaultValue != nil {
+		defaultValue = input.DefaultValue
+	}
+	defaultValue, err = normalizeDefaultValue(spec, defaultValue)
+	if err != nil {
+		return err
+	}
+
+	wasSecret := record.ValueType == ValueTypeSecret
+	// secret 参数回传掩码表示保持原值
+	newValue := input.ConfigValue
+	if newValue != nil && wasSecret && strings.TrimSpace(*newValue) == SecretMask {
+		newValue = nil
+	}
+
+	var value string
+	switch {
+	case valueType == ValueTypeSecret && newValue == nil && wasSecret:
+		value = record.ConfigValue
+	case valueType == ValueTypeSecret:
+		plaintext := record.ConfigValue
+		if newValue != nil {
+			plaintext = *newValue
+		}
+		if value, err = normalizeConfigValue(spec, plaintext, nil); err != nil {
+			return err
+		}
+		if value, err = s.seal(value); err != nil {
+			return err
+		}
+	case wasSecret && newValue == nil:
+		// 不解密旧值，避免通过修改类型读出明文
+		return ErrSecretValueRequired
+	case newValue != nil:
+		if value, err = normalizeConfigValue(spec, *newValue, defaultValue); err != nil {
+			return err
+		}
+	default:
+		if value, err = spec.normalize(record.ConfigValue); err != nil {
+			// 未修改参数值时，现有值也必须符合新的类型
+			return fmt.Errorf("%w: %v", ErrInvalidConfigValue, err)
+		}
+	}
+
+	record.ValueType = valueType
+	record.ValueSchema = schema
+	record.DefaultValue = defaultValue
+	record.ConfigValue = value
+	return nil
+}
+
+// RotateSecrets 用当前加密密钥重新封装所有租户 secret 参数的数据密钥，返回更新的参数数量
+func (s *Service) RotateSecrets(ctx context.Context) (int, error) {
+	if s == nil || s.repo == nil {
+		return 0, ErrServiceUnavailable
+	}
+	if s.keyring == nil {
+		return 0, ErrSecretsUnavailable
+	}
+
+	ctx = tenant.WithoutScope(ctx)
+	records, err := s.repo.ListSecretConfigs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	for i := range records {
+		record := &records[i]
+		if record.ConfigValue == "" {
+			continue
+		}
+		value, changed, err := s.keyring.Rewrap(record.ConfigValue)
+		if err != nil {
+			return rotated, fmt.Errorf("config %q (tenant %d): %w", record.ConfigKey, record.TenantID, err)
+		}
+		if !changed {
+			continue
+		}
+		if err := s.repo.UpdateStoredValue(ctx, int64(record.ID), value); err != nil {
+			return rotated, err
+		}
+		rotated++
+	}
+	return rotated, nil
+}
+
+func (s *Service) seal(value string) (string, error) {
+	if s.keyring == nil {
+		return "", ErrSecretsUnavailable
+	}
+	return s.keyring.Seal([]byte(value))
+}
+
+// checkSecretOptions 默认值会以明文保存，公开参数无需登录即可读取，二者都不适用于 secret 参数
+func checkSecretOptions(defaultValue *string, public bool) error {
+	if defaultValue != nil {
+		return fmt.Errorf("%w: secret configs cannot have a default value", ErrInvalidDefaultValue)
+	}
+	if public {
+		return ErrSecretNotPublic
+	}
+	return nil
+}
+
+func normalizeDefaultValue(spec valueSpec, defaultValue *string) (*string, error) {
+	if defaultValue == nil {
+		return nil, nil
+	}
+	normalized, err := spec.normalize(*defaultValue)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDefaultValue, err)
+	}
+	if normalized == "" {
+		return nil, nil
+	}
+	return &normalized, nil
+}
+
+// normalizeConfigValue 校验参数值；存在默认值时允许留空
+func normalizeConfigValue(spec valueSpec, value string, defaultValue *string) (string, error) {
+	normalized, err := spec.normalize(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidConfigValue, err)
+	}
+	if normalized == "" && defaultValue == nil {
+		return "", ErrConfigValueRequired
+	}
+	return normalized, nil
+}
+
+// ValueInput 参数的值类型、约束、默认值、参数值与公开标记，含义与新增参数接口一致
+type ValueInput struct {
+	ValueType    string
+	ValueSchema  *string
+	DefaultValue *string
+	ConfigValue  string
+	IsPublic     bool
+}
+
+// ValidateValue 按新增参数接口的规则校验参数值及其类型约束，供清单同步等不经过接口的写入在落库前调用。
+// 内置参数允许留空，因此不要求参数值；secret 参数的值不会经由这些写入保存，只校验其默认值与公开标记
+func ValidateValue(input ValueInput) error {
+	valueType, err := normalizeValueType(input.ValueType)
+	if err != nil {
+		return err
+	}
+	spec, _, err := compileValueSpec(valueType, input.ValueSchema)
+	if err != nil {
+		return err
+	}
+	defaultValue, err := normalizeDefaultValue(spec, input.DefaultValue)
+	if err != nil {
+		return err
+	}
+	if valueType == ValueTypeSecret {
+		return checkSecretOptions(defaultValue, input.IsPublic)
+	}
+	if _, err := spec.normalize(input.ConfigValue); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfigValue, err)
+	}
+	return nil
+}
+
+func effectiveValue(record *model.SysConfig) string {
+	if record.ConfigValue == "" && record.DefaultValue != nil {
+		return *record.DefaultValue
+	}
+	return record.ConfigValue
 }
 
 func normalizeConfigType(cfgType string) string {
@@ -237,12 +673,25 @@ func configFromModel(record *model.SysConfig) *Config {
 	if record == nil {
 		return nil
 	}
+	valueType := record.ValueType
+	if valueType == "" {
+		valueType = ValueTypeString
+	}
+	value := record.ConfigValue
+	if valueType == ValueTypeSecret && value != "" {
+		value = SecretMask
+	}
 	return &Config{
-		ConfigID:    int64(record.ID),
-		ConfigName:  record.ConfigName,
-		ConfigKey:   record.ConfigKey,
-		ConfigValue: record.ConfigValue,
-		ConfigType:  record.ConfigType,
-		Remark:      record.Remark,
+		ConfigID:     int64(record.ID),
+		ConfigName:   record.ConfigName,
+		ConfigKey:    record.ConfigKey,
+		ConfigValue:  value,
+		ConfigType:   record.ConfigType,
+		ValueType:    valueType,
+		ValueSchema:  record.ValueSchema,
+		DefaultValue: record.DefaultValue,
+		IsPublic:     record.IsPublic,
+		Remark:       record.Remark,
+		UpdatedAt:    &record.UpdatedAt,
 	}
 }
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/starter-kit-fe/admin/internal/system/settings"
+	"github.com/starter-kit-fe/admin/pkg/jsonschema"
+)
+
+const (
+	ValueTypeString   = "string"
+	ValueTypeInt      = "int"
+	ValueTypeBool     = "bool"
+	ValueTypeDuration = "duration"
+	ValueTypeEnum     = "enum"
+	ValueTypeJSON     = "json"
+	ValueTypeURL      = "url"
+	ValueTypeEmail    = "email"
+	// ValueTypeSecret 参数值加密存储，接口只返回掩码，仅能通过运行时参数服务解密读取
+	ValueTypeSecret = "secret"
+)
+
+// SecretMask secret 参数在接口响应中的占位值，修改时回传该值表示保持原值
+const SecretMask = "******"
+
+var validValueTypes = map[string]struct{}{
+	ValueTypeString:   {},
+	ValueTypeInt:      {},
+	ValueTypeBool:     {},
+	ValueTypeDuration: {},
+	ValueTypeEnum:     {},
+	ValueTypeJSON:     {},
+	ValueTypeURL:      {},
+	ValueTypeEmail:    {},
+	ValueTypeSecret:   {},
+}
+
+// valueSpec 描述参数值的类型约束，校验参数值与默认值时共用
+type valueSpec struct {
+	valueType string
+	options   []string
+	schema    *jsonschema.Schema
+}
+
+func normalizeValueType(valueType string) (string, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(valueType))
+	if trimmed == "" {
+		return ValueTypeString, nil
+	}
+	if _, ok := validValueTypes[trimmed]; !ok {
+		return "", ErrInvalidValueType
+	}
+	return trimmed, nil
+}
+
+// compileValueSpec 校验类型约束本身：enum 需要非空的可选值数组，json 的约束可省略，其余类型不接受约束
+func compileValueSpec(valueType string, schema *string) (valueSpec, *string, error) {
+	spec := valueSpec{valueType: valueType}
+	raw := ""
+	if schema != nil {
+		raw = strings.TrimSpace(*schema)
+	}
+
+	switch valueType {
+	case ValueTypeEnum:
+		var options []string
+		if raw == "" || json.Unmarshal([]byte(raw), &options) != nil || len(options) == 0 {
+			return spec, nil, fmt.Errorf("%w: enum configs require a JSON array of allowed values", ErrInvalidValueSchema)
+		}
+		for _, option := range options {
+			if strings.TrimSpace(option) == "" {
+				return spec, nil, fmt.Errorf("%w: enum values must not be empty", ErrInvalidValueSchema)
+			}
+		}
+		spec.options = options
+	case ValueTypeJSON:
+		if raw == "" {
+			return spec, nil, nil
+		}
+		compiled, err := jsonschema.Compile([]byte(raw))
+		if err != nil {
+			return spec, nil, fmt.Errorf("%w: %v", ErrInvalidValueSchema, err)
+		}
+		spec.schema = compiled
+	default:
+		if raw != "" {
+			return spec, nil, fmt.Errorf("%w: only enum and json configs accept a schema", ErrInvalidValueSchema)
+		}
+		return spec, nil, nil
+	}
+	return spec, &raw, nil
+}
+
+// normalize 校验并规范化参数值，返回的错误只含原因，由调用方区分参数值与默认值；空值由调用方按是否存在默认值处理
+func (spec valueSpec) normalize(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	switch spec.valueType {
+	case ValueTypeInt:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not an integer", value)
+		}
+		return strconv.FormatInt(parsed, 10), nil
+	case ValueTypeBool:
+		parsed, ok := parseBoolValue(value)
+		if !ok {
+			return "", fmt.Errorf("%q is not a boolean", value)
+		}
+		return strconv.FormatBool(parsed), nil
+	case ValueTypeDuration:
+		parsed, err := settings.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return "", fmt.Errorf("%q is not a duration such as 30s or 5m", value)
+		}
+		return value, nil
+	case ValueTypeEnum:
+		for _, option := range spec.options {
+			if option == value {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("%q is not one of %s", value, strings.Join(spec.options, ", "))
+	case ValueTypeJSON:
+		if !json.Valid([]byte(value)) {
+			return "", errors.New("malformed JSON")
+		}
+		if spec.schema != nil {
+			if err := spec.schema.Validate([]byte(value)); err != nil {
+				return "", err
+			}
+		}
+		return value, nil
+	case ValueTypeURL:
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			// 站点 Logo 等也允许填写站内相对路径
+			if err == nil && parsed.Scheme == "" && parsed.Host == "" && strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "//") {
+				return value, nil
+			}
+			return "", fmt.Errorf("%q is not an http(s) URL or absolute path", value)
+		}
+		return value, nil
+	case ValueTypeEmail:
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value {
+			return "", fmt.Errorf("%q is not an email address", value)
+		}
+		return value, nil
+	}
+	return value, nil
+}
+
+// typedValue 按参数类型转换为 JSON 值，供公开接口直接返回布尔、数字或对象；无法转换时返回 nil
+func typedValue(valueType, value string) interface{} {
+	switch valueType {
+	case ValueTypeInt:
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+		return nil
+	case ValueTypeBool:
+		if parsed, ok := parseBoolValue(value); ok {
+			return parsed
+		}
+		return nil
+	case ValueTypeJSON:
+		if json.Valid([]byte(value)) {
+			return json.RawMessage(value)
+		}
+		return nil
+	}
+	return value
+}
+
+func parseBoolValue(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "1", "yes", "y", "on":
+		return true, true
+	case "false", "0", "no", "n", "off":
+		return false, true
+	}
+	return false, false
+}
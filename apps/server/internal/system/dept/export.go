@@ -0,0 +1,85 @@
+package dept
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+const exportBatchSize = 500
+
+var exportHeader = []string{"部门编号", "部门名称", "上级部门", "负责人", "联系电话", "邮箱", "显示顺序", "状态", "创建时间", "备注"}
+
+var exportStatusLabels = map[string]string{"0": "正常", "1": "停用"}
+
+// ExportDepartments 按列表筛选条件分批导出部门，先输出表头，再逐行回调
+func (s *Service) ExportDepartments(ctx context.Context, opts QueryOptions, fn func([]string) error) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+
+	headerWritten := false
+	err := s.repo.ExportDepartments(ctx, ListOptions{
+		Status:   opts.Status,
+		DeptName: opts.DeptName,
+	}, exportBatchSize, func(records []model.SysDept) error {
+		parentIDs := make([]int64, 0, len(records))
+		for _, record := range records {
+			if record.ParentID > 0 {
+				parentIDs = append(parentIDs, record.ParentID)
+			}
+		}
+		parentNames, err := s.repo.GetDepartmentNames(ctx, parentIDs)
+		if err != nil {
+			return err
+		}
+
+		if !headerWritten {
+			headerWritten = true
+			if err := fn(exportHeader); err != nil {
+				return err
+			}
+		}
+		for i := range records {
+			if err := fn(exportDeptRow(&records[i], parentNames)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !headerWritten {
+		return fn(exportHeader)
+	}
+	return nil
+}
+
+func exportDeptRow(record *model.SysDept, parentNames map[int64]string) []string {
+	status := record.Status
+	if label, ok := exportStatusLabels[status]; ok {
+		status = label
+	}
+	return []string{
+		strconv.FormatUint(uint64(record.ID), 10),
+		record.DeptName,
+		parentNames[record.ParentID],
+		derefString(record.Leader),
+		derefString(record.Phone),
+		derefString(record.Email),
+		strconv.Itoa(record.OrderNum),
+		status,
+		record.CreatedAt.Format(time.DateTime),
+		derefString(record.Remark),
+	}
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
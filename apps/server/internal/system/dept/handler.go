@@ -4,12 +4,15 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 	"github.com/starter-kit-fe/admin/pkg/resp"
+	"github.com/starter-kit-fe/admin/pkg/spreadsheet"
 )
 
 type Handler struct {
@@ -75,6 +78,49 @@ func (h *Handler) List(ctx *gin.Context) {
 	resp.OK(ctx, resp.WithData(items))
 }
 
+// Export godoc
+// @Summary 导出部门
+// @Description 按列表筛选条件导出部门及上级部门名称
+// @Tags System/Dept
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param status query string false "部门状态"
+// @Param deptName query string false "部门名称"
+// @Param format query string false "导出格式：csv 或 xlsx，默认 csv"
+// @Success 200 {file} file
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/departments/export [get]
+func (h *Handler) Export(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("department service unavailable"))
+		return
+	}
+
+	format, err := spreadsheet.ParseFormat(ctx.Query("format"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("unsupported export format"))
+		return
+	}
+
+	out := spreadsheet.NewAttachment(ctx.Writer, format, "departments_"+time.Now().Format("20060102150405"), "departments")
+	err = h.service.ExportDepartments(ctx.Request.Context(), QueryOptions{
+		Status:   ctx.Query("status"),
+		DeptName: ctx.Query("deptName"),
+	}, out.WriteRow)
+	if err == nil {
+		err = out.Close()
+	}
+	if err != nil {
+		if out.Started() {
+			_ = ctx.Error(err)
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to export departments"))
+	}
+}
+
 // Tree godoc
 // @Summary 获取部门树
 // @Description 查询部门树形结构
@@ -113,6 +159,7 @@ func (h *Handler) Tree(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "部门ID"
 // @Success 200 {object} resp.Response
+// @Header 200 {string} ETag "部门版本，修改或删除时通过 If-Match 回传"
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 500 {object} resp.Response
@@ -140,6 +187,7 @@ func (h *Handler) Get(ctx *gin.Context) {
 		return
 	}
 
+	etag.Set(ctx, *dept.UpdatedAt)
 	resp.OK(ctx, resp.WithData(dept))
 }
 
@@ -213,10 +261,13 @@ func (h *Handler) Create(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "部门ID"
 // @Param request body updateDepartmentRequest true "部门参数"
+// @Param If-Match header string true "获取部门时返回的 ETag"
 // @Success 200 {object} resp.Response
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 409 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/departments/{id} [put]
@@ -238,6 +289,11 @@ func (h *Handler) Update(ctx *gin.Context) {
 		return
 	}
 
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
 	operator := resolveOperator(ctx)
 	dept, err := h.service.UpdateDepartment(ctx.Request.Context(), UpdateDepartmentInput{
 		ID:       id,
@@ -255,6 +311,8 @@ func (h *Handler) Update(ctx *gin.Context) {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("department not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("department has been modified, reload and retry"))
 		case errors.Is(err, ErrDeptNameRequired),
 			errors.Is(err, ErrInvalidDepartmentOrder),
 			errors.Is(err, ErrInvalidDepartmentStatus),
@@ -278,10 +336,13 @@ func (h *Handler) Update(ctx *gin.Context) {
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "部门ID"
+// @Param If-Match header string true "获取部门时返回的 ETag"
 // @Success 204 {object} nil
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 409 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/departments/{id} [delete]
@@ -297,11 +358,18 @@ func (h *Handler) Delete(ctx *gin.Context) {
 		return
 	}
 
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
 	operator := resolveOperator(ctx)
 	if err := h.service.DeleteDepartment(ctx.Request.Context(), id, operator); err != nil {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("department not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("department has been modified, reload and retry"))
 		case errors.Is(err, ErrDepartmentHasChildren),
 			errors.Is(err, ErrDepartmentHasUsers):
 			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
@@ -314,6 +382,115 @@ func (h *Handler) Delete(ctx *gin.Context) {
 	resp.NoContent(ctx)
 }
 
+type mergePreviewQuery struct {
+	TargetID int64 `form:"targetId" binding:"required"`
+}
+
+type mergeDepartmentRequest struct {
+	TargetID int64 `json:"targetId" binding:"required"`
+}
+
+// MergePreview godoc
+// @Summary 预览部门合并
+// @Description 统计将部门合并到目标部门时迁移的用户、角色数据权限与下级部门数量
+// @Tags System/Dept
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "源部门ID"
+// @Param targetId query int true "目标部门ID"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/departments/{id}/merge/preview [get]
+func (h *Handler) MergePreview(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("department service unavailable"))
+		return
+	}
+
+	id, err := parseDeptID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid department id"))
+		return
+	}
+
+	var query mergePreviewQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	preview, err := h.service.PreviewMerge(ctx.Request.Context(), id, query.TargetID)
+	if err != nil {
+		writeMergeError(ctx, err)
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(preview))
+}
+
+// Merge godoc
+// @Summary 合并部门
+// @Description 将部门的用户、角色数据权限与下级部门迁移到目标部门，并删除该部门
+// @Tags System/Dept
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "源部门ID"
+// @Param request body mergeDepartmentRequest true "目标部门"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 409 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/departments/{id}/merge [post]
+func (h *Handler) Merge(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("department service unavailable"))
+		return
+	}
+
+	id, err := parseDeptID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid department id"))
+		return
+	}
+
+	var payload mergeDepartmentRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid merge payload"))
+		return
+	}
+
+	result, err := h.service.MergeDepartment(ctx.Request.Context(), MergeDepartmentInput{
+		SourceID: id,
+		TargetID: payload.TargetID,
+		Operator: resolveOperator(ctx),
+	})
+	if err != nil {
+		writeMergeError(ctx, err)
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(result))
+}
+
+func writeMergeError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		resp.NotFound(ctx, resp.WithMessage("department not found"))
+	case errors.Is(err, ErrInvalidMergeTarget):
+		resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+	case errors.Is(err, ErrDuplicateDepartmentName):
+		resp.Conflict(ctx, resp.WithMessage("target department already has child departments with the same name"))
+	default:
+		resp.InternalServerError(ctx, resp.WithMessage("failed to merge department"))
+	}
+}
+
 func parseDeptID(raw string) (int64, error) {
 	trimmed := strings.TrimSpace(raw)
 	if trimmed == "" {
@@ -0,0 +1,68 @@
+package dept
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrInvalidMergeTarget = errors.New("invalid merge target department")
+
+// MergePreview 描述将源部门合并到目标部门时受影响的数据量
+type MergePreview struct {
+	SourceID    int64    `json:"sourceId"`
+	SourceName  string   `json:"sourceName"`
+	TargetID    int64    `json:"targetId"`
+	TargetName  string   `json:"targetName"`
+	Users       int64    `json:"users"`
+	RoleGrants  int64    `json:"roleGrants"`
+	Children    int64    `json:"children"`
+	Descendants int64    `json:"descendants"`
+	Conflicts   []string `json:"conflicts"`
+}
+
+type MergeDepartmentInput struct {
+	SourceID int64
+	TargetID int64
+	Operator string
+}
+
+// PreviewMerge 统计合并将迁移的用户、角色数据权限与下级部门，并列出与目标部门重名的下级部门
+func (s *Service) PreviewMerge(ctx context.Context, sourceID, targetID int64) (*MergePreview, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	plan, err := s.repo.PlanMerge(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return buildMergePreview(plan), nil
+}
+
+// MergeDepartment 将源部门合并到目标部门，存在重名下级部门时拒绝执行
+func (s *Service) MergeDepartment(ctx context.Context, input MergeDepartmentInput) (*MergePreview, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	plan, err := s.repo.MergeDepartment(ctx, input.SourceID, input.TargetID, sanitizeOperator(input.Operator), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return buildMergePreview(plan), nil
+}
+
+func buildMergePreview(plan *MergePlan) *MergePreview {
+	return &MergePreview{
+		SourceID:    int64(plan.Source.ID),
+		SourceName:  plan.Source.DeptName,
+		TargetID:    int64(plan.Target.ID),
+		TargetName:  plan.Target.DeptName,
+		Users:       plan.Stats.Users,
+		RoleGrants:  plan.Stats.RoleGrants,
+		Children:    plan.Stats.Children,
+		Descendants: plan.Stats.Descendants,
+		Conflicts:   plan.Conflicts,
+	}
+}
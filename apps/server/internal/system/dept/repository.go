@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 )
 
 var (
@@ -38,21 +40,61 @@ func (r *Repository) ListDepartments(ctx context.Context, opts ListOptions) ([]m
 		return nil, ErrRepositoryUnavailable
 	}
 
-	query := r.db.WithContext(ctx).Model(&model.SysDept{})
+	query := applyDeptFilters(r.db.WithContext(ctx).Model(&model.SysDept{}), opts)
 
-	if status := strings.TrimSpace(opts.Status); status != "" && status != "all" {
-		query = query.Where("status = ?", status)
+	var depts []model.SysDept
+	if err := query.Order("parent_id ASC, order_num ASC, id ASC").Find(&depts).Error; err != nil {
+		return nil, err
 	}
+	return depts, nil
+}
 
-	if name := strings.TrimSpace(opts.DeptName); name != "" {
-		query = query.Where("dept_name ILIKE ?", "%"+name+"%")
+// ExportDepartments 按列表筛选条件分批读取部门
+func (r *Repository) ExportDepartments(ctx context.Context, opts ListOptions, batchSize int, fn func([]model.SysDept) error) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+
+	var batch []model.SysDept
+	result := applyDeptFilters(r.db.WithContext(ctx).Model(&model.SysDept{}), opts).
+		FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+			return fn(batch)
+		})
+	return result.Error
+}
+
+// GetDepartmentNames 返回指定部门的名称映射
+func (r *Repository) GetDepartmentNames(ctx context.Context, ids []int64) (map[int64]string, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	names := make(map[int64]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
 	}
 
 	var depts []model.SysDept
-	if err := query.Order("parent_id ASC, order_num ASC, id ASC").Find(&depts).Error; err != nil {
+	if err := r.db.WithContext(ctx).
+		Select("id", "dept_name").
+		Where("id IN ?", ids).
+		Find(&depts).Error; err != nil {
 		return nil, err
 	}
-	return depts, nil
+	for _, dept := range depts {
+		names[int64(dept.ID)] = dept.DeptName
+	}
+	return names, nil
+}
+
+func applyDeptFilters(query *gorm.DB, opts ListOptions) *gorm.DB {
+	if status := strings.TrimSpace(opts.Status); status != "" && status != "all" {
+		query = query.Where("status = ?", status)
+	}
+
+	if name := strings.TrimSpace(opts.DeptName); name != "" {
+		query = query.Where("dept_name ILIKE ?", "%"+name+"%")
+	}
+	return query
 }
 
 func (r *Repository) GetDepartment(ctx context.Context, id int64) (*model.SysDept, error) {
@@ -94,49 +136,338 @@ func (r *Repository) UpdateDepartment(ctx context.Context, id int64, updates map
 		return nil
 	}
 
-	result := r.db.WithContext(ctx).
-		Model(&model.SysDept{}).
-		Where("id = ?", id).
-		Updates(updates)
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Model(&model.SysDept{}).Where("id = ?", id)).Updates(updates)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+		return etag.Missed(db, &model.SysDept{}, id)
 	}
 	return nil
 }
 
-func (r *Repository) UpdateDescendantAncestors(
+// MoveDepartment 在同一事务内更新部门并改写其全部下级部门的 ancestors 路径，
+// 任一步失败都会整体回滚，避免物化路径不一致。
+func (r *Repository) MoveDepartment(
 	ctx context.Context,
+	id int64,
+	updates map[string]interface{},
 	oldPrefix string,
 	newPrefix string,
-	deptID int64,
 	operator string,
 	ts time.Time,
 ) error {
 	if r == nil || r.db == nil {
 		return ErrRepositoryUnavailable
 	}
-	if deptID <= 0 {
+	if id <= 0 {
 		return gorm.ErrRecordNotFound
 	}
-	if strings.TrimSpace(oldPrefix) == "" || strings.TrimSpace(newPrefix) == "" || oldPrefix == newPrefix {
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := etag.Guard(tx.Model(&model.SysDept{}).Where("id = ?", id)).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return etag.Missed(tx, &model.SysDept{}, id)
+		}
+
+		_, err := rewriteAncestors(tx, subtreePath(oldPrefix, id), subtreePath(newPrefix, id), operator, ts)
+		return err
+	})
+}
+
+// MergeStats 记录合并部门时受影响的数据量
+type MergeStats struct {
+	Users       int64
+	RoleGrants  int64
+	Children    int64
+	Descendants int64
+}
+
+// MergePlan 描述一次部门合并：合并双方、受影响的数据量以及与目标部门下级重名的源部门下级
+type MergePlan struct {
+	Source    *model.SysDept
+	Target    *model.SysDept
+	Stats     *MergeStats
+	Conflicts []string
+}
+
+// PlanMerge 读取合并双方并统计将被迁移的数据量与重名的下级部门，不做任何修改
+func (r *Repository) PlanMerge(ctx context.Context, sourceID, targetID int64) (*MergePlan, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	db := r.db.WithContext(ctx)
+	source, target, err := loadMergePair(db, sourceID, targetID, false)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := countMergeImpact(db, source)
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := mergeConflicts(db, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &MergePlan{Source: source, Target: target, Stats: stats, Conflicts: conflicts}, nil
+}
+
+// loadMergePair 读取合并双方并校验目标部门不在源部门的子树内；lock 为 true 时以 SELECT ... FOR UPDATE 锁定双方
+func loadMergePair(db *gorm.DB, sourceID, targetID int64, lock bool) (*model.SysDept, *model.SysDept, error) {
+	if sourceID <= 0 {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+	if targetID <= 0 || targetID == sourceID {
+		return nil, nil, ErrInvalidMergeTarget
+	}
+
+	load := func(id int64) (*model.SysDept, error) {
+		query := db
+		if lock {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		var dept model.SysDept
+		if err := query.Where("id = ?", id).First(&dept).Error; err != nil {
+			return nil, err
+		}
+		return &dept, nil
+	}
+
+	source, err := load(sourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := load(targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 目标部门不能位于源部门的子树内，否则合并后会形成环
+	if ancestorsContains(target.Ancestors, sourceID) {
+		return nil, nil, ErrInvalidMergeTarget
+	}
+	return source, target, nil
+}
+
+// countMergeImpact 统计合并源部门时将被迁移的数据量
+func countMergeImpact(db *gorm.DB, source *model.SysDept) (*MergeStats, error) {
+	sourceID := int64(source.ID)
+	stats := &MergeStats{}
+
+	if err := db.Model(&model.SysUser{}).Where("dept_id = ?", sourceID).Count(&stats.Users).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&model.SysRoleDept{}).Where("dept_id = ?", sourceID).Count(&stats.RoleGrants).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&model.SysDept{}).Where("parent_id = ?", sourceID).Count(&stats.Children).Error; err != nil {
+		return nil, err
+	}
+	if err := descendantsOf(db.Model(&model.SysDept{}), subtreePath(source.Ancestors, sourceID)).
+		Count(&stats.Descendants).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// mergeConflicts 返回源部门与目标部门下同名的直接下级部门
+func mergeConflicts(db *gorm.DB, sourceID, targetID int64) ([]string, error) {
+	sourceChildren, err := childNames(db, sourceID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(sourceChildren) == 0 {
+		return []string{}, nil
+	}
+	// 源部门本身可能是目标部门的下级，合并后会被删除，不计入重名
+	targetChildren, err := childNames(db, targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]struct{}, len(targetChildren))
+	for _, name := range targetChildren {
+		existing[name] = struct{}{}
+	}
+	conflicts := make([]string, 0)
+	for _, name := range sourceChildren {
+		if _, ok := existing[name]; ok {
+			conflicts = append(conflicts, name)
+		}
+	}
+	return conflicts, nil
+}
+
+// childNames 返回指定部门的直接下级部门名称，excludeID 大于 0 时排除该部门
+func childNames(db *gorm.DB, parentID int64, excludeID int64) ([]string, error) {
+	query := db.Model(&model.SysDept{}).Where("parent_id = ?", parentID)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	var names []string
+	err := query.Order("order_num ASC, id ASC").Pluck("dept_name", &names).Error
+	return names, err
+}
+
+// MergeDepartment 在同一事务内将源部门的用户、角色数据权限与下级部门迁移到目标部门，
+// 并软删除源部门。合并双方在事务内加锁读取并重新校验，存在重名下级部门时拒绝执行。
+func (r *Repository) MergeDepartment(
+	ctx context.Context,
+	sourceID int64,
+	targetID int64,
+	operator string,
+	ts time.Time,
+) (*MergePlan, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	plan := &MergePlan{Stats: &MergeStats{}, Conflicts: []string{}}
+	stats := plan.Stats
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		source, target, err := loadMergePair(tx, sourceID, targetID, true)
+		if err != nil {
+			return err
+		}
+		conflicts, err := mergeConflicts(tx, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return ErrDuplicateDepartmentName
+		}
+		plan.Source, plan.Target = source, target
+
+		result := tx.Model(&model.SysUser{}).
+			Where("dept_id = ?", sourceID).
+			Updates(map[string]interface{}{
+				"dept_id":    targetID,
+				"update_by":  operator,
+				"updated_at": ts,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		stats.Users = result.RowsAffected
+
+		var roleIDs []int64
+		if err := tx.Model(&model.SysRoleDept{}).
+			Where("dept_id = ?", sourceID).
+			Pluck("role_id", &roleIDs).Error; err != nil {
+			return err
+		}
+		stats.RoleGrants = int64(len(roleIDs))
+
+		if len(roleIDs) > 0 {
+			var granted []int64
+			if err := tx.Model(&model.SysRoleDept{}).
+				Where("dept_id = ? AND role_id IN ?", targetID, roleIDs).
+				Pluck("role_id", &granted).Error; err != nil {
+				return err
+			}
+			existing := make(map[int64]struct{}, len(granted))
+			for _, id := range granted {
+				existing[id] = struct{}{}
+			}
+
+			grants := make([]model.SysRoleDept, 0, len(roleIDs))
+			for _, roleID := range roleIDs {
+				if _, ok := existing[roleID]; ok {
+					continue
+				}
+				grants = append(grants, model.SysRoleDept{RoleID: roleID, DeptID: targetID})
+			}
+			if len(grants) > 0 {
+				if err := tx.Create(&grants).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Where("dept_id = ?", sourceID).Delete(&model.SysRoleDept{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := moveRoleBindings(tx, sourceID, targetID); err != nil {
+			return err
+		}
+
+		result = tx.Model(&model.SysDept{}).
+			Where("parent_id = ?", sourceID).
+			Updates(map[string]interface{}{
+				"parent_id":  targetID,
+				"update_by":  operator,
+				"updated_at": ts,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		stats.Children = result.RowsAffected
+
+		// 源部门的下级整体挂到目标部门下，路径前缀由源部门替换为目标部门
+		descendants, err := rewriteAncestors(
+			tx,
+			subtreePath(source.Ancestors, sourceID),
+			subtreePath(target.Ancestors, targetID),
+			operator,
+			ts,
+		)
+		if err != nil {
+			return err
+		}
+		stats.Descendants = descendants
+
+		if err := tx.Model(&model.SysDept{}).
+			Where("id = ?", sourceID).
+			Updates(map[string]interface{}{
+				"update_by":  operator,
+				"updated_at": ts,
+			}).Error; err != nil {
+			return err
+		}
+		result = tx.Delete(&model.SysDept{}, sourceID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
 		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return plan, nil
+}
 
-	oldPath := fmt.Sprintf("%s,%d", oldPrefix, deptID)
-	newPath := fmt.Sprintf("%s,%d", newPrefix, deptID)
+// subtreePath 返回部门下级节点 ancestors 的公共前缀
+func subtreePath(ancestors string, id int64) string {
+	return fmt.Sprintf("%s,%d", ancestors, id)
+}
 
-	result := r.db.WithContext(ctx).
-		Model(&model.SysDept{}).
-		Where("ancestors LIKE ?", oldPath+"%").
+// descendantsOf 匹配路径位于 path 之下的全部部门；追加逗号避免 "0,1" 误匹配 "0,10"
+func descendantsOf(query *gorm.DB, path string) *gorm.DB {
+	return query.Where("ancestors = ? OR ancestors LIKE ?", path, path+",%")
+}
+
+// rewriteAncestors 将 oldPath 子树下所有部门的路径前缀替换为 newPath
+func rewriteAncestors(tx *gorm.DB, oldPath, newPath, operator string, ts time.Time) (int64, error) {
+	if strings.TrimSpace(oldPath) == "" || strings.TrimSpace(newPath) == "" || oldPath == newPath {
+		return 0, nil
+	}
+
+	result := descendantsOf(tx.Model(&model.SysDept{}), oldPath).
 		Updates(map[string]interface{}{
-			"ancestors":   gorm.Expr("REPLACE(ancestors, ?, ?)", oldPath, newPath),
-			"update_by":   operator,
-			"update_time": ts,
+			"ancestors":  gorm.Expr(fmt.Sprintf("CAST(? AS TEXT) || SUBSTR(ancestors, %d)", len(oldPath)+1), newPath),
+			"update_by":  operator,
+			"updated_at": ts,
 		})
-	return result.Error
+	return result.RowsAffected, result.Error
 }
 
 func (r *Repository) SoftDeleteDepartment(ctx context.Context, id int64, operator string, ts time.Time) error {
@@ -147,18 +478,20 @@ func (r *Repository) SoftDeleteDepartment(ctx context.Context, id int64, operato
 		return gorm.ErrRecordNotFound
 	}
 
-	result := r.db.WithContext(ctx).
-		Model(&model.SysDept{}).
-		Where("id = ?", id).
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Model(&model.SysDept{}).Where("id = ?", id)).
 		Updates(map[string]interface{}{
-			"update_by":   operator,
-			"update_time": ts,
+			"update_by":  operator,
+			"updated_at": ts,
 		})
 	if result.Error != nil {
 		return result.Error
 	}
+	if result.RowsAffected == 0 {
+		return etag.Missed(db, &model.SysDept{}, id)
+	}
 
-	result = r.db.WithContext(ctx).Delete(&model.SysDept{}, id)
+	result = db.Delete(&model.SysDept{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -224,3 +557,40 @@ func (r *Repository) ExistsByName(ctx context.Context, parentID int64, name stri
 	}
 	return count > 0, nil
 }
+
+// moveRoleBindings 将源部门绑定的角色转移到目标部门，目标部门已绑定的角色保留原配置
+func moveRoleBindings(tx *gorm.DB, sourceID, targetID int64) error {
+	var bindings []model.SysDeptRole
+	if err := tx.Where("dept_id = ?", sourceID).Find(&bindings).Error; err != nil {
+		return err
+	}
+	if len(bindings) == 0 {
+		return nil
+	}
+
+	var bound []int64
+	if err := tx.Model(&model.SysDeptRole{}).
+		Where("dept_id = ?", targetID).
+		Pluck("role_id", &bound).Error; err != nil {
+		return err
+	}
+	existing := make(map[int64]struct{}, len(bound))
+	for _, id := range bound {
+		existing[id] = struct{}{}
+	}
+
+	moved := make([]model.SysDeptRole, 0, len(bindings))
+	for _, binding := range bindings {
+		if _, ok := existing[binding.RoleID]; ok {
+			continue
+		}
+		binding.DeptID = targetID
+		moved = append(moved, binding)
+	}
+	if len(moved) > 0 {
+		if err := tx.Create(&moved).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Where("dept_id = ?", sourceID).Delete(&model.SysDeptRole{}).Error
+}
@@ -11,33 +11,41 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+	"github.com/starter-kit-fe/admin/internal/system/history"
 )
 
 var (
-	ErrServiceUnavailable        = errors.New("department service is not initialized")
-	ErrDeptNameRequired          = errors.New("department name is required")
-	ErrInvalidDepartmentStatus   = errors.New("invalid department status")
-	ErrInvalidDepartmentOrder    = errors.New("invalid department order")
-	ErrInvalidParentDepartment   = errors.New("invalid parent department")
-	ErrDuplicateDepartmentName   = errors.New("duplicate department name")
-	ErrDepartmentHasChildren     = errors.New("department has child departments")
-	ErrDepartmentHasUsers        = errors.New("department has linked users")
-	defaultAncestor              = "0"
-	validDepartmentStatusOptions = map[string]struct{}{
-		"0": {},
-		"1": {},
-	}
+	ErrServiceUnavailable      = errors.New("department service is not initialized")
+	ErrDeptNameRequired        = errors.New("department name is required")
+	ErrInvalidDepartmentStatus = errors.New("invalid department status")
+	ErrInvalidDepartmentOrder  = errors.New("invalid department order")
+	ErrInvalidParentDepartment = errors.New("invalid parent department")
+	ErrDuplicateDepartmentName = errors.New("duplicate department name")
+	ErrDepartmentHasChildren   = errors.New("department has child departments")
+	ErrDepartmentHasUsers      = errors.New("department has linked users")
+	defaultAncestor            = "0"
 )
 
+var statusEnum = dict.Enum{
+	DictType: dict.TypeNormalDisable,
+	Fallback: []string{"0", "1"},
+	Default:  "0",
+	Invalid:  ErrInvalidDepartmentStatus,
+}
+
 type Service struct {
-	repo *Repository
+	repo    *Repository
+	history *history.Service
+	dicts   *dict.Service
 }
 
-func NewService(repo *Repository) *Service {
+// NewService 创建部门服务；dicts 可选，用于按字典校验状态取值
+func NewService(repo *Repository, history *history.Service, dicts *dict.Service) *Service {
 	if repo == nil {
 		return nil
 	}
-	return &Service{repo: repo}
+	return &Service{repo: repo, history: history, dicts: dicts}
 }
 
 type QueryOptions struct {
@@ -46,16 +54,17 @@ type QueryOptions struct {
 }
 
 type Department struct {
-	ID       int64         `json:"id"`
-	ParentID int64         `json:"parentId"`
-	DeptName string        `json:"deptName"`
-	Leader   *string       `json:"leader,omitempty"`
-	Phone    *string       `json:"phone,omitempty"`
-	Email    *string       `json:"email,omitempty"`
-	OrderNum int           `json:"orderNum"`
-	Status   string        `json:"status"`
-	Remark   *string       `json:"remark,omitempty"`
-	Children []*Department `json:"children,omitempty"`
+	ID        int64         `json:"id"`
+	ParentID  int64         `json:"parentId"`
+	DeptName  string        `json:"deptName"`
+	Leader    *string       `json:"leader,omitempty"`
+	Phone     *string       `json:"phone,omitempty"`
+	Email     *string       `json:"email,omitempty"`
+	OrderNum  int           `json:"orderNum"`
+	Status    string        `json:"status"`
+	Remark    *string       `json:"remark,omitempty"`
+	UpdatedAt *time.Time    `json:"updatedAt,omitempty"`
+	Children  []*Department `json:"children,omitempty"`
 }
 
 type CreateDepartmentInput struct {
@@ -144,9 +153,9 @@ func (s *Service) CreateDepartment(ctx context.Context, input CreateDepartmentIn
 		return nil, ErrInvalidDepartmentOrder
 	}
 
-	status := normalizeStatus(input.Status)
-	if _, ok := validDepartmentStatusOptions[status]; !ok {
-		return nil, ErrInvalidDepartmentStatus
+	status, err := s.dicts.Normalize(ctx, statusEnum, input.Status)
+	if err != nil {
+		return nil, err
 	}
 
 	if input.ParentID < 0 {
@@ -195,7 +204,15 @@ func (s *Service) CreateDepartment(ctx context.Context, input CreateDepartmentIn
 		return nil, err
 	}
 
-	return departmentFromModel(record), nil
+	created := departmentFromModel(record)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleDept,
+		EntityID: created.ID,
+		Action:   history.ActionCreate,
+		Operator: operator,
+		After:    created,
+	})
+	return created, nil
 }
 
 func (s *Service) UpdateDepartment(ctx context.Context, input UpdateDepartmentInput) (*Department, error) {
@@ -277,9 +294,9 @@ func (s *Service) UpdateDepartment(ctx context.Context, input UpdateDepartmentIn
 	}
 
 	if input.Status != nil {
-		status := normalizeStatus(*input.Status)
-		if _, ok := validDepartmentStatusOptions[status]; !ok {
-			return nil, ErrInvalidDepartmentStatus
+		status, err := s.dicts.Normalize(ctx, statusEnum, *input.Status)
+		if err != nil {
+			return nil, err
 		}
 		updates["status"] = status
 	}
@@ -298,23 +315,31 @@ func (s *Service) UpdateDepartment(ctx context.Context, input UpdateDepartmentIn
 	}
 
 	updates["update_by"] = operator
-	updates["update_time"] = now
-
-	if err := s.repo.UpdateDepartment(ctx, input.ID, updates); err != nil {
-		return nil, err
-	}
+	updates["updated_at"] = now
 
 	if parentChanged {
-		if err := s.repo.UpdateDescendantAncestors(ctx, oldAncestors, newAncestors, int64(current.ID), operator, now); err != nil {
+		// 变更上级时部门与其下级的路径需在同一事务内更新
+		if err := s.repo.MoveDepartment(ctx, input.ID, updates, oldAncestors, newAncestors, operator, now); err != nil {
 			return nil, err
 		}
+	} else if err := s.repo.UpdateDepartment(ctx, input.ID, updates); err != nil {
+		return nil, err
 	}
 
-	updated, err := s.repo.GetDepartment(ctx, input.ID)
+	record, err := s.repo.GetDepartment(ctx, input.ID)
 	if err != nil {
 		return nil, err
 	}
-	return departmentFromModel(updated), nil
+	updated := departmentFromModel(record)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleDept,
+		EntityID: input.ID,
+		Action:   history.ActionUpdate,
+		Operator: operator,
+		Before:   departmentFromModel(current),
+		After:    updated,
+	})
+	return updated, nil
 }
 
 func (s *Service) DeleteDepartment(ctx context.Context, id int64, operator string) error {
@@ -325,7 +350,8 @@ func (s *Service) DeleteDepartment(ctx context.Context, id int64, operator strin
 		return gorm.ErrRecordNotFound
 	}
 
-	if _, err := s.repo.GetDepartment(ctx, id); err != nil {
+	current, err := s.repo.GetDepartment(ctx, id)
+	if err != nil {
 		return err
 	}
 
@@ -341,8 +367,18 @@ func (s *Service) DeleteDepartment(ctx context.Context, id int64, operator strin
 		return ErrDepartmentHasUsers
 	}
 
-	now := time.Now()
-	return s.repo.SoftDeleteDepartment(ctx, id, sanitizeOperator(operator), now)
+	operator = sanitizeOperator(operator)
+	if err := s.repo.SoftDeleteDepartment(ctx, id, operator, time.Now()); err != nil {
+		return err
+	}
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleDept,
+		EntityID: id,
+		Action:   history.ActionDelete,
+		Operator: operator,
+		Before:   departmentFromModel(current),
+	})
+	return nil
 }
 
 func buildTree(records []model.SysDept) []*Department {
@@ -391,24 +427,17 @@ func departmentFromModel(record *model.SysDept) *Department {
 		return nil
 	}
 	return &Department{
-		ID:       int64(record.ID),
-		ParentID: record.ParentID,
-		DeptName: record.DeptName,
-		Leader:   record.Leader,
-		Phone:    record.Phone,
-		Email:    record.Email,
-		OrderNum: record.OrderNum,
-		Status:   record.Status,
-		Remark:   record.Remark,
-	}
-}
-
-func normalizeStatus(value string) string {
-	trimmed := strings.TrimSpace(value)
-	if trimmed == "" {
-		return "0"
+		ID:        int64(record.ID),
+		ParentID:  record.ParentID,
+		DeptName:  record.DeptName,
+		Leader:    record.Leader,
+		Phone:     record.Phone,
+		Email:     record.Email,
+		OrderNum:  record.OrderNum,
+		Status:    record.Status,
+		Remark:    record.Remark,
+		UpdatedAt: &record.UpdatedAt,
 	}
-	return trimmed
 }
 
 func composeAncestors(parentAncestors string, parentID int64) string {
@@ -0,0 +1,103 @@
+package dict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidEnumValue 未指定 Enum.Invalid 时，取值不在字典中返回该错误
+var ErrInvalidEnumValue = errors.New("invalid dictionary value")
+
+// 系统模块共用的字典类型
+const (
+	TypeNormalDisable = "sys_normal_disable"
+	TypeUserSex       = "sys_user_sex"
+	TypeNoticeType    = "sys_notice_type"
+	TypeNoticeStatus  = "sys_notice_status"
+	TypeDataScope     = "sys_data_scope"
+)
+
+// Enum 描述取值受字典约束的字段：合法取值为字典类型下启用的字典数据值，
+// 空值取字典中标记为默认的一项。字典类型不存在或没有启用的数据时退回 Fallback，
+// 保证未初始化字典的租户仍可正常写入。
+type Enum struct {
+	DictType string
+	// Fallback 字典不可用时允许的取值
+	Fallback []string
+	// Default 值为空且字典没有默认项时使用的取值，为空表示必填
+	Default string
+	// Invalid 取值不合法时返回的错误，便于各模块沿用原有错误
+	Invalid error
+	// Strict 为 true 时字典取值还必须属于 Fallback，用于在代码中有固定含义的取值，
+	// 此时字典只能停用或排序已有取值，不能引入新取值
+	Strict bool
+}
+
+// Normalize 校验并返回字段取值；s 为 nil 时只按 Fallback 校验
+func (s *Service) Normalize(ctx context.Context, enum Enum, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	allowed, defaultValue, err := s.enumValues(ctx, enum)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		if defaultValue == "" {
+			return "", fmt.Errorf("%w: a %s value is required", enum.invalid(), enum.DictType)
+		}
+		// 字典没有默认项时退回 Enum.Default，该取值可能已被停用
+		if !contains(allowed, defaultValue) {
+			return "", fmt.Errorf("%w: default %q is not an enabled %s value, a value is required", enum.invalid(), defaultValue, enum.DictType)
+		}
+		return defaultValue, nil
+	}
+	if contains(allowed, value) {
+		return value, nil
+	}
+	return "", fmt.Errorf("%w: %q is not an enabled %s value", enum.invalid(), value, enum.DictType)
+}
+
+func (s *Service) enumValues(ctx context.Context, enum Enum) ([]string, string, error) {
+	if s != nil && s.repo != nil && enum.DictType != "" {
+		result, err := s.LookupOptions(ctx, []string{enum.DictType})
+		if err != nil {
+			return nil, "", err
+		}
+		if options := result[enum.DictType]; len(options) > 0 {
+			values := make([]string, 0, len(options))
+			defaultValue := ""
+			for _, option := range options {
+				if enum.Strict && !contains(enum.Fallback, option.DictValue) {
+					continue
+				}
+				values = append(values, option.DictValue)
+				if defaultValue == "" && option.IsDefault == "Y" {
+					defaultValue = option.DictValue
+				}
+			}
+			if defaultValue == "" {
+				defaultValue = enum.Default
+			}
+			return values, defaultValue, nil
+		}
+	}
+	return enum.Fallback, enum.Default, nil
+}
+
+func (e Enum) invalid() error {
+	if e.Invalid != nil {
+		return e.Invalid
+	}
+	return ErrInvalidEnumValue
+}
+
+func contains(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -1,16 +1,25 @@
 package dict
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"github.com/starter-kit-fe/admin/internal/system/i18n"
 	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 	"github.com/starter-kit-fe/admin/pkg/resp"
+	"github.com/starter-kit-fe/admin/pkg/spreadsheet"
 )
 
+const maxImportFileSize = 10 << 20
+
 type listDictsQuery struct {
 	Status   string `form:"status"`
 	DictName string `form:"dictName"`
@@ -35,12 +44,14 @@ type listDictDataQuery struct {
 	Status    string `form:"status"`
 	DictLabel string `form:"dictLabel"`
 	DictValue string `form:"dictValue"`
+	ParentID  *int64 `form:"parentId"`
 }
 
 type createDictDataRequest struct {
 	DictLabel string  `json:"dictLabel" binding:"required"`
 	DictValue string  `json:"dictValue" binding:"required"`
 	DictSort  *int    `json:"dictSort"`
+	ParentID  int64   `json:"parentId"`
 	Status    string  `json:"status"`
 	IsDefault string  `json:"isDefault"`
 	Remark    *string `json:"remark"`
@@ -52,6 +63,7 @@ type updateDictDataRequest struct {
 	DictLabel *string `json:"dictLabel"`
 	DictValue *string `json:"dictValue"`
 	DictSort  *int    `json:"dictSort"`
+	ParentID  *int64  `json:"parentId"`
 	Status    *string `json:"status"`
 	IsDefault *string `json:"isDefault"`
 	Remark    *string `json:"remark"`
@@ -59,15 +71,25 @@ type updateDictDataRequest struct {
 	CSSClass  *string `json:"cssClass"`
 }
 
+type lookupOptionsQuery struct {
+	Types string `form:"types"`
+}
+
+type childOptionsQuery struct {
+	Parent string `form:"parent"`
+}
+
 type Handler struct {
-	service *Service
+	service      *Service
+	translations *i18n.Service
 }
 
-func NewHandler(service *Service) *Handler {
+// NewHandler 创建字典处理器；translations 可选，用于按请求语言翻译字典选项的标签
+func NewHandler(service *Service, translations *i18n.Service) *Handler {
 	if service == nil {
 		return nil
 	}
-	return &Handler{service: service}
+	return &Handler{service: service, translations: translations}
 }
 
 // List godoc
@@ -117,6 +139,7 @@ func (h *Handler) List(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "字典ID"
 // @Success 200 {object} resp.Response
+// @Header 200 {string} ETag "字典版本，修改或删除时通过 If-Match 回传"
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 500 {object} resp.Response
@@ -144,6 +167,7 @@ func (h *Handler) Get(ctx *gin.Context) {
 		return
 	}
 
+	etag.Set(ctx, *item.UpdatedAt)
 	resp.OK(ctx, resp.WithData(item))
 }
 
@@ -207,10 +231,13 @@ func (h *Handler) Create(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "字典ID"
 // @Param request body updateDictTypeRequest true "字典类型参数"
+// @Param If-Match header string true "获取字典时返回的 ETag"
 // @Success 200 {object} resp.Response
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 409 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/dicts/{id} [put]
@@ -232,6 +259,11 @@ func (h *Handler) Update(ctx *gin.Context) {
 		return
 	}
 
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
 	operator := resolveOperator(ctx)
 	item, err := h.service.UpdateDictType(ctx.Request.Context(), UpdateDictTypeInput{
 		ID:       id,
@@ -245,6 +277,8 @@ func (h *Handler) Update(ctx *gin.Context) {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("dictionary not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("dictionary has been modified, reload and retry"))
 		case errors.Is(err, ErrDictNameRequired),
 			errors.Is(err, ErrDictTypeRequired),
 			errors.Is(err, ErrInvalidDictStatus):
@@ -267,9 +301,12 @@ func (h *Handler) Update(ctx *gin.Context) {
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "字典ID"
+// @Param If-Match header string true "获取字典时返回的 ETag"
 // @Success 204 {object} nil
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/dicts/{id} [delete]
@@ -285,11 +322,20 @@ func (h *Handler) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteDictType(ctx.Request.Context(), id); err != nil {
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	if err := h.service.DeleteDictType(ctx.Request.Context(), id, resolveOperator(ctx)); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			resp.NotFound(ctx, resp.WithMessage("dictionary not found"))
 			return
 		}
+		if errors.Is(err, etag.ErrPreconditionFailed) {
+			resp.PreconditionFailed(ctx, resp.WithMessage("dictionary has been modified, reload and retry"))
+			return
+		}
 		resp.InternalServerError(ctx, resp.WithMessage("failed to delete dictionary"))
 		return
 	}
@@ -307,6 +353,7 @@ func (h *Handler) Delete(ctx *gin.Context) {
 // @Param status query string false "状态"
 // @Param dictLabel query string false "数据标签"
 // @Param dictValue query string false "数据值"
+// @Param parentId query int false "上级数据ID，0 表示只返回顶级数据"
 // @Success 200 {object} resp.Response
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
@@ -335,6 +382,7 @@ func (h *Handler) ListData(ctx *gin.Context) {
 		Status:    query.Status,
 		DictLabel: query.DictLabel,
 		DictValue: query.DictValue,
+		ParentID:  query.ParentID,
 	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -393,6 +441,7 @@ func (h *Handler) CreateData(ctx *gin.Context) {
 		DictLabel: payload.DictLabel,
 		DictValue: payload.DictValue,
 		DictSort:  sort,
+		ParentID:  payload.ParentID,
 		Status:    payload.Status,
 		IsDefault: payload.IsDefault,
 		Remark:    payload.Remark,
@@ -408,7 +457,9 @@ func (h *Handler) CreateData(ctx *gin.Context) {
 			errors.Is(err, ErrDictValueRequired),
 			errors.Is(err, ErrInvalidDictDataStatus),
 			errors.Is(err, ErrInvalidDictDataSort),
-			errors.Is(err, ErrInvalidDefaultFlag):
+			errors.Is(err, ErrInvalidDefaultFlag),
+			errors.Is(err, ErrInvalidParentData),
+			errors.Is(err, ErrDictDataCycle):
 			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
 		case errors.Is(err, ErrDuplicateDictLabel),
 			errors.Is(err, ErrDuplicateDictValue):
@@ -422,6 +473,53 @@ func (h *Handler) CreateData(ctx *gin.Context) {
 	resp.Created(ctx, resp.WithData(item))
 }
 
+// GetData godoc
+// @Summary 获取字典数据详情
+// @Description 查询指定字典下的单个数据项
+// @Tags System/Dict
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "字典ID"
+// @Param itemId path int true "数据ID"
+// @Success 200 {object} resp.Response
+// @Header 200 {string} ETag "字典数据版本，修改或删除时通过 If-Match 回传"
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/dicts/{id}/data/{itemId} [get]
+func (h *Handler) GetData(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("dictionary service unavailable"))
+		return
+	}
+
+	dictID, err := parseDictID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid dictionary id"))
+		return
+	}
+
+	dictCode, err := parseDictDataID(ctx.Param("itemId"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid dictionary data id"))
+		return
+	}
+
+	item, err := h.service.GetDictData(ctx.Request.Context(), dictID, dictCode)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.NotFound(ctx, resp.WithMessage("dictionary data not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load dictionary data"))
+		return
+	}
+
+	etag.Set(ctx, *item.UpdatedAt)
+	resp.OK(ctx, resp.WithData(item))
+}
+
 // UpdateData godoc
 // @Summary 修改字典数据
 // @Description 更新字典数据项
@@ -432,10 +530,13 @@ func (h *Handler) CreateData(ctx *gin.Context) {
 // @Param id path int true "字典ID"
 // @Param itemId path int true "数据ID"
 // @Param request body updateDictDataRequest true "字典数据参数"
+// @Param If-Match header string true "获取字典数据时返回的 ETag"
 // @Success 200 {object} resp.Response
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 409 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/dicts/{id}/data/{itemId} [put]
@@ -463,6 +564,11 @@ func (h *Handler) UpdateData(ctx *gin.Context) {
 		return
 	}
 
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
 	operator := resolveOperator(ctx)
 	item, err := h.service.UpdateDictData(ctx.Request.Context(), UpdateDictDataInput{
 		DictID:    dictID,
@@ -470,6 +576,7 @@ func (h *Handler) UpdateData(ctx *gin.Context) {
 		DictLabel: payload.DictLabel,
 		DictValue: payload.DictValue,
 		DictSort:  payload.DictSort,
+		ParentID:  payload.ParentID,
 		Status:    payload.Status,
 		IsDefault: payload.IsDefault,
 		Remark:    payload.Remark,
@@ -481,11 +588,15 @@ func (h *Handler) UpdateData(ctx *gin.Context) {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("dictionary data not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("dictionary data has been modified, reload and retry"))
 		case errors.Is(err, ErrDictLabelRequired),
 			errors.Is(err, ErrDictValueRequired),
 			errors.Is(err, ErrInvalidDictDataStatus),
 			errors.Is(err, ErrInvalidDictDataSort),
-			errors.Is(err, ErrInvalidDefaultFlag):
+			errors.Is(err, ErrInvalidDefaultFlag),
+			errors.Is(err, ErrInvalidParentData),
+			errors.Is(err, ErrDictDataCycle):
 			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
 		case errors.Is(err, ErrDuplicateDictLabel),
 			errors.Is(err, ErrDuplicateDictValue):
@@ -507,9 +618,14 @@ func (h *Handler) UpdateData(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "字典ID"
 // @Param itemId path int true "数据ID"
+// @Param cascade query bool false "存在下级时连同全部下级一起删除"
+// @Param If-Match header string true "获取字典数据时返回的 ETag"
 // @Success 204 {object} nil
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 409 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/dicts/{id}/data/{itemId} [delete]
@@ -531,18 +647,137 @@ func (h *Handler) DeleteData(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteDictData(ctx.Request.Context(), dictID, dictCode); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			resp.NotFound(ctx, resp.WithMessage("dictionary data not found"))
+	cascade := false
+	if raw := strings.TrimSpace(ctx.Query("cascade")); raw != "" {
+		cascade, err = strconv.ParseBool(raw)
+		if err != nil {
+			resp.BadRequest(ctx, resp.WithMessage("invalid cascade value"))
 			return
 		}
-		resp.InternalServerError(ctx, resp.WithMessage("failed to delete dictionary data"))
+	}
+
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	if err := h.service.DeleteDictData(ctx.Request.Context(), dictID, dictCode, resolveOperator(ctx), cascade); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			resp.NotFound(ctx, resp.WithMessage("dictionary data not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("dictionary data has been modified, reload and retry"))
+		case errors.Is(err, ErrDictDataHasChildren):
+			resp.Conflict(ctx, resp.WithMessage("dictionary data has child items, delete with cascade=true"))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to delete dictionary data"))
+		}
 		return
 	}
 
 	resp.NoContent(ctx)
 }
 
+// ImportData godoc
+// @Summary 导入字典数据
+// @Description 从 CSV/XLSX 导入字典数据，"上级键值"列引用文件中或已有的字典键值以构建层级；已存在的键值会被更新。默认只校验不写入（dryRun=true），正式导入时任一行校验失败则整体不写入
+// @Tags System/Dict
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "字典ID"
+// @Param file formData file true "导入文件（.csv 或 .xlsx）"
+// @Param dryRun formData bool false "仅校验不写入，默认 true"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 422 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/dicts/{id}/data/import [post]
+func (h *Handler) ImportData(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("dictionary service unavailable"))
+		return
+	}
+
+	dictID, err := parseDictID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid dictionary id"))
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("import file is required"))
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		resp.BadRequest(ctx, resp.WithMessage("import file is too large"))
+		return
+	}
+
+	format, err := spreadsheet.FormatFromFilename(fileHeader.Filename)
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("unsupported import file format"))
+		return
+	}
+
+	dryRun := true
+	if raw := strings.TrimSpace(ctx.PostForm("dryRun")); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			resp.BadRequest(ctx, resp.WithMessage("invalid dryRun value"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("failed to read import file"))
+		return
+	}
+	defer file.Close()
+
+	// 首行为表头，其后最多 MaxImportRows 行数据
+	rows, err := spreadsheet.ReadAll(format, io.LimitReader(file, maxImportFileSize), MaxImportRows+1)
+	if err != nil {
+		if errors.Is(err, spreadsheet.ErrTooManyRows) {
+			resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("import file exceeds %d rows", MaxImportRows)))
+			return
+		}
+		resp.BadRequest(ctx, resp.WithMessage("failed to parse import file"))
+		return
+	}
+
+	result, err := h.service.ImportDictData(ctx.Request.Context(), ImportDictDataInput{
+		DictID:   dictID,
+		Rows:     rows,
+		DryRun:   dryRun,
+		Operator: resolveOperator(ctx),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			resp.NotFound(ctx, resp.WithMessage("dictionary not found"))
+		case errors.Is(err, ErrImportEmpty):
+			resp.BadRequest(ctx, resp.WithMessage("import file has no data rows"))
+		case errors.Is(err, ErrImportTooManyRows):
+			resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("import file exceeds %d rows", MaxImportRows)))
+		case errors.Is(err, ErrImportMissingColumns):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		case errors.Is(err, ErrImportValidationFails):
+			resp.UnprocessableEntity(ctx, resp.WithMessage("import rows failed validation"), resp.WithData(result))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to import dictionary data"))
+		}
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(result))
+}
+
 func parseDictID(value string) (int64, error) {
 	return strconv.ParseInt(value, 10, 64)
 }
@@ -558,3 +793,215 @@ func resolveOperator(ctx *gin.Context) string {
 	}
 	return strconv.FormatUint(uint64(id), 10)
 }
+
+// Options godoc
+// @Summary 按类型获取字典选项
+// @Description 返回字典类型下启用的字典数据，按排序号排列；登录即可访问。响应带 ETag，请求携带 If-None-Match 且未变化时返回 304
+// @Tags Dict
+// @Security BearerAuth
+// @Produce json
+// @Param type path string true "字典类型"
+// @Param Accept-Language header string false "界面语言，用户设置了语言偏好时以偏好为准；标签按该语言的译文返回"
+// @Param If-None-Match header string false "上次响应的 ETag"
+// @Success 200 {object} resp.Response
+// @Success 304 {object} nil
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/dicts/types/{type} [get]
+func (h *Handler) Options(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("dictionary service unavailable"))
+		return
+	}
+
+	dictType := strings.TrimSpace(ctx.Param("type"))
+	result, err := h.service.LookupOptions(ctx.Request.Context(), []string{dictType})
+	if err != nil {
+		h.respondLookupError(ctx, err)
+		return
+	}
+	options, ok := result[dictType]
+	if !ok {
+		resp.NotFound(ctx, resp.WithMessage("dictionary not found"))
+		return
+	}
+	respondCacheable(ctx, localizeOptions(h.localizer(ctx), dictType, options))
+}
+
+// BatchOptions godoc
+// @Summary 批量获取字典选项
+// @Description 按逗号分隔的字典类型批量返回启用的字典数据，结果以类型为键，不存在的类型不返回；登录即可访问，支持 ETag/304
+// @Tags Dict
+// @Security BearerAuth
+// @Produce json
+// @Param types query string true "字典类型，逗号分隔，最多 50 个"
+// @Param Accept-Language header string false "界面语言，用户设置了语言偏好时以偏好为准；标签按该语言的译文返回"
+// @Param If-None-Match header string false "上次响应的 ETag"
+// @Success 200 {object} resp.Response
+// @Success 304 {object} nil
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/dicts/types [get]
+func (h *Handler) BatchOptions(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("dictionary service unavailable"))
+		return
+	}
+
+	var query lookupOptionsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	result, err := h.service.LookupOptions(ctx.Request.Context(), strings.Split(query.Types, ","))
+	if err != nil {
+		h.respondLookupError(ctx, err)
+		return
+	}
+	localizer := h.localizer(ctx)
+	for dictType, options := range result {
+		result[dictType] = localizeOptions(localizer, dictType, options)
+	}
+	respondCacheable(ctx, result)
+}
+
+// TreeOptions godoc
+// @Summary 获取树形字典选项
+// @Description 以树形返回字典类型下启用的字典数据，用于级联选择；上级停用时其下级一并隐藏。登录即可访问，支持 ETag/304
+// @Tags Dict
+// @Security BearerAuth
+// @Produce json
+// @Param type path string true "字典类型"
+// @Param Accept-Language header string false "界面语言，用户设置了语言偏好时以偏好为准；标签按该语言的译文返回"
+// @Param If-None-Match header string false "上次响应的 ETag"
+// @Success 200 {object} resp.Response
+// @Success 304 {object} nil
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/dicts/types/{type}/tree [get]
+func (h *Handler) TreeOptions(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("dictionary service unavailable"))
+		return
+	}
+
+	dictType := strings.TrimSpace(ctx.Param("type"))
+	nodes, ok, err := h.service.LookupTree(ctx.Request.Context(), dictType)
+	if err != nil {
+		h.respondLookupError(ctx, err)
+		return
+	}
+	if !ok {
+		resp.NotFound(ctx, resp.WithMessage("dictionary not found"))
+		return
+	}
+	respondCacheable(ctx, localizeTree(h.localizer(ctx), dictType, nodes))
+}
+
+// ChildOptions godoc
+// @Summary 获取下级字典选项
+// @Description 返回上级键值为 parent 的直接下级选项，parent 为空时返回顶级选项；用于按需加载的级联选择。登录即可访问，支持 ETag/304
+// @Tags Dict
+// @Security BearerAuth
+// @Produce json
+// @Param type path string true "字典类型"
+// @Param parent query string false "上级字典键值"
+// @Param Accept-Language header string false "界面语言，用户设置了语言偏好时以偏好为准；标签按该语言的译文返回"
+// @Param If-None-Match header string false "上次响应的 ETag"
+// @Success 200 {object} resp.Response
+// @Success 304 {object} nil
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/dicts/types/{type}/children [get]
+func (h *Handler) ChildOptions(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("dictionary service unavailable"))
+		return
+	}
+
+	var query childOptionsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	dictType := strings.TrimSpace(ctx.Param("type"))
+	options, ok, err := h.service.LookupChildren(ctx.Request.Context(), dictType, query.Parent)
+	if err != nil {
+		h.respondLookupError(ctx, err)
+		return
+	}
+	if !ok {
+		resp.NotFound(ctx, resp.WithMessage("dictionary not found"))
+		return
+	}
+	respondCacheable(ctx, localizeOptions(h.localizer(ctx), dictType, options))
+}
+
+func (h *Handler) respondLookupError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrDictTypesRequired), errors.Is(err, ErrTooManyDictTypes):
+		resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+	default:
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load dictionary options"))
+	}
+}
+
+// localizer 返回请求语言下的字典译文查询器；未启用多语言时返回 nil，选项保持原始标签
+func (h *Handler) localizer(ctx *gin.Context) *i18n.Localizer {
+	if h.translations == nil {
+		return nil
+	}
+	return h.translations.Localizer(ctx.Request.Context(), i18n.ResourceDict, h.translations.RequestLocale(ctx))
+}
+
+// localizeOptions 返回替换为译文标签的副本，缓存中的选项保持原始标签
+func localizeOptions(localizer *i18n.Localizer, dictType string, options []DictOption) []DictOption {
+	if localizer == nil {
+		return options
+	}
+	localized := make([]DictOption, len(options))
+	for i, option := range options {
+		option.DictLabel = localizer.Text(dictType, option.DictValue, option.DictLabel)
+		localized[i] = option
+	}
+	return localized
+}
+
+func localizeTree(localizer *i18n.Localizer, dictType string, nodes []DictTreeNode) []DictTreeNode {
+	if localizer == nil {
+		return nodes
+	}
+	localized := make([]DictTreeNode, len(nodes))
+	for i, node := range nodes {
+		node.DictLabel = localizer.Text(dictType, node.DictValue, node.DictLabel)
+		node.Children = localizeTree(localizer, dictType, node.Children)
+		localized[i] = node
+	}
+	return localized
+}
+
+// respondCacheable 以数据内容计算 ETag，客户端缓存仍有效时返回 304；
+// no-cache 允许浏览器缓存但每次使用前都需重新验证，标签随语言变化因此按 Accept-Language 区分
+func respondCacheable(ctx *gin.Context, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load dictionary options"))
+		return
+	}
+	tag := etag.Hash(payload)
+	ctx.Header(etag.HeaderETag, tag)
+	ctx.Header("Cache-Control", "private, no-cache")
+	ctx.Header("Vary", "Accept-Language")
+	if etag.NotModified(ctx, tag) {
+		resp.NotModified(ctx)
+		return
+	}
+	resp.OK(ctx, resp.WithData(json.RawMessage(payload)))
+}
@@ -0,0 +1,408 @@
+package dict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+var (
+	ErrImportEmpty           = errors.New("import file has no data rows")
+	ErrImportTooManyRows     = errors.New("import file has too many rows")
+	ErrImportMissingColumns  = errors.New("import file is missing required columns")
+	ErrImportValidationFails = errors.New("import rows failed validation")
+)
+
+const (
+	// MaxImportRows 足以容纳省市区三级行政区划
+	MaxImportRows   = 50000
+	importBatchSize = 500
+)
+
+type importColumn struct {
+	key      string
+	title    string
+	required bool
+	aliases  []string
+}
+
+// importColumns 表头同时接受中文标题与英文字段名，上级以上级的字典键值表示
+var importColumns = []importColumn{
+	{key: "label", title: "字典标签", required: true, aliases: []string{"dictlabel", "dict_label", "标签", "名称"}},
+	{key: "value", title: "字典键值", required: true, aliases: []string{"dictvalue", "dict_value", "键值", "值", "编码"}},
+	{key: "parent", title: "上级键值", aliases: []string{"parentvalue", "parent_value", "上级", "上级编码"}},
+	{key: "sort", title: "排序", aliases: []string{"dictsort", "dict_sort", "显示排序"}},
+	{key: "status", title: "状态", aliases: []string{"数据状态"}},
+	{key: "isDefault", title: "是否默认", aliases: []string{"isdefault", "is_default", "默认"}},
+	{key: "remark", title: "备注", aliases: []string{"memo"}},
+}
+
+var (
+	importStatusValues = map[string]string{
+		"0": "0", "正常": "0", "启用": "0", "enabled": "0", "normal": "0",
+		"1": "1", "停用": "1", "禁用": "1", "disabled": "1",
+	}
+	importDefaultFlags = map[string]string{
+		"y": "Y", "yes": "Y", "是": "Y",
+		"n": "N", "no": "N", "否": "N",
+	}
+)
+
+type ImportDictDataInput struct {
+	DictID   int64
+	Rows     [][]string
+	DryRun   bool
+	Operator string
+}
+
+type ImportRowError struct {
+	Row    int      `json:"row"`
+	Value  string   `json:"value"`
+	Errors []string `json:"errors"`
+}
+
+// ImportResult 大批量导入时只列出校验失败的行
+type ImportResult struct {
+	DryRun  bool             `json:"dryRun"`
+	Total   int              `json:"total"`
+	Valid   int              `json:"valid"`
+	Invalid int              `json:"invalid"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+type importItem struct {
+	row         int
+	parentValue string
+	record      *model.SysDictData
+	// existing 非空表示按字典键值更新已有数据
+	existing *model.SysDictData
+	errors   []string
+}
+
+// ImportDictData 从 CSV/XLSX 导入字典数据。已存在的键值更新标签、排序、上级、状态与备注，
+// 其余新增；上级可引用文件中任意行或已有数据。全部行校验通过后在同一事务内写入。
+func (s *Service) ImportDictData(ctx context.Context, input ImportDictDataInput) (*ImportResult, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	dictTypeRecord, err := s.repo.GetDictType(ctx, input.DictID)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, dataRows, err := mapImportHeader(input.Rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(dataRows) > MaxImportRows {
+		return nil, ErrImportTooManyRows
+	}
+
+	existing, err := s.repo.ListAllData(ctx, dictTypeRecord.DictType)
+	if err != nil {
+		return nil, err
+	}
+	existingByID := make(map[int64]*model.SysDictData, len(existing))
+	existingByValue := make(map[string]*model.SysDictData, len(existing))
+	for i := range existing {
+		existingByID[int64(existing[i].ID)] = &existing[i]
+		existingByValue[existing[i].DictValue] = &existing[i]
+	}
+
+	operator := sanitizeOperator(input.Operator)
+	items := make([]*importItem, 0, len(dataRows))
+	byValue := make(map[string]*importItem, len(dataRows))
+	for _, row := range dataRows {
+		values := row.values(columns)
+		if isBlankRow(values) {
+			continue
+		}
+		item := buildImportItem(row.number, values, dictTypeRecord.DictType, operator, existingByValue)
+		if value := item.record.DictValue; value != "" {
+			if previous, ok := byValue[value]; ok {
+				item.errors = append(item.errors, fmt.Sprintf("duplicate value in file (row %d)", previous.row))
+			} else {
+				byValue[value] = item
+			}
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return nil, ErrImportEmpty
+	}
+
+	// 合并已有数据与文件内容后的上级关系，用于校验上级、循环与同级标签
+	parentOf := make(map[string]string, len(existing)+len(items))
+	labelOf := make(map[string]string, len(existing)+len(items))
+	for i := range existing {
+		record := &existing[i]
+		if parent, ok := existingByID[record.ParentID]; ok {
+			parentOf[record.DictValue] = parent.DictValue
+		}
+		labelOf[record.DictValue] = record.DictLabel
+	}
+	for value, item := range byValue {
+		parentOf[value] = item.parentValue
+		labelOf[value] = item.record.DictLabel
+	}
+
+	siblings := make(map[string]string, len(labelOf))
+	for value, label := range labelOf {
+		if _, inFile := byValue[value]; !inFile {
+			siblings[parentOf[value]+"\x00"+label] = value
+		}
+	}
+	for _, item := range items {
+		value := item.record.DictValue
+		if byValue[value] != item {
+			continue
+		}
+		if parent := item.parentValue; parent != "" {
+			if _, ok := labelOf[parent]; !ok {
+				item.errors = append(item.errors, fmt.Sprintf("parent value %q not found", parent))
+			} else if hasImportCycle(value, parentOf) {
+				item.errors = append(item.errors, ErrDictDataCycle.Error())
+			}
+		}
+		key := item.parentValue + "\x00" + item.record.DictLabel
+		if other, ok := siblings[key]; ok && other != value {
+			item.errors = append(item.errors, fmt.Sprintf("%s: %q", ErrDuplicateDictLabel.Error(), item.record.DictLabel))
+		} else {
+			siblings[key] = value
+		}
+	}
+
+	result := &ImportResult{DryRun: input.DryRun, Total: len(items), Errors: []ImportRowError{}}
+	for _, item := range items {
+		if len(item.errors) > 0 {
+			result.Invalid++
+			result.Errors = append(result.Errors, ImportRowError{Row: item.row, Value: item.record.DictValue, Errors: item.errors})
+			continue
+		}
+		result.Valid++
+	}
+	if input.DryRun {
+		return result, nil
+	}
+	if result.Invalid > 0 {
+		return result, ErrImportValidationFails
+	}
+
+	var levels [][]*model.SysDictData
+	updates := make([]*model.SysDictData, 0)
+	for _, item := range items {
+		if item.existing != nil {
+			updates = append(updates, item.record)
+			continue
+		}
+		depth := importDepth(item.record.DictValue, parentOf, byValue)
+		for len(levels) <= depth {
+			levels = append(levels, nil)
+		}
+		levels[depth] = append(levels[depth], item.record)
+	}
+
+	resolveParent := func(record *model.SysDictData) {
+		record.ParentID = 0
+		parent := parentOf[record.DictValue]
+		if parent == "" {
+			return
+		}
+		if item, ok := byValue[parent]; ok && item.existing == nil {
+			record.ParentID = int64(item.record.ID)
+		} else if current, ok := existingByValue[parent]; ok {
+			record.ParentID = int64(current.ID)
+		}
+	}
+	if err := s.repo.ImportDictData(ctx, levels, updates, resolveParent); err != nil {
+		return result, err
+	}
+	s.InvalidateOptions(ctx, dictTypeRecord.DictType)
+
+	result.Updated = len(updates)
+	result.Created = len(items) - len(updates)
+	return result, nil
+}
+
+func buildImportItem(row int, values map[string]string, dictType, operator string, existing map[string]*model.SysDictData) *importItem {
+	item := &importItem{row: row, parentValue: strings.TrimSpace(values["parent"])}
+
+	label := strings.TrimSpace(values["label"])
+	if label == "" {
+		item.errors = append(item.errors, ErrDictLabelRequired.Error())
+	}
+	value := strings.TrimSpace(values["value"])
+	if value == "" {
+		item.errors = append(item.errors, ErrDictValueRequired.Error())
+	}
+	if value != "" && item.parentValue == value {
+		item.errors = append(item.errors, ErrDictDataCycle.Error())
+	}
+
+	record := &model.SysDictData{
+		DictLabel: label,
+		DictValue: value,
+		DictType:  dictType,
+		Status:    "0",
+		IsDefault: "N",
+		CreateBy:  operator,
+		UpdateBy:  operator,
+	}
+	if current, ok := existing[value]; ok {
+		item.existing = current
+		record.ID = current.ID
+		record.DictSort = current.DictSort
+		record.Status = current.Status
+		record.IsDefault = current.IsDefault
+		record.Remark = current.Remark
+	}
+
+	if raw := strings.TrimSpace(values["sort"]); raw != "" {
+		sort, err := strconv.Atoi(raw)
+		if err != nil || sort < 0 {
+			item.errors = append(item.errors, ErrInvalidDictDataSort.Error())
+		}
+		record.DictSort = sort
+	}
+	if raw := strings.TrimSpace(values["status"]); raw != "" {
+		status, ok := importStatusValues[strings.ToLower(raw)]
+		if !ok {
+			item.errors = append(item.errors, ErrInvalidDictDataStatus.Error())
+		}
+		record.Status = status
+	}
+	if raw := strings.TrimSpace(values["isDefault"]); raw != "" {
+		flag, ok := importDefaultFlags[strings.ToLower(raw)]
+		if !ok {
+			item.errors = append(item.errors, ErrInvalidDefaultFlag.Error())
+		}
+		record.IsDefault = flag
+	}
+	if raw, ok := values["remark"]; ok {
+		record.Remark = normalizeRemark(&raw)
+	}
+
+	item.record = record
+	return item
+}
+
+// hasImportCycle 自 value 沿上级链向上，回到 value 时视为循环
+func hasImportCycle(value string, parentOf map[string]string) bool {
+	visited := make(map[string]struct{})
+	for current := parentOf[value]; current != ""; current = parentOf[current] {
+		if current == value {
+			return true
+		}
+		if _, ok := visited[current]; ok {
+			return false
+		}
+		visited[current] = struct{}{}
+	}
+	return false
+}
+
+// importDepth 返回新增数据在新增数据中的层级，使上级总是先于下级写入
+func importDepth(value string, parentOf map[string]string, byValue map[string]*importItem) int {
+	depth := 0
+	for current := parentOf[value]; current != ""; current = parentOf[current] {
+		item, ok := byValue[current]
+		if !ok || item.existing != nil {
+			break
+		}
+		depth++
+	}
+	return depth
+}
+
+type importRow struct {
+	number int
+	cells  []string
+}
+
+func (r importRow) values(columns map[string]int) map[string]string {
+	values := make(map[string]string, len(columns))
+	for key, idx := range columns {
+		if idx < len(r.cells) {
+			values[key] = r.cells[idx]
+		}
+	}
+	return values
+}
+
+// mapImportHeader 找到首个非空行作为表头，返回列映射与其后的数据行
+func mapImportHeader(rows [][]string) (map[string]int, []importRow, error) {
+	headerIdx := -1
+	for i, row := range rows {
+		if !isBlankCells(row) {
+			headerIdx = i
+			break
+		}
+	}
+	if headerIdx < 0 {
+		return nil, nil, ErrImportEmpty
+	}
+
+	aliases := make(map[string]string)
+	for _, column := range importColumns {
+		for _, name := range append([]string{column.key, column.title}, column.aliases...) {
+			aliases[normalizeHeader(name)] = column.key
+		}
+	}
+
+	columns := make(map[string]int)
+	for idx, cell := range rows[headerIdx] {
+		if key, ok := aliases[normalizeHeader(cell)]; ok {
+			if _, exists := columns[key]; !exists {
+				columns[key] = idx
+			}
+		}
+	}
+
+	var missing []string
+	for _, column := range importColumns {
+		if _, ok := columns[column.key]; column.required && !ok {
+			missing = append(missing, column.title)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrImportMissingColumns, strings.Join(missing, ", "))
+	}
+
+	dataRows := make([]importRow, 0, len(rows)-headerIdx-1)
+	for i := headerIdx + 1; i < len(rows); i++ {
+		dataRows = append(dataRows, importRow{number: i + 1, cells: rows[i]})
+	}
+	return columns, dataRows, nil
+}
+
+func normalizeHeader(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.TrimSuffix(value, "*")
+	value = strings.ReplaceAll(value, " ", "")
+	return value
+}
+
+func isBlankRow(values map[string]string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func isBlankCells(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,228 @@
+package dict
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+var (
+	ErrDictTypesRequired = errors.New("at least one dictionary type is required")
+	ErrTooManyDictTypes  = errors.New("too many dictionary types requested")
+)
+
+const (
+	optionsCacheKeyPrefix = "dict:options:"
+	optionsCacheTTL       = 10 * time.Minute
+	// maxLookupTypes 限制单次批量查询的类型数量
+	maxLookupTypes = 50
+)
+
+// DictOption 前端下拉、标签等组件使用的字典选项，只包含启用的字典数据
+type DictOption struct {
+	DictLabel string  `json:"dictLabel"`
+	DictValue string  `json:"dictValue"`
+	DictSort  int     `json:"dictSort"`
+	IsDefault string  `json:"isDefault"`
+	ListClass *string `json:"listClass,omitempty"`
+	CSSClass  *string `json:"cssClass,omitempty"`
+	// ParentValue 上级字典数据的取值，顶级选项为空
+	ParentValue string `json:"parentValue,omitempty"`
+}
+
+// cachedOptions 同时缓存不存在的类型，避免反复查库
+type cachedOptions struct {
+	Found   bool         `json:"found"`
+	Options []DictOption `json:"options"`
+}
+
+// LookupOptions 按字典类型返回启用的字典选项，不存在的类型不出现在结果中。
+// 结果按类型缓存在 Redis 中，字典类型或数据变更时失效。
+func (s *Service) LookupOptions(ctx context.Context, dictTypes []string) (map[string][]DictOption, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	dictTypes = normalizeDictTypes(dictTypes)
+	if len(dictTypes) == 0 {
+		return nil, ErrDictTypesRequired
+	}
+	if len(dictTypes) > maxLookupTypes {
+		return nil, ErrTooManyDictTypes
+	}
+
+	result := make(map[string][]DictOption, len(dictTypes))
+	missing := dictTypes
+	if s.cache != nil {
+		missing = s.readCachedOptions(ctx, dictTypes, result)
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	loaded, err := s.loadOptions(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, dictType := range missing {
+		entry := loaded[dictType]
+		if entry.Found {
+			result[dictType] = entry.Options
+		}
+	}
+
+	if s.cache != nil {
+		pipe := s.cache.Pipeline()
+		for _, dictType := range missing {
+			if payload, err := json.Marshal(loaded[dictType]); err == nil {
+				pipe.Set(ctx, s.optionsCacheKey(ctx, dictType), payload, optionsCacheTTL)
+			}
+		}
+		// 缓存写入失败不影响本次结果
+		_, _ = pipe.Exec(ctx)
+	}
+	return result, nil
+}
+
+// readCachedOptions 将命中缓存的类型写入 result，返回未命中的类型；Redis 不可用时视为全部未命中
+func (s *Service) readCachedOptions(ctx context.Context, dictTypes []string, result map[string][]DictOption) []string {
+	keys := make([]string, len(dictTypes))
+	for i, dictType := range dictTypes {
+		keys[i] = s.optionsCacheKey(ctx, dictType)
+	}
+	values, err := s.cache.MGet(ctx, keys...).Result()
+	if err != nil {
+		return dictTypes
+	}
+
+	missing := make([]string, 0, len(dictTypes))
+	for i, value := range values {
+		raw, ok := value.(string)
+		var entry cachedOptions
+		if !ok || json.Unmarshal([]byte(raw), &entry) != nil {
+			missing = append(missing, dictTypes[i])
+			continue
+		}
+		if entry.Found {
+			result[dictTypes[i]] = entry.Options
+		}
+	}
+	return missing
+}
+
+func (s *Service) loadOptions(ctx context.Context, dictTypes []string) (map[string]cachedOptions, error) {
+	existing, err := s.repo.ListExistingTypes(ctx, dictTypes)
+	if err != nil {
+		return nil, err
+	}
+	loaded := make(map[string]cachedOptions, len(dictTypes))
+	if len(existing) == 0 {
+		return loaded, nil
+	}
+	for _, dictType := range existing {
+		loaded[dictType] = cachedOptions{Found: true, Options: []DictOption{}}
+	}
+
+	items, err := s.repo.ListEnabledData(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*model.SysDictData, len(items))
+	for i := range items {
+		byID[int64(items[i].ID)] = &items[i]
+	}
+	for i := range items {
+		item := &items[i]
+		entry, ok := loaded[item.DictType]
+		if !ok {
+			continue
+		}
+		parentValue, visible := resolveParentValue(item, byID)
+		if !visible {
+			continue
+		}
+		entry.Options = append(entry.Options, DictOption{
+			DictLabel:   item.DictLabel,
+			DictValue:   item.DictValue,
+			DictSort:    item.DictSort,
+			IsDefault:   item.IsDefault,
+			ListClass:   item.ListClass,
+			CSSClass:    item.CSSClass,
+			ParentValue: parentValue,
+		})
+		loaded[item.DictType] = entry
+	}
+	return loaded, nil
+}
+
+// resolveParentValue 返回上级取值；上级链路中任一节点停用或缺失时整棵子树不可见
+func resolveParentValue(item *model.SysDictData, enabled map[int64]*model.SysDictData) (string, bool) {
+	if item.ParentID == 0 {
+		return "", true
+	}
+	parent, ok := enabled[item.ParentID]
+	if !ok {
+		return "", false
+	}
+	visited := map[int64]struct{}{int64(item.ID): {}}
+	for current := parent; current.ParentID != 0; {
+		if _, ok := visited[int64(current.ID)]; ok {
+			return "", false
+		}
+		visited[int64(current.ID)] = struct{}{}
+		next, ok := enabled[current.ParentID]
+		if !ok {
+			return "", false
+		}
+		current = next
+	}
+	return parent.DictValue, true
+}
+
+// InvalidateOptions 清除字典类型的选项缓存；删除失败时缓存最迟在过期后与数据库一致。
+// 清单同步、回收站恢复等绕过本服务的写入同样需要调用
+func (s *Service) InvalidateOptions(ctx context.Context, dictTypes ...string) {
+	if s == nil || s.cache == nil {
+		return
+	}
+	dictTypes = normalizeDictTypes(dictTypes)
+	if len(dictTypes) == 0 {
+		return
+	}
+	keys := make([]string, len(dictTypes))
+	for i, dictType := range dictTypes {
+		keys[i] = s.optionsCacheKey(ctx, dictType)
+	}
+	_ = s.cache.Del(ctx, keys...).Err()
+}
+
+func (s *Service) optionsCacheKey(ctx context.Context, dictType string) string {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		tenantID = tenant.DefaultID
+	}
+	return optionsCacheKeyPrefix + strconv.FormatInt(tenantID, 10) + ":" + dictType
+}
+
+func normalizeDictTypes(dictTypes []string) []string {
+	seen := make(map[string]struct{}, len(dictTypes))
+	result := make([]string, 0, len(dictTypes))
+	for _, dictType := range dictTypes {
+		dictType = strings.TrimSpace(dictType)
+		if dictType == "" {
+			continue
+		}
+		if _, ok := seen[dictType]; ok {
+			continue
+		}
+		seen[dictType] = struct{}{}
+		result = append(result, dictType)
+	}
+	return result
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 )
 
 var (
@@ -63,6 +65,8 @@ type ListDataOptions struct {
 	Status    string
 	DictLabel string
 	DictValue string
+	// ParentID 非空时只返回该上级的直接下级，0 表示顶级数据
+	ParentID *int64
 }
 
 func (r *Repository) ListDictData(ctx context.Context, opts ListDataOptions) ([]model.SysDictData, error) {
@@ -84,6 +88,10 @@ func (r *Repository) ListDictData(ctx context.Context, opts ListDataOptions) ([]
 		query = query.Where("dict_value ILIKE ?", "%"+value+"%")
 	}
 
+	if opts.ParentID != nil {
+		query = query.Where("parent_id = ?", *opts.ParentID)
+	}
+
 	var items []model.SysDictData
 	if err := query.Order("dict_sort ASC, id ASC").Find(&items).Error; err != nil {
 		return nil, err
@@ -138,27 +146,38 @@ func (r *Repository) SaveDictType(ctx context.Context, dictType *model.SysDictTy
 	if dictType == nil {
 		return errors.New("dict type record is required")
 	}
-	return r.db.WithContext(ctx).Save(dictType).Error
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Model(dictType)).Select("*").Updates(dictType)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return etag.Missed(db, &model.SysDictType{}, dictType.ID)
+	}
+	return nil
 }
 
-func (r *Repository) DeleteDictType(ctx context.Context, id int64, dictType string) error {
+// DeleteDictType 软删除字典类型及其字典数据；两者使用相同的删除时间，
+// 便于回收站恢复字典类型时一并恢复随之删除的数据。
+func (r *Repository) DeleteDictType(ctx context.Context, id int64, dictType string, operator string, at time.Time) error {
 	if r == nil || r.db == nil {
 		return ErrRepositoryUnavailable
 	}
 
+	updates := map[string]interface{}{
+		"deleted_at": at,
+		"update_by":  operator,
+		"updated_at": at,
+	}
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where("dict_type = ?", dictType).Delete(&model.SysDictData{}).Error; err != nil {
-			return err
-		}
-
-		result := tx.Delete(&model.SysDictType{}, id)
+		result := etag.Guard(tx.Model(&model.SysDictType{}).Where("id = ?", id)).Updates(updates)
 		if result.Error != nil {
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return gorm.ErrRecordNotFound
+			return etag.Missed(tx, &model.SysDictType{}, id)
 		}
-		return nil
+		return tx.Model(&model.SysDictData{}).Where("dict_type = ?", dictType).Updates(updates).Error
 	})
 }
 
@@ -175,7 +194,8 @@ func (r *Repository) GetDictData(ctx context.Context, code int64) (*model.SysDic
 	return &record, nil
 }
 
-func (r *Repository) ExistsDictDataByLabel(ctx context.Context, dictType, label string, excludeCode int64) (bool, error) {
+// ExistsDictDataByLabel 校验同一上级下的标签是否重复，不同上级下允许同名（如各省的“市辖区”）
+func (r *Repository) ExistsDictDataByLabel(ctx context.Context, dictType string, parentID int64, label string, excludeCode int64) (bool, error) {
 	if r == nil || r.db == nil {
 		return false, ErrRepositoryUnavailable
 	}
@@ -183,7 +203,7 @@ func (r *Repository) ExistsDictDataByLabel(ctx context.Context, dictType, label
 	var count int64
 	query := r.db.WithContext(ctx).
 		Model(&model.SysDictData{}).
-		Where("dict_type = ? AND dict_label = ?", dictType, label)
+		Where("dict_type = ? AND parent_id = ? AND dict_label = ?", dictType, parentID, label)
 	if excludeCode > 0 {
 		query = query.Where("id <> ?", excludeCode)
 	}
@@ -228,20 +248,151 @@ func (r *Repository) SaveDictData(ctx context.Context, record *model.SysDictData
 	if record == nil {
 		return errors.New("dict data record is required")
 	}
-	return r.db.WithContext(ctx).Save(record).Error
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Model(record)).Select("*").Updates(record)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return etag.Missed(db, &model.SysDictData{}, record.ID)
+	}
+	return nil
 }
 
-func (r *Repository) DeleteDictData(ctx context.Context, code int64) error {
+// DeleteDictData 软删除字典数据及其下级，ids 首项为删除目标，其余为其下级；
+// 同一次删除使用相同的删除时间，便于回收站恢复上级时一并恢复随之删除的下级。
+func (r *Repository) DeleteDictData(ctx context.Context, ids []int64, operator string, at time.Time) error {
 	if r == nil || r.db == nil {
 		return ErrRepositoryUnavailable
 	}
+	if len(ids) == 0 {
+		return gorm.ErrRecordNotFound
+	}
 
-	result := r.db.WithContext(ctx).Delete(&model.SysDictData{}, code)
-	if result.Error != nil {
-		return result.Error
+	// 记录删除人，供回收站展示
+	updates := map[string]interface{}{
+		"deleted_at": at,
+		"update_by":  operator,
+		"updated_at": at,
 	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// If-Match 只针对删除目标本身
+		result := etag.Guard(tx.Model(&model.SysDictData{}).Where("id = ?", ids[0])).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return etag.Missed(tx, &model.SysDictData{}, ids[0])
+		}
+		if len(ids) == 1 {
+			return nil
+		}
+		return tx.Model(&model.SysDictData{}).Where("id IN ?", ids[1:]).Updates(updates).Error
+	})
+}
+
+// ListDescendantIDs 按层级逐层返回字典数据的全部下级
+func (r *Repository) ListDescendantIDs(ctx context.Context, id int64) ([]int64, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
 	}
-	return nil
+
+	result := make([]int64, 0)
+	seen := map[int64]struct{}{id: {}}
+	frontier := []int64{id}
+	for len(frontier) > 0 {
+		var children []int64
+		if err := r.db.WithContext(ctx).
+			Model(&model.SysDictData{}).
+			Where("parent_id IN ?", frontier).
+			Pluck("id", &children).Error; err != nil {
+			return nil, err
+		}
+		frontier = frontier[:0]
+		for _, child := range children {
+			// 历史数据中的循环不会导致死循环
+			if _, ok := seen[child]; ok {
+				continue
+			}
+			seen[child] = struct{}{}
+			result = append(result, child)
+			frontier = append(frontier, child)
+		}
+	}
+	return result, nil
+}
+
+// ListAllData 返回字典类型下的全部数据（含停用），用于导入时解析上级与去重
+func (r *Repository) ListAllData(ctx context.Context, dictType string) ([]model.SysDictData, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	var items []model.SysDictData
+	err := r.db.WithContext(ctx).
+		Where("dict_type = ?", dictType).
+		Order("dict_sort ASC, id ASC").
+		Find(&items).Error
+	return items, err
+}
+
+// ImportDictData 在同一事务内按层级顺序写入字典数据，create 中上级排在下级之前；
+// resolveParent 在写入每一层前根据已写入的记录回填上级 ID
+func (r *Repository) ImportDictData(ctx context.Context, levels [][]*model.SysDictData, updates []*model.SysDictData, resolveParent func(record *model.SysDictData)) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, level := range levels {
+			for _, record := range level {
+				resolveParent(record)
+			}
+			if len(level) == 0 {
+				continue
+			}
+			if err := tx.CreateInBatches(level, importBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		for _, record := range updates {
+			resolveParent(record)
+			if err := tx.Model(&model.SysDictData{}).Where("id = ?", record.ID).Updates(map[string]interface{}{
+				"dict_label": record.DictLabel,
+				"dict_sort":  record.DictSort,
+				"parent_id":  record.ParentID,
+				"status":     record.Status,
+				"remark":     record.Remark,
+				"update_by":  record.UpdateBy,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListExistingTypes 返回给定字典类型中未删除的类型
+func (r *Repository) ListExistingTypes(ctx context.Context, dictTypes []string) ([]string, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	var existing []string
+	err := r.db.WithContext(ctx).
+		Model(&model.SysDictType{}).
+		Where("dict_type IN ?", dictTypes).
+		Pluck("dict_type", &existing).Error
+	return existing, err
+}
+
+// ListEnabledData 按字典排序返回给定类型下启用的字典数据
+func (r *Repository) ListEnabledData(ctx context.Context, dictTypes []string) ([]model.SysDictData, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	var items []model.SysDictData
+	err := r.db.WithContext(ctx).
+		Where("dict_type IN ? AND status = ?", dictTypes, "0").
+		Order("dict_sort ASC, id ASC").
+		Find(&items).Error
+	return items, err
 }
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/history"
 )
 
 var (
@@ -38,14 +41,17 @@ var validDefaultFlags = map[string]struct{}{
 }
 
 type Service struct {
-	repo *Repository
+	repo    *Repository
+	history *history.Service
+	cache   *redis.Client
 }
 
-func NewService(repo *Repository) *Service {
+// NewService 创建字典服务；cache 可选，用于缓存按类型查询的字典选项
+func NewService(repo *Repository, history *history.Service, cache *redis.Client) *Service {
 	if repo == nil {
 		return nil
 	}
-	return &Service{repo: repo}
+	return &Service{repo: repo, history: history, cache: cache}
 }
 
 type QueryOptions struct {
@@ -55,24 +61,27 @@ type QueryOptions struct {
 }
 
 type DictType struct {
-	ID       int64   `json:"id"`
-	DictName string  `json:"dictName"`
-	DictType string  `json:"dictType"`
-	Status   string  `json:"status"`
-	Remark   *string `json:"remark,omitempty"`
+	ID        int64      `json:"id"`
+	DictName  string     `json:"dictName"`
+	DictType  string     `json:"dictType"`
+	Status    string     `json:"status"`
+	Remark    *string    `json:"remark,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
 }
 
 type DictData struct {
-	ID        int64   `json:"id"`
-	DictSort  int     `json:"dictSort"`
-	DictLabel string  `json:"dictLabel"`
-	DictValue string  `json:"dictValue"`
-	DictType  string  `json:"dictType"`
-	Status    string  `json:"status"`
-	IsDefault string  `json:"isDefault"`
-	ListClass *string `json:"listClass,omitempty"`
-	CSSClass  *string `json:"cssClass,omitempty"`
-	Remark    *string `json:"remark,omitempty"`
+	ID        int64      `json:"id"`
+	DictSort  int        `json:"dictSort"`
+	DictLabel string     `json:"dictLabel"`
+	DictValue string     `json:"dictValue"`
+	DictType  string     `json:"dictType"`
+	ParentID  int64      `json:"parentId"`
+	Status    string     `json:"status"`
+	IsDefault string     `json:"isDefault"`
+	ListClass *string    `json:"listClass,omitempty"`
+	CSSClass  *string    `json:"cssClass,omitempty"`
+	Remark    *string    `json:"remark,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
 }
 
 type DictDataList struct {
@@ -84,6 +93,7 @@ type DictDataQueryOptions struct {
 	Status    string
 	DictLabel string
 	DictValue string
+	ParentID  *int64
 }
 
 type CreateDictTypeInput struct {
@@ -108,6 +118,7 @@ type CreateDictDataInput struct {
 	DictLabel string
 	DictValue string
 	DictSort  int
+	ParentID  int64
 	Status    string
 	IsDefault string
 	Remark    *string
@@ -122,6 +133,7 @@ type UpdateDictDataInput struct {
 	DictLabel *string
 	DictValue *string
 	DictSort  *int
+	ParentID  *int64
 	Status    *string
 	IsDefault *string
 	Remark    *string
@@ -204,8 +216,18 @@ func (s *Service) CreateDictType(ctx context.Context, input CreateDictTypeInput)
 	if err := s.repo.CreateDictType(ctx, record); err != nil {
 		return nil, err
 	}
+	// 新增前该类型可能已作为不存在的类型被缓存
+	s.InvalidateOptions(ctx, record.DictType)
 
-	return dictTypeFromModel(record), nil
+	created := dictTypeFromModel(record)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleDict,
+		EntityID: created.ID,
+		Action:   history.ActionCreate,
+		Operator: operator,
+		After:    created,
+	})
+	return created, nil
 }
 
 func (s *Service) UpdateDictType(ctx context.Context, input UpdateDictTypeInput) (*DictType, error) {
@@ -217,6 +239,7 @@ func (s *Service) UpdateDictType(ctx context.Context, input UpdateDictTypeInput)
 	if err != nil {
 		return nil, err
 	}
+	before := dictTypeFromModel(record)
 
 	if input.DictName != nil {
 		name := strings.TrimSpace(*input.DictName)
@@ -257,11 +280,21 @@ func (s *Service) UpdateDictType(ctx context.Context, input UpdateDictTypeInput)
 	if err := s.repo.SaveDictType(ctx, record); err != nil {
 		return nil, err
 	}
+	s.InvalidateOptions(ctx, before.DictType, record.DictType)
 
-	return dictTypeFromModel(record), nil
+	updated := dictTypeFromModel(record)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleDict,
+		EntityID: updated.ID,
+		Action:   history.ActionUpdate,
+		Operator: operator,
+		Before:   before,
+		After:    updated,
+	})
+	return updated, nil
 }
 
-func (s *Service) DeleteDictType(ctx context.Context, id int64) error {
+func (s *Service) DeleteDictType(ctx context.Context, id int64, operator string) error {
 	if s == nil || s.repo == nil {
 		return ErrServiceUnavailable
 	}
@@ -271,7 +304,19 @@ func (s *Service) DeleteDictType(ctx context.Context, id int64) error {
 		return err
 	}
 
-	return s.repo.DeleteDictType(ctx, int64(record.ID), record.DictType)
+	operator = strings.TrimSpace(operator)
+	if err := s.repo.DeleteDictType(ctx, int64(record.ID), record.DictType, operator, time.Now()); err != nil {
+		return err
+	}
+	s.InvalidateOptions(ctx, record.DictType)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleDict,
+		EntityID: int64(record.ID),
+		Action:   history.ActionDelete,
+		Operator: operator,
+		Before:   dictTypeFromModel(record),
+	})
+	return nil
 }
 
 func (s *Service) ListDictData(ctx context.Context, dictID int64, opts DictDataQueryOptions) (*DictDataList, error) {
@@ -289,6 +334,7 @@ func (s *Service) ListDictData(ctx context.Context, dictID int64, opts DictDataQ
 		Status:    opts.Status,
 		DictLabel: opts.DictLabel,
 		DictValue: opts.DictValue,
+		ParentID:  opts.ParentID,
 	})
 	if err != nil {
 		return nil, err
@@ -339,7 +385,11 @@ func (s *Service) CreateDictData(ctx context.Context, input CreateDictDataInput)
 		return nil, ErrInvalidDefaultFlag
 	}
 
-	if exists, err := s.repo.ExistsDictDataByLabel(ctx, dictTypeRecord.DictType, label, 0); err != nil {
+	if err := s.validateParent(ctx, dictTypeRecord.DictType, 0, input.ParentID); err != nil {
+		return nil, err
+	}
+
+	if exists, err := s.repo.ExistsDictDataByLabel(ctx, dictTypeRecord.DictType, input.ParentID, label, 0); err != nil {
 		return nil, err
 	} else if exists {
 		return nil, ErrDuplicateDictLabel
@@ -361,6 +411,7 @@ func (s *Service) CreateDictData(ctx context.Context, input CreateDictDataInput)
 		DictLabel: label,
 		DictValue: value,
 		DictType:  dictTypeRecord.DictType,
+		ParentID:  input.ParentID,
 		Status:    status,
 		IsDefault: defaultFlag,
 		Remark:    remark,
@@ -373,6 +424,37 @@ func (s *Service) CreateDictData(ctx context.Context, input CreateDictDataInput)
 	if err := s.repo.CreateDictData(ctx, record); err != nil {
 		return nil, err
 	}
+	s.InvalidateOptions(ctx, record.DictType)
+
+	created := dictDataFromModel(record)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleDictData,
+		EntityID: created.ID,
+		Action:   history.ActionCreate,
+		Operator: operator,
+		After:    created,
+	})
+	return created, nil
+}
+
+func (s *Service) GetDictData(ctx context.Context, dictID, id int64) (*DictData, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	dictTypeRecord, err := s.repo.GetDictType(ctx, dictID)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.GetDictData(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.DictType != dictTypeRecord.DictType {
+		return nil, gorm.ErrRecordNotFound
+	}
 
 	return dictDataFromModel(record), nil
 }
@@ -395,14 +477,27 @@ func (s *Service) UpdateDictData(ctx context.Context, input UpdateDictDataInput)
 	if record.DictType != dictTypeRecord.DictType {
 		return nil, gorm.ErrRecordNotFound
 	}
+	before := dictDataFromModel(record)
+
+	parentChanged := false
+	if input.ParentID != nil && *input.ParentID != record.ParentID {
+		if err := s.validateParent(ctx, dictTypeRecord.DictType, int64(record.ID), *input.ParentID); err != nil {
+			return nil, err
+		}
+		record.ParentID = *input.ParentID
+		parentChanged = true
+	}
 
-	if input.DictLabel != nil {
-		label := strings.TrimSpace(*input.DictLabel)
+	if input.DictLabel != nil || parentChanged {
+		label := record.DictLabel
+		if input.DictLabel != nil {
+			label = strings.TrimSpace(*input.DictLabel)
+		}
 		if label == "" {
 			return nil, ErrDictLabelRequired
 		}
-		if !strings.EqualFold(label, record.DictLabel) {
-			if exists, err := s.repo.ExistsDictDataByLabel(ctx, dictTypeRecord.DictType, label, int64(record.ID)); err != nil {
+		if parentChanged || !strings.EqualFold(label, record.DictLabel) {
+			if exists, err := s.repo.ExistsDictDataByLabel(ctx, dictTypeRecord.DictType, record.ParentID, label, int64(record.ID)); err != nil {
 				return nil, err
 			} else if exists {
 				return nil, ErrDuplicateDictLabel
@@ -467,11 +562,22 @@ func (s *Service) UpdateDictData(ctx context.Context, input UpdateDictDataInput)
 	if err := s.repo.SaveDictData(ctx, record); err != nil {
 		return nil, err
 	}
+	s.InvalidateOptions(ctx, record.DictType)
 
-	return dictDataFromModel(record), nil
+	updated := dictDataFromModel(record)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleDictData,
+		EntityID: updated.ID,
+		Action:   history.ActionUpdate,
+		Operator: operator,
+		Before:   before,
+		After:    updated,
+	})
+	return updated, nil
 }
 
-func (s *Service) DeleteDictData(ctx context.Context, dictID, id int64) error {
+// DeleteDictData 删除字典数据；存在下级时需指定 cascade 才会连同全部下级一起删除
+func (s *Service) DeleteDictData(ctx context.Context, dictID, id int64, operator string, cascade bool) error {
 	if s == nil || s.repo == nil {
 		return ErrServiceUnavailable
 	}
@@ -490,7 +596,79 @@ func (s *Service) DeleteDictData(ctx context.Context, dictID, id int64) error {
 		return gorm.ErrRecordNotFound
 	}
 
-	return s.repo.DeleteDictData(ctx, id)
+	descendants, err := s.repo.ListDescendantIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(descendants) > 0 && !cascade {
+		return ErrDictDataHasChildren
+	}
+
+	operator = strings.TrimSpace(operator)
+	ids := append([]int64{id}, descendants...)
+	if err := s.repo.DeleteDictData(ctx, ids, operator, time.Now()); err != nil {
+		return err
+	}
+	s.InvalidateOptions(ctx, record.DictType)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleDictData,
+		EntityID: id,
+		Action:   history.ActionDelete,
+		Operator: operator,
+		Before:   dictDataFromModel(record),
+	})
+	return nil
+}
+
+// RecordExternalTypeChange 在字典类型被清单同步等流程直接写入后刷新选项缓存并记录变更历史；
+// 新增时 before 为空，删除时 after 为空
+func (s *Service) RecordExternalTypeChange(ctx context.Context, before, after *model.SysDictType, operator string) {
+	if s == nil || (before == nil && after == nil) {
+		return
+	}
+	entry := history.Entry{Module: history.ModuleDict, Action: history.ActionUpdate, Operator: operator}
+	var dictTypes []string
+	if before != nil {
+		entry.EntityID = int64(before.ID)
+		entry.Before = dictTypeFromModel(before)
+		dictTypes = append(dictTypes, before.DictType)
+	} else {
+		entry.Action = history.ActionCreate
+	}
+	if after != nil {
+		entry.EntityID = int64(after.ID)
+		entry.After = dictTypeFromModel(after)
+		dictTypes = append(dictTypes, after.DictType)
+	} else {
+		entry.Action = history.ActionDelete
+	}
+	s.InvalidateOptions(ctx, dictTypes...)
+	s.history.Record(ctx, entry)
+}
+
+// RecordExternalDataChange 与 RecordExternalTypeChange 相同，用于字典数据
+func (s *Service) RecordExternalDataChange(ctx context.Context, before, after *model.SysDictData, operator string) {
+	if s == nil || (before == nil && after == nil) {
+		return
+	}
+	entry := history.Entry{Module: history.ModuleDictData, Action: history.ActionUpdate, Operator: operator}
+	var dictTypes []string
+	if before != nil {
+		entry.EntityID = int64(before.ID)
+		entry.Before = dictDataFromModel(before)
+		dictTypes = append(dictTypes, before.DictType)
+	} else {
+		entry.Action = history.ActionCreate
+	}
+	if after != nil {
+		entry.EntityID = int64(after.ID)
+		entry.After = dictDataFromModel(after)
+		dictTypes = append(dictTypes, after.DictType)
+	} else {
+		entry.Action = history.ActionDelete
+	}
+	s.InvalidateOptions(ctx, dictTypes...)
+	s.history.Record(ctx, entry)
 }
 
 func normalizeStatus(status string) string {
@@ -540,11 +718,12 @@ func dictTypeFromModel(record *model.SysDictType) *DictType {
 		return nil
 	}
 	return &DictType{
-		ID:       int64(record.ID),
-		DictName: record.DictName,
-		DictType: record.DictType,
-		Status:   record.Status,
-		Remark:   record.Remark,
+		ID:        int64(record.ID),
+		DictName:  record.DictName,
+		DictType:  record.DictType,
+		Status:    record.Status,
+		Remark:    record.Remark,
+		UpdatedAt: &record.UpdatedAt,
 	}
 }
 
@@ -558,10 +737,12 @@ func dictDataFromModel(record *model.SysDictData) *DictData {
 		DictLabel: record.DictLabel,
 		DictValue: record.DictValue,
 		DictType:  record.DictType,
+		ParentID:  record.ParentID,
 		Status:    record.Status,
 		IsDefault: record.IsDefault,
 		ListClass: record.ListClass,
 		CSSClass:  record.CSSClass,
 		Remark:    record.Remark,
+		UpdatedAt: &record.UpdatedAt,
 	}
 }
@@ -0,0 +1,128 @@
+package dict
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidParentData   = errors.New("invalid parent dictionary data")
+	ErrDictDataCycle       = errors.New("parent dictionary data would create a cycle")
+	ErrDictDataHasChildren = errors.New("dictionary data has child items")
+)
+
+// DictTreeNode 级联选择组件使用的字典选项树
+type DictTreeNode struct {
+	DictOption
+	Children []DictTreeNode `json:"children,omitempty"`
+}
+
+// validateParent 校验上级字典数据属于同一字典类型且不会形成循环
+func (s *Service) validateParent(ctx context.Context, dictType string, id, parentID int64) error {
+	if parentID < 0 {
+		return ErrInvalidParentData
+	}
+	if parentID == 0 {
+		return nil
+	}
+	if parentID == id {
+		return ErrDictDataCycle
+	}
+
+	visited := make(map[int64]struct{})
+	for current := parentID; current > 0; {
+		if current == id {
+			return ErrDictDataCycle
+		}
+		if _, ok := visited[current]; ok {
+			// 历史数据已存在的循环，不再继续向上
+			break
+		}
+		visited[current] = struct{}{}
+
+		record, err := s.repo.GetDictData(ctx, current)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if current == parentID {
+					return ErrInvalidParentData
+				}
+				break
+			}
+			return err
+		}
+		if current == parentID && record.DictType != dictType {
+			return ErrInvalidParentData
+		}
+		current = record.ParentID
+	}
+	return nil
+}
+
+// LookupTree 以树形返回字典类型下启用的字典选项；ok 为 false 表示字典类型不存在
+func (s *Service) LookupTree(ctx context.Context, dictType string) ([]DictTreeNode, bool, error) {
+	options, ok, err := s.lookupType(ctx, dictType)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return buildOptionTree(options, ""), true, nil
+}
+
+// LookupChildren 返回上级取值为 parentValue 的直接下级选项，parentValue 为空时返回顶级选项
+func (s *Service) LookupChildren(ctx context.Context, dictType, parentValue string) ([]DictOption, bool, error) {
+	options, ok, err := s.lookupType(ctx, dictType)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	parentValue = strings.TrimSpace(parentValue)
+	children := make([]DictOption, 0)
+	for _, option := range options {
+		if option.ParentValue == parentValue {
+			children = append(children, option)
+		}
+	}
+	return children, true, nil
+}
+
+func (s *Service) lookupType(ctx context.Context, dictType string) ([]DictOption, bool, error) {
+	dictType = strings.TrimSpace(dictType)
+	result, err := s.LookupOptions(ctx, []string{dictType})
+	if err != nil {
+		return nil, false, err
+	}
+	options, ok := result[dictType]
+	return options, ok, nil
+}
+
+// buildOptionTree 选项已按排序号排列，同级顺序保持不变
+func buildOptionTree(options []DictOption, parentValue string) []DictTreeNode {
+	children := make(map[string][]DictOption, len(options))
+	for _, option := range options {
+		children[option.ParentValue] = append(children[option.ParentValue], option)
+	}
+
+	var build func(value string, depth int) []DictTreeNode
+	build = func(value string, depth int) []DictTreeNode {
+		items := children[value]
+		// 选项中的上级链路已校验可达，深度限制只用于防御异常数据
+		if len(items) == 0 || depth > len(options) {
+			return nil
+		}
+		nodes := make([]DictTreeNode, 0, len(items))
+		for _, item := range items {
+			nodes = append(nodes, DictTreeNode{
+				DictOption: item,
+				Children:   build(item.DictValue, depth+1),
+			})
+		}
+		return nodes
+	}
+
+	nodes := build(parentValue, 0)
+	if nodes == nil {
+		nodes = []DictTreeNode{}
+	}
+	return nodes
+}
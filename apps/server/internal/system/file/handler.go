@@ -0,0 +1,439 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/resp"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	if service == nil {
+		return nil
+	}
+	return &Handler{service: service}
+}
+
+type listFilesQuery struct {
+	PageNum      int    `form:"pageNum"`
+	PageSize     int    `form:"pageSize"`
+	OriginalName string `form:"originalName"`
+	Module       string `form:"module"`
+	RefID        string `form:"refId"`
+	OwnerID      int64  `form:"ownerId"`
+}
+
+type signedDownloadQuery struct {
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required"`
+}
+
+// List godoc
+// @Summary 获取文件列表
+// @Description 按文件名、所属模块、关联记录或上传人分页查询文件
+// @Tags System/File
+// @Security BearerAuth
+// @Produce json
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Param originalName query string false "文件名"
+// @Param module query string false "所属模块"
+// @Param refId query string false "关联记录ID"
+// @Param ownerId query int false "上传人ID"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/files [get]
+func (h *Handler) List(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("file service unavailable"))
+		return
+	}
+
+	var query listFilesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	result, err := h.service.ListFiles(ctx.Request.Context(), ListOptions{
+		PageNum:      query.PageNum,
+		PageSize:     query.PageSize,
+		OriginalName: query.OriginalName,
+		Module:       query.Module,
+		RefID:        query.RefID,
+		OwnerID:      query.OwnerID,
+	})
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load files"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(result))
+}
+
+// Get godoc
+// @Summary 获取文件信息
+// @Description 根据ID查询文件元数据
+// @Tags System/File
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "文件ID"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/files/{id} [get]
+func (h *Handler) Get(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("file service unavailable"))
+		return
+	}
+
+	id, err := parseFileID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid file id"))
+		return
+	}
+
+	item, err := h.service.GetFile(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.NotFound(ctx, resp.WithMessage("file not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load file"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(item))
+}
+
+// Upload godoc
+// @Summary 上传文件
+// @Description 上传单个文件，受大小与类型限制，可关联到业务模块记录；头像等公开模块不能通过该接口写入
+// @Tags System/File
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "文件"
+// @Param module formData string false "所属模块"
+// @Param refId formData string false "关联记录ID"
+// @Success 201 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 415 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/files [post]
+func (h *Handler) Upload(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("file service unavailable"))
+		return
+	}
+
+	item, ok := h.upload(ctx, ctx.PostForm("module"), ctx.PostForm("refId"))
+	if !ok {
+		return
+	}
+
+	resp.Created(ctx, resp.WithData(item))
+}
+
+func (h *Handler) upload(ctx *gin.Context, module, refID string) (*File, bool) {
+	maxSize := h.service.MaxSize()
+	// 预留 multipart 边界与其他字段的空间
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize+1<<20)
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("file exceeds %d bytes", maxSize)))
+			return nil, false
+		}
+		resp.BadRequest(ctx, resp.WithMessage("file is required"))
+		return nil, false
+	}
+	if fileHeader.Size > maxSize {
+		resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("file exceeds %d bytes", maxSize)))
+		return nil, false
+	}
+
+	src, err := fileHeader.Open()
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("failed to read file"))
+		return nil, false
+	}
+	defer src.Close()
+
+	var ownerID int64
+	if id, ok := middleware.GetUserID(ctx); ok {
+		ownerID = int64(id)
+	}
+
+	item, err := h.service.Upload(ctx.Request.Context(), UploadInput{
+		Filename: fileHeader.Filename,
+		Reader:   src,
+		Module:   module,
+		RefID:    refID,
+		OwnerID:  ownerID,
+		Operator: resolveOperator(ctx),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrFileRequired):
+			resp.BadRequest(ctx, resp.WithMessage("file is required"))
+		case errors.Is(err, ErrModuleReserved):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		case errors.Is(err, ErrFileTooLarge):
+			resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("file exceeds %d bytes", maxSize)))
+		case errors.Is(err, ErrFileTypeNotAllowed):
+			resp.Error(ctx, http.StatusUnsupportedMediaType, "file type is not allowed")
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to upload file"))
+		}
+		return nil, false
+	}
+	return item, true
+}
+
+// Download godoc
+// @Summary 下载文件
+// @Description 以附件形式下载文件内容
+// @Tags System/File
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param id path int true "文件ID"
+// @Success 200 {file} file
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/files/{id}/download [get]
+func (h *Handler) Download(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("file service unavailable"))
+		return
+	}
+
+	id, err := parseFileID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid file id"))
+		return
+	}
+
+	item, reader, err := h.service.Open(ctx.Request.Context(), id)
+	if err != nil {
+		h.writeOpenError(ctx, err)
+		return
+	}
+	defer reader.Close()
+
+	serveFile(ctx, item, reader)
+}
+
+// SignedDownload godoc
+// @Summary 通过签名链接下载文件
+// @Description 校验签名与有效期后下载文件，无需登录
+// @Tags System/File
+// @Produce octet-stream
+// @Param id path int true "文件ID"
+// @Param expires query int true "过期时间戳"
+// @Param signature query string true "签名"
+// @Success 200 {file} file
+// @Failure 400 {object} resp.Response
+// @Failure 403 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/files/{id} [get]
+func (h *Handler) SignedDownload(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("file service unavailable"))
+		return
+	}
+
+	id, err := parseFileID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid file id"))
+		return
+	}
+
+	var query signedDownloadQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	item, reader, err := h.service.OpenSigned(ctx.Request.Context(), id, query.Expires, query.Signature)
+	if err != nil {
+		h.writeOpenError(ctx, err)
+		return
+	}
+	defer reader.Close()
+
+	serveFile(ctx, item, reader)
+}
+
+// PublicDownload godoc
+// @Summary 访问公开文件
+// @Description 访问头像等公开模块的文件，无需登录，内容按文件 ID 长期缓存
+// @Tags System/File
+// @Produce octet-stream
+// @Param id path int true "文件ID"
+// @Success 200 {file} file
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/public/files/{id} [get]
+func (h *Handler) PublicDownload(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("file service unavailable"))
+		return
+	}
+
+	id, err := parseFileID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid file id"))
+		return
+	}
+
+	item, reader, err := h.service.OpenPublic(ctx.Request.Context(), id)
+	if err != nil {
+		h.writeOpenError(ctx, err)
+		return
+	}
+	defer reader.Close()
+
+	// 文件内容写入后不再变化，可交由浏览器与 CDN 长期缓存
+	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	ctx.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": item.OriginalName}))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.DataFromReader(http.StatusOK, item.Size, item.ContentType, reader, nil)
+}
+
+// Presign godoc
+// @Summary 生成文件下载链接
+// @Description 生成限时有效的文件下载地址
+// @Tags System/File
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "文件ID"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/files/{id}/presign [get]
+func (h *Handler) Presign(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("file service unavailable"))
+		return
+	}
+
+	id, err := parseFileID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid file id"))
+		return
+	}
+
+	link, err := h.service.PresignDownload(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.NotFound(ctx, resp.WithMessage("file not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to presign file"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(link))
+}
+
+// Delete godoc
+// @Summary 删除文件
+// @Description 删除文件元数据及存储内容
+// @Tags System/File
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "文件ID"
+// @Success 204 {object} nil
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/files/{id} [delete]
+func (h *Handler) Delete(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("file service unavailable"))
+		return
+	}
+
+	id, err := parseFileID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid file id"))
+		return
+	}
+
+	if err := h.service.Delete(ctx.Request.Context(), id, resolveOperator(ctx)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.NotFound(ctx, resp.WithMessage("file not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to delete file"))
+		return
+	}
+
+	resp.NoContent(ctx)
+}
+
+func (h *Handler) writeOpenError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidSignature):
+		resp.Forbidden(ctx, resp.WithMessage("invalid or expired download link"))
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrObjectMissing):
+		resp.NotFound(ctx, resp.WithMessage("file not found"))
+	default:
+		resp.InternalServerError(ctx, resp.WithMessage("failed to open file"))
+	}
+}
+
+func serveFile(ctx *gin.Context, item *File, reader io.Reader) {
+	contentType := item.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": item.OriginalName}))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.DataFromReader(http.StatusOK, item.Size, contentType, reader, nil)
+}
+
+func parseFileID(param string) (int64, error) {
+	id, err := strconv.ParseInt(strings.TrimSpace(param), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("invalid file id")
+	}
+	return id, nil
+}
+
+func resolveOperator(ctx *gin.Context) string {
+	id, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
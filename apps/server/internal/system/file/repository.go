@@ -0,0 +1,132 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+var (
+	ErrRepositoryUnavailable = errors.New("file repository is not initialized")
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	if db == nil {
+		return nil
+	}
+	return &Repository{db: db}
+}
+
+type ListOptions struct {
+	PageNum      int
+	PageSize     int
+	OriginalName string
+	Module       string
+	RefID        string
+	OwnerID      int64
+}
+
+func (r *Repository) ListFiles(ctx context.Context, opts ListOptions) ([]model.SysFile, int64, error) {
+	if r == nil || r.db == nil {
+		return nil, 0, ErrRepositoryUnavailable
+	}
+
+	pageNum := opts.PageNum
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	pageSize := opts.PageSize
+	if pageSize < 0 {
+		pageSize = 0
+	}
+
+	base := r.db.WithContext(ctx).Model(&model.SysFile{})
+
+	if name := strings.TrimSpace(opts.OriginalName); name != "" {
+		base = base.Where("original_name ILIKE ?", "%"+name+"%")
+	}
+	if module := strings.TrimSpace(opts.Module); module != "" {
+		base = base.Where("module = ?", module)
+	}
+	if refID := strings.TrimSpace(opts.RefID); refID != "" {
+		base = base.Where("ref_id = ?", refID)
+	}
+	if opts.OwnerID > 0 {
+		base = base.Where("owner_id = ?", opts.OwnerID)
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []model.SysFile{}, 0, nil
+	}
+
+	dataQuery := base.Session(&gorm.Session{})
+	if pageSize > 0 {
+		dataQuery = dataQuery.Offset((pageNum - 1) * pageSize).Limit(pageSize)
+	}
+
+	var files []model.SysFile
+	if err := dataQuery.Order("id DESC").Find(&files).Error; err != nil {
+		return nil, 0, err
+	}
+	return files, total, nil
+}
+
+func (r *Repository) GetFile(ctx context.Context, id int64) (*model.SysFile, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	if id <= 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var record model.SysFile
+	if err := r.db.WithContext(ctx).First(&record, id).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *Repository) CreateFile(ctx context.Context, record *model.SysFile) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if record == nil {
+		return errors.New("file record is required")
+	}
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+func (r *Repository) SoftDeleteFile(ctx context.Context, id int64, operator string, at time.Time) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&model.SysFile{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"deleted_at": at,
+			"update_by":  operator,
+			"updated_at": at,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
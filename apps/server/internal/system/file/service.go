@@ -0,0 +1,508 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/storage"
+)
+
+var (
+	ErrServiceUnavailable = errors.New("file service is not initialized")
+	ErrFileRequired       = errors.New("file is required")
+	ErrFileTooLarge       = errors.New("file exceeds size limit")
+	ErrFileTypeNotAllowed = errors.New("file type is not allowed")
+	ErrInvalidSignature   = errors.New("invalid or expired download signature")
+	ErrObjectMissing      = errors.New("file content is missing")
+	ErrModuleReserved     = errors.New("file module is reserved")
+)
+
+const (
+	defaultMaxSize    = 20 << 20
+	defaultPresignTTL = 15 * time.Minute
+	defaultModule     = "files"
+	sniffLength       = 512
+	maxNameLength     = 255
+	maxModuleLength   = 64
+)
+
+type Service struct {
+	repo   *Repository
+	driver storage.Driver
+	opts   ServiceOptions
+}
+
+type ServiceOptions struct {
+	// MaxSize 单个文件的最大字节数
+	MaxSize int64
+	// AllowedTypes 允许的 MIME 类型，支持 "image/*" 形式的通配
+	AllowedTypes []string
+	PresignTTL   time.Duration
+	// SigningKey 用于本地驱动签名下载地址
+	SigningKey string
+	// SignedURLPath 签名下载地址的路径前缀，文件 ID 会追加在其后
+	SignedURLPath string
+	// PublicModules 无需登录即可访问的模块，例如用户头像
+	PublicModules []string
+	// PublicURLPath 公开访问地址的路径前缀，文件 ID 会追加在其后
+	PublicURLPath string
+}
+
+func NewService(repo *Repository, driver storage.Driver, opts ServiceOptions) *Service {
+	if repo == nil || driver == nil {
+		return nil
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = defaultMaxSize
+	}
+	if opts.PresignTTL <= 0 {
+		opts.PresignTTL = defaultPresignTTL
+	}
+	opts.SignedURLPath = strings.TrimSuffix(opts.SignedURLPath, "/")
+	opts.PublicURLPath = strings.TrimSuffix(opts.PublicURLPath, "/")
+	return &Service{repo: repo, driver: driver, opts: opts}
+}
+
+type File struct {
+	FileID       int64     `json:"fileId"`
+	OriginalName string    `json:"originalName"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"contentType"`
+	Checksum     string    `json:"checksum"`
+	Driver       string    `json:"driver"`
+	OwnerID      int64     `json:"ownerId"`
+	Module       string    `json:"module"`
+	RefID        string    `json:"refId"`
+	URL          string    `json:"url,omitempty"`
+	CreateBy     string    `json:"createBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type ListResult struct {
+	List     []File `json:"list"`
+	Total    int64  `json:"total"`
+	PageNum  int    `json:"pageNum"`
+	PageSize int    `json:"pageSize"`
+}
+
+type UploadInput struct {
+	Filename string
+	Reader   io.Reader
+	Module   string
+	RefID    string
+	OwnerID  int64
+	Operator string
+	// Public 允许写入 PublicModules 中的公开模块，仅供头像等内置流程使用
+	Public bool
+}
+
+type PresignedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// MaxSize returns the configured upload size limit in bytes.
+func (s *Service) MaxSize() int64 {
+	if s == nil {
+		return 0
+	}
+	return s.opts.MaxSize
+}
+
+func (s *Service) ListFiles(ctx context.Context, opts ListOptions) (*ListResult, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	pageNum := opts.PageNum
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	pageSize := opts.PageSize
+	if pageSize < 0 {
+		pageSize = 0
+	}
+	opts.PageNum = pageNum
+	opts.PageSize = pageSize
+
+	records, total, err := s.repo.ListFiles(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]File, 0, len(records))
+	for i := range records {
+		items = append(items, s.toFile(&records[i]))
+	}
+	return &ListResult{List: items, Total: total, PageNum: pageNum, PageSize: pageSize}, nil
+}
+
+func (s *Service) GetFile(ctx context.Context, id int64) (*File, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	record, err := s.repo.GetFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	item := s.toFile(record)
+	return &item, nil
+}
+
+// Upload 校验大小与类型后写入存储驱动，并保存文件元数据
+func (s *Service) Upload(ctx context.Context, input UploadInput) (*File, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	if input.Reader == nil {
+		return nil, ErrFileRequired
+	}
+
+	name := sanitizeFilename(input.Filename)
+	if name == "" {
+		return nil, ErrFileRequired
+	}
+
+	buffered := bufio.NewReaderSize(input.Reader, sniffLength)
+	head, err := buffered.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+	if len(head) == 0 {
+		return nil, ErrFileRequired
+	}
+
+	contentType := resolveContentType(name, head)
+	if !s.typeAllowed(contentType) {
+		return nil, ErrFileTypeNotAllowed
+	}
+
+	module := sanitizeModule(input.Module)
+	// 公开模块的文件无需登录即可下载，不允许通过通用上传写入
+	if s.isPublicModule(module) && !input.Public {
+		return nil, ErrModuleReserved
+	}
+	key, err := generateKey(module, name)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher := sha256.New()
+	counter := &limitedCounter{r: io.TeeReader(buffered, hasher), limit: s.opts.MaxSize}
+	if err := s.driver.Put(ctx, key, counter, -1, contentType); err != nil {
+		if counter.exceeded {
+			return nil, ErrFileTooLarge
+		}
+		return nil, err
+	}
+	if counter.exceeded {
+		_ = s.driver.Delete(context.WithoutCancel(ctx), key)
+		return nil, ErrFileTooLarge
+	}
+
+	operator := strings.TrimSpace(input.Operator)
+	record := &model.SysFile{
+		OriginalName: name,
+		StorageKey:   key,
+		Driver:       s.driver.Name(),
+		Size:         counter.n,
+		ContentType:  contentType,
+		Checksum:     hex.EncodeToString(hasher.Sum(nil)),
+		OwnerID:      input.OwnerID,
+		Module:       module,
+		RefID:        truncate(strings.TrimSpace(input.RefID), maxModuleLength),
+		CreateBy:     operator,
+		UpdateBy:     operator,
+	}
+	if err := s.repo.CreateFile(ctx, record); err != nil {
+		// 元数据写入失败时清理已上传的对象
+		_ = s.driver.Delete(context.WithoutCancel(ctx), key)
+		return nil, err
+	}
+
+	item := s.toFile(record)
+	return &item, nil
+}
+
+// Open returns the file metadata and a reader for its content; callers must close the reader.
+func (s *Service) Open(ctx context.Context, id int64) (*File, io.ReadCloser, error) {
+	if s == nil || s.repo == nil {
+		return nil, nil, ErrServiceUnavailable
+	}
+	record, err := s.repo.GetFile(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader, err := s.driver.Open(ctx, record.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, nil, ErrObjectMissing
+		}
+		return nil, nil, err
+	}
+	item := s.toFile(record)
+	return &item, reader, nil
+}
+
+// OpenPublic opens a file that belongs to a public module; other files are reported as not found.
+func (s *Service) OpenPublic(ctx context.Context, id int64) (*File, io.ReadCloser, error) {
+	if s == nil || s.repo == nil {
+		return nil, nil, ErrServiceUnavailable
+	}
+	record, err := s.repo.GetFile(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.isPublicModule(record.Module) {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+	return s.Open(ctx, id)
+}
+
+// OpenSigned verifies a signed download link before opening the file.
+func (s *Service) OpenSigned(ctx context.Context, id, expires int64, signature string) (*File, io.ReadCloser, error) {
+	if s == nil || s.repo == nil {
+		return nil, nil, ErrServiceUnavailable
+	}
+	if expires < time.Now().Unix() || !hmac.Equal([]byte(s.sign(id, expires)), []byte(signature)) {
+		return nil, nil, ErrInvalidSignature
+	}
+	return s.Open(ctx, id)
+}
+
+// PresignDownload 生成限时下载地址：S3 使用对象存储的预签名，本地驱动使用服务端签名链接
+func (s *Service) PresignDownload(ctx context.Context, id int64) (*PresignedURL, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	record, err := s.repo.GetFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.opts.PresignTTL)
+	link, err := s.driver.PresignGet(ctx, record.StorageKey, s.opts.PresignTTL, record.OriginalName)
+	if err == nil {
+		return &PresignedURL{URL: link, ExpiresAt: expiresAt}, nil
+	}
+	if !errors.Is(err, storage.ErrPresignUnsupported) {
+		return nil, err
+	}
+
+	expires := expiresAt.Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(int64(record.ID), expires))
+	link = fmt.Sprintf("%s/%d?%s", s.opts.SignedURLPath, record.ID, query.Encode())
+	return &PresignedURL{URL: link, ExpiresAt: time.Unix(expires, 0)}, nil
+}
+
+// Delete 软删除元数据并移除存储对象
+func (s *Service) Delete(ctx context.Context, id int64, operator string) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+	record, err := s.repo.GetFile(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SoftDeleteFile(ctx, id, strings.TrimSpace(operator), time.Now()); err != nil {
+		return err
+	}
+	return s.driver.Delete(ctx, record.StorageKey)
+}
+
+// DeleteByRef 删除某条业务记录关联的文件，keep 中的文件会被保留
+func (s *Service) DeleteByRef(ctx context.Context, module, refID string, keep []int64, operator string) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+	refID = strings.TrimSpace(refID)
+	if refID == "" {
+		return nil
+	}
+
+	records, _, err := s.repo.ListFiles(ctx, ListOptions{Module: sanitizeModule(module), RefID: refID})
+	if err != nil {
+		return err
+	}
+
+	kept := make(map[int64]struct{}, len(keep))
+	for _, id := range keep {
+		kept[id] = struct{}{}
+	}
+
+	var errs []error
+	for i := range records {
+		id := int64(records[i].ID)
+		if _, ok := kept[id]; ok {
+			continue
+		}
+		if err := s.Delete(ctx, id, operator); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) isPublicModule(module string) bool {
+	for _, item := range s.opts.PublicModules {
+		if item == module {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) sign(id, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.opts.SigningKey))
+	fmt.Fprintf(mac, "file:%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Service) typeAllowed(contentType string) bool {
+	if len(s.opts.AllowedTypes) == 0 {
+		return true
+	}
+	for _, allowed := range s.opts.AllowedTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == "*" || allowed == "*/*" || allowed == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok && strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveContentType 以文件内容嗅探结果为准；扩展名给出的类型仅在与内容兼容时采用，
+// 防止把 HTML 等内容伪装成图片上传。
+func resolveContentType(name string, head []byte) string {
+	sniffed := baseMediaType(http.DetectContentType(head))
+	declared := baseMediaType(mime.TypeByExtension(strings.ToLower(filepath.Ext(name))))
+	if declared == "" || declared == sniffed {
+		return sniffed
+	}
+
+	switch {
+	case sniffed == "application/octet-stream":
+		// 内容无法识别时，仅信任非可执行的文档类扩展名
+		if !strings.HasPrefix(declared, "text/html") && !strings.Contains(declared, "javascript") && declared != "image/svg+xml" {
+			return declared
+		}
+	case sniffed == "application/zip" && strings.HasPrefix(declared, "application/vnd.openxmlformats-officedocument."):
+		return declared
+	case sniffed == "text/plain" && strings.HasPrefix(declared, "text/") && declared != "text/html":
+		return declared
+	}
+	return sniffed
+}
+
+func baseMediaType(value string) string {
+	if value == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+	return mediaType
+}
+
+func generateKey(module, name string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	if len(ext) > 16 {
+		ext = ""
+	}
+	return path.Join(module, time.Now().Format("2006/01/02"), hex.EncodeToString(random)+ext), nil
+}
+
+func sanitizeFilename(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return truncate(name, maxNameLength)
+}
+
+func sanitizeModule(module string) string {
+	module = strings.ToLower(strings.TrimSpace(module))
+	var builder strings.Builder
+	for _, ch := range module {
+		if (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || ch == '-' || ch == '_' {
+			builder.WriteRune(ch)
+		}
+	}
+	if builder.Len() == 0 {
+		return defaultModule
+	}
+	return truncate(builder.String(), maxModuleLength)
+}
+
+func truncate(value string, limit int) string {
+	if runes := []rune(value); len(runes) > limit {
+		return string(runes[:limit])
+	}
+	return value
+}
+
+func (s *Service) toFile(record *model.SysFile) File {
+	item := File{
+		FileID:       int64(record.ID),
+		OriginalName: record.OriginalName,
+		Size:         record.Size,
+		ContentType:  record.ContentType,
+		Checksum:     record.Checksum,
+		Driver:       record.Driver,
+		OwnerID:      record.OwnerID,
+		Module:       record.Module,
+		RefID:        record.RefID,
+		CreateBy:     record.CreateBy,
+		CreatedAt:    record.CreatedAt,
+	}
+	if s.isPublicModule(record.Module) && s.opts.PublicURLPath != "" {
+		item.URL = fmt.Sprintf("%s/%d", s.opts.PublicURLPath, record.ID)
+	}
+	return item
+}
+
+// limitedCounter counts bytes read and fails once the limit is exceeded.
+type limitedCounter struct {
+	r        io.Reader
+	limit    int64
+	n        int64
+	exceeded bool
+}
+
+func (c *limitedCounter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if c.n > c.limit {
+		c.exceeded = true
+		return n, ErrFileTooLarge
+	}
+	return n, err
+}
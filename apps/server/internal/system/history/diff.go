@@ -0,0 +1,156 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// FieldChange 单个字段的前后取值；列表字段只记录新增与移除的元素
+type FieldChange struct {
+	Field   string        `json:"field"`
+	Before  interface{}   `json:"before,omitempty"`
+	After   interface{}   `json:"after,omitempty"`
+	Added   []interface{} `json:"added,omitempty"`
+	Removed []interface{} `json:"removed,omitempty"`
+}
+
+// ignoredFields 审计字段与派生字段，不计入变更
+var ignoredFields = map[string]struct{}{
+	"createdAt":        {},
+	"updatedAt":        {},
+	"createBy":         {},
+	"updateBy":         {},
+	"inheritedMenuIds": {},
+	"effectiveRoles":   {},
+	"loginIp":          {},
+	"loginDate":        {},
+}
+
+// Diff 比较两个快照的 JSON 表示，返回按字段名排序的差异。任一侧为 nil 时视为空对象。
+func Diff(before, after interface{}) ([]FieldChange, error) {
+	left, err := snapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	right, err := snapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(left)+len(right))
+	for field := range left {
+		fields = append(fields, field)
+	}
+	for field := range right {
+		if _, ok := left[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := make([]FieldChange, 0)
+	for _, field := range fields {
+		oldValue, newValue := left[field], right[field]
+		if isEmpty(oldValue) && isEmpty(newValue) {
+			continue
+		}
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		oldList, oldIsList := asList(oldValue)
+		newList, newIsList := asList(newValue)
+		if oldIsList && newIsList {
+			added, removed := diffList(oldList, newList)
+			if len(added) == 0 && len(removed) == 0 {
+				// 仅顺序不同
+				continue
+			}
+			changes = append(changes, FieldChange{Field: field, Added: added, Removed: removed})
+			continue
+		}
+
+		changes = append(changes, FieldChange{Field: field, Before: oldValue, After: newValue})
+	}
+	return changes, nil
+}
+
+func snapshot(value interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	if value == nil {
+		return result, nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return result, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	for field := range ignoredFields {
+		delete(result, field)
+	}
+	return result, nil
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+func asList(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, true
+	case []interface{}:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+func diffList(before, after []interface{}) (added, removed []interface{}) {
+	oldKeys := make(map[string]struct{}, len(before))
+	for _, item := range before {
+		oldKeys[listKey(item)] = struct{}{}
+	}
+	newKeys := make(map[string]struct{}, len(after))
+	for _, item := range after {
+		key := listKey(item)
+		newKeys[key] = struct{}{}
+		if _, ok := oldKeys[key]; !ok {
+			added = append(added, item)
+		}
+	}
+	for _, item := range before {
+		if _, ok := newKeys[listKey(item)]; !ok {
+			removed = append(removed, item)
+		}
+	}
+	return added, removed
+}
+
+func listKey(item interface{}) string {
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Sprint(item)
+	}
+	return string(raw)
+}
@@ -0,0 +1,77 @@
+package history
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/starter-kit-fe/admin/pkg/resp"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	if service == nil {
+		return nil
+	}
+	return &Handler{service: service}
+}
+
+type listQuery struct {
+	PageNum  int `form:"pageNum"`
+	PageSize int `form:"pageSize"`
+}
+
+// List godoc
+// @Summary 获取变更历史
+// @Description 按时间倒序返回实体的字段级变更记录，包含操作人与请求ID
+// @Tags System/History
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "实体ID"
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/users/{id}/history [get]
+// @Router /v1/system/roles/{id}/history [get]
+// @Router /v1/system/departments/{id}/history [get]
+// @Router /v1/system/menus/{id}/history [get]
+// @Router /v1/system/configs/{id}/history [get]
+// @Router /v1/system/dicts/{id}/history [get]
+// @Router /v1/system/dicts/{id}/data/{itemId}/history [get]
+func (h *Handler) List(module, idParam string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if h == nil || h.service == nil {
+			resp.ServiceUnavailable(ctx, resp.WithMessage("history service unavailable"))
+			return
+		}
+
+		id, err := strconv.ParseInt(ctx.Param(idParam), 10, 64)
+		if err != nil || id <= 0 {
+			resp.BadRequest(ctx, resp.WithMessage("invalid id"))
+			return
+		}
+
+		var query listQuery
+		if err := ctx.ShouldBindQuery(&query); err != nil {
+			resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+			return
+		}
+
+		result, err := h.service.ListChanges(ctx.Request.Context(), module, id, QueryOptions{
+			PageNum:  query.PageNum,
+			PageSize: query.PageSize,
+		})
+		if err != nil {
+			resp.InternalServerError(ctx, resp.WithMessage("failed to load change history"))
+			return
+		}
+
+		resp.OK(ctx, resp.WithData(result))
+	}
+}
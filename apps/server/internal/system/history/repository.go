@@ -0,0 +1,92 @@
+package history
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+var ErrRepositoryUnavailable = errors.New("history repository is not initialized")
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	if db == nil {
+		return nil
+	}
+	return &Repository{db: db}
+}
+
+type ListOptions struct {
+	Module   string
+	EntityID int64
+	PageNum  int
+	PageSize int
+}
+
+func (r *Repository) CreateChangeLog(ctx context.Context, record *model.SysChangeLog) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+func (r *Repository) ListChangeLogs(ctx context.Context, opts ListOptions) ([]model.SysChangeLog, int64, error) {
+	if r == nil || r.db == nil {
+		return nil, 0, ErrRepositoryUnavailable
+	}
+
+	base := r.db.WithContext(ctx).
+		Model(&model.SysChangeLog{}).
+		Where("module = ? AND entity_id = ?", opts.Module, opts.EntityID)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []model.SysChangeLog{}, 0, nil
+	}
+
+	dataQuery := base.Session(&gorm.Session{})
+	if opts.PageSize > 0 {
+		dataQuery = dataQuery.Offset((opts.PageNum - 1) * opts.PageSize).Limit(opts.PageSize)
+	}
+
+	var records []model.SysChangeLog
+	if err := dataQuery.Order("id DESC").Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+func (r *Repository) GetChangeLog(ctx context.Context, module string, entityID, id int64) (*model.SysChangeLog, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	var record model.SysChangeLog
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND module = ? AND entity_id = ?", id, module, entityID).
+		First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *Repository) ListChangeLogsAfter(ctx context.Context, module string, entityID, id int64) ([]model.SysChangeLog, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	var records []model.SysChangeLog
+	err := r.db.WithContext(ctx).
+		Where("module = ? AND entity_id = ? AND id > ?", module, entityID, id).
+		Order("id DESC").
+		Find(&records).Error
+	return records, err
+}
@@ -0,0 +1,221 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/requestid"
+)
+
+var (
+	ErrServiceUnavailable = errors.New("history service is not initialized")
+	ErrRevisionNotFound   = errors.New("revision not found")
+)
+
+const (
+	ModuleUser     = "user"
+	ModuleRole     = "role"
+	ModuleDept     = "dept"
+	ModuleMenu     = "menu"
+	ModuleConfig   = "config"
+	ModuleDict     = "dict"
+	ModuleDictData = "dict_data"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	// ActionRollback 将实体恢复到某条变更记录之后的状态
+	ActionRollback = "rollback"
+)
+
+type Service struct {
+	repo   *Repository
+	logger *slog.Logger
+}
+
+func NewService(repo *Repository, logger *slog.Logger) *Service {
+	if repo == nil {
+		return nil
+	}
+	return &Service{repo: repo, logger: logger}
+}
+
+// Entry 描述一次实体变更。Before 与 After 为服务层返回的实体视图，新增时 Before 为空，删除时 After 为空
+type Entry struct {
+	Module   string
+	EntityID int64
+	Action   string
+	Operator string
+	Before   interface{}
+	After    interface{}
+	// RollbackTo 回滚时为恢复到的变更记录ID
+	RollbackTo int64
+}
+
+type QueryOptions struct {
+	PageNum  int
+	PageSize int
+}
+
+type ChangeLog struct {
+	ID         int64         `json:"id"`
+	Action     string        `json:"action"`
+	Changes    []FieldChange `json:"changes"`
+	Operator   string        `json:"operator"`
+	RequestID  string        `json:"requestId,omitempty"`
+	RollbackTo *int64        `json:"rollbackTo,omitempty"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+type ListResult struct {
+	List     []ChangeLog `json:"list"`
+	Total    int64       `json:"total"`
+	PageNum  int         `json:"pageNum"`
+	PageSize int         `json:"pageSize"`
+}
+
+// Record 计算字段差异并写入变更记录；没有字段变化的修改不记录。
+// 写入失败只记日志，不影响已经完成的业务操作。
+func (s *Service) Record(ctx context.Context, entry Entry) {
+	if s == nil || s.repo == nil || entry.EntityID <= 0 {
+		return
+	}
+
+	changes, err := Diff(entry.Before, entry.After)
+	if err != nil {
+		s.warn("diff change history failed", err, entry)
+		return
+	}
+	if len(changes) == 0 && entry.Action == ActionUpdate {
+		return
+	}
+	payload, err := json.Marshal(changes)
+	if err != nil {
+		s.warn("encode change history failed", err, entry)
+		return
+	}
+
+	record := &model.SysChangeLog{
+		Module:    entry.Module,
+		EntityID:  entry.EntityID,
+		Action:    entry.Action,
+		Changes:   string(payload),
+		Operator:  strings.TrimSpace(entry.Operator),
+		RequestID: requestid.FromContext(ctx),
+	}
+	if entry.RollbackTo > 0 {
+		record.RollbackTo = &entry.RollbackTo
+	}
+	if err := s.repo.CreateChangeLog(ctx, record); err != nil {
+		s.warn("persist change history failed", err, entry)
+	}
+}
+
+func (s *Service) ListChanges(ctx context.Context, module string, entityID int64, opts QueryOptions) (*ListResult, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	pageNum := opts.PageNum
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	records, total, err := s.repo.ListChangeLogs(ctx, ListOptions{
+		Module:   module,
+		EntityID: entityID,
+		PageNum:  pageNum,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]ChangeLog, 0, len(records))
+	for i := range records {
+		items = append(items, changeLogFromModel(&records[i]))
+	}
+
+	return &ListResult{
+		List:     items,
+		Total:    total,
+		PageNum:  pageNum,
+		PageSize: pageSize,
+	}, nil
+}
+
+// ChangesAfter 返回指定变更记录之后的全部变更，按时间倒序；记录不属于该实体时返回 ErrRevisionNotFound
+func (s *Service) ChangesAfter(ctx context.Context, module string, entityID, revisionID int64) ([]ChangeLog, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	if _, err := s.repo.GetChangeLog(ctx, module, entityID, revisionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+
+	records, err := s.repo.ListChangeLogsAfter(ctx, module, entityID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]ChangeLog, 0, len(records))
+	for i := range records {
+		items = append(items, changeLogFromModel(&records[i]))
+	}
+	return items, nil
+}
+
+// Revert 将快照中的字段依次还原为变更前的值，changes 须按时间倒序，用于重建历史状态
+func Revert(state map[string]interface{}, changes []ChangeLog) {
+	for _, log := range changes {
+		for _, change := range log.Changes {
+			if change.Before == nil {
+				delete(state, change.Field)
+				continue
+			}
+			state[change.Field] = change.Before
+		}
+	}
+}
+
+func (s *Service) warn(msg string, err error, entry Entry) {
+	if s.logger == nil {
+		return
+	}
+	s.logger.Warn(msg, "error", err, "module", entry.Module, "entity_id", entry.EntityID)
+}
+
+func changeLogFromModel(record *model.SysChangeLog) ChangeLog {
+	changes := make([]FieldChange, 0)
+	if record.Changes != "" {
+		_ = json.Unmarshal([]byte(record.Changes), &changes)
+	}
+	return ChangeLog{
+		ID:         int64(record.ID),
+		Action:     record.Action,
+		Changes:    changes,
+		Operator:   record.Operator,
+		RequestID:  record.RequestID,
+		RollbackTo: record.RollbackTo,
+		CreatedAt:  record.CreatedAt,
+	}
+}
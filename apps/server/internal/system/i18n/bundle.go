@@ -0,0 +1,202 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+var (
+	ErrBundleEmpty            = errors.New("translation bundle has no entries")
+	ErrBundleTooLarge         = errors.New("translation bundle has too many entries")
+	ErrBundleValidationFailed = errors.New("translation bundle failed validation")
+	ErrDuplicateEntry         = errors.New("duplicate translation entry")
+)
+
+// MaxBundleEntries 单个语言包允许的译文条数
+const MaxBundleEntries = 50000
+
+// Bundle 单一语言的译文包，字典译文按字典类型、字典键值组织，菜单译文以菜单ID为键
+type Bundle struct {
+	Locale string                       `json:"locale"`
+	Dicts  map[string]map[string]string `json:"dicts"`
+	Menus  map[string]string            `json:"menus"`
+}
+
+type BundleImportInput struct {
+	Bundle   *Bundle
+	DryRun   bool
+	Operator string
+}
+
+type BundleError struct {
+	Resource string `json:"resource"`
+	Scope    string `json:"scope,omitempty"`
+	ItemKey  string `json:"itemKey"`
+	Error    string `json:"error"`
+}
+
+type BundleImportResult struct {
+	DryRun  bool          `json:"dryRun"`
+	Locale  string        `json:"locale"`
+	Total   int           `json:"total"`
+	Valid   int           `json:"valid"`
+	Invalid int           `json:"invalid"`
+	Created int           `json:"created"`
+	Updated int           `json:"updated"`
+	Errors  []BundleError `json:"errors"`
+}
+
+// ExportBundle 导出指定语言的全部译文
+func (s *Service) ExportBundle(ctx context.Context, locale string) (*Bundle, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	locale, err := normalizeTargetLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.repo.ListTranslations(ctx, ListOptions{Locale: locale})
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &Bundle{
+		Locale: locale,
+		Dicts:  make(map[string]map[string]string),
+		Menus:  make(map[string]string),
+	}
+	for _, record := range records {
+		switch record.Resource {
+		case ResourceDict:
+			if bundle.Dicts[record.Scope] == nil {
+				bundle.Dicts[record.Scope] = make(map[string]string)
+			}
+			bundle.Dicts[record.Scope][record.ItemKey] = record.Text
+		case ResourceMenu:
+			bundle.Menus[record.ItemKey] = record.Text
+		}
+	}
+	return bundle, nil
+}
+
+// ImportBundle 校验语言包并在同一批次内新增或覆盖译文；任一条目无效时不写入，
+// 以 ErrBundleValidationFailed 返回逐条错误。dryRun 时只返回校验结果
+func (s *Service) ImportBundle(ctx context.Context, input BundleImportInput) (*BundleImportResult, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	if input.Bundle == nil {
+		return nil, ErrBundleEmpty
+	}
+
+	locale, err := normalizeTargetLocale(input.Bundle.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	type rawEntry struct {
+		resource, scope, key, text string
+	}
+	raw := make([]rawEntry, 0)
+	for _, dictType := range sortedKeys(input.Bundle.Dicts) {
+		values := input.Bundle.Dicts[dictType]
+		for _, value := range sortedKeys(values) {
+			raw = append(raw, rawEntry{ResourceDict, dictType, value, values[value]})
+		}
+	}
+	for _, id := range sortedKeys(input.Bundle.Menus) {
+		raw = append(raw, rawEntry{ResourceMenu, "", id, input.Bundle.Menus[id]})
+	}
+	if len(raw) == 0 {
+		return nil, ErrBundleEmpty
+	}
+	if len(raw) > MaxBundleEntries {
+		return nil, fmt.Errorf("%w: max %d", ErrBundleTooLarge, MaxBundleEntries)
+	}
+
+	result := &BundleImportResult{
+		DryRun: input.DryRun,
+		Locale: locale,
+		Total:  len(raw),
+		Errors: make([]BundleError, 0),
+	}
+	reject := func(item rawEntry, err error) {
+		result.Errors = append(result.Errors, BundleError{
+			Resource: item.resource,
+			Scope:    item.scope,
+			ItemKey:  item.key,
+			Error:    err.Error(),
+		})
+	}
+
+	entries := make([]normalizedEntry, 0, len(raw))
+	keys := make([]entryKey, 0, len(raw))
+	seen := make(map[entryKey]struct{}, len(raw))
+	for _, item := range raw {
+		entry, err := normalizeEntry(item.resource, item.scope, item.key, item.text)
+		if err != nil {
+			reject(item, err)
+			continue
+		}
+		// 首尾空白不同的键规范化后指向同一条目
+		if _, ok := seen[entry.entryKey]; ok {
+			reject(item, ErrDuplicateEntry)
+			continue
+		}
+		seen[entry.entryKey] = struct{}{}
+		entries = append(entries, entry)
+		keys = append(keys, entry.entryKey)
+	}
+
+	targets, err := s.resolveTargets(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.ListTranslations(ctx, ListOptions{Locale: locale})
+	if err != nil {
+		return nil, err
+	}
+	translated := make(map[string]struct{}, len(existing))
+	for _, record := range existing {
+		translated[sourceKey(record.Resource, record.Scope, record.ItemKey)] = struct{}{}
+	}
+
+	now := time.Now()
+	records := make([]*model.SysTranslation, 0, len(entries))
+	for _, entry := range entries {
+		key := sourceKey(entry.resource, entry.scope, entry.key)
+		if _, ok := targets[key]; !ok {
+			reject(rawEntry{entry.resource, entry.scope, entry.key, entry.text}, ErrTargetNotFound)
+			continue
+		}
+		if _, ok := translated[key]; ok {
+			result.Updated++
+		} else {
+			result.Created++
+		}
+		records = append(records, newTranslation(entry.resource, entry.scope, entry.key, locale, entry.text, input.Operator, now))
+	}
+	result.Valid = len(records)
+	result.Invalid = len(result.Errors)
+
+	if result.Invalid > 0 {
+		if input.DryRun {
+			return result, nil
+		}
+		return result, ErrBundleValidationFailed
+	}
+	if input.DryRun {
+		return result, nil
+	}
+
+	if err := s.repo.UpsertTranslations(ctx, records); err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, locale, ResourceDict, ResourceMenu)
+	return result, nil
+}
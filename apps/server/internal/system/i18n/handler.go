@@ -0,0 +1,331 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/resp"
+)
+
+// maxBundleSize 语言包请求体上限
+const maxBundleSize = 10 << 20
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	if service == nil {
+		return nil
+	}
+	return &Handler{service: service}
+}
+
+type listTranslationsQuery struct {
+	Resource string `form:"resource"`
+	Scope    string `form:"scope"`
+	Locale   string `form:"locale"`
+	Keyword  string `form:"keyword"`
+}
+
+type saveTranslationRequest struct {
+	Resource string `json:"resource" binding:"required"`
+	Scope    string `json:"scope"`
+	ItemKey  string `json:"itemKey" binding:"required"`
+	Locale   string `json:"locale" binding:"required"`
+	Text     string `json:"text" binding:"required"`
+}
+
+type importBundleQuery struct {
+	DryRun bool `form:"dryRun"`
+}
+
+// List godoc
+// @Summary 获取译文列表
+// @Description 按资源、字典类型、语言与关键字筛选译文，附带对应的原始名称
+// @Tags System/I18n
+// @Security BearerAuth
+// @Produce json
+// @Param resource query string false "资源：dict 或 menu"
+// @Param scope query string false "字典类型"
+// @Param locale query string false "语言"
+// @Param keyword query string false "键或译文关键字"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/translations [get]
+func (h *Handler) List(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("translation service unavailable"))
+		return
+	}
+
+	var query listTranslationsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+	if strings.TrimSpace(query.Locale) != "" {
+		locale, err := NormalizeLocale(query.Locale)
+		if err != nil {
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+			return
+		}
+		query.Locale = locale
+	}
+
+	items, err := h.service.ListTranslations(ctx.Request.Context(), ListOptions{
+		Resource: query.Resource,
+		Scope:    query.Scope,
+		Locale:   query.Locale,
+		Keyword:  query.Keyword,
+	})
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load translations"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(items))
+}
+
+// Save godoc
+// @Summary 保存译文
+// @Description 新增或覆盖字典标签、菜单名称在指定语言下的译文；字典译文 scope 为字典类型、itemKey 为字典键值，菜单译文 itemKey 为菜单ID
+// @Tags System/I18n
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body saveTranslationRequest true "译文"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/translations [put]
+func (h *Handler) Save(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("translation service unavailable"))
+		return
+	}
+
+	var payload saveTranslationRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid translation payload"))
+		return
+	}
+
+	item, err := h.service.SaveTranslation(ctx.Request.Context(), SaveTranslationInput{
+		Resource: payload.Resource,
+		Scope:    payload.Scope,
+		ItemKey:  payload.ItemKey,
+		Locale:   payload.Locale,
+		Text:     payload.Text,
+		Operator: resolveOperator(ctx),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTargetNotFound):
+			resp.NotFound(ctx, resp.WithMessage(err.Error()))
+		case errors.Is(err, ErrInvalidResource),
+			errors.Is(err, ErrInvalidLocale),
+			errors.Is(err, ErrDefaultLocale),
+			errors.Is(err, ErrKeyRequired),
+			errors.Is(err, ErrTextRequired),
+			errors.Is(err, ErrTextTooLong):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to save translation"))
+		}
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(item))
+}
+
+// Delete godoc
+// @Summary 删除译文
+// @Description 删除后该语言下恢复显示原始名称
+// @Tags System/I18n
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "译文ID"
+// @Success 204 {object} nil
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/translations/{id} [delete]
+func (h *Handler) Delete(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("translation service unavailable"))
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSpace(ctx.Param("id")), 10, 64)
+	if err != nil || id <= 0 {
+		resp.BadRequest(ctx, resp.WithMessage("invalid translation id"))
+		return
+	}
+
+	if err := h.service.DeleteTranslation(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.NotFound(ctx, resp.WithMessage("translation not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to delete translation"))
+		return
+	}
+
+	resp.NoContent(ctx)
+}
+
+// Export godoc
+// @Summary 导出语言包
+// @Description 以 JSON 文件导出指定语言的全部译文，可直接用于导入
+// @Tags System/I18n
+// @Security BearerAuth
+// @Produce json
+// @Param locale query string true "语言"
+// @Success 200 {object} Bundle
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/translations/export [get]
+func (h *Handler) Export(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("translation service unavailable"))
+		return
+	}
+
+	bundle, err := h.service.ExportBundle(ctx.Request.Context(), ctx.Query("locale"))
+	if err != nil {
+		if errors.Is(err, ErrInvalidLocale) || errors.Is(err, ErrDefaultLocale) {
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to export translations"))
+		return
+	}
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to encode translations"))
+		return
+	}
+
+	name := "translations_" + bundle.Locale + ".json"
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, name, url.PathEscape(name)))
+	ctx.Data(http.StatusOK, "application/json", data)
+}
+
+// Import godoc
+// @Summary 导入语言包
+// @Description 导入导出格式的 JSON 语言包，按条目新增或覆盖译文；任一条目无效时整体不写入并返回 422 与逐条错误，dryRun 时仅返回校验结果
+// @Tags System/I18n
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param dryRun query bool false "仅校验不写入"
+// @Param bundle body Bundle true "语言包"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 422 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/translations/import [post]
+func (h *Handler) Import(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("translation service unavailable"))
+		return
+	}
+
+	var query importBundleQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxBundleSize+1))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("failed to read translation bundle"))
+		return
+	}
+	if len(data) > maxBundleSize {
+		resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("translation bundle exceeds %d bytes", maxBundleSize)))
+		return
+	}
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid translation bundle"))
+		return
+	}
+
+	result, err := h.service.ImportBundle(ctx.Request.Context(), BundleImportInput{
+		Bundle:   &bundle,
+		DryRun:   query.DryRun,
+		Operator: resolveOperator(ctx),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrBundleValidationFailed):
+			resp.UnprocessableEntity(ctx, resp.WithMessage("translation bundle failed validation"), resp.WithData(result))
+		case errors.Is(err, ErrInvalidLocale),
+			errors.Is(err, ErrDefaultLocale),
+			errors.Is(err, ErrBundleEmpty),
+			errors.Is(err, ErrBundleTooLarge):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to import translations"))
+		}
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(result))
+}
+
+// Locales godoc
+// @Summary 获取可选界面语言
+// @Description 返回默认语言及已有译文的语言，并给出按用户偏好与 Accept-Language 协商出的当前语言；登录即可访问
+// @Tags I18n
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/i18n/locales [get]
+func (h *Handler) Locales(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("translation service unavailable"))
+		return
+	}
+
+	locales, err := h.service.Locales(ctx.Request.Context())
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load locales"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(gin.H{
+		"default":   DefaultLocale,
+		"current":   h.service.RequestLocale(ctx),
+		"available": locales,
+	}))
+}
+
+func resolveOperator(ctx *gin.Context) string {
+	id, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
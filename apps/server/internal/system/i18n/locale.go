@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+
+	"github.com/starter-kit-fe/admin/middleware"
+)
+
+func parseTag(locale string) (language.Tag, error) {
+	return language.Parse(locale)
+}
+
+// MatchLocale 依次按偏好在可选语言中协商，偏好可以是单个语言标识或 Accept-Language 头；
+// 均无法匹配时返回默认语言
+func (s *Service) MatchLocale(ctx context.Context, preferences ...string) string {
+	locales, err := s.Locales(ctx)
+	if err != nil || len(locales) <= 1 {
+		return DefaultLocale
+	}
+
+	supported := make([]language.Tag, 0, len(locales))
+	available := make([]string, 0, len(locales))
+	for _, locale := range locales {
+		tag, err := parseTag(locale)
+		if err != nil {
+			continue
+		}
+		supported = append(supported, tag)
+		available = append(available, locale)
+	}
+	matcher := language.NewMatcher(supported)
+
+	for _, preference := range preferences {
+		if preference == "" {
+			continue
+		}
+		desired, _, err := language.ParseAcceptLanguage(preference)
+		if err != nil || len(desired) == 0 {
+			continue
+		}
+		_, index, confidence := matcher.Match(desired...)
+		if confidence != language.No {
+			return available[index]
+		}
+	}
+	return DefaultLocale
+}
+
+// RequestLocale 解析请求使用的语言：登录用户设置的语言偏好优先，其次为 Accept-Language
+func (s *Service) RequestLocale(ctx *gin.Context) string {
+	if s == nil || s.repo == nil {
+		return DefaultLocale
+	}
+
+	var preferred string
+	if userID, ok := middleware.GetUserID(ctx); ok {
+		// 读取失败时退回 Accept-Language
+		preferred, _ = s.repo.GetUserLocale(ctx.Request.Context(), int64(userID))
+	}
+	return s.MatchLocale(ctx.Request.Context(), preferred, ctx.GetHeader("Accept-Language"))
+}
@@ -0,0 +1,235 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+var (
+	ErrRepositoryUnavailable = errors.New("translation repository is not initialized")
+	ErrInvalidTranslation    = errors.New("translation payload is invalid")
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	if db == nil {
+		return nil
+	}
+	return &Repository{db: db}
+}
+
+type ListOptions struct {
+	Resource string
+	Scope    string
+	Locale   string
+	Keyword  string
+}
+
+func (r *Repository) ListTranslations(ctx context.Context, opts ListOptions) ([]model.SysTranslation, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	query := r.db.WithContext(ctx).Model(&model.SysTranslation{})
+	if resource := strings.TrimSpace(opts.Resource); resource != "" {
+		query = query.Where("resource = ?", resource)
+	}
+	if scope := strings.TrimSpace(opts.Scope); scope != "" {
+		query = query.Where("scope = ?", scope)
+	}
+	if locale := strings.TrimSpace(opts.Locale); locale != "" {
+		query = query.Where("locale = ?", locale)
+	}
+	if keyword := strings.TrimSpace(opts.Keyword); keyword != "" {
+		like := "%" + keyword + "%"
+		query = query.Where("item_key LIKE ? OR text LIKE ?", like, like)
+	}
+
+	var records []model.SysTranslation
+	if err := query.Order("resource ASC, scope ASC, item_key ASC, locale ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *Repository) GetTranslation(ctx context.Context, id int64) (*model.SysTranslation, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	if id <= 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var record model.SysTranslation
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *Repository) FindTranslation(ctx context.Context, resource, scope, key, locale string) (*model.SysTranslation, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	var record model.SysTranslation
+	if err := r.db.WithContext(ctx).
+		Where("resource = ? AND scope = ? AND item_key = ? AND locale = ?", resource, scope, key, locale).
+		First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// ListLocales 返回已有译文的语言，按语言标识排序
+func (r *Repository) ListLocales(ctx context.Context) ([]string, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	var locales []string
+	if err := r.db.WithContext(ctx).
+		Model(&model.SysTranslation{}).
+		Distinct("locale").
+		Order("locale ASC").
+		Pluck("locale", &locales).Error; err != nil {
+		return nil, err
+	}
+	return locales, nil
+}
+
+// UpsertTranslations 按 (资源, 范围, 键, 语言) 新增或覆盖译文
+func (r *Repository) UpsertTranslations(ctx context.Context, records []*model.SysTranslation) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	for _, record := range records {
+		if record == nil {
+			return ErrInvalidTranslation
+		}
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "tenant_id"}, {Name: "resource"}, {Name: "scope"}, {Name: "item_key"}, {Name: "locale"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"text", "update_by", "updated_at"}),
+	}).CreateInBatches(records, 500).Error
+}
+
+// DeleteTranslation 物理删除译文，删除后恢复显示原始名称
+func (r *Repository) DeleteTranslation(ctx context.Context, id int64) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+
+	result := r.db.WithContext(ctx).Unscoped().Where("id = ?", id).Delete(&model.SysTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListDictKeys 返回各字典类型下已有的字典键值，用于校验导入的译文
+func (r *Repository) ListDictKeys(ctx context.Context, dictTypes []string) (map[string]map[string]string, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	result := make(map[string]map[string]string, len(dictTypes))
+	if len(dictTypes) == 0 {
+		return result, nil
+	}
+
+	var items []model.SysDictData
+	if err := r.db.WithContext(ctx).
+		Select("dict_type", "dict_value", "dict_label").
+		Where("dict_type IN ?", dictTypes).
+		Order("dict_type ASC, dict_sort ASC, id ASC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if result[item.DictType] == nil {
+			result[item.DictType] = make(map[string]string)
+		}
+		result[item.DictType][item.DictValue] = item.DictLabel
+	}
+	return result, nil
+}
+
+// ListMenuNames 返回菜单ID与名称，ids 为 nil 时返回全部菜单
+func (r *Repository) ListMenuNames(ctx context.Context, ids []int64) (map[int64]string, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	query := r.db.WithContext(ctx).Model(&model.SysMenu{}).Select("id", "menu_name")
+	if ids != nil {
+		if len(ids) == 0 {
+			return map[int64]string{}, nil
+		}
+		query = query.Where("id IN ?", ids)
+	}
+
+	var menus []model.SysMenu
+	if err := query.Find(&menus).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[int64]string, len(menus))
+	for _, menu := range menus {
+		result[int64(menu.ID)] = menu.MenuName
+	}
+	return result, nil
+}
+
+// GetUserLocale 返回用户的界面语言偏好
+func (r *Repository) GetUserLocale(ctx context.Context, userID int64) (string, error) {
+	if r == nil || r.db == nil {
+		return "", ErrRepositoryUnavailable
+	}
+
+	var locales []string
+	if err := r.db.WithContext(ctx).
+		Model(&model.SysUser{}).
+		Where("id = ?", userID).
+		Limit(1).
+		Pluck("locale", &locales).Error; err != nil {
+		return "", err
+	}
+	if len(locales) == 0 {
+		return "", nil
+	}
+	return locales[0], nil
+}
+
+func newTranslation(resource, scope, key, locale, text, operator string, at time.Time) *model.SysTranslation {
+	record := &model.SysTranslation{
+		Resource: resource,
+		Scope:    scope,
+		ItemKey:  key,
+		Locale:   locale,
+		Text:     text,
+		CreateBy: operator,
+		UpdateBy: operator,
+	}
+	record.CreatedAt = at
+	record.UpdatedAt = at
+	return record
+}
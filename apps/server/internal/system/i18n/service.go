@@ -0,0 +1,433 @@
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+var (
+	ErrServiceUnavailable = errors.New("translation service is not initialized")
+	ErrInvalidResource    = errors.New("translation resource must be dict or menu")
+	ErrInvalidLocale      = errors.New("invalid locale")
+	ErrDefaultLocale      = errors.New("default locale uses the original labels and cannot be translated")
+	ErrKeyRequired        = errors.New("translation key is required")
+	ErrTextRequired       = errors.New("translation text is required")
+	ErrTextTooLong        = errors.New("translation text exceeds 255 characters")
+	ErrTargetNotFound     = errors.New("translated dictionary data or menu not found")
+)
+
+const (
+	ResourceDict = "dict"
+	ResourceMenu = "menu"
+
+	// DefaultLocale 字典标签、菜单名称原文所用的语言
+	DefaultLocale = "zh-CN"
+
+	// maxTextLength 与 sys_translation.text 的列宽一致
+	maxTextLength = 255
+	maxKeyLength  = 100
+
+	cacheKeyPrefix = "i18n:"
+	cacheTTL       = 10 * time.Minute
+)
+
+type Service struct {
+	repo  *Repository
+	cache *redis.Client
+}
+
+// NewService 创建多语言服务；cache 可选，用于缓存各语言的译文
+func NewService(repo *Repository, cache *redis.Client) *Service {
+	if repo == nil {
+		return nil
+	}
+	return &Service{repo: repo, cache: cache}
+}
+
+type Translation struct {
+	ID       int64  `json:"id"`
+	Resource string `json:"resource"`
+	Scope    string `json:"scope"`
+	ItemKey  string `json:"itemKey"`
+	Locale   string `json:"locale"`
+	Text     string `json:"text"`
+	// Source 译文对应的原始名称，原数据已删除时为空
+	Source    string     `json:"source"`
+	UpdateBy  string     `json:"updateBy"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+type SaveTranslationInput struct {
+	Resource string
+	Scope    string
+	ItemKey  string
+	Locale   string
+	Text     string
+	Operator string
+}
+
+// NormalizeLocale 校验并规范化 BCP 47 语言标识，例如 en-us 规范为 en-US
+func NormalizeLocale(locale string) (string, error) {
+	locale = strings.TrimSpace(locale)
+	if locale == "" {
+		return "", ErrInvalidLocale
+	}
+	tag, err := parseTag(locale)
+	if err != nil {
+		return "", ErrInvalidLocale
+	}
+	normalized := tag.String()
+	if len(normalized) > 16 {
+		return "", ErrInvalidLocale
+	}
+	return normalized, nil
+}
+
+func (s *Service) ListTranslations(ctx context.Context, opts ListOptions) ([]Translation, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	records, err := s.repo.ListTranslations(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := s.loadSources(ctx, records)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Translation, 0, len(records))
+	for i := range records {
+		item := translationFromModel(&records[i])
+		item.Source = sources[sourceKey(item.Resource, item.Scope, item.ItemKey)]
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// SaveTranslation 新增或覆盖同一名称在指定语言下的译文
+func (s *Service) SaveTranslation(ctx context.Context, input SaveTranslationInput) (*Translation, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	entry, err := normalizeEntry(input.Resource, input.Scope, input.ItemKey, input.Text)
+	if err != nil {
+		return nil, err
+	}
+	locale, err := normalizeTargetLocale(input.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := s.resolveTargets(ctx, []entryKey{entry.entryKey})
+	if err != nil {
+		return nil, err
+	}
+	source, ok := targets[sourceKey(entry.resource, entry.scope, entry.key)]
+	if !ok {
+		return nil, ErrTargetNotFound
+	}
+
+	operator := strings.TrimSpace(input.Operator)
+	record := newTranslation(entry.resource, entry.scope, entry.key, locale, entry.text, operator, time.Now())
+	if err := s.repo.UpsertTranslations(ctx, []*model.SysTranslation{record}); err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, locale, entry.resource)
+
+	saved, err := s.repo.FindTranslation(ctx, entry.resource, entry.scope, entry.key, locale)
+	if err != nil {
+		return nil, err
+	}
+	result := translationFromModel(saved)
+	result.Source = source
+	return &result, nil
+}
+
+func (s *Service) DeleteTranslation(ctx context.Context, id int64) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+
+	record, err := s.repo.GetTranslation(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTranslation(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate(ctx, record.Locale, record.Resource)
+	return nil
+}
+
+// Locales 返回可选的界面语言，默认语言始终排在首位
+func (s *Service) Locales(ctx context.Context) ([]string, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	key := s.cacheKey(ctx, "locales")
+	var locales []string
+	if s.readCache(ctx, key, &locales) {
+		return locales, nil
+	}
+
+	stored, err := s.repo.ListLocales(ctx)
+	if err != nil {
+		return nil, err
+	}
+	locales = make([]string, 0, len(stored)+1)
+	locales = append(locales, DefaultLocale)
+	for _, locale := range stored {
+		if locale != DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	s.writeCache(ctx, key, locales)
+	return locales, nil
+}
+
+// Texts 返回资源在指定语言下的全部译文，按范围与键索引
+func (s *Service) Texts(ctx context.Context, resource, locale string) (map[string]map[string]string, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	if locale == "" || locale == DefaultLocale {
+		return map[string]map[string]string{}, nil
+	}
+
+	key := s.cacheKey(ctx, "texts:"+resource+":"+locale)
+	var texts map[string]map[string]string
+	if s.readCache(ctx, key, &texts) {
+		return texts, nil
+	}
+
+	records, err := s.repo.ListTranslations(ctx, ListOptions{Resource: resource, Locale: locale})
+	if err != nil {
+		return nil, err
+	}
+	texts = make(map[string]map[string]string)
+	for _, record := range records {
+		if texts[record.Scope] == nil {
+			texts[record.Scope] = make(map[string]string)
+		}
+		texts[record.Scope][record.ItemKey] = record.Text
+	}
+	s.writeCache(ctx, key, texts)
+	return texts, nil
+}
+
+// Localizer 返回资源在指定语言下的译文查询器；读取失败时返回空查询器，调用方继续使用原始名称
+func (s *Service) Localizer(ctx context.Context, resource, locale string) *Localizer {
+	texts, err := s.Texts(ctx, resource, locale)
+	if err != nil || len(texts) == 0 {
+		return nil
+	}
+	return &Localizer{texts: texts}
+}
+
+// Localizer 按范围与键查找译文，未翻译时返回原始名称；nil 查询器始终返回原始名称
+type Localizer struct {
+	texts map[string]map[string]string
+}
+
+func (l *Localizer) Text(scope, key, fallback string) string {
+	if l == nil {
+		return fallback
+	}
+	if text, ok := l.texts[scope][key]; ok && text != "" {
+		return text
+	}
+	return fallback
+}
+
+// MenuKey 菜单译文使用菜单ID作为键
+func MenuKey(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+type entryKey struct {
+	resource string
+	scope    string
+	key      string
+}
+
+type normalizedEntry struct {
+	entryKey
+	text string
+}
+
+func normalizeEntry(resource, scope, key, text string) (normalizedEntry, error) {
+	resource = strings.ToLower(strings.TrimSpace(resource))
+	scope = strings.TrimSpace(scope)
+	key = strings.TrimSpace(key)
+	text = strings.TrimSpace(text)
+
+	switch resource {
+	case ResourceDict:
+		if scope == "" {
+			return normalizedEntry{}, ErrKeyRequired
+		}
+	case ResourceMenu:
+		// 菜单不区分范围
+		scope = ""
+		if id, err := strconv.ParseInt(key, 10, 64); err != nil || id <= 0 {
+			return normalizedEntry{}, ErrTargetNotFound
+		}
+	default:
+		return normalizedEntry{}, ErrInvalidResource
+	}
+	if key == "" {
+		return normalizedEntry{}, ErrKeyRequired
+	}
+	if utf8.RuneCountInString(scope) > maxKeyLength || utf8.RuneCountInString(key) > maxKeyLength {
+		return normalizedEntry{}, ErrTargetNotFound
+	}
+	if text == "" {
+		return normalizedEntry{}, ErrTextRequired
+	}
+	if utf8.RuneCountInString(text) > maxTextLength {
+		return normalizedEntry{}, ErrTextTooLong
+	}
+	return normalizedEntry{entryKey: entryKey{resource: resource, scope: scope, key: key}, text: text}, nil
+}
+
+// normalizeTargetLocale 默认语言直接使用原始名称，不保存译文
+func normalizeTargetLocale(locale string) (string, error) {
+	normalized, err := NormalizeLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	if normalized == DefaultLocale {
+		return "", ErrDefaultLocale
+	}
+	return normalized, nil
+}
+
+// resolveTargets 返回仍存在的字典数据与菜单的原始名称，以 sourceKey 索引
+func (s *Service) resolveTargets(ctx context.Context, keys []entryKey) (map[string]string, error) {
+	dictTypes := make([]string, 0)
+	menuIDs := make([]int64, 0)
+	seenTypes := make(map[string]struct{})
+	for _, key := range keys {
+		switch key.resource {
+		case ResourceDict:
+			if _, ok := seenTypes[key.scope]; !ok {
+				seenTypes[key.scope] = struct{}{}
+				dictTypes = append(dictTypes, key.scope)
+			}
+		case ResourceMenu:
+			if id, err := strconv.ParseInt(key.key, 10, 64); err == nil {
+				menuIDs = append(menuIDs, id)
+			}
+		}
+	}
+
+	targets := make(map[string]string)
+	dictKeys, err := s.repo.ListDictKeys(ctx, dictTypes)
+	if err != nil {
+		return nil, err
+	}
+	for dictType, values := range dictKeys {
+		for value, label := range values {
+			targets[sourceKey(ResourceDict, dictType, value)] = label
+		}
+	}
+	menus, err := s.repo.ListMenuNames(ctx, menuIDs)
+	if err != nil {
+		return nil, err
+	}
+	for id, name := range menus {
+		targets[sourceKey(ResourceMenu, "", MenuKey(id))] = name
+	}
+	return targets, nil
+}
+
+func (s *Service) loadSources(ctx context.Context, records []model.SysTranslation) (map[string]string, error) {
+	keys := make([]entryKey, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, entryKey{resource: record.Resource, scope: record.Scope, key: record.ItemKey})
+	}
+	return s.resolveTargets(ctx, keys)
+}
+
+func sourceKey(resource, scope, key string) string {
+	return resource + "\x00" + scope + "\x00" + key
+}
+
+func translationFromModel(record *model.SysTranslation) Translation {
+	updatedAt := record.UpdatedAt
+	return Translation{
+		ID:        int64(record.ID),
+		Resource:  record.Resource,
+		Scope:     record.Scope,
+		ItemKey:   record.ItemKey,
+		Locale:    record.Locale,
+		Text:      record.Text,
+		UpdateBy:  record.UpdateBy,
+		UpdatedAt: &updatedAt,
+	}
+}
+
+// invalidate 清除语言列表与指定资源的译文缓存；删除失败时缓存最迟在过期后与数据库一致
+func (s *Service) invalidate(ctx context.Context, locale string, resources ...string) {
+	if s.cache == nil {
+		return
+	}
+	keys := []string{s.cacheKey(ctx, "locales")}
+	for _, resource := range resources {
+		keys = append(keys, s.cacheKey(ctx, "texts:"+resource+":"+locale))
+	}
+	_ = s.cache.Del(ctx, keys...).Err()
+}
+
+func (s *Service) readCache(ctx context.Context, key string, dest interface{}) bool {
+	if s.cache == nil {
+		return false
+	}
+	raw, err := s.cache.Get(ctx, key).Bytes()
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(raw, dest) == nil
+}
+
+// writeCache 缓存写入失败不影响本次结果
+func (s *Service) writeCache(ctx context.Context, key string, value interface{}) {
+	if s.cache == nil {
+		return
+	}
+	if payload, err := json.Marshal(value); err == nil {
+		_ = s.cache.Set(ctx, key, payload, cacheTTL).Err()
+	}
+}
+
+func (s *Service) cacheKey(ctx context.Context, suffix string) string {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		tenantID = tenant.DefaultID
+	}
+	return cacheKeyPrefix + strconv.FormatInt(tenantID, 10) + ":" + suffix
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
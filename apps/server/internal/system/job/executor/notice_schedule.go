@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/starter-kit-fe/admin/internal/system/job/types"
+)
+
+// NoticeScheduler 公告定时发布所需的能力
+type NoticeScheduler interface {
+	ApplySchedule(ctx context.Context, now time.Time) (published, expired int64, err error)
+}
+
+// NewNoticeScheduleExecutor 创建公告定时发布执行器，将到达发布时间的公告置为正常、超过过期时间的置为已过期。
+// 公告的可见性始终按发布时间窗口判断，执行间隔只影响管理列表中状态的更新及时性
+func NewNoticeScheduleExecutor(scheduler NoticeScheduler) types.Executor {
+	return func(ctx context.Context, payload types.ExecutionPayload) error {
+		if scheduler == nil {
+			return fmt.Errorf("notice service is not initialized")
+		}
+
+		var step types.StepInterface
+		if payload.StepLogger != nil {
+			step = payload.StepLogger.StartStep("更新公告发布状态")
+		}
+
+		published, expired, err := scheduler.ApplySchedule(ctx, time.Now())
+		if err != nil {
+			if step != nil {
+				_ = step.Fail(err)
+			}
+			return err
+		}
+
+		if step != nil {
+			step.Log("发布 %d 条，过期 %d 条", published, expired)
+			_ = step.Success()
+		} else if payload.Logger != nil {
+			payload.Logger.Info("notice schedule applied", "published", published, "expired", expired)
+		}
+		return nil
+	}
+}
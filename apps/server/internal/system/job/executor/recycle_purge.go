@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/starter-kit-fe/admin/internal/system/job/types"
+)
+
+// defaultRecycleRetentionDays 回收站记录默认保留天数
+const defaultRecycleRetentionDays = 30
+
+// RecyclePurger 定期清理回收站所需的能力
+type RecyclePurger interface {
+	PurgeExpired(ctx context.Context, before time.Time, resources []string) (map[string]int64, error)
+}
+
+// RecyclePurgeParams 回收站清理任务参数
+type RecyclePurgeParams struct {
+	RetentionDays int      `json:"retentionDays"` // 保留天数,默认 30 天
+	Resources     []string `json:"resources"`     // 需要清理的模块,可选,默认全部
+}
+
+// NewRecyclePurgeExecutor 创建回收站清理执行器，彻底删除超过保留天数的已删除记录
+func NewRecyclePurgeExecutor(purger RecyclePurger) types.Executor {
+	return func(ctx context.Context, payload types.ExecutionPayload) error {
+		if purger == nil {
+			return fmt.Errorf("recycle bin service is not initialized")
+		}
+
+		params := RecyclePurgeParams{RetentionDays: defaultRecycleRetentionDays}
+		if len(payload.Params) > 0 && string(payload.Params) != "null" {
+			if err := json.Unmarshal(payload.Params, &params); err != nil {
+				return fmt.Errorf("parse recycle purge params: %w", err)
+			}
+		}
+		if params.RetentionDays <= 0 {
+			params.RetentionDays = defaultRecycleRetentionDays
+		}
+
+		var step types.StepInterface
+		if payload.StepLogger != nil {
+			step = payload.StepLogger.StartStep("清理回收站")
+		}
+		logf := func(format string, args ...interface{}) {
+			if step != nil {
+				step.Log(format, args...)
+				return
+			}
+			if payload.Logger != nil {
+				payload.Logger.Info(fmt.Sprintf(format, args...))
+			}
+		}
+
+		before := time.Now().AddDate(0, 0, -params.RetentionDays)
+		logf("清理 %s 之前删除的记录", before.Format(time.DateTime))
+
+		result, err := purger.PurgeExpired(ctx, before, params.Resources)
+		keys := make([]string, 0, len(result))
+		for key := range result {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			logf("%s: 彻底删除 %d 条", key, result[key])
+		}
+
+		if err != nil {
+			if step != nil {
+				_ = step.Fail(err)
+			}
+			return err
+		}
+		if step != nil {
+			_ = step.Success()
+		}
+		return nil
+	}
+}
@@ -0,0 +1,179 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/resp"
+)
+
+// MaxManifestSize 同步接口允许的清单大小上限
+const MaxManifestSize = 4 << 20
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	if service == nil {
+		return nil
+	}
+	return &Handler{service: service}
+}
+
+type syncQuery struct {
+	Format string `form:"format"`
+	DryRun bool   `form:"dryRun"`
+	Prune  bool   `form:"prune"`
+}
+
+// Export godoc
+// @Summary 导出权限清单
+// @Description 将菜单、角色授权、字典与参数配置导出为可版本化的 YAML 或 JSON 清单
+// @Tags System/Manifest
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param format query string false "导出格式：yaml 或 json，默认 yaml"
+// @Success 200 {file} file
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/manifest/export [get]
+func (h *Handler) Export(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("manifest service unavailable"))
+		return
+	}
+
+	format, err := NormalizeFormat(ctx.Query("format"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("unsupported manifest format"))
+		return
+	}
+	if format == "" {
+		format = FormatYAML
+	}
+
+	m, err := h.service.Export(ctx.Request.Context())
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to export manifest"))
+		return
+	}
+	data, err := Encode(m, format)
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to encode manifest"))
+		return
+	}
+
+	contentType := "application/yaml"
+	if format == FormatJSON {
+		contentType = "application/json"
+	}
+	name := "manifest_" + time.Now().Format("20060102150405") + "." + format
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, name, url.PathEscape(name)))
+	ctx.Data(http.StatusOK, contentType, data)
+}
+
+// Sync godoc
+// @Summary 同步权限清单
+// @Description 对比清单与数据库差异并在同一事务内应用，dryRun 时仅返回计划；prune 会删除清单中未声明的记录
+// @Tags System/Manifest
+// @Security BearerAuth
+// @Accept plain
+// @Produce json
+// @Param format query string false "清单格式：yaml 或 json，缺省时根据 Content-Type 或内容识别"
+// @Param dryRun query bool false "仅预览变更"
+// @Param prune query bool false "删除清单中未声明的记录"
+// @Param manifest body string true "清单内容"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/manifest/sync [post]
+func (h *Handler) Sync(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("manifest service unavailable"))
+		return
+	}
+
+	var query syncQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	format, err := NormalizeFormat(query.Format)
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("unsupported manifest format"))
+		return
+	}
+	if format == "" {
+		format = formatFromContentType(ctx.GetHeader("Content-Type"))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(ctx.Request.Body, MaxManifestSize+1))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("failed to read manifest"))
+		return
+	}
+	if len(data) > MaxManifestSize {
+		resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("manifest exceeds %d bytes", MaxManifestSize)))
+		return
+	}
+
+	m, err := Decode(data, format)
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	plan, err := h.service.Sync(ctx.Request.Context(), m, SyncOptions{
+		DryRun:   query.DryRun,
+		Prune:    query.Prune,
+		Operator: resolveOperator(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidManifest) {
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to sync manifest"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(plan))
+}
+
+// formatFromContentType 根据请求类型推断清单格式，无法识别时返回空字符串
+func formatFromContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return FormatJSON
+	case strings.Contains(mediaType, "yaml"):
+		return FormatYAML
+	default:
+		return ""
+	}
+}
+
+func resolveOperator(ctx *gin.Context) string {
+	id, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,216 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version 当前支持的清单格式版本
+const Version = 1
+
+// 清单文件格式
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported manifest format")
+
+// Manifest 以声明式描述菜单、角色、字典与参数配置，用于在环境之间同步。
+// 各部分为空列表时表示该部分为空，缺省时同步会跳过该部分。
+type Manifest struct {
+	Version int      `json:"version" yaml:"version"`
+	Menus   []Menu   `json:"menus" yaml:"menus"`
+	Roles   []Role   `json:"roles" yaml:"roles"`
+	Dicts   []Dict   `json:"dicts" yaml:"dicts"`
+	Configs []Config `json:"configs" yaml:"configs"`
+}
+
+// Menu 菜单节点，通过 Children 表达层级。
+// 目录与菜单以路由路径作为稳定标识，按钮以上级菜单加权限标识作为稳定标识。
+type Menu struct {
+	Name     string `json:"name" yaml:"name"`
+	Type     string `json:"type" yaml:"type"`
+	Path     string `json:"path,omitempty" yaml:"path,omitempty"`
+	Query    string `json:"query,omitempty" yaml:"query,omitempty"`
+	Perms    string `json:"perms,omitempty" yaml:"perms,omitempty"`
+	Icon     string `json:"icon,omitempty" yaml:"icon,omitempty"`
+	Order    int    `json:"order" yaml:"order"`
+	IsFrame  bool   `json:"isFrame" yaml:"isFrame"`
+	IsCache  bool   `json:"isCache" yaml:"isCache"`
+	Visible  string `json:"visible" yaml:"visible"`
+	Status   string `json:"status" yaml:"status"`
+	Remark   string `json:"remark,omitempty" yaml:"remark,omitempty"`
+	Children []Menu `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// Role 角色以 RoleKey 作为稳定标识，Menus 为授权菜单的稳定标识
+type Role struct {
+	Key               string   `json:"key" yaml:"key"`
+	Name              string   `json:"name" yaml:"name"`
+	Sort              int      `json:"sort" yaml:"sort"`
+	Parent            string   `json:"parent,omitempty" yaml:"parent,omitempty"`
+	DataScope         string   `json:"dataScope" yaml:"dataScope"`
+	MenuCheckStrictly bool     `json:"menuCheckStrictly" yaml:"menuCheckStrictly"`
+	DeptCheckStrictly bool     `json:"deptCheckStrictly" yaml:"deptCheckStrictly"`
+	Status            string   `json:"status" yaml:"status"`
+	Remark            string   `json:"remark,omitempty" yaml:"remark,omitempty"`
+	Menus             []string `json:"menus" yaml:"menus"`
+}
+
+// Dict 字典类型以 DictType 作为稳定标识，字典数据以字典值作为稳定标识
+type Dict struct {
+	Type   string     `json:"type" yaml:"type"`
+	Name   string     `json:"name" yaml:"name"`
+	Status string     `json:"status" yaml:"status"`
+	Remark string     `json:"remark,omitempty" yaml:"remark,omitempty"`
+	Items  []DictItem `json:"items" yaml:"items"`
+}
+
+type DictItem struct {
+	Value string `json:"value" yaml:"value"`
+	Label string `json:"label" yaml:"label"`
+	// Parent 上级字典数据的取值，顶级数据为空
+	Parent    string `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Sort      int    `json:"sort" yaml:"sort"`
+	CSSClass  string `json:"cssClass,omitempty" yaml:"cssClass,omitempty"`
+	ListClass string `json:"listClass,omitempty" yaml:"listClass,omitempty"`
+	IsDefault string `json:"isDefault" yaml:"isDefault"`
+	Status    string `json:"status" yaml:"status"`
+	Remark    string `json:"remark,omitempty" yaml:"remark,omitempty"`
+}
+
+// Config 参数配置以 ConfigKey 作为稳定标识；值类型、约束、默认值与公开标记未声明时保持现状
+type Config struct {
+	Key       string `json:"key" yaml:"key"`
+	Name      string `json:"name" yaml:"name"`
+	Value     string `json:"value" yaml:"value"`
+	Type      string `json:"type" yaml:"type"`
+	ValueType string `json:"valueType,omitempty" yaml:"valueType,omitempty"`
+	Schema    string `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Default   string `json:"default,omitempty" yaml:"default,omitempty"`
+	Public    *bool  `json:"public,omitempty" yaml:"public,omitempty"`
+	Remark    string `json:"remark,omitempty" yaml:"remark,omitempty"`
+}
+
+// NormalizeFormat 规范化格式名称，空字符串表示自动识别
+func NormalizeFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "":
+		return "", nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// Encode 将清单编码为指定格式
+func Encode(m *Manifest, format string) ([]byte, error) {
+	normalized, err := NormalizeFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	if normalized == FormatJSON {
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode 解析清单，format 为空时根据内容识别 JSON 或 YAML；未知字段视为错误以便发现拼写问题
+func Decode(data []byte, format string) (*Manifest, error) {
+	normalized, err := NormalizeFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	if normalized == "" {
+		normalized = FormatYAML
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			normalized = FormatJSON
+		}
+	}
+
+	var m Manifest
+	if normalized == FormatJSON {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&m); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+		}
+		return &m, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+	return &m, nil
+}
+
+// keyInput 为计算菜单稳定标识所需的字段
+type keyInput struct {
+	Type  string
+	Path  string
+	Perms string
+	Name  string
+}
+
+// siblingKeys 计算同一上级下各菜单的稳定标识。
+// 目录与菜单使用上级标识拼接路由路径；按钮使用上级标识拼接权限标识，
+// 同一上级下权限标识重复的按钮额外拼接名称加以区分。
+func siblingKeys(parentKey string, items []keyInput) []string {
+	keys := make([]string, len(items))
+	counts := make(map[string]int, len(items))
+	for i, item := range items {
+		keys[i] = baseMenuKey(parentKey, item)
+		counts[keys[i]]++
+	}
+	for i, item := range items {
+		if counts[keys[i]] > 1 && isButton(item.Type) {
+			keys[i] = keys[i] + "#" + strings.TrimSpace(item.Name)
+		}
+	}
+	return keys
+}
+
+func baseMenuKey(parentKey string, item keyInput) string {
+	if isButton(item.Type) {
+		if perms := strings.TrimSpace(item.Perms); perms != "" {
+			return parentKey + "#" + perms
+		}
+		return parentKey + "#" + strings.TrimSpace(item.Name)
+	}
+
+	segment := strings.Trim(strings.TrimSpace(item.Path), "/")
+	if segment == "" || segment == "#" {
+		segment = strings.TrimSpace(item.Name)
+	}
+	return parentKey + "/" + segment
+}
+
+func isButton(menuType string) bool {
+	return strings.EqualFold(strings.TrimSpace(menuType), "F")
+}
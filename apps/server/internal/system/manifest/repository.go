@@ -0,0 +1,358 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+var (
+	ErrRepositoryUnavailable = errors.New("manifest repository is not initialized")
+	// errDryRun 用于在预览模式下回滚事务
+	errDryRun = errors.New("manifest dry run")
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	if db == nil {
+		return nil
+	}
+	return &Repository{db: db}
+}
+
+// Export 读取当前数据库中的菜单、角色、字典与参数配置
+func (r *Repository) Export(ctx context.Context) (*Manifest, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	db := r.db.WithContext(ctx)
+	menus, err := loadMenuState(db)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := exportRoles(db, menus)
+	if err != nil {
+		return nil, err
+	}
+	dicts, err := exportDicts(db)
+	if err != nil {
+		return nil, err
+	}
+	configs, err := exportConfigs(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manifest{
+		Version: Version,
+		Menus:   menus.tree(0),
+		Roles:   roles,
+		Dicts:   dicts,
+		Configs: configs,
+	}, nil
+}
+
+// Sync 在同一事务内将清单同步到数据库，dryRun 时仅生成计划并回滚；
+// 返回的 writes 为已提交的参数与字典写入，供调用方刷新缓存与记录历史
+func (r *Repository) Sync(ctx context.Context, m *Manifest, opts SyncOptions, at time.Time) (*Plan, *writes, error) {
+	if r == nil || r.db == nil {
+		return nil, nil, ErrRepositoryUnavailable
+	}
+
+	plan := &Plan{Changes: []Change{}}
+	var applied *writes
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		s := &syncer{tx: tx, operator: opts.Operator, prune: opts.Prune, now: at, plan: plan}
+		if err := s.run(m); err != nil {
+			return err
+		}
+		if opts.DryRun {
+			return errDryRun
+		}
+		var err error
+		applied, err = s.collectWrites()
+		return err
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, nil, err
+	}
+	plan.Applied = !opts.DryRun
+	if applied == nil {
+		applied = &writes{}
+	}
+	return plan, applied, nil
+}
+
+// menuState 数据库中的菜单及其稳定标识
+type menuState struct {
+	byKey    map[string]model.SysMenu
+	keyOf    map[int64]string
+	children map[int64][]model.SysMenu
+	// extras 标识重复的菜单，无法通过清单定位
+	extras []model.SysMenu
+}
+
+func loadMenuState(db *gorm.DB) (*menuState, error) {
+	var menus []model.SysMenu
+	if err := db.Order("parent_id ASC, order_num ASC, id ASC").Find(&menus).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int64]struct{}, len(menus))
+	for _, menu := range menus {
+		ids[int64(menu.ID)] = struct{}{}
+	}
+
+	state := &menuState{
+		byKey:    make(map[string]model.SysMenu, len(menus)),
+		keyOf:    make(map[int64]string, len(menus)),
+		children: make(map[int64][]model.SysMenu),
+	}
+	for _, menu := range menus {
+		parentID := menu.ParentID
+		// 上级不存在的菜单按顶级菜单处理
+		if _, ok := ids[parentID]; !ok {
+			parentID = 0
+		}
+		state.children[parentID] = append(state.children[parentID], menu)
+	}
+
+	visited := make(map[int64]struct{}, len(menus))
+	var walk func(parentID int64, parentKey string)
+	walk = func(parentID int64, parentKey string) {
+		items := state.children[parentID]
+		inputs := make([]keyInput, len(items))
+		for i, item := range items {
+			inputs[i] = menuKeyInput(item)
+		}
+		keys := siblingKeys(parentKey, inputs)
+		for i, item := range items {
+			id := int64(item.ID)
+			if _, ok := visited[id]; ok {
+				continue
+			}
+			visited[id] = struct{}{}
+
+			if _, exists := state.byKey[keys[i]]; exists {
+				state.extras = append(state.extras, item)
+				continue
+			}
+			state.byKey[keys[i]] = item
+			state.keyOf[id] = keys[i]
+			walk(id, keys[i])
+		}
+	}
+	walk(0, "")
+
+	// 上级链路成环的菜单无法从顶级访问，同样视为无法定位
+	for _, menu := range menus {
+		if _, ok := visited[int64(menu.ID)]; !ok {
+			state.extras = append(state.extras, menu)
+		}
+	}
+	return state, nil
+}
+
+// tree 将数据库菜单转换为清单中的菜单树
+func (s *menuState) tree(parentID int64) []Menu {
+	items := s.children[parentID]
+	result := make([]Menu, 0, len(items))
+	for _, item := range items {
+		if _, ok := s.keyOf[int64(item.ID)]; !ok {
+			continue
+		}
+		result = append(result, Menu{
+			Name:     item.MenuName,
+			Type:     item.MenuType,
+			Path:     item.Path,
+			Query:    derefString(item.Query),
+			Perms:    derefString(item.Perms),
+			Icon:     item.Icon,
+			Order:    item.OrderNum,
+			IsFrame:  item.IsFrame,
+			IsCache:  item.IsCache,
+			Visible:  item.Visible,
+			Status:   item.Status,
+			Remark:   item.Remark,
+			Children: s.tree(int64(item.ID)),
+		})
+	}
+	return result
+}
+
+func menuKeyInput(menu model.SysMenu) keyInput {
+	return keyInput{
+		Type:  menu.MenuType,
+		Path:  menu.Path,
+		Perms: derefString(menu.Perms),
+		Name:  menu.MenuName,
+	}
+}
+
+func exportRoles(db *gorm.DB, menus *menuState) ([]Role, error) {
+	var records []model.SysRole
+	if err := db.Order("role_sort ASC, id ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	keyByID := make(map[int64]string, len(records))
+	ids := make([]int64, 0, len(records))
+	for _, record := range records {
+		keyByID[int64(record.ID)] = record.RoleKey
+		ids = append(ids, int64(record.ID))
+	}
+
+	grants, err := loadRoleMenuKeys(db, ids, menus)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]Role, 0, len(records))
+	for _, record := range records {
+		menuKeys := grants[int64(record.ID)]
+		if menuKeys == nil {
+			menuKeys = []string{}
+		}
+		roles = append(roles, Role{
+			Key:               record.RoleKey,
+			Name:              record.RoleName,
+			Sort:              record.RoleSort,
+			Parent:            keyByID[record.ParentID],
+			DataScope:         record.DataScope,
+			MenuCheckStrictly: record.MenuCheckStrictly,
+			DeptCheckStrictly: record.DeptCheckStrictly,
+			Status:            record.Status,
+			Remark:            derefString(record.Remark),
+			Menus:             menuKeys,
+		})
+	}
+	return roles, nil
+}
+
+// loadRoleMenuKeys 返回角色授权菜单的稳定标识，无法定位的菜单被忽略
+func loadRoleMenuKeys(db *gorm.DB, roleIDs []int64, menus *menuState) (map[int64][]string, error) {
+	result := make(map[int64][]string, len(roleIDs))
+	if len(roleIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []model.SysRoleMenu
+	if err := db.Where("role_id IN ?", roleIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if key, ok := menus.keyOf[row.MenuID]; ok {
+			result[row.RoleID] = append(result[row.RoleID], key)
+		}
+	}
+	for id := range result {
+		sort.Strings(result[id])
+	}
+	return result, nil
+}
+
+func exportDicts(db *gorm.DB) ([]Dict, error) {
+	var types []model.SysDictType
+	if err := db.Order("id ASC").Find(&types).Error; err != nil {
+		return nil, err
+	}
+	var data []model.SysDictData
+	if err := db.Order("dict_type ASC, dict_sort ASC, id ASC").Find(&data).Error; err != nil {
+		return nil, err
+	}
+
+	values := make(map[int64]string, len(data))
+	for _, record := range data {
+		values[int64(record.ID)] = record.DictValue
+	}
+	items := make(map[string][]DictItem, len(types))
+	for _, record := range data {
+		item := dictItemFromModel(record)
+		item.Parent = values[record.ParentID]
+		items[record.DictType] = append(items[record.DictType], item)
+	}
+
+	dicts := make([]Dict, 0, len(types))
+	for _, record := range types {
+		list := items[record.DictType]
+		if list == nil {
+			list = []DictItem{}
+		}
+		dicts = append(dicts, Dict{
+			Type:   record.DictType,
+			Name:   record.DictName,
+			Status: record.Status,
+			Remark: derefString(record.Remark),
+			Items:  list,
+		})
+	}
+	return dicts, nil
+}
+
+func dictItemFromModel(record model.SysDictData) DictItem {
+	return DictItem{
+		Value:     record.DictValue,
+		Label:     record.DictLabel,
+		Sort:      record.DictSort,
+		CSSClass:  derefString(record.CSSClass),
+		ListClass: derefString(record.ListClass),
+		IsDefault: record.IsDefault,
+		Status:    record.Status,
+		Remark:    derefString(record.Remark),
+	}
+}
+
+func exportConfigs(db *gorm.DB) ([]Config, error) {
+	var records []model.SysConfig
+	if err := db.Order("id ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	configs := make([]Config, 0, len(records))
+	for _, record := range records {
+		public := record.IsPublic
+		value := record.ConfigValue
+		if record.ValueType == "secret" {
+			value = ""
+		}
+		configs = append(configs, Config{
+			Key:       record.ConfigKey,
+			Name:      record.ConfigName,
+			Value:     value,
+			Type:      record.ConfigType,
+			ValueType: record.ValueType,
+			Schema:    derefString(record.ValueSchema),
+			Default:   derefString(record.DefaultValue),
+			Public:    &public,
+			Remark:    derefString(record.Remark),
+		})
+	}
+	return configs, nil
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// optionalString 空字符串映射为 NULL
+func optionalString(value string) *string {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -0,0 +1,218 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sysconfig "github.com/starter-kit-fe/admin/internal/system/config"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+)
+
+var (
+	ErrServiceUnavailable = errors.New("manifest service is not initialized")
+	ErrInvalidManifest    = errors.New("invalid manifest")
+)
+
+// 变更对象类型
+const (
+	KindMenu     = "menu"
+	KindRole     = "role"
+	KindRoleMenu = "role_menu"
+	KindDictType = "dict_type"
+	KindDictData = "dict_data"
+	KindConfig   = "config"
+)
+
+// 变更动作
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+var validMenuTypes = map[string]struct{}{"M": {}, "C": {}, "F": {}}
+
+const defaultOperator = "system"
+
+type Service struct {
+	repo *Repository
+	// 同步直接写入参数与字典表，提交后经对应服务刷新缓存并记录历史
+	configs *sysconfig.Service
+	dicts   *dict.Service
+}
+
+func NewService(repo *Repository, configs *sysconfig.Service, dicts *dict.Service) *Service {
+	if repo == nil {
+		return nil
+	}
+	return &Service{repo: repo, configs: configs, dicts: dicts}
+}
+
+// Change 同步计划中的一项变更，Fields 为更新的字段，角色授权变更以 +/- 前缀列出菜单
+type Change struct {
+	Kind   string   `json:"kind"`
+	Key    string   `json:"key"`
+	Action string   `json:"action"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Plan 清单与数据库的差异，Applied 表示是否已写入数据库
+type Plan struct {
+	Changes []Change `json:"changes"`
+	Applied bool     `json:"applied"`
+}
+
+// Empty reports whether the database already matches the manifest.
+func (p *Plan) Empty() bool {
+	return p == nil || len(p.Changes) == 0
+}
+
+type SyncOptions struct {
+	// DryRun 仅生成计划，不写入数据库
+	DryRun bool
+	// Prune 删除清单中未声明的记录，仅作用于清单中出现的部分
+	Prune    bool
+	Operator string
+}
+
+// Export 导出当前数据库中的菜单、角色、字典与参数配置
+func (s *Service) Export(ctx context.Context) (*Manifest, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	return s.repo.Export(ctx)
+}
+
+// Sync 校验清单并在同一事务内同步到数据库，重复执行同一清单不会产生变更
+func (s *Service) Sync(ctx context.Context, m *Manifest, opts SyncOptions) (*Plan, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	if err := Validate(m); err != nil {
+		return nil, err
+	}
+
+	opts.Operator = strings.TrimSpace(opts.Operator)
+	if opts.Operator == "" {
+		opts.Operator = defaultOperator
+	}
+	plan, applied, err := s.repo.Sync(ctx, m, opts, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range applied.Configs {
+		s.configs.RecordExternalChange(ctx, w.Before, w.After, opts.Operator)
+	}
+	for _, w := range applied.DictTypes {
+		s.dicts.RecordExternalTypeChange(ctx, w.Before, w.After, opts.Operator)
+	}
+	for _, w := range applied.DictData {
+		s.dicts.RecordExternalDataChange(ctx, w.Before, w.After, opts.Operator)
+	}
+	return plan, nil
+}
+
+// Validate 校验清单版本、必填字段、稳定标识的唯一性与参数值的类型约束
+func Validate(m *Manifest) error {
+	if m == nil {
+		return fmt.Errorf("%w: manifest is empty", ErrInvalidManifest)
+	}
+	if m.Version != Version {
+		return fmt.Errorf("%w: unsupported version %d, expected %d", ErrInvalidManifest, m.Version, Version)
+	}
+
+	if err := validateMenus(m.Menus, "", make(map[string]struct{})); err != nil {
+		return err
+	}
+
+	roleKeys := make(map[string]struct{}, len(m.Roles))
+	for _, role := range m.Roles {
+		key := strings.TrimSpace(role.Key)
+		if key == "" || key != role.Key {
+			return fmt.Errorf("%w: role key %q is empty or has surrounding spaces", ErrInvalidManifest, role.Key)
+		}
+		if strings.TrimSpace(role.Name) == "" {
+			return fmt.Errorf("%w: role %q has no name", ErrInvalidManifest, key)
+		}
+		if _, ok := roleKeys[key]; ok {
+			return fmt.Errorf("%w: duplicate role %q", ErrInvalidManifest, key)
+		}
+		roleKeys[key] = struct{}{}
+		if strings.TrimSpace(role.Parent) == key {
+			return fmt.Errorf("%w: role %q cannot be its own parent", ErrInvalidManifest, key)
+		}
+	}
+
+	dictTypes := make(map[string]struct{}, len(m.Dicts))
+	for _, dict := range m.Dicts {
+		dictType := strings.TrimSpace(dict.Type)
+		if dictType == "" || dictType != dict.Type {
+			return fmt.Errorf("%w: dictionary type %q is empty or has surrounding spaces", ErrInvalidManifest, dict.Type)
+		}
+		if _, ok := dictTypes[dictType]; ok {
+			return fmt.Errorf("%w: duplicate dictionary type %q", ErrInvalidManifest, dictType)
+		}
+		dictTypes[dictType] = struct{}{}
+
+		values := make(map[string]struct{}, len(dict.Items))
+		for _, item := range dict.Items {
+			if strings.TrimSpace(item.Label) == "" {
+				return fmt.Errorf("%w: dictionary %q has an item without label", ErrInvalidManifest, dictType)
+			}
+			if _, ok := values[item.Value]; ok {
+				return fmt.Errorf("%w: duplicate value %q in dictionary %q", ErrInvalidManifest, item.Value, dictType)
+			}
+			values[item.Value] = struct{}{}
+		}
+	}
+
+	configKeys := make(map[string]struct{}, len(m.Configs))
+	for _, config := range m.Configs {
+		key := strings.TrimSpace(config.Key)
+		if key == "" || key != config.Key {
+			return fmt.Errorf("%w: config key %q is empty or has surrounding spaces", ErrInvalidManifest, config.Key)
+		}
+		if _, ok := configKeys[key]; ok {
+			return fmt.Errorf("%w: duplicate config %q", ErrInvalidManifest, key)
+		}
+		configKeys[key] = struct{}{}
+		// 未声明值类型时同步沿用现有类型，新增的参数按字符串处理，这里只能按后者校验，其余由同步时按现有类型补充校验
+		if err := sysconfig.ValidateValue(sysconfig.ValueInput{
+			ValueType:    config.ValueType,
+			ValueSchema:  optionalString(config.Schema),
+			DefaultValue: optionalString(config.Default),
+			ConfigValue:  config.Value,
+			IsPublic:     config.Public != nil && *config.Public,
+		}); err != nil {
+			return fmt.Errorf("%w: config %q: %v", ErrInvalidManifest, key, err)
+		}
+	}
+	return nil
+}
+
+func validateMenus(menus []Menu, parentKey string, seen map[string]struct{}) error {
+	keys := siblingKeys(parentKey, menuInputs(menus))
+	for i, menu := range menus {
+		if strings.TrimSpace(menu.Name) == "" {
+			return fmt.Errorf("%w: menu %q has no name", ErrInvalidManifest, keys[i])
+		}
+		if _, ok := validMenuTypes[strings.ToUpper(strings.TrimSpace(menu.Type))]; !ok {
+			return fmt.Errorf("%w: menu %q has invalid type %q", ErrInvalidManifest, keys[i], menu.Type)
+		}
+		if isButton(menu.Type) && len(menu.Children) > 0 {
+			return fmt.Errorf("%w: button %q cannot have children", ErrInvalidManifest, keys[i])
+		}
+		if _, ok := seen[keys[i]]; ok {
+			return fmt.Errorf("%w: duplicate menu %q", ErrInvalidManifest, keys[i])
+		}
+		seen[keys[i]] = struct{}{}
+
+		if err := validateMenus(menu.Children, keys[i], seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,852 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	sysconfig "github.com/starter-kit-fe/admin/internal/system/config"
+)
+
+// syncer 在事务内对比清单与数据库并写入差异，每项差异记录到计划中
+type syncer struct {
+	tx       *gorm.DB
+	operator string
+	prune    bool
+	now      time.Time
+	plan     *Plan
+
+	// 写入过的参数与字典在写入前的快照，键为记录ID，新建的记录为 nil
+	configs   map[int64]*model.SysConfig
+	dictTypes map[int64]*model.SysDictType
+	dictData  map[int64]*model.SysDictData
+}
+
+// writes 同步写入的参数与字典在写入前后的状态，提交后据此刷新缓存并记录变更历史；
+// 新建时 Before 为空，删除时 After 为空
+type writes struct {
+	Configs   []configWrite
+	DictTypes []dictTypeWrite
+	DictData  []dictDataWrite
+}
+
+type configWrite struct{ Before, After *model.SysConfig }
+
+type dictTypeWrite struct{ Before, After *model.SysDictType }
+
+type dictDataWrite struct{ Before, After *model.SysDictData }
+
+func (s *syncer) run(m *Manifest) error {
+	menus, err := s.syncMenus(m.Menus)
+	if err != nil {
+		return err
+	}
+	if m.Roles != nil {
+		if err := s.syncRoles(m.Roles, menus); err != nil {
+			return err
+		}
+	}
+	if m.Dicts != nil {
+		if err := s.syncDicts(m.Dicts); err != nil {
+			return err
+		}
+	}
+	if m.Configs != nil {
+		if err := s.syncConfigs(m.Configs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// touch 记录首次写入前的快照，同一记录多次写入时保留最初的状态
+func touch[T any](snapshots *map[int64]*T, id int64, before *T) {
+	if *snapshots == nil {
+		*snapshots = make(map[int64]*T)
+	}
+	if _, ok := (*snapshots)[id]; ok {
+		return
+	}
+	if before != nil {
+		copied := *before
+		before = &copied
+	}
+	(*snapshots)[id] = before
+}
+
+// collectWrites 在事务提交前读取写入后的状态，已删除的记录查询不到，After 保持为空
+func (s *syncer) collectWrites() (*writes, error) {
+	result := &writes{}
+
+	if len(s.configs) > 0 {
+		var records []model.SysConfig
+		if err := s.tx.Where("id IN ?", sortedIDs(s.configs)).Find(&records).Error; err != nil {
+			return nil, err
+		}
+		after := make(map[int64]*model.SysConfig, len(records))
+		for i := range records {
+			after[int64(records[i].ID)] = &records[i]
+		}
+		for _, id := range sortedIDs(s.configs) {
+			result.Configs = append(result.Configs, configWrite{Before: s.configs[id], After: after[id]})
+		}
+	}
+
+	if len(s.dictTypes) > 0 {
+		var records []model.SysDictType
+		if err := s.tx.Where("id IN ?", sortedIDs(s.dictTypes)).Find(&records).Error; err != nil {
+			return nil, err
+		}
+		after := make(map[int64]*model.SysDictType, len(records))
+		for i := range records {
+			after[int64(records[i].ID)] = &records[i]
+		}
+		for _, id := range sortedIDs(s.dictTypes) {
+			result.DictTypes = append(result.DictTypes, dictTypeWrite{Before: s.dictTypes[id], After: after[id]})
+		}
+	}
+
+	if len(s.dictData) > 0 {
+		var records []model.SysDictData
+		if err := s.tx.Where("id IN ?", sortedIDs(s.dictData)).Find(&records).Error; err != nil {
+			return nil, err
+		}
+		after := make(map[int64]*model.SysDictData, len(records))
+		for i := range records {
+			after[int64(records[i].ID)] = &records[i]
+		}
+		for _, id := range sortedIDs(s.dictData) {
+			result.DictData = append(result.DictData, dictDataWrite{Before: s.dictData[id], After: after[id]})
+		}
+	}
+	return result, nil
+}
+
+func sortedIDs[T any](items map[int64]*T) []int64 {
+	ids := make([]int64, 0, len(items))
+	for id := range items {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (s *syncer) record(kind, key, action string, fields []string) {
+	s.plan.Changes = append(s.plan.Changes, Change{Kind: kind, Key: key, Action: action, Fields: fields})
+}
+
+// stamp 为更新补充操作人与更新时间
+func (s *syncer) stamp(updates map[string]interface{}) map[string]interface{} {
+	updates["update_by"] = s.operator
+	updates["updated_at"] = s.now
+	return updates
+}
+
+// syncMenus 同步菜单树，返回同步后全部菜单的稳定标识与 ID 映射
+func (s *syncer) syncMenus(menus []Menu) (map[string]int64, error) {
+	state, err := loadMenuState(s.tx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]int64, len(state.byKey))
+	for key, menu := range state.byKey {
+		ids[key] = int64(menu.ID)
+	}
+	if menus == nil {
+		return ids, nil
+	}
+
+	seen := make(map[string]struct{})
+	var walk func(items []Menu, parentID int64, parentKey string) error
+	walk = func(items []Menu, parentID int64, parentKey string) error {
+		keys := siblingKeys(parentKey, menuInputs(items))
+		for i, item := range items {
+			key := keys[i]
+			seen[key] = struct{}{}
+
+			id, err := s.syncMenu(key, item, parentID, state)
+			if err != nil {
+				return err
+			}
+			ids[key] = id
+
+			if err := walk(item.Children, id, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(menus, 0, ""); err != nil {
+		return nil, err
+	}
+
+	if !s.prune {
+		return ids, nil
+	}
+
+	removed := make([]int64, 0)
+	for _, key := range sortedKeys(state.byKey) {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		menu := state.byKey[key]
+		removed = append(removed, int64(menu.ID))
+		delete(ids, key)
+		s.record(KindMenu, key, ActionDelete, nil)
+	}
+	for _, menu := range state.extras {
+		removed = append(removed, int64(menu.ID))
+		s.record(KindMenu, fmt.Sprintf("#%d %s", menu.ID, menu.MenuName), ActionDelete, nil)
+	}
+	if len(removed) == 0 {
+		return ids, nil
+	}
+
+	if err := s.tx.Where("menu_id IN ?", removed).Delete(&model.SysRoleMenu{}).Error; err != nil {
+		return nil, err
+	}
+	if err := s.tx.Model(&model.SysMenu{}).Where("id IN ?", removed).Updates(s.stamp(map[string]interface{}{})).Error; err != nil {
+		return nil, err
+	}
+	if err := s.tx.Where("id IN ?", removed).Delete(&model.SysMenu{}).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *syncer) syncMenu(key string, item Menu, parentID int64, state *menuState) (int64, error) {
+	desired := model.SysMenu{
+		MenuName: item.Name,
+		ParentID: parentID,
+		OrderNum: item.Order,
+		Path:     item.Path,
+		Query:    optionalString(item.Query),
+		IsFrame:  item.IsFrame,
+		IsCache:  item.IsCache,
+		MenuType: strings.ToUpper(strings.TrimSpace(item.Type)),
+		Visible:  defaultString(item.Visible, "0"),
+		Status:   defaultString(item.Status, "0"),
+		Perms:    optionalString(item.Perms),
+		Icon:     item.Icon,
+		Remark:   item.Remark,
+	}
+
+	current, ok := state.byKey[key]
+	if !ok {
+		desired.CreateBy = s.operator
+		desired.UpdateBy = s.operator
+		if err := s.tx.Create(&desired).Error; err != nil {
+			return 0, err
+		}
+		s.record(KindMenu, key, ActionCreate, nil)
+		return int64(desired.ID), nil
+	}
+
+	diff := newFieldDiff()
+	diff.compare("menu_name", current.MenuName, desired.MenuName)
+	diff.compare("parent_id", current.ParentID, desired.ParentID)
+	diff.compare("order_num", current.OrderNum, desired.OrderNum)
+	diff.compare("path", current.Path, desired.Path)
+	diff.compareOptional("query", current.Query, desired.Query)
+	diff.compare("is_frame", current.IsFrame, desired.IsFrame)
+	diff.compare("is_cache", current.IsCache, desired.IsCache)
+	diff.compare("menu_type", current.MenuType, desired.MenuType)
+	diff.compare("visible", current.Visible, desired.Visible)
+	diff.compare("status", current.Status, desired.Status)
+	diff.compareOptional("perms", current.Perms, desired.Perms)
+	diff.compare("icon", current.Icon, desired.Icon)
+	diff.compare("remark", current.Remark, desired.Remark)
+
+	if diff.empty() {
+		return int64(current.ID), nil
+	}
+	if err := s.tx.Model(&model.SysMenu{}).Where("id = ?", current.ID).Updates(s.stamp(diff.updates)).Error; err != nil {
+		return 0, err
+	}
+	s.record(KindMenu, key, ActionUpdate, diff.fields)
+	return int64(current.ID), nil
+}
+
+func menuInputs(items []Menu) []keyInput {
+	inputs := make([]keyInput, len(items))
+	for i, item := range items {
+		inputs[i] = keyInput{Type: item.Type, Path: item.Path, Perms: item.Perms, Name: item.Name}
+	}
+	return inputs
+}
+
+func (s *syncer) syncRoles(roles []Role, menuIDs map[string]int64) error {
+	var records []model.SysRole
+	if err := s.tx.Order("id ASC").Find(&records).Error; err != nil {
+		return err
+	}
+	byKey := make(map[string]model.SysRole, len(records))
+	for _, record := range records {
+		if _, ok := byKey[record.RoleKey]; !ok {
+			byKey[record.RoleKey] = record
+		}
+	}
+
+	roleIDs := make(map[string]int64, len(roles))
+	for _, role := range roles {
+		id, err := s.syncRole(role, byKey)
+		if err != nil {
+			return err
+		}
+		roleIDs[role.Key] = id
+	}
+
+	// 全部角色就绪后再解析上级角色，清单中的角色可以引用排在后面的角色
+	parents := make(map[int64]int64, len(records)+len(roles))
+	for _, record := range records {
+		parents[int64(record.ID)] = record.ParentID
+	}
+	for _, role := range roles {
+		id := roleIDs[role.Key]
+		var parentID int64
+		if parentKey := strings.TrimSpace(role.Parent); parentKey != "" {
+			if resolved, ok := roleIDs[parentKey]; ok {
+				parentID = resolved
+			} else if existing, ok := byKey[parentKey]; ok && !s.prune {
+				parentID = int64(existing.ID)
+			} else {
+				return fmt.Errorf("%w: role %q references unknown parent role %q", ErrInvalidManifest, role.Key, parentKey)
+			}
+		}
+		if parents[id] != parentID {
+			if err := s.tx.Model(&model.SysRole{}).Where("id = ?", id).
+				Updates(s.stamp(map[string]interface{}{"parent_id": parentID})).Error; err != nil {
+				return err
+			}
+			if current, ok := byKey[role.Key]; ok && int64(current.ID) == id {
+				s.record(KindRole, role.Key, ActionUpdate, []string{"parent_id"})
+			}
+		}
+		parents[id] = parentID
+	}
+	if err := detectRoleCycle(parents); err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		if err := s.syncRoleMenus(role, roleIDs[role.Key], menuIDs); err != nil {
+			return err
+		}
+	}
+
+	if !s.prune {
+		return nil
+	}
+
+	removed := make([]int64, 0)
+	for _, key := range sortedKeys(byKey) {
+		if _, ok := roleIDs[key]; ok {
+			continue
+		}
+		removed = append(removed, int64(byKey[key].ID))
+		s.record(KindRole, key, ActionDelete, nil)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	for _, relation := range []interface{}{&model.SysRoleMenu{}, &model.SysDeptRole{}, &model.SysPostRole{}} {
+		if err := s.tx.Where("role_id IN ?", removed).Delete(relation).Error; err != nil {
+			return err
+		}
+	}
+	if err := s.tx.Model(&model.SysRole{}).Where("id IN ?", removed).Updates(s.stamp(map[string]interface{}{})).Error; err != nil {
+		return err
+	}
+	return s.tx.Where("id IN ?", removed).Delete(&model.SysRole{}).Error
+}
+
+func (s *syncer) syncRole(role Role, byKey map[string]model.SysRole) (int64, error) {
+	desired := model.SysRole{
+		RoleName:          role.Name,
+		RoleKey:           role.Key,
+		RoleSort:          role.Sort,
+		DataScope:         defaultString(role.DataScope, "1"),
+		MenuCheckStrictly: role.MenuCheckStrictly,
+		DeptCheckStrictly: role.DeptCheckStrictly,
+		Status:            defaultString(role.Status, "0"),
+		Remark:            optionalString(role.Remark),
+	}
+
+	current, ok := byKey[role.Key]
+	if !ok {
+		desired.CreateBy = s.operator
+		desired.UpdateBy = s.operator
+		if err := s.tx.Create(&desired).Error; err != nil {
+			return 0, err
+		}
+		s.record(KindRole, role.Key, ActionCreate, nil)
+		return int64(desired.ID), nil
+	}
+
+	diff := newFieldDiff()
+	diff.compare("role_name", current.RoleName, desired.RoleName)
+	diff.compare("role_sort", current.RoleSort, desired.RoleSort)
+	diff.compare("data_scope", current.DataScope, desired.DataScope)
+	diff.compare("menu_check_strictly", current.MenuCheckStrictly, desired.MenuCheckStrictly)
+	diff.compare("dept_check_strictly", current.DeptCheckStrictly, desired.DeptCheckStrictly)
+	diff.compare("status", current.Status, desired.Status)
+	diff.compareOptional("remark", current.Remark, desired.Remark)
+
+	if !diff.empty() {
+		if err := s.tx.Model(&model.SysRole{}).Where("id = ?", current.ID).Updates(s.stamp(diff.updates)).Error; err != nil {
+			return 0, err
+		}
+		s.record(KindRole, role.Key, ActionUpdate, diff.fields)
+	}
+	return int64(current.ID), nil
+}
+
+// syncRoleMenus 对比角色菜单授权，变更字段以 +/- 前缀列出新增与移除的菜单
+func (s *syncer) syncRoleMenus(role Role, roleID int64, menuIDs map[string]int64) error {
+	desired := make(map[int64]string, len(role.Menus))
+	for _, key := range role.Menus {
+		id, ok := menuIDs[key]
+		if !ok {
+			return fmt.Errorf("%w: role %q references unknown menu %q", ErrInvalidManifest, role.Key, key)
+		}
+		desired[id] = key
+	}
+
+	var current []int64
+	if err := s.tx.Model(&model.SysRoleMenu{}).Where("role_id = ?", roleID).Pluck("menu_id", &current).Error; err != nil {
+		return err
+	}
+	keyOf := make(map[int64]string, len(menuIDs))
+	for key, id := range menuIDs {
+		keyOf[id] = key
+	}
+
+	changes := make([]string, 0)
+	removed := make([]int64, 0)
+	granted := make(map[int64]struct{}, len(current))
+	for _, id := range current {
+		granted[id] = struct{}{}
+		if _, ok := desired[id]; ok {
+			continue
+		}
+		removed = append(removed, id)
+		label := keyOf[id]
+		if label == "" {
+			label = fmt.Sprintf("#%d", id)
+		}
+		changes = append(changes, "-"+label)
+	}
+
+	added := make([]model.SysRoleMenu, 0)
+	for id, key := range desired {
+		if _, ok := granted[id]; ok {
+			continue
+		}
+		added = append(added, model.SysRoleMenu{RoleID: roleID, MenuID: id})
+		changes = append(changes, "+"+key)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	sort.Strings(changes)
+
+	if len(removed) > 0 {
+		if err := s.tx.Where("role_id = ? AND menu_id IN ?", roleID, removed).Delete(&model.SysRoleMenu{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(added) > 0 {
+		if err := s.tx.Create(&added).Error; err != nil {
+			return err
+		}
+	}
+	s.record(KindRoleMenu, role.Key, ActionUpdate, changes)
+	return nil
+}
+
+// detectRoleCycle 校验同步后的上级角色链路不存在循环
+func detectRoleCycle(parents map[int64]int64) error {
+	for start := range parents {
+		visited := map[int64]struct{}{start: {}}
+		for current := parents[start]; current > 0; current = parents[current] {
+			if _, ok := visited[current]; ok {
+				return fmt.Errorf("%w: role hierarchy contains a cycle", ErrInvalidManifest)
+			}
+			visited[current] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func (s *syncer) syncDicts(dicts []Dict) error {
+	var types []model.SysDictType
+	if err := s.tx.Order("id ASC").Find(&types).Error; err != nil {
+		return err
+	}
+	byType := make(map[string]model.SysDictType, len(types))
+	for _, record := range types {
+		byType[record.DictType] = record
+	}
+
+	seen := make(map[string]struct{}, len(dicts))
+	for _, dict := range dicts {
+		seen[dict.Type] = struct{}{}
+		if err := s.syncDictType(dict, byType); err != nil {
+			return err
+		}
+		if err := s.syncDictItems(dict); err != nil {
+			return err
+		}
+	}
+
+	if !s.prune {
+		return nil
+	}
+	for _, record := range types {
+		if _, ok := seen[record.DictType]; ok {
+			continue
+		}
+		// 字典类型与其数据使用相同的删除时间，便于从回收站一并恢复
+		deleted := map[string]interface{}{"deleted_at": s.now, "update_by": s.operator, "updated_at": s.now}
+		if err := s.tx.Model(&model.SysDictData{}).Where("dict_type = ?", record.DictType).Updates(deleted).Error; err != nil {
+			return err
+		}
+		if err := s.tx.Model(&model.SysDictType{}).Where("id = ?", record.ID).Updates(deleted).Error; err != nil {
+			return err
+		}
+		touch(&s.dictTypes, int64(record.ID), &record)
+		s.record(KindDictType, record.DictType, ActionDelete, nil)
+	}
+	return nil
+}
+
+func (s *syncer) syncDictType(dict Dict, byType map[string]model.SysDictType) error {
+	desired := model.SysDictType{
+		DictName: dict.Name,
+		DictType: dict.Type,
+		Status:   defaultString(dict.Status, "0"),
+		Remark:   optionalString(dict.Remark),
+	}
+
+	current, ok := byType[dict.Type]
+	if !ok {
+		desired.CreateBy = s.operator
+		desired.UpdateBy = s.operator
+		if err := s.tx.Create(&desired).Error; err != nil {
+			return err
+		}
+		touch(&s.dictTypes, int64(desired.ID), nil)
+		s.record(KindDictType, dict.Type, ActionCreate, nil)
+		return nil
+	}
+
+	diff := newFieldDiff()
+	diff.compare("dict_name", current.DictName, desired.DictName)
+	diff.compare("status", current.Status, desired.Status)
+	diff.compareOptional("remark", current.Remark, desired.Remark)
+	if diff.empty() {
+		return nil
+	}
+	if err := s.tx.Model(&model.SysDictType{}).Where("id = ?", current.ID).Updates(s.stamp(diff.updates)).Error; err != nil {
+		return err
+	}
+	touch(&s.dictTypes, int64(current.ID), &current)
+	s.record(KindDictType, dict.Type, ActionUpdate, diff.fields)
+	return nil
+}
+
+func (s *syncer) syncDictItems(dict Dict) error {
+	var records []model.SysDictData
+	if err := s.tx.Where("dict_type = ?", dict.Type).Order("id ASC").Find(&records).Error; err != nil {
+		return err
+	}
+	byValue := make(map[string]model.SysDictData, len(records))
+	for _, record := range records {
+		if _, ok := byValue[record.DictValue]; !ok {
+			byValue[record.DictValue] = record
+		}
+	}
+
+	seen := make(map[string]struct{}, len(dict.Items))
+	for _, item := range dict.Items {
+		seen[item.Value] = struct{}{}
+		key := dict.Type + "/" + item.Value
+		desired := model.SysDictData{
+			DictSort:  item.Sort,
+			DictLabel: item.Label,
+			DictValue: item.Value,
+			DictType:  dict.Type,
+			CSSClass:  optionalString(item.CSSClass),
+			ListClass: optionalString(item.ListClass),
+			IsDefault: defaultString(item.IsDefault, "N"),
+			Status:    defaultString(item.Status, "0"),
+			Remark:    optionalString(item.Remark),
+		}
+
+		current, ok := byValue[item.Value]
+		if !ok {
+			desired.CreateBy = s.operator
+			desired.UpdateBy = s.operator
+			if err := s.tx.Create(&desired).Error; err != nil {
+				return err
+			}
+			touch(&s.dictData, int64(desired.ID), nil)
+			s.record(KindDictData, key, ActionCreate, nil)
+			continue
+		}
+
+		diff := newFieldDiff()
+		diff.compare("dict_sort", current.DictSort, desired.DictSort)
+		diff.compare("dict_label", current.DictLabel, desired.DictLabel)
+		diff.compareOptional("css_class", current.CSSClass, desired.CSSClass)
+		diff.compareOptional("list_class", current.ListClass, desired.ListClass)
+		diff.compare("is_default", current.IsDefault, desired.IsDefault)
+		diff.compare("status", current.Status, desired.Status)
+		diff.compareOptional("remark", current.Remark, desired.Remark)
+		if diff.empty() {
+			continue
+		}
+		if err := s.tx.Model(&model.SysDictData{}).Where("id = ?", current.ID).Updates(s.stamp(diff.updates)).Error; err != nil {
+			return err
+		}
+		touch(&s.dictData, int64(current.ID), &current)
+		s.record(KindDictData, key, ActionUpdate, diff.fields)
+	}
+
+	if err := s.syncDictParents(dict); err != nil {
+		return err
+	}
+
+	if !s.prune {
+		return nil
+	}
+	for _, record := range records {
+		if _, ok := seen[record.DictValue]; ok {
+			continue
+		}
+		deleted := map[string]interface{}{"deleted_at": s.now, "update_by": s.operator, "updated_at": s.now}
+		if err := s.tx.Model(&model.SysDictData{}).Where("id = ?", record.ID).Updates(deleted).Error; err != nil {
+			return err
+		}
+		touch(&s.dictData, int64(record.ID), &record)
+		s.record(KindDictData, dict.Type+"/"+record.DictValue, ActionDelete, nil)
+	}
+	return nil
+}
+
+// syncDictParents 全部字典数据就绪后再解析上级，清单中的数据可以引用排在后面的数据
+func (s *syncer) syncDictParents(dict Dict) error {
+	var records []model.SysDictData
+	if err := s.tx.Where("dict_type = ?", dict.Type).Order("id ASC").Find(&records).Error; err != nil {
+		return err
+	}
+	ids := make(map[string]int64, len(records))
+	parents := make(map[int64]int64, len(records))
+	byID := make(map[int64]*model.SysDictData, len(records))
+	for i, record := range records {
+		if _, ok := ids[record.DictValue]; !ok {
+			ids[record.DictValue] = int64(record.ID)
+		}
+		parents[int64(record.ID)] = record.ParentID
+		byID[int64(record.ID)] = &records[i]
+	}
+
+	declared := make(map[string]struct{}, len(dict.Items))
+	for _, item := range dict.Items {
+		declared[item.Value] = struct{}{}
+	}
+
+	for _, item := range dict.Items {
+		id := ids[item.Value]
+		var parentID int64
+		if parentValue := strings.TrimSpace(item.Parent); parentValue != "" {
+			resolved, ok := ids[parentValue]
+			// 裁剪模式下未在清单中声明的数据会被删除，不能作为上级
+			if _, inManifest := declared[parentValue]; !inManifest && s.prune {
+				ok = false
+			}
+			if !ok || resolved == id {
+				return fmt.Errorf("%w: dictionary %q item %q references unknown parent %q", ErrInvalidManifest, dict.Type, item.Value, parentValue)
+			}
+			parentID = resolved
+		}
+		if parents[id] == parentID {
+			continue
+		}
+		if err := s.tx.Model(&model.SysDictData{}).Where("id = ?", id).
+			Updates(s.stamp(map[string]interface{}{"parent_id": parentID})).Error; err != nil {
+			return err
+		}
+		touch(&s.dictData, id, byID[id])
+		parents[id] = parentID
+		s.record(KindDictData, dict.Type+"/"+item.Value, ActionUpdate, []string{"parent_id"})
+	}
+
+	for start := range parents {
+		visited := map[int64]struct{}{start: {}}
+		for current := parents[start]; current > 0; current = parents[current] {
+			if _, ok := visited[current]; ok {
+				return fmt.Errorf("%w: dictionary %q contains a parent cycle", ErrInvalidManifest, dict.Type)
+			}
+			visited[current] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func (s *syncer) syncConfigs(configs []Config) error {
+	var records []model.SysConfig
+	if err := s.tx.Order("id ASC").Find(&records).Error; err != nil {
+		return err
+	}
+	byKey := make(map[string]model.SysConfig, len(records))
+	for _, record := range records {
+		if _, ok := byKey[record.ConfigKey]; !ok {
+			byKey[record.ConfigKey] = record
+		}
+	}
+
+	seen := make(map[string]struct{}, len(configs))
+	for _, config := range configs {
+		seen[config.Key] = struct{}{}
+		desired := model.SysConfig{
+			ConfigName:   config.Name,
+			ConfigKey:    config.Key,
+			ConfigValue:  config.Value,
+			ConfigType:   defaultString(config.Type, "N"),
+			ValueType:    strings.ToLower(defaultString(config.ValueType, "string")),
+			ValueSchema:  optionalString(config.Schema),
+			DefaultValue: optionalString(config.Default),
+			IsPublic:     config.Public != nil && *config.Public,
+			Remark:       optionalString(config.Remark),
+		}
+		// secret 参数只能通过接口设置，清单中不携带密文，也不会写入明文
+		if desired.ValueType == "secret" {
+			desired.ConfigValue = ""
+		}
+
+		current, ok := byKey[config.Key]
+		if !ok {
+			desired.CreateBy = s.operator
+			desired.UpdateBy = s.operator
+			if err := s.tx.Create(&desired).Error; err != nil {
+				return err
+			}
+			touch(&s.configs, int64(desired.ID), nil)
+			s.record(KindConfig, config.Key, ActionCreate, nil)
+			continue
+		}
+
+		if strings.TrimSpace(config.ValueType) == "" && current.ValueType != sysconfig.ValueTypeSecret {
+			// 未声明值类型时沿用现有类型与约束，参数值需符合现有类型
+			if err := sysconfig.ValidateValue(sysconfig.ValueInput{
+				ValueType:    current.ValueType,
+				ValueSchema:  current.ValueSchema,
+				DefaultValue: current.DefaultValue,
+				ConfigValue:  desired.ConfigValue,
+			}); err != nil {
+				return fmt.Errorf("%w: config %q: %v", ErrInvalidManifest, config.Key, err)
+			}
+		}
+
+		diff := newFieldDiff()
+		diff.compare("config_name", current.ConfigName, desired.ConfigName)
+		keepSecret := current.ValueType == "secret" && (strings.TrimSpace(config.ValueType) == "" || desired.ValueType == "secret")
+		if !keepSecret {
+			diff.compare("config_value", current.ConfigValue, desired.ConfigValue)
+		}
+		diff.compare("config_type", current.ConfigType, desired.ConfigType)
+		if strings.TrimSpace(config.ValueType) != "" {
+			diff.compare("value_type", current.ValueType, desired.ValueType)
+			diff.compareOptional("value_schema", current.ValueSchema, desired.ValueSchema)
+			diff.compareOptional("default_value", current.DefaultValue, desired.DefaultValue)
+		}
+		if config.Public != nil {
+			diff.compare("is_public", current.IsPublic, desired.IsPublic)
+		}
+		diff.compareOptional("remark", current.Remark, desired.Remark)
+		if diff.empty() {
+			continue
+		}
+		if err := s.tx.Model(&model.SysConfig{}).Where("id = ?", current.ID).Updates(s.stamp(diff.updates)).Error; err != nil {
+			return err
+		}
+		touch(&s.configs, int64(current.ID), &current)
+		s.record(KindConfig, config.Key, ActionUpdate, diff.fields)
+	}
+
+	if !s.prune {
+		return nil
+	}
+	for _, key := range sortedKeys(byKey) {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		record := byKey[key]
+		if err := s.tx.Model(&model.SysConfig{}).Where("id = ?", record.ID).Updates(s.stamp(map[string]interface{}{})).Error; err != nil {
+			return err
+		}
+		if err := s.tx.Delete(&model.SysConfig{}, record.ID).Error; err != nil {
+			return err
+		}
+		touch(&s.configs, int64(record.ID), &record)
+		s.record(KindConfig, key, ActionDelete, nil)
+	}
+	return nil
+}
+
+// fieldDiff 收集需要更新的字段
+type fieldDiff struct {
+	updates map[string]interface{}
+	fields  []string
+}
+
+func newFieldDiff() *fieldDiff {
+	return &fieldDiff{updates: make(map[string]interface{})}
+}
+
+func (d *fieldDiff) compare(column string, current, desired interface{}) {
+	if current == desired {
+		return
+	}
+	d.updates[column] = desired
+	d.fields = append(d.fields, column)
+}
+
+func (d *fieldDiff) compareOptional(column string, current, desired *string) {
+	if strings.TrimSpace(derefString(current)) == derefString(desired) {
+		return
+	}
+	if desired == nil {
+		d.updates[column] = nil
+	} else {
+		d.updates[column] = *desired
+	}
+	d.fields = append(d.fields, column)
+}
+
+func (d *fieldDiff) empty() bool {
+	return len(d.fields) == 0
+}
+
+func sortedKeys[T any](items map[string]T) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func defaultString(value, fallback string) string {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return fallback
+	}
+	return trimmed
+}
@@ -9,6 +9,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 	"github.com/starter-kit-fe/admin/pkg/resp"
 )
 
@@ -106,6 +107,7 @@ func (h *Handler) Tree(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "菜单ID"
 // @Success 200 {object} resp.Response
+// @Header 200 {string} ETag "菜单版本，修改或删除时通过 If-Match 回传"
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 500 {object} resp.Response
@@ -133,6 +135,7 @@ func (h *Handler) Get(ctx *gin.Context) {
 		return
 	}
 
+	etag.Set(ctx, *menu.UpdatedAt)
 	resp.OK(ctx, resp.WithData(menu))
 }
 
@@ -210,9 +213,12 @@ func (h *Handler) Create(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "菜单ID"
 // @Param request body updateMenuRequest true "菜单参数"
+// @Param If-Match header string true "获取菜单时返回的 ETag"
 // @Success 200 {object} resp.Response
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/menus/{id} [put]
@@ -234,6 +240,11 @@ func (h *Handler) Update(ctx *gin.Context) {
 		return
 	}
 
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
 	operator := resolveOperator(ctx)
 	menu, err := h.service.UpdateMenu(ctx.Request.Context(), UpdateMenuInput{
 		ID:       id,
@@ -256,6 +267,8 @@ func (h *Handler) Update(ctx *gin.Context) {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("menu not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("menu has been modified, reload and retry"))
 		case errors.Is(err, ErrMenuNameRequired):
 			resp.BadRequest(ctx, resp.WithMessage("menu name is required"))
 		case errors.Is(err, ErrInvalidMenuType):
@@ -284,9 +297,12 @@ func (h *Handler) Update(ctx *gin.Context) {
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "菜单ID"
+// @Param If-Match header string true "获取菜单时返回的 ETag"
 // @Success 204 {object} nil
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/menus/{id} [delete]
@@ -302,11 +318,20 @@ func (h *Handler) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteMenu(ctx.Request.Context(), id); err != nil {
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	if err := h.service.DeleteMenu(ctx.Request.Context(), id, resolveOperator(ctx)); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			resp.NotFound(ctx, resp.WithMessage("menu not found"))
 			return
 		}
+		if errors.Is(err, etag.ErrPreconditionFailed) {
+			resp.PreconditionFailed(ctx, resp.WithMessage("menu has been modified, reload and retry"))
+			return
+		}
 		resp.InternalServerError(ctx, resp.WithMessage("failed to delete menu"))
 		return
 	}
@@ -9,6 +9,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 )
 
 var (
@@ -115,15 +116,13 @@ func (r *Repository) UpdateMenu(ctx context.Context, id int64, updates map[strin
 		return nil
 	}
 
-	result := r.db.WithContext(ctx).
-		Model(&model.SysMenu{}).
-		Where("id = ?", id).
-		Updates(updates)
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Model(&model.SysMenu{}).Where("id = ?", id)).Updates(updates)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+		return etag.Missed(db, &model.SysMenu{}, id)
 	}
 	return nil
 }
@@ -136,14 +135,13 @@ func (r *Repository) DeleteMenu(ctx context.Context, id int64) error {
 		return gorm.ErrRecordNotFound
 	}
 
-	result := r.db.WithContext(ctx).
-		Where("id = ?", id).
-		Delete(&model.SysMenu{})
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Where("id = ?", id)).Delete(&model.SysMenu{})
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+		return etag.Missed(db, &model.SysMenu{}, id)
 	}
 	return nil
 }
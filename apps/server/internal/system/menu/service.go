@@ -9,6 +9,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/history"
 )
 
 var (
@@ -31,14 +32,15 @@ var (
 )
 
 type Service struct {
-	repo *Repository
+	repo    *Repository
+	history *history.Service
 }
 
-func NewService(repo *Repository) *Service {
+func NewService(repo *Repository, history *history.Service) *Service {
 	if repo == nil {
 		return nil
 	}
-	return &Service{repo: repo}
+	return &Service{repo: repo, history: history}
 }
 
 type QueryOptions struct {
@@ -47,21 +49,22 @@ type QueryOptions struct {
 }
 
 type Menu struct {
-	ID       int64   `json:"id"`
-	MenuName string  `json:"menuName"`
-	ParentID int64   `json:"parentId"`
-	OrderNum int     `json:"orderNum"`
-	Path     string  `json:"path"`
-	Query    *string `json:"query,omitempty"`
-	IsFrame  bool    `json:"isFrame"`
-	IsCache  bool    `json:"isCache"`
-	MenuType string  `json:"menuType"`
-	Visible  string  `json:"visible"`
-	Status   string  `json:"status"`
-	Perms    *string `json:"perms,omitempty"`
-	Icon     string  `json:"icon"`
-	Remark   string  `json:"remark"`
-	Children []*Menu `json:"children,omitempty"`
+	ID        int64      `json:"id"`
+	MenuName  string     `json:"menuName"`
+	ParentID  int64      `json:"parentId"`
+	OrderNum  int        `json:"orderNum"`
+	Path      string     `json:"path"`
+	Query     *string    `json:"query,omitempty"`
+	IsFrame   bool       `json:"isFrame"`
+	IsCache   bool       `json:"isCache"`
+	MenuType  string     `json:"menuType"`
+	Visible   string     `json:"visible"`
+	Status    string     `json:"status"`
+	Perms     *string    `json:"perms,omitempty"`
+	Icon      string     `json:"icon"`
+	Remark    string     `json:"remark"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	Children  []*Menu    `json:"children,omitempty"`
 }
 
 type CreateMenuInput struct {
@@ -233,7 +236,15 @@ func (s *Service) CreateMenu(ctx context.Context, input CreateMenuInput) (*Menu,
 	if err != nil {
 		return nil, err
 	}
-	return menuFromModel(created), nil
+	menu := menuFromModel(created)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleMenu,
+		EntityID: menu.ID,
+		Action:   history.ActionCreate,
+		Operator: operator,
+		After:    menu,
+	})
+	return menu, nil
 }
 
 func (s *Service) UpdateMenu(ctx context.Context, input UpdateMenuInput) (*Menu, error) {
@@ -375,6 +386,11 @@ func (s *Service) UpdateMenu(ctx context.Context, input UpdateMenuInput) (*Menu,
 	updates["update_by"] = operator
 	updates["updated_at"] = time.Now()
 
+	var before *Menu
+	if s.history != nil {
+		before, _ = s.GetMenu(ctx, input.ID)
+	}
+
 	if err := s.repo.UpdateMenu(ctx, input.ID, updates); err != nil {
 		return nil, err
 	}
@@ -383,14 +399,39 @@ func (s *Service) UpdateMenu(ctx context.Context, input UpdateMenuInput) (*Menu,
 	if err != nil {
 		return nil, err
 	}
-	return menuFromModel(updated), nil
+	menu := menuFromModel(updated)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleMenu,
+		EntityID: input.ID,
+		Action:   history.ActionUpdate,
+		Operator: operator,
+		Before:   before,
+		After:    menu,
+	})
+	return menu, nil
 }
 
-func (s *Service) DeleteMenu(ctx context.Context, id int64) error {
+func (s *Service) DeleteMenu(ctx context.Context, id int64, operator string) error {
 	if s == nil || s.repo == nil {
 		return ErrServiceUnavailable
 	}
-	return s.repo.DeleteMenu(ctx, id)
+
+	var before *Menu
+	if s.history != nil {
+		before, _ = s.GetMenu(ctx, id)
+	}
+
+	if err := s.repo.DeleteMenu(ctx, id); err != nil {
+		return err
+	}
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleMenu,
+		EntityID: id,
+		Action:   history.ActionDelete,
+		Operator: strings.TrimSpace(operator),
+		Before:   before,
+	})
+	return nil
 }
 
 func (s *Service) ReorderMenus(ctx context.Context, input ReorderMenusInput) error {
@@ -428,20 +469,21 @@ func menuFromModel(menu *model.SysMenu) *Menu {
 		return nil
 	}
 	result := &Menu{
-		ID:       int64(menu.ID),
-		MenuName: menu.MenuName,
-		ParentID: menu.ParentID,
-		OrderNum: menu.OrderNum,
-		Path:     menu.Path,
-		Query:    menu.Query,
-		IsFrame:  menu.IsFrame,
-		IsCache:  menu.IsCache,
-		MenuType: menu.MenuType,
-		Visible:  menu.Visible,
-		Status:   menu.Status,
-		Perms:    menu.Perms,
-		Icon:     menu.Icon,
-		Remark:   menu.Remark,
+		ID:        int64(menu.ID),
+		MenuName:  menu.MenuName,
+		ParentID:  menu.ParentID,
+		OrderNum:  menu.OrderNum,
+		Path:      menu.Path,
+		Query:     menu.Query,
+		IsFrame:   menu.IsFrame,
+		IsCache:   menu.IsCache,
+		MenuType:  menu.MenuType,
+		Visible:   menu.Visible,
+		Status:    menu.Status,
+		Perms:     menu.Perms,
+		Icon:      menu.Icon,
+		Remark:    menu.Remark,
+		UpdatedAt: &menu.UpdatedAt,
 	}
 	return result
 }
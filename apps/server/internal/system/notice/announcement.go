@@ -0,0 +1,46 @@
+package notice
+
+import (
+	"context"
+	"time"
+)
+
+// Announcement 登录页展示的公告，仅包含面向全员且处于发布窗口内的公告
+type Announcement struct {
+	NoticeID      int64      `json:"noticeId"`
+	NoticeTitle   string     `json:"noticeTitle"`
+	NoticeType    string     `json:"noticeType"`
+	NoticeContent string     `json:"noticeContent"`
+	Pinned        bool       `json:"pinned"`
+	Priority      int        `json:"priority"`
+	PublishedAt   time.Time  `json:"publishedAt"`
+	ExpireAt      *time.Time `json:"expireAt,omitempty"`
+}
+
+// ActiveAnnouncements 返回当前有效的全员公告，置顶与高优先级在前
+func (s *Service) ActiveAnnouncements(ctx context.Context) ([]Announcement, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	records, err := s.repo.ListActiveNotices(ctx, time.Now(), AudienceAll)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Announcement, 0, len(records))
+	for i := range records {
+		record := &records[i]
+		result = append(result, Announcement{
+			NoticeID:      int64(record.ID),
+			NoticeTitle:   record.NoticeTitle,
+			NoticeType:    record.NoticeType,
+			NoticeContent: string(record.NoticeContent),
+			Pinned:        record.Pinned,
+			Priority:      record.Priority,
+			PublishedAt:   publishedAt(record),
+			ExpireAt:      record.ExpireAt,
+		})
+	}
+	return result, nil
+}
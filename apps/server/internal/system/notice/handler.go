@@ -8,7 +8,9 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 	"github.com/starter-kit-fe/admin/pkg/resp"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
 )
 
 type listNoticesQuery struct {
@@ -22,15 +24,37 @@ type createNoticeRequest struct {
 	NoticeType    string  `json:"noticeType" binding:"required"`
 	NoticeContent string  `json:"noticeContent" binding:"required"`
 	Status        string  `json:"status"`
+	AudienceType  string  `json:"audienceType"`
+	TargetIDs     []int64 `json:"targetIds"`
+	PublishAt     string  `json:"publishAt"`
+	ExpireAt      string  `json:"expireAt"`
+	Pinned        bool    `json:"pinned"`
+	Priority      int     `json:"priority"`
 	Remark        *string `json:"remark"`
 }
 
 type updateNoticeRequest struct {
-	NoticeTitle   *string `json:"noticeTitle"`
-	NoticeType    *string `json:"noticeType"`
-	NoticeContent *string `json:"noticeContent"`
-	Status        *string `json:"status"`
-	Remark        *string `json:"remark"`
+	NoticeTitle   *string  `json:"noticeTitle"`
+	NoticeType    *string  `json:"noticeType"`
+	NoticeContent *string  `json:"noticeContent"`
+	Status        *string  `json:"status"`
+	AudienceType  *string  `json:"audienceType"`
+	TargetIDs     *[]int64 `json:"targetIds"`
+	PublishAt     *string  `json:"publishAt"`
+	ExpireAt      *string  `json:"expireAt"`
+	Pinned        *bool    `json:"pinned"`
+	Priority      *int     `json:"priority"`
+	Remark        *string  `json:"remark"`
+}
+
+type publicNoticesQuery struct {
+	Tenant string `form:"tenant"`
+}
+
+type inboxQuery struct {
+	UnreadOnly bool `form:"unreadOnly"`
+	PageNum    int  `form:"pageNum"`
+	PageSize   int  `form:"pageSize"`
 }
 
 type Handler struct {
@@ -46,7 +70,7 @@ func NewHandler(service *Service) *Handler {
 
 // List godoc
 // @Summary 获取通知公告列表
-// @Description 按标题、类型、状态过滤公告
+// @Description 按标题、类型、状态过滤公告，置顶与高优先级在前
 // @Tags System/Notice
 // @Security BearerAuth
 // @Produce json
@@ -91,6 +115,7 @@ func (h *Handler) List(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "公告ID"
 // @Success 200 {object} resp.Response
+// @Header 200 {string} ETag "公告版本，修改或删除时通过 If-Match 回传"
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 500 {object} resp.Response
@@ -118,12 +143,14 @@ func (h *Handler) Get(ctx *gin.Context) {
 		return
 	}
 
+	etag.Set(ctx, *item.UpdatedAt)
 	resp.OK(ctx, resp.WithData(item))
 }
 
 // Create godoc
 // @Summary 新增通知公告
-// @Description 发布公告
+// @Description 发布公告；audienceType 为 all（默认）、users、roles 或 depts，非全员时 targetIds 为对应的用户、角色或部门ID，部门包含其下级。
+// @Description publishAt、expireAt 为 RFC 3339 时间，未到发布时间的公告状态为待发布，超过过期时间为已过期
 // @Tags System/Notice
 // @Security BearerAuth
 // @Accept json
@@ -152,6 +179,12 @@ func (h *Handler) Create(ctx *gin.Context) {
 		NoticeType:    payload.NoticeType,
 		NoticeContent: payload.NoticeContent,
 		Status:        payload.Status,
+		AudienceType:  payload.AudienceType,
+		TargetIDs:     payload.TargetIDs,
+		PublishAt:     payload.PublishAt,
+		ExpireAt:      payload.ExpireAt,
+		Pinned:        payload.Pinned,
+		Priority:      payload.Priority,
 		Remark:        payload.Remark,
 		Operator:      operator,
 	})
@@ -161,7 +194,11 @@ func (h *Handler) Create(ctx *gin.Context) {
 			errors.Is(err, ErrTypeRequired),
 			errors.Is(err, ErrContentRequired),
 			errors.Is(err, ErrInvalidType),
-			errors.Is(err, ErrInvalidStatus):
+			errors.Is(err, ErrInvalidStatus),
+			errors.Is(err, ErrInvalidAudience),
+			errors.Is(err, ErrTargetsRequired),
+			errors.Is(err, ErrTargetNotFound),
+			errors.Is(err, ErrInvalidSchedule):
 			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
 		default:
 			resp.InternalServerError(ctx, resp.WithMessage("failed to create notice"))
@@ -174,16 +211,19 @@ func (h *Handler) Create(ctx *gin.Context) {
 
 // Update godoc
 // @Summary 修改通知公告
-// @Description 更新公告信息
+// @Description 更新公告信息，publishAt、expireAt 传空字符串时清除；修改发布时间窗口后重新推导待发布、已过期状态
 // @Tags System/Notice
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path int true "公告ID"
 // @Param request body updateNoticeRequest true "公告参数"
+// @Param If-Match header string true "获取公告时返回的 ETag"
 // @Success 200 {object} resp.Response
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/notices/{id} [put]
@@ -205,6 +245,11 @@ func (h *Handler) Update(ctx *gin.Context) {
 		return
 	}
 
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
 	operator := resolveOperator(ctx)
 	item, err := h.service.UpdateNotice(ctx.Request.Context(), UpdateNoticeInput{
 		ID:            id,
@@ -212,6 +257,12 @@ func (h *Handler) Update(ctx *gin.Context) {
 		NoticeType:    payload.NoticeType,
 		NoticeContent: payload.NoticeContent,
 		Status:        payload.Status,
+		AudienceType:  payload.AudienceType,
+		TargetIDs:     payload.TargetIDs,
+		PublishAt:     payload.PublishAt,
+		ExpireAt:      payload.ExpireAt,
+		Pinned:        payload.Pinned,
+		Priority:      payload.Priority,
 		Remark:        payload.Remark,
 		Operator:      operator,
 	})
@@ -219,11 +270,17 @@ func (h *Handler) Update(ctx *gin.Context) {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("notice not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("notice has been modified, reload and retry"))
 		case errors.Is(err, ErrTitleRequired),
 			errors.Is(err, ErrTypeRequired),
 			errors.Is(err, ErrContentRequired),
 			errors.Is(err, ErrInvalidType),
-			errors.Is(err, ErrInvalidStatus):
+			errors.Is(err, ErrInvalidStatus),
+			errors.Is(err, ErrInvalidAudience),
+			errors.Is(err, ErrTargetsRequired),
+			errors.Is(err, ErrTargetNotFound),
+			errors.Is(err, ErrInvalidSchedule):
 			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
 		default:
 			resp.InternalServerError(ctx, resp.WithMessage("failed to update notice"))
@@ -241,9 +298,12 @@ func (h *Handler) Update(ctx *gin.Context) {
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "公告ID"
+// @Param If-Match header string true "获取公告时返回的 ETag"
 // @Success 204 {object} nil
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/notices/{id} [delete]
@@ -259,11 +319,20 @@ func (h *Handler) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteNotice(ctx.Request.Context(), id); err != nil {
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	if err := h.service.DeleteNotice(ctx.Request.Context(), id, resolveOperator(ctx)); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			resp.NotFound(ctx, resp.WithMessage("notice not found"))
 			return
 		}
+		if errors.Is(err, etag.ErrPreconditionFailed) {
+			resp.PreconditionFailed(ctx, resp.WithMessage("notice has been modified, reload and retry"))
+			return
+		}
 		resp.InternalServerError(ctx, resp.WithMessage("failed to delete notice"))
 		return
 	}
@@ -271,6 +340,242 @@ func (h *Handler) Delete(ctx *gin.Context) {
 	resp.NoContent(ctx)
 }
 
+// ReadStats godoc
+// @Summary 获取公告阅读统计
+// @Description 按公告当前接收范围统计状态正常用户的已读与未读情况，并列出已读用户与未读用户
+// @Tags System/Notice
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "公告ID"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/notices/{id}/reads [get]
+func (h *Handler) ReadStats(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("notice service unavailable"))
+		return
+	}
+
+	id, err := parseNoticeID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid notice id"))
+		return
+	}
+
+	stats, err := h.service.ReadStats(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.NotFound(ctx, resp.WithMessage("notice not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load notice read statistics"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(stats))
+}
+
+// Inbox godoc
+// @Summary 获取我的公告
+// @Description 分页返回当前用户接收范围内的已发布公告及已读状态，未读数量见 /v1/notices/unread-count；登录即可访问
+// @Tags Notice
+// @Security BearerAuth
+// @Produce json
+// @Param unreadOnly query bool false "仅返回未读公告"
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "每页数量，默认 10，最大 100"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 401 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/notices/inbox [get]
+func (h *Handler) Inbox(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("notice service unavailable"))
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		resp.Unauthorized(ctx, resp.WithMessage("invalid token"))
+		return
+	}
+
+	var query inboxQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	inbox, err := h.service.Inbox(ctx.Request.Context(), int64(userID), InboxOptions{
+		UnreadOnly: query.UnreadOnly,
+		PageNum:    query.PageNum,
+		PageSize:   query.PageSize,
+	})
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load notices"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(inbox))
+}
+
+// UnreadCount godoc
+// @Summary 获取我的未读公告数量
+// @Description 返回当前用户接收范围内、处于发布窗口的未读公告数量，供角标轮询；登录即可访问
+// @Tags Notice
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} resp.Response
+// @Failure 401 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/notices/unread-count [get]
+func (h *Handler) UnreadCount(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("notice service unavailable"))
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		resp.Unauthorized(ctx, resp.WithMessage("invalid token"))
+		return
+	}
+
+	count, err := h.service.UnreadCount(ctx.Request.Context(), int64(userID))
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to count unread notices"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(gin.H{"unreadCount": count}))
+}
+
+// MarkRead godoc
+// @Summary 标记公告已读
+// @Description 将当前用户收到的公告标记为已读，重复标记保留首次阅读时间
+// @Tags Notice
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "公告ID"
+// @Success 204 {object} nil
+// @Failure 400 {object} resp.Response
+// @Failure 401 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/notices/{id}/read [post]
+func (h *Handler) MarkRead(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("notice service unavailable"))
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		resp.Unauthorized(ctx, resp.WithMessage("invalid token"))
+		return
+	}
+
+	id, err := parseNoticeID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid notice id"))
+		return
+	}
+
+	if err := h.service.MarkRead(ctx.Request.Context(), int64(userID), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.NotFound(ctx, resp.WithMessage("notice not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to mark notice as read"))
+		return
+	}
+
+	resp.NoContent(ctx)
+}
+
+// MarkAllRead godoc
+// @Summary 全部标记已读
+// @Description 将当前用户收到的全部公告标记为已读，返回本次标记的数量
+// @Tags Notice
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} resp.Response
+// @Failure 401 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/notices/read-all [post]
+func (h *Handler) MarkAllRead(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("notice service unavailable"))
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		resp.Unauthorized(ctx, resp.WithMessage("invalid token"))
+		return
+	}
+
+	marked, err := h.service.MarkAllRead(ctx.Request.Context(), int64(userID))
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to mark notices as read"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(gin.H{"marked": marked}))
+}
+
+// Public godoc
+// @Summary 获取登录页公告
+// @Description 返回面向全员且处于发布窗口内的公告，置顶与高优先级在前，无需登录
+// @Tags Notice
+// @Produce json
+// @Param tenant query string false "租户编码，默认为平台租户"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/public/notices [get]
+func (h *Handler) Public(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("notice service unavailable"))
+		return
+	}
+
+	var query publicNoticesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	tenantID, err := h.service.ResolveTenant(ctx.Request.Context(), query.Tenant)
+	if err != nil {
+		if errors.Is(err, ErrTenantUnavailable) {
+			resp.NotFound(ctx, resp.WithMessage("tenant not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load notices"))
+		return
+	}
+
+	items, err := h.service.ActiveAnnouncements(tenant.WithID(ctx.Request.Context(), tenantID))
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load notices"))
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=60")
+	resp.OK(ctx, resp.WithData(items))
+}
+
 func parseNoticeID(value string) (int64, error) {
 	return strconv.ParseInt(value, 10, 64)
 }
@@ -0,0 +1,285 @@
+package notice
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+// InboxItem 当前用户收到的公告
+type InboxItem struct {
+	NoticeID      int64      `json:"noticeId"`
+	NoticeTitle   string     `json:"noticeTitle"`
+	NoticeType    string     `json:"noticeType"`
+	NoticeContent string     `json:"noticeContent"`
+	Pinned        bool       `json:"pinned"`
+	Priority      int        `json:"priority"`
+	CreateBy      string     `json:"createBy"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	PublishedAt   time.Time  `json:"publishedAt"`
+	Read          bool       `json:"read"`
+	ReadAt        *time.Time `json:"readAt,omitempty"`
+}
+
+type Inbox struct {
+	Items    []InboxItem `json:"items"`
+	Total    int64       `json:"total"`
+	PageNum  int         `json:"pageNum"`
+	PageSize int         `json:"pageSize"`
+}
+
+type InboxOptions struct {
+	// UnreadOnly 仅返回未读公告
+	UnreadOnly bool
+	PageNum    int
+	PageSize   int
+}
+
+// 收件箱每页数量，未指定时使用默认值，超过上限时按上限返回
+const (
+	defaultInboxPageSize = 10
+	maxInboxPageSize     = 100
+)
+
+// Recipient 公告接收人及其阅读时间
+type Recipient struct {
+	UserID   int64      `json:"userId"`
+	UserName string     `json:"userName"`
+	NickName string     `json:"nickName"`
+	DeptID   *int64     `json:"deptId,omitempty"`
+	ReadAt   *time.Time `json:"readAt,omitempty"`
+}
+
+// ReadStats 公告的阅读统计，按当前接收范围内状态正常的用户计算
+type ReadStats struct {
+	NoticeID     int64       `json:"noticeId"`
+	AudienceType string      `json:"audienceType"`
+	Total        int         `json:"total"`
+	ReadCount    int         `json:"readCount"`
+	UnreadCount  int         `json:"unreadCount"`
+	Readers      []Recipient `json:"readers"`
+	UnreadUsers  []Recipient `json:"unreadUsers"`
+}
+
+// audience 用户用于匹配公告接收范围的身份，deptIDs 含所在部门的全部上级部门
+type audience struct {
+	userID  int64
+	roleIDs []int64
+	deptIDs []int64
+}
+
+// Inbox 分页返回当前用户接收范围内、处于发布窗口的公告及已读状态，接收范围在数据库中匹配
+func (s *Service) Inbox(ctx context.Context, userID int64, opts InboxOptions) (*Inbox, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	if opts.PageNum <= 0 {
+		opts.PageNum = 1
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultInboxPageSize
+	}
+	if opts.PageSize > maxInboxPageSize {
+		opts.PageSize = maxInboxPageSize
+	}
+
+	member, err := s.resolveAudience(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	records, total, err := s.repo.ListInbox(ctx, member, time.Now(), opts)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(records))
+	for i := range records {
+		ids = append(ids, int64(records[i].ID))
+	}
+	reads, err := s.repo.ListReads(ctx, ids, []int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	readAt := make(map[int64]time.Time, len(reads))
+	for _, read := range reads {
+		readAt[read.NoticeID] = read.ReadAt
+	}
+
+	inbox := &Inbox{
+		Items:    make([]InboxItem, 0, len(records)),
+		Total:    total,
+		PageNum:  opts.PageNum,
+		PageSize: opts.PageSize,
+	}
+	for i := range records {
+		record := &records[i]
+		item := InboxItem{
+			NoticeID:      int64(record.ID),
+			NoticeTitle:   record.NoticeTitle,
+			NoticeType:    record.NoticeType,
+			NoticeContent: string(record.NoticeContent),
+			Pinned:        record.Pinned,
+			Priority:      record.Priority,
+			CreateBy:      record.CreateBy,
+			CreatedAt:     record.CreatedAt,
+			PublishedAt:   publishedAt(record),
+		}
+		if at, ok := readAt[item.NoticeID]; ok {
+			item.Read = true
+			item.ReadAt = &at
+		}
+		inbox.Items = append(inbox.Items, item)
+	}
+	return inbox, nil
+}
+
+// UnreadCount 返回当前用户接收范围内、处于发布窗口的未读公告数量
+func (s *Service) UnreadCount(ctx context.Context, userID int64) (int64, error) {
+	if s == nil || s.repo == nil {
+		return 0, ErrServiceUnavailable
+	}
+
+	member, err := s.resolveAudience(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.CountUnread(ctx, member, time.Now())
+}
+
+// MarkRead 将当前用户可见的公告标记为已读，不可见的公告按不存在处理
+func (s *Service) MarkRead(ctx context.Context, userID, noticeID int64) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+
+	member, err := s.resolveAudience(ctx, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	visible, err := s.repo.IsVisible(ctx, member, now, noticeID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return gorm.ErrRecordNotFound
+	}
+	return s.repo.MarkRead(ctx, userID, []int64{noticeID}, now)
+}
+
+// MarkAllRead 将当前用户可见的全部公告标记为已读，返回新标记的数量
+func (s *Service) MarkAllRead(ctx context.Context, userID int64) (int, error) {
+	if s == nil || s.repo == nil {
+		return 0, ErrServiceUnavailable
+	}
+
+	member, err := s.resolveAudience(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	ids, err := s.repo.ListUnreadIDs(ctx, member, now)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.repo.MarkRead(ctx, userID, ids, now); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// ReadStats 返回公告接收人的已读与未读情况，供发布人查看
+func (s *Service) ReadStats(ctx context.Context, noticeID int64) (*ReadStats, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	record, err := s.repo.GetNotice(ctx, noticeID)
+	if err != nil {
+		return nil, err
+	}
+	audienceType := normalizeAudienceType(record.AudienceType)
+	targets, err := s.repo.ListTargets(ctx, []int64{noticeID})
+	if err != nil {
+		return nil, err
+	}
+	targetIDs := targets[noticeID]
+
+	var roleMembers []int64
+	if audienceType == AudienceRoles && s.grants != nil {
+		roleMembers, err = s.grants.ListRoleMemberIDs(ctx, targetIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	users, err := s.repo.ListAudienceUsers(ctx, audienceType, targetIDs, roleMembers)
+	if err != nil {
+		return nil, err
+	}
+	reads, err := s.repo.ListReads(ctx, []int64{noticeID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	readAt := make(map[int64]time.Time, len(reads))
+	for _, read := range reads {
+		readAt[read.UserID] = read.ReadAt
+	}
+
+	stats := &ReadStats{
+		NoticeID:     noticeID,
+		AudienceType: audienceType,
+		Total:        len(users),
+		Readers:      make([]Recipient, 0),
+		UnreadUsers:  make([]Recipient, 0),
+	}
+	for i := range users {
+		user := &users[i]
+		recipient := Recipient{
+			UserID:   int64(user.ID),
+			UserName: user.UserName,
+			NickName: user.NickName,
+			DeptID:   user.DeptID,
+		}
+		if at, ok := readAt[recipient.UserID]; ok {
+			recipient.ReadAt = &at
+			stats.Readers = append(stats.Readers, recipient)
+		} else {
+			stats.UnreadUsers = append(stats.UnreadUsers, recipient)
+		}
+	}
+	stats.ReadCount = len(stats.Readers)
+	stats.UnreadCount = len(stats.UnreadUsers)
+	return stats, nil
+}
+
+func (s *Service) resolveAudience(ctx context.Context, userID int64) (*audience, error) {
+	member := &audience{userID: userID}
+
+	if s.grants != nil {
+		grants, err := s.grants.ResolveRoleGrants(ctx, uint(userID))
+		if err != nil {
+			return nil, err
+		}
+		for _, grant := range grants {
+			member.roleIDs = append(member.roleIDs, grant.RoleID)
+		}
+	}
+
+	depts, err := s.repo.GetUserDept(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	member.deptIDs = depts
+	return member, nil
+}
+
+// publishedAt 返回公告的生效时间，未设置定时发布时为创建时间
+func publishedAt(record *model.SysNotice) time.Time {
+	if record.PublishAt != nil {
+		return *record.PublishAt
+	}
+	return record.CreatedAt
+}
@@ -3,11 +3,16 @@ package notice
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 )
 
 var (
@@ -51,12 +56,173 @@ func (r *Repository) ListNotices(ctx context.Context, opts ListOptions) ([]model
 	}
 
 	var records []model.SysNotice
-	if err := query.Order("id DESC").Find(&records).Error; err != nil {
+	if err := query.Order(displayOrder).Find(&records).Error; err != nil {
 		return nil, err
 	}
 	return records, nil
 }
 
+// displayOrder 置顶优先，其次按优先级、发布时间倒序
+const displayOrder = "pinned DESC, priority DESC, COALESCE(publish_at, created_at) DESC, id DESC"
+
+// ListActiveNotices 返回 now 时处于发布窗口内的公告，audienceType 非空时只返回该接收范围的公告。
+// 待发布状态的公告到达发布时间即可见，不依赖定时任务先行更新状态
+func (r *Repository) ListActiveNotices(ctx context.Context, now time.Time, audienceType string) ([]model.SysNotice, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	query := r.activeQuery(ctx, now)
+	if audienceType != "" {
+		query = query.Where("audience_type = ?", audienceType)
+	}
+
+	var records []model.SysNotice
+	if err := query.Order(displayOrder).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// ListInbox 分页返回用户接收范围内处于发布窗口的公告，pageSize 为 0 时不分页
+func (r *Repository) ListInbox(ctx context.Context, member *audience, now time.Time, opts InboxOptions) ([]model.SysNotice, int64, error) {
+	if r == nil || r.db == nil {
+		return nil, 0, ErrRepositoryUnavailable
+	}
+
+	base := r.inboxQuery(ctx, member, now)
+	if opts.UnreadOnly {
+		base = r.unread(ctx, base, member.userID)
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []model.SysNotice{}, 0, nil
+	}
+
+	dataQuery := base.Session(&gorm.Session{}).Order(displayOrder)
+	if opts.PageSize > 0 {
+		dataQuery = dataQuery.Offset((opts.PageNum - 1) * opts.PageSize).Limit(opts.PageSize)
+	}
+	var records []model.SysNotice
+	if err := dataQuery.Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+// CountUnread 返回用户接收范围内处于发布窗口且未读的公告数量
+func (r *Repository) CountUnread(ctx context.Context, member *audience, now time.Time) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, ErrRepositoryUnavailable
+	}
+	var count int64
+	err := r.unread(ctx, r.inboxQuery(ctx, member, now), member.userID).Count(&count).Error
+	return count, err
+}
+
+// ListUnreadIDs 返回用户接收范围内处于发布窗口且未读的公告 ID
+func (r *Repository) ListUnreadIDs(ctx context.Context, member *audience, now time.Time) ([]int64, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	var ids []int64
+	err := r.unread(ctx, r.inboxQuery(ctx, member, now), member.userID).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// IsVisible 判断公告是否处于发布窗口且在用户的接收范围内
+func (r *Repository) IsVisible(ctx context.Context, member *audience, now time.Time, noticeID int64) (bool, error) {
+	if r == nil || r.db == nil {
+		return false, ErrRepositoryUnavailable
+	}
+	var count int64
+	if err := r.inboxQuery(ctx, member, now).Where("id = ?", noticeID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// activeQuery 返回 now 时处于发布窗口内的公告查询
+func (r *Repository) activeQuery(ctx context.Context, now time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.SysNotice{}).
+		Where("status IN ?", []string{statusPublished, statusScheduled}).
+		Where("publish_at IS NULL OR publish_at <= ?", now).
+		Where("expire_at IS NULL OR expire_at > ?", now)
+}
+
+// inboxQuery 在 activeQuery 基础上按接收对象表匹配用户、角色与部门，接收范围为空的历史数据视为全员
+func (r *Repository) inboxQuery(ctx context.Context, member *audience, now time.Time) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	targeted := func(audienceType string, ids []int64) *gorm.DB {
+		targets := db.Model(&model.SysNoticeTarget{}).
+			Select("1").
+			Where("notice_id = "+model.SysNotice{}.TableName()+".id AND target_id IN ?", nonEmpty(ids))
+		return db.Where("audience_type = ? AND EXISTS (?)", audienceType, targets)
+	}
+	scope := db.Where("audience_type IN ?", []string{AudienceAll, ""}).
+		Or(targeted(AudienceUsers, []int64{member.userID})).
+		Or(targeted(AudienceRoles, member.roleIDs)).
+		Or(targeted(AudienceDepts, member.deptIDs))
+	return r.activeQuery(ctx, now).Where(scope)
+}
+
+// unread 排除用户已读的公告
+func (r *Repository) unread(ctx context.Context, query *gorm.DB, userID int64) *gorm.DB {
+	reads := r.db.WithContext(ctx).Model(&model.SysNoticeRead{}).
+		Select("1").
+		Where("notice_id = "+model.SysNotice{}.TableName()+".id AND user_id = ?", userID)
+	return query.Where("NOT EXISTS (?)", reads)
+}
+
+// ApplySchedule 将到期的公告置为已过期、到达发布时间的待发布公告置为正常，返回各自更新数量
+func (r *Repository) ApplySchedule(ctx context.Context, now time.Time) (published, expired int64, err error) {
+	if r == nil || r.db == nil {
+		return 0, 0, ErrRepositoryUnavailable
+	}
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.SysNotice{}).
+			Where("status IN ? AND expire_at IS NOT NULL AND expire_at <= ?", []string{statusPublished, statusScheduled}, now).
+			Update("status", statusExpired)
+		if result.Error != nil {
+			return result.Error
+		}
+		expired = result.RowsAffected
+
+		result = tx.Model(&model.SysNotice{}).
+			Where("status = ? AND (publish_at IS NULL OR publish_at <= ?)", statusScheduled, now).
+			Update("status", statusPublished)
+		if result.Error != nil {
+			return result.Error
+		}
+		published = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return published, expired, nil
+}
+
+// FindActiveTenantID 按编码查找启用中的租户
+func (r *Repository) FindActiveTenantID(ctx context.Context, code string) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, ErrRepositoryUnavailable
+	}
+	var record model.SysTenant
+	err := r.db.WithContext(ctx).Select("id").Where("tenant_code = ? AND status = ?", code, "0").First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrTenantUnavailable
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int64(record.ID), nil
+}
+
 func (r *Repository) GetNotice(ctx context.Context, id int64) (*model.SysNotice, error) {
 	if r == nil || r.db == nil {
 		return nil, ErrRepositoryUnavailable
@@ -68,36 +234,273 @@ func (r *Repository) GetNotice(ctx context.Context, id int64) (*model.SysNotice,
 	return &record, nil
 }
 
-func (r *Repository) CreateNotice(ctx context.Context, record *model.SysNotice) error {
+// CreateNotice 新增公告及其接收对象
+func (r *Repository) CreateNotice(ctx context.Context, record *model.SysNotice, targetIDs []int64) error {
 	if r == nil || r.db == nil {
 		return ErrRepositoryUnavailable
 	}
 	if record == nil {
 		return errors.New("notice record is required")
 	}
-	return r.db.WithContext(ctx).Create(record).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		return replaceTargets(tx, int64(record.ID), targetIDs)
+	})
 }
 
-func (r *Repository) SaveNotice(ctx context.Context, record *model.SysNotice) error {
+// SaveNotice 保存公告；targetIDs 为 nil 时保留原接收对象，非 nil 时整体替换
+func (r *Repository) SaveNotice(ctx context.Context, record *model.SysNotice, targetIDs []int64) error {
 	if r == nil || r.db == nil {
 		return ErrRepositoryUnavailable
 	}
 	if record == nil {
 		return errors.New("notice record is required")
 	}
-	return r.db.WithContext(ctx).Save(record).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := etag.Guard(tx.Model(record)).Select("*").Updates(record)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return etag.Missed(tx, &model.SysNotice{}, record.ID)
+		}
+		if targetIDs == nil {
+			return nil
+		}
+		return replaceTargets(tx, int64(record.ID), targetIDs)
+	})
+}
+
+func replaceTargets(tx *gorm.DB, noticeID int64, targetIDs []int64) error {
+	if err := tx.Where("notice_id = ?", noticeID).Delete(&model.SysNoticeTarget{}).Error; err != nil {
+		return err
+	}
+	if len(targetIDs) == 0 {
+		return nil
+	}
+	targets := make([]model.SysNoticeTarget, 0, len(targetIDs))
+	for _, id := range targetIDs {
+		targets = append(targets, model.SysNoticeTarget{NoticeID: noticeID, TargetID: id})
+	}
+	return tx.Create(&targets).Error
+}
+
+// ListTargets 返回公告ID到接收对象ID的映射
+func (r *Repository) ListTargets(ctx context.Context, noticeIDs []int64) (map[int64][]int64, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	result := make(map[int64][]int64)
+	if len(noticeIDs) == 0 {
+		return result, nil
+	}
+	var rows []model.SysNoticeTarget
+	if err := r.db.WithContext(ctx).
+		Where("notice_id IN ?", noticeIDs).
+		Order("notice_id ASC, target_id ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.NoticeID] = append(result[row.NoticeID], row.TargetID)
+	}
+	return result, nil
+}
+
+// CountTargets 统计接收对象中实际存在的用户、角色或部门数量
+func (r *Repository) CountTargets(ctx context.Context, audienceType string, ids []int64) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, ErrRepositoryUnavailable
+	}
+	var value interface{}
+	switch audienceType {
+	case AudienceUsers:
+		value = &model.SysUser{}
+	case AudienceRoles:
+		value = &model.SysRole{}
+	case AudienceDepts:
+		value = &model.SysDept{}
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAudience, audienceType)
+	}
+	var count int64
+	if err := r.db.WithContext(ctx).Model(value).Where("id IN ?", ids).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetUserDept 返回用户所在部门及其全部上级部门 ID，用户未分配部门时为空
+func (r *Repository) GetUserDept(ctx context.Context, userID int64) ([]int64, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	db := r.db.WithContext(ctx)
+
+	var users []model.SysUser
+	if err := db.Select("id", "dept_id").Where("id = ?", userID).Limit(1).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	if len(users) == 0 || users[0].DeptID == nil || *users[0].DeptID <= 0 {
+		return []int64{}, nil
+	}
+	deptID := *users[0].DeptID
+
+	var depts []model.SysDept
+	if err := db.Select("id", "ancestors").Where("id = ?", deptID).Limit(1).Find(&depts).Error; err != nil {
+		return nil, err
+	}
+	if len(depts) == 0 {
+		return []int64{}, nil
+	}
+	return append(parseAncestors(depts[0].Ancestors), deptID), nil
+}
+
+// ListAudienceUsers 返回公告接收范围内状态正常的用户；roleMembers 为按角色投放时解析出的用户 ID
+func (r *Repository) ListAudienceUsers(ctx context.Context, audienceType string, targetIDs, roleMembers []int64) ([]model.SysUser, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	db := r.db.WithContext(ctx)
+	query := db.Model(&model.SysUser{}).
+		Select("id", "user_name", "nick_name", "dept_id").
+		Where("status = ?", "0")
+
+	switch audienceType {
+	case AudienceAll:
+	case AudienceUsers:
+		query = query.Where("id IN ?", nonEmpty(targetIDs))
+	case AudienceRoles:
+		query = query.Where("id IN ?", nonEmpty(roleMembers))
+	case AudienceDepts:
+		deptIDs, err := r.expandDepts(ctx, targetIDs)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("dept_id IN ?", nonEmpty(deptIDs))
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAudience, audienceType)
+	}
+
+	var users []model.SysUser
+	if err := query.Order("id ASC").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// expandDepts 返回指定部门及其全部下级部门
+func (r *Repository) expandDepts(ctx context.Context, deptIDs []int64) ([]int64, error) {
+	if len(deptIDs) == 0 {
+		return []int64{}, nil
+	}
+	db := r.db.WithContext(ctx)
+
+	var depts []model.SysDept
+	if err := db.Select("id", "ancestors").Where("id IN ?", deptIDs).Find(&depts).Error; err != nil {
+		return nil, err
+	}
+	if len(depts) == 0 {
+		return []int64{}, nil
+	}
+
+	subtree := db.Where("1 = 0")
+	for _, dept := range depts {
+		// 追加逗号避免 "0,1" 误匹配 "0,10"
+		path := fmt.Sprintf("%s,%d", dept.Ancestors, dept.ID)
+		subtree = subtree.Or("ancestors = ? OR ancestors LIKE ?", path, path+",%")
+	}
+	var descendants []int64
+	if err := db.Model(&model.SysDept{}).Where(subtree).Pluck("id", &descendants).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]int64, 0, len(depts)+len(descendants))
+	for _, dept := range depts {
+		result = append(result, int64(dept.ID))
+	}
+	return append(result, descendants...), nil
 }
 
-func (r *Repository) DeleteNotice(ctx context.Context, id int64) error {
+// ListReads 返回公告已读记录，userIDs 为空时返回全部
+func (r *Repository) ListReads(ctx context.Context, noticeIDs, userIDs []int64) ([]model.SysNoticeRead, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	if len(noticeIDs) == 0 {
+		return []model.SysNoticeRead{}, nil
+	}
+	query := r.db.WithContext(ctx).Where("notice_id IN ?", noticeIDs)
+	if len(userIDs) > 0 {
+		query = query.Where("user_id IN ?", userIDs)
+	}
+	var records []model.SysNoticeRead
+	if err := query.Order("read_at ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// MarkRead 记录用户已读，已读过的公告保留首次阅读时间
+func (r *Repository) MarkRead(ctx context.Context, userID int64, noticeIDs []int64, readAt time.Time) error {
 	if r == nil || r.db == nil {
 		return ErrRepositoryUnavailable
 	}
-	result := r.db.WithContext(ctx).Delete(&model.SysNotice{}, id)
-	if result.Error != nil {
-		return result.Error
+	if len(noticeIDs) == 0 {
+		return nil
 	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	records := make([]model.SysNoticeRead, 0, len(noticeIDs))
+	for _, id := range noticeIDs {
+		records = append(records, model.SysNoticeRead{NoticeID: id, UserID: userID, ReadAt: readAt})
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&records, 500).Error
+}
+
+func (r *Repository) DeleteNotice(ctx context.Context, id int64, operator string) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 记录删除人，供回收站展示；If-Match 在这一步校验
+		marked := etag.Guard(tx.Model(&model.SysNotice{}).Where("id = ?", id)).Update("update_by", operator)
+		if marked.Error != nil {
+			return marked.Error
+		}
+		if marked.RowsAffected == 0 {
+			return etag.Missed(tx, &model.SysNotice{}, id)
+		}
+		result := tx.Delete(&model.SysNotice{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func parseAncestors(ancestors string) []int64 {
+	parts := strings.Split(ancestors, ",")
+	ids := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || id <= 0 {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// nonEmpty 保证 IN 条件至少有一个值，空列表时不匹配任何记录
+func nonEmpty(ids []int64) []int64 {
+	if len(ids) == 0 {
+		return []int64{0}
 	}
-	return nil
+	return ids
 }
@@ -3,10 +3,30 @@ package notice
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/auth"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+// 公告接收范围
+const (
+	AudienceAll   = "all"   // 全部用户
+	AudienceUsers = "users" // 指定用户
+	AudienceRoles = "roles" // 拥有指定角色的用户，含通过部门、岗位继承
+	AudienceDepts = "depts" // 指定部门及其下级部门的用户
+)
+
+// 公告状态；待发布与已过期由发布时间窗口推导，不接受直接设置
+const (
+	statusPublished = "0" // 正常，发布窗口内对接收人可见
+	statusClosed    = "1" // 关闭
+	statusScheduled = "2" // 待发布，未到发布时间
+	statusExpired   = "3" // 已过期
 )
 
 var (
@@ -17,27 +37,41 @@ var (
 	ErrContentRequired = errors.New("notice content is required")
 	ErrInvalidStatus   = errors.New("invalid notice status")
 	ErrInvalidType     = errors.New("invalid notice type")
-)
+	ErrInvalidAudience = errors.New("invalid notice audience type")
+	ErrTargetsRequired = errors.New("notice targets are required")
+	ErrTargetNotFound  = errors.New("notice target does not exist")
 
-var validTypes = map[string]struct{}{
-	"1": {},
-	"2": {},
-}
+	ErrInvalidSchedule   = errors.New("invalid notice schedule")
+	ErrTenantUnavailable = errors.New("tenant unavailable")
+)
 
-var validStatus = map[string]struct{}{
-	"0": {},
-	"1": {},
-}
+var (
+	typeEnum = dict.Enum{
+		DictType: dict.TypeNoticeType,
+		Fallback: []string{"1", "2"},
+		Invalid:  ErrInvalidType,
+	}
+	statusEnum = dict.Enum{
+		DictType: dict.TypeNoticeStatus,
+		Fallback: []string{"0", "1"},
+		Default:  "0",
+		Invalid:  ErrInvalidStatus,
+	}
+)
 
 type Service struct {
-	repo *Repository
+	repo   *Repository
+	dicts  *dict.Service
+	grants *auth.Repository
 }
 
-func NewService(repo *Repository) *Service {
+// NewService 创建通知服务；dicts 可选，用于按字典校验类型与状态；
+// grants 可选，用于解析用户拥有的角色与角色成员，缺省时按角色投放的公告不会送达任何用户
+func NewService(repo *Repository, dicts *dict.Service, grants *auth.Repository) *Service {
 	if repo == nil {
 		return nil
 	}
-	return &Service{repo: repo}
+	return &Service{repo: repo, dicts: dicts, grants: grants}
 }
 
 type Notice struct {
@@ -46,6 +80,12 @@ type Notice struct {
 	NoticeType    string     `json:"noticeType"`
 	NoticeContent string     `json:"noticeContent"`
 	Status        string     `json:"status"`
+	AudienceType  string     `json:"audienceType"`
+	TargetIDs     []int64    `json:"targetIds"`
+	PublishAt     *time.Time `json:"publishAt,omitempty"`
+	ExpireAt      *time.Time `json:"expireAt,omitempty"`
+	Pinned        bool       `json:"pinned"`
+	Priority      int        `json:"priority"`
 	Remark        *string    `json:"remark,omitempty"`
 	CreateBy      string     `json:"createBy"`
 	CreatedAt     time.Time  `json:"createdAt"`
@@ -58,8 +98,15 @@ type CreateNoticeInput struct {
 	NoticeType    string
 	NoticeContent string
 	Status        string
-	Remark        *string
-	Operator      string
+	AudienceType  string
+	TargetIDs     []int64
+	// PublishAt 与 ExpireAt 为 RFC 3339 时间，为空表示不限
+	PublishAt string
+	ExpireAt  string
+	Pinned    bool
+	Priority  int
+	Remark    *string
+	Operator  string
 }
 
 type UpdateNoticeInput struct {
@@ -68,8 +115,15 @@ type UpdateNoticeInput struct {
 	NoticeType    *string
 	NoticeContent *string
 	Status        *string
-	Remark        *string
-	Operator      string
+	AudienceType  *string
+	TargetIDs     *[]int64
+	// PublishAt 与 ExpireAt 为空字符串时清除
+	PublishAt *string
+	ExpireAt  *string
+	Pinned    *bool
+	Priority  *int
+	Remark    *string
+	Operator  string
 }
 
 func (s *Service) ListNotices(ctx context.Context, opts ListOptions) ([]Notice, error) {
@@ -82,9 +136,20 @@ func (s *Service) ListNotices(ctx context.Context, opts ListOptions) ([]Notice,
 		return nil, err
 	}
 
+	ids := make([]int64, 0, len(records))
+	for i := range records {
+		ids = append(ids, int64(records[i].ID))
+	}
+	targets, err := s.repo.ListTargets(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]Notice, 0, len(records))
 	for i := range records {
-		result = append(result, *noticeFromModel(&records[i]))
+		item := noticeFromModel(&records[i])
+		item.TargetIDs = targetsOf(targets, item.NoticeID)
+		result = append(result, *item)
 	}
 	return result, nil
 }
@@ -98,7 +163,13 @@ func (s *Service) GetNotice(ctx context.Context, id int64) (*Notice, error) {
 	if err != nil {
 		return nil, err
 	}
-	return noticeFromModel(record), nil
+	targets, err := s.repo.ListTargets(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+	item := noticeFromModel(record)
+	item.TargetIDs = targetsOf(targets, id)
+	return item, nil
 }
 
 func (s *Service) CreateNotice(ctx context.Context, input CreateNoticeInput) (*Notice, error) {
@@ -115,8 +186,9 @@ func (s *Service) CreateNotice(ctx context.Context, input CreateNoticeInput) (*N
 	if noticeType == "" {
 		return nil, ErrTypeRequired
 	}
-	if _, ok := validTypes[noticeType]; !ok {
-		return nil, ErrInvalidType
+	noticeType, err := s.dicts.Normalize(ctx, typeEnum, noticeType)
+	if err != nil {
+		return nil, err
 	}
 
 	content := strings.TrimSpace(input.NoticeContent)
@@ -124,26 +196,50 @@ func (s *Service) CreateNotice(ctx context.Context, input CreateNoticeInput) (*N
 		return nil, ErrContentRequired
 	}
 
-	status := normalizeStatus(input.Status)
-	if _, ok := validStatus[status]; !ok {
-		return nil, ErrInvalidStatus
+	status, err := s.dicts.Normalize(ctx, statusEnum, input.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	audienceType, targetIDs, err := s.normalizeAudience(ctx, input.AudienceType, input.TargetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	publishAt, err := parseScheduleTime("publishAt", input.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+	expireAt, err := parseScheduleTime("expireAt", input.ExpireAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWindow(publishAt, expireAt); err != nil {
+		return nil, err
 	}
 
 	record := &model.SysNotice{
 		NoticeTitle:   title,
 		NoticeType:    noticeType,
 		NoticeContent: []byte(content),
-		Status:        status,
+		Status:        scheduleStatus(status, publishAt, expireAt, time.Now()),
+		AudienceType:  audienceType,
+		PublishAt:     publishAt,
+		ExpireAt:      expireAt,
+		Pinned:        input.Pinned,
+		Priority:      input.Priority,
 		Remark:        normalizeRemark(input.Remark),
 		CreateBy:      sanitizeOperator(input.Operator),
 		UpdateBy:      sanitizeOperator(input.Operator),
 	}
 
-	if err := s.repo.CreateNotice(ctx, record); err != nil {
+	if err := s.repo.CreateNotice(ctx, record, targetIDs); err != nil {
 		return nil, err
 	}
 
-	return noticeFromModel(record), nil
+	item := noticeFromModel(record)
+	item.TargetIDs = targetIDs
+	return item, nil
 }
 
 func (s *Service) UpdateNotice(ctx context.Context, input UpdateNoticeInput) (*Notice, error) {
@@ -169,8 +265,9 @@ func (s *Service) UpdateNotice(ctx context.Context, input UpdateNoticeInput) (*N
 		if noticeType == "" {
 			return nil, ErrTypeRequired
 		}
-		if _, ok := validTypes[noticeType]; !ok {
-			return nil, ErrInvalidType
+		noticeType, err := s.dicts.Normalize(ctx, typeEnum, noticeType)
+		if err != nil {
+			return nil, err
 		}
 		record.NoticeType = noticeType
 	}
@@ -184,31 +281,92 @@ func (s *Service) UpdateNotice(ctx context.Context, input UpdateNoticeInput) (*N
 	}
 
 	if input.Status != nil {
-		status := normalizeStatus(*input.Status)
-		if _, ok := validStatus[status]; !ok {
-			return nil, ErrInvalidStatus
+		status, err := s.dicts.Normalize(ctx, statusEnum, *input.Status)
+		if err != nil {
+			return nil, err
 		}
 		record.Status = status
 	}
 
+	// targetIDs 为 nil 表示接收对象不变
+	var targetIDs []int64
+	if input.AudienceType != nil || input.TargetIDs != nil {
+		audienceType := record.AudienceType
+		if input.AudienceType != nil {
+			audienceType = *input.AudienceType
+		}
+		var requested []int64
+		if input.TargetIDs != nil {
+			requested = *input.TargetIDs
+		} else if normalizeAudienceType(audienceType) == record.AudienceType {
+			// 仅修改其他字段时沿用原接收对象
+			existing, err := s.repo.ListTargets(ctx, []int64{input.ID})
+			if err != nil {
+				return nil, err
+			}
+			requested = existing[input.ID]
+		}
+		audienceType, targetIDs, err = s.normalizeAudience(ctx, audienceType, requested)
+		if err != nil {
+			return nil, err
+		}
+		record.AudienceType = audienceType
+	}
+
+	if input.PublishAt != nil {
+		publishAt, err := parseScheduleTime("publishAt", *input.PublishAt)
+		if err != nil {
+			return nil, err
+		}
+		record.PublishAt = publishAt
+	}
+	if input.ExpireAt != nil {
+		expireAt, err := parseScheduleTime("expireAt", *input.ExpireAt)
+		if err != nil {
+			return nil, err
+		}
+		record.ExpireAt = expireAt
+	}
+	if err := validateWindow(record.PublishAt, record.ExpireAt); err != nil {
+		return nil, err
+	}
+	// 修改发布时间窗口后重新推导状态，例如延长过期时间可恢复已过期的公告
+	record.Status = scheduleStatus(record.Status, record.PublishAt, record.ExpireAt, time.Now())
+
+	if input.Pinned != nil {
+		record.Pinned = *input.Pinned
+	}
+	if input.Priority != nil {
+		record.Priority = *input.Priority
+	}
+
 	if input.Remark != nil {
 		record.Remark = normalizeRemark(input.Remark)
 	}
 
 	record.UpdateBy = sanitizeOperator(input.Operator)
 
-	if err := s.repo.SaveNotice(ctx, record); err != nil {
+	if err := s.repo.SaveNotice(ctx, record, targetIDs); err != nil {
 		return nil, err
 	}
 
-	return noticeFromModel(record), nil
+	if targetIDs == nil {
+		targets, err := s.repo.ListTargets(ctx, []int64{input.ID})
+		if err != nil {
+			return nil, err
+		}
+		targetIDs = targetsOf(targets, input.ID)
+	}
+	item := noticeFromModel(record)
+	item.TargetIDs = targetIDs
+	return item, nil
 }
 
-func (s *Service) DeleteNotice(ctx context.Context, id int64) error {
+func (s *Service) DeleteNotice(ctx context.Context, id int64, operator string) error {
 	if s == nil || s.repo == nil {
 		return ErrServiceUnavailable
 	}
-	return s.repo.DeleteNotice(ctx, id)
+	return s.repo.DeleteNotice(ctx, id, strings.TrimSpace(operator))
 }
 
 func noticeFromModel(record *model.SysNotice) *Notice {
@@ -227,6 +385,12 @@ func noticeFromModel(record *model.SysNotice) *Notice {
 		NoticeType:    record.NoticeType,
 		NoticeContent: string(record.NoticeContent),
 		Status:        record.Status,
+		AudienceType:  normalizeAudienceType(record.AudienceType),
+		TargetIDs:     []int64{},
+		PublishAt:     record.PublishAt,
+		ExpireAt:      record.ExpireAt,
+		Pinned:        record.Pinned,
+		Priority:      record.Priority,
 		Remark:        record.Remark,
 		CreateBy:      record.CreateBy,
 		CreatedAt:     created,
@@ -235,12 +399,109 @@ func noticeFromModel(record *model.SysNotice) *Notice {
 	}
 }
 
-func normalizeStatus(status string) string {
-	trimmed := strings.TrimSpace(status)
-	if trimmed == "" {
-		return "0"
+// normalizeAudience 校验接收范围与接收对象，全员公告不保留接收对象
+func (s *Service) normalizeAudience(ctx context.Context, audienceType string, targetIDs []int64) (string, []int64, error) {
+	audienceType = normalizeAudienceType(audienceType)
+	switch audienceType {
+	case AudienceAll:
+		return audienceType, []int64{}, nil
+	case AudienceUsers, AudienceRoles, AudienceDepts:
+	default:
+		return "", nil, ErrInvalidAudience
+	}
+
+	seen := make(map[int64]struct{}, len(targetIDs))
+	ids := make([]int64, 0, len(targetIDs))
+	for _, id := range targetIDs {
+		if id <= 0 {
+			return "", nil, ErrTargetNotFound
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return "", nil, ErrTargetsRequired
+	}
+
+	count, err := s.repo.CountTargets(ctx, audienceType, ids)
+	if err != nil {
+		return "", nil, err
+	}
+	if count != int64(len(ids)) {
+		return "", nil, ErrTargetNotFound
+	}
+	return audienceType, ids, nil
+}
+
+// ApplySchedule 按发布时间窗口更新待发布与已过期公告的状态，供定时任务调用
+func (s *Service) ApplySchedule(ctx context.Context, now time.Time) (published, expired int64, err error) {
+	if s == nil || s.repo == nil {
+		return 0, 0, ErrServiceUnavailable
+	}
+	return s.repo.ApplySchedule(ctx, now)
+}
+
+// ResolveTenant 按租户编码查找启用中的租户，未提供编码时为默认租户
+func (s *Service) ResolveTenant(ctx context.Context, code string) (int64, error) {
+	if s == nil || s.repo == nil {
+		return 0, ErrServiceUnavailable
+	}
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return tenant.DefaultID, nil
+	}
+	return s.repo.FindActiveTenantID(ctx, code)
+}
+
+// scheduleStatus 按发布时间窗口推导公告状态；关闭的公告保持关闭
+func scheduleStatus(status string, publishAt, expireAt *time.Time, now time.Time) string {
+	if status == statusClosed {
+		return status
+	}
+	if publishAt != nil && publishAt.After(now) {
+		return statusScheduled
+	}
+	if expireAt != nil && !expireAt.After(now) {
+		return statusExpired
+	}
+	return statusPublished
+}
+
+func parseScheduleTime(field, value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be an RFC 3339 time", ErrInvalidSchedule, field)
+	}
+	return &parsed, nil
+}
+
+func validateWindow(publishAt, expireAt *time.Time) error {
+	if publishAt != nil && expireAt != nil && !expireAt.After(*publishAt) {
+		return fmt.Errorf("%w: expireAt must be later than publishAt", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+func normalizeAudienceType(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return AudienceAll
+	}
+	return value
+}
+
+func targetsOf(targets map[int64][]int64, noticeID int64) []int64 {
+	if ids, ok := targets[noticeID]; ok {
+		return ids
 	}
-	return trimmed
+	return []int64{}
 }
 
 func normalizeRemark(remark *string) *string {
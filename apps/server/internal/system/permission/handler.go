@@ -0,0 +1,85 @@
+package permission
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/starter-kit-fe/admin/pkg/resp"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	if service == nil {
+		return nil
+	}
+	return &Handler{service: service}
+}
+
+type listRoutesQuery struct {
+	Permission string `form:"permission"`
+	Path       string `form:"path"`
+}
+
+// ListRoutes godoc
+// @Summary 获取路由权限清单
+// @Description 列出所有声明了权限元数据的路由，可按权限标识或路径过滤
+// @Tags System/Permission
+// @Security BearerAuth
+// @Produce json
+// @Param permission query string false "权限标识"
+// @Param path query string false "路径关键字"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/permissions/routes [get]
+func (h *Handler) ListRoutes(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("permission service unavailable"))
+		return
+	}
+
+	var query listRoutesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	routes, err := h.service.ListRoutes(ctx.Request.Context(), RouteQuery{
+		Permission: query.Permission,
+		Path:       query.Path,
+	})
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load routes"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(routes))
+}
+
+// Audit godoc
+// @Summary 核对路由与菜单权限
+// @Description 报告路由需要但菜单未声明的权限，以及菜单声明但未保护任何路由的权限
+// @Tags System/Permission
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/permissions/audit [get]
+func (h *Handler) Audit(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("permission service unavailable"))
+		return
+	}
+
+	report, err := h.service.Audit(ctx.Request.Context())
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to audit permissions"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(report))
+}
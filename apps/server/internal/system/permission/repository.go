@@ -0,0 +1,42 @@
+package permission
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+var (
+	ErrRepositoryUnavailable = errors.New("permission repository is not initialized")
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	if db == nil {
+		return nil
+	}
+	return &Repository{db: db}
+}
+
+// ListMenusWithPerms returns every menu that declares a non-empty permission string.
+func (r *Repository) ListMenusWithPerms(ctx context.Context) ([]model.SysMenu, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	var menus []model.SysMenu
+	if err := r.db.WithContext(ctx).
+		Model(&model.SysMenu{}).
+		Where("perms IS NOT NULL AND perms <> ''").
+		Order("id ASC").
+		Find(&menus).Error; err != nil {
+		return nil, err
+	}
+	return menus, nil
+}
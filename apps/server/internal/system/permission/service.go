@@ -0,0 +1,197 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrServiceUnavailable = errors.New("permission service is not initialized")
+)
+
+// wildcardPermission 为超级权限标识，不对应具体路由
+const wildcardPermission = "*:*:*"
+
+// Route mirrors a route registered with permission metadata.
+type Route struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Permissions []string `json:"permissions"`
+	Description string   `json:"description"`
+}
+
+// RouteSource supplies the routes currently registered on the HTTP engine.
+type RouteSource interface {
+	Routes() []Route
+}
+
+type Service struct {
+	repo   *Repository
+	routes RouteSource
+}
+
+func NewService(repo *Repository, routes RouteSource) *Service {
+	if repo == nil {
+		return nil
+	}
+	return &Service{repo: repo, routes: routes}
+}
+
+type RouteRef struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+}
+
+type MenuRef struct {
+	MenuID   int64  `json:"menuId"`
+	MenuName string `json:"menuName"`
+	MenuType string `json:"menuType"`
+}
+
+// MissingPermission is a permission required by routes but not declared by any menu.
+type MissingPermission struct {
+	Permission string     `json:"permission"`
+	Routes     []RouteRef `json:"routes"`
+}
+
+// UnusedPermission is a permission declared by menus that protects no route.
+type UnusedPermission struct {
+	Permission string    `json:"permission"`
+	Menus      []MenuRef `json:"menus"`
+}
+
+type AuditReport struct {
+	RouteCount       int                 `json:"routeCount"`
+	RoutePermissions int                 `json:"routePermissions"`
+	MenuPermissions  int                 `json:"menuPermissions"`
+	MissingInMenus   []MissingPermission `json:"missingInMenus"`
+	UnusedByRoutes   []UnusedPermission  `json:"unusedByRoutes"`
+	Unprotected      []RouteRef          `json:"unprotectedRoutes"`
+}
+
+// Consistent reports whether routes and menus declare the same permission set.
+func (r *AuditReport) Consistent() bool {
+	return r != nil && len(r.MissingInMenus) == 0 && len(r.UnusedByRoutes) == 0
+}
+
+type RouteQuery struct {
+	Permission string
+	Path       string
+}
+
+func (s *Service) ListRoutes(ctx context.Context, query RouteQuery) ([]Route, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	permission := strings.TrimSpace(query.Permission)
+	pathKeyword := strings.TrimSpace(query.Path)
+
+	routes := s.loadRoutes()
+	items := make([]Route, 0, len(routes))
+	for _, route := range routes {
+		if permission != "" && !containsString(route.Permissions, permission) {
+			continue
+		}
+		if pathKeyword != "" && !strings.Contains(route.Path, pathKeyword) {
+			continue
+		}
+		items = append(items, route)
+	}
+	return items, nil
+}
+
+func (s *Service) Audit(ctx context.Context) (*AuditReport, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	menus, err := s.repo.ListMenusWithPerms(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := s.loadRoutes()
+
+	routesByPerm := make(map[string][]RouteRef)
+	unprotected := make([]RouteRef, 0)
+	for _, route := range routes {
+		ref := RouteRef{Method: route.Method, Path: route.Path, Description: route.Description}
+		if len(route.Permissions) == 0 {
+			unprotected = append(unprotected, ref)
+			continue
+		}
+		for _, perm := range route.Permissions {
+			routesByPerm[perm] = append(routesByPerm[perm], ref)
+		}
+	}
+
+	menusByPerm := make(map[string][]MenuRef)
+	for _, menu := range menus {
+		if menu.Perms == nil {
+			continue
+		}
+		perm := strings.TrimSpace(*menu.Perms)
+		if perm == "" {
+			continue
+		}
+		menusByPerm[perm] = append(menusByPerm[perm], MenuRef{
+			MenuID:   int64(menu.ID),
+			MenuName: menu.MenuName,
+			MenuType: menu.MenuType,
+		})
+	}
+
+	missing := make([]MissingPermission, 0)
+	for perm, refs := range routesByPerm {
+		if _, ok := menusByPerm[perm]; ok {
+			continue
+		}
+		missing = append(missing, MissingPermission{Permission: perm, Routes: refs})
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Permission < missing[j].Permission })
+
+	unused := make([]UnusedPermission, 0)
+	for perm, refs := range menusByPerm {
+		if perm == wildcardPermission {
+			continue
+		}
+		if _, ok := routesByPerm[perm]; ok {
+			continue
+		}
+		unused = append(unused, UnusedPermission{Permission: perm, Menus: refs})
+	}
+	sort.Slice(unused, func(i, j int) bool { return unused[i].Permission < unused[j].Permission })
+
+	return &AuditReport{
+		RouteCount:       len(routes),
+		RoutePermissions: len(routesByPerm),
+		MenuPermissions:  len(menusByPerm),
+		MissingInMenus:   missing,
+		UnusedByRoutes:   unused,
+		Unprotected:      unprotected,
+	}, nil
+}
+
+func (s *Service) loadRoutes() []Route {
+	if s.routes == nil {
+		return []Route{}
+	}
+	routes := s.routes.Routes()
+	if routes == nil {
+		return []Route{}
+	}
+	return routes
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package post
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+const exportBatchSize = 500
+
+var exportHeader = []string{"岗位编号", "岗位编码", "岗位名称", "显示顺序", "状态", "创建时间", "备注"}
+
+var exportStatusLabels = map[string]string{"0": "正常", "1": "停用"}
+
+// ExportPosts 按列表筛选条件分批导出岗位，先输出表头，再逐行回调
+func (s *Service) ExportPosts(ctx context.Context, opts QueryOptions, fn func([]string) error) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+
+	headerWritten := false
+	err := s.repo.ExportPosts(ctx, ListOptions{
+		Status:   strings.TrimSpace(opts.Status),
+		PostName: opts.PostName,
+		PostCode: opts.PostCode,
+	}, exportBatchSize, func(records []model.SysPost) error {
+		if !headerWritten {
+			headerWritten = true
+			if err := fn(exportHeader); err != nil {
+				return err
+			}
+		}
+		for i := range records {
+			if err := fn(exportPostRow(&records[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !headerWritten {
+		return fn(exportHeader)
+	}
+	return nil
+}
+
+func exportPostRow(record *model.SysPost) []string {
+	remark := ""
+	if record.Remark != nil {
+		remark = *record.Remark
+	}
+	status := record.Status
+	if label, ok := exportStatusLabels[status]; ok {
+		status = label
+	}
+	return []string{
+		strconv.FormatUint(uint64(record.ID), 10),
+		record.PostCode,
+		record.PostName,
+		strconv.Itoa(record.PostSort),
+		status,
+		record.CreatedAt.Format(time.DateTime),
+		remark,
+	}
+}
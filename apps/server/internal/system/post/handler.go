@@ -4,12 +4,15 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 	"github.com/starter-kit-fe/admin/pkg/resp"
+	"github.com/starter-kit-fe/admin/pkg/spreadsheet"
 )
 
 type Handler struct {
@@ -31,6 +34,12 @@ type listPostsQuery struct {
 	PostCode string `form:"postCode"`
 }
 
+type exportPostsQuery struct {
+	Status   string `form:"status"`
+	PostName string `form:"postName"`
+	PostCode string `form:"postCode"`
+}
+
 // List godoc
 // @Summary 获取岗位列表
 // @Description 按状态、岗位名称或岗位编码过滤
@@ -90,6 +99,57 @@ type updatePostRequest struct {
 	Remark   *string `json:"remark"`
 }
 
+// Export godoc
+// @Summary 导出岗位
+// @Description 按列表筛选条件导出岗位
+// @Tags System/Post
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param status query string false "岗位状态"
+// @Param postName query string false "岗位名称"
+// @Param postCode query string false "岗位编码"
+// @Param format query string false "导出格式：csv 或 xlsx，默认 csv"
+// @Success 200 {file} file
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/posts/export [get]
+func (h *Handler) Export(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("post service unavailable"))
+		return
+	}
+
+	var query exportPostsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	format, err := spreadsheet.ParseFormat(ctx.Query("format"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("unsupported export format"))
+		return
+	}
+
+	out := spreadsheet.NewAttachment(ctx.Writer, format, "posts_"+time.Now().Format("20060102150405"), "posts")
+	err = h.service.ExportPosts(ctx.Request.Context(), QueryOptions{
+		Status:   query.Status,
+		PostName: query.PostName,
+		PostCode: query.PostCode,
+	}, out.WriteRow)
+	if err == nil {
+		err = out.Close()
+	}
+	if err != nil {
+		if out.Started() {
+			_ = ctx.Error(err)
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to export posts"))
+	}
+}
+
 // Create godoc
 // @Summary 新增岗位
 // @Description 创建岗位信息
@@ -148,6 +208,46 @@ func (h *Handler) Create(ctx *gin.Context) {
 	resp.Created(ctx, resp.WithData(post))
 }
 
+// Get godoc
+// @Summary 获取岗位详情
+// @Description 根据ID查询岗位信息
+// @Tags System/Post
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "岗位ID"
+// @Success 200 {object} resp.Response
+// @Header 200 {string} ETag "岗位版本，修改或删除时通过 If-Match 回传"
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/posts/{id} [get]
+func (h *Handler) Get(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("post service unavailable"))
+		return
+	}
+
+	id, err := parsePostID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid post id"))
+		return
+	}
+
+	post, err := h.service.GetPost(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.NotFound(ctx, resp.WithMessage("post not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load post"))
+		return
+	}
+
+	etag.Set(ctx, *post.UpdatedAt)
+	resp.OK(ctx, resp.WithData(post))
+}
+
 // Update godoc
 // @Summary 修改岗位
 // @Description 更新岗位信息
@@ -157,10 +257,13 @@ func (h *Handler) Create(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "岗位ID"
 // @Param request body updatePostRequest true "岗位参数"
+// @Param If-Match header string true "获取岗位时返回的 ETag"
 // @Success 200 {object} resp.Response
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 409 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/posts/{id} [put]
@@ -182,6 +285,11 @@ func (h *Handler) Update(ctx *gin.Context) {
 		return
 	}
 
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
 	operator := resolveOperator(ctx)
 	post, err := h.service.UpdatePost(ctx.Request.Context(), UpdatePostInput{
 		ID:       id,
@@ -196,6 +304,8 @@ func (h *Handler) Update(ctx *gin.Context) {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("post not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("post has been modified, reload and retry"))
 		case errors.Is(err, ErrPostCodeRequired),
 			errors.Is(err, ErrPostNameRequired),
 			errors.Is(err, ErrInvalidPostSort),
@@ -219,9 +329,12 @@ func (h *Handler) Update(ctx *gin.Context) {
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "岗位ID"
+// @Param If-Match header string true "获取岗位时返回的 ETag"
 // @Success 204 {object} nil
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/posts/{id} [delete]
@@ -237,11 +350,20 @@ func (h *Handler) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeletePost(ctx.Request.Context(), id); err != nil {
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	if err := h.service.DeletePost(ctx.Request.Context(), id, resolveOperator(ctx)); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			resp.NotFound(ctx, resp.WithMessage("post not found"))
 			return
 		}
+		if errors.Is(err, etag.ErrPreconditionFailed) {
+			resp.PreconditionFailed(ctx, resp.WithMessage("post has been modified, reload and retry"))
+			return
+		}
 		resp.InternalServerError(ctx, resp.WithMessage("failed to delete post"))
 		return
 	}
@@ -9,6 +9,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 )
 
 var (
@@ -49,19 +50,7 @@ func (r *Repository) ListPosts(ctx context.Context, opts ListOptions) ([]model.S
 		pageSize = 0
 	}
 
-	base := r.db.WithContext(ctx).Model(&model.SysPost{})
-
-	if status := strings.TrimSpace(opts.Status); status != "" && status != "all" {
-		base = base.Where("status = ?", status)
-	}
-
-	if name := strings.TrimSpace(opts.PostName); name != "" {
-		base = base.Where("post_name ILIKE ?", "%"+name+"%")
-	}
-
-	if code := strings.TrimSpace(opts.PostCode); code != "" {
-		base = base.Where("post_code ILIKE ?", "%"+code+"%")
-	}
+	base := applyPostFilters(r.db.WithContext(ctx).Model(&model.SysPost{}), opts)
 
 	var total int64
 	countQuery := base.Session(&gorm.Session{})
@@ -86,6 +75,35 @@ func (r *Repository) ListPosts(ctx context.Context, opts ListOptions) ([]model.S
 	return posts, total, nil
 }
 
+// ExportPosts 按列表筛选条件分批读取岗位
+func (r *Repository) ExportPosts(ctx context.Context, opts ListOptions, batchSize int, fn func([]model.SysPost) error) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+
+	var batch []model.SysPost
+	result := applyPostFilters(r.db.WithContext(ctx).Model(&model.SysPost{}), opts).
+		FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+			return fn(batch)
+		})
+	return result.Error
+}
+
+func applyPostFilters(query *gorm.DB, opts ListOptions) *gorm.DB {
+	if status := strings.TrimSpace(opts.Status); status != "" && status != "all" {
+		query = query.Where("status = ?", status)
+	}
+
+	if name := strings.TrimSpace(opts.PostName); name != "" {
+		query = query.Where("post_name ILIKE ?", "%"+name+"%")
+	}
+
+	if code := strings.TrimSpace(opts.PostCode); code != "" {
+		query = query.Where("post_code ILIKE ?", "%"+code+"%")
+	}
+	return query
+}
+
 func (r *Repository) GetPost(ctx context.Context, id int64) (*model.SysPost, error) {
 	if r == nil || r.db == nil {
 		return nil, ErrRepositoryUnavailable
@@ -122,20 +140,18 @@ func (r *Repository) UpdatePost(ctx context.Context, id int64, updates map[strin
 		return nil
 	}
 
-	result := r.db.WithContext(ctx).
-		Model(&model.SysPost{}).
-		Where("id = ?", id).
-		Updates(updates)
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Model(&model.SysPost{}).Where("id = ?", id)).Updates(updates)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+		return etag.Missed(db, &model.SysPost{}, id)
 	}
 	return nil
 }
 
-func (r *Repository) DeletePost(ctx context.Context, id int64) error {
+func (r *Repository) DeletePost(ctx context.Context, id int64, operator string) error {
 	if r == nil || r.db == nil {
 		return ErrRepositoryUnavailable
 	}
@@ -143,14 +159,24 @@ func (r *Repository) DeletePost(ctx context.Context, id int64) error {
 		return gorm.ErrRecordNotFound
 	}
 
-	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.SysPost{})
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
-	}
-	return nil
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 记录删除人，供回收站展示；If-Match 在这一步校验
+		marked := etag.Guard(tx.Model(&model.SysPost{}).Where("id = ?", id)).Update("update_by", operator)
+		if marked.Error != nil {
+			return marked.Error
+		}
+		if marked.RowsAffected == 0 {
+			return etag.Missed(tx, &model.SysPost{}, id)
+		}
+		result := tx.Where("id = ?", id).Delete(&model.SysPost{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
 }
 
 func (r *Repository) ExistsByCode(ctx context.Context, code string, excludeID int64) (bool, error) {
@@ -210,7 +236,7 @@ func (r *Repository) TouchPost(ctx context.Context, id int64, operator string, a
 	}
 
 	updates := map[string]interface{}{
-		"update_time": at,
+		"updated_at": at,
 	}
 	if trimmed := strings.TrimSpace(operator); trimmed != "" {
 		updates["update_by"] = trimmed
@@ -9,6 +9,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
 )
 
 var (
@@ -21,20 +22,24 @@ var (
 	ErrDuplicatePostName  = errors.New("duplicate post name")
 )
 
-var validStatuses = map[string]struct{}{
-	"0": {},
-	"1": {},
+var statusEnum = dict.Enum{
+	DictType: dict.TypeNormalDisable,
+	Fallback: []string{"0", "1"},
+	Default:  "0",
+	Invalid:  ErrInvalidPostStatus,
 }
 
 type Service struct {
-	repo *Repository
+	repo  *Repository
+	dicts *dict.Service
 }
 
-func NewService(repo *Repository) *Service {
+// NewService 创建岗位服务；dicts 可选，用于按字典校验状态取值
+func NewService(repo *Repository, dicts *dict.Service) *Service {
 	if repo == nil {
 		return nil
 	}
-	return &Service{repo: repo}
+	return &Service{repo: repo, dicts: dicts}
 }
 
 type QueryOptions struct {
@@ -53,12 +58,13 @@ type ListResult struct {
 }
 
 type Post struct {
-	PostID   int64   `json:"postId"`
-	PostCode string  `json:"postCode"`
-	PostName string  `json:"postName"`
-	PostSort int     `json:"postSort"`
-	Status   string  `json:"status"`
-	Remark   *string `json:"remark,omitempty"`
+	PostID    int64      `json:"postId"`
+	PostCode  string     `json:"postCode"`
+	PostName  string     `json:"postName"`
+	PostSort  int        `json:"postSort"`
+	Status    string     `json:"status"`
+	Remark    *string    `json:"remark,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
 }
 
 type CreatePostInput struct {
@@ -160,9 +166,9 @@ func (s *Service) CreatePost(ctx context.Context, input CreatePostInput) (*Post,
 		return nil, ErrInvalidPostSort
 	}
 
-	status := normalizeStatus(input.Status)
-	if _, ok := validStatuses[status]; !ok {
-		return nil, ErrInvalidPostStatus
+	status, err := s.dicts.Normalize(ctx, statusEnum, input.Status)
+	if err != nil {
+		return nil, err
 	}
 
 	if exists, err := s.repo.ExistsByCode(ctx, code, 0); err != nil {
@@ -244,9 +250,9 @@ func (s *Service) UpdatePost(ctx context.Context, input UpdatePostInput) (*Post,
 	}
 
 	if input.Status != nil {
-		status := normalizeStatus(*input.Status)
-		if _, ok := validStatuses[status]; !ok {
-			return nil, ErrInvalidPostStatus
+		status, err := s.dicts.Normalize(ctx, statusEnum, *input.Status)
+		if err != nil {
+			return nil, err
 		}
 		updates["status"] = status
 	}
@@ -276,22 +282,14 @@ func (s *Service) UpdatePost(ctx context.Context, input UpdatePostInput) (*Post,
 	return s.GetPost(ctx, input.ID)
 }
 
-func (s *Service) DeletePost(ctx context.Context, id int64) error {
+func (s *Service) DeletePost(ctx context.Context, id int64, operator string) error {
 	if s == nil || s.repo == nil {
 		return ErrServiceUnavailable
 	}
 	if id <= 0 {
 		return gorm.ErrRecordNotFound
 	}
-	return s.repo.DeletePost(ctx, id)
-}
-
-func normalizeStatus(status string) string {
-	trimmed := strings.TrimSpace(status)
-	if trimmed == "" {
-		return "0"
-	}
-	return trimmed
+	return s.repo.DeletePost(ctx, id, strings.TrimSpace(operator))
 }
 
 func normalizeRemark(remark *string) *string {
@@ -314,11 +312,12 @@ func postFromModel(record *model.SysPost) *Post {
 		return nil
 	}
 	return &Post{
-		PostID:   int64(record.ID),
-		PostCode: record.PostCode,
-		PostName: record.PostName,
-		PostSort: record.PostSort,
-		Status:   record.Status,
-		Remark:   record.Remark,
+		PostID:    int64(record.ID),
+		PostCode:  record.PostCode,
+		PostName:  record.PostName,
+		PostSort:  record.PostSort,
+		Status:    record.Status,
+		Remark:    record.Remark,
+		UpdatedAt: &record.UpdatedAt,
 	}
 }
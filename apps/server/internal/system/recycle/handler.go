@@ -0,0 +1,201 @@
+package recycle
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/resp"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	if service == nil {
+		return nil
+	}
+	return &Handler{service: service}
+}
+
+type listItemsQuery struct {
+	PageNum  int    `form:"pageNum"`
+	PageSize int    `form:"pageSize"`
+	Keyword  string `form:"keyword"`
+}
+
+// Summary godoc
+// @Summary 获取回收站概览
+// @Description 返回各模块回收站中的记录数量
+// @Tags System/Recycle
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/recycle-bin [get]
+func (h *Handler) Summary(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("recycle bin service unavailable"))
+		return
+	}
+
+	items, err := h.service.ListResources(ctx.Request.Context())
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load recycle bin"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(items))
+}
+
+// List godoc
+// @Summary 获取回收站记录
+// @Description 分页查询指定模块已删除的记录，包含删除时间与删除人
+// @Tags System/Recycle
+// @Security BearerAuth
+// @Produce json
+// @Param resource path string true "模块：user/role/dept/post/dict_type/dict_data/config/notice"
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Param keyword query string false "名称关键字"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/recycle-bin/{resource} [get]
+func (h *Handler) List(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("recycle bin service unavailable"))
+		return
+	}
+
+	var query listItemsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	result, err := h.service.ListItems(ctx.Request.Context(), ctx.Param("resource"), ListOptions{
+		PageNum:  query.PageNum,
+		PageSize: query.PageSize,
+		Keyword:  query.Keyword,
+	})
+	if err != nil {
+		if errors.Is(err, ErrUnknownResource) {
+			resp.NotFound(ctx, resp.WithMessage(err.Error()))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to list recycle bin"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(result))
+}
+
+// Restore godoc
+// @Summary 恢复已删除记录
+// @Description 恢复回收站中的记录，与现有数据冲突或上级已删除时拒绝恢复
+// @Tags System/Recycle
+// @Security BearerAuth
+// @Produce json
+// @Param resource path string true "模块"
+// @Param id path int true "记录ID"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 409 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/recycle-bin/{resource}/{id}/restore [post]
+func (h *Handler) Restore(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("recycle bin service unavailable"))
+		return
+	}
+
+	id, err := parseRecordID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid record id"))
+		return
+	}
+
+	if err := h.service.Restore(ctx.Request.Context(), ctx.Param("resource"), id, resolveOperator(ctx)); err != nil {
+		switch {
+		case errors.Is(err, ErrUnknownResource):
+			resp.NotFound(ctx, resp.WithMessage(err.Error()))
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			resp.NotFound(ctx, resp.WithMessage("deleted record not found"))
+		case errors.Is(err, ErrRestoreConflict), errors.Is(err, ErrParentDeleted):
+			resp.Conflict(ctx, resp.WithMessage(err.Error()))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to restore record"))
+		}
+		return
+	}
+
+	resp.OK(ctx, resp.WithMessage("record restored"))
+}
+
+// Purge godoc
+// @Summary 彻底删除记录
+// @Description 从回收站中彻底删除记录及其关联数据，操作不可恢复
+// @Tags System/Recycle
+// @Security BearerAuth
+// @Produce json
+// @Param resource path string true "模块"
+// @Param id path int true "记录ID"
+// @Success 204 {object} nil
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/recycle-bin/{resource}/{id} [delete]
+func (h *Handler) Purge(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("recycle bin service unavailable"))
+		return
+	}
+
+	id, err := parseRecordID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid record id"))
+		return
+	}
+
+	if err := h.service.Purge(ctx.Request.Context(), ctx.Param("resource"), id); err != nil {
+		switch {
+		case errors.Is(err, ErrUnknownResource):
+			resp.NotFound(ctx, resp.WithMessage(err.Error()))
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			resp.NotFound(ctx, resp.WithMessage("deleted record not found"))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to purge record"))
+		}
+		return
+	}
+
+	resp.NoContent(ctx)
+}
+
+func parseRecordID(param string) (int64, error) {
+	id, err := strconv.ParseInt(strings.TrimSpace(param), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("invalid record id")
+	}
+	return id, nil
+}
+
+func resolveOperator(ctx *gin.Context) string {
+	id, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,213 @@
+package recycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRepositoryUnavailable = errors.New("recycle bin repository is not initialized")
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	if db == nil {
+		return nil
+	}
+	return &Repository{db: db}
+}
+
+type ListOptions struct {
+	PageNum  int
+	PageSize int
+	Keyword  string
+}
+
+// deletedRow 为回收站列表的查询结果
+type deletedRow struct {
+	ID        int64
+	Name      string
+	DeletedAt time.Time
+	DeletedBy string
+}
+
+func (r *Repository) ListDeleted(ctx context.Context, res *resource, opts ListOptions) ([]deletedRow, int64, error) {
+	if r == nil || r.db == nil {
+		return nil, 0, ErrRepositoryUnavailable
+	}
+
+	base := r.deletedQuery(ctx, res)
+	if keyword := strings.TrimSpace(opts.Keyword); keyword != "" {
+		base = base.Where(res.nameColumn+" ILIKE ?", "%"+keyword+"%")
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []deletedRow{}, 0, nil
+	}
+
+	dataQuery := base.Session(&gorm.Session{}).
+		Select("id, " + res.nameColumn + " AS name, deleted_at, update_by AS deleted_by").
+		Order("deleted_at DESC, id DESC")
+	if opts.PageSize > 0 {
+		pageNum := opts.PageNum
+		if pageNum <= 0 {
+			pageNum = 1
+		}
+		dataQuery = dataQuery.Offset((pageNum - 1) * opts.PageSize).Limit(opts.PageSize)
+	}
+
+	var rows []deletedRow
+	if err := dataQuery.Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+func (r *Repository) CountDeleted(ctx context.Context, res *resource) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, ErrRepositoryUnavailable
+	}
+
+	var total int64
+	err := r.deletedQuery(ctx, res).Count(&total).Error
+	return total, err
+}
+
+// ListExpiredIDs 返回删除时间早于 before 的记录 ID
+func (r *Repository) ListExpiredIDs(ctx context.Context, res *resource, before time.Time, limit int) ([]int64, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	var ids []int64
+	err := r.deletedQuery(ctx, res).
+		Where("deleted_at < ?", before).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Restore 在事务内校验冲突并恢复记录及随之删除的关联数据
+func (r *Repository) Restore(ctx context.Context, res *resource, id int64, operator string, at time.Time) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if id <= 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Unscoped().
+			Model(res.newModel()).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if res.checkRestore != nil {
+			if err := res.checkRestore(tx, id); err != nil {
+				return err
+			}
+		}
+		if res.restoreRelated != nil {
+			if err := res.restoreRelated(tx, id); err != nil {
+				return err
+			}
+		}
+
+		return tx.Unscoped().
+			Model(res.newModel()).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"deleted_at": nil,
+				"update_by":  operator,
+				"updated_at": at,
+			}).Error
+	})
+}
+
+// Purge 彻底删除处于删除状态的记录及其关联数据，返回实际删除的数量
+func (r *Repository) Purge(ctx context.Context, res *resource, ids []int64) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, ErrRepositoryUnavailable
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var purged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deleted []int64
+		if err := tx.Unscoped().
+			Model(res.newModel()).
+			Where("id IN ? AND deleted_at IS NOT NULL", ids).
+			Pluck("id", &deleted).Error; err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+
+		if res.purgeRelated != nil {
+			if err := res.purgeRelated(tx, deleted); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Unscoped().Where("id IN ?", deleted).Delete(res.newModel())
+		if result.Error != nil {
+			return result.Error
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	return purged, err
+}
+
+// cacheKey 记录所属租户及其缓存字段的取值
+type cacheKey struct {
+	TenantID int64
+	Key      string
+}
+
+// ListCacheKeys 返回记录所属租户及 cacheColumn 的取值，包括已删除的记录
+func (r *Repository) ListCacheKeys(ctx context.Context, res *resource, ids []int64) ([]cacheKey, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	if res.cacheColumn == "" || len(ids) == 0 {
+		return nil, nil
+	}
+
+	var keys []cacheKey
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(res.newModel()).
+		Select("tenant_id, "+res.cacheColumn+" AS key").
+		Where("id IN ?", ids).
+		Scan(&keys).Error
+	return keys, err
+}
+
+func (r *Repository) deletedQuery(ctx context.Context, res *resource) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Unscoped().
+		Model(res.newModel()).
+		Where("deleted_at IS NOT NULL")
+}
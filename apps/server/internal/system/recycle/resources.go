@@ -0,0 +1,322 @@
+package recycle
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/i18n"
+)
+
+// resource 描述一类可进入回收站的系统数据
+type resource struct {
+	key   string
+	label string
+	// newModel 返回对应表的模型，用于确定表名与软删除字段
+	newModel func() interface{}
+	// nameColumn 列表中展示的名称字段
+	nameColumn string
+	// checkRestore 校验恢复后是否与现有数据冲突，tx 中记录仍处于删除状态
+	checkRestore func(tx *gorm.DB, id int64) error
+	// restoreRelated 恢复与记录一同删除的关联数据
+	restoreRelated func(tx *gorm.DB, id int64) error
+	// purgeRelated 彻底删除记录前清理关联数据
+	purgeRelated func(tx *gorm.DB, ids []int64) error
+	// cacheColumn 其他模块缓存所依据的字段，恢复或彻底删除后按其取值通知缓存失效
+	cacheColumn string
+}
+
+var resources = []resource{
+	{
+		key:        "user",
+		label:      "用户",
+		newModel:   func() interface{} { return &model.SysUser{} },
+		nameColumn: "user_name",
+		checkRestore: func(tx *gorm.DB, id int64) error {
+			var record model.SysUser
+			if err := tx.Unscoped().First(&record, id).Error; err != nil {
+				return err
+			}
+			return ensureAbsent(tx, &model.SysUser{}, fmt.Sprintf("username %q already exists", record.UserName),
+				"user_name = ?", record.UserName)
+		},
+		purgeRelated: func(tx *gorm.DB, ids []int64) error {
+			if err := tx.Where("user_id IN ?", ids).Delete(&model.SysUserRole{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("user_id IN ?", ids).Delete(&model.SysUserPost{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("user_id IN ?", ids).Delete(&model.SysUserAttr{}).Error; err != nil {
+				return err
+			}
+			return tx.Where("user_id IN ?", ids).Delete(&model.SysNoticeRead{}).Error
+		},
+	},
+	{
+		// 删除角色时已清空其菜单授权，恢复后需重新分配
+		key:        "role",
+		label:      "角色",
+		newModel:   func() interface{} { return &model.SysRole{} },
+		nameColumn: "role_name",
+		checkRestore: func(tx *gorm.DB, id int64) error {
+			var record model.SysRole
+			if err := tx.Unscoped().First(&record, id).Error; err != nil {
+				return err
+			}
+			if record.ParentID != 0 {
+				if err := ensurePresent(tx, &model.SysRole{}, "parent role is deleted", "id = ?", record.ParentID); err != nil {
+					return err
+				}
+			}
+			if err := ensureAbsent(tx, &model.SysRole{}, fmt.Sprintf("role name %q already exists", record.RoleName),
+				"role_name = ?", record.RoleName); err != nil {
+				return err
+			}
+			return ensureAbsent(tx, &model.SysRole{}, fmt.Sprintf("role key %q already exists", record.RoleKey),
+				"role_key = ?", record.RoleKey)
+		},
+		purgeRelated: func(tx *gorm.DB, ids []int64) error {
+			if err := tx.Where("role_id IN ?", ids).Delete(&model.SysRoleMenu{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("role_id IN ?", ids).Delete(&model.SysRoleDept{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("role_id IN ?", ids).Delete(&model.SysDeptRole{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("role_id IN ?", ids).Delete(&model.SysPostRole{}).Error; err != nil {
+				return err
+			}
+			return tx.Where("role_id IN ?", ids).Delete(&model.SysUserRole{}).Error
+		},
+	},
+	{
+		key:        "dept",
+		label:      "部门",
+		newModel:   func() interface{} { return &model.SysDept{} },
+		nameColumn: "dept_name",
+		checkRestore: func(tx *gorm.DB, id int64) error {
+			var record model.SysDept
+			if err := tx.Unscoped().First(&record, id).Error; err != nil {
+				return err
+			}
+			if record.ParentID != 0 {
+				if err := ensurePresent(tx, &model.SysDept{}, "parent department is deleted", "id = ?", record.ParentID); err != nil {
+					return err
+				}
+			}
+			return ensureAbsent(tx, &model.SysDept{}, fmt.Sprintf("department %q already exists under the parent", record.DeptName),
+				"parent_id = ? AND dept_name = ?", record.ParentID, record.DeptName)
+		},
+		purgeRelated: func(tx *gorm.DB, ids []int64) error {
+			if err := tx.Where("dept_id IN ?", ids).Delete(&model.SysRoleDept{}).Error; err != nil {
+				return err
+			}
+			return tx.Where("dept_id IN ?", ids).Delete(&model.SysDeptRole{}).Error
+		},
+	},
+	{
+		key:        "post",
+		label:      "岗位",
+		newModel:   func() interface{} { return &model.SysPost{} },
+		nameColumn: "post_name",
+		checkRestore: func(tx *gorm.DB, id int64) error {
+			var record model.SysPost
+			if err := tx.Unscoped().First(&record, id).Error; err != nil {
+				return err
+			}
+			if err := ensureAbsent(tx, &model.SysPost{}, fmt.Sprintf("post code %q already exists", record.PostCode),
+				"post_code = ?", record.PostCode); err != nil {
+				return err
+			}
+			return ensureAbsent(tx, &model.SysPost{}, fmt.Sprintf("post name %q already exists", record.PostName),
+				"post_name = ?", record.PostName)
+		},
+		purgeRelated: func(tx *gorm.DB, ids []int64) error {
+			if err := tx.Where("post_id IN ?", ids).Delete(&model.SysUserPost{}).Error; err != nil {
+				return err
+			}
+			return tx.Where("post_id IN ?", ids).Delete(&model.SysPostRole{}).Error
+		},
+	},
+	{
+		key:         "dict_type",
+		label:       "字典类型",
+		newModel:    func() interface{} { return &model.SysDictType{} },
+		nameColumn:  "dict_name",
+		cacheColumn: "dict_type",
+		checkRestore: func(tx *gorm.DB, id int64) error {
+			var record model.SysDictType
+			if err := tx.Unscoped().First(&record, id).Error; err != nil {
+				return err
+			}
+			return ensureAbsent(tx, &model.SysDictType{}, fmt.Sprintf("dictionary type %q already exists", record.DictType),
+				"dict_type = ?", record.DictType)
+		},
+		// 删除字典类型时其数据使用相同的删除时间，恢复时一并恢复
+		restoreRelated: func(tx *gorm.DB, id int64) error {
+			var record model.SysDictType
+			if err := tx.Unscoped().First(&record, id).Error; err != nil {
+				return err
+			}
+			deletedAt := tx.Unscoped().Model(&model.SysDictType{}).Select("deleted_at").Where("id = ?", id)
+			return tx.Unscoped().
+				Model(&model.SysDictData{}).
+				Where("dict_type = ? AND deleted_at = (?)", record.DictType, deletedAt).
+				Update("deleted_at", nil).Error
+		},
+		purgeRelated: func(tx *gorm.DB, ids []int64) error {
+			var dictTypes []string
+			if err := tx.Unscoped().Model(&model.SysDictType{}).Where("id IN ?", ids).Pluck("dict_type", &dictTypes).Error; err != nil {
+				return err
+			}
+			if len(dictTypes) == 0 {
+				return nil
+			}
+			if err := tx.Unscoped().
+				Where("dict_type IN ? AND deleted_at IS NOT NULL", dictTypes).
+				Delete(&model.SysDictData{}).Error; err != nil {
+				return err
+			}
+			for _, dictType := range dictTypes {
+				if err := purgeDictTranslations(tx, dictType, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		key:         "dict_data",
+		label:       "字典数据",
+		newModel:    func() interface{} { return &model.SysDictData{} },
+		nameColumn:  "dict_label",
+		cacheColumn: "dict_type",
+		checkRestore: func(tx *gorm.DB, id int64) error {
+			var record model.SysDictData
+			if err := tx.Unscoped().First(&record, id).Error; err != nil {
+				return err
+			}
+			if err := ensurePresent(tx, &model.SysDictType{}, "dictionary type is deleted", "dict_type = ?", record.DictType); err != nil {
+				return err
+			}
+			if record.ParentID != 0 {
+				if err := ensurePresent(tx, &model.SysDictData{}, "parent dictionary data is deleted", "id = ?", record.ParentID); err != nil {
+					return err
+				}
+			}
+			return ensureAbsent(tx, &model.SysDictData{}, fmt.Sprintf("dictionary value %q already exists", record.DictValue),
+				"dict_type = ? AND dict_value = ?", record.DictType, record.DictValue)
+		},
+		// 级联删除的下级与上级使用相同的删除时间，恢复时逐层一并恢复
+		restoreRelated: func(tx *gorm.DB, id int64) error {
+			deletedAt := tx.Unscoped().Model(&model.SysDictData{}).Select("deleted_at").Where("id = ?", id)
+			for parents := []int64{id}; len(parents) > 0; {
+				var children []int64
+				if err := tx.Unscoped().Model(&model.SysDictData{}).
+					Where("parent_id IN ? AND deleted_at = (?)", parents, deletedAt).
+					Pluck("id", &children).Error; err != nil {
+					return err
+				}
+				if len(children) == 0 {
+					return nil
+				}
+				if err := tx.Unscoped().Model(&model.SysDictData{}).Where("id IN ?", children).Update("deleted_at", nil).Error; err != nil {
+					return err
+				}
+				parents = children
+			}
+			return nil
+		},
+		purgeRelated: func(tx *gorm.DB, ids []int64) error {
+			var items []model.SysDictData
+			if err := tx.Unscoped().Select("dict_type", "dict_value").Where("id IN ?", ids).Find(&items).Error; err != nil {
+				return err
+			}
+			values := make(map[string][]string)
+			for _, item := range items {
+				values[item.DictType] = append(values[item.DictType], item.DictValue)
+			}
+			for dictType, dictValues := range values {
+				if err := purgeDictTranslations(tx, dictType, dictValues); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		key:         "config",
+		label:       "参数配置",
+		newModel:    func() interface{} { return &model.SysConfig{} },
+		nameColumn:  "config_key",
+		cacheColumn: "config_key",
+		checkRestore: func(tx *gorm.DB, id int64) error {
+			var record model.SysConfig
+			if err := tx.Unscoped().First(&record, id).Error; err != nil {
+				return err
+			}
+			return ensureAbsent(tx, &model.SysConfig{}, fmt.Sprintf("config key %q already exists", record.ConfigKey),
+				"config_key = ?", record.ConfigKey)
+		},
+	},
+	{
+		key:        "notice",
+		label:      "通知公告",
+		newModel:   func() interface{} { return &model.SysNotice{} },
+		nameColumn: "notice_title",
+		purgeRelated: func(tx *gorm.DB, ids []int64) error {
+			if err := tx.Where("notice_id IN ?", ids).Delete(&model.SysNoticeTarget{}).Error; err != nil {
+				return err
+			}
+			return tx.Where("notice_id IN ?", ids).Delete(&model.SysNoticeRead{}).Error
+		},
+	},
+}
+
+// purgeDictTranslations 删除字典数据的标签译文，values 为 nil 时删除整个字典类型的译文；
+// 同类型下仍有相同取值的有效数据时保留其译文，删除后重建的数据沿用原译文
+func purgeDictTranslations(tx *gorm.DB, dictType string, values []string) error {
+	live := tx.Model(&model.SysDictData{}).Select("dict_value").Where("dict_type = ?", dictType)
+	query := tx.Unscoped().Where("resource = ? AND scope = ? AND item_key NOT IN (?)", i18n.ResourceDict, dictType, live)
+	if values != nil {
+		query = query.Where("item_key IN ?", values)
+	}
+	return query.Delete(&model.SysTranslation{}).Error
+}
+
+func lookupResource(key string) (*resource, bool) {
+	for i := range resources {
+		if resources[i].key == key {
+			return &resources[i], true
+		}
+	}
+	return nil, false
+}
+
+// ensureAbsent 要求不存在满足条件的未删除记录
+func ensureAbsent(tx *gorm.DB, value interface{}, message string, query string, args ...interface{}) error {
+	var count int64
+	if err := tx.Model(value).Where(query, args...).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %s", ErrRestoreConflict, message)
+	}
+	return nil
+}
+
+// ensurePresent 要求存在满足条件的未删除记录，通常用于校验上级数据
+func ensurePresent(tx *gorm.DB, value interface{}, message string, query string, args ...interface{}) error {
+	var count int64
+	if err := tx.Model(value).Where(query, args...).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %s", ErrParentDeleted, message)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package recycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+var (
+	ErrServiceUnavailable = errors.New("recycle bin service is not initialized")
+	ErrUnknownResource    = errors.New("unknown recycle bin resource")
+	ErrRestoreConflict    = errors.New("restore conflicts with existing data")
+	ErrParentDeleted      = errors.New("parent record is deleted")
+)
+
+// purgeBatchSize 定期清理时每批彻底删除的记录数
+const purgeBatchSize = 500
+
+type Service struct {
+	repo *Repository
+	// handlers 按模块注册的缓存失效回调，仅在启动时注册
+	handlers map[string][]ChangeHandler
+}
+
+// ChangeHandler 接收被恢复或彻底删除记录的缓存字段取值，ctx 绑定记录所属租户
+type ChangeHandler func(ctx context.Context, keys ...string)
+
+func NewService(repo *Repository) *Service {
+	if repo == nil {
+		return nil
+	}
+	return &Service{repo: repo, handlers: make(map[string][]ChangeHandler)}
+}
+
+// OnChange 注册模块记录被恢复或彻底删除后的回调，用于清除依赖这些记录的缓存
+func (s *Service) OnChange(key string, fn ChangeHandler) {
+	if s == nil || fn == nil {
+		return
+	}
+	s.handlers[key] = append(s.handlers[key], fn)
+}
+
+type ResourceSummary struct {
+	Resource string `json:"resource"`
+	Label    string `json:"label"`
+	Count    int64  `json:"count"`
+}
+
+type Item struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deletedAt"`
+	DeletedBy string    `json:"deletedBy"`
+}
+
+type ListResult struct {
+	Resource string `json:"resource"`
+	Label    string `json:"label"`
+	List     []Item `json:"list"`
+	Total    int64  `json:"total"`
+	PageNum  int    `json:"pageNum"`
+	PageSize int    `json:"pageSize"`
+}
+
+// ListResources 返回各模块回收站中的记录数量
+func (s *Service) ListResources(ctx context.Context) ([]ResourceSummary, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	items := make([]ResourceSummary, 0, len(resources))
+	for i := range resources {
+		count, err := s.repo.CountDeleted(ctx, &resources[i])
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, ResourceSummary{
+			Resource: resources[i].key,
+			Label:    resources[i].label,
+			Count:    count,
+		})
+	}
+	return items, nil
+}
+
+func (s *Service) ListItems(ctx context.Context, key string, opts ListOptions) (*ListResult, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	res, ok := lookupResource(key)
+	if !ok {
+		return nil, ErrUnknownResource
+	}
+
+	if opts.PageNum <= 0 {
+		opts.PageNum = 1
+	}
+	if opts.PageSize < 0 {
+		opts.PageSize = 0
+	}
+
+	rows, total, err := s.repo.ListDeleted(ctx, res, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, Item(row))
+	}
+	return &ListResult{
+		Resource: res.key,
+		Label:    res.label,
+		List:     items,
+		Total:    total,
+		PageNum:  opts.PageNum,
+		PageSize: opts.PageSize,
+	}, nil
+}
+
+// Restore 恢复一条已删除的记录，存在唯一性冲突或上级已删除时拒绝恢复
+func (s *Service) Restore(ctx context.Context, key string, id int64, operator string) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+	res, ok := lookupResource(key)
+	if !ok {
+		return ErrUnknownResource
+	}
+	keys, err := s.cacheKeys(ctx, res, []int64{id})
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Restore(ctx, res, id, strings.TrimSpace(operator), time.Now()); err != nil {
+		return err
+	}
+	s.notify(ctx, res, keys)
+	return nil
+}
+
+// Purge 彻底删除一条已删除的记录
+func (s *Service) Purge(ctx context.Context, key string, id int64) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+	res, ok := lookupResource(key)
+	if !ok {
+		return ErrUnknownResource
+	}
+	if id <= 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	keys, err := s.cacheKeys(ctx, res, []int64{id})
+	if err != nil {
+		return err
+	}
+	purged, err := s.repo.Purge(ctx, res, []int64{id})
+	if err != nil {
+		return err
+	}
+	if purged == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	s.notify(ctx, res, keys)
+	return nil
+}
+
+// PurgeExpired 彻底删除删除时间早于 before 的记录，keys 为空时处理全部模块，返回各模块清理数量
+func (s *Service) PurgeExpired(ctx context.Context, before time.Time, keys []string) (map[string]int64, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	targets := make([]*resource, 0, len(resources))
+	if len(keys) == 0 {
+		for i := range resources {
+			targets = append(targets, &resources[i])
+		}
+	} else {
+		for _, key := range keys {
+			res, ok := lookupResource(strings.TrimSpace(key))
+			if !ok {
+				return nil, ErrUnknownResource
+			}
+			targets = append(targets, res)
+		}
+	}
+
+	result := make(map[string]int64, len(targets))
+	for _, res := range targets {
+		for {
+			ids, err := s.repo.ListExpiredIDs(ctx, res, before, purgeBatchSize)
+			if err != nil {
+				return result, err
+			}
+			if len(ids) == 0 {
+				break
+			}
+			keys, err := s.cacheKeys(ctx, res, ids)
+			if err != nil {
+				return result, err
+			}
+			purged, err := s.repo.Purge(ctx, res, ids)
+			if err != nil {
+				return result, err
+			}
+			s.notify(ctx, res, keys)
+			result[res.key] += purged
+			if len(ids) < purgeBatchSize {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// cacheKeys 在写入前读取记录的缓存字段，彻底删除后已无法查询；模块未注册回调时跳过
+func (s *Service) cacheKeys(ctx context.Context, res *resource, ids []int64) ([]cacheKey, error) {
+	if len(s.handlers[res.key]) == 0 {
+		return nil, nil
+	}
+	return s.repo.ListCacheKeys(ctx, res, ids)
+}
+
+// notify 按租户分组调用回调，定期清理任务不绑定租户，需逐个租户通知
+func (s *Service) notify(ctx context.Context, res *resource, keys []cacheKey) {
+	if len(keys) == 0 {
+		return
+	}
+	byTenant := make(map[int64][]string)
+	for _, key := range keys {
+		byTenant[key.TenantID] = append(byTenant[key.TenantID], key.Key)
+	}
+	for tenantID, values := range byTenant {
+		tenantCtx := tenant.WithID(ctx, tenantID)
+		for _, fn := range s.handlers[res.key] {
+			fn(tenantCtx, values...)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+var (
+	ErrInvalidDeptBinding = errors.New("invalid department binding")
+	ErrInvalidPostBinding = errors.New("invalid post binding")
+)
+
+// DeptBinding 角色绑定的部门，部门内用户自动继承该角色
+type DeptBinding struct {
+	DeptID int64 `json:"deptId"`
+	// IncludeChildren 为真时下级部门的用户同样继承
+	IncludeChildren bool `json:"includeChildren"`
+}
+
+// loadBindings 读取角色绑定的部门与岗位
+func (s *Service) loadBindings(ctx context.Context, roleID int64) ([]DeptBinding, []int64, error) {
+	records, postIDs, err := s.repo.GetRoleBindings(ctx, roleID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	depts := make([]DeptBinding, 0, len(records))
+	for _, record := range records {
+		depts = append(depts, DeptBinding{DeptID: record.DeptID, IncludeChildren: record.IncludeChildren})
+	}
+	return depts, postIDs, nil
+}
+
+// validateDeptBindings 去重并校验绑定的部门均存在，重复部门以包含下级为准
+func (s *Service) validateDeptBindings(ctx context.Context, bindings []DeptBinding) ([]model.SysDeptRole, error) {
+	merged := make(map[int64]bool, len(bindings))
+	for _, binding := range bindings {
+		if binding.DeptID <= 0 {
+			return nil, ErrInvalidDeptBinding
+		}
+		merged[binding.DeptID] = merged[binding.DeptID] || binding.IncludeChildren
+	}
+	if len(merged) == 0 {
+		return []model.SysDeptRole{}, nil
+	}
+
+	ids := make([]int64, 0, len(merged))
+	for id := range merged {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	count, err := s.repo.CountDepartments(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if count != int64(len(ids)) {
+		return nil, ErrInvalidDeptBinding
+	}
+
+	result := make([]model.SysDeptRole, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, model.SysDeptRole{DeptID: id, IncludeChildren: merged[id]})
+	}
+	return result, nil
+}
+
+// validatePostBindings 去重并校验绑定的岗位均存在
+func (s *Service) validatePostBindings(ctx context.Context, ids []int64) ([]int64, error) {
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, ErrInvalidPostBinding
+		}
+	}
+	sanitized := sanitizeMenuIDs(ids)
+	if len(sanitized) == 0 {
+		return sanitized, nil
+	}
+
+	count, err := s.repo.CountPosts(ctx, sanitized)
+	if err != nil {
+		return nil, err
+	}
+	if count != int64(len(sanitized)) {
+		return nil, ErrInvalidPostBinding
+	}
+	return sanitized, nil
+}
@@ -0,0 +1,87 @@
+package role
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+const exportBatchSize = 500
+
+var exportHeader = []string{"角色编号", "角色名称", "权限字符", "显示顺序", "数据范围", "状态", "创建时间", "备注"}
+
+var (
+	exportDataScopeLabels = map[string]string{
+		"1": "全部数据权限",
+		"2": "自定数据权限",
+		"3": "本部门数据权限",
+		"4": "本部门及以下数据权限",
+		"5": "仅本人数据权限",
+	}
+	exportStatusLabels = map[string]string{"0": "正常", "1": "停用"}
+)
+
+// ExportRoles 按列表筛选条件分批导出角色，先输出表头，再逐行回调
+func (s *Service) ExportRoles(ctx context.Context, opts QueryOptions, fn func([]string) error) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+
+	status := strings.TrimSpace(opts.Status)
+	if status == "all" {
+		status = ""
+	}
+
+	headerWritten := false
+	err := s.repo.ExportRoles(ctx, ListOptions{
+		RoleName: opts.RoleName,
+		Status:   status,
+	}, exportBatchSize, func(records []model.SysRole) error {
+		if !headerWritten {
+			headerWritten = true
+			if err := fn(exportHeader); err != nil {
+				return err
+			}
+		}
+		for i := range records {
+			if err := fn(exportRoleRow(&records[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !headerWritten {
+		return fn(exportHeader)
+	}
+	return nil
+}
+
+func exportRoleRow(record *model.SysRole) []string {
+	remark := ""
+	if record.Remark != nil {
+		remark = *record.Remark
+	}
+	return []string{
+		strconv.FormatUint(uint64(record.ID), 10),
+		record.RoleName,
+		record.RoleKey,
+		strconv.Itoa(record.RoleSort),
+		labelOf(exportDataScopeLabels, record.DataScope),
+		labelOf(exportStatusLabels, record.Status),
+		record.CreatedAt.Format(time.DateTime),
+		remark,
+	}
+}
+
+func labelOf(labels map[string]string, value string) string {
+	if label, ok := labels[value]; ok {
+		return label
+	}
+	return value
+}
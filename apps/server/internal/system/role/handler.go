@@ -3,12 +3,15 @@ package role
 import (
 	"errors"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 	"github.com/starter-kit-fe/admin/pkg/resp"
+	"github.com/starter-kit-fe/admin/pkg/spreadsheet"
 )
 
 type Handler struct {
@@ -29,28 +32,43 @@ type listRolesQuery struct {
 	Status   string `form:"status"`
 }
 
+type exportRolesQuery struct {
+	RoleName string `form:"roleName"`
+	Status   string `form:"status"`
+}
+
 type createRoleRequest struct {
 	RoleName          string  `json:"roleName" binding:"required"`
 	RoleKey           string  `json:"roleKey" binding:"required"`
 	RoleSort          *int    `json:"roleSort"`
+	ParentID          int64   `json:"parentId"`
 	DataScope         string  `json:"dataScope"`
 	MenuCheckStrictly bool    `json:"menuCheckStrictly"`
 	DeptCheckStrictly bool    `json:"deptCheckStrictly"`
 	Status            string  `json:"status"`
 	Remark            *string `json:"remark"`
 	MenuIDs           []int64 `json:"menuIds"`
+	// DeptBindings 绑定的部门，部门成员自动继承该角色
+	DeptBindings []DeptBinding `json:"deptBindings"`
+	// PostIDs 绑定的岗位，担任岗位的用户自动继承该角色
+	PostIDs []int64 `json:"postIds"`
 }
 
 type updateRoleRequest struct {
 	RoleName          *string  `json:"roleName"`
 	RoleKey           *string  `json:"roleKey"`
 	RoleSort          *int     `json:"roleSort"`
+	ParentID          *int64   `json:"parentId"`
 	DataScope         *string  `json:"dataScope"`
 	MenuCheckStrictly *bool    `json:"menuCheckStrictly"`
 	DeptCheckStrictly *bool    `json:"deptCheckStrictly"`
 	Status            *string  `json:"status"`
 	Remark            *string  `json:"remark"`
 	MenuIDs           *[]int64 `json:"menuIds"`
+	// DeptBindings 绑定的部门，传入时覆盖原有绑定
+	DeptBindings *[]DeptBinding `json:"deptBindings"`
+	// PostIDs 绑定的岗位，传入时覆盖原有绑定
+	PostIDs *[]int64 `json:"postIds"`
 }
 
 // List godoc
@@ -94,6 +112,55 @@ func (h *Handler) List(ctx *gin.Context) {
 	resp.OK(ctx, resp.WithData(result))
 }
 
+// Export godoc
+// @Summary 导出角色
+// @Description 按列表筛选条件导出角色
+// @Tags System/Role
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param roleName query string false "角色名称"
+// @Param status query string false "角色状态"
+// @Param format query string false "导出格式：csv 或 xlsx，默认 csv"
+// @Success 200 {file} file
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/roles/export [get]
+func (h *Handler) Export(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("role service unavailable"))
+		return
+	}
+
+	var query exportRolesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	format, err := spreadsheet.ParseFormat(ctx.Query("format"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("unsupported export format"))
+		return
+	}
+
+	out := spreadsheet.NewAttachment(ctx.Writer, format, "roles_"+time.Now().Format("20060102150405"), "roles")
+	err = h.service.ExportRoles(ctx.Request.Context(), QueryOptions{
+		RoleName: query.RoleName,
+		Status:   query.Status,
+	}, out.WriteRow)
+	if err == nil {
+		err = out.Close()
+	}
+	if err != nil {
+		if out.Started() {
+			_ = ctx.Error(err)
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to export roles"))
+	}
+}
+
 // Get godoc
 // @Summary 获取角色详情
 // @Description 根据ID查询角色信息
@@ -102,6 +169,7 @@ func (h *Handler) List(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "角色ID"
 // @Success 200 {object} resp.Response
+// @Header 200 {string} ETag "角色版本，修改或删除时通过 If-Match 回传"
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 500 {object} resp.Response
@@ -129,6 +197,7 @@ func (h *Handler) Get(ctx *gin.Context) {
 		return
 	}
 
+	etag.Set(ctx, *role.UpdatedAt)
 	resp.OK(ctx, resp.WithData(role))
 }
 
@@ -163,6 +232,7 @@ func (h *Handler) Create(ctx *gin.Context) {
 		RoleName:          payload.RoleName,
 		RoleKey:           payload.RoleKey,
 		RoleSort:          payload.RoleSort,
+		ParentID:          payload.ParentID,
 		DataScope:         payload.DataScope,
 		MenuCheckStrictly: payload.MenuCheckStrictly,
 		DeptCheckStrictly: payload.DeptCheckStrictly,
@@ -170,6 +240,8 @@ func (h *Handler) Create(ctx *gin.Context) {
 		Remark:            payload.Remark,
 		Operator:          operator,
 		MenuIDs:           payload.MenuIDs,
+		DeptBindings:      payload.DeptBindings,
+		PostIDs:           payload.PostIDs,
 	})
 	if err != nil {
 		switch {
@@ -189,6 +261,14 @@ func (h *Handler) Create(ctx *gin.Context) {
 			resp.Conflict(ctx, resp.WithMessage("role key already exists"))
 		case errors.Is(err, ErrInvalidMenuSelection):
 			resp.BadRequest(ctx, resp.WithMessage("invalid menu selection"))
+		case errors.Is(err, ErrInvalidDeptBinding):
+			resp.BadRequest(ctx, resp.WithMessage("invalid department binding"))
+		case errors.Is(err, ErrInvalidPostBinding):
+			resp.BadRequest(ctx, resp.WithMessage("invalid post binding"))
+		case errors.Is(err, ErrInvalidParentRole):
+			resp.BadRequest(ctx, resp.WithMessage("invalid parent role"))
+		case errors.Is(err, ErrRoleCycle):
+			resp.BadRequest(ctx, resp.WithMessage("parent role would create a cycle"))
 		default:
 			resp.InternalServerError(ctx, resp.WithMessage("failed to create role"))
 		}
@@ -207,10 +287,13 @@ func (h *Handler) Create(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "角色ID"
 // @Param request body updateRoleRequest true "角色参数"
+// @Param If-Match header string true "获取角色时返回的 ETag"
 // @Success 200 {object} resp.Response
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 409 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/roles/{id} [put]
@@ -232,12 +315,18 @@ func (h *Handler) Update(ctx *gin.Context) {
 		return
 	}
 
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
 	operator := resolveOperator(ctx)
 	role, err := h.service.UpdateRole(ctx.Request.Context(), UpdateRoleInput{
 		ID:                id,
 		RoleName:          payload.RoleName,
 		RoleKey:           payload.RoleKey,
 		RoleSort:          payload.RoleSort,
+		ParentID:          payload.ParentID,
 		DataScope:         payload.DataScope,
 		MenuCheckStrictly: payload.MenuCheckStrictly,
 		DeptCheckStrictly: payload.DeptCheckStrictly,
@@ -245,11 +334,15 @@ func (h *Handler) Update(ctx *gin.Context) {
 		Remark:            payload.Remark,
 		Operator:          operator,
 		MenuIDs:           payload.MenuIDs,
+		DeptBindings:      payload.DeptBindings,
+		PostIDs:           payload.PostIDs,
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("role not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("role has been modified, reload and retry"))
 		case errors.Is(err, ErrRoleNameRequired):
 			resp.BadRequest(ctx, resp.WithMessage("role name is required"))
 		case errors.Is(err, ErrRoleKeyRequired):
@@ -266,6 +359,14 @@ func (h *Handler) Update(ctx *gin.Context) {
 			resp.Conflict(ctx, resp.WithMessage("role key already exists"))
 		case errors.Is(err, ErrInvalidMenuSelection):
 			resp.BadRequest(ctx, resp.WithMessage("invalid menu selection"))
+		case errors.Is(err, ErrInvalidDeptBinding):
+			resp.BadRequest(ctx, resp.WithMessage("invalid department binding"))
+		case errors.Is(err, ErrInvalidPostBinding):
+			resp.BadRequest(ctx, resp.WithMessage("invalid post binding"))
+		case errors.Is(err, ErrInvalidParentRole):
+			resp.BadRequest(ctx, resp.WithMessage("invalid parent role"))
+		case errors.Is(err, ErrRoleCycle):
+			resp.BadRequest(ctx, resp.WithMessage("parent role would create a cycle"))
 		default:
 			resp.InternalServerError(ctx, resp.WithMessage("failed to update role"))
 		}
@@ -282,9 +383,13 @@ func (h *Handler) Update(ctx *gin.Context) {
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "角色ID"
+// @Param If-Match header string true "获取角色时返回的 ETag"
 // @Success 204 {object} nil
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 409 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/roles/{id} [delete]
@@ -300,17 +405,27 @@ func (h *Handler) Delete(ctx *gin.Context) {
 		return
 	}
 
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
 	operator := resolveOperator(ctx)
 	err = h.service.DeleteRole(ctx.Request.Context(), DeleteRoleInput{
 		ID:       id,
 		Operator: operator,
 	})
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("role not found"))
-			return
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("role has been modified, reload and retry"))
+		case errors.Is(err, ErrRoleHasChildren):
+			resp.Conflict(ctx, resp.WithMessage("role has child roles"))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to delete role"))
 		}
-		resp.InternalServerError(ctx, resp.WithMessage("failed to delete role"))
 		return
 	}
 
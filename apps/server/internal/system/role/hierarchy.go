@@ -0,0 +1,76 @@
+package role
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidParentRole = errors.New("invalid parent role")
+	ErrRoleCycle         = errors.New("parent role would create a cycle")
+	ErrRoleHasChildren   = errors.New("role has child roles")
+)
+
+// ancestorIDs 自 parentID 起沿上级链返回全部上级角色，链路中出现 roleID 时视为循环
+func (s *Service) ancestorIDs(ctx context.Context, roleID, parentID int64) ([]int64, error) {
+	ids := make([]int64, 0)
+	visited := make(map[int64]struct{})
+
+	for current := parentID; current > 0; {
+		if current == roleID {
+			return nil, ErrRoleCycle
+		}
+		if _, ok := visited[current]; ok {
+			// 历史数据已存在的循环，不再继续向上
+			break
+		}
+		visited[current] = struct{}{}
+
+		record, err := s.repo.GetRole(ctx, current)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// 上级角色已删除时不再继承
+				break
+			}
+			return nil, err
+		}
+		ids = append(ids, current)
+		current = record.ParentID
+	}
+	return ids, nil
+}
+
+// validateParent 校验上级角色存在且不会形成循环
+func (s *Service) validateParent(ctx context.Context, roleID, parentID int64) error {
+	if parentID < 0 {
+		return ErrInvalidParentRole
+	}
+	if parentID == 0 {
+		return nil
+	}
+	if parentID == roleID {
+		return ErrRoleCycle
+	}
+
+	if _, err := s.repo.GetRole(ctx, parentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidParentRole
+		}
+		return err
+	}
+
+	_, err := s.ancestorIDs(ctx, roleID, parentID)
+	return err
+}
+
+// inheritedMenuIDs 返回从上级角色继承的菜单
+func (s *Service) inheritedMenuIDs(ctx context.Context, parentID int64) ([]int64, error) {
+	// 读取时不做循环校验，visited 集合保证历史脏数据不会死循环
+	ancestors, err := s.ancestorIDs(ctx, 0, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetMenuIDsByRoles(ctx, ancestors)
+}
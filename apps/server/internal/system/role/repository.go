@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 )
 
 var (
@@ -47,16 +49,7 @@ func (r *Repository) ListRoles(ctx context.Context, opts ListOptions) ([]model.S
 		pageSize = 0
 	}
 
-	query := r.db.WithContext(ctx).
-		Model(&model.SysRole{})
-
-	if name := strings.TrimSpace(opts.RoleName); name != "" {
-		query = query.Where("role_name ILIKE ?", "%"+name+"%")
-	}
-
-	if status := strings.TrimSpace(opts.Status); status != "" {
-		query = query.Where("status = ?", status)
-	}
+	query := applyRoleFilters(r.db.WithContext(ctx).Model(&model.SysRole{}), opts)
 
 	var total int64
 	countQuery := query.Session(&gorm.Session{})
@@ -82,6 +75,31 @@ func (r *Repository) ListRoles(ctx context.Context, opts ListOptions) ([]model.S
 	return roles, total, nil
 }
 
+// ExportRoles 按列表筛选条件分批读取角色
+func (r *Repository) ExportRoles(ctx context.Context, opts ListOptions, batchSize int, fn func([]model.SysRole) error) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+
+	var batch []model.SysRole
+	result := applyRoleFilters(r.db.WithContext(ctx).Model(&model.SysRole{}), opts).
+		FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+			return fn(batch)
+		})
+	return result.Error
+}
+
+func applyRoleFilters(query *gorm.DB, opts ListOptions) *gorm.DB {
+	if name := strings.TrimSpace(opts.RoleName); name != "" {
+		query = query.Where("role_name ILIKE ?", "%"+name+"%")
+	}
+
+	if status := strings.TrimSpace(opts.Status); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return query
+}
+
 func (r *Repository) GetRole(ctx context.Context, id int64) (*model.SysRole, error) {
 	if r == nil || r.db == nil {
 		return nil, ErrRepositoryUnavailable
@@ -123,20 +141,69 @@ func (r *Repository) UpdateRole(ctx context.Context, roleID int64, updates map[s
 		return nil
 	}
 
-	result := r.db.WithContext(ctx).
-		Model(&model.SysRole{}).
-		Where("id = ?", roleID).
-		Updates(updates)
-	if result.Error != nil {
-		return result.Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if parentID, ok := updates["parent_id"].(int64); ok {
+			if err := lockParentChain(tx, roleID, parentID); err != nil {
+				return err
+			}
+		}
+
+		result := etag.Guard(tx.Model(&model.SysRole{}).Where("id = ?", roleID)).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return etag.Missed(tx, &model.SysRole{}, roleID)
+		}
+		return nil
+	})
+}
+
+// lockParentChain 以 SELECT ... FOR UPDATE 锁定角色自身及新上级链上的角色，
+// 校验上级存在且不会形成循环。并发调整上级时后到的事务会等待先到的事务提交，
+// 从而基于最新的上级链校验；SQLite 忽略行锁，由其库级写锁串行化写事务。
+func lockParentChain(tx *gorm.DB, roleID, parentID int64) error {
+	lock := func(id int64) (*model.SysRole, error) {
+		var record model.SysRole
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "parent_id").
+			Where("id = ?", id).
+			First(&record).Error
+		return &record, err
 	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+
+	if _, err := lock(roleID); err != nil {
+		return err
 	}
 
+	visited := make(map[int64]struct{})
+	for current := parentID; current > 0; {
+		if current == roleID {
+			return ErrRoleCycle
+		}
+		if _, ok := visited[current]; ok {
+			// 历史数据已存在的循环，不再继续向上
+			return nil
+		}
+		visited[current] = struct{}{}
+
+		record, err := lock(current)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if current == parentID {
+				return ErrInvalidParentRole
+			}
+			// 更上级的角色已删除，链路到此为止
+			return nil
+		}
+		current = record.ParentID
+	}
 	return nil
 }
 
+// SoftDeleteRole 在同一事务中软删除角色，并清除其部门、岗位绑定与菜单授权
 func (r *Repository) SoftDeleteRole(ctx context.Context, roleID int64, operator string, at time.Time) error {
 	if r == nil || r.db == nil {
 		return ErrRepositoryUnavailable
@@ -146,22 +213,37 @@ func (r *Repository) SoftDeleteRole(ctx context.Context, roleID int64, operator
 	}
 
 	updates := map[string]interface{}{
-		"update_time": at,
+		"updated_at": at,
 	}
 	if trimmed := strings.TrimSpace(operator); trimmed != "" {
 		updates["update_by"] = trimmed
 	}
 
-	r.db.WithContext(ctx).Model(&model.SysRole{}).Where("id = ?", roleID).Updates(updates)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := etag.Guard(tx.Model(&model.SysRole{}).Where("id = ?", roleID)).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return etag.Missed(tx, &model.SysRole{}, roleID)
+		}
+
+		result = tx.Delete(&model.SysRole{}, roleID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
 
-	result := r.db.WithContext(ctx).Delete(&model.SysRole{}, roleID)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
-	}
-	return nil
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.SysDeptRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.SysPostRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("role_id = ?", roleID).Delete(&model.SysRoleMenu{}).Error
+	})
 }
 
 func (r *Repository) ExistsByName(ctx context.Context, roleName string, excludeID int64) (bool, error) {
@@ -264,3 +346,154 @@ func (r *Repository) ReplaceRoleMenus(ctx context.Context, roleID int64, menuIDs
 		return nil
 	})
 }
+
+// GetRoleBindings 返回角色绑定的部门与岗位
+func (r *Repository) GetRoleBindings(ctx context.Context, roleID int64) ([]model.SysDeptRole, []int64, error) {
+	if r == nil || r.db == nil {
+		return nil, nil, ErrRepositoryUnavailable
+	}
+	if roleID <= 0 {
+		return []model.SysDeptRole{}, []int64{}, nil
+	}
+
+	var depts []model.SysDeptRole
+	if err := r.db.WithContext(ctx).
+		Where("role_id = ?", roleID).
+		Order("dept_id ASC").
+		Find(&depts).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var postIDs []int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.SysPostRole{}).
+		Where("role_id = ?", roleID).
+		Order("post_id ASC").
+		Pluck("post_id", &postIDs).Error; err != nil {
+		return nil, nil, err
+	}
+	if postIDs == nil {
+		postIDs = []int64{}
+	}
+	return depts, postIDs, nil
+}
+
+// ReplaceDeptBindings 覆盖角色绑定的部门
+func (r *Repository) ReplaceDeptBindings(ctx context.Context, roleID int64, bindings []model.SysDeptRole) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if roleID <= 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.SysDeptRole{}).Error; err != nil {
+			return err
+		}
+		if len(bindings) == 0 {
+			return nil
+		}
+
+		entries := make([]model.SysDeptRole, 0, len(bindings))
+		for _, binding := range bindings {
+			entries = append(entries, model.SysDeptRole{
+				DeptID:          binding.DeptID,
+				RoleID:          roleID,
+				IncludeChildren: binding.IncludeChildren,
+			})
+		}
+		return tx.Create(&entries).Error
+	})
+}
+
+// ReplacePostBindings 覆盖角色绑定的岗位
+func (r *Repository) ReplacePostBindings(ctx context.Context, roleID int64, postIDs []int64) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if roleID <= 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.SysPostRole{}).Error; err != nil {
+			return err
+		}
+		if len(postIDs) == 0 {
+			return nil
+		}
+
+		entries := make([]model.SysPostRole, 0, len(postIDs))
+		for _, id := range postIDs {
+			entries = append(entries, model.SysPostRole{PostID: id, RoleID: roleID})
+		}
+		return tx.Create(&entries).Error
+	})
+}
+
+// CountDepartments 统计存在的部门数量，用于校验绑定的部门
+func (r *Repository) CountDepartments(ctx context.Context, ids []int64) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, ErrRepositoryUnavailable
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.SysDept{}).Where("id IN ?", ids).Count(&count).Error
+	return count, err
+}
+
+// CountPosts 统计存在的岗位数量，用于校验绑定的岗位
+func (r *Repository) CountPosts(ctx context.Context, ids []int64) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, ErrRepositoryUnavailable
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.SysPost{}).Where("id IN ?", ids).Count(&count).Error
+	return count, err
+}
+
+// GetMenuIDsByRoles 返回多个角色授权菜单的并集
+func (r *Repository) GetMenuIDsByRoles(ctx context.Context, roleIDs []int64) ([]int64, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	if len(roleIDs) == 0 {
+		return []int64{}, nil
+	}
+
+	var ids []int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.SysRoleMenu{}).
+		Distinct("menu_id").
+		Where("role_id IN ?", roleIDs).
+		Order("menu_id ASC").
+		Pluck("menu_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if ids == nil {
+		ids = []int64{}
+	}
+	return ids, nil
+}
+
+// CountChildren 统计以指定角色为上级的角色数量
+func (r *Repository) CountChildren(ctx context.Context, roleID int64) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, ErrRepositoryUnavailable
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.SysRole{}).
+		Where("parent_id = ?", roleID).
+		Count(&count).Error
+	return count, err
+}
@@ -10,6 +10,8 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+	"github.com/starter-kit-fe/admin/internal/system/history"
 	"github.com/starter-kit-fe/admin/internal/system/menu"
 )
 
@@ -23,8 +25,6 @@ var (
 	ErrInvalidDataScope     = errors.New("invalid data scope")
 	ErrInvalidRoleSort      = errors.New("invalid role sort")
 	ErrInvalidMenuSelection = errors.New("invalid menu selection")
-	validStatuses           = map[string]struct{}{"0": {}, "1": {}}
-	validDataScopes         = map[string]struct{}{"1": {}, "2": {}, "3": {}, "4": {}, "5": {}}
 	defaultDataScope        = "1"
 	defaultRoleSort         = 0
 	maxRemarkLength         = 256
@@ -33,16 +33,36 @@ var (
 	defaultOperator         = "system"
 )
 
+var (
+	statusEnum = dict.Enum{
+		DictType: dict.TypeNormalDisable,
+		Fallback: []string{"0", "1"},
+		Default:  "0",
+		Invalid:  ErrInvalidStatus,
+	}
+	// 数据范围的取值与数据权限过滤逻辑一一对应，字典只能停用已有范围
+	dataScopeEnum = dict.Enum{
+		DictType: dict.TypeDataScope,
+		Fallback: []string{"1", "2", "3", "4", "5"},
+		Default:  defaultDataScope,
+		Invalid:  ErrInvalidDataScope,
+		Strict:   true,
+	}
+)
+
 type Service struct {
 	repo     *Repository
 	menuRepo *menu.Repository
+	history  *history.Service
+	dicts    *dict.Service
 }
 
-func NewService(repo *Repository, menuRepo *menu.Repository) *Service {
+// NewService 创建角色服务；dicts 可选，用于按字典校验状态与数据范围
+func NewService(repo *Repository, menuRepo *menu.Repository, history *history.Service, dicts *dict.Service) *Service {
 	if repo == nil {
 		return nil
 	}
-	return &Service{repo: repo, menuRepo: menuRepo}
+	return &Service{repo: repo, menuRepo: menuRepo, history: history, dicts: dicts}
 }
 
 type QueryOptions struct {
@@ -64,6 +84,7 @@ type Role struct {
 	RoleName          string     `json:"roleName"`
 	RoleKey           string     `json:"roleKey"`
 	RoleSort          int        `json:"roleSort"`
+	ParentID          int64      `json:"parentId"`
 	DataScope         string     `json:"dataScope"`
 	MenuCheckStrictly bool       `json:"menuCheckStrictly"`
 	DeptCheckStrictly bool       `json:"deptCheckStrictly"`
@@ -74,12 +95,18 @@ type Role struct {
 	UpdateBy          string     `json:"updateBy"`
 	UpdatedAt         *time.Time `json:"updatedAt,omitempty"`
 	MenuIDs           []int64    `json:"menuIds"`
+	// InheritedMenuIDs 从上级角色继承的菜单，仅在角色详情中返回
+	InheritedMenuIDs []int64 `json:"inheritedMenuIds,omitempty"`
+	// DeptBindings 与 PostIDs 为绑定该角色的部门与岗位，其成员自动继承该角色
+	DeptBindings []DeptBinding `json:"deptBindings"`
+	PostIDs      []int64       `json:"postIds"`
 }
 
 type CreateRoleInput struct {
 	RoleName          string
 	RoleKey           string
 	RoleSort          *int
+	ParentID          int64
 	DataScope         string
 	MenuCheckStrictly bool
 	DeptCheckStrictly bool
@@ -87,6 +114,8 @@ type CreateRoleInput struct {
 	Remark            *string
 	Operator          string
 	MenuIDs           []int64
+	DeptBindings      []DeptBinding
+	PostIDs           []int64
 }
 
 type UpdateRoleInput struct {
@@ -94,6 +123,7 @@ type UpdateRoleInput struct {
 	RoleName          *string
 	RoleKey           *string
 	RoleSort          *int
+	ParentID          *int64
 	DataScope         *string
 	MenuCheckStrictly *bool
 	DeptCheckStrictly *bool
@@ -101,6 +131,8 @@ type UpdateRoleInput struct {
 	Remark            *string
 	Operator          string
 	MenuIDs           *[]int64
+	DeptBindings      *[]DeptBinding
+	PostIDs           *[]int64
 }
 
 type DeleteRoleInput struct {
@@ -144,6 +176,7 @@ func (s *Service) ListRoles(ctx context.Context, opts QueryOptions) (*ListResult
 			RoleName:          record.RoleName,
 			RoleKey:           record.RoleKey,
 			RoleSort:          record.RoleSort,
+			ParentID:          record.ParentID,
 			DataScope:         record.DataScope,
 			MenuCheckStrictly: record.MenuCheckStrictly,
 			DeptCheckStrictly: record.DeptCheckStrictly,
@@ -154,6 +187,8 @@ func (s *Service) ListRoles(ctx context.Context, opts QueryOptions) (*ListResult
 			UpdateBy:          record.UpdateBy,
 			UpdatedAt:         &record.UpdatedAt,
 			MenuIDs:           []int64{},
+			DeptBindings:      []DeptBinding{},
+			PostIDs:           []int64{},
 		}
 		roles = append(roles, role)
 	}
@@ -184,11 +219,22 @@ func (s *Service) GetRole(ctx context.Context, id int64) (*Role, error) {
 		return nil, err
 	}
 
+	inheritedMenuIDs, err := s.inheritedMenuIDs(ctx, record.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	deptBindings, postIDs, err := s.loadBindings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Role{
 		RoleID:            int64(record.ID),
 		RoleName:          record.RoleName,
 		RoleKey:           record.RoleKey,
 		RoleSort:          record.RoleSort,
+		ParentID:          record.ParentID,
 		DataScope:         record.DataScope,
 		MenuCheckStrictly: record.MenuCheckStrictly,
 		DeptCheckStrictly: record.DeptCheckStrictly,
@@ -199,6 +245,9 @@ func (s *Service) GetRole(ctx context.Context, id int64) (*Role, error) {
 		UpdateBy:          record.UpdateBy,
 		UpdatedAt:         &record.UpdatedAt,
 		MenuIDs:           menuIDs,
+		InheritedMenuIDs:  inheritedMenuIDs,
+		DeptBindings:      deptBindings,
+		PostIDs:           postIDs,
 	}, nil
 }
 
@@ -223,14 +272,14 @@ func (s *Service) CreateRole(ctx context.Context, input CreateRoleInput) (*Role,
 		}
 	}
 
-	status := normalizeStatus(input.Status)
-	if _, ok := validStatuses[status]; !ok {
-		return nil, ErrInvalidStatus
+	status, err := s.dicts.Normalize(ctx, statusEnum, input.Status)
+	if err != nil {
+		return nil, err
 	}
 
-	dataScope := normalizeDataScope(input.DataScope)
-	if _, ok := validDataScopes[dataScope]; !ok {
-		return nil, ErrInvalidDataScope
+	dataScope, err := s.dicts.Normalize(ctx, dataScopeEnum, input.DataScope)
+	if err != nil {
+		return nil, err
 	}
 
 	if exists, err := s.repo.ExistsByName(ctx, roleName, 0); err != nil {
@@ -245,10 +294,22 @@ func (s *Service) CreateRole(ctx context.Context, input CreateRoleInput) (*Role,
 		return nil, ErrDuplicateRoleKey
 	}
 
+	if err := s.validateParent(ctx, 0, input.ParentID); err != nil {
+		return nil, err
+	}
+
 	menuIDs, err := s.validateMenuIDs(ctx, input.MenuIDs)
 	if err != nil {
 		return nil, err
 	}
+	deptBindings, err := s.validateDeptBindings(ctx, input.DeptBindings)
+	if err != nil {
+		return nil, err
+	}
+	postIDs, err := s.validatePostBindings(ctx, input.PostIDs)
+	if err != nil {
+		return nil, err
+	}
 
 	remark := normalizeRemark(input.Remark)
 	operator := sanitizeOperator(input.Operator)
@@ -257,6 +318,7 @@ func (s *Service) CreateRole(ctx context.Context, input CreateRoleInput) (*Role,
 		RoleName:          roleName,
 		RoleKey:           roleKey,
 		RoleSort:          roleSort,
+		ParentID:          input.ParentID,
 		DataScope:         dataScope,
 		MenuCheckStrictly: input.MenuCheckStrictly,
 		DeptCheckStrictly: input.DeptCheckStrictly,
@@ -277,8 +339,25 @@ func (s *Service) CreateRole(ctx context.Context, input CreateRoleInput) (*Role,
 	if err := s.repo.ReplaceRoleMenus(ctx, int64(record.ID), menuIDs); err != nil {
 		return nil, err
 	}
+	if err := s.repo.ReplaceDeptBindings(ctx, int64(record.ID), deptBindings); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplacePostBindings(ctx, int64(record.ID), postIDs); err != nil {
+		return nil, err
+	}
 
-	return s.GetRole(ctx, int64(record.ID))
+	role, err := s.GetRole(ctx, int64(record.ID))
+	if err != nil {
+		return nil, err
+	}
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleRole,
+		EntityID: role.RoleID,
+		Action:   history.ActionCreate,
+		Operator: operator,
+		After:    role,
+	})
+	return role, nil
 }
 
 func (s *Service) UpdateRole(ctx context.Context, input UpdateRoleInput) (*Role, error) {
@@ -289,6 +368,30 @@ func (s *Service) UpdateRole(ctx context.Context, input UpdateRoleInput) (*Role,
 		return nil, gorm.ErrRecordNotFound
 	}
 
+	// 修改前的快照，菜单授权的增减由变更历史比对得出
+	var before *Role
+	if s.history != nil {
+		before, _ = s.GetRole(ctx, input.ID)
+	}
+
+	var deptBindings []model.SysDeptRole
+	if input.DeptBindings != nil {
+		validated, err := s.validateDeptBindings(ctx, *input.DeptBindings)
+		if err != nil {
+			return nil, err
+		}
+		deptBindings = validated
+	}
+
+	var postIDs []int64
+	if input.PostIDs != nil {
+		validated, err := s.validatePostBindings(ctx, *input.PostIDs)
+		if err != nil {
+			return nil, err
+		}
+		postIDs = validated
+	}
+
 	updates := make(map[string]interface{})
 
 	if input.RoleName != nil {
@@ -324,18 +427,29 @@ func (s *Service) UpdateRole(ctx context.Context, input UpdateRoleInput) (*Role,
 		updates["role_sort"] = *input.RoleSort
 	}
 
+	if input.ParentID != nil {
+		if *input.ParentID < 0 {
+			return nil, ErrInvalidParentRole
+		}
+		if *input.ParentID == input.ID {
+			return nil, ErrRoleCycle
+		}
+		// 上级是否存在、是否形成循环在更新事务内锁定相关角色后校验
+		updates["parent_id"] = *input.ParentID
+	}
+
 	if input.Status != nil {
-		status := normalizeStatus(*input.Status)
-		if _, ok := validStatuses[status]; !ok {
-			return nil, ErrInvalidStatus
+		status, err := s.dicts.Normalize(ctx, statusEnum, *input.Status)
+		if err != nil {
+			return nil, err
 		}
 		updates["status"] = status
 	}
 
 	if input.DataScope != nil {
-		scope := normalizeDataScope(*input.DataScope)
-		if _, ok := validDataScopes[scope]; !ok {
-			return nil, ErrInvalidDataScope
+		scope, err := s.dicts.Normalize(ctx, dataScopeEnum, *input.DataScope)
+		if err != nil {
+			return nil, err
 		}
 		updates["data_scope"] = scope
 	}
@@ -356,7 +470,9 @@ func (s *Service) UpdateRole(ctx context.Context, input UpdateRoleInput) (*Role,
 		}
 	}
 
-	if len(updates) > 0 {
+	// 授权关系变化同样视为角色被修改，需要刷新 updated_at 使旧的 ETag 失效
+	bindingsChanged := input.MenuIDs != nil || input.DeptBindings != nil || input.PostIDs != nil
+	if len(updates) > 0 || bindingsChanged {
 		operator := sanitizeOperator(input.Operator)
 		now := time.Now()
 		updates["update_by"] = operator
@@ -386,7 +502,31 @@ func (s *Service) UpdateRole(ctx context.Context, input UpdateRoleInput) (*Role,
 		}
 	}
 
-	return s.GetRole(ctx, input.ID)
+	if input.DeptBindings != nil {
+		if err := s.repo.ReplaceDeptBindings(ctx, input.ID, deptBindings); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.PostIDs != nil {
+		if err := s.repo.ReplacePostBindings(ctx, input.ID, postIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	role, err := s.GetRole(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleRole,
+		EntityID: input.ID,
+		Action:   history.ActionUpdate,
+		Operator: sanitizeOperator(input.Operator),
+		Before:   before,
+		After:    role,
+	})
+	return role, nil
 }
 
 func (s *Service) DeleteRole(ctx context.Context, input DeleteRoleInput) error {
@@ -397,11 +537,31 @@ func (s *Service) DeleteRole(ctx context.Context, input DeleteRoleInput) error {
 		return gorm.ErrRecordNotFound
 	}
 
+	children, err := s.repo.CountChildren(ctx, input.ID)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return ErrRoleHasChildren
+	}
+
+	var before *Role
+	if s.history != nil {
+		before, _ = s.GetRole(ctx, input.ID)
+	}
+
 	operator := sanitizeOperator(input.Operator)
 	if err := s.repo.SoftDeleteRole(ctx, input.ID, operator, time.Now()); err != nil {
 		return err
 	}
-	return s.repo.ReplaceRoleMenus(ctx, input.ID, nil)
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleRole,
+		EntityID: input.ID,
+		Action:   history.ActionDelete,
+		Operator: operator,
+		Before:   before,
+	})
+	return nil
 }
 
 func sanitizeRoleName(name string) string {
@@ -426,22 +586,6 @@ func sanitizeRoleKey(key string) string {
 	return value
 }
 
-func normalizeStatus(status string) string {
-	value := strings.TrimSpace(status)
-	if value == "" {
-		return "0"
-	}
-	return value
-}
-
-func normalizeDataScope(scope string) string {
-	value := strings.TrimSpace(scope)
-	if value == "" {
-		return defaultDataScope
-	}
-	return value
-}
-
 func sanitizeOperator(operator string) string {
 	value := strings.TrimSpace(operator)
 	if value == "" {
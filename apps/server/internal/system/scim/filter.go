@@ -0,0 +1,131 @@
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// 支持的过滤运算符
+const (
+	opEqual      = "eq"
+	opNotEqual   = "ne"
+	opContains   = "co"
+	opStartsWith = "sw"
+	opEndsWith   = "ew"
+	opPresent    = "pr"
+)
+
+var filterOperators = map[string]struct{}{
+	opEqual: {}, opNotEqual: {}, opContains: {}, opStartsWith: {}, opEndsWith: {}, opPresent: {},
+}
+
+// Condition 过滤条件，Attr 为小写的属性路径
+type Condition struct {
+	Attr  string
+	Op    string
+	Value string
+}
+
+// ParseFilter 解析 SCIM 过滤表达式。
+// 仅支持以 and 连接的简单比较（如 userName eq "alice" and active eq true），
+// 不支持 or、not 与括号分组，身份源的增量同步只依赖这一子集。
+func ParseFilter(filter string) ([]Condition, error) {
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	var conditions []Condition
+	for i := 0; i < len(tokens); {
+		if len(conditions) > 0 {
+			if !strings.EqualFold(tokens[i].text, "and") || tokens[i].quoted {
+				return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "only 'and' is supported to combine filter expressions")
+			}
+			i++
+		}
+		if i+1 >= len(tokens) || tokens[i].quoted {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "incomplete filter expression")
+		}
+
+		attr := strings.ToLower(tokens[i].text)
+		op := strings.ToLower(tokens[i+1].text)
+		if strings.ContainsAny(attr, "()[]") {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "grouping and value filters are not supported")
+		}
+		if _, ok := filterOperators[op]; !ok || tokens[i+1].quoted {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "unsupported filter operator %q", tokens[i+1].text)
+		}
+
+		if op == opPresent {
+			conditions = append(conditions, Condition{Attr: attr, Op: op})
+			i += 2
+			continue
+		}
+		if i+2 >= len(tokens) {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "missing value for %s", tokens[i].text)
+		}
+		value := tokens[i+2]
+		if !value.quoted {
+			// 未加引号的值只允许布尔、数字与 null
+			lower := strings.ToLower(value.text)
+			if _, err := strconv.ParseFloat(value.text, 64); err != nil && lower != "true" && lower != "false" && lower != "null" {
+				return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "invalid filter value %q", value.text)
+			}
+			value.text = lower
+		}
+		conditions = append(conditions, Condition{Attr: attr, Op: op, Value: value.text})
+		i += 3
+	}
+	return conditions, nil
+}
+
+type filterToken struct {
+	text   string
+	quoted bool
+}
+
+func tokenizeFilter(filter string) ([]filterToken, error) {
+	var (
+		tokens []filterToken
+		runes  = []rune(strings.TrimSpace(filter))
+	)
+	for i := 0; i < len(runes); {
+		switch {
+		case runes[i] == ' ' || runes[i] == '\t':
+			i++
+		case runes[i] == '"':
+			var b strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					b.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "unterminated string in filter")
+			}
+			tokens = append(tokens, filterToken{text: b.String(), quoted: true})
+		default:
+			start := i
+			for i < len(runes) && runes[i] != ' ' && runes[i] != '\t' && runes[i] != '"' {
+				i++
+			}
+			tokens = append(tokens, filterToken{text: string(runes[start:i])})
+		}
+	}
+	return tokens, nil
+}
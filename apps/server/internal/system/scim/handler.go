@@ -0,0 +1,485 @@
+package scim
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+// MaxRequestSize SCIM 请求体大小上限
+const MaxRequestSize = 1 << 20
+
+type Handler struct {
+	service *Service
+	tokens  [][]byte
+}
+
+// NewHandler 创建 SCIM 处理器，未配置令牌时返回 nil，接口不会被注册
+func NewHandler(service *Service, tokens []string) *Handler {
+	if service == nil {
+		return nil
+	}
+	handler := &Handler{service: service}
+	for _, token := range tokens {
+		if token = strings.TrimSpace(token); token != "" {
+			handler.tokens = append(handler.tokens, []byte(token))
+		}
+	}
+	if len(handler.tokens) == 0 {
+		return nil
+	}
+	return handler
+}
+
+// Authenticate 校验身份源的 Bearer 令牌，与用户登录令牌相互独立
+func (h *Handler) Authenticate(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		writeError(ctx, newError(http.StatusServiceUnavailable, "", "scim service unavailable"))
+		ctx.Abort()
+		return
+	}
+
+	header := ctx.GetHeader("Authorization")
+	token := ""
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		token = strings.TrimSpace(header[7:])
+	}
+	if token != "" {
+		for _, expected := range h.tokens {
+			if subtle.ConstantTimeCompare([]byte(token), expected) == 1 {
+				// 身份源同步的账号归属默认租户
+				ctx.Request = ctx.Request.WithContext(tenant.WithID(ctx.Request.Context(), tenant.DefaultID))
+				ctx.Next()
+				return
+			}
+		}
+	}
+	ctx.Header("WWW-Authenticate", `Bearer realm="scim"`)
+	writeError(ctx, newError(http.StatusUnauthorized, "", "invalid or missing bearer token"))
+	ctx.Abort()
+}
+
+type listQuery struct {
+	Filter             string `form:"filter"`
+	StartIndex         int    `form:"startIndex"`
+	Count              *int   `form:"count"`
+	ExcludedAttributes string `form:"excludedAttributes"`
+}
+
+func (q listQuery) options() ListOptions {
+	return ListOptions{
+		Filter:         q.Filter,
+		StartIndex:     q.StartIndex,
+		Count:          q.Count,
+		IncludeMembers: !excludesMembers(q.ExcludedAttributes),
+	}
+}
+
+// ServiceProviderConfig godoc
+// @Summary SCIM 服务能力
+// @Description 返回 SCIM 服务支持的能力，供身份源探测
+// @Tags System/SCIM
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (h *Handler) ServiceProviderConfig(ctx *gin.Context) {
+	writeJSON(ctx, http.StatusOK, gin.H{
+		"schemas":        []string{SchemaServiceProviderConfig},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": maxPageSize},
+		"changePassword": gin.H{"supported": true},
+		"sort":           gin.H{"supported": false},
+		"etag":           gin.H{"supported": false},
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "Static bearer token configured via SCIM_TOKENS",
+			"primary":     true,
+		}},
+	})
+}
+
+// ResourceTypes godoc
+// @Summary SCIM 资源类型
+// @Description 返回可同步的资源类型：用户与组
+// @Tags System/SCIM
+// @Produce json
+// @Success 200 {object} ListResponse
+// @Router /scim/v2/ResourceTypes [get]
+func (h *Handler) ResourceTypes(ctx *gin.Context) {
+	resources := []gin.H{
+		{
+			"schemas":          []string{SchemaResourceType},
+			"id":               "User",
+			"name":             "User",
+			"endpoint":         "/Users",
+			"schema":           SchemaUser,
+			"schemaExtensions": []gin.H{{"schema": SchemaEnterpriseUser, "required": false}},
+		},
+		{
+			"schemas":  []string{SchemaResourceType},
+			"id":       "Group",
+			"name":     "Group",
+			"endpoint": "/Groups",
+			"schema":   SchemaGroup,
+		},
+	}
+	writeJSON(ctx, http.StatusOK, ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: int64(len(resources)),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// ListUsers godoc
+// @Summary SCIM 用户列表
+// @Description 按 filter 分页查询用户，支持 eq、ne、co、sw、ew、pr 与 and 组合
+// @Tags System/SCIM
+// @Produce json
+// @Param filter query string false "过滤表达式，如 userName eq \"alice\""
+// @Param startIndex query int false "起始序号，从 1 开始"
+// @Param count query int false "每页数量"
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /scim/v2/Users [get]
+func (h *Handler) ListUsers(ctx *gin.Context) {
+	var query listQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		writeError(ctx, newError(http.StatusBadRequest, scimTypeInvalidValue, "invalid query parameters"))
+		return
+	}
+	result, err := h.service.ListUsers(ctx.Request.Context(), query.options())
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+	writeJSON(ctx, http.StatusOK, result)
+}
+
+// GetUser godoc
+// @Summary SCIM 用户详情
+// @Tags System/SCIM
+// @Produce json
+// @Param id path string true "用户ID"
+// @Success 200 {object} User
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /scim/v2/Users/{id} [get]
+func (h *Handler) GetUser(ctx *gin.Context) {
+	result, err := h.service.GetUser(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+	writeJSON(ctx, http.StatusOK, result)
+}
+
+// CreateUser godoc
+// @Summary SCIM 新建用户
+// @Description 身份源入职同步；未提供密码时生成随机密码，企业扩展中的 department 按名称匹配部门
+// @Tags System/SCIM
+// @Accept json
+// @Produce json
+// @Param body body User true "用户"
+// @Success 201 {object} User
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /scim/v2/Users [post]
+func (h *Handler) CreateUser(ctx *gin.Context) {
+	var payload User
+	if !bindBody(ctx, &payload) {
+		return
+	}
+	result, err := h.service.CreateUser(ctx.Request.Context(), &payload)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+	ctx.Header("Location", result.Meta.Location)
+	writeJSON(ctx, http.StatusCreated, result)
+}
+
+// ReplaceUser godoc
+// @Summary SCIM 替换用户
+// @Tags System/SCIM
+// @Accept json
+// @Produce json
+// @Param id path string true "用户ID"
+// @Param body body User true "用户"
+// @Success 200 {object} User
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /scim/v2/Users/{id} [put]
+func (h *Handler) ReplaceUser(ctx *gin.Context) {
+	var payload User
+	if !bindBody(ctx, &payload) {
+		return
+	}
+	result, err := h.service.ReplaceUser(ctx.Request.Context(), ctx.Param("id"), &payload)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+	writeJSON(ctx, http.StatusOK, result)
+}
+
+// PatchUser godoc
+// @Summary SCIM 修改用户
+// @Description 支持 add、replace、remove 操作；active 置为 false 时停用账号并注销其会话
+// @Tags System/SCIM
+// @Accept json
+// @Produce json
+// @Param id path string true "用户ID"
+// @Param body body PatchRequest true "PATCH 操作"
+// @Success 200 {object} User
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /scim/v2/Users/{id} [patch]
+func (h *Handler) PatchUser(ctx *gin.Context) {
+	var payload PatchRequest
+	if !bindBody(ctx, &payload) {
+		return
+	}
+	result, err := h.service.PatchUser(ctx.Request.Context(), ctx.Param("id"), &payload)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+	writeJSON(ctx, http.StatusOK, result)
+}
+
+// DeleteUser godoc
+// @Summary SCIM 删除用户
+// @Description 离职同步：用户进入回收站并注销其全部会话
+// @Tags System/SCIM
+// @Param id path string true "用户ID"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /scim/v2/Users/{id} [delete]
+func (h *Handler) DeleteUser(ctx *gin.Context) {
+	if err := h.service.DeleteUser(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		writeError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListGroups godoc
+// @Summary SCIM 组列表
+// @Description 角色（role-<id>）与部门（dept-<id>）均以组的形式返回
+// @Tags System/SCIM
+// @Produce json
+// @Param filter query string false "过滤表达式，如 displayName eq \"管理员\""
+// @Param startIndex query int false "起始序号，从 1 开始"
+// @Param count query int false "每页数量"
+// @Param excludedAttributes query string false "传入 members 时不返回成员"
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /scim/v2/Groups [get]
+func (h *Handler) ListGroups(ctx *gin.Context) {
+	var query listQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		writeError(ctx, newError(http.StatusBadRequest, scimTypeInvalidValue, "invalid query parameters"))
+		return
+	}
+	result, err := h.service.ListGroups(ctx.Request.Context(), query.options())
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+	writeJSON(ctx, http.StatusOK, result)
+}
+
+// GetGroup godoc
+// @Summary SCIM 组详情
+// @Tags System/SCIM
+// @Produce json
+// @Param id path string true "组ID"
+// @Param excludedAttributes query string false "传入 members 时不返回成员"
+// @Success 200 {object} Group
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /scim/v2/Groups/{id} [get]
+func (h *Handler) GetGroup(ctx *gin.Context) {
+	includeMembers := !excludesMembers(ctx.Query("excludedAttributes"))
+	result, err := h.service.GetGroup(ctx.Request.Context(), ctx.Param("id"), includeMembers)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+	writeJSON(ctx, http.StatusOK, result)
+}
+
+// CreateGroup godoc
+// @Summary SCIM 新建组
+// @Description 新建角色，externalId 作为角色权限字符；部门需在后台维护
+// @Tags System/SCIM
+// @Accept json
+// @Produce json
+// @Param body body Group true "组"
+// @Success 201 {object} Group
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /scim/v2/Groups [post]
+func (h *Handler) CreateGroup(ctx *gin.Context) {
+	var payload Group
+	if !bindBody(ctx, &payload) {
+		return
+	}
+	result, err := h.service.CreateGroup(ctx.Request.Context(), &payload)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+	ctx.Header("Location", result.Meta.Location)
+	writeJSON(ctx, http.StatusCreated, result)
+}
+
+// ReplaceGroup godoc
+// @Summary SCIM 替换组
+// @Tags System/SCIM
+// @Accept json
+// @Produce json
+// @Param id path string true "组ID"
+// @Param body body Group true "组"
+// @Success 200 {object} Group
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /scim/v2/Groups/{id} [put]
+func (h *Handler) ReplaceGroup(ctx *gin.Context) {
+	var payload Group
+	if !bindBody(ctx, &payload) {
+		return
+	}
+	result, err := h.service.ReplaceGroup(ctx.Request.Context(), ctx.Param("id"), &payload)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+	writeJSON(ctx, http.StatusOK, result)
+}
+
+// PatchGroup godoc
+// @Summary SCIM 修改组
+// @Description 增量调整组成员，角色成员写入用户角色关联，部门成员写入用户所属部门
+// @Tags System/SCIM
+// @Accept json
+// @Produce json
+// @Param id path string true "组ID"
+// @Param body body PatchRequest true "PATCH 操作"
+// @Success 200 {object} Group
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /scim/v2/Groups/{id} [patch]
+func (h *Handler) PatchGroup(ctx *gin.Context) {
+	var payload PatchRequest
+	if !bindBody(ctx, &payload) {
+		return
+	}
+	result, err := h.service.PatchGroup(ctx.Request.Context(), ctx.Param("id"), &payload)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+	writeJSON(ctx, http.StatusOK, result)
+}
+
+// DeleteGroup godoc
+// @Summary SCIM 删除组
+// @Description 删除角色；部门不能通过 SCIM 删除
+// @Tags System/SCIM
+// @Param id path string true "组ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /scim/v2/Groups/{id} [delete]
+func (h *Handler) DeleteGroup(ctx *gin.Context) {
+	if err := h.service.DeleteGroup(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		writeError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func excludesMembers(value string) bool {
+	for _, attr := range strings.Split(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(attr), "members") {
+			return true
+		}
+	}
+	return false
+}
+
+// bindBody 解析 JSON 请求体，身份源通常使用 application/scim+json
+func bindBody(ctx *gin.Context, target any) bool {
+	data, err := io.ReadAll(io.LimitReader(ctx.Request.Body, MaxRequestSize+1))
+	if err != nil {
+		writeError(ctx, newError(http.StatusBadRequest, scimTypeInvalidSyntax, "failed to read request body"))
+		return false
+	}
+	if len(data) > MaxRequestSize {
+		writeError(ctx, newError(http.StatusBadRequest, scimTypeInvalidSyntax, "request body exceeds %d bytes", MaxRequestSize))
+		return false
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		writeError(ctx, newError(http.StatusBadRequest, scimTypeInvalidSyntax, "invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(ctx *gin.Context, status int, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		writeError(ctx, fmt.Errorf("encode scim response: %w", err))
+		return
+	}
+	ctx.Data(status, ContentType, data)
+}
+
+// writeError 按 SCIM 错误格式输出，非业务错误统一返回 500
+func writeError(ctx *gin.Context, err error) {
+	var scimErr *Error
+	switch {
+	case errors.As(err, &scimErr):
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		scimErr = newError(http.StatusNotFound, "", "resource not found")
+	case errors.Is(err, ErrServiceUnavailable), errors.Is(err, ErrRepositoryUnavailable):
+		scimErr = newError(http.StatusServiceUnavailable, "", "scim service unavailable")
+	default:
+		_ = ctx.Error(err)
+		scimErr = newError(http.StatusInternalServerError, "", "internal server error")
+	}
+
+	data, _ := json.Marshal(ErrorResponse{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(scimErr.Status),
+		ScimType: scimErr.ScimType,
+		Detail:   scimErr.Detail,
+	})
+	ctx.Data(scimErr.Status, ContentType, data)
+}
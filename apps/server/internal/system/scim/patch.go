@@ -0,0 +1,437 @@
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PATCH 操作类型
+const (
+	patchAdd     = "add"
+	patchReplace = "replace"
+	patchRemove  = "remove"
+)
+
+// enterpriseDepartmentPath 企业扩展中部门属性的完整路径
+var enterpriseDepartmentPath = strings.ToLower(SchemaEnterpriseUser + ":department")
+
+func validatePatch(req *PatchRequest) error {
+	if req == nil || len(req.Operations) == 0 {
+		return newError(http.StatusBadRequest, scimTypeInvalidSyntax, "at least one operation is required")
+	}
+	for _, operation := range req.Operations {
+		switch strings.ToLower(operation.Op) {
+		case patchAdd, patchReplace, patchRemove:
+		default:
+			return newError(http.StatusBadRequest, scimTypeInvalidSyntax, "unsupported patch operation %q", operation.Op)
+		}
+	}
+	return nil
+}
+
+// patchUser 将单个操作应用到用户资源上，未识别的属性直接忽略
+func patchUser(state *User, operation PatchOperation) error {
+	op := strings.ToLower(operation.Op)
+	path := strings.TrimSpace(operation.Path)
+	if path == "" {
+		if op == patchRemove {
+			return newError(http.StatusBadRequest, scimTypeNoTarget, "remove requires a path")
+		}
+		values, ok := operation.Value.(map[string]any)
+		if !ok {
+			return newError(http.StatusBadRequest, scimTypeInvalidValue, "value must be an object when path is omitted")
+		}
+		for key, value := range values {
+			if err := patchUserAttribute(state, op, key, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return patchUserAttribute(state, op, path, operation.Value)
+}
+
+func patchUserAttribute(state *User, op, path string, value any) error {
+	lower := strings.ToLower(path)
+	remove := op == patchRemove
+
+	switch {
+	case lower == "username":
+		if remove {
+			return newError(http.StatusBadRequest, scimTypeMutability, "userName cannot be removed")
+		}
+		text, err := stringValue(path, value)
+		if err != nil {
+			return err
+		}
+		state.UserName = text
+	case lower == "displayname":
+		text, err := optionalString(path, value, remove)
+		if err != nil {
+			return err
+		}
+		state.DisplayName = text
+		if state.Name != nil {
+			state.Name.Formatted = text
+		}
+	case lower == "externalid":
+		text, err := optionalString(path, value, remove)
+		if err != nil {
+			return err
+		}
+		state.ExternalID = text
+	case lower == "active":
+		if remove {
+			return newError(http.StatusBadRequest, scimTypeMutability, "active cannot be removed")
+		}
+		active, err := boolValue(path, value)
+		if err != nil {
+			return err
+		}
+		state.Active = &active
+	case lower == "password":
+		text, err := optionalString(path, value, remove)
+		if err != nil {
+			return err
+		}
+		state.Password = text
+	case lower == "name":
+		if remove {
+			state.Name, state.DisplayName = nil, ""
+			return nil
+		}
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return newError(http.StatusBadRequest, scimTypeInvalidValue, "name must be an object")
+		}
+		for key, item := range fields {
+			if err := patchUserAttribute(state, op, "name."+key, item); err != nil {
+				return err
+			}
+		}
+	case strings.HasPrefix(lower, "name."):
+		text, err := optionalString(path, value, remove)
+		if err != nil {
+			return err
+		}
+		if state.Name == nil {
+			state.Name = &Name{}
+		}
+		switch strings.TrimPrefix(lower, "name.") {
+		case "formatted":
+			state.Name.Formatted = text
+			state.DisplayName = text
+		case "givenname":
+			state.Name.GivenName = text
+		case "familyname":
+			state.Name.FamilyName = text
+		}
+	case strings.HasPrefix(lower, "emails"):
+		values, err := patchMultiValue(state.Emails, op, path[len("emails"):], value)
+		if err != nil {
+			return err
+		}
+		state.Emails = values
+	case strings.HasPrefix(lower, "phonenumbers"):
+		values, err := patchMultiValue(state.Phones, op, path[len("phonenumbers"):], value)
+		if err != nil {
+			return err
+		}
+		state.Phones = values
+	case lower == enterpriseDepartmentPath:
+		text, err := optionalString(path, value, remove)
+		if err != nil {
+			return err
+		}
+		state.Enterprise = &EnterpriseUser{Department: text}
+	case lower == strings.ToLower(SchemaEnterpriseUser):
+		if remove {
+			state.Enterprise = &EnterpriseUser{}
+			return nil
+		}
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return newError(http.StatusBadRequest, scimTypeInvalidValue, "%s must be an object", SchemaEnterpriseUser)
+		}
+		for key, item := range fields {
+			if strings.EqualFold(key, "department") {
+				if err := patchUserAttribute(state, op, enterpriseDepartmentPath, item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// patchMultiValue 处理 emails、phoneNumbers 的 PATCH，suffix 为属性名之后的部分，
+// 如 ""、".value" 或 `[type eq "work"].value`
+func patchMultiValue(current []MultiValue, op, suffix string, value any) ([]MultiValue, error) {
+	typeFilter := ""
+	if strings.HasPrefix(suffix, "[") {
+		end := strings.Index(suffix, "]")
+		if end < 0 {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidPath, "unterminated value filter")
+		}
+		conditions, err := ParseFilter(suffix[1:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(conditions) != 1 || conditions[0].Attr != "type" || conditions[0].Op != opEqual {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidPath, "only type eq filters are supported")
+		}
+		typeFilter = conditions[0].Value
+		suffix = suffix[end+1:]
+	}
+
+	switch strings.ToLower(suffix) {
+	case "":
+		if typeFilter != "" {
+			if op == patchRemove {
+				return removeByType(current, typeFilter), nil
+			}
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidPath, "a sub-attribute is required with a value filter")
+		}
+		if op == patchRemove {
+			return nil, nil
+		}
+		items, err := multiValues(value)
+		if err != nil {
+			return nil, err
+		}
+		if op == patchAdd {
+			return append(current, items...), nil
+		}
+		return items, nil
+	case ".value":
+		if op == patchRemove {
+			if typeFilter == "" {
+				return nil, nil
+			}
+			return removeByType(current, typeFilter), nil
+		}
+		text, err := stringValue("value", value)
+		if err != nil {
+			return nil, err
+		}
+		for i := range current {
+			if typeFilter == "" || strings.EqualFold(current[i].Type, typeFilter) {
+				current[i].Value = text
+				return current, nil
+			}
+		}
+		entryType := typeFilter
+		if entryType == "" {
+			entryType = "work"
+		}
+		return append(current, MultiValue{Value: text, Type: entryType, Primary: len(current) == 0}), nil
+	default:
+		// primary、type 等子属性不影响存储，忽略
+		return current, nil
+	}
+}
+
+func removeByType(current []MultiValue, entryType string) []MultiValue {
+	kept := current[:0]
+	for _, item := range current {
+		if !strings.EqualFold(item.Type, entryType) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+func multiValues(value any) ([]MultiValue, error) {
+	var list []any
+	switch v := value.(type) {
+	case []any:
+		list = v
+	case map[string]any:
+		list = []any{v}
+	default:
+		return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "multi-valued attribute must be an object or array")
+	}
+
+	items := make([]MultiValue, 0, len(list))
+	for _, raw := range list {
+		entry, ok := raw.(map[string]any)
+		if !ok {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "multi-valued attribute entries must be objects")
+		}
+		item := MultiValue{}
+		item.Value, _ = entry["value"].(string)
+		item.Type, _ = entry["type"].(string)
+		item.Primary, _ = entry["primary"].(bool)
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// memberSet 保持插入顺序的成员集合
+type memberSet struct {
+	order []int64
+	index map[int64]struct{}
+}
+
+func newMemberSet(members []MultiValue) *memberSet {
+	set := &memberSet{index: make(map[int64]struct{})}
+	for _, member := range members {
+		// 现有成员均来自数据库，标识必然合法
+		_ = set.add(member.Value)
+	}
+	return set
+}
+
+func (s *memberSet) add(value string) error {
+	id, ok := parseID(value)
+	if !ok {
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "invalid member %q", value)
+	}
+	if _, exists := s.index[id]; !exists {
+		s.index[id] = struct{}{}
+		s.order = append(s.order, id)
+	}
+	return nil
+}
+
+func (s *memberSet) remove(value string) {
+	id, ok := parseID(value)
+	if !ok {
+		return
+	}
+	if _, exists := s.index[id]; !exists {
+		return
+	}
+	delete(s.index, id)
+	for i, item := range s.order {
+		if item == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *memberSet) reset() {
+	s.order = nil
+	s.index = make(map[int64]struct{})
+}
+
+func (s *memberSet) ids() []int64 {
+	return append([]int64{}, s.order...)
+}
+
+// patchGroup 将单个操作应用到组，成员变化记录在 members 中，名称变化记录在 change 中
+func patchGroup(change *groupChange, members *memberSet, operation PatchOperation) error {
+	op := strings.ToLower(operation.Op)
+	path := strings.TrimSpace(operation.Path)
+	if path == "" {
+		if op == patchRemove {
+			return newError(http.StatusBadRequest, scimTypeNoTarget, "remove requires a path")
+		}
+		values, ok := operation.Value.(map[string]any)
+		if !ok {
+			return newError(http.StatusBadRequest, scimTypeInvalidValue, "value must be an object when path is omitted")
+		}
+		for key, value := range values {
+			if err := patchGroupAttribute(change, members, op, key, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return patchGroupAttribute(change, members, op, path, operation.Value)
+}
+
+func patchGroupAttribute(change *groupChange, members *memberSet, op, path string, value any) error {
+	lower := strings.ToLower(path)
+	switch {
+	case lower == "displayname":
+		if op == patchRemove {
+			return newError(http.StatusBadRequest, scimTypeMutability, "displayName cannot be removed")
+		}
+		text, err := stringValue(path, value)
+		if err != nil {
+			return err
+		}
+		change.displayName = &text
+	case lower == "externalid":
+		if op == patchRemove {
+			return newError(http.StatusBadRequest, scimTypeMutability, "externalId cannot be removed")
+		}
+		text, err := stringValue(path, value)
+		if err != nil {
+			return err
+		}
+		change.externalID = &text
+	case lower == "members":
+		if op == patchRemove && value == nil {
+			members.reset()
+			return nil
+		}
+		items, err := multiValues(value)
+		if err != nil {
+			return err
+		}
+		if op == patchReplace {
+			members.reset()
+		}
+		for _, item := range items {
+			if op == patchRemove {
+				members.remove(item.Value)
+				continue
+			}
+			if err := members.add(item.Value); err != nil {
+				return err
+			}
+		}
+	case strings.HasPrefix(lower, "members["):
+		// 形如 members[value eq "12"]，只支持移除单个成员
+		end := strings.LastIndex(path, "]")
+		if op != patchRemove || end < 0 {
+			return newError(http.StatusBadRequest, scimTypeInvalidPath, "unsupported path %q", path)
+		}
+		conditions, err := ParseFilter(path[len("members["):end])
+		if err != nil {
+			return err
+		}
+		if len(conditions) != 1 || conditions[0].Attr != "value" || conditions[0].Op != opEqual {
+			return newError(http.StatusBadRequest, scimTypeInvalidPath, "only value eq filters are supported for members")
+		}
+		members.remove(conditions[0].Value)
+	default:
+		return newError(http.StatusBadRequest, scimTypeInvalidPath, "unsupported path %q", path)
+	}
+	return nil
+}
+
+func stringValue(path string, value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", newError(http.StatusBadRequest, scimTypeInvalidValue, "%s must be a string", path)
+	}
+}
+
+func optionalString(path string, value any, remove bool) (string, error) {
+	if remove || value == nil {
+		return "", nil
+	}
+	return stringValue(path, value)
+}
+
+// boolValue 兼容部分身份源以字符串 "True"/"False" 传递布尔值
+func boolValue(path string, value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if parsed, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			return parsed, nil
+		}
+	}
+	return false, newError(http.StatusBadRequest, scimTypeInvalidValue, "%s must be a boolean", path)
+}
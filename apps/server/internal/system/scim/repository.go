@@ -0,0 +1,532 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+var ErrRepositoryUnavailable = errors.New("scim repository is not initialized")
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	if db == nil {
+		return nil
+	}
+	return &Repository{db: db}
+}
+
+// userColumns SCIM 用户属性与数据表列的对应关系，caseExact 为 false 的属性忽略大小写比较
+var userColumns = map[string]struct {
+	column    string
+	caseExact bool
+}{
+	"username":           {column: "user_name"},
+	"externalid":         {column: "external_id", caseExact: true},
+	"displayname":        {column: "nick_name"},
+	"name.formatted":     {column: "nick_name"},
+	"emails":             {column: "email"},
+	"emails.value":       {column: "email"},
+	"phonenumbers":       {column: "phonenumber"},
+	"phonenumbers.value": {column: "phonenumber"},
+}
+
+// userRecord 用户及其渲染 SCIM 资源所需的关联数据
+type userRecord struct {
+	user  model.SysUser
+	dept  *model.SysDept
+	roles []model.SysRole
+}
+
+// ListUsers 按过滤条件分页查询用户，offset 从 0 开始，limit 为 0 时只统计数量
+func (r *Repository) ListUsers(ctx context.Context, conditions []Condition, offset, limit int) ([]userRecord, int64, error) {
+	if r == nil || r.db == nil {
+		return nil, 0, ErrRepositoryUnavailable
+	}
+
+	query := r.db.WithContext(ctx).Model(&model.SysUser{})
+	for _, condition := range conditions {
+		var err error
+		if query, err = applyUserCondition(query, condition); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 || limit == 0 {
+		return []userRecord{}, total, nil
+	}
+
+	var users []model.SysUser
+	if err := query.Session(&gorm.Session{}).Order("id ASC").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	records, err := r.loadUserRelations(ctx, users)
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+func applyUserCondition(query *gorm.DB, condition Condition) (*gorm.DB, error) {
+	switch condition.Attr {
+	case "id":
+		if condition.Op != opEqual {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "id only supports eq")
+		}
+		id, ok := parseID(condition.Value)
+		if !ok {
+			return query.Where("1 = 0"), nil
+		}
+		return query.Where("id = ?", id), nil
+	case "active":
+		if condition.Op == opPresent {
+			return query, nil
+		}
+		active, err := strconv.ParseBool(condition.Value)
+		if err != nil || (condition.Op != opEqual && condition.Op != opNotEqual) {
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "active only supports eq and ne with a boolean")
+		}
+		if condition.Op == opNotEqual {
+			active = !active
+		}
+		return query.Where("status = ?", statusFromActive(active)), nil
+	}
+
+	mapping, ok := userColumns[condition.Attr]
+	if !ok {
+		return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "filtering on %q is not supported", condition.Attr)
+	}
+	return applyStringCondition(query, mapping.column, mapping.caseExact, condition), nil
+}
+
+// applyStringCondition 将字符串比较转换为 SQL，使用 LOWER 以兼容不支持 ILIKE 的数据库
+func applyStringCondition(query *gorm.DB, column string, caseExact bool, condition Condition) *gorm.DB {
+	if condition.Op == opPresent {
+		return query.Where(column + " IS NOT NULL AND " + column + " <> ''")
+	}
+
+	target, value := column, condition.Value
+	if !caseExact {
+		target, value = "LOWER("+column+")", strings.ToLower(value)
+	}
+	escaped := escapeLike(value)
+	switch condition.Op {
+	case opNotEqual:
+		return query.Where(target+" <> ?", value)
+	case opContains:
+		return query.Where(target+" LIKE ? ESCAPE '\\'", "%"+escaped+"%")
+	case opStartsWith:
+		return query.Where(target+" LIKE ? ESCAPE '\\'", escaped+"%")
+	case opEndsWith:
+		return query.Where(target+" LIKE ? ESCAPE '\\'", "%"+escaped)
+	default:
+		return query.Where(target+" = ?", value)
+	}
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// GetUser 读取单个用户及其部门与直接分配的角色
+func (r *Repository) GetUser(ctx context.Context, id int64) (*userRecord, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	var user model.SysUser
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error; err != nil {
+		return nil, err
+	}
+	records, err := r.loadUserRelations(ctx, []model.SysUser{user})
+	if err != nil {
+		return nil, err
+	}
+	return &records[0], nil
+}
+
+func (r *Repository) loadUserRelations(ctx context.Context, users []model.SysUser) ([]userRecord, error) {
+	records := make([]userRecord, len(users))
+	if len(users) == 0 {
+		return records, nil
+	}
+
+	userIDs := make([]int64, 0, len(users))
+	deptIDs := make([]int64, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, int64(user.ID))
+		if user.DeptID != nil && *user.DeptID > 0 {
+			deptIDs = append(deptIDs, *user.DeptID)
+		}
+	}
+
+	db := r.db.WithContext(ctx)
+	depts := make(map[int64]model.SysDept, len(deptIDs))
+	if len(deptIDs) > 0 {
+		var list []model.SysDept
+		if err := db.Where("id IN ?", deptIDs).Find(&list).Error; err != nil {
+			return nil, err
+		}
+		for _, dept := range list {
+			depts[int64(dept.ID)] = dept
+		}
+	}
+
+	var links []model.SysUserRole
+	if err := db.Where("user_id IN ?", userIDs).Order("role_id ASC").Find(&links).Error; err != nil {
+		return nil, err
+	}
+	roleIDs := make([]int64, 0, len(links))
+	for _, link := range links {
+		roleIDs = append(roleIDs, link.RoleID)
+	}
+	roles := make(map[int64]model.SysRole, len(roleIDs))
+	if len(roleIDs) > 0 {
+		var list []model.SysRole
+		if err := db.Where("id IN ?", roleIDs).Find(&list).Error; err != nil {
+			return nil, err
+		}
+		for _, role := range list {
+			roles[int64(role.ID)] = role
+		}
+	}
+	userRoles := make(map[int64][]model.SysRole, len(users))
+	for _, link := range links {
+		if role, ok := roles[link.RoleID]; ok {
+			userRoles[link.UserID] = append(userRoles[link.UserID], role)
+		}
+	}
+
+	for i, user := range users {
+		records[i].user = user
+		records[i].roles = userRoles[int64(user.ID)]
+		if user.DeptID != nil {
+			if dept, ok := depts[*user.DeptID]; ok {
+				records[i].dept = &dept
+			}
+		}
+	}
+	return records, nil
+}
+
+// UpdateUserLink 更新 SCIM 独有的字段：外部标识与所属部门
+func (r *Repository) UpdateUserLink(ctx context.Context, userID int64, externalID *string, deptID *int64, operator string, at time.Time) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	return r.db.WithContext(ctx).Model(&model.SysUser{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"external_id": externalID,
+		"dept_id":     deptID,
+		"update_by":   operator,
+		"updated_at":  at,
+	}).Error
+}
+
+// FindDepartmentByName 按名称查找部门，同名部门存在多个时无法确定归属
+func (r *Repository) FindDepartmentByName(ctx context.Context, name string) (*model.SysDept, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	var depts []model.SysDept
+	if err := r.db.WithContext(ctx).Where("dept_name = ?", name).Limit(2).Find(&depts).Error; err != nil {
+		return nil, err
+	}
+	switch len(depts) {
+	case 0:
+		return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "department %q does not exist", name)
+	case 1:
+		return &depts[0], nil
+	default:
+		return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "department name %q is ambiguous", name)
+	}
+}
+
+// groupRecord 角色或部门，以组的形式暴露
+type groupRecord struct {
+	kind       string
+	id         int64
+	name       string
+	externalID string
+	createdAt  time.Time
+	updatedAt  time.Time
+	members    []model.SysUser
+}
+
+// ListGroups 分页查询组，角色在前、部门在后
+func (r *Repository) ListGroups(ctx context.Context, conditions []Condition, offset, limit int, withMembers bool) ([]groupRecord, int64, error) {
+	if r == nil || r.db == nil {
+		return nil, 0, ErrRepositoryUnavailable
+	}
+
+	db := r.db.WithContext(ctx)
+	roleQuery, err := applyGroupConditions(db.Model(&model.SysRole{}), GroupTypeRole, conditions)
+	if err != nil {
+		return nil, 0, err
+	}
+	deptQuery, err := applyGroupConditions(db.Model(&model.SysDept{}), GroupTypeDept, conditions)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var roleTotal, deptTotal int64
+	if err := roleQuery.Session(&gorm.Session{}).Count(&roleTotal).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := deptQuery.Session(&gorm.Session{}).Count(&deptTotal).Error; err != nil {
+		return nil, 0, err
+	}
+	total := roleTotal + deptTotal
+
+	groups := make([]groupRecord, 0, limit)
+	if limit > 0 && int64(offset) < roleTotal {
+		var roles []model.SysRole
+		if err := roleQuery.Session(&gorm.Session{}).Order("id ASC").Offset(offset).Limit(limit).Find(&roles).Error; err != nil {
+			return nil, 0, err
+		}
+		for _, role := range roles {
+			groups = append(groups, roleGroup(role))
+		}
+	}
+	if remaining := limit - len(groups); remaining > 0 {
+		deptOffset := offset - int(roleTotal)
+		if deptOffset < 0 {
+			deptOffset = 0
+		}
+		var depts []model.SysDept
+		if err := deptQuery.Session(&gorm.Session{}).Order("id ASC").Offset(deptOffset).Limit(remaining).Find(&depts).Error; err != nil {
+			return nil, 0, err
+		}
+		for _, dept := range depts {
+			groups = append(groups, deptGroup(dept))
+		}
+	}
+
+	if withMembers {
+		for i := range groups {
+			if groups[i].members, err = r.groupMembers(db, groups[i].kind, groups[i].id); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return groups, total, nil
+}
+
+func applyGroupConditions(query *gorm.DB, kind string, conditions []Condition) (*gorm.DB, error) {
+	nameColumn := "role_name"
+	if kind == GroupTypeDept {
+		nameColumn = "dept_name"
+	}
+
+	for _, condition := range conditions {
+		switch condition.Attr {
+		case "displayname":
+			query = applyStringCondition(query, nameColumn, false, condition)
+		case "externalid":
+			// 角色的外部标识即角色权限字符，部门没有外部标识
+			if kind == GroupTypeDept {
+				if condition.Op == opNotEqual {
+					continue
+				}
+				query = query.Where("1 = 0")
+				continue
+			}
+			query = applyStringCondition(query, "role_key", true, condition)
+		case "id":
+			if condition.Op != opEqual {
+				return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "id only supports eq")
+			}
+			groupKind, id, ok := parseGroupID(condition.Value)
+			if !ok || groupKind != kind {
+				query = query.Where("1 = 0")
+				continue
+			}
+			query = query.Where("id = ?", id)
+		default:
+			return nil, newError(http.StatusBadRequest, scimTypeInvalidFilter, "filtering on %q is not supported", condition.Attr)
+		}
+	}
+	return query, nil
+}
+
+// GetGroup 读取单个组及其成员
+func (r *Repository) GetGroup(ctx context.Context, kind string, id int64, withMembers bool) (*groupRecord, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	db := r.db.WithContext(ctx)
+	var group groupRecord
+	if kind == GroupTypeRole {
+		var role model.SysRole
+		if err := db.Where("id = ?", id).First(&role).Error; err != nil {
+			return nil, err
+		}
+		group = roleGroup(role)
+	} else {
+		var dept model.SysDept
+		if err := db.Where("id = ?", id).First(&dept).Error; err != nil {
+			return nil, err
+		}
+		group = deptGroup(dept)
+	}
+
+	if withMembers {
+		members, err := r.groupMembers(db, kind, id)
+		if err != nil {
+			return nil, err
+		}
+		group.members = members
+	}
+	return &group, nil
+}
+
+func (r *Repository) groupMembers(db *gorm.DB, kind string, id int64) ([]model.SysUser, error) {
+	query := db.Model(&model.SysUser{}).Select("id", "user_name")
+	if kind == GroupTypeRole {
+		query = query.Where("id IN (?)", db.Model(&model.SysUserRole{}).Select("user_id").Where("role_id = ?", id))
+	} else {
+		query = query.Where("dept_id = ?", id)
+	}
+
+	var users []model.SysUser
+	if err := query.Order("id ASC").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func roleGroup(role model.SysRole) groupRecord {
+	return groupRecord{
+		kind:       GroupTypeRole,
+		id:         int64(role.ID),
+		name:       role.RoleName,
+		externalID: role.RoleKey,
+		createdAt:  role.CreatedAt,
+		updatedAt:  role.UpdatedAt,
+	}
+}
+
+func deptGroup(dept model.SysDept) groupRecord {
+	return groupRecord{
+		kind:      GroupTypeDept,
+		id:        int64(dept.ID),
+		name:      dept.DeptName,
+		createdAt: dept.CreatedAt,
+		updatedAt: dept.UpdatedAt,
+	}
+}
+
+// FilterExistingUsers 返回仍然存在的用户ID，保持输入顺序
+func (r *Repository) FilterExistingUsers(ctx context.Context, ids []int64) ([]int64, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	if len(ids) == 0 {
+		return []int64{}, nil
+	}
+
+	var existing []int64
+	if err := r.db.WithContext(ctx).Model(&model.SysUser{}).Where("id IN ?", ids).Pluck("id", &existing).Error; err != nil {
+		return nil, err
+	}
+	found := make(map[int64]struct{}, len(existing))
+	for _, id := range existing {
+		found[id] = struct{}{}
+	}
+	result := make([]int64, 0, len(existing))
+	for _, id := range ids {
+		if _, ok := found[id]; ok {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// UpdateMembers 在同一事务内调整组成员：replace 为真时先清空原有成员。
+// 角色成员写入用户角色关联表；部门成员通过修改用户所属部门实现，移出部门后用户不属于任何部门。
+func (r *Repository) UpdateMembers(ctx context.Context, kind string, id int64, replace bool, add, remove []int64) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if kind == GroupTypeRole {
+			// 角色分配变化的用户需要刷新 updated_at，使其旧的 ETag 失效
+			touched := append(append([]int64{}, add...), remove...)
+			if replace {
+				var members []int64
+				if err := tx.Model(&model.SysUserRole{}).Where("role_id = ?", id).Pluck("user_id", &members).Error; err != nil {
+					return err
+				}
+				touched = append(touched, members...)
+			}
+			if len(touched) > 0 {
+				if err := tx.Model(&model.SysUser{}).Where("id IN ?", touched).Update("updated_at", time.Now()).Error; err != nil {
+					return err
+				}
+			}
+			if replace || len(remove) > 0 {
+				detach := tx.Where("role_id = ?", id)
+				if !replace {
+					detach = detach.Where("user_id IN ?", remove)
+				}
+				if err := detach.Delete(&model.SysUserRole{}).Error; err != nil {
+					return err
+				}
+			}
+			if len(add) == 0 {
+				return nil
+			}
+			entries := make([]model.SysUserRole, len(add))
+			for i, userID := range add {
+				entries[i] = model.SysUserRole{UserID: userID, RoleID: id}
+			}
+			return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entries).Error
+		}
+
+		if replace || len(remove) > 0 {
+			detach := tx.Model(&model.SysUser{}).Where("dept_id = ?", id)
+			if !replace {
+				detach = detach.Where("id IN ?", remove)
+			}
+			if err := detach.Update("dept_id", nil).Error; err != nil {
+				return err
+			}
+		}
+		if len(add) == 0 {
+			return nil
+		}
+		return tx.Model(&model.SysUser{}).Where("id IN ?", add).Update("dept_id", id).Error
+	})
+}
+
+func parseID(value string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+func statusFromActive(active bool) string {
+	if active {
+		return "0"
+	}
+	return "1"
+}
@@ -0,0 +1,178 @@
+package scim
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SCIM 2.0 协议使用的 schema 标识（RFC 7643 / RFC 7644）
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaEnterpriseUser        = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+// ContentType SCIM 响应使用的媒体类型
+const ContentType = "application/scim+json"
+
+// 组标识前缀：角色与部门均以组的形式暴露给身份源
+const (
+	groupPrefixRole = "role-"
+	groupPrefixDept = "dept-"
+)
+
+// 组类型
+const (
+	GroupTypeRole = "role"
+	GroupTypeDept = "department"
+)
+
+// Meta 资源元数据
+type Meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// MultiValue 邮箱、电话等多值属性
+type MultiValue struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// EnterpriseUser 企业扩展，department 对应用户所属部门名称
+type EnterpriseUser struct {
+	Department string `json:"department,omitempty"`
+}
+
+// User SCIM 用户资源，对应 SysUser
+type User struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id,omitempty"`
+	ExternalID  string          `json:"externalId,omitempty"`
+	UserName    string          `json:"userName"`
+	Name        *Name           `json:"name,omitempty"`
+	DisplayName string          `json:"displayName,omitempty"`
+	Active      *bool           `json:"active,omitempty"`
+	Password    string          `json:"password,omitempty"`
+	Emails      []MultiValue    `json:"emails,omitempty"`
+	Phones      []MultiValue    `json:"phoneNumbers,omitempty"`
+	Groups      []MultiValue    `json:"groups,omitempty"`
+	Enterprise  *EnterpriseUser `json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,omitempty"`
+	Meta        *Meta           `json:"meta,omitempty"`
+}
+
+// Group SCIM 组资源，对应角色或部门
+type Group struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []MultiValue `json:"members"`
+	Meta        *Meta        `json:"meta,omitempty"`
+}
+
+// ListResponse 分页查询结果，startIndex 从 1 开始
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int64    `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources"`
+}
+
+// PatchRequest PATCH 请求体
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation 单个 PATCH 操作，Value 的结构取决于 Path
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// ErrorResponse SCIM 错误响应
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// scimType 错误分类
+const (
+	scimTypeInvalidFilter = "invalidFilter"
+	scimTypeInvalidValue  = "invalidValue"
+	scimTypeInvalidPath   = "invalidPath"
+	scimTypeInvalidSyntax = "invalidSyntax"
+	scimTypeUniqueness    = "uniqueness"
+	scimTypeMutability    = "mutability"
+	scimTypeNoTarget      = "noTarget"
+)
+
+// Error 带有 SCIM 错误分类的业务错误，Status 为 HTTP 状态码
+type Error struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+func (e *Error) Error() string {
+	if e.ScimType == "" {
+		return e.Detail
+	}
+	return e.ScimType + ": " + e.Detail
+}
+
+func newError(status int, scimType, format string, args ...any) *Error {
+	return &Error{Status: status, ScimType: scimType, Detail: fmt.Sprintf(format, args...)}
+}
+
+func roleGroupID(id int64) string {
+	return fmt.Sprintf("%s%d", groupPrefixRole, id)
+}
+
+func deptGroupID(id int64) string {
+	return fmt.Sprintf("%s%d", groupPrefixDept, id)
+}
+
+// parseGroupID 解析组标识，返回组类型与记录ID
+func parseGroupID(value string) (string, int64, bool) {
+	value = strings.TrimSpace(value)
+	var (
+		kind   string
+		digits string
+	)
+	switch {
+	case strings.HasPrefix(value, groupPrefixRole):
+		kind, digits = GroupTypeRole, strings.TrimPrefix(value, groupPrefixRole)
+	case strings.HasPrefix(value, groupPrefixDept):
+		kind, digits = GroupTypeDept, strings.TrimPrefix(value, groupPrefixDept)
+	default:
+		return "", 0, false
+	}
+	id, ok := parseID(digits)
+	if !ok {
+		return "", 0, false
+	}
+	return kind, id, true
+}
@@ -0,0 +1,688 @@
+package scim
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/system/role"
+	"github.com/starter-kit-fe/admin/internal/system/user"
+)
+
+var ErrServiceUnavailable = errors.New("scim service is not initialized")
+
+// BasePath SCIM 接口的挂载路径，用于生成资源 Location
+const BasePath = "/api/scim/v2"
+
+const (
+	operator         = "scim"
+	defaultPageSize  = 100
+	maxPageSize      = 200
+	generatedKeySize = 6
+)
+
+// SessionRevoker 用于在账号停用或删除时注销其全部会话
+type SessionRevoker interface {
+	RevokeAll(ctx context.Context, userID uint) (int, error)
+}
+
+type Service struct {
+	repo     *Repository
+	users    *user.Service
+	roles    *role.Service
+	sessions SessionRevoker
+}
+
+func NewService(repo *Repository, users *user.Service, roles *role.Service, sessions SessionRevoker) *Service {
+	if repo == nil || users == nil || roles == nil {
+		return nil
+	}
+	return &Service{repo: repo, users: users, roles: roles, sessions: sessions}
+}
+
+// ListOptions 列表查询参数，StartIndex 从 1 开始
+type ListOptions struct {
+	Filter         string
+	StartIndex     int
+	Count          *int
+	IncludeMembers bool
+}
+
+func (o ListOptions) page() (offset, limit, startIndex int) {
+	startIndex = o.StartIndex
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	limit = defaultPageSize
+	if o.Count != nil {
+		limit = *o.Count
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return startIndex - 1, limit, startIndex
+}
+
+// ListUsers 按过滤条件分页查询用户
+func (s *Service) ListUsers(ctx context.Context, opts ListOptions) (*ListResponse, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	conditions, err := ParseFilter(opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+	offset, limit, startIndex := opts.page()
+	records, total, err := s.repo.ListUsers(ctx, conditions, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]*User, 0, len(records))
+	for i := range records {
+		resources = append(resources, renderUser(&records[i]))
+	}
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, nil
+}
+
+// GetUser 读取单个用户
+func (s *Service) GetUser(ctx context.Context, id string) (*User, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	record, err := s.loadUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return renderUser(record), nil
+}
+
+func (s *Service) loadUser(ctx context.Context, id string) (*userRecord, error) {
+	userID, ok := parseID(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return s.repo.GetUser(ctx, userID)
+}
+
+// CreateUser 新建用户；身份源未提供密码时生成随机密码，账号通过单点登录使用
+func (s *Service) CreateUser(ctx context.Context, input *User) (*User, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	desired, err := normalizeUser(input)
+	if err != nil {
+		return nil, err
+	}
+	var deptID *int64
+	if desired.department != nil && *desired.department != "" {
+		dept, err := s.repo.FindDepartmentByName(ctx, *desired.department)
+		if err != nil {
+			return nil, err
+		}
+		id := int64(dept.ID)
+		deptID = &id
+	}
+
+	password := desired.password
+	if password == "" {
+		if password, err = randomPassword(); err != nil {
+			return nil, err
+		}
+	}
+	status := "0"
+	if desired.active != nil {
+		status = statusFromActive(*desired.active)
+	}
+
+	created, err := s.users.CreateUser(ctx, user.CreateUserInput{
+		UserName:    desired.userName,
+		NickName:    desired.nickName,
+		DeptID:      deptID,
+		Email:       desired.email,
+		Phonenumber: desired.phone,
+		Status:      status,
+		Password:    password,
+		Operator:    operator,
+	})
+	if err != nil {
+		return nil, translateUserError(err)
+	}
+	if err := s.repo.UpdateUserLink(ctx, created.UserID, desired.externalID, deptID, operator, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, strconv.FormatInt(created.UserID, 10))
+}
+
+// ReplaceUser 以请求内容替换用户属性。
+// 未提供 active 与 password 时保持原值；仅在提供企业扩展时调整部门，部门名称为空表示移出部门。
+func (s *Service) ReplaceUser(ctx context.Context, id string, input *User) (*User, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	existing, err := s.loadUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := normalizeUser(input)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyUser(ctx, existing, desired)
+}
+
+// PatchUser 将 PATCH 操作应用到当前用户后整体保存
+func (s *Service) PatchUser(ctx context.Context, id string, req *PatchRequest) (*User, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	existing, err := s.loadUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePatch(req); err != nil {
+		return nil, err
+	}
+
+	state := renderUser(existing)
+	state.Groups = nil
+	if state.Enterprise == nil {
+		state.Enterprise = &EnterpriseUser{}
+	}
+	for _, operation := range req.Operations {
+		if err := patchUser(state, operation); err != nil {
+			return nil, err
+		}
+	}
+
+	desired, err := normalizeUser(state)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyUser(ctx, existing, desired)
+}
+
+func (s *Service) applyUser(ctx context.Context, existing *userRecord, desired *userState) (*User, error) {
+	userID := int64(existing.user.ID)
+
+	deptID := existing.user.DeptID
+	if desired.department != nil {
+		switch {
+		case *desired.department == "":
+			deptID = nil
+		case existing.dept == nil || existing.dept.DeptName != *desired.department:
+			dept, err := s.repo.FindDepartmentByName(ctx, *desired.department)
+			if err != nil {
+				return nil, err
+			}
+			id := int64(dept.ID)
+			deptID = &id
+		}
+	}
+
+	input := user.UpdateUserInput{
+		ID:          userID,
+		UserName:    &desired.userName,
+		NickName:    &desired.nickName,
+		Email:       &desired.email,
+		Phonenumber: &desired.phone,
+		Operator:    operator,
+	}
+	deactivated := false
+	if desired.active != nil {
+		status := statusFromActive(*desired.active)
+		input.Status = &status
+		deactivated = !*desired.active && existing.user.Status == "0"
+	}
+	if _, err := s.users.UpdateUser(ctx, input); err != nil {
+		return nil, translateUserError(err)
+	}
+	if desired.password != "" {
+		if err := s.users.ResetPassword(ctx, user.ResetPasswordInput{UserID: userID, Password: desired.password, Operator: operator}); err != nil {
+			return nil, translateUserError(err)
+		}
+	}
+	if err := s.repo.UpdateUserLink(ctx, userID, desired.externalID, deptID, operator, time.Now()); err != nil {
+		return nil, err
+	}
+	if deactivated {
+		s.revokeSessions(ctx, userID)
+	}
+	return s.GetUser(ctx, strconv.FormatInt(userID, 10))
+}
+
+// DeleteUser 删除用户（进入回收站）并注销其会话
+func (s *Service) DeleteUser(ctx context.Context, id string) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+
+	existing, err := s.loadUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	userID := int64(existing.user.ID)
+	if err := s.users.DeleteUser(ctx, user.DeleteUserInput{ID: userID, Operator: operator}); err != nil {
+		return err
+	}
+	s.revokeSessions(ctx, userID)
+	return nil
+}
+
+func (s *Service) revokeSessions(ctx context.Context, userID int64) {
+	if s.sessions == nil {
+		return
+	}
+	// 会话注销失败不影响账号状态的同步，令牌将在过期后失效
+	_, _ = s.sessions.RevokeAll(ctx, uint(userID))
+}
+
+// ListGroups 分页查询组，excludedAttributes=members 时不加载成员
+func (s *Service) ListGroups(ctx context.Context, opts ListOptions) (*ListResponse, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	conditions, err := ParseFilter(opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+	offset, limit, startIndex := opts.page()
+	records, total, err := s.repo.ListGroups(ctx, conditions, offset, limit, opts.IncludeMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]*Group, 0, len(records))
+	for i := range records {
+		resources = append(resources, renderGroup(&records[i], opts.IncludeMembers))
+	}
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, nil
+}
+
+// GetGroup 读取单个组
+func (s *Service) GetGroup(ctx context.Context, id string, includeMembers bool) (*Group, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	kind, groupID, ok := parseGroupID(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	record, err := s.repo.GetGroup(ctx, kind, groupID, includeMembers)
+	if err != nil {
+		return nil, err
+	}
+	return renderGroup(record, includeMembers), nil
+}
+
+// CreateGroup 新建组，仅支持创建角色；externalId 作为角色权限字符，缺省时自动生成
+func (s *Service) CreateGroup(ctx context.Context, input *Group) (*Group, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	if input == nil || strings.TrimSpace(input.DisplayName) == "" {
+		return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "displayName is required")
+	}
+
+	members, err := s.memberIDs(ctx, input.Members)
+	if err != nil {
+		return nil, err
+	}
+	roleKey := strings.TrimSpace(input.ExternalID)
+	if roleKey == "" {
+		suffix := make([]byte, generatedKeySize)
+		if _, err := rand.Read(suffix); err != nil {
+			return nil, err
+		}
+		roleKey = "scim_" + hex.EncodeToString(suffix)
+	}
+
+	created, err := s.roles.CreateRole(ctx, role.CreateRoleInput{
+		RoleName: input.DisplayName,
+		RoleKey:  roleKey,
+		Status:   "0",
+		Operator: operator,
+	})
+	if err != nil {
+		return nil, translateRoleError(err)
+	}
+	if err := s.repo.UpdateMembers(ctx, GroupTypeRole, created.RoleID, true, members, nil); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, roleGroupID(created.RoleID), true)
+}
+
+// ReplaceGroup 替换组名称与成员，部门只允许调整成员
+func (s *Service) ReplaceGroup(ctx context.Context, id string, input *Group) (*Group, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	if input == nil || strings.TrimSpace(input.DisplayName) == "" {
+		return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "displayName is required")
+	}
+
+	current, err := s.GetGroup(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.memberIDs(ctx, input.Members)
+	if err != nil {
+		return nil, err
+	}
+	change := groupChange{displayName: &input.DisplayName, replace: true, add: members}
+	if externalID := strings.TrimSpace(input.ExternalID); externalID != "" {
+		change.externalID = &externalID
+	}
+	if err := s.applyGroup(ctx, id, current, change); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, id, true)
+}
+
+// PatchGroup 应用组的 PATCH 操作，常用于身份源增量调整成员
+func (s *Service) PatchGroup(ctx context.Context, id string, req *PatchRequest) (*Group, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	current, err := s.GetGroup(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePatch(req); err != nil {
+		return nil, err
+	}
+
+	state := newMemberSet(current.Members)
+	change := groupChange{}
+	for _, operation := range req.Operations {
+		if err := patchGroup(&change, state, operation); err != nil {
+			return nil, err
+		}
+	}
+
+	members, err := s.repo.FilterExistingUsers(ctx, state.ids())
+	if err != nil {
+		return nil, err
+	}
+	if len(members) != len(state.ids()) {
+		return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "members reference users that do not exist")
+	}
+	change.replace = true
+	change.add = members
+	if err := s.applyGroup(ctx, id, current, change); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, id, true)
+}
+
+// groupChange 组的待保存变更，replace 为真时以 add 作为完整成员列表
+type groupChange struct {
+	displayName *string
+	externalID  *string
+	replace     bool
+	add         []int64
+}
+
+func (s *Service) applyGroup(ctx context.Context, id string, current *Group, change groupChange) error {
+	kind, groupID, _ := parseGroupID(id)
+
+	renamed := change.displayName != nil && strings.TrimSpace(*change.displayName) != current.DisplayName
+	rekeyed := change.externalID != nil && *change.externalID != current.ExternalID
+	if kind == GroupTypeDept && (renamed || rekeyed) {
+		return newError(http.StatusBadRequest, scimTypeMutability, "department attributes are managed in the admin console; only members can be changed")
+	}
+	if renamed || rekeyed {
+		input := role.UpdateRoleInput{ID: groupID, Operator: operator}
+		if renamed {
+			input.RoleName = change.displayName
+		}
+		if rekeyed {
+			input.RoleKey = change.externalID
+		}
+		if _, err := s.roles.UpdateRole(ctx, input); err != nil {
+			return translateRoleError(err)
+		}
+	}
+	return s.repo.UpdateMembers(ctx, kind, groupID, change.replace, change.add, nil)
+}
+
+// DeleteGroup 删除角色组；部门不能通过 SCIM 删除
+func (s *Service) DeleteGroup(ctx context.Context, id string) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+
+	if _, err := s.GetGroup(ctx, id, false); err != nil {
+		return err
+	}
+	kind, groupID, _ := parseGroupID(id)
+	if kind == GroupTypeDept {
+		return newError(http.StatusBadRequest, scimTypeMutability, "departments cannot be deleted through SCIM")
+	}
+	if err := s.roles.DeleteRole(ctx, role.DeleteRoleInput{ID: groupID, Operator: operator}); err != nil {
+		return translateRoleError(err)
+	}
+	return nil
+}
+
+// memberIDs 解析成员引用并确认用户存在
+func (s *Service) memberIDs(ctx context.Context, members []MultiValue) ([]int64, error) {
+	set := newMemberSet(nil)
+	for _, member := range members {
+		if err := set.add(member.Value); err != nil {
+			return nil, err
+		}
+	}
+	ids := set.ids()
+	existing, err := s.repo.FilterExistingUsers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) != len(ids) {
+		return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "members reference users that do not exist")
+	}
+	return existing, nil
+}
+
+// userState 规范化后的用户属性，nil 表示请求未提供
+type userState struct {
+	userName   string
+	nickName   string
+	email      string
+	phone      string
+	password   string
+	active     *bool
+	externalID *string
+	department *string
+}
+
+func normalizeUser(input *User) (*userState, error) {
+	if input == nil {
+		return nil, newError(http.StatusBadRequest, scimTypeInvalidSyntax, "request body is required")
+	}
+
+	state := &userState{
+		userName: strings.TrimSpace(input.UserName),
+		email:    primaryValue(input.Emails),
+		phone:    primaryValue(input.Phones),
+		password: input.Password,
+		active:   input.Active,
+	}
+	if state.userName == "" {
+		return nil, newError(http.StatusBadRequest, scimTypeInvalidValue, "userName is required")
+	}
+
+	state.nickName = strings.TrimSpace(input.DisplayName)
+	if state.nickName == "" && input.Name != nil {
+		state.nickName = strings.TrimSpace(input.Name.Formatted)
+		if state.nickName == "" {
+			state.nickName = strings.TrimSpace(strings.TrimSpace(input.Name.GivenName) + " " + strings.TrimSpace(input.Name.FamilyName))
+		}
+	}
+	if state.nickName == "" {
+		state.nickName = state.userName
+	}
+
+	if externalID := strings.TrimSpace(input.ExternalID); externalID != "" {
+		state.externalID = &externalID
+	}
+	if input.Enterprise != nil {
+		department := strings.TrimSpace(input.Enterprise.Department)
+		state.department = &department
+	}
+	return state, nil
+}
+
+// primaryValue 返回标记为 primary 的值，没有时返回第一个非空值
+func primaryValue(values []MultiValue) string {
+	first := ""
+	for _, item := range values {
+		value := strings.TrimSpace(item.Value)
+		if value == "" {
+			continue
+		}
+		if item.Primary {
+			return value
+		}
+		if first == "" {
+			first = value
+		}
+	}
+	return first
+}
+
+func renderUser(record *userRecord) *User {
+	active := record.user.Status == "0"
+	id := strconv.FormatUint(uint64(record.user.ID), 10)
+	result := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          id,
+		UserName:    record.user.UserName,
+		Name:        &Name{Formatted: record.user.NickName},
+		DisplayName: record.user.NickName,
+		Active:      &active,
+		Meta:        resourceMeta("User", "/Users/"+id, record.user.CreatedAt, record.user.UpdatedAt),
+	}
+	if record.user.ExternalID != nil {
+		result.ExternalID = *record.user.ExternalID
+	}
+	if record.user.Email != "" {
+		result.Emails = []MultiValue{{Value: record.user.Email, Type: "work", Primary: true}}
+	}
+	if record.user.Phonenumber != "" {
+		result.Phones = []MultiValue{{Value: record.user.Phonenumber, Type: "work", Primary: true}}
+	}
+	for _, item := range record.roles {
+		groupID := roleGroupID(int64(item.ID))
+		result.Groups = append(result.Groups, MultiValue{Value: groupID, Display: item.RoleName, Type: GroupTypeRole, Ref: BasePath + "/Groups/" + groupID})
+	}
+	if record.dept != nil {
+		groupID := deptGroupID(int64(record.dept.ID))
+		result.Groups = append(result.Groups, MultiValue{Value: groupID, Display: record.dept.DeptName, Type: GroupTypeDept, Ref: BasePath + "/Groups/" + groupID})
+		result.Schemas = append(result.Schemas, SchemaEnterpriseUser)
+		result.Enterprise = &EnterpriseUser{Department: record.dept.DeptName}
+	}
+	return result
+}
+
+func renderGroup(record *groupRecord, includeMembers bool) *Group {
+	id := roleGroupID(record.id)
+	if record.kind == GroupTypeDept {
+		id = deptGroupID(record.id)
+	}
+	result := &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          id,
+		ExternalID:  record.externalID,
+		DisplayName: record.name,
+		Meta:        resourceMeta("Group", "/Groups/"+id, record.createdAt, record.updatedAt),
+	}
+	if includeMembers {
+		result.Members = make([]MultiValue, 0, len(record.members))
+		for _, member := range record.members {
+			userID := strconv.FormatUint(uint64(member.ID), 10)
+			result.Members = append(result.Members, MultiValue{Value: userID, Display: member.UserName, Ref: BasePath + "/Users/" + userID})
+		}
+	}
+	return result
+}
+
+func resourceMeta(resourceType, location string, created, updated time.Time) *Meta {
+	meta := &Meta{ResourceType: resourceType, Location: BasePath + location}
+	if !created.IsZero() {
+		meta.Created = &created
+	}
+	if !updated.IsZero() {
+		meta.LastModified = &updated
+	}
+	return meta
+}
+
+func randomPassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// translateUserError 将用户服务的校验错误转换为 SCIM 错误
+func translateUserError(err error) error {
+	switch {
+	case errors.Is(err, user.ErrDuplicateUsername):
+		return newError(http.StatusConflict, scimTypeUniqueness, "userName already exists")
+	case errors.Is(err, user.ErrUsernameRequired),
+		errors.Is(err, user.ErrNicknameRequired),
+		errors.Is(err, user.ErrPasswordRequired),
+		errors.Is(err, user.ErrPasswordTooShort),
+		errors.Is(err, user.ErrInvalidStatus):
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "%s", err.Error())
+	default:
+		return err
+	}
+}
+
+// translateRoleError 将角色服务的校验错误转换为 SCIM 错误
+func translateRoleError(err error) error {
+	switch {
+	case errors.Is(err, role.ErrDuplicateRoleName), errors.Is(err, role.ErrDuplicateRoleKey):
+		return newError(http.StatusConflict, scimTypeUniqueness, "%s", err.Error())
+	case errors.Is(err, role.ErrRoleHasChildren):
+		return newError(http.StatusConflict, "", "%s", err.Error())
+	case errors.Is(err, role.ErrRoleNameRequired), errors.Is(err, role.ErrRoleKeyRequired):
+		return newError(http.StatusBadRequest, scimTypeInvalidValue, "%s", err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,49 @@
+package settings
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+var ErrRepositoryUnavailable = errors.New("settings repository is not initialized")
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	if db == nil {
+		return nil
+	}
+	return &Repository{db: db}
+}
+
+// GetValue 按参数键名读取参数值，参数值为空时返回默认值，参数不存在时 found 为 false；
+// secret 为 true 时 value 为密文，由调用方解密
+func (r *Repository) GetValue(ctx context.Context, key string) (value string, secret bool, found bool, err error) {
+	if r == nil || r.db == nil {
+		return "", false, false, ErrRepositoryUnavailable
+	}
+
+	var record model.SysConfig
+	err = r.db.WithContext(ctx).
+		Select("id", "config_value", "default_value", "value_type").
+		Where("config_key = ?", key).
+		Order("id ASC").
+		First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false, false, nil
+	}
+	if err != nil {
+		return "", false, false, err
+	}
+	secret = record.ValueType == "secret"
+	if record.ConfigValue == "" && record.DefaultValue != nil {
+		return *record.DefaultValue, secret, true, nil
+	}
+	return record.ConfigValue, secret, true, nil
+}
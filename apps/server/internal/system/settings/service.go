@@ -0,0 +1,445 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/starter-kit-fe/admin/pkg/envelope"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+var (
+	ErrServiceUnavailable = errors.New("settings service is not initialized")
+	ErrSecretsUnavailable = errors.New("settings keyring is not configured")
+)
+
+const (
+	defaultKeyPrefix = "settings"
+	defaultCacheTTL  = 5 * time.Minute
+	// defaultLocalTTL 兜底进程内缓存的时效，失效消息丢失时最迟在该时间后读到新值
+	defaultLocalTTL = 30 * time.Second
+)
+
+// Redis 中缓存值的首字符标记参数是否存在，用于缓存不存在的键；secret 参数缓存密文
+const (
+	cachedFound   = "1"
+	cachedMissing = "0"
+	cachedSecret  = "2"
+)
+
+type Options struct {
+	KeyPrefix string
+	CacheTTL  time.Duration
+	LocalTTL  time.Duration
+	Logger    *slog.Logger
+	// Keyring 用于解密 secret 类型参数，未配置时读取 secret 参数返回错误
+	Keyring *envelope.Keyring
+}
+
+// Change 描述一次参数变更，订阅者据此重新读取所需的值
+type Change struct {
+	TenantID int64  `json:"tenantId"`
+	Key      string `json:"key"`
+}
+
+// Subscriber 在参数变更后被调用，ctx 已绑定变更所属租户
+type Subscriber func(ctx context.Context, change Change)
+
+// Service 为运行时读取 sys_config 参数提供带缓存的类型化访问。
+// 读取顺序为进程内缓存、Redis、数据库；参数修改后通过 Redis 发布失效消息，
+// 其他副本收到后清除本地缓存并通知订阅者。
+type Service struct {
+	repo     *Repository
+	cache    *redis.Client
+	logger   *slog.Logger
+	keyring  *envelope.Keyring
+	prefix   string
+	cacheTTL time.Duration
+	localTTL time.Duration
+	instance string
+
+	mu    sync.RWMutex
+	local map[string]localEntry
+
+	subMu       sync.RWMutex
+	subscribers map[string][]Subscriber
+
+	pubsub *redis.PubSub
+}
+
+type localEntry struct {
+	value   string
+	secret  bool
+	found   bool
+	expires time.Time
+}
+
+type invalidation struct {
+	Origin   string   `json:"origin"`
+	TenantID int64    `json:"tenantId"`
+	Keys     []string `json:"keys"`
+}
+
+// NewService creates the runtime settings service; cache is optional and, when nil,
+// values are only cached in process and changes are not propagated to other replicas.
+func NewService(repo *Repository, cache *redis.Client, opts Options) *Service {
+	if repo == nil {
+		return nil
+	}
+	prefix := strings.TrimSpace(opts.KeyPrefix)
+	if prefix == "" {
+		prefix = defaultKeyPrefix
+	}
+	cacheTTL := opts.CacheTTL
+	if cacheTTL <= 0 {
+		cacheTTL = defaultCacheTTL
+	}
+	localTTL := opts.LocalTTL
+	if localTTL <= 0 {
+		localTTL = defaultLocalTTL
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Service{
+		repo:        repo,
+		cache:       cache,
+		logger:      logger,
+		keyring:     opts.Keyring,
+		prefix:      prefix,
+		cacheTTL:    cacheTTL,
+		localTTL:    localTTL,
+		instance:    uuid.NewString(),
+		local:       make(map[string]localEntry),
+		subscribers: make(map[string][]Subscriber),
+	}
+}
+
+// Start 订阅失效频道，未配置 Redis 时无需订阅
+func (s *Service) Start(ctx context.Context) error {
+	if s == nil || s.cache == nil {
+		return nil
+	}
+
+	pubsub := s.cache.Subscribe(ctx, s.channel())
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return err
+	}
+	s.pubsub = pubsub
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			s.handleMessage(msg.Payload)
+		}
+	}()
+	return nil
+}
+
+// Stop 关闭失效频道订阅
+func (s *Service) Stop() {
+	if s == nil || s.pubsub == nil {
+		return
+	}
+	if err := s.pubsub.Close(); err != nil {
+		s.logger.Warn("close settings subscription", "error", err)
+	}
+}
+
+// Subscribe 注册指定参数键的变更回调，同一进程内的修改与其他副本广播的修改都会触发。
+// 每次使用时经 Get 系列方法读取的参数（如验证码开关、密码策略）在 Invalidate 后即读到新值，
+// 广播丢失时最迟在本地缓存 TTL 后生效，无需订阅；只有在启动时复制进长期状态的参数（如限流阈值）才需要订阅
+func (s *Service) Subscribe(key string, fn Subscriber) {
+	if s == nil || fn == nil {
+		return
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return
+	}
+	s.subMu.Lock()
+	s.subscribers[key] = append(s.subscribers[key], fn)
+	s.subMu.Unlock()
+}
+
+// Lookup 返回当前租户下参数键对应的值，found 表示参数是否存在
+func (s *Service) Lookup(ctx context.Context, key string) (string, bool, error) {
+	if s == nil || s.repo == nil {
+		return "", false, ErrServiceUnavailable
+	}
+	key = strings.TrimSpace(key)
+	tenantID := resolveTenant(ctx)
+	localKey := s.localKey(tenantID, key)
+
+	s.mu.RLock()
+	entry, ok := s.local[localKey]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expires) {
+		return s.reveal(entry)
+	}
+
+	if s.cache != nil {
+		cached, err := s.cache.Get(ctx, s.valueKey(tenantID, key)).Result()
+		switch {
+		case err == nil && strings.HasPrefix(cached, cachedFound):
+			return s.reveal(s.remember(localKey, cached[len(cachedFound):], false, true))
+		case err == nil && strings.HasPrefix(cached, cachedSecret):
+			return s.reveal(s.remember(localKey, cached[len(cachedSecret):], true, true))
+		case err == nil && cached == cachedMissing:
+			return s.reveal(s.remember(localKey, "", false, false))
+		case err != nil && !errors.Is(err, redis.Nil):
+			// Redis 不可用时直接读库，不影响业务
+			s.logger.Warn("read settings cache", "key", key, "error", err)
+		}
+	}
+
+	value, secret, found, err := s.repo.GetValue(tenant.WithID(ctx, tenantID), key)
+	if err != nil {
+		return "", false, err
+	}
+
+	if s.cache != nil {
+		cached := cachedMissing
+		switch {
+		case found && secret:
+			cached = cachedSecret + value
+		case found:
+			cached = cachedFound + value
+		}
+		if err := s.cache.Set(ctx, s.valueKey(tenantID, key), cached, s.cacheTTL).Err(); err != nil {
+			s.logger.Warn("write settings cache", "key", key, "error", err)
+		}
+	}
+	return s.reveal(s.remember(localKey, value, secret, found))
+}
+
+// reveal 解密 secret 参数；缓存中只保存密文，明文不落入 Redis 与进程内缓存
+func (s *Service) reveal(entry localEntry) (string, bool, error) {
+	if !entry.secret || entry.value == "" {
+		return entry.value, entry.found, nil
+	}
+	if s.keyring == nil {
+		return "", false, ErrSecretsUnavailable
+	}
+	plaintext, err := s.keyring.Open(entry.value)
+	if err != nil {
+		return "", false, err
+	}
+	return string(plaintext), true, nil
+}
+
+// GetString 返回参数值，参数不存在或读取失败时返回 fallback
+func (s *Service) GetString(ctx context.Context, key string, fallback string) string {
+	value, ok := s.lookupOrLog(ctx, key)
+	if !ok {
+		return fallback
+	}
+	return value
+}
+
+// GetInt 按整数解析参数值，无法解析时返回 fallback
+func (s *Service) GetInt(ctx context.Context, key string, fallback int) int {
+	value, ok := s.lookupOrLog(ctx, key)
+	if !ok || value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		s.logger.Warn("invalid integer setting", "key", key, "value", value)
+		return fallback
+	}
+	return parsed
+}
+
+// GetBool 按布尔值解析参数值，除 strconv.ParseBool 支持的写法外还接受 yes/no、on/off、Y/N
+func (s *Service) GetBool(ctx context.Context, key string, fallback bool) bool {
+	value, ok := s.lookupOrLog(ctx, key)
+	if !ok || value == "" {
+		return fallback
+	}
+	parsed, ok := parseBool(value)
+	if !ok {
+		s.logger.Warn("invalid boolean setting", "key", key, "value", value)
+		return fallback
+	}
+	return parsed
+}
+
+// GetDuration 按时长解析参数值，如 "30s"、"5m"；纯数字按秒计
+func (s *Service) GetDuration(ctx context.Context, key string, fallback time.Duration) time.Duration {
+	value, ok := s.lookupOrLog(ctx, key)
+	if !ok || value == "" {
+		return fallback
+	}
+	parsed, err := ParseDuration(value)
+	if err != nil {
+		s.logger.Warn("invalid duration setting", "key", key, "value", value)
+		return fallback
+	}
+	return parsed
+}
+
+// Invalidate 清除当前租户下指定参数的缓存，通知本进程订阅者并广播给其他副本
+func (s *Service) Invalidate(ctx context.Context, keys ...string) {
+	if s == nil {
+		return
+	}
+	tenantID := resolveTenant(ctx)
+	keys = normalizeKeys(keys)
+	if len(keys) == 0 {
+		return
+	}
+
+	s.forget(tenantID, keys)
+
+	if s.cache != nil {
+		valueKeys := make([]string, len(keys))
+		for i, key := range keys {
+			valueKeys[i] = s.valueKey(tenantID, key)
+		}
+		if err := s.cache.Del(ctx, valueKeys...).Err(); err != nil {
+			s.logger.Warn("delete settings cache", "keys", keys, "error", err)
+		}
+
+		payload, _ := json.Marshal(invalidation{Origin: s.instance, TenantID: tenantID, Keys: keys})
+		if err := s.cache.Publish(ctx, s.channel(), payload).Err(); err != nil {
+			s.logger.Warn("publish settings invalidation", "keys", keys, "error", err)
+		}
+	}
+
+	s.notify(tenantID, keys)
+}
+
+func (s *Service) handleMessage(payload string) {
+	var msg invalidation
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		s.logger.Warn("decode settings invalidation", "error", err)
+		return
+	}
+	// 本进程发出的消息已在 Invalidate 中处理
+	if msg.Origin == s.instance {
+		return
+	}
+	s.forget(msg.TenantID, msg.Keys)
+	s.notify(msg.TenantID, msg.Keys)
+}
+
+func (s *Service) notify(tenantID int64, keys []string) {
+	ctx := tenant.WithID(context.Background(), tenantID)
+	for _, key := range keys {
+		s.subMu.RLock()
+		subscribers := append([]Subscriber(nil), s.subscribers[key]...)
+		s.subMu.RUnlock()
+
+		for _, fn := range subscribers {
+			s.safeCall(ctx, fn, Change{TenantID: tenantID, Key: key})
+		}
+	}
+}
+
+func (s *Service) safeCall(ctx context.Context, fn Subscriber, change Change) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("settings subscriber panicked", "key", change.Key, "panic", r)
+		}
+	}()
+	fn(ctx, change)
+}
+
+func (s *Service) lookupOrLog(ctx context.Context, key string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	value, found, err := s.Lookup(ctx, key)
+	if err != nil {
+		s.logger.Warn("read setting", "key", key, "error", err)
+		return "", false
+	}
+	return strings.TrimSpace(value), found
+}
+
+func (s *Service) remember(localKey, value string, secret, found bool) localEntry {
+	entry := localEntry{value: value, secret: secret, found: found, expires: time.Now().Add(s.localTTL)}
+	s.mu.Lock()
+	s.local[localKey] = entry
+	s.mu.Unlock()
+	return entry
+}
+
+func (s *Service) forget(tenantID int64, keys []string) {
+	s.mu.Lock()
+	for _, key := range keys {
+		delete(s.local, s.localKey(tenantID, key))
+	}
+	s.mu.Unlock()
+}
+
+func (s *Service) localKey(tenantID int64, key string) string {
+	return strconv.FormatInt(tenantID, 10) + ":" + key
+}
+
+func (s *Service) valueKey(tenantID int64, key string) string {
+	return s.prefix + ":value:" + s.localKey(tenantID, key)
+}
+
+func (s *Service) channel() string {
+	return s.prefix + ":invalidate"
+}
+
+// resolveTenant 未绑定租户的调用（如登录前、后台任务）读取平台租户的参数
+func resolveTenant(ctx context.Context) int64 {
+	if id, ok := tenant.FromContext(ctx); ok {
+		return id
+	}
+	return tenant.DefaultID
+}
+
+func normalizeKeys(keys []string) []string {
+	seen := make(map[string]struct{}, len(keys))
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, key)
+	}
+	return result
+}
+
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "yes", "y", "on":
+		return true, true
+	case "no", "n", "off":
+		return false, true
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, false
+	}
+	return parsed, true
+}
+
+// ParseDuration 解析时长参数，纯数字按秒计
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return time.ParseDuration(value)
+}
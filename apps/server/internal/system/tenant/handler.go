@@ -0,0 +1,335 @@
+package tenant
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/etag"
+	"github.com/starter-kit-fe/admin/pkg/resp"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	if service == nil {
+		return nil
+	}
+	return &Handler{service: service}
+}
+
+type listTenantsQuery struct {
+	PageNum    int    `form:"pageNum"`
+	PageSize   int    `form:"pageSize"`
+	Status     string `form:"status"`
+	TenantName string `form:"tenantName"`
+	TenantCode string `form:"tenantCode"`
+}
+
+type createTenantRequest struct {
+	TenantCode    string  `json:"tenantCode" binding:"required"`
+	TenantName    string  `json:"tenantName" binding:"required"`
+	Status        string  `json:"status"`
+	Remark        *string `json:"remark"`
+	AdminUserName string  `json:"adminUserName"`
+	AdminPassword string  `json:"adminPassword"`
+}
+
+type updateTenantRequest struct {
+	TenantName *string `json:"tenantName"`
+	Status     *string `json:"status"`
+	Remark     *string `json:"remark"`
+}
+
+// List godoc
+// @Summary 获取租户列表
+// @Description 平台管理员按状态、名称或编码过滤租户
+// @Tags System/Tenant
+// @Security BearerAuth
+// @Produce json
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Param status query string false "租户状态"
+// @Param tenantName query string false "租户名称"
+// @Param tenantCode query string false "租户编码"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 403 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/tenants [get]
+func (h *Handler) List(ctx *gin.Context) {
+	if !h.ensurePlatform(ctx) {
+		return
+	}
+
+	var query listTenantsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	result, err := h.service.ListTenants(ctx.Request.Context(), QueryOptions{
+		PageNum:    query.PageNum,
+		PageSize:   query.PageSize,
+		Status:     query.Status,
+		TenantName: query.TenantName,
+		TenantCode: query.TenantCode,
+	})
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load tenants"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(result))
+}
+
+// Get godoc
+// @Summary 获取租户详情
+// @Tags System/Tenant
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "租户ID"
+// @Success 200 {object} resp.Response
+// @Header 200 {string} ETag "租户版本，修改或删除时通过 If-Match 回传"
+// @Failure 400 {object} resp.Response
+// @Failure 403 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/tenants/{id} [get]
+func (h *Handler) Get(ctx *gin.Context) {
+	if !h.ensurePlatform(ctx) {
+		return
+	}
+
+	id, err := parseTenantID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid tenant id"))
+		return
+	}
+
+	tenant, err := h.service.GetTenant(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.NotFound(ctx, resp.WithMessage("tenant not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load tenant"))
+		return
+	}
+
+	etag.Set(ctx, tenant.UpdatedAt)
+	resp.OK(ctx, resp.WithData(tenant))
+}
+
+// Create godoc
+// @Summary 新增租户
+// @Description 创建租户并初始化岗位、字典、参数、根部门与管理员账号，初始密码只返回一次
+// @Tags System/Tenant
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body createTenantRequest true "租户参数"
+// @Success 201 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 403 {object} resp.Response
+// @Failure 409 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/tenants [post]
+func (h *Handler) Create(ctx *gin.Context) {
+	if !h.ensurePlatform(ctx) {
+		return
+	}
+
+	var payload createTenantRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid tenant payload"))
+		return
+	}
+
+	result, err := h.service.CreateTenant(ctx.Request.Context(), CreateTenantInput{
+		TenantCode:    payload.TenantCode,
+		TenantName:    payload.TenantName,
+		Status:        payload.Status,
+		Remark:        payload.Remark,
+		AdminUserName: payload.AdminUserName,
+		AdminPassword: payload.AdminPassword,
+		Operator:      resolveOperator(ctx),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTenantCodeRequired),
+			errors.Is(err, ErrInvalidTenantCode),
+			errors.Is(err, ErrTenantNameRequired),
+			errors.Is(err, ErrInvalidTenantStatus),
+			errors.Is(err, ErrInvalidAdminCredentials):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		case errors.Is(err, ErrDuplicateTenantCode):
+			resp.Conflict(ctx, resp.WithMessage(err.Error()))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to create tenant"))
+		}
+		return
+	}
+
+	resp.Created(ctx, resp.WithData(result))
+}
+
+// Update godoc
+// @Summary 修改租户
+// @Description 更新租户名称、状态或备注，租户编码创建后不可修改
+// @Tags System/Tenant
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "租户ID"
+// @Param request body updateTenantRequest true "租户参数"
+// @Param If-Match header string true "获取租户时返回的 ETag"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 403 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/tenants/{id} [put]
+func (h *Handler) Update(ctx *gin.Context) {
+	if !h.ensurePlatform(ctx) {
+		return
+	}
+
+	id, err := parseTenantID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid tenant id"))
+		return
+	}
+
+	var payload updateTenantRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid tenant payload"))
+		return
+	}
+
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	tenant, err := h.service.UpdateTenant(ctx.Request.Context(), UpdateTenantInput{
+		ID:         id,
+		TenantName: payload.TenantName,
+		Status:     payload.Status,
+		Remark:     payload.Remark,
+		Operator:   resolveOperator(ctx),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			resp.NotFound(ctx, resp.WithMessage("tenant not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("tenant has been modified, reload and retry"))
+		case errors.Is(err, ErrTenantNameRequired),
+			errors.Is(err, ErrInvalidTenantStatus),
+			errors.Is(err, ErrDefaultTenantProtected):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to update tenant"))
+		}
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(tenant))
+}
+
+// Delete godoc
+// @Summary 删除租户
+// @Description 删除后租户成员无法登录，业务数据保留
+// @Tags System/Tenant
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "租户ID"
+// @Param If-Match header string true "获取租户时返回的 ETag"
+// @Success 204 {object} nil
+// @Failure 400 {object} resp.Response
+// @Failure 403 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/tenants/{id} [delete]
+func (h *Handler) Delete(ctx *gin.Context) {
+	if !h.ensurePlatform(ctx) {
+		return
+	}
+
+	id, err := parseTenantID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid tenant id"))
+		return
+	}
+
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	if err := h.service.DeleteTenant(ctx.Request.Context(), id, resolveOperator(ctx)); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			resp.NotFound(ctx, resp.WithMessage("tenant not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("tenant has been modified, reload and retry"))
+		case errors.Is(err, ErrDefaultTenantProtected):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to delete tenant"))
+		}
+		return
+	}
+
+	resp.NoContent(ctx)
+}
+
+// ensurePlatform 租户管理只对平台租户的用户开放，菜单权限之外再做一次归属校验
+func (h *Handler) ensurePlatform(ctx *gin.Context) bool {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("tenant service unavailable"))
+		return false
+	}
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		resp.Unauthorized(ctx, resp.WithMessage("invalid token"))
+		return false
+	}
+	if err := h.service.EnsurePlatform(ctx.Request.Context(), userID); err != nil {
+		if errors.Is(err, ErrPlatformOnly) {
+			resp.Forbidden(ctx, resp.WithMessage(err.Error()))
+		} else {
+			resp.InternalServerError(ctx, resp.WithMessage("failed to verify tenant access"))
+		}
+		return false
+	}
+	return true
+}
+
+func resolveOperator(ctx *gin.Context) string {
+	id, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func parseTenantID(param string) (int64, error) {
+	return strconv.ParseInt(strings.TrimSpace(param), 10, 64)
+}
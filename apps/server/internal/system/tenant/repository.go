@@ -0,0 +1,199 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/etag"
+	tenantpkg "github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+var (
+	ErrRepositoryUnavailable = errors.New("tenant repository is not initialized")
+	ErrInvalidTenantPayload  = errors.New("tenant payload is invalid")
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	if db == nil {
+		return nil
+	}
+	return &Repository{db: db}
+}
+
+type ListOptions struct {
+	PageNum    int
+	PageSize   int
+	Status     string
+	TenantName string
+	TenantCode string
+}
+
+func (r *Repository) ListTenants(ctx context.Context, opts ListOptions) ([]model.SysTenant, int64, error) {
+	if r == nil || r.db == nil {
+		return nil, 0, ErrRepositoryUnavailable
+	}
+
+	pageNum := opts.PageNum
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	pageSize := opts.PageSize
+	if pageSize < 0 {
+		pageSize = 0
+	}
+
+	base := r.db.WithContext(ctx).Model(&model.SysTenant{})
+	if status := strings.TrimSpace(opts.Status); status != "" && status != "all" {
+		base = base.Where("status = ?", status)
+	}
+	if name := strings.TrimSpace(opts.TenantName); name != "" {
+		base = base.Where("tenant_name ILIKE ?", "%"+name+"%")
+	}
+	if code := strings.TrimSpace(opts.TenantCode); code != "" {
+		base = base.Where("tenant_code ILIKE ?", "%"+code+"%")
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []model.SysTenant{}, 0, nil
+	}
+
+	dataQuery := base.Session(&gorm.Session{})
+	if pageSize > 0 {
+		dataQuery = dataQuery.Offset((pageNum - 1) * pageSize).Limit(pageSize)
+	}
+
+	var tenants []model.SysTenant
+	if err := dataQuery.Order("id ASC").Find(&tenants).Error; err != nil {
+		return nil, 0, err
+	}
+	return tenants, total, nil
+}
+
+func (r *Repository) GetTenant(ctx context.Context, id int64) (*model.SysTenant, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	if id <= 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var record model.SysTenant
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// CreateTenant 在同一事务中创建租户并写入其默认数据
+func (r *Repository) CreateTenant(ctx context.Context, record *model.SysTenant, seed func(tx *gorm.DB) error) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if record == nil {
+		return ErrInvalidTenantPayload
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		if seed == nil {
+			return nil
+		}
+		return seed(tx)
+	})
+}
+
+func (r *Repository) UpdateTenant(ctx context.Context, id int64, updates map[string]interface{}) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if id <= 0 {
+		return gorm.ErrRecordNotFound
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Model(&model.SysTenant{}).Where("id = ?", id)).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return etag.Missed(db, &model.SysTenant{}, id)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteTenant(ctx context.Context, id int64, operator string) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if id <= 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		marked := etag.Guard(tx.Model(&model.SysTenant{}).Where("id = ?", id)).Update("update_by", operator)
+		if marked.Error != nil {
+			return marked.Error
+		}
+		if marked.RowsAffected == 0 {
+			return etag.Missed(tx, &model.SysTenant{}, id)
+		}
+		result := tx.Where("id = ?", id).Delete(&model.SysTenant{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// ExistsByCode 检查租户编码是否已被占用，已删除的租户同样占用编码
+func (r *Repository) ExistsByCode(ctx context.Context, code string) (bool, error) {
+	if r == nil || r.db == nil {
+		return false, ErrRepositoryUnavailable
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&model.SysTenant{}).
+		Where("tenant_code = ?", strings.TrimSpace(code)).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetUserTenantID 返回用户所属租户，不受当前访问租户限制
+func (r *Repository) GetUserTenantID(ctx context.Context, userID uint) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, ErrRepositoryUnavailable
+	}
+
+	var user model.SysUser
+	if err := r.db.WithContext(tenantpkg.WithoutScope(ctx)).
+		Select("id", "tenant_id").
+		Where("id = ?", userID).
+		First(&user).Error; err != nil {
+		return 0, err
+	}
+	return user.TenantID, nil
+}
@@ -0,0 +1,333 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/db"
+	"github.com/starter-kit-fe/admin/internal/model"
+	tenantpkg "github.com/starter-kit-fe/admin/pkg/tenant"
+)
+
+var (
+	ErrServiceUnavailable      = errors.New("tenant service is not initialized")
+	ErrPlatformOnly            = errors.New("only platform administrators can manage tenants")
+	ErrTenantCodeRequired      = errors.New("tenant code is required")
+	ErrInvalidTenantCode       = errors.New("tenant code must be 2-64 lowercase letters, digits, '-' or '_'")
+	ErrTenantNameRequired      = errors.New("tenant name is required")
+	ErrInvalidTenantStatus     = errors.New("invalid tenant status")
+	ErrDuplicateTenantCode     = errors.New("duplicate tenant code")
+	ErrDefaultTenantProtected  = errors.New("default tenant cannot be disabled or deleted")
+	ErrInvalidAdminCredentials = errors.New("admin password must be at least 6 characters")
+)
+
+var tenantCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{1,63}$`)
+
+var validStatuses = map[string]struct{}{
+	"0": {},
+	"1": {},
+}
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	if repo == nil {
+		return nil
+	}
+	return &Service{repo: repo}
+}
+
+type QueryOptions struct {
+	PageNum    int
+	PageSize   int
+	Status     string
+	TenantName string
+	TenantCode string
+}
+
+type ListResult struct {
+	List     []Tenant `json:"list"`
+	Total    int64    `json:"total"`
+	PageNum  int      `json:"pageNum"`
+	PageSize int      `json:"pageSize"`
+}
+
+type Tenant struct {
+	TenantID   int64     `json:"tenantId"`
+	TenantCode string    `json:"tenantCode"`
+	TenantName string    `json:"tenantName"`
+	Status     string    `json:"status"`
+	Remark     *string   `json:"remark,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// CreateResult 新租户及其初始管理员账号，密码仅在创建时返回一次
+type CreateResult struct {
+	Tenant        *Tenant `json:"tenant"`
+	AdminUserName string  `json:"adminUserName"`
+	AdminPassword string  `json:"adminPassword"`
+}
+
+type CreateTenantInput struct {
+	TenantCode    string
+	TenantName    string
+	Status        string
+	Remark        *string
+	AdminUserName string
+	AdminPassword string
+	Operator      string
+}
+
+type UpdateTenantInput struct {
+	ID         int64
+	TenantName *string
+	Status     *string
+	Remark     *string
+	Operator   string
+}
+
+// EnsurePlatform 校验操作人属于平台租户；切换进其他租户后依然按其所属租户判断
+func (s *Service) EnsurePlatform(ctx context.Context, userID uint) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+	tenantID, err := s.repo.GetUserTenantID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPlatformOnly
+		}
+		return err
+	}
+	if tenantID != tenantpkg.DefaultID {
+		return ErrPlatformOnly
+	}
+	return nil
+}
+
+func (s *Service) ListTenants(ctx context.Context, opts QueryOptions) (*ListResult, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	pageNum := opts.PageNum
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	pageSize := opts.PageSize
+	if pageSize < 0 {
+		pageSize = 0
+	}
+
+	records, total, err := s.repo.ListTenants(ctx, ListOptions{
+		PageNum:    pageNum,
+		PageSize:   pageSize,
+		Status:     opts.Status,
+		TenantName: opts.TenantName,
+		TenantCode: opts.TenantCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Tenant, 0, len(records))
+	for i := range records {
+		items = append(items, *tenantFromModel(&records[i]))
+	}
+
+	return &ListResult{
+		List:     items,
+		Total:    total,
+		PageNum:  pageNum,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (s *Service) GetTenant(ctx context.Context, id int64) (*Tenant, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	record, err := s.repo.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return tenantFromModel(record), nil
+}
+
+// CreateTenant 创建租户并初始化其默认数据与管理员账号
+func (s *Service) CreateTenant(ctx context.Context, input CreateTenantInput) (*CreateResult, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	code := strings.ToLower(strings.TrimSpace(input.TenantCode))
+	if code == "" {
+		return nil, ErrTenantCodeRequired
+	}
+	if !tenantCodePattern.MatchString(code) {
+		return nil, ErrInvalidTenantCode
+	}
+
+	name := strings.TrimSpace(input.TenantName)
+	if name == "" {
+		return nil, ErrTenantNameRequired
+	}
+
+	status := normalizeStatus(input.Status)
+	if _, ok := validStatuses[status]; !ok {
+		return nil, ErrInvalidTenantStatus
+	}
+
+	if input.AdminPassword != "" && len(input.AdminPassword) < 6 {
+		return nil, ErrInvalidAdminCredentials
+	}
+
+	if exists, err := s.repo.ExistsByCode(ctx, code); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, ErrDuplicateTenantCode
+	}
+
+	adminUserName := strings.TrimSpace(input.AdminUserName)
+	if adminUserName == "" {
+		adminUserName = "admin"
+	}
+	operator := strings.TrimSpace(input.Operator)
+
+	record := &model.SysTenant{
+		TenantCode: code,
+		TenantName: name,
+		Status:     status,
+		Remark:     normalizeRemark(input.Remark),
+		CreateBy:   operator,
+		UpdateBy:   operator,
+	}
+
+	var password string
+	err := s.repo.CreateTenant(ctx, record, func(tx *gorm.DB) error {
+		var err error
+		password, err = db.SeedTenant(ctx, tx, db.TenantSeed{
+			TenantID:      int64(record.ID),
+			TenantName:    name,
+			AdminUserName: adminUserName,
+			AdminPassword: input.AdminPassword,
+			Operator:      operator,
+		}, nil)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrDuplicateTenantCode
+		}
+		return nil, err
+	}
+
+	return &CreateResult{
+		Tenant:        tenantFromModel(record),
+		AdminUserName: adminUserName,
+		AdminPassword: password,
+	}, nil
+}
+
+func (s *Service) UpdateTenant(ctx context.Context, input UpdateTenantInput) (*Tenant, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	if input.ID <= 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	updates := make(map[string]interface{})
+
+	if input.TenantName != nil {
+		name := strings.TrimSpace(*input.TenantName)
+		if name == "" {
+			return nil, ErrTenantNameRequired
+		}
+		updates["tenant_name"] = name
+	}
+
+	if input.Status != nil {
+		status := normalizeStatus(*input.Status)
+		if _, ok := validStatuses[status]; !ok {
+			return nil, ErrInvalidTenantStatus
+		}
+		if status != "0" && input.ID == tenantpkg.DefaultID {
+			return nil, ErrDefaultTenantProtected
+		}
+		updates["status"] = status
+	}
+
+	if input.Remark != nil {
+		if remark := normalizeRemark(input.Remark); remark == nil {
+			updates["remark"] = nil
+		} else {
+			updates["remark"] = *remark
+		}
+	}
+
+	updates["updated_at"] = time.Now()
+	if operator := strings.TrimSpace(input.Operator); operator != "" {
+		updates["update_by"] = operator
+	}
+
+	if err := s.repo.UpdateTenant(ctx, input.ID, updates); err != nil {
+		return nil, err
+	}
+	return s.GetTenant(ctx, input.ID)
+}
+
+// DeleteTenant 软删除租户，其成员随即无法登录或续签令牌，业务数据保留
+func (s *Service) DeleteTenant(ctx context.Context, id int64, operator string) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+	if id <= 0 {
+		return gorm.ErrRecordNotFound
+	}
+	if id == tenantpkg.DefaultID {
+		return ErrDefaultTenantProtected
+	}
+	return s.repo.DeleteTenant(ctx, id, strings.TrimSpace(operator))
+}
+
+func normalizeStatus(status string) string {
+	trimmed := strings.TrimSpace(status)
+	if trimmed == "" {
+		return "0"
+	}
+	return trimmed
+}
+
+func normalizeRemark(remark *string) *string {
+	if remark == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*remark)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func tenantFromModel(record *model.SysTenant) *Tenant {
+	if record == nil {
+		return nil
+	}
+	return &Tenant{
+		TenantID:   int64(record.ID),
+		TenantCode: record.TenantCode,
+		TenantName: record.TenantName,
+		Status:     record.Status,
+		Remark:     record.Remark,
+		CreatedAt:  record.CreatedAt,
+		UpdatedAt:  record.UpdatedAt,
+	}
+}
@@ -0,0 +1,96 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/starter-kit-fe/admin/internal/system/userattr"
+)
+
+// attributeDefinitions 返回启用的扩展属性定义，未配置属性服务时视为没有扩展属性
+func (s *Service) attributeDefinitions(ctx context.Context) ([]userattr.Definition, error) {
+	if s.attrs == nil {
+		return nil, nil
+	}
+	return s.attrs.EnabledDefinitions(ctx)
+}
+
+// resolveAttributes 校验并规范化提交的属性取值。新增时检查全部必填属性；
+// 修改时只处理提交的键，空值表示清除该属性。
+func resolveAttributes(defs []userattr.Definition, values map[string]string, creating bool) (map[string]string, error) {
+	byKey := make(map[string]userattr.Definition, len(defs))
+	for _, def := range defs {
+		byKey[def.AttrKey] = def
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]string, len(values))
+	for _, key := range keys {
+		def, ok := byKey[strings.TrimSpace(key)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", userattr.ErrUnknownAttribute, key)
+		}
+		value, err := def.Normalize(values[key])
+		if err != nil {
+			return nil, err
+		}
+		if value == "" && def.Required {
+			return nil, fmt.Errorf("%w: %s", userattr.ErrAttributeRequired, def.AttrKey)
+		}
+		result[def.AttrKey] = value
+	}
+
+	if creating {
+		for _, def := range defs {
+			if def.Required && result[def.AttrKey] == "" {
+				return nil, fmt.Errorf("%w: %s", userattr.ErrAttributeRequired, def.AttrKey)
+			}
+		}
+	}
+	return result, nil
+}
+
+// resolveAttributeFilters 将列表筛选条件规范化为存储格式，忽略空值
+func (s *Service) resolveAttributeFilters(ctx context.Context, values map[string]string) (map[string]string, error) {
+	filters := make(map[string]string, len(values))
+	for key, value := range values {
+		if strings.TrimSpace(value) != "" {
+			filters[key] = value
+		}
+	}
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	defs, err := s.attributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resolveAttributes(defs, filters, false)
+}
+
+// visibleAttributes 仅保留启用属性的取值，停用属性的历史数据不对外展示
+func visibleAttributes(values map[string]string, defs []userattr.Definition) map[string]string {
+	result := make(map[string]string, len(defs))
+	for _, def := range defs {
+		if value, ok := values[def.AttrKey]; ok {
+			result[def.AttrKey] = value
+		}
+	}
+	return result
+}
+
+// isAttributeError 判断是否为扩展属性取值校验失败
+func isAttributeError(err error) bool {
+	return errors.Is(err, userattr.ErrUnknownAttribute) ||
+		errors.Is(err, userattr.ErrAttributeRequired) ||
+		errors.Is(err, userattr.ErrInvalidAttributeVal)
+}
@@ -0,0 +1,205 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strconv"
+	"time"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/system/file"
+)
+
+// AvatarModule 头像文件在文件服务中的所属模块，该模块的文件可公开访问
+const AvatarModule = "avatar"
+
+const (
+	// MaxAvatarFileSize 限制头像原图大小为 5MB
+	MaxAvatarFileSize  = 5 << 20
+	minAvatarDimension = 64
+	maxAvatarDimension = 4096
+	avatarSize         = 256
+	avatarJPEGQuality  = 90
+)
+
+// avatarThumbnailSizes 在主图之外额外生成的缩略图边长
+var avatarThumbnailSizes = []int{96, 48}
+
+var (
+	ErrAvatarUnavailable  = errors.New("avatar storage is not configured")
+	ErrAvatarRequired     = errors.New("avatar image is required")
+	ErrAvatarFileTooLarge = errors.New("avatar image file is too large")
+	ErrAvatarFormat       = errors.New("avatar must be a PNG, JPEG or WebP image")
+	ErrAvatarTooSmall     = errors.New("avatar image dimensions are too small")
+	ErrAvatarTooLarge     = errors.New("avatar image dimensions are too large")
+)
+
+type UploadAvatarInput struct {
+	UserID   int64
+	Reader   io.Reader
+	Operator string
+}
+
+type AvatarVariant struct {
+	Size   int    `json:"size"`
+	FileID int64  `json:"fileId"`
+	URL    string `json:"url"`
+}
+
+type AvatarResult struct {
+	Avatar   string          `json:"avatar"`
+	Variants []AvatarVariant `json:"variants"`
+}
+
+// UploadAvatar 校验图片后居中裁剪为正方形，生成主图与缩略图写入文件服务，
+// 更新用户头像地址并清理之前的头像文件。
+func (s *Service) UploadAvatar(ctx context.Context, input UploadAvatarInput) (*AvatarResult, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+	if s.files == nil {
+		return nil, ErrAvatarUnavailable
+	}
+	if input.UserID <= 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if input.Reader == nil {
+		return nil, ErrAvatarRequired
+	}
+
+	if _, err := s.repo.GetUser(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
+	source, err := decodeAvatar(input.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	operator := sanitizeOperator(input.Operator)
+	if operator == "" {
+		operator = sanitizeOperator(strconv.FormatInt(input.UserID, 10))
+	}
+	refID := strconv.FormatInt(input.UserID, 10)
+
+	sizes := append([]int{avatarSize}, avatarThumbnailSizes...)
+	variants := make([]AvatarVariant, 0, len(sizes))
+	keep := make([]int64, 0, len(sizes))
+	cleanup := func() {
+		for _, id := range keep {
+			_ = s.files.Delete(context.WithoutCancel(ctx), id, operator)
+		}
+	}
+
+	for _, size := range sizes {
+		data, ext, err := encodeAvatar(resizeSquare(source, size))
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		item, err := s.files.Upload(ctx, file.UploadInput{
+			Filename: fmt.Sprintf("avatar_%d.%s", size, ext),
+			Reader:   bytes.NewReader(data),
+			Module:   AvatarModule,
+			RefID:    refID,
+			OwnerID:  input.UserID,
+			Operator: operator,
+			Public:   true,
+		})
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		keep = append(keep, item.FileID)
+		variants = append(variants, AvatarVariant{Size: size, FileID: item.FileID, URL: item.URL})
+	}
+
+	if err := s.repo.UpdateUser(ctx, input.UserID, map[string]interface{}{
+		"avatar":     variants[0].URL,
+		"update_by":  operator,
+		"updated_at": time.Now(),
+	}); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	// 旧头像清理失败不影响本次更新，残留文件可在文件管理中手动删除
+	_ = s.files.DeleteByRef(ctx, AvatarModule, refID, keep, operator)
+
+	return &AvatarResult{Avatar: variants[0].URL, Variants: variants}, nil
+}
+
+// decodeAvatar 先读取图片头部校验格式与尺寸，避免解码超大图片耗尽内存
+func decodeAvatar(reader io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, MaxAvatarFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrAvatarRequired
+	}
+	if len(data) > MaxAvatarFileSize {
+		return nil, ErrAvatarFileTooLarge
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrAvatarFormat
+	}
+	switch format {
+	case "png", "jpeg", "webp":
+	default:
+		return nil, ErrAvatarFormat
+	}
+	if config.Width < minAvatarDimension || config.Height < minAvatarDimension {
+		return nil, ErrAvatarTooSmall
+	}
+	if config.Width > maxAvatarDimension || config.Height > maxAvatarDimension {
+		return nil, ErrAvatarTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrAvatarFormat
+	}
+	return img, nil
+}
+
+// resizeSquare 居中裁剪出最大的正方形区域并缩放到指定边长，原图不足时不放大
+func resizeSquare(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+	))
+
+	size = min(size, side)
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
+
+// encodeAvatar 不透明图片输出 JPEG 以减小体积，带透明通道的输出 PNG
+func encodeAvatar(img *image.RGBA) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if img.Opaque() {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: avatarJPEGQuality}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "jpg", nil
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "png", nil
+}
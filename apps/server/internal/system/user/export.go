@@ -0,0 +1,172 @@
+package user
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+	"github.com/starter-kit-fe/admin/internal/system/i18n"
+	"github.com/starter-kit-fe/admin/internal/system/userattr"
+)
+
+const exportBatchSize = 500
+
+var exportHeader = []string{
+	"用户编号", "用户名称", "用户昵称", "部门", "邮箱", "手机号码", "性别", "状态",
+	"角色", "角色标识", "岗位", "岗位编码", "最后登录IP", "最后登录时间", "创建时间", "备注",
+}
+
+// exportFallbackLabels 字典不可用或缺少取值时使用的性别、状态标签
+var exportFallbackLabels = map[string]map[string]string{
+	dict.TypeUserSex:       {"0": "男", "1": "女", "2": "未知"},
+	dict.TypeNormalDisable: {"0": "正常", "1": "停用"},
+}
+
+// ExportUsers 按列表筛选条件分批导出用户，先输出表头，再逐行回调。
+// 启用的扩展属性按定义顺序追加在固定列之后；性别与状态取字典标签，localizer 非空时按其语言翻译。
+func (s *Service) ExportUsers(ctx context.Context, opts ListOptions, localizer *i18n.Localizer, fn func([]string) error) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+
+	status := strings.TrimSpace(opts.Status)
+	if status == "all" {
+		status = ""
+	}
+
+	attrFilters, err := s.resolveAttributeFilters(ctx, opts.Attributes)
+	if err != nil {
+		return err
+	}
+	defs, err := s.attributeDefinitions(ctx)
+	if err != nil {
+		return err
+	}
+
+	labels := s.exportLabels(ctx, localizer)
+
+	header := append([]string(nil), exportHeader...)
+	for _, def := range defs {
+		header = append(header, def.AttrLabel)
+	}
+
+	headerWritten := false
+	err = s.repo.ExportUsers(ctx, ListUsersOptions{
+		UserName:   opts.UserName,
+		Status:     status,
+		Attributes: attrFilters,
+	}, exportBatchSize, func(records []model.SysUser) error {
+		users, err := s.composeUsers(ctx, records)
+		if err != nil {
+			return err
+		}
+		if !headerWritten {
+			headerWritten = true
+			if err := fn(header); err != nil {
+				return err
+			}
+		}
+		for i := range users {
+			if err := fn(exportUserRow(&users[i], defs, labels)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !headerWritten {
+		return fn(header)
+	}
+	return nil
+}
+
+// exportLabels 返回各字典类型取值到显示标签的映射：与字典选项接口一致取启用数据的标签并翻译，
+// 字典读取失败或缺少某个取值时退回内置标签
+func (s *Service) exportLabels(ctx context.Context, localizer *i18n.Localizer) map[string]map[string]string {
+	dictTypes := make([]string, 0, len(exportFallbackLabels))
+	for dictType := range exportFallbackLabels {
+		dictTypes = append(dictTypes, dictType)
+	}
+	// 读取失败时结果为空，全部使用内置标签
+	options, _ := s.dicts.LookupOptions(ctx, dictTypes)
+
+	labels := make(map[string]map[string]string, len(exportFallbackLabels))
+	for dictType, fallback := range exportFallbackLabels {
+		byValue := make(map[string]string, len(fallback))
+		for value, label := range fallback {
+			byValue[value] = label
+		}
+		for _, option := range options[dictType] {
+			byValue[option.DictValue] = option.DictLabel
+		}
+		for value, label := range byValue {
+			byValue[value] = localizer.Text(dictType, value, label)
+		}
+		labels[dictType] = byValue
+	}
+	return labels
+}
+
+func exportUserRow(user *User, defs []userattr.Definition, labels map[string]map[string]string) []string {
+	roleNames := make([]string, 0, len(user.Roles))
+	roleKeys := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roleNames = append(roleNames, role.RoleName)
+		roleKeys = append(roleKeys, role.RoleKey)
+	}
+	postNames := make([]string, 0, len(user.Posts))
+	postCodes := make([]string, 0, len(user.Posts))
+	for _, post := range user.Posts {
+		postNames = append(postNames, post.PostName)
+		postCodes = append(postCodes, post.PostCode)
+	}
+
+	row := []string{
+		strconv.FormatInt(user.UserID, 10),
+		user.UserName,
+		user.NickName,
+		stringValue(user.DeptName),
+		user.Email,
+		user.Phonenumber,
+		labelOf(labels[dict.TypeUserSex], user.Sex),
+		labelOf(labels[dict.TypeNormalDisable], user.Status),
+		strings.Join(roleNames, ","),
+		strings.Join(roleKeys, ","),
+		strings.Join(postNames, ","),
+		strings.Join(postCodes, ","),
+		user.LoginIP,
+		formatExportTime(user.LoginDate),
+		formatExportTime(user.CreatedAt),
+		stringValue(user.Remark),
+	}
+	for _, def := range defs {
+		row = append(row, def.Display(user.Attributes[def.AttrKey]))
+	}
+	return row
+}
+
+func labelOf(labels map[string]string, value string) string {
+	if label, ok := labels[value]; ok {
+		return label
+	}
+	return value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func formatExportTime(value *time.Time) string {
+	if value == nil || value.IsZero() {
+		return ""
+	}
+	return value.Format(time.DateTime)
+}
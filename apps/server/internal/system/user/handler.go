@@ -2,27 +2,40 @@ package user
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/internal/system/file"
+	"github.com/starter-kit-fe/admin/internal/system/i18n"
 	"github.com/starter-kit-fe/admin/internal/system/online"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 	"github.com/starter-kit-fe/admin/pkg/resp"
+	"github.com/starter-kit-fe/admin/pkg/spreadsheet"
 )
 
+// maxImportFileSize 限制导入文件大小为 10MB
+const maxImportFileSize = 10 << 20
+
 type Handler struct {
-	service *Service
-	online  *online.Service
+	service      *Service
+	online       *online.Service
+	translations *i18n.Service
 }
 
-func NewHandler(service *Service, onlineSvc *online.Service) *Handler {
+// NewHandler 创建用户处理器；translations 可选，用于按请求语言翻译导出的字典标签
+func NewHandler(service *Service, onlineSvc *online.Service, translations *i18n.Service) *Handler {
 	if service == nil {
 		return nil
 	}
-	return &Handler{service: service, online: onlineSvc}
+	return &Handler{service: service, online: onlineSvc, translations: translations}
 }
 
 type listUsersQuery struct {
@@ -32,6 +45,12 @@ type listUsersQuery struct {
 	Status   string `form:"status"`
 }
 
+type exportUsersQuery struct {
+	UserName string `form:"userName"`
+	Status   string `form:"status"`
+	Format   string `form:"format"`
+}
+
 type createUserRequest struct {
 	UserName    string  `json:"userName" binding:"required"`
 	NickName    string  `json:"nickName" binding:"required"`
@@ -44,6 +63,8 @@ type createUserRequest struct {
 	Remark      *string `json:"remark"`
 	RoleIDs     []int64 `json:"roleIds"`
 	PostIDs     []int64 `json:"postIds"`
+	// Attributes 扩展属性取值，键为属性键
+	Attributes map[string]string `json:"attributes"`
 }
 
 type updateUserRequest struct {
@@ -57,6 +78,8 @@ type updateUserRequest struct {
 	Remark      *string  `json:"remark"`
 	RoleIDs     *[]int64 `json:"roleIds"`
 	PostIDs     *[]int64 `json:"postIds"`
+	// Attributes 只修改提交的扩展属性，空字符串表示清除
+	Attributes map[string]string `json:"attributes"`
 }
 
 type listOptionsQuery struct {
@@ -74,6 +97,8 @@ type profileUpdateRequest struct {
 	Phonenumber string  `json:"phonenumber"`
 	Sex         string  `json:"sex"`
 	Remark      *string `json:"remark"`
+	// Locale 界面语言偏好，空字符串表示跟随浏览器
+	Locale *string `json:"locale"`
 }
 
 type changePasswordRequest struct {
@@ -91,6 +116,7 @@ type changePasswordRequest struct {
 // @Param pageSize query int false "每页数量"
 // @Param userName query string false "用户名"
 // @Param status query string false "用户状态"
+// @Param attrs query string false "扩展属性筛选，形如 attrs[属性键]=取值"
 // @Success 200 {object} resp.Response
 // @Failure 400 {object} resp.Response
 // @Failure 500 {object} resp.Response
@@ -109,12 +135,17 @@ func (h *Handler) List(ctx *gin.Context) {
 	}
 
 	result, err := h.service.ListUsers(ctx.Request.Context(), ListOptions{
-		PageNum:  query.PageNum,
-		PageSize: query.PageSize,
-		UserName: query.UserName,
-		Status:   query.Status,
+		PageNum:    query.PageNum,
+		PageSize:   query.PageSize,
+		UserName:   query.UserName,
+		Status:     query.Status,
+		Attributes: ctx.QueryMap("attrs"),
 	})
 	if err != nil {
+		if isAttributeError(err) {
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+			return
+		}
 		resp.InternalServerError(ctx, resp.WithMessage("failed to load users"))
 		return
 	}
@@ -122,6 +153,62 @@ func (h *Handler) List(ctx *gin.Context) {
 	resp.OK(ctx, resp.WithData(result))
 }
 
+// Export godoc
+// @Summary 导出用户
+// @Description 按列表筛选条件导出用户及其部门、角色、岗位名称
+// @Tags System/User
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param userName query string false "用户名"
+// @Param status query string false "用户状态"
+// @Param attrs query string false "扩展属性筛选，形如 attrs[属性键]=取值"
+// @Param format query string false "导出格式：csv 或 xlsx，默认 csv"
+// @Param Accept-Language header string false "界面语言，用户设置了语言偏好时以偏好为准；性别、状态按该语言的译文导出"
+// @Success 200 {file} file
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/users/export [get]
+func (h *Handler) Export(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("user service unavailable"))
+		return
+	}
+
+	var query exportUsersQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	format, err := spreadsheet.ParseFormat(query.Format)
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("unsupported export format"))
+		return
+	}
+
+	out := spreadsheet.NewAttachment(ctx.Writer, format, "users_"+time.Now().Format("20060102150405"), "users")
+	err = h.service.ExportUsers(ctx.Request.Context(), ListOptions{
+		UserName:   query.UserName,
+		Status:     query.Status,
+		Attributes: ctx.QueryMap("attrs"),
+	}, h.localizer(ctx), out.WriteRow)
+	if err == nil {
+		err = out.Close()
+	}
+	if err != nil {
+		if out.Started() {
+			_ = ctx.Error(err)
+			return
+		}
+		if isAttributeError(err) {
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to export users"))
+	}
+}
+
 // ListDepartmentOptions godoc
 // @Summary 部门选项
 // @Description 查询可用的部门下拉数据
@@ -232,6 +319,7 @@ func (h *Handler) ListPostOptions(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "用户ID"
 // @Success 200 {object} resp.Response
+// @Header 200 {string} ETag "用户版本，修改或删除时通过 If-Match 回传"
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 500 {object} resp.Response
@@ -259,6 +347,7 @@ func (h *Handler) Get(ctx *gin.Context) {
 		return
 	}
 
+	etag.Set(ctx, *user.UpdatedAt)
 	resp.OK(ctx, resp.WithData(user))
 }
 
@@ -302,11 +391,16 @@ func (h *Handler) Create(ctx *gin.Context) {
 		Operator:    operator,
 		RoleIDs:     payload.RoleIDs,
 		PostIDs:     payload.PostIDs,
+		Attributes:  payload.Attributes,
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrDuplicateUsername):
 			resp.Conflict(ctx, resp.WithMessage("username already exists"))
+		case errors.Is(err, ErrUsernameRequired):
+			resp.BadRequest(ctx, resp.WithMessage("username is required"))
+		case errors.Is(err, ErrNicknameRequired):
+			resp.BadRequest(ctx, resp.WithMessage("nickname is required"))
 		case errors.Is(err, ErrPasswordRequired):
 			resp.BadRequest(ctx, resp.WithMessage("password is required"))
 		case errors.Is(err, ErrInvalidStatus):
@@ -315,6 +409,8 @@ func (h *Handler) Create(ctx *gin.Context) {
 			resp.BadRequest(ctx, resp.WithMessage("invalid role selection"))
 		case errors.Is(err, ErrInvalidPostSelection):
 			resp.BadRequest(ctx, resp.WithMessage("invalid post selection"))
+		case isAttributeError(err):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
 		default:
 			resp.InternalServerError(ctx, resp.WithMessage("failed to create user"))
 		}
@@ -324,6 +420,158 @@ func (h *Handler) Create(ctx *gin.Context) {
 	resp.Created(ctx, resp.WithData(user))
 }
 
+// Import godoc
+// @Summary 批量导入用户
+// @Description 上传 CSV/XLSX 文件批量创建用户，默认仅校验并返回逐行报告；atomic 模式任一行失败则整体不写入，batch 模式跳过失败行按批次提交
+// @Tags System/User
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "导入文件（.csv 或 .xlsx）"
+// @Param dryRun formData bool false "仅校验不写入，默认 true"
+// @Param mode formData string false "导入模式：atomic 或 batch，默认 atomic"
+// @Param batchSize formData int false "batch 模式下每批提交的行数"
+// @Param defaultPassword formData string false "未填写密码时使用的初始密码"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 422 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/users/import [post]
+func (h *Handler) Import(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("user service unavailable"))
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("import file is required"))
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		resp.BadRequest(ctx, resp.WithMessage("import file is too large"))
+		return
+	}
+
+	format, err := spreadsheet.FormatFromFilename(fileHeader.Filename)
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("unsupported import file format"))
+		return
+	}
+
+	dryRun := true
+	if raw := strings.TrimSpace(ctx.PostForm("dryRun")); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			resp.BadRequest(ctx, resp.WithMessage("invalid dryRun value"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	mode, err := ParseImportMode(ctx.PostForm("mode"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid import mode"))
+		return
+	}
+
+	batchSize := 0
+	if raw := strings.TrimSpace(ctx.PostForm("batchSize")); raw != "" {
+		batchSize, err = strconv.Atoi(raw)
+		if err != nil || batchSize <= 0 {
+			resp.BadRequest(ctx, resp.WithMessage("invalid batchSize value"))
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("failed to read import file"))
+		return
+	}
+	defer file.Close()
+
+	// 首行为表头，其后最多 MaxImportRows 行数据
+	rows, err := spreadsheet.ReadAll(format, io.LimitReader(file, maxImportFileSize), MaxImportRows+1)
+	if err != nil {
+		if errors.Is(err, spreadsheet.ErrTooManyRows) {
+			resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("import file exceeds %d rows", MaxImportRows)))
+			return
+		}
+		resp.BadRequest(ctx, resp.WithMessage("failed to parse import file"))
+		return
+	}
+
+	result, err := h.service.ImportUsers(ctx.Request.Context(), ImportUsersInput{
+		Rows:            rows,
+		DryRun:          dryRun,
+		Mode:            mode,
+		BatchSize:       batchSize,
+		DefaultPassword: ctx.PostForm("defaultPassword"),
+		Operator:        resolveOperator(ctx),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrImportEmpty):
+			resp.BadRequest(ctx, resp.WithMessage("import file has no data rows"))
+		case errors.Is(err, ErrImportTooManyRows):
+			resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("import file exceeds %d rows", MaxImportRows)))
+		case errors.Is(err, ErrImportMissingColumns):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		case errors.Is(err, ErrImportValidationFails):
+			resp.UnprocessableEntity(ctx, resp.WithMessage("import rows failed validation"), resp.WithData(result))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to import users"))
+		}
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(result))
+}
+
+// ImportTemplate godoc
+// @Summary 下载用户导入模板
+// @Description 下载包含表头与示例行的用户导入模板，启用的扩展属性以属性名称作为列标题
+// @Tags System/User
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param format query string false "模板格式：csv 或 xlsx，默认 csv"
+// @Success 200 {file} file
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/users/import/template [get]
+func (h *Handler) ImportTemplate(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("user service unavailable"))
+		return
+	}
+
+	format, err := spreadsheet.ParseFormat(ctx.Query("format"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("unsupported template format"))
+		return
+	}
+
+	rows, err := h.service.ImportTemplateRows(ctx.Request.Context())
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to build import template"))
+		return
+	}
+
+	out := spreadsheet.NewAttachment(ctx.Writer, format, "user_import_template", "users")
+	for _, row := range rows {
+		if err := out.WriteRow(row); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	if err := out.Close(); err != nil {
+		_ = ctx.Error(err)
+	}
+}
+
 // Update godoc
 // @Summary 修改用户
 // @Description 根据用户ID更新信息
@@ -333,10 +581,13 @@ func (h *Handler) Create(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "用户ID"
 // @Param request body updateUserRequest true "用户参数"
+// @Param If-Match header string true "获取用户时返回的 ETag"
 // @Success 200 {object} resp.Response
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
 // @Failure 409 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/users/{id} [put]
@@ -358,6 +609,11 @@ func (h *Handler) Update(ctx *gin.Context) {
 		return
 	}
 
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
 	operator := resolveOperator(ctx)
 	user, err := h.service.UpdateUser(ctx.Request.Context(), UpdateUserInput{
 		ID:          id,
@@ -372,11 +628,14 @@ func (h *Handler) Update(ctx *gin.Context) {
 		Operator:    operator,
 		RoleIDs:     payload.RoleIDs,
 		PostIDs:     payload.PostIDs,
+		Attributes:  payload.Attributes,
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("user not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("user has been modified, reload and retry"))
 		case errors.Is(err, ErrDuplicateUsername):
 			resp.Conflict(ctx, resp.WithMessage("username already exists"))
 		case errors.Is(err, ErrInvalidStatus):
@@ -385,6 +644,8 @@ func (h *Handler) Update(ctx *gin.Context) {
 			resp.BadRequest(ctx, resp.WithMessage("invalid role selection"))
 		case errors.Is(err, ErrInvalidPostSelection):
 			resp.BadRequest(ctx, resp.WithMessage("invalid post selection"))
+		case isAttributeError(err):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
 		default:
 			resp.InternalServerError(ctx, resp.WithMessage("failed to update user"))
 		}
@@ -401,9 +662,12 @@ func (h *Handler) Update(ctx *gin.Context) {
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "用户ID"
+// @Param If-Match header string true "获取用户时返回的 ETag"
 // @Success 204 {object} nil
 // @Failure 400 {object} resp.Response
 // @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
 // @Failure 500 {object} resp.Response
 // @Failure 503 {object} resp.Response
 // @Router /v1/system/users/{id} [delete]
@@ -419,6 +683,11 @@ func (h *Handler) Delete(ctx *gin.Context) {
 		return
 	}
 
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
 	operator := resolveOperator(ctx)
 	if err := h.service.DeleteUser(ctx.Request.Context(), DeleteUserInput{
 		ID:       id,
@@ -428,6 +697,10 @@ func (h *Handler) Delete(ctx *gin.Context) {
 			resp.NotFound(ctx, resp.WithMessage("user not found"))
 			return
 		}
+		if errors.Is(err, etag.ErrPreconditionFailed) {
+			resp.PreconditionFailed(ctx, resp.WithMessage("user has been modified, reload and retry"))
+			return
+		}
 		resp.InternalServerError(ctx, resp.WithMessage("failed to delete user"))
 		return
 	}
@@ -527,7 +800,7 @@ func (h *Handler) GetProfile(ctx *gin.Context) {
 
 // UpdateProfile godoc
 // @Summary 更新个人资料
-// @Description 修改当前登录用户的昵称、邮箱、手机号等信息
+// @Description 修改当前登录用户的昵称、邮箱、手机号、界面语言偏好等信息
 // @Tags System/Profile
 // @Security BearerAuth
 // @Accept json
@@ -563,11 +836,14 @@ func (h *Handler) UpdateProfile(ctx *gin.Context) {
 		Phonenumber: payload.Phonenumber,
 		Sex:         payload.Sex,
 		Remark:      payload.Remark,
+		Locale:      payload.Locale,
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			resp.NotFound(ctx, resp.WithMessage("user not found"))
+		case errors.Is(err, i18n.ErrInvalidLocale):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
 		default:
 			resp.InternalServerError(ctx, resp.WithMessage("failed to update profile"))
 		}
@@ -636,6 +912,117 @@ func (h *Handler) ChangePassword(ctx *gin.Context) {
 	resp.OK(ctx, resp.WithMessage("password updated"))
 }
 
+// UploadAvatar godoc
+// @Summary 上传个人头像
+// @Description 上传 PNG、JPEG 或 WebP 图片，居中裁剪为正方形并生成缩略图后更新当前用户头像
+// @Tags System/Profile
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "头像图片"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 401 {object} resp.Response
+// @Failure 415 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/profile/avatar [post]
+func (h *Handler) UploadAvatar(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("user service unavailable"))
+		return
+	}
+
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		resp.Unauthorized(ctx, resp.WithMessage("invalid token"))
+		return
+	}
+
+	h.uploadAvatar(ctx, int64(userID))
+}
+
+// UploadUserAvatar godoc
+// @Summary 上传用户头像
+// @Description 管理员为指定用户上传头像，处理规则与个人头像一致
+// @Tags System/User
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param file formData file true "头像图片"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 415 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/users/{id}/avatar [post]
+func (h *Handler) UploadUserAvatar(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("user service unavailable"))
+		return
+	}
+
+	id, err := parseUserID(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		resp.BadRequest(ctx, resp.WithMessage("invalid user id"))
+		return
+	}
+
+	h.uploadAvatar(ctx, id)
+}
+
+func (h *Handler) uploadAvatar(ctx *gin.Context, userID int64) {
+	// 预留 multipart 边界的空间
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, MaxAvatarFileSize+1<<20)
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("avatar exceeds %d bytes", MaxAvatarFileSize)))
+			return
+		}
+		resp.BadRequest(ctx, resp.WithMessage("avatar file is required"))
+		return
+	}
+
+	src, err := fileHeader.Open()
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("failed to read avatar file"))
+		return
+	}
+	defer src.Close()
+
+	result, err := h.service.UploadAvatar(ctx.Request.Context(), UploadAvatarInput{
+		UserID:   userID,
+		Reader:   src,
+		Operator: resolveOperator(ctx),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			resp.NotFound(ctx, resp.WithMessage("user not found"))
+		case errors.Is(err, ErrAvatarRequired):
+			resp.BadRequest(ctx, resp.WithMessage("avatar file is required"))
+		case errors.Is(err, ErrAvatarFileTooLarge):
+			resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("avatar exceeds %d bytes", MaxAvatarFileSize)))
+		case errors.Is(err, ErrAvatarFormat), errors.Is(err, file.ErrFileTypeNotAllowed):
+			resp.Error(ctx, http.StatusUnsupportedMediaType, "avatar must be a PNG, JPEG or WebP image")
+		case errors.Is(err, ErrAvatarTooSmall), errors.Is(err, ErrAvatarTooLarge):
+			resp.BadRequest(ctx, resp.WithMessage(fmt.Sprintf("avatar dimensions must be between %dpx and %dpx", minAvatarDimension, maxAvatarDimension)))
+		case errors.Is(err, ErrAvatarUnavailable):
+			resp.ServiceUnavailable(ctx, resp.WithMessage("avatar storage unavailable"))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to update avatar"))
+		}
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(result))
+}
+
 // ListSelfSessions godoc
 // @Summary 获取当前用户的登录会话
 // @Description 查看当前登录用户的在线会话并支持自助管理
@@ -753,3 +1140,11 @@ func resolveOperator(ctx *gin.Context) string {
 	}
 	return strconv.FormatUint(uint64(id), 10)
 }
+
+// localizer 返回请求语言下的字典译文查询器；未启用多语言时返回 nil，导出原始标签
+func (h *Handler) localizer(ctx *gin.Context) *i18n.Localizer {
+	if h.translations == nil {
+		return nil
+	}
+	return h.translations.Localizer(ctx.Request.Context(), i18n.ResourceDict, h.translations.RequestLocale(ctx))
+}
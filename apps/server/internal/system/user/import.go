@@ -0,0 +1,679 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/userattr"
+)
+
+var (
+	ErrImportEmpty           = errors.New("import file has no data rows")
+	ErrImportTooManyRows     = errors.New("import file has too many rows")
+	ErrImportMissingColumns  = errors.New("import file is missing required columns")
+	ErrInvalidImportMode     = errors.New("invalid import mode")
+	ErrImportValidationFails = errors.New("import rows failed validation")
+)
+
+const (
+	MaxImportRows          = 5000
+	defaultImportBatchSize = 100
+	maxImportBatchSize     = 1000
+
+	deptPathSeparator = "/"
+
+	// attrColumnPrefix 扩展属性列的内部键前缀，避免与固定列冲突
+	attrColumnPrefix = "attr:"
+)
+
+type ImportMode string
+
+const (
+	// ImportModeAtomic 全部行校验通过后在同一事务内写入，任一失败则整体回滚
+	ImportModeAtomic ImportMode = "atomic"
+	// ImportModeBatch 跳过校验失败的行，其余按批次分别提交
+	ImportModeBatch ImportMode = "batch"
+)
+
+const (
+	ImportRowValid   = "valid"
+	ImportRowInvalid = "invalid"
+	ImportRowCreated = "created"
+	ImportRowFailed  = "failed"
+	ImportRowSkipped = "skipped"
+)
+
+type importColumn struct {
+	key      string
+	title    string
+	required bool
+	example  string
+	aliases  []string
+}
+
+// importColumns 定义导入模板列，表头同时接受中文标题与英文字段名
+var importColumns = []importColumn{
+	{key: "userName", title: "用户名称", required: true, example: "zhangsan", aliases: []string{"username", "user_name", "用户名", "登录账号"}},
+	{key: "nickName", title: "用户昵称", required: true, example: "张三", aliases: []string{"nickname", "nick_name", "昵称"}},
+	{key: "dept", title: "部门", example: "总部/研发部门", aliases: []string{"deptname", "dept_name", "deptpath", "部门名称", "部门路径"}},
+	{key: "email", title: "邮箱", example: "zhangsan@example.com", aliases: []string{"mail", "用户邮箱"}},
+	{key: "phonenumber", title: "手机号码", example: "13800000000", aliases: []string{"phone", "mobile", "手机号", "手机"}},
+	{key: "sex", title: "性别", example: "男", aliases: []string{"gender", "用户性别"}},
+	{key: "status", title: "状态", example: "正常", aliases: []string{"帐号状态", "账号状态"}},
+	{key: "password", title: "初始密码", example: "", aliases: []string{"密码"}},
+	{key: "roles", title: "角色标识", example: "common", aliases: []string{"rolekeys", "role_keys", "role", "角色"}},
+	{key: "posts", title: "岗位编码", example: "user", aliases: []string{"postcodes", "post_codes", "post", "岗位"}},
+	{key: "remark", title: "备注", example: "", aliases: []string{"memo"}},
+}
+
+var (
+	importSexValues = map[string]string{
+		"0": "0", "男": "0", "male": "0", "m": "0",
+		"1": "1", "女": "1", "female": "1", "f": "1",
+		"2": "2", "未知": "2", "unknown": "2",
+	}
+	importStatusValues = map[string]string{
+		"0": "0", "正常": "0", "启用": "0", "enabled": "0", "normal": "0",
+		"1": "1", "停用": "1", "禁用": "1", "disabled": "1",
+	}
+)
+
+type ImportUsersInput struct {
+	Rows            [][]string
+	DryRun          bool
+	Mode            ImportMode
+	BatchSize       int
+	DefaultPassword string
+	Operator        string
+}
+
+type ImportRowResult struct {
+	Row      int      `json:"row"`
+	UserName string   `json:"userName"`
+	Status   string   `json:"status"`
+	UserID   int64    `json:"userId,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+type ImportResult struct {
+	DryRun  bool              `json:"dryRun"`
+	Mode    ImportMode        `json:"mode"`
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Invalid int               `json:"invalid"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// ImportTemplateRows 返回导入模板的表头与示例行，包含启用的扩展属性列
+func (s *Service) ImportTemplateRows(ctx context.Context) ([][]string, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	defs, err := s.attributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := buildImportColumns(defs)
+	header := make([]string, len(columns))
+	example := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.title
+		if column.required {
+			header[i] += "*"
+		}
+		example[i] = column.example
+	}
+	return [][]string{header, example}, nil
+}
+
+// buildImportColumns 在固定列之后追加扩展属性列，表头可使用属性名称或属性键
+func buildImportColumns(defs []userattr.Definition) []importColumn {
+	columns := append([]importColumn(nil), importColumns...)
+	for _, def := range defs {
+		example := ""
+		if def.AttrType == userattr.TypeSelect && len(def.Options) > 0 {
+			example = def.Options[0].Label
+		}
+		columns = append(columns, importColumn{
+			key:      attrColumnPrefix + def.AttrKey,
+			title:    def.AttrLabel,
+			required: def.Required,
+			example:  example,
+			aliases:  []string{def.AttrKey},
+		})
+	}
+	return columns
+}
+
+func ParseImportMode(value string) (ImportMode, error) {
+	switch ImportMode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", ImportModeAtomic:
+		return ImportModeAtomic, nil
+	case ImportModeBatch:
+		return ImportModeBatch, nil
+	default:
+		return "", ErrInvalidImportMode
+	}
+}
+
+type importLookup struct {
+	deptsByPath map[string]int64
+	deptsByName map[string][]int64
+	rolesByKey  map[string]int64
+	postsByCode map[string]int64
+	attributes  []userattr.Definition
+}
+
+type importCandidate struct {
+	index    int
+	prepared *preparedUser
+}
+
+func (s *Service) ImportUsers(ctx context.Context, input ImportUsersInput) (*ImportResult, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	mode, err := ParseImportMode(string(input.Mode))
+	if err != nil {
+		return nil, err
+	}
+
+	lookup, err := s.loadImportLookup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, dataRows, err := mapImportHeader(input.Rows, buildImportColumns(lookup.attributes))
+	if err != nil {
+		return nil, err
+	}
+	if len(dataRows) == 0 {
+		return nil, ErrImportEmpty
+	}
+	if len(dataRows) > MaxImportRows {
+		return nil, ErrImportTooManyRows
+	}
+
+	result := &ImportResult{
+		DryRun: input.DryRun,
+		Mode:   mode,
+		Rows:   make([]ImportRowResult, 0, len(dataRows)),
+	}
+
+	candidates := make([]importCandidate, 0, len(dataRows))
+	seenUsernames := make(map[string]int, len(dataRows))
+
+	for _, row := range dataRows {
+		values := row.values(columns)
+		if isBlankRow(values) {
+			continue
+		}
+
+		rowResult := ImportRowResult{
+			Row:      row.number,
+			UserName: strings.TrimSpace(values["userName"]),
+		}
+
+		createInput, rowErrors := buildImportInput(values, lookup, input.DefaultPassword, input.Operator)
+		if previous, ok := seenUsernames[rowResult.UserName]; ok && rowResult.UserName != "" {
+			rowErrors = append(rowErrors, fmt.Sprintf("duplicate username in file (row %d)", previous))
+		} else if rowResult.UserName != "" {
+			seenUsernames[rowResult.UserName] = row.number
+		}
+
+		if len(rowErrors) == 0 {
+			prepared, err := s.prepareCreateUser(ctx, createInput, lookup.attributes)
+			if err != nil {
+				if !isImportValidationError(err) {
+					return nil, err
+				}
+				rowErrors = append(rowErrors, err.Error())
+			} else {
+				candidates = append(candidates, importCandidate{index: len(result.Rows), prepared: prepared})
+			}
+		}
+
+		if len(rowErrors) > 0 {
+			rowResult.Status = ImportRowInvalid
+			rowResult.Errors = rowErrors
+			result.Invalid++
+		} else {
+			rowResult.Status = ImportRowValid
+			result.Valid++
+		}
+		result.Rows = append(result.Rows, rowResult)
+	}
+
+	result.Total = len(result.Rows)
+	if result.Total == 0 {
+		return nil, ErrImportEmpty
+	}
+
+	if input.DryRun {
+		return result, nil
+	}
+	if err := hashImportPasswords(candidates); err != nil {
+		return result, err
+	}
+
+	if mode == ImportModeAtomic {
+		if result.Invalid > 0 {
+			return result, ErrImportValidationFails
+		}
+		if err := s.commitImportBatch(ctx, candidates, result, false); err != nil {
+			return result, err
+		}
+		return result, nil
+	}
+
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+	if batchSize > maxImportBatchSize {
+		batchSize = maxImportBatchSize
+	}
+
+	for i := range result.Rows {
+		if result.Rows[i].Status == ImportRowInvalid {
+			result.Rows[i].Status = ImportRowSkipped
+		}
+	}
+	for start := 0; start < len(candidates); start += batchSize {
+		end := start + batchSize
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+		if err := s.commitImportBatch(ctx, candidates[start:end], result, true); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// hashImportPasswords 为待导入用户生成密码哈希。导入文件通常共用默认密码，
+// 相同明文只计算一次 bcrypt，避免大文件导入耗时随行数线性增长。
+func hashImportPasswords(candidates []importCandidate) error {
+	hashes := make(map[string]string)
+	for _, candidate := range candidates {
+		password := candidate.prepared.password
+		hashed, ok := hashes[password]
+		if !ok {
+			generated, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+			if err != nil {
+				return err
+			}
+			hashed = string(generated)
+			hashes[password] = hashed
+		}
+		candidate.prepared.record.Password = hashed
+	}
+	return nil
+}
+
+// commitImportBatch 在单个事务中写入一批用户。tolerant 为 true 时，
+// 数据冲突的行被标记为失败并剔除，剩余行重新提交。
+func (s *Service) commitImportBatch(ctx context.Context, batch []importCandidate, result *ImportResult, tolerant bool) error {
+	pending := append([]importCandidate(nil), batch...)
+	for len(pending) > 0 {
+		items := make([]UserWithRelations, len(pending))
+		for i, candidate := range pending {
+			// 重试前清除上一次事务回滚后残留的主键
+			candidate.prepared.record.ID = 0
+			items[i] = UserWithRelations{
+				User:    candidate.prepared.record,
+				RoleIDs: candidate.prepared.roleIDs,
+				PostIDs: candidate.prepared.postIDs,
+				Attrs:   candidate.prepared.attrs,
+			}
+		}
+
+		failedIndex, err := s.repo.CreateUsersWithRelations(ctx, items)
+		if err == nil {
+			for _, candidate := range pending {
+				row := &result.Rows[candidate.index]
+				row.Status = ImportRowCreated
+				row.UserID = int64(candidate.prepared.record.ID)
+				result.Created++
+			}
+			return nil
+		}
+		// 只有数据冲突归咎于某一行，其他错误（如数据库不可用）无论是否容错都原样返回
+		if failedIndex < 0 || !errors.Is(err, gorm.ErrDuplicatedKey) {
+			return err
+		}
+
+		row := &result.Rows[pending[failedIndex].index]
+		row.Status = ImportRowFailed
+		row.Errors = append(row.Errors, ErrDuplicateUsername.Error())
+		result.Failed++
+
+		if !tolerant {
+			return ErrImportValidationFails
+		}
+		pending = append(pending[:failedIndex], pending[failedIndex+1:]...)
+	}
+	return nil
+}
+
+func (s *Service) loadImportLookup(ctx context.Context) (*importLookup, error) {
+	depts, err := s.repo.ListAllDepartments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.repo.ListActiveRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := s.repo.ListActivePosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defs, err := s.attributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := &importLookup{
+		deptsByPath: make(map[string]int64, len(depts)),
+		deptsByName: make(map[string][]int64, len(depts)),
+		rolesByKey:  make(map[string]int64, len(roles)),
+		postsByCode: make(map[string]int64, len(posts)),
+		attributes:  defs,
+	}
+
+	deptByID := make(map[int64]model.SysDept, len(depts))
+	for _, dept := range depts {
+		deptByID[int64(dept.ID)] = dept
+	}
+	for _, dept := range depts {
+		id := int64(dept.ID)
+		lookup.deptsByName[dept.DeptName] = append(lookup.deptsByName[dept.DeptName], id)
+		lookup.deptsByPath[buildDeptPath(dept, deptByID)] = id
+	}
+	for _, role := range roles {
+		lookup.rolesByKey[strings.TrimSpace(role.RoleKey)] = int64(role.ID)
+	}
+	for _, post := range posts {
+		lookup.postsByCode[strings.TrimSpace(post.PostCode)] = int64(post.ID)
+	}
+	return lookup, nil
+}
+
+// buildDeptPath 以名称拼接从根到当前部门的路径，例如 "示例组织/总部/研发部门"
+func buildDeptPath(dept model.SysDept, deptByID map[int64]model.SysDept) string {
+	names := []string{dept.DeptName}
+	visited := map[int64]struct{}{int64(dept.ID): {}}
+	parentID := dept.ParentID
+	for parentID > 0 {
+		if _, ok := visited[parentID]; ok {
+			break
+		}
+		parent, ok := deptByID[parentID]
+		if !ok {
+			break
+		}
+		visited[parentID] = struct{}{}
+		names = append([]string{parent.DeptName}, names...)
+		parentID = parent.ParentID
+	}
+	return strings.Join(names, deptPathSeparator)
+}
+
+func (l *importLookup) resolveDept(value string) (*int64, error) {
+	value = strings.Trim(strings.TrimSpace(value), deptPathSeparator)
+	if value == "" {
+		return nil, nil
+	}
+
+	if strings.Contains(value, deptPathSeparator) {
+		parts := strings.Split(value, deptPathSeparator)
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		path := strings.Join(parts, deptPathSeparator)
+		if id, ok := l.deptsByPath[path]; ok {
+			return &id, nil
+		}
+		// 允许省略顶层部门，按路径后缀唯一匹配
+		var matched []int64
+		for candidate, id := range l.deptsByPath {
+			if strings.HasSuffix(candidate, deptPathSeparator+path) {
+				matched = append(matched, id)
+			}
+		}
+		if len(matched) == 1 {
+			return &matched[0], nil
+		}
+		if len(matched) > 1 {
+			return nil, fmt.Errorf("department path %q is ambiguous", value)
+		}
+		return nil, fmt.Errorf("department %q not found", value)
+	}
+
+	ids := l.deptsByName[value]
+	switch len(ids) {
+	case 0:
+		return nil, fmt.Errorf("department %q not found", value)
+	case 1:
+		id := ids[0]
+		return &id, nil
+	default:
+		return nil, fmt.Errorf("department name %q is ambiguous, use a path such as parent/child", value)
+	}
+}
+
+func buildImportInput(values map[string]string, lookup *importLookup, defaultPassword, operator string) (CreateUserInput, []string) {
+	var rowErrors []string
+
+	input := CreateUserInput{
+		UserName:    strings.TrimSpace(values["userName"]),
+		NickName:    strings.TrimSpace(values["nickName"]),
+		Email:       strings.TrimSpace(values["email"]),
+		Phonenumber: strings.TrimSpace(values["phonenumber"]),
+		Password:    strings.TrimSpace(values["password"]),
+		Operator:    operator,
+	}
+	if input.Password == "" {
+		input.Password = strings.TrimSpace(defaultPassword)
+	}
+	if remark := strings.TrimSpace(values["remark"]); remark != "" {
+		input.Remark = &remark
+	}
+
+	if raw := strings.TrimSpace(values["sex"]); raw != "" {
+		sex, ok := importSexValues[strings.ToLower(raw)]
+		if !ok {
+			rowErrors = append(rowErrors, fmt.Sprintf("invalid sex %q", raw))
+		}
+		input.Sex = sex
+	}
+
+	if raw := strings.TrimSpace(values["status"]); raw != "" {
+		status, ok := importStatusValues[strings.ToLower(raw)]
+		if !ok {
+			rowErrors = append(rowErrors, ErrInvalidStatus.Error())
+		}
+		input.Status = status
+	}
+
+	deptID, err := lookup.resolveDept(values["dept"])
+	if err != nil {
+		rowErrors = append(rowErrors, err.Error())
+	}
+	input.DeptID = deptID
+
+	for _, key := range splitImportList(values["roles"]) {
+		id, ok := lookup.rolesByKey[key]
+		if !ok {
+			rowErrors = append(rowErrors, fmt.Sprintf("role %q not found", key))
+			continue
+		}
+		input.RoleIDs = append(input.RoleIDs, id)
+	}
+
+	for _, code := range splitImportList(values["posts"]) {
+		id, ok := lookup.postsByCode[code]
+		if !ok {
+			rowErrors = append(rowErrors, fmt.Sprintf("post %q not found", code))
+			continue
+		}
+		input.PostIDs = append(input.PostIDs, id)
+	}
+
+	for _, def := range lookup.attributes {
+		if value, ok := values[attrColumnPrefix+def.AttrKey]; ok {
+			if input.Attributes == nil {
+				input.Attributes = make(map[string]string, len(lookup.attributes))
+			}
+			input.Attributes[def.AttrKey] = value
+		}
+	}
+
+	if input.UserName == "" {
+		rowErrors = append(rowErrors, ErrUsernameRequired.Error())
+	}
+	if input.NickName == "" {
+		rowErrors = append(rowErrors, ErrNicknameRequired.Error())
+	}
+	if input.Password == "" {
+		rowErrors = append(rowErrors, ErrPasswordRequired.Error())
+	}
+
+	return input, rowErrors
+}
+
+func isImportValidationError(err error) bool {
+	return errors.Is(err, ErrUsernameRequired) ||
+		errors.Is(err, ErrNicknameRequired) ||
+		errors.Is(err, ErrPasswordRequired) ||
+		errors.Is(err, ErrDuplicateUsername) ||
+		errors.Is(err, ErrInvalidStatus) ||
+		errors.Is(err, ErrInvalidRoleSelection) ||
+		errors.Is(err, ErrInvalidPostSelection) ||
+		isAttributeError(err)
+}
+
+type importRow struct {
+	number int
+	cells  []string
+}
+
+func (r importRow) values(columns map[string]int) map[string]string {
+	values := make(map[string]string, len(columns))
+	for key, idx := range columns {
+		if idx < len(r.cells) {
+			values[key] = r.cells[idx]
+		}
+	}
+	return values
+}
+
+// mapImportHeader 找到首个非空行作为表头，返回列映射与其后的数据行。
+// 表头别名先到先得，扩展属性不会覆盖固定列。
+func mapImportHeader(rows [][]string, known []importColumn) (map[string]int, []importRow, error) {
+	headerIdx := -1
+	for i, row := range rows {
+		if !isBlankCells(row) {
+			headerIdx = i
+			break
+		}
+	}
+	if headerIdx < 0 {
+		return nil, nil, ErrImportEmpty
+	}
+
+	aliases := make(map[string]string)
+	for _, column := range known {
+		names := append([]string{column.key, column.title}, column.aliases...)
+		for _, name := range names {
+			if _, taken := aliases[normalizeHeader(name)]; !taken {
+				aliases[normalizeHeader(name)] = column.key
+			}
+		}
+	}
+
+	columns := make(map[string]int)
+	for idx, cell := range rows[headerIdx] {
+		if key, ok := aliases[normalizeHeader(cell)]; ok {
+			if _, exists := columns[key]; !exists {
+				columns[key] = idx
+			}
+		}
+	}
+
+	var missing []string
+	for _, column := range known {
+		if !column.required {
+			continue
+		}
+		if _, ok := columns[column.key]; !ok {
+			missing = append(missing, column.title)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrImportMissingColumns, strings.Join(missing, ", "))
+	}
+
+	dataRows := make([]importRow, 0, len(rows)-headerIdx-1)
+	for i := headerIdx + 1; i < len(rows); i++ {
+		dataRows = append(dataRows, importRow{number: i + 1, cells: rows[i]})
+	}
+	return columns, dataRows, nil
+}
+
+func normalizeHeader(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.TrimSuffix(value, "*")
+	value = strings.ReplaceAll(value, " ", "")
+	return value
+}
+
+func splitImportList(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == '，' || r == '；' || r == '|'
+	})
+	result := make([]string, 0, len(fields))
+	seen := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		trimmed := strings.TrimSpace(field)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		result = append(result, trimmed)
+	}
+	return result
+}
+
+func isBlankRow(values map[string]string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func isBlankCells(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -10,6 +10,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 )
 
 var (
@@ -33,6 +34,8 @@ type ListUsersOptions struct {
 	PageSize int
 	UserName string
 	Status   string
+	// Attributes 按扩展属性精确匹配，键为属性键，值为规范化后的取值
+	Attributes map[string]string
 }
 
 func (r *Repository) ListUsers(ctx context.Context, opts ListUsersOptions) ([]model.SysUser, int64, error) {
@@ -49,15 +52,7 @@ func (r *Repository) ListUsers(ctx context.Context, opts ListUsersOptions) ([]mo
 		pageSize = 0
 	}
 
-	base := r.db.WithContext(ctx).Model(&model.SysUser{})
-
-	if userName := strings.TrimSpace(opts.UserName); userName != "" {
-		base = base.Where("user_name ILIKE ?", "%"+userName+"%")
-	}
-
-	if status := strings.TrimSpace(opts.Status); status != "" {
-		base = base.Where("status = ?", status)
-	}
+	base := applyUserFilters(r.db.WithContext(ctx).Model(&model.SysUser{}), opts)
 
 	var total int64
 	countQuery := base.Session(&gorm.Session{})
@@ -83,6 +78,44 @@ func (r *Repository) ListUsers(ctx context.Context, opts ListUsersOptions) ([]mo
 	return users, total, nil
 }
 
+// ExportUsers 按列表筛选条件分批读取用户，避免一次性加载全部记录
+func (r *Repository) ExportUsers(ctx context.Context, opts ListUsersOptions, batchSize int, fn func([]model.SysUser) error) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+
+	var batch []model.SysUser
+	result := applyUserFilters(r.db.WithContext(ctx).Model(&model.SysUser{}), opts).
+		FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+			return fn(batch)
+		})
+	return result.Error
+}
+
+func applyUserFilters(query *gorm.DB, opts ListUsersOptions) *gorm.DB {
+	if userName := strings.TrimSpace(opts.UserName); userName != "" {
+		query = query.Where("user_name ILIKE ?", "%"+userName+"%")
+	}
+
+	if status := strings.TrimSpace(opts.Status); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	keys := make([]string, 0, len(opts.Attributes))
+	for key := range opts.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		matched := query.Session(&gorm.Session{NewDB: true}).
+			Model(&model.SysUserAttr{}).
+			Select("user_id").
+			Where("attr_key = ? AND attr_value = ?", key, opts.Attributes[key])
+		query = query.Where("id IN (?)", matched)
+	}
+	return query
+}
+
 func (r *Repository) GetDepartments(ctx context.Context, ids []int64) (map[int64]model.SysDept, error) {
 	if r == nil || r.db == nil {
 		return nil, ErrRepositoryUnavailable
@@ -397,6 +430,73 @@ func (r *Repository) GetUserPostIDs(ctx context.Context, userIDs []int64) (map[i
 	return result, nil
 }
 
+// GetUserAttrs 批量读取用户扩展属性取值，按用户ID分组
+func (r *Repository) GetUserAttrs(ctx context.Context, userIDs []int64) (map[int64]map[string]string, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	if len(userIDs) == 0 {
+		return map[int64]map[string]string{}, nil
+	}
+
+	var rows []model.SysUserAttr
+	if err := r.db.WithContext(ctx).
+		Where("user_id IN ?", userIDs).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[int64]map[string]string, len(userIDs))
+	for _, row := range rows {
+		if result[row.UserID] == nil {
+			result[row.UserID] = make(map[string]string)
+		}
+		result[row.UserID][row.AttrKey] = row.AttrValue
+	}
+	return result, nil
+}
+
+// SaveUserAttrs 覆盖给定键的取值，空值表示清除该属性，未出现的键保持不变
+func (r *Repository) SaveUserAttrs(ctx context.Context, userID int64, values map[string]string) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if userID <= 0 {
+		return gorm.ErrInvalidData
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND attr_key IN ?", userID, keys).Delete(&model.SysUserAttr{}).Error; err != nil {
+			return err
+		}
+		return createUserAttrs(tx, userID, keys, values)
+	})
+}
+
+func createUserAttrs(tx *gorm.DB, userID int64, keys []string, values map[string]string) error {
+	entries := make([]model.SysUserAttr, 0, len(keys))
+	for _, key := range keys {
+		if values[key] == "" {
+			continue
+		}
+		entries = append(entries, model.SysUserAttr{UserID: userID, AttrKey: key, AttrValue: values[key]})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return tx.Create(&entries).Error
+}
+
 func (r *Repository) ReplaceUserRoles(ctx context.Context, userID int64, roleIDs []int64) error {
 	if r == nil || r.db == nil {
 		return ErrRepositoryUnavailable
@@ -494,15 +594,13 @@ func (r *Repository) UpdateUser(ctx context.Context, userID int64, updates map[s
 		return nil
 	}
 
-	result := r.db.WithContext(ctx).
-		Model(&model.SysUser{}).
-		Where("id = ?", userID).
-		Updates(updates)
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Model(&model.SysUser{}).Where("id = ?", userID)).Updates(updates)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+		return etag.Missed(db, &model.SysUser{}, userID)
 	}
 	return nil
 }
@@ -517,16 +615,22 @@ func (r *Repository) SoftDeleteUser(ctx context.Context, userID int64, operator
 
 	// Update the updater info first
 	updates := map[string]interface{}{
-		"updated_at":  time.Now(),
-		"update_time": at,
+		"updated_at": at,
 	}
 	if strings.TrimSpace(operator) != "" {
 		updates["update_by"] = strings.TrimSpace(operator)
 	}
-	r.db.WithContext(ctx).Model(&model.SysUser{}).Where("id = ?", userID).Updates(updates)
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Model(&model.SysUser{}).Where("id = ?", userID)).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return etag.Missed(db, &model.SysUser{}, userID)
+	}
 
 	// Perform soft delete
-	result := r.db.WithContext(ctx).Delete(&model.SysUser{}, userID)
+	result = db.Delete(&model.SysUser{}, userID)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -551,7 +655,7 @@ func (r *Repository) UpdateUserPassword(ctx context.Context, userID int64, hashe
 	updates := map[string]interface{}{
 		"password":        hashedPassword,
 		"pwd_update_date": at,
-		"update_time":     at,
+		"updated_at":      at,
 	}
 	if trimmed := strings.TrimSpace(operator); trimmed != "" {
 		updates["update_by"] = trimmed
@@ -595,3 +699,121 @@ func (r *Repository) ExistsByUsername(ctx context.Context, username string, excl
 
 	return count > 0, nil
 }
+
+func (r *Repository) ListAllDepartments(ctx context.Context) ([]model.SysDept, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	var depts []model.SysDept
+	if err := r.db.WithContext(ctx).
+		Model(&model.SysDept{}).
+		Order("parent_id ASC, order_num ASC, id ASC").
+		Find(&depts).Error; err != nil {
+		return nil, err
+	}
+	return depts, nil
+}
+
+func (r *Repository) ListActiveRoles(ctx context.Context) ([]model.SysRole, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	var roles []model.SysRole
+	if err := r.db.WithContext(ctx).
+		Model(&model.SysRole{}).
+		Where("status = ?", "0").
+		Order("role_sort ASC, id ASC").
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *Repository) ListActivePosts(ctx context.Context) ([]model.SysPost, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	var posts []model.SysPost
+	if err := r.db.WithContext(ctx).
+		Model(&model.SysPost{}).
+		Where("status = ?", "0").
+		Order("post_sort ASC, id ASC").
+		Find(&posts).Error; err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// UserWithRelations bundles a new user with its role and post assignments.
+type UserWithRelations struct {
+	User    *model.SysUser
+	RoleIDs []int64
+	PostIDs []int64
+	Attrs   map[string]string
+}
+
+// CreateUsersWithRelations inserts users and their relations in a single transaction.
+// On failure it returns the index of the offending item, or -1 when no item is to blame.
+func (r *Repository) CreateUsersWithRelations(ctx context.Context, items []UserWithRelations) (int, error) {
+	if r == nil || r.db == nil {
+		return -1, ErrRepositoryUnavailable
+	}
+	if len(items) == 0 {
+		return -1, nil
+	}
+
+	failedIndex := -1
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, item := range items {
+			if item.User == nil {
+				failedIndex = i
+				return ErrInvalidUserPayload
+			}
+			if err := tx.Create(item.User).Error; err != nil {
+				failedIndex = i
+				return err
+			}
+
+			userID := int64(item.User.ID)
+			if len(item.RoleIDs) > 0 {
+				entries := make([]model.SysUserRole, len(item.RoleIDs))
+				for j, id := range item.RoleIDs {
+					entries[j] = model.SysUserRole{UserID: userID, RoleID: id}
+				}
+				if err := tx.Create(&entries).Error; err != nil {
+					failedIndex = i
+					return err
+				}
+			}
+			if len(item.PostIDs) > 0 {
+				entries := make([]model.SysUserPost, len(item.PostIDs))
+				for j, id := range item.PostIDs {
+					entries[j] = model.SysUserPost{UserID: userID, PostID: id}
+				}
+				if err := tx.Create(&entries).Error; err != nil {
+					failedIndex = i
+					return err
+				}
+			}
+			if len(item.Attrs) > 0 {
+				keys := make([]string, 0, len(item.Attrs))
+				for key := range item.Attrs {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				if err := createUserAttrs(tx, userID, keys, item.Attrs); err != nil {
+					failedIndex = i
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return failedIndex, err
+	}
+	return -1, nil
+}
@@ -12,6 +12,12 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/auth"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+	"github.com/starter-kit-fe/admin/internal/system/file"
+	"github.com/starter-kit-fe/admin/internal/system/history"
+	"github.com/starter-kit-fe/admin/internal/system/i18n"
+	"github.com/starter-kit-fe/admin/internal/system/userattr"
 )
 
 var (
@@ -23,17 +29,36 @@ var (
 	ErrInvalidStatus        = errors.New("invalid user status")
 	ErrInvalidRoleSelection = errors.New("invalid role selection")
 	ErrInvalidPostSelection = errors.New("invalid post selection")
+	ErrUsernameRequired     = errors.New("username is required")
+	ErrNicknameRequired     = errors.New("nickname is required")
 )
 
+var statusEnum = dict.Enum{
+	DictType: dict.TypeNormalDisable,
+	Fallback: []string{"0", "1"},
+	Default:  "0",
+	Invalid:  ErrInvalidStatus,
+}
+
 type Service struct {
-	repo *Repository
+	repo    *Repository
+	files   *file.Service
+	grants  *auth.Repository
+	attrs   *userattr.Service
+	history *history.Service
+	dicts   *dict.Service
 }
 
-func NewService(repo *Repository) *Service {
+// NewService creates the user service; files is optional and only required for avatar uploads,
+// grants is optional and only required to show inherited roles in user details,
+// attrs is optional and enables admin-defined user attributes,
+// history is optional and records field-level changes when present,
+// dicts is optional and validates status values against the sys_normal_disable dictionary.
+func NewService(repo *Repository, files *file.Service, grants *auth.Repository, attrs *userattr.Service, history *history.Service, dicts *dict.Service) *Service {
 	if repo == nil {
 		return nil
 	}
-	return &Service{repo: repo}
+	return &Service{repo: repo, files: files, grants: grants, attrs: attrs, history: history, dicts: dicts}
 }
 
 type ListOptions struct {
@@ -41,6 +66,8 @@ type ListOptions struct {
 	PageSize int
 	UserName string
 	Status   string
+	// Attributes 按扩展属性取值精确筛选，键为属性键
+	Attributes map[string]string
 }
 
 type ListResult struct {
@@ -61,6 +88,12 @@ type RoleOption struct {
 	RoleKey  string `json:"roleKey"`
 }
 
+// EffectiveRole 用户实际拥有的角色，Sources 说明角色来自直接分配还是部门、岗位继承
+type EffectiveRole struct {
+	RoleOption
+	Sources []auth.RoleSource `json:"sources"`
+}
+
 type PostOption struct {
 	PostID   int64  `json:"postId"`
 	PostName string `json:"postName"`
@@ -80,6 +113,7 @@ type User struct {
 	Avatar        string       `json:"avatar"`
 	Status        string       `json:"status"`
 	Remark        *string      `json:"remark,omitempty"`
+	Locale        string       `json:"locale"`
 	LoginIP       string       `json:"loginIp"`
 	LoginDate     *time.Time   `json:"loginDate,omitempty"`
 	PwdUpdateDate *time.Time   `json:"pwdUpdateDate,omitempty"`
@@ -89,6 +123,10 @@ type User struct {
 	UpdatedAt     *time.Time   `json:"updatedAt,omitempty"`
 	Roles         []RoleOption `json:"roles"`
 	Posts         []PostOption `json:"posts"`
+	// Attributes 扩展属性取值，仅包含启用的属性
+	Attributes map[string]string `json:"attributes"`
+	// EffectiveRoles 仅在用户详情中返回，包含继承的角色
+	EffectiveRoles []EffectiveRole `json:"effectiveRoles,omitempty"`
 }
 
 type CreateUserInput struct {
@@ -104,6 +142,7 @@ type CreateUserInput struct {
 	Operator    string
 	RoleIDs     []int64
 	PostIDs     []int64
+	Attributes  map[string]string
 }
 
 type UpdateUserInput struct {
@@ -119,6 +158,8 @@ type UpdateUserInput struct {
 	Operator    string
 	RoleIDs     *[]int64
 	PostIDs     *[]int64
+	// Attributes 只修改提交的属性，空值表示清除
+	Attributes map[string]string
 }
 
 type DeleteUserInput struct {
@@ -139,6 +180,8 @@ type UpdateProfileInput struct {
 	Phonenumber string
 	Sex         string
 	Remark      *string
+	// Locale 为 nil 时不修改，空字符串清除偏好
+	Locale *string
 }
 
 type ChangePasswordInput struct {
@@ -167,11 +210,17 @@ func (s *Service) ListUsers(ctx context.Context, opts ListOptions) (*ListResult,
 		status = ""
 	}
 
+	attrFilters, err := s.resolveAttributeFilters(ctx, opts.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	result, total, err := s.repo.ListUsers(ctx, ListUsersOptions{
-		PageNum:  pageNum,
-		PageSize: pageSize,
-		UserName: opts.UserName,
-		Status:   status,
+		PageNum:    pageNum,
+		PageSize:   pageSize,
+		UserName:   opts.UserName,
+		Status:     status,
+		Attributes: attrFilters,
 	})
 	if err != nil {
 		return nil, err
@@ -208,25 +257,134 @@ func (s *Service) GetUser(ctx context.Context, id int64) (*User, error) {
 		return nil, gorm.ErrRecordNotFound
 	}
 
+	effective, err := s.effectiveRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	users[0].EffectiveRoles = effective
+
 	return &users[0], nil
 }
 
+// effectiveRoles 汇总用户直接分配与继承的启用角色及其来源
+func (s *Service) effectiveRoles(ctx context.Context, userID uint) ([]EffectiveRole, error) {
+	if s.grants == nil {
+		return nil, nil
+	}
+
+	grants, err := s.grants.ResolveRoleGrants(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(grants) == 0 {
+		return []EffectiveRole{}, nil
+	}
+
+	roleIDs := make([]int64, 0, len(grants))
+	for _, grant := range grants {
+		roleIDs = append(roleIDs, grant.RoleID)
+	}
+	roleMap, err := s.repo.GetRolesByIDs(ctx, roleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]EffectiveRole, 0, len(grants))
+	for _, grant := range grants {
+		role, ok := roleMap[grant.RoleID]
+		if !ok || role.Status != "0" {
+			continue
+		}
+		roles = append(roles, EffectiveRole{
+			RoleOption: RoleOption{
+				RoleID:   int64(role.ID),
+				RoleName: role.RoleName,
+				RoleKey:  role.RoleKey,
+			},
+			Sources: grant.Sources,
+		})
+	}
+	return roles, nil
+}
+
 func (s *Service) CreateUser(ctx context.Context, input CreateUserInput) (*User, error) {
 	if s == nil || s.repo == nil {
 		return nil, ErrServiceUnavailable
 	}
 
+	defs, err := s.attributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prepared, err := s.prepareCreateUser(ctx, input, defs)
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(prepared.password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	user := prepared.record
+	user.Password = string(hashedPassword)
+
+	if err := s.repo.CreateUser(ctx, user); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrDuplicateUsername
+		}
+		return nil, err
+	}
+
+	if err := s.repo.ReplaceUserRoles(ctx, int64(user.ID), prepared.roleIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReplaceUserPosts(ctx, int64(user.ID), prepared.postIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveUserAttrs(ctx, int64(user.ID), prepared.attrs); err != nil {
+		return nil, err
+	}
+
+	created, err := s.GetUser(ctx, int64(user.ID))
+	if err != nil {
+		return nil, err
+	}
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleUser,
+		EntityID: created.UserID,
+		Action:   history.ActionCreate,
+		Operator: user.CreateBy,
+		After:    created,
+	})
+	return created, nil
+}
+
+// preparedUser 为通过校验、尚未写库的用户数据，密码仍为明文
+type preparedUser struct {
+	record   *model.SysUser
+	password string
+	roleIDs  []int64
+	postIDs  []int64
+	attrs    map[string]string
+}
+
+// prepareCreateUser 校验新增用户参数，单个新增与批量导入共用同一套规则
+func (s *Service) prepareCreateUser(ctx context.Context, input CreateUserInput, defs []userattr.Definition) (*preparedUser, error) {
 	username := strings.TrimSpace(input.UserName)
 	if username == "" {
-		return nil, errors.New("username is required")
+		return nil, ErrUsernameRequired
 	}
 
 	nickname := strings.TrimSpace(input.NickName)
 	if nickname == "" {
-		return nil, errors.New("nickname is required")
+		return nil, ErrNicknameRequired
 	}
 
-	if strings.TrimSpace(input.Password) == "" {
+	password := strings.TrimSpace(input.Password)
+	if password == "" {
 		return nil, ErrPasswordRequired
 	}
 
@@ -238,18 +396,13 @@ func (s *Service) CreateUser(ctx context.Context, input CreateUserInput) (*User,
 		return nil, ErrDuplicateUsername
 	}
 
-	status, err := normalizeStatus(input.Status)
+	status, err := s.dicts.Normalize(ctx, statusEnum, input.Status)
 	if err != nil {
 		return nil, err
 	}
 
 	sex := normalizeSex(input.Sex)
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(strings.TrimSpace(input.Password)), bcrypt.DefaultCost)
-	if err != nil {
-		return nil, err
-	}
-
 	now := time.Now()
 	operator := sanitizeOperator(input.Operator)
 	email := strings.TrimSpace(input.Email)
@@ -285,7 +438,12 @@ func (s *Service) CreateUser(ctx context.Context, input CreateUserInput) (*User,
 		}
 	}
 
-	user := &model.SysUser{
+	attrs, err := resolveAttributes(defs, input.Attributes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &model.SysUser{
 		DeptID:      input.DeptID,
 		UserName:    username,
 		NickName:    nickname,
@@ -294,7 +452,6 @@ func (s *Service) CreateUser(ctx context.Context, input CreateUserInput) (*User,
 		Phonenumber: phone,
 		Sex:         sex,
 		Avatar:      "",
-		Password:    string(hashedPassword),
 		Status:      status,
 
 		LoginIP:       "",
@@ -304,22 +461,13 @@ func (s *Service) CreateUser(ctx context.Context, input CreateUserInput) (*User,
 		Remark:        remark,
 	}
 
-	if err := s.repo.CreateUser(ctx, user); err != nil {
-		if errors.Is(err, gorm.ErrDuplicatedKey) {
-			return nil, ErrDuplicateUsername
-		}
-		return nil, err
-	}
-
-	if err := s.repo.ReplaceUserRoles(ctx, int64(user.ID), roleIDs); err != nil {
-		return nil, err
-	}
-
-	if err := s.repo.ReplaceUserPosts(ctx, int64(user.ID), postIDs); err != nil {
-		return nil, err
-	}
-
-	return s.GetUser(ctx, int64(user.ID))
+	return &preparedUser{
+		record:   record,
+		password: password,
+		roleIDs:  roleIDs,
+		postIDs:  postIDs,
+		attrs:    attrs,
+	}, nil
 }
 
 func (s *Service) UpdateUser(ctx context.Context, input UpdateUserInput) (*User, error) {
@@ -376,10 +524,22 @@ func (s *Service) UpdateUser(ctx context.Context, input UpdateUserInput) (*User,
 		}
 	}
 
+	var attrs map[string]string
+	if input.Attributes != nil {
+		defs, err := s.attributeDefinitions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		attrs, err = resolveAttributes(defs, input.Attributes, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if input.UserName != nil {
 		newUsername := strings.TrimSpace(*input.UserName)
 		if newUsername == "" {
-			return nil, errors.New("username is required")
+			return nil, ErrUsernameRequired
 		}
 		if newUsername != existing.UserName {
 			exists, err := s.repo.ExistsByUsername(ctx, newUsername, input.ID)
@@ -396,7 +556,7 @@ func (s *Service) UpdateUser(ctx context.Context, input UpdateUserInput) (*User,
 	if input.NickName != nil {
 		newNickname := strings.TrimSpace(*input.NickName)
 		if newNickname == "" {
-			return nil, errors.New("nickname is required")
+			return nil, ErrNicknameRequired
 		}
 		updates["nick_name"] = newNickname
 	}
@@ -418,7 +578,7 @@ func (s *Service) UpdateUser(ctx context.Context, input UpdateUserInput) (*User,
 	}
 
 	if input.Status != nil {
-		status, err := normalizeStatus(*input.Status)
+		status, err := s.dicts.Normalize(ctx, statusEnum, *input.Status)
 		if err != nil {
 			return nil, err
 		}
@@ -433,10 +593,15 @@ func (s *Service) UpdateUser(ctx context.Context, input UpdateUserInput) (*User,
 		}
 	}
 
-	if len(updates) == 0 && !roleUpdateRequested && !postUpdateRequested {
+	if len(updates) == 0 && !roleUpdateRequested && !postUpdateRequested && len(attrs) == 0 {
 		return s.GetUser(ctx, input.ID)
 	}
 
+	var before *User
+	if s.history != nil {
+		before, _ = s.GetUser(ctx, input.ID)
+	}
+
 	operator := sanitizeOperator(input.Operator)
 	now := time.Now()
 	updates["update_by"] = operator
@@ -463,7 +628,11 @@ func (s *Service) UpdateUser(ctx context.Context, input UpdateUserInput) (*User,
 		}
 	}
 
-	return s.GetUser(ctx, input.ID)
+	if err := s.repo.SaveUserAttrs(ctx, input.ID, attrs); err != nil {
+		return nil, err
+	}
+
+	return s.recordUpdate(ctx, input.ID, operator, before)
 }
 
 func (s *Service) DeleteUser(ctx context.Context, input DeleteUserInput) error {
@@ -474,8 +643,23 @@ func (s *Service) DeleteUser(ctx context.Context, input DeleteUserInput) error {
 		return gorm.ErrRecordNotFound
 	}
 
+	var before *User
+	if s.history != nil {
+		before, _ = s.GetUser(ctx, input.ID)
+	}
+
 	operator := sanitizeOperator(input.Operator)
-	return s.repo.SoftDeleteUser(ctx, input.ID, operator, time.Now())
+	if err := s.repo.SoftDeleteUser(ctx, input.ID, operator, time.Now()); err != nil {
+		return err
+	}
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleUser,
+		EntityID: input.ID,
+		Action:   history.ActionDelete,
+		Operator: operator,
+		Before:   before,
+	})
+	return nil
 }
 
 func (s *Service) ResetPassword(ctx context.Context, input ResetPasswordInput) error {
@@ -523,7 +707,7 @@ func (s *Service) UpdateProfile(ctx context.Context, input UpdateProfileInput) (
 
 	nickname := strings.TrimSpace(input.NickName)
 	if nickname == "" {
-		return nil, errors.New("nickname is required")
+		return nil, ErrNicknameRequired
 	}
 
 	updates := map[string]interface{}{
@@ -541,6 +725,21 @@ func (s *Service) UpdateProfile(ctx context.Context, input UpdateProfileInput) (
 		}
 	}
 
+	if input.Locale != nil {
+		locale := strings.TrimSpace(*input.Locale)
+		if locale != "" {
+			if locale, err = i18n.NormalizeLocale(locale); err != nil {
+				return nil, err
+			}
+		}
+		updates["locale"] = locale
+	}
+
+	var before *User
+	if s.history != nil {
+		before, _ = s.GetUser(ctx, input.UserID)
+	}
+
 	operator := sanitizeOperator(strconv.FormatInt(input.UserID, 10))
 	now := time.Now()
 	updates["update_by"] = operator
@@ -549,7 +748,24 @@ func (s *Service) UpdateProfile(ctx context.Context, input UpdateProfileInput) (
 	if err := s.repo.UpdateUser(ctx, input.UserID, updates); err != nil {
 		return nil, err
 	}
-	return s.GetUser(ctx, input.UserID)
+	return s.recordUpdate(ctx, input.UserID, operator, before)
+}
+
+// recordUpdate 重新加载用户并记录与修改前快照的字段差异
+func (s *Service) recordUpdate(ctx context.Context, id int64, operator string, before *User) (*User, error) {
+	updated, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.history.Record(ctx, history.Entry{
+		Module:   history.ModuleUser,
+		EntityID: id,
+		Action:   history.ActionUpdate,
+		Operator: operator,
+		Before:   before,
+		After:    updated,
+	})
+	return updated, nil
 }
 
 func (s *Service) ChangePassword(ctx context.Context, input ChangePasswordInput) error {
@@ -635,9 +851,19 @@ func (s *Service) composeUsers(ctx context.Context, records []model.SysUser) ([]
 		}
 	}
 
+	attrMap, err := s.repo.GetUserAttrs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defs, err := s.attributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	users := make([]User, len(records))
 	for i, record := range records {
 		users[i] = toUserDTO(record, deptMap, roleIDMap, roleMap, postIDMap, postMap)
+		users[i].Attributes = visibleAttributes(attrMap[int64(record.ID)], defs)
 	}
 	return users, nil
 }
@@ -801,6 +1027,7 @@ func toUserDTO(
 		Avatar:        user.Avatar,
 		Status:        user.Status,
 		Remark:        copyStringPtr(user.Remark),
+		Locale:        user.Locale,
 		LoginIP:       user.LoginIP,
 		LoginDate:     user.LoginDate,
 		PwdUpdateDate: user.PwdUpdateDate,
@@ -837,20 +1064,6 @@ func normalizeIDs(ids []int64) []int64 {
 	return result
 }
 
-func normalizeStatus(status string) (string, error) {
-	value := strings.TrimSpace(status)
-	if value == "" {
-		return "0", nil
-	}
-
-	switch value {
-	case "0", "1":
-		return value, nil
-	default:
-		return "", ErrInvalidStatus
-	}
-}
-
 func (s *Service) ListDepartmentOptions(ctx context.Context, keyword string, limit int) ([]DeptOption, error) {
 	if s == nil || s.repo == nil {
 		return nil, ErrServiceUnavailable
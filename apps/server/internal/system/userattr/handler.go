@@ -0,0 +1,327 @@
+package userattr
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/middleware"
+	"github.com/starter-kit-fe/admin/pkg/etag"
+	"github.com/starter-kit-fe/admin/pkg/resp"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	if service == nil {
+		return nil
+	}
+	return &Handler{service: service}
+}
+
+type listDefinitionsQuery struct {
+	Status string `form:"status"`
+}
+
+type createDefinitionRequest struct {
+	AttrKey   string   `json:"attrKey" binding:"required"`
+	AttrLabel string   `json:"attrLabel" binding:"required"`
+	AttrType  string   `json:"attrType"`
+	Required  bool     `json:"required"`
+	Pattern   *string  `json:"pattern"`
+	MaxLength int      `json:"maxLength"`
+	MinValue  *float64 `json:"minValue"`
+	MaxValue  *float64 `json:"maxValue"`
+	DictType  *string  `json:"dictType"`
+	SortOrder int      `json:"sortOrder"`
+	Status    string   `json:"status"`
+	Remark    *string  `json:"remark"`
+}
+
+type updateDefinitionRequest struct {
+	AttrLabel *string  `json:"attrLabel"`
+	Required  *bool    `json:"required"`
+	Pattern   *string  `json:"pattern"`
+	MaxLength *int     `json:"maxLength"`
+	MinValue  *float64 `json:"minValue"`
+	MaxValue  *float64 `json:"maxValue"`
+	DictType  *string  `json:"dictType"`
+	SortOrder *int     `json:"sortOrder"`
+	Status    *string  `json:"status"`
+	Remark    *string  `json:"remark"`
+}
+
+// List godoc
+// @Summary 获取用户扩展属性定义
+// @Description 按排序返回属性定义，select 类型附带字典可选项，用于渲染用户表单
+// @Tags System/UserAttribute
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "定义状态"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/user-attributes [get]
+func (h *Handler) List(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("user attribute service unavailable"))
+		return
+	}
+
+	var query listDefinitionsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid query parameters"))
+		return
+	}
+
+	defs, err := h.service.ListDefinitions(ctx.Request.Context(), QueryOptions{Status: query.Status})
+	if err != nil {
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load user attributes"))
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(defs))
+}
+
+// Get godoc
+// @Summary 获取用户扩展属性定义详情
+// @Tags System/UserAttribute
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "属性定义ID"
+// @Success 200 {object} resp.Response
+// @Header 200 {string} ETag "属性定义版本，修改或删除时通过 If-Match 回传"
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/user-attributes/{id} [get]
+func (h *Handler) Get(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("user attribute service unavailable"))
+		return
+	}
+
+	id, err := parseDefinitionID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid user attribute id"))
+		return
+	}
+
+	def, err := h.service.GetDefinition(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.NotFound(ctx, resp.WithMessage("user attribute not found"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to load user attribute"))
+		return
+	}
+
+	etag.Set(ctx, *def.UpdatedAt)
+	resp.OK(ctx, resp.WithData(def))
+}
+
+// Create godoc
+// @Summary 新增用户扩展属性定义
+// @Description 属性类型可选 string、number、boolean、date、select，select 类型需指定字典类型
+// @Tags System/UserAttribute
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body createDefinitionRequest true "属性定义"
+// @Success 201 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 409 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/user-attributes [post]
+func (h *Handler) Create(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("user attribute service unavailable"))
+		return
+	}
+
+	var payload createDefinitionRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid user attribute payload"))
+		return
+	}
+
+	def, err := h.service.CreateDefinition(ctx.Request.Context(), CreateDefinitionInput{
+		AttrKey:   payload.AttrKey,
+		AttrLabel: payload.AttrLabel,
+		AttrType:  payload.AttrType,
+		Required:  payload.Required,
+		Pattern:   payload.Pattern,
+		MaxLength: payload.MaxLength,
+		MinValue:  payload.MinValue,
+		MaxValue:  payload.MaxValue,
+		DictType:  payload.DictType,
+		SortOrder: payload.SortOrder,
+		Status:    payload.Status,
+		Remark:    payload.Remark,
+		Operator:  resolveOperator(ctx),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrDuplicateAttrKey):
+			resp.Conflict(ctx, resp.WithMessage(err.Error()))
+		case isValidationError(err), errors.Is(err, ErrAttrKeyRequired), errors.Is(err, ErrInvalidAttrKey), errors.Is(err, ErrInvalidAttrType):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to create user attribute"))
+		}
+		return
+	}
+
+	resp.Created(ctx, resp.WithData(def))
+}
+
+// Update godoc
+// @Summary 修改用户扩展属性定义
+// @Description 属性键与类型创建后不可修改
+// @Tags System/UserAttribute
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "属性定义ID"
+// @Param request body updateDefinitionRequest true "属性定义"
+// @Param If-Match header string true "获取属性定义时返回的 ETag"
+// @Success 200 {object} resp.Response
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/user-attributes/{id} [put]
+func (h *Handler) Update(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("user attribute service unavailable"))
+		return
+	}
+
+	id, err := parseDefinitionID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid user attribute id"))
+		return
+	}
+
+	var payload updateDefinitionRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid user attribute payload"))
+		return
+	}
+
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	def, err := h.service.UpdateDefinition(ctx.Request.Context(), UpdateDefinitionInput{
+		ID:        id,
+		AttrLabel: payload.AttrLabel,
+		Required:  payload.Required,
+		Pattern:   payload.Pattern,
+		MaxLength: payload.MaxLength,
+		MinValue:  payload.MinValue,
+		MaxValue:  payload.MaxValue,
+		DictType:  payload.DictType,
+		SortOrder: payload.SortOrder,
+		Status:    payload.Status,
+		Remark:    payload.Remark,
+		Operator:  resolveOperator(ctx),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			resp.NotFound(ctx, resp.WithMessage("user attribute not found"))
+		case errors.Is(err, etag.ErrPreconditionFailed):
+			resp.PreconditionFailed(ctx, resp.WithMessage("user attribute has been modified, reload and retry"))
+		case isValidationError(err):
+			resp.BadRequest(ctx, resp.WithMessage(err.Error()))
+		default:
+			resp.InternalServerError(ctx, resp.WithMessage("failed to update user attribute"))
+		}
+		return
+	}
+
+	resp.OK(ctx, resp.WithData(def))
+}
+
+// Delete godoc
+// @Summary 删除用户扩展属性定义
+// @Description 删除定义的同时清除所有用户在该属性上的取值
+// @Tags System/UserAttribute
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "属性定义ID"
+// @Param If-Match header string true "获取属性定义时返回的 ETag"
+// @Success 204 {object} nil
+// @Failure 400 {object} resp.Response
+// @Failure 404 {object} resp.Response
+// @Failure 412 {object} resp.Response
+// @Failure 428 {object} resp.Response
+// @Failure 500 {object} resp.Response
+// @Failure 503 {object} resp.Response
+// @Router /v1/system/user-attributes/{id} [delete]
+func (h *Handler) Delete(ctx *gin.Context) {
+	if h == nil || h.service == nil {
+		resp.ServiceUnavailable(ctx, resp.WithMessage("user attribute service unavailable"))
+		return
+	}
+
+	id, err := parseDefinitionID(ctx.Param("id"))
+	if err != nil {
+		resp.BadRequest(ctx, resp.WithMessage("invalid user attribute id"))
+		return
+	}
+
+	if err := etag.Bind(ctx); err != nil {
+		resp.PreconditionRequired(ctx, resp.WithMessage(err.Error()))
+		return
+	}
+
+	if err := h.service.DeleteDefinition(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.NotFound(ctx, resp.WithMessage("user attribute not found"))
+			return
+		}
+		if errors.Is(err, etag.ErrPreconditionFailed) {
+			resp.PreconditionFailed(ctx, resp.WithMessage("user attribute has been modified, reload and retry"))
+			return
+		}
+		resp.InternalServerError(ctx, resp.WithMessage("failed to delete user attribute"))
+		return
+	}
+
+	resp.NoContent(ctx)
+}
+
+func isValidationError(err error) bool {
+	return errors.Is(err, ErrAttrLabelRequired) ||
+		errors.Is(err, ErrInvalidAttrStatus) ||
+		errors.Is(err, ErrInvalidPattern) ||
+		errors.Is(err, ErrInvalidRange) ||
+		errors.Is(err, ErrDictTypeRequired) ||
+		errors.Is(err, ErrDictTypeNotFound)
+}
+
+func resolveOperator(ctx *gin.Context) string {
+	id, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func parseDefinitionID(param string) (int64, error) {
+	return strconv.ParseInt(strings.TrimSpace(param), 10, 64)
+}
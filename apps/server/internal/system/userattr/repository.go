@@ -0,0 +1,168 @@
+package userattr
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/pkg/etag"
+)
+
+var (
+	ErrRepositoryUnavailable = errors.New("user attribute repository is not initialized")
+	ErrInvalidDefinition     = errors.New("user attribute definition payload is invalid")
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	if db == nil {
+		return nil
+	}
+	return &Repository{db: db}
+}
+
+type ListOptions struct {
+	Status string
+}
+
+func (r *Repository) ListDefinitions(ctx context.Context, opts ListOptions) ([]model.SysUserAttrDef, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+
+	query := r.db.WithContext(ctx).Model(&model.SysUserAttrDef{})
+	if status := strings.TrimSpace(opts.Status); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var defs []model.SysUserAttrDef
+	if err := query.Order("sort_order ASC, id ASC").Find(&defs).Error; err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+func (r *Repository) GetDefinition(ctx context.Context, id int64) (*model.SysUserAttrDef, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	if id <= 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var def model.SysUserAttrDef
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&def).Error; err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+func (r *Repository) ExistsByKey(ctx context.Context, key string) (bool, error) {
+	if r == nil || r.db == nil {
+		return false, ErrRepositoryUnavailable
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.SysUserAttrDef{}).
+		Where("attr_key = ?", key).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *Repository) CreateDefinition(ctx context.Context, def *model.SysUserAttrDef) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if def == nil {
+		return ErrInvalidDefinition
+	}
+	return r.db.WithContext(ctx).Create(def).Error
+}
+
+func (r *Repository) SaveDefinition(ctx context.Context, def *model.SysUserAttrDef) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if def == nil {
+		return ErrInvalidDefinition
+	}
+	db := r.db.WithContext(ctx)
+	result := etag.Guard(db.Model(def)).Select("*").Updates(def)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return etag.Missed(db, &model.SysUserAttrDef{}, def.ID)
+	}
+	return nil
+}
+
+// DeleteDefinition 物理删除属性定义及所有用户在该属性上的取值
+func (r *Repository) DeleteDefinition(ctx context.Context, def *model.SysUserAttrDef) error {
+	if r == nil || r.db == nil {
+		return ErrRepositoryUnavailable
+	}
+	if def == nil {
+		return ErrInvalidDefinition
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := etag.Guard(tx.Unscoped().Where("id = ?", def.ID)).Delete(&model.SysUserAttrDef{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return etag.Missed(tx, &model.SysUserAttrDef{}, def.ID)
+		}
+		return tx.Where("attr_key = ?", def.AttrKey).Delete(&model.SysUserAttr{}).Error
+	})
+}
+
+func (r *Repository) DictTypeExists(ctx context.Context, dictType string) (bool, error) {
+	if r == nil || r.db == nil {
+		return false, ErrRepositoryUnavailable
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.SysDictType{}).
+		Where("dict_type = ?", dictType).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListDictOptions 返回各字典类型下启用的字典数据，按排序号排列
+func (r *Repository) ListDictOptions(ctx context.Context, dictTypes []string) (map[string][]model.SysDictData, error) {
+	if r == nil || r.db == nil {
+		return nil, ErrRepositoryUnavailable
+	}
+	if len(dictTypes) == 0 {
+		return map[string][]model.SysDictData{}, nil
+	}
+
+	var items []model.SysDictData
+	if err := r.db.WithContext(ctx).
+		Where("dict_type IN ?", dictTypes).
+		Where("status = ?", "0").
+		Order("dict_sort ASC, id ASC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]model.SysDictData, len(dictTypes))
+	for _, item := range items {
+		result[item.DictType] = append(result[item.DictType], item)
+	}
+	return result, nil
+}
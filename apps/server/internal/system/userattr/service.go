@@ -0,0 +1,485 @@
+package userattr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+var (
+	ErrServiceUnavailable  = errors.New("user attribute service is not initialized")
+	ErrAttrKeyRequired     = errors.New("attribute key is required")
+	ErrInvalidAttrKey      = errors.New("attribute key must start with a letter and contain only letters, digits or '_' (max 64)")
+	ErrAttrLabelRequired   = errors.New("attribute label is required")
+	ErrInvalidAttrType     = errors.New("invalid attribute type")
+	ErrInvalidAttrStatus   = errors.New("invalid attribute status")
+	ErrInvalidPattern      = errors.New("invalid attribute pattern")
+	ErrInvalidRange        = errors.New("attribute min value must not exceed max value")
+	ErrDictTypeRequired    = errors.New("select attributes require a dictionary type")
+	ErrDictTypeNotFound    = errors.New("dictionary type not found")
+	ErrDuplicateAttrKey    = errors.New("duplicate attribute key")
+	ErrUnknownAttribute    = errors.New("unknown user attribute")
+	ErrAttributeRequired   = errors.New("user attribute is required")
+	ErrInvalidAttributeVal = errors.New("invalid user attribute value")
+)
+
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeDate    = "date"
+	TypeSelect  = "select"
+)
+
+// DateLayout 日期类型属性的存储格式
+const DateLayout = "2006-01-02"
+
+var (
+	attrKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,63}$`)
+	validTypes     = map[string]struct{}{
+		TypeString:  {},
+		TypeNumber:  {},
+		TypeBoolean: {},
+		TypeDate:    {},
+		TypeSelect:  {},
+	}
+	validStatuses = map[string]struct{}{
+		"0": {},
+		"1": {},
+	}
+	booleanValues = map[string]string{
+		"true": "true", "1": "true", "yes": "true", "y": "true", "是": "true",
+		"false": "false", "0": "false", "no": "false", "n": "false", "否": "false",
+	}
+)
+
+// maxValueLength 与 sys_user_attr.attr_value 的列宽一致
+const maxValueLength = 500
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	if repo == nil {
+		return nil
+	}
+	return &Service{repo: repo}
+}
+
+type Option struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+type Definition struct {
+	ID        int64      `json:"id"`
+	AttrKey   string     `json:"attrKey"`
+	AttrLabel string     `json:"attrLabel"`
+	AttrType  string     `json:"attrType"`
+	Required  bool       `json:"required"`
+	Pattern   *string    `json:"pattern,omitempty"`
+	MaxLength int        `json:"maxLength,omitempty"`
+	MinValue  *float64   `json:"minValue,omitempty"`
+	MaxValue  *float64   `json:"maxValue,omitempty"`
+	DictType  *string    `json:"dictType,omitempty"`
+	Options   []Option   `json:"options,omitempty"`
+	SortOrder int        `json:"sortOrder"`
+	Status    string     `json:"status"`
+	Remark    *string    `json:"remark,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+type QueryOptions struct {
+	Status string
+}
+
+type CreateDefinitionInput struct {
+	AttrKey   string
+	AttrLabel string
+	AttrType  string
+	Required  bool
+	Pattern   *string
+	MaxLength int
+	MinValue  *float64
+	MaxValue  *float64
+	DictType  *string
+	SortOrder int
+	Status    string
+	Remark    *string
+	Operator  string
+}
+
+// UpdateDefinitionInput 属性键与类型决定了已存取值的含义，创建后不可修改
+type UpdateDefinitionInput struct {
+	ID        int64
+	AttrLabel *string
+	Required  *bool
+	Pattern   *string
+	MaxLength *int
+	MinValue  *float64
+	MaxValue  *float64
+	DictType  *string
+	SortOrder *int
+	Status    *string
+	Remark    *string
+	Operator  string
+}
+
+func (s *Service) ListDefinitions(ctx context.Context, opts QueryOptions) ([]Definition, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	records, err := s.repo.ListDefinitions(ctx, ListOptions{Status: opts.Status})
+	if err != nil {
+		return nil, err
+	}
+	return s.withOptions(ctx, records)
+}
+
+// EnabledDefinitions 返回启用的属性定义，select 类型附带字典可选项，供用户模块校验与展示
+func (s *Service) EnabledDefinitions(ctx context.Context) ([]Definition, error) {
+	return s.ListDefinitions(ctx, QueryOptions{Status: "0"})
+}
+
+func (s *Service) GetDefinition(ctx context.Context, id int64) (*Definition, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	record, err := s.repo.GetDefinition(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defs, err := s.withOptions(ctx, []model.SysUserAttrDef{*record})
+	if err != nil {
+		return nil, err
+	}
+	return &defs[0], nil
+}
+
+func (s *Service) CreateDefinition(ctx context.Context, input CreateDefinitionInput) (*Definition, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	key := strings.TrimSpace(input.AttrKey)
+	if key == "" {
+		return nil, ErrAttrKeyRequired
+	}
+	if !attrKeyPattern.MatchString(key) {
+		return nil, ErrInvalidAttrKey
+	}
+
+	attrType := strings.ToLower(strings.TrimSpace(input.AttrType))
+	if attrType == "" {
+		attrType = TypeString
+	}
+	if _, ok := validTypes[attrType]; !ok {
+		return nil, ErrInvalidAttrType
+	}
+
+	status := normalizeStatus(input.Status)
+	if _, ok := validStatuses[status]; !ok {
+		return nil, ErrInvalidAttrStatus
+	}
+
+	operator := strings.TrimSpace(input.Operator)
+	record := &model.SysUserAttrDef{
+		AttrKey:   key,
+		AttrLabel: strings.TrimSpace(input.AttrLabel),
+		AttrType:  attrType,
+		Required:  input.Required,
+		Pattern:   normalizeOptional(input.Pattern),
+		MaxLength: input.MaxLength,
+		MinValue:  input.MinValue,
+		MaxValue:  input.MaxValue,
+		DictType:  normalizeOptional(input.DictType),
+		SortOrder: input.SortOrder,
+		Status:    status,
+		Remark:    normalizeOptional(input.Remark),
+		CreateBy:  operator,
+		UpdateBy:  operator,
+	}
+	if err := s.validateDefinition(ctx, record); err != nil {
+		return nil, err
+	}
+
+	if exists, err := s.repo.ExistsByKey(ctx, key); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, ErrDuplicateAttrKey
+	}
+
+	if err := s.repo.CreateDefinition(ctx, record); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrDuplicateAttrKey
+		}
+		return nil, err
+	}
+	return s.GetDefinition(ctx, int64(record.ID))
+}
+
+func (s *Service) UpdateDefinition(ctx context.Context, input UpdateDefinitionInput) (*Definition, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrServiceUnavailable
+	}
+
+	record, err := s.repo.GetDefinition(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.AttrLabel != nil {
+		record.AttrLabel = strings.TrimSpace(*input.AttrLabel)
+	}
+	if input.Required != nil {
+		record.Required = *input.Required
+	}
+	if input.Pattern != nil {
+		record.Pattern = normalizeOptional(input.Pattern)
+	}
+	if input.MaxLength != nil {
+		record.MaxLength = *input.MaxLength
+	}
+	if input.MinValue != nil {
+		record.MinValue = input.MinValue
+	}
+	if input.MaxValue != nil {
+		record.MaxValue = input.MaxValue
+	}
+	if input.DictType != nil {
+		record.DictType = normalizeOptional(input.DictType)
+	}
+	if input.SortOrder != nil {
+		record.SortOrder = *input.SortOrder
+	}
+	if input.Status != nil {
+		status := normalizeStatus(*input.Status)
+		if _, ok := validStatuses[status]; !ok {
+			return nil, ErrInvalidAttrStatus
+		}
+		record.Status = status
+	}
+	if input.Remark != nil {
+		record.Remark = normalizeOptional(input.Remark)
+	}
+
+	if err := s.validateDefinition(ctx, record); err != nil {
+		return nil, err
+	}
+
+	record.UpdateBy = strings.TrimSpace(input.Operator)
+	if err := s.repo.SaveDefinition(ctx, record); err != nil {
+		return nil, err
+	}
+	return s.GetDefinition(ctx, input.ID)
+}
+
+// DeleteDefinition 删除属性定义，同时清除所有用户在该属性上的取值
+func (s *Service) DeleteDefinition(ctx context.Context, id int64) error {
+	if s == nil || s.repo == nil {
+		return ErrServiceUnavailable
+	}
+
+	record, err := s.repo.GetDefinition(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteDefinition(ctx, record)
+}
+
+func (s *Service) validateDefinition(ctx context.Context, record *model.SysUserAttrDef) error {
+	if record.AttrLabel == "" {
+		return ErrAttrLabelRequired
+	}
+	if record.Pattern != nil {
+		if _, err := regexp.Compile(*record.Pattern); err != nil {
+			return ErrInvalidPattern
+		}
+	}
+	if record.MaxLength < 0 || record.MaxLength > maxValueLength {
+		record.MaxLength = 0
+	}
+	if record.MinValue != nil && record.MaxValue != nil && *record.MinValue > *record.MaxValue {
+		return ErrInvalidRange
+	}
+
+	if record.AttrType != TypeSelect {
+		record.DictType = nil
+		return nil
+	}
+	if record.DictType == nil {
+		return ErrDictTypeRequired
+	}
+	exists, err := s.repo.DictTypeExists(ctx, *record.DictType)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrDictTypeNotFound
+	}
+	return nil
+}
+
+func (s *Service) withOptions(ctx context.Context, records []model.SysUserAttrDef) ([]Definition, error) {
+	dictTypes := make([]string, 0)
+	for _, record := range records {
+		if record.AttrType == TypeSelect && record.DictType != nil {
+			dictTypes = append(dictTypes, *record.DictType)
+		}
+	}
+	options, err := s.repo.ListDictOptions(ctx, dictTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := make([]Definition, 0, len(records))
+	for i := range records {
+		def := definitionFromModel(&records[i])
+		if def.DictType != nil {
+			for _, item := range options[*def.DictType] {
+				def.Options = append(def.Options, Option{Value: item.DictValue, Label: item.DictLabel})
+			}
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// Normalize 校验原始取值并转换为存储格式；空字符串表示未填写。
+// select 类型同时接受字典值与字典标签，便于导入文件直接填写标签。
+func (d Definition) Normalize(raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return "", nil
+	}
+
+	switch d.AttrType {
+	case TypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", d.invalid("must be a number")
+		}
+		if d.MinValue != nil && number < *d.MinValue {
+			return "", d.invalid(fmt.Sprintf("must be at least %s", formatNumber(*d.MinValue)))
+		}
+		if d.MaxValue != nil && number > *d.MaxValue {
+			return "", d.invalid(fmt.Sprintf("must be at most %s", formatNumber(*d.MaxValue)))
+		}
+		return formatNumber(number), nil
+	case TypeBoolean:
+		normalized, ok := booleanValues[strings.ToLower(value)]
+		if !ok {
+			return "", d.invalid("must be true or false")
+		}
+		return normalized, nil
+	case TypeDate:
+		parsed, err := time.Parse(DateLayout, value)
+		if err != nil {
+			return "", d.invalid("must be a date in YYYY-MM-DD format")
+		}
+		return parsed.Format(DateLayout), nil
+	case TypeSelect:
+		for _, option := range d.Options {
+			if option.Value == value {
+				return value, nil
+			}
+		}
+		for _, option := range d.Options {
+			if option.Label == value {
+				return option.Value, nil
+			}
+		}
+		return "", d.invalid(fmt.Sprintf("%q is not an allowed option", value))
+	}
+
+	limit := maxValueLength
+	if d.MaxLength > 0 {
+		limit = d.MaxLength
+	}
+	if utf8.RuneCountInString(value) > limit {
+		return "", d.invalid(fmt.Sprintf("must be at most %d characters", limit))
+	}
+	if d.pattern != nil && !d.pattern.MatchString(value) {
+		return "", d.invalid("does not match the required format")
+	}
+	return value, nil
+}
+
+// Display 返回取值的展示文本，select 类型输出字典标签
+func (d Definition) Display(value string) string {
+	if d.AttrType != TypeSelect {
+		return value
+	}
+	for _, option := range d.Options {
+		if option.Value == value {
+			return option.Label
+		}
+	}
+	return value
+}
+
+func (d Definition) invalid(reason string) error {
+	return fmt.Errorf("%w: %s %s", ErrInvalidAttributeVal, d.AttrKey, reason)
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func normalizeStatus(status string) string {
+	trimmed := strings.TrimSpace(status)
+	if trimmed == "" {
+		return "0"
+	}
+	return trimmed
+}
+
+func normalizeOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func definitionFromModel(record *model.SysUserAttrDef) Definition {
+	updatedAt := record.UpdatedAt
+	def := Definition{
+		ID:        int64(record.ID),
+		AttrKey:   record.AttrKey,
+		AttrLabel: record.AttrLabel,
+		AttrType:  record.AttrType,
+		Required:  record.Required,
+		Pattern:   record.Pattern,
+		MaxLength: record.MaxLength,
+		MinValue:  record.MinValue,
+		MaxValue:  record.MaxValue,
+		DictType:  record.DictType,
+		SortOrder: record.SortOrder,
+		Status:    record.Status,
+		Remark:    record.Remark,
+		UpdatedAt: &updatedAt,
+	}
+	// 表达式在写入时已校验
+	if record.Pattern != nil {
+		def.pattern, _ = regexp.Compile(*record.Pattern)
+	}
+	return def
+}
@@ -4,6 +4,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/starter-kit-fe/admin/pkg/tenant"
 )
 
 const (
@@ -11,6 +13,7 @@ const (
 	contextKeyClaims  = "auth.claims"
 	contextKeyPermSet = "auth.permissions"
 	ContextKeySession = "auth.session_id"
+	ContextKeyTenant  = "auth.tenant_id"
 )
 
 type permissionSet struct {
@@ -30,6 +33,12 @@ func setSessionID(ctx *gin.Context, sessionID string) {
 	ctx.Set(ContextKeySession, strings.TrimSpace(sessionID))
 }
 
+// setTenantID 记录当前租户，并让后续数据库访问限定在该租户内
+func setTenantID(ctx *gin.Context, tenantID int64) {
+	ctx.Set(ContextKeyTenant, tenantID)
+	ctx.Request = ctx.Request.WithContext(tenant.WithID(ctx.Request.Context(), tenantID))
+}
+
 func setPermissions(ctx *gin.Context, permissions []string) {
 	set := &permissionSet{
 		values: make(map[string]struct{}, len(permissions)),
@@ -107,3 +116,12 @@ func GetSessionID(ctx *gin.Context) (string, bool) {
 	sessionID, ok := value.(string)
 	return strings.TrimSpace(sessionID), ok
 }
+
+func GetTenantID(ctx *gin.Context) (int64, bool) {
+	value, ok := ctx.Get(ContextKeyTenant)
+	if !ok {
+		return 0, false
+	}
+	tenantID, ok := value.(int64)
+	return tenantID, ok && tenantID > 0
+}
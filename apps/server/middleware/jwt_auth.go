@@ -12,6 +12,7 @@ import (
 	jwtpkg "github.com/starter-kit-fe/admin/pkg/jwt"
 	"github.com/starter-kit-fe/admin/pkg/resp"
 	"github.com/starter-kit-fe/admin/pkg/security"
+	"github.com/starter-kit-fe/admin/pkg/tenant"
 )
 
 type PermissionProvider interface {
@@ -28,8 +29,9 @@ type SessionValidator interface {
 }
 
 type SessionMetadata struct {
-	UserID  uint
-	Revoked bool
+	UserID   uint
+	TenantID int64
+	Revoked  bool
 }
 
 type JWTAuthOptions struct {
@@ -119,7 +121,8 @@ func NewJWTAuthMiddleware(options JWTAuthOptions) gin.HandlerFunc {
 				ctx.Abort()
 				return
 			}
-			if record.Revoked || record.UserID != claims.ID {
+			// 切换租户后，签发给原租户的令牌随之失效
+			if record.Revoked || record.UserID != claims.ID || normalizeTenantID(record.TenantID) != normalizeTenantID(claims.TenantID) {
 				resp.Unauthorized(ctx, resp.WithMessage("session revoked"))
 				ctx.Abort()
 				return
@@ -147,11 +150,20 @@ func NewJWTAuthMiddleware(options JWTAuthOptions) gin.HandlerFunc {
 		} else {
 			setPermissions(ctx, nil)
 		}
+		// 权限按用户所属租户加载完成后再限定数据访问范围
+		setTenantID(ctx, normalizeTenantID(claims.TenantID))
 
 		ctx.Next()
 	}
 }
 
+func normalizeTenantID(tenantID int64) int64 {
+	if tenantID <= 0 {
+		return tenant.DefaultID
+	}
+	return tenantID
+}
+
 func extractToken(ctx *gin.Context, cookieName string) string {
 	header := strings.TrimSpace(ctx.GetHeader("Authorization"))
 	if header != "" {
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/starter-kit-fe/admin/pkg/requestid"
+)
+
+const ContextKeyRequestID = "request.id"
+
+const maxRequestIDLength = 64
+
+// RequestID 为每个请求分配关联 ID，优先沿用网关传入的 X-Request-ID，并在响应头中回写
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := strings.TrimSpace(ctx.GetHeader(requestid.Header))
+		if !validRequestID(id) {
+			id = requestid.New()
+		}
+		ctx.Set(ContextKeyRequestID, id)
+		ctx.Header(requestid.Header, id)
+		ctx.Request = ctx.Request.WithContext(requestid.WithID(ctx.Request.Context(), id))
+		ctx.Next()
+	}
+}
+
+// GetRequestID 返回当前请求的关联 ID
+func GetRequestID(ctx *gin.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	return ctx.GetString(ContextKeyRequestID)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
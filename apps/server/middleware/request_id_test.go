@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/starter-kit-fe/admin/pkg/requestid"
+)
+
+func TestRequestID_GeneratesAndPropagates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+
+	var seen string
+	router.GET("/", func(ctx *gin.Context) {
+		seen = requestid.FromContext(ctx.Request.Context())
+		ctx.Status(http.StatusOK)
+	})
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if seen == "" {
+		t.Fatalf("expected request id in request context")
+	}
+	if got := resp.Header().Get(requestid.Header); got != seen {
+		t.Fatalf("expected response header %q, got %q", seen, got)
+	}
+}
+
+func TestRequestID_ReusesValidIncomingHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, GetRequestID(ctx))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestid.Header, "gateway-123")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Body.String() != "gateway-123" {
+		t.Fatalf("expected incoming id to be reused, got %q", resp.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestid.Header, "bad id\n"+strings.Repeat("x", 80))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if got := resp.Body.String(); got == "" || strings.Contains(got, "bad") {
+		t.Fatalf("expected invalid id to be replaced, got %q", got)
+	}
+}
@@ -24,6 +24,7 @@ func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
 			"status", ctx.Writer.Status(),
 			"duration", time.Since(start).String(),
 			"ip", netutil.RealIPFromContext(ctx),
+			"request_id", GetRequestID(ctx),
 		)
 	}
 }
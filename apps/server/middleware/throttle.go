@@ -19,60 +19,89 @@ type clientLimiter struct {
 	lastSeen time.Time
 }
 
-func NewThrottleMiddleware(limit rate.Limit, burst int, keyFn KeyFunc, logger *slog.Logger) gin.HandlerFunc {
-	if limit <= 0 {
-		return func(ctx *gin.Context) {
-			ctx.Next()
-		}
+// Throttle limits requests per client key. The limit can be changed while the
+// server is running; a non-positive limit disables throttling.
+type Throttle struct {
+	mu       sync.Mutex
+	limit    rate.Limit
+	burst    int
+	keyFn    KeyFunc
+	logger   *slog.Logger
+	limiters map[string]*clientLimiter
+}
+
+func NewThrottle(limit rate.Limit, burst int, keyFn KeyFunc, logger *slog.Logger) *Throttle {
+	t := &Throttle{
+		keyFn:    keyFn,
+		logger:   logger,
+		limiters: make(map[string]*clientLimiter),
 	}
+	t.SetLimit(limit, burst)
+	return t
+}
 
+func NewThrottleMiddleware(limit rate.Limit, burst int, keyFn KeyFunc, logger *slog.Logger) gin.HandlerFunc {
+	return NewThrottle(limit, burst, keyFn, logger).Handler()
+}
+
+// SetLimit replaces the limit and burst, including for clients already being tracked.
+func (t *Throttle) SetLimit(limit rate.Limit, burst int) {
 	if burst <= 0 {
 		burst = 1
 	}
 
-	var (
-		mu       sync.Mutex
-		limiters = make(map[string]*clientLimiter)
-	)
-
-	cleanup := func() {
-		const ttl = 15 * time.Minute
-		now := time.Now()
-		for key, entry := range limiters {
-			if now.Sub(entry.lastSeen) > ttl {
-				delete(limiters, key)
-			}
-		}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limit = limit
+	t.burst = burst
+	now := time.Now()
+	for _, entry := range t.limiters {
+		entry.limiter.SetLimitAt(now, limit)
+		entry.limiter.SetBurstAt(now, burst)
 	}
+}
+
+// Limit reports the current limit and burst.
+func (t *Throttle) Limit() (rate.Limit, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limit, t.burst
+}
 
+func (t *Throttle) Handler() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		key := "global"
-		if keyFn != nil {
-			if v := keyFn(ctx); v != "" {
+		if t.keyFn != nil {
+			if v := t.keyFn(ctx); v != "" {
 				key = v
 			}
 		} else if ip := netutil.RealIPFromContext(ctx); ip != "" {
 			key = ip
 		}
 
-		mu.Lock()
-		entry, exists := limiters[key]
+		t.mu.Lock()
+		if t.limit <= 0 {
+			t.mu.Unlock()
+			ctx.Next()
+			return
+		}
+		entry, exists := t.limiters[key]
 		if !exists {
 			entry = &clientLimiter{
-				limiter:  rate.NewLimiter(limit, burst),
+				limiter:  rate.NewLimiter(t.limit, t.burst),
 				lastSeen: time.Now(),
 			}
-			limiters[key] = entry
+			t.limiters[key] = entry
 		}
 		entry.lastSeen = time.Now()
-		if len(limiters) > 1024 {
-			cleanup()
+		if len(t.limiters) > 1024 {
+			t.cleanup()
 		}
-		mu.Unlock()
+		t.mu.Unlock()
 
 		if !entry.limiter.Allow() {
-			if logger != nil {
-				logger.Warn("request throttled", "key", key)
+			if t.logger != nil {
+				t.logger.Warn("request throttled", "key", key)
 			}
 			resp.TooManyRequests(ctx, resp.WithMessage("too many requests"))
 			ctx.Abort()
@@ -82,3 +111,14 @@ func NewThrottleMiddleware(limit rate.Limit, burst int, keyFn KeyFunc, logger *s
 		ctx.Next()
 	}
 }
+
+// cleanup drops idle clients; the caller must hold t.mu.
+func (t *Throttle) cleanup() {
+	const ttl = 15 * time.Minute
+	now := time.Now()
+	for key, entry := range t.limiters {
+		if now.Sub(entry.lastSeen) > ttl {
+			delete(t.limiters, key)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+func TestThrottle_SetLimitAppliesToTrackedClients(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	throttle := NewThrottle(rate.Limit(0.001), 1, func(*gin.Context) string { return "client" }, nil)
+	router := gin.New()
+	router.GET("/", throttle.Handler(), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	call := func() int {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+		return resp.Code
+	}
+
+	if code := call(); code != http.StatusOK {
+		t.Fatalf("expected first request to pass, got %d", code)
+	}
+	if code := call(); code != http.StatusTooManyRequests {
+		t.Fatalf("expected second request to be throttled, got %d", code)
+	}
+
+	throttle.SetLimit(rate.Inf, 10)
+	if code := call(); code != http.StatusOK {
+		t.Fatalf("expected raised limit to apply to the existing client, got %d", code)
+	}
+
+	throttle.SetLimit(0, 0)
+	for i := 0; i < 3; i++ {
+		if code := call(); code != http.StatusOK {
+			t.Fatalf("expected disabled throttle to pass, got %d", code)
+		}
+	}
+}
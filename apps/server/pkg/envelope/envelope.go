@@ -0,0 +1,262 @@
+// Package envelope encrypts small values such as passwords and API keys with
+// envelope encryption: every value gets a fresh data key, the value is sealed
+// with AES-256-GCM under that data key, and the data key is in turn sealed
+// with a key-encryption key (KEK) from a Keyring. Rotating the KEK only
+// requires re-wrapping the data keys, never the values themselves.
+//
+// Sealed values are printable strings of the form
+//
+//	enc:v1:<kek id>:<wrapped data key>:<ciphertext>
+//
+// where both binary parts are unpadded base64url.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	prefix  = "enc:v1:"
+	keySize = 32
+)
+
+var (
+	ErrNoKeys     = errors.New("envelope: keyring has no keys")
+	ErrInvalidKey = errors.New("envelope: invalid key")
+	ErrUnknownKey = errors.New("envelope: value was sealed with an unknown key")
+	ErrMalformed  = errors.New("envelope: malformed sealed value")
+	ErrDecrypt    = errors.New("envelope: decryption failed")
+)
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+var encoding = base64.RawURLEncoding
+
+// Keyring holds the key-encryption keys. The active key seals new values;
+// every key can open values sealed with it.
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// ParseKeyring builds a keyring from "id:base64key" specs. The first spec is
+// the active key; the remaining ones are kept to open older values during a
+// rotation. Keys must decode to 32 bytes (standard or URL base64).
+func ParseKeyring(specs []string) (*Keyring, error) {
+	if len(specs) == 0 {
+		return nil, ErrNoKeys
+	}
+	keys := make(map[string][]byte, len(specs))
+	order := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(spec), ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: expected id:base64key", ErrInvalidKey)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %v", ErrInvalidKey, id, err)
+		}
+		keys[id] = key
+		order = append(order, id)
+	}
+	return NewKeyring(order[0], keys)
+}
+
+// NewKeyring builds a keyring from raw 32-byte keys.
+func NewKeyring(activeID string, keys map[string][]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("%w: active key %q is missing", ErrInvalidKey, activeID)
+	}
+	ring := &Keyring{active: activeID, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("%w: key id %q must be 1-32 letters, digits, '-' or '_'", ErrInvalidKey, id)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %v", ErrInvalidKey, id, err)
+		}
+		ring.keys[id] = aead
+	}
+	return ring, nil
+}
+
+// GenerateKey returns a random key encoded for use in a keyring spec.
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ActiveID reports the id of the key used to seal new values.
+func (k *Keyring) ActiveID() string {
+	if k == nil {
+		return ""
+	}
+	return k.active
+}
+
+// IsSealed reports whether value looks like the output of Seal.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Seal encrypts plaintext under a fresh data key wrapped by the active key.
+func (k *Keyring) Seal(plaintext []byte) (string, error) {
+	if k == nil || len(k.keys) == 0 {
+		return "", ErrNoKeys
+	}
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataAEAD, plaintext, nil)
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := seal(k.keys[k.active], dataKey, []byte(k.active))
+	if err != nil {
+		return "", err
+	}
+	return prefix + k.active + ":" + encoding.EncodeToString(wrapped) + ":" + encoding.EncodeToString(ciphertext), nil
+}
+
+// Open decrypts a value produced by Seal with any key in the keyring.
+func (k *Keyring) Open(sealed string) ([]byte, error) {
+	parts, err := split(sealed)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := k.unwrap(parts)
+	if err != nil {
+		return nil, err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := open(dataAEAD, parts.ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// Rewrap re-seals the data key of a value with the active key. The value
+// itself is not re-encrypted. It reports false when the value already uses
+// the active key.
+func (k *Keyring) Rewrap(sealed string) (string, bool, error) {
+	parts, err := split(sealed)
+	if err != nil {
+		return "", false, err
+	}
+	if parts.keyID == k.active {
+		return sealed, false, nil
+	}
+	dataKey, err := k.unwrap(parts)
+	if err != nil {
+		return "", false, err
+	}
+	wrapped, err := seal(k.keys[k.active], dataKey, []byte(k.active))
+	if err != nil {
+		return "", false, err
+	}
+	return prefix + k.active + ":" + encoding.EncodeToString(wrapped) + ":" + encoding.EncodeToString(parts.ciphertext), true, nil
+}
+
+type sealedParts struct {
+	keyID      string
+	wrapped    []byte
+	ciphertext []byte
+}
+
+func split(sealed string) (sealedParts, error) {
+	if !IsSealed(sealed) {
+		return sealedParts{}, ErrMalformed
+	}
+	fields := strings.Split(strings.TrimPrefix(sealed, prefix), ":")
+	if len(fields) != 3 || fields[0] == "" {
+		return sealedParts{}, ErrMalformed
+	}
+	wrapped, err := encoding.DecodeString(fields[1])
+	if err != nil {
+		return sealedParts{}, ErrMalformed
+	}
+	ciphertext, err := encoding.DecodeString(fields[2])
+	if err != nil {
+		return sealedParts{}, ErrMalformed
+	}
+	return sealedParts{keyID: fields[0], wrapped: wrapped, ciphertext: ciphertext}, nil
+}
+
+func (k *Keyring) unwrap(parts sealedParts) ([]byte, error) {
+	if k == nil {
+		return nil, ErrNoKeys
+	}
+	kek, ok := k.keys[parts.keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, parts.keyID)
+	}
+	dataKey, err := open(kek, parts.wrapped, []byte(parts.keyID))
+	if err != nil || len(dataKey) != keySize {
+		return nil, ErrDecrypt
+	}
+	return dataKey, nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(encoded); err == nil {
+			if len(key) != keySize {
+				return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+			}
+			return key, nil
+		}
+	}
+	return nil, errors.New("key is not valid base64")
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+func open(aead cipher.AEAD, sealed, additional []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additional)
+}
@@ -0,0 +1,112 @@
+package envelope
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mustKeyring(t *testing.T, specs ...string) *Keyring {
+	t.Helper()
+	ring, err := ParseKeyring(specs)
+	if err != nil {
+		t.Fatalf("parse keyring: %v", err)
+	}
+	return ring
+}
+
+func mustKey(t *testing.T) string {
+	t.Helper()
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key
+}
+
+func TestSealAndOpen(t *testing.T) {
+	ring := mustKeyring(t, "k1:"+mustKey(t))
+
+	sealed, err := ring.Seal([]byte("smtp-password"))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if !IsSealed(sealed) || strings.Contains(sealed, "smtp-password") {
+		t.Fatalf("unexpected sealed value %q", sealed)
+	}
+	again, _ := ring.Seal([]byte("smtp-password"))
+	if again == sealed {
+		t.Fatal("expected every seal to use a fresh data key and nonce")
+	}
+
+	plaintext, err := ring.Open(sealed)
+	if err != nil || string(plaintext) != "smtp-password" {
+		t.Fatalf("open: %q %v", plaintext, err)
+	}
+}
+
+func TestOpenRejectsTamperedValues(t *testing.T) {
+	ring := mustKeyring(t, "k1:"+mustKey(t))
+	sealed, _ := ring.Seal([]byte("secret"))
+
+	// Flip a character inside the ciphertext; the trailing character may only carry padding bits.
+	pos := len(sealed) - 5
+	replacement := byte('A')
+	if sealed[pos] == 'A' {
+		replacement = 'B'
+	}
+	tampered := sealed[:pos] + string(replacement) + sealed[pos+1:]
+	if _, err := ring.Open(tampered); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected tampered ciphertext to fail, got %v", err)
+	}
+	if _, err := ring.Open("enc:v1:k1:broken"); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected malformed value to fail, got %v", err)
+	}
+	other := mustKeyring(t, "k2:"+mustKey(t))
+	if _, err := other.Open(sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+	// A different key registered under the same id cannot unwrap the data key.
+	impostor := mustKeyring(t, "k1:"+mustKey(t))
+	if _, err := impostor.Open(sealed); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected wrong key to fail, got %v", err)
+	}
+}
+
+func TestRewrapMovesValuesToActiveKey(t *testing.T) {
+	oldKey, newKey := mustKey(t), mustKey(t)
+	before := mustKeyring(t, "old:"+oldKey)
+	sealed, _ := before.Seal([]byte("webhook-secret"))
+
+	during := mustKeyring(t, "new:"+newKey, "old:"+oldKey)
+	rewrapped, changed, err := during.Rewrap(sealed)
+	if err != nil || !changed {
+		t.Fatalf("rewrap: changed=%v err=%v", changed, err)
+	}
+	if !strings.HasPrefix(rewrapped, "enc:v1:new:") {
+		t.Fatalf("expected value to use the new key, got %q", rewrapped)
+	}
+	if _, changed, _ := during.Rewrap(rewrapped); changed {
+		t.Fatal("expected rewrapping an up-to-date value to be a no-op")
+	}
+
+	after := mustKeyring(t, "new:"+newKey)
+	plaintext, err := after.Open(rewrapped)
+	if err != nil || string(plaintext) != "webhook-secret" {
+		t.Fatalf("open after rotation: %q %v", plaintext, err)
+	}
+}
+
+func TestParseKeyringValidatesKeys(t *testing.T) {
+	for _, specs := range [][]string{
+		nil,
+		{"missing-separator"},
+		{"k1:not-base64!"},
+		{"k1:c2hvcnQ="},
+		{"bad id:" + mustKey(t)},
+	} {
+		if _, err := ParseKeyring(specs); err == nil {
+			t.Fatalf("expected %v to be rejected", specs)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Package etag derives entity tags from a record's last modification time so
+// update endpoints can reject writes based on a stale read, and from response
+// content so read endpoints can answer conditional GETs with 304.
+//
+// If-Match is required on guarded writes: Bind rejects a request without it
+// with ErrPreconditionRequired (428), otherwise it carries the header to the
+// repository and Guard turns it into a condition on the UPDATE itself, so a
+// stale version fails (412) even when two requests race. Clients that do not
+// care about lost updates send "If-Match: *".
+package etag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// ErrPreconditionRequired is returned by Bind when a guarded write carries no
+// If-Match header.
+var ErrPreconditionRequired = errors.New("If-Match header is required")
+
+// ErrPreconditionFailed is returned by a guarded write when the stored record
+// no longer matches any version named by If-Match.
+var ErrPreconditionFailed = errors.New("record has been modified")
+
+type preconditionKey struct{}
+
+// Format renders a strong entity tag for a record last modified at updatedAt.
+// Microsecond precision matches what the database keeps.
+func Format(updatedAt time.Time) string {
+	return `"` + strconv.FormatInt(updatedAt.UnixMicro(), 36) + `"`
+}
+
+// Set writes the entity tag of the record to the response.
+func Set(ctx *gin.Context, updatedAt time.Time) {
+	if ctx == nil {
+		return
+	}
+	ctx.Header(HeaderETag, Format(updatedAt))
+}
+
+// Conditional reports whether the request carries an If-Match precondition.
+func Conditional(ctx *gin.Context) bool {
+	if ctx == nil || ctx.Request == nil {
+		return false
+	}
+	return strings.TrimSpace(ctx.GetHeader(HeaderIfMatch)) != ""
+}
+
+// Match reports whether the If-Match header accepts a record last modified at
+// updatedAt. "*" matches any existing record; weak tags never match.
+func Match(ctx *gin.Context, updatedAt time.Time) bool {
+	if !Conditional(ctx) {
+		return true
+	}
+	return matches(ctx.GetHeader(HeaderIfMatch), Format(updatedAt))
+}
+
+// Bind stores the request's If-Match header in its context so that Guard can
+// enforce it inside the write. A request without If-Match is rejected with
+// ErrPreconditionRequired and its context is left untouched.
+func Bind(ctx *gin.Context) error {
+	if !Conditional(ctx) {
+		return ErrPreconditionRequired
+	}
+	ctx.Request = ctx.Request.WithContext(WithPrecondition(ctx.Request.Context(), ctx.GetHeader(HeaderIfMatch)))
+	return nil
+}
+
+// WithPrecondition returns a context carrying an If-Match header value.
+func WithPrecondition(ctx context.Context, ifMatch string) context.Context {
+	return context.WithValue(ctx, preconditionKey{}, strings.TrimSpace(ifMatch))
+}
+
+func precondition(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	header, ok := ctx.Value(preconditionKey{}).(string)
+	return header, ok && header != ""
+}
+
+// Guard restricts an UPDATE or DELETE to the versions named by the If-Match
+// header bound to the statement context, matching updated_at at microsecond
+// precision like Format. With "*", or without a precondition as for writes
+// issued outside a request (jobs, manifest sync, recycle bin), tx is returned
+// unchanged; a header naming no usable tag matches nothing.
+func Guard(tx *gorm.DB) *gorm.DB {
+	header, ok := precondition(tx.Statement.Context)
+	if !ok {
+		return tx
+	}
+
+	conditions := make([]string, 0, 1)
+	args := make([]interface{}, 0, 2)
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return tx
+		}
+		at, ok := parse(candidate)
+		if !ok {
+			continue
+		}
+		conditions = append(conditions, "(updated_at >= ? AND updated_at < ?)")
+		args = append(args, at, at.Add(time.Microsecond))
+	}
+	if len(conditions) == 0 {
+		return tx.Where("1 = 0")
+	}
+	return tx.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// Missed explains a guarded write that affected no rows: the record identified
+// by id is gone (gorm.ErrRecordNotFound) or its version has moved on
+// (ErrPreconditionFailed).
+func Missed(tx *gorm.DB, model interface{}, id interface{}) error {
+	if _, ok := precondition(tx.Statement.Context); !ok {
+		return gorm.ErrRecordNotFound
+	}
+	var count int64
+	if err := tx.Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return ErrPreconditionFailed
+}
+
+func parse(tag string) (time.Time, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return time.Time{}, false
+	}
+	micros, err := strconv.ParseInt(tag[1:len(tag)-1], 36, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMicro(micros), true
+}
+
+func matches(header, current string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == current {
+			return true
+		}
+	}
+	return false
+}
+
+// Hash renders a strong entity tag for a response body or any other content
+// whose identity is its bytes.
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// NotModified reports whether the If-None-Match header already names tag, in
+// which case the client's cached copy is current. Per RFC 9110 the comparison
+// is weak, so "W/" prefixes added by intermediaries are ignored.
+func NotModified(ctx *gin.Context, tag string) bool {
+	if ctx == nil || ctx.Request == nil {
+		return false
+	}
+	header := strings.TrimSpace(ctx.GetHeader(HeaderIfNoneMatch))
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package etag
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type versionedItem struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	UpdatedAt time.Time
+}
+
+func newContext(ifMatch string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("PUT", "/", nil)
+	if ifMatch != "" {
+		ctx.Request.Header.Set(HeaderIfMatch, ifMatch)
+	}
+	return ctx
+}
+
+func TestFormatIgnoresSubMicrosecondPrecision(t *testing.T) {
+	base := time.Date(2024, 5, 1, 8, 0, 0, 123456000, time.UTC)
+	if Format(base) != Format(base.Add(789*time.Nanosecond)) {
+		t.Fatalf("expected tags to ignore nanoseconds")
+	}
+	if Format(base) == Format(base.Add(time.Microsecond)) {
+		t.Fatalf("expected tags to differ across microseconds")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	current := Format(updatedAt)
+	stale := Format(updatedAt.Add(-time.Second))
+
+	cases := []struct {
+		name    string
+		ifMatch string
+		want    bool
+	}{
+		{name: "absent", ifMatch: "", want: true},
+		{name: "current", ifMatch: current, want: true},
+		{name: "wildcard", ifMatch: "*", want: true},
+		{name: "list", ifMatch: stale + ", " + current, want: true},
+		{name: "stale", ifMatch: stale, want: false},
+		{name: "weak", ifMatch: "W/" + current, want: false},
+	}
+	for _, tc := range cases {
+		if got := Match(newContext(tc.ifMatch), updatedAt); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestBind(t *testing.T) {
+	ctx := newContext("")
+	if err := Bind(ctx); !errors.Is(err, ErrPreconditionRequired) {
+		t.Fatalf("expected missing If-Match to be rejected, got %v", err)
+	}
+	if _, ok := precondition(ctx.Request.Context()); ok {
+		t.Fatalf("expected rejected request to carry no precondition")
+	}
+
+	ctx = newContext(`"abc"`)
+	if err := Bind(ctx); err != nil {
+		t.Fatalf("expected If-Match to bind, got %v", err)
+	}
+	if header, ok := precondition(ctx.Request.Context()); !ok || header != `"abc"` {
+		t.Fatalf("expected bound precondition, got %q", header)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	tag := Hash([]byte(`{"sys_user_sex":[]}`))
+	if tag != Hash([]byte(`{"sys_user_sex":[]}`)) || tag == Hash([]byte(`{}`)) {
+		t.Fatalf("expected hash tags to follow content")
+	}
+
+	cases := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{name: "absent", ifNoneMatch: "", want: false},
+		{name: "current", ifNoneMatch: tag, want: true},
+		{name: "weak", ifNoneMatch: "W/" + tag, want: true},
+		{name: "list", ifNoneMatch: `"stale", ` + tag, want: true},
+		{name: "wildcard", ifNoneMatch: "*", want: true},
+		{name: "stale", ifNoneMatch: `"stale"`, want: false},
+	}
+	for _, tc := range cases {
+		ctx := newContext("")
+		ctx.Request.Method = "GET"
+		if tc.ifNoneMatch != "" {
+			ctx.Request.Header.Set(HeaderIfNoneMatch, tc.ifNoneMatch)
+		}
+		if got := NotModified(ctx, tag); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestGuard(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if err := db.AutoMigrate(&versionedItem{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	item := versionedItem{Name: "a", UpdatedAt: time.Now()}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("failed to create item: %v", err)
+	}
+	current := Format(item.UpdatedAt)
+	stale := Format(item.UpdatedAt.Add(-time.Second))
+
+	update := func(ifMatch, name string) error {
+		ctx := context.Background()
+		if ifMatch != "" {
+			ctx = WithPrecondition(ctx, ifMatch)
+		}
+		tx := db.WithContext(ctx)
+		result := Guard(tx.Model(&versionedItem{}).Where("id = ?", item.ID)).Update("name", name)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return Missed(tx, &versionedItem{}, item.ID)
+		}
+		return nil
+	}
+
+	if err := update(stale, "stale"); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected stale tag to fail, got %v", err)
+	}
+	if err := update(`"not-a-tag!"`, "garbage"); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected unusable tag to fail, got %v", err)
+	}
+	if err := update(stale+", "+current, "list"); err != nil {
+		t.Fatalf("expected current tag in list to match, got %v", err)
+	}
+	// The previous write moved updated_at on, so the same tag cannot apply twice.
+	if err := update(current, "again"); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected consumed tag to fail, got %v", err)
+	}
+	if err := update("*", "wildcard"); err != nil {
+		t.Fatalf("expected wildcard to match, got %v", err)
+	}
+	if err := update("", "unconditional"); err != nil {
+		t.Fatalf("expected unconditional write to apply, got %v", err)
+	}
+
+	item.ID++
+	if err := update(current, "missing"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected missing record, got %v", err)
+	}
+}
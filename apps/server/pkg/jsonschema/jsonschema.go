@@ -0,0 +1,616 @@
+// Package jsonschema validates JSON documents against a practical subset of
+// JSON Schema (draft 2020-12) using only the standard library.
+//
+// Supported keywords: type, enum, const, properties, required,
+// additionalProperties, items, minItems, maxItems, uniqueItems, minLength,
+// maxLength, pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum,
+// multipleOf, minProperties, maxProperties, allOf, anyOf, oneOf and not.
+// Annotation keywords such as title, description and default are accepted and
+// ignored. Every other keyword, including references ($ref) and format, is
+// rejected at compile time rather than silently skipped.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidSchema = errors.New("invalid json schema")
+
+// ValidationError reports the first location at which a document does not
+// conform to the schema. Path is a JSON pointer into the document.
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	return path + ": " + e.Message
+}
+
+// Schema is a compiled schema ready for validation.
+type Schema struct {
+	// boolean schemas: true accepts everything, false rejects everything
+	always *bool
+
+	types    []string
+	enum     []interface{}
+	constVal interface{}
+	hasConst bool
+
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	minProperties        *int
+	maxProperties        *int
+
+	items       *Schema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	minimum          *big.Float
+	maximum          *big.Float
+	exclusiveMinimum *big.Float
+	exclusiveMaximum *big.Float
+	multipleOf       *big.Float
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+}
+
+var knownTypes = map[string]struct{}{
+	"null": {}, "boolean": {}, "object": {}, "array": {}, "number": {}, "integer": {}, "string": {},
+}
+
+// knownKeywords lists the keywords Compile understands: the validation keywords
+// implemented below plus annotations that never affect validation.
+var knownKeywords = map[string]struct{}{
+	"type": {}, "enum": {}, "const": {},
+	"properties": {}, "required": {}, "additionalProperties": {}, "minProperties": {}, "maxProperties": {},
+	"items": {}, "minItems": {}, "maxItems": {}, "uniqueItems": {},
+	"minLength": {}, "maxLength": {}, "pattern": {},
+	"minimum": {}, "maximum": {}, "exclusiveMinimum": {}, "exclusiveMaximum": {}, "multipleOf": {},
+	"allOf": {}, "anyOf": {}, "oneOf": {}, "not": {},
+
+	"$schema": {}, "$id": {}, "$comment": {}, "title": {}, "description": {}, "default": {},
+	"examples": {}, "deprecated": {}, "readOnly": {}, "writeOnly": {},
+}
+
+// Compile parses a schema document.
+func Compile(raw []byte) (*Schema, error) {
+	doc, err := decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	schema, err := compile(doc, "")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return schema, nil
+}
+
+// Validate checks a JSON document against the schema. Malformed JSON is
+// reported as a plain error; violations are reported as *ValidationError.
+func (s *Schema) Validate(raw []byte) error {
+	doc, err := decode(raw)
+	if err != nil {
+		return err
+	}
+	return s.validate(doc, "")
+}
+
+func decode(raw []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after top-level value")
+	}
+	return doc, nil
+}
+
+func compile(doc interface{}, path string) (*Schema, error) {
+	if b, ok := doc.(bool); ok {
+		return &Schema{always: &b}, nil
+	}
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: schema must be an object or boolean", pointer(path))
+	}
+	keywords := make([]string, 0, len(obj))
+	for keyword := range obj {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	for _, keyword := range keywords {
+		if _, ok := knownKeywords[keyword]; !ok {
+			return nil, fmt.Errorf("%s: keyword %q is not supported", pointer(path), keyword)
+		}
+	}
+
+	s := &Schema{}
+	var err error
+
+	if raw, ok := obj["type"]; ok {
+		if s.types, err = compileTypes(raw); err != nil {
+			return nil, fmt.Errorf("%s: %v", pointer(path+"/type"), err)
+		}
+	}
+	if raw, ok := obj["enum"]; ok {
+		values, ok := raw.([]interface{})
+		if !ok || len(values) == 0 {
+			return nil, fmt.Errorf("%s: must be a non-empty array", pointer(path+"/enum"))
+		}
+		s.enum = values
+	}
+	if raw, ok := obj["const"]; ok {
+		s.constVal, s.hasConst = raw, true
+	}
+
+	if raw, ok := obj["properties"]; ok {
+		props, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: must be an object", pointer(path+"/properties"))
+		}
+		s.properties = make(map[string]*Schema, len(props))
+		for name, sub := range props {
+			if s.properties[name], err = compile(sub, path+"/properties/"+escape(name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if raw, ok := obj["required"]; ok {
+		list, ok := raw.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: must be an array of strings", pointer(path+"/required"))
+		}
+		for _, item := range list {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an array of strings", pointer(path+"/required"))
+			}
+			s.required = append(s.required, name)
+		}
+	}
+	if raw, ok := obj["additionalProperties"]; ok {
+		if s.additionalProperties, err = compile(raw, path+"/additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+	if raw, ok := obj["items"]; ok {
+		if s.items, err = compile(raw, path+"/items"); err != nil {
+			return nil, err
+		}
+	}
+	if raw, ok := obj["uniqueItems"]; ok {
+		b, ok := raw.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s: must be a boolean", pointer(path+"/uniqueItems"))
+		}
+		s.uniqueItems = b
+	}
+	if raw, ok := obj["pattern"]; ok {
+		expr, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: must be a string", pointer(path+"/pattern"))
+		}
+		if s.pattern, err = regexp.Compile(expr); err != nil {
+			return nil, fmt.Errorf("%s: %v", pointer(path+"/pattern"), err)
+		}
+	}
+
+	counts := map[string]**int{
+		"minProperties": &s.minProperties,
+		"maxProperties": &s.maxProperties,
+		"minItems":      &s.minItems,
+		"maxItems":      &s.maxItems,
+		"minLength":     &s.minLength,
+		"maxLength":     &s.maxLength,
+	}
+	for keyword, target := range counts {
+		raw, ok := obj[keyword]
+		if !ok {
+			continue
+		}
+		n, ok := toInt(raw)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("%s: must be a non-negative integer", pointer(path+"/"+keyword))
+		}
+		*target = &n
+	}
+
+	bounds := map[string]**big.Float{
+		"minimum":          &s.minimum,
+		"maximum":          &s.maximum,
+		"exclusiveMinimum": &s.exclusiveMinimum,
+		"exclusiveMaximum": &s.exclusiveMaximum,
+		"multipleOf":       &s.multipleOf,
+	}
+	for keyword, target := range bounds {
+		raw, ok := obj[keyword]
+		if !ok {
+			continue
+		}
+		n, ok := toNumber(raw)
+		if !ok {
+			return nil, fmt.Errorf("%s: must be a number", pointer(path+"/"+keyword))
+		}
+		*target = n
+	}
+	if s.multipleOf != nil && s.multipleOf.Sign() <= 0 {
+		return nil, fmt.Errorf("%s: must be greater than 0", pointer(path+"/multipleOf"))
+	}
+
+	lists := map[string]*[]*Schema{"allOf": &s.allOf, "anyOf": &s.anyOf, "oneOf": &s.oneOf}
+	for keyword, target := range lists {
+		raw, ok := obj[keyword]
+		if !ok {
+			continue
+		}
+		items, ok := raw.([]interface{})
+		if !ok || len(items) == 0 {
+			return nil, fmt.Errorf("%s: must be a non-empty array", pointer(path+"/"+keyword))
+		}
+		for i, item := range items {
+			sub, err := compile(item, path+"/"+keyword+"/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			*target = append(*target, sub)
+		}
+	}
+	if raw, ok := obj["not"]; ok {
+		if s.not, err = compile(raw, path+"/not"); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func compileTypes(raw interface{}) ([]string, error) {
+	var names []string
+	switch v := raw.(type) {
+	case string:
+		names = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok {
+				return nil, errors.New("must be a string or an array of strings")
+			}
+			names = append(names, name)
+		}
+	default:
+		return nil, errors.New("must be a string or an array of strings")
+	}
+	for _, name := range names {
+		if _, ok := knownTypes[name]; !ok {
+			return nil, fmt.Errorf("unknown type %q", name)
+		}
+	}
+	return names, nil
+}
+
+func (s *Schema) validate(value interface{}, path string) error {
+	if s.always != nil {
+		if *s.always {
+			return nil
+		}
+		return fail(path, "no value is allowed here")
+	}
+
+	if len(s.types) > 0 && !matchesAnyType(value, s.types) {
+		return fail(path, fmt.Sprintf("expected %s, got %s", strings.Join(s.types, " or "), typeOf(value)))
+	}
+	if len(s.enum) > 0 {
+		matched := false
+		for _, candidate := range s.enum {
+			if equal(value, candidate) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fail(path, "value is not one of the allowed values")
+		}
+	}
+	if s.hasConst && !equal(value, s.constVal) {
+		return fail(path, "value does not match the constant")
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if err := s.validateObject(v, path); err != nil {
+			return err
+		}
+	case []interface{}:
+		if err := s.validateArray(v, path); err != nil {
+			return err
+		}
+	case string:
+		if err := s.validateString(v, path); err != nil {
+			return err
+		}
+	case json.Number:
+		if err := s.validateNumber(v, path); err != nil {
+			return err
+		}
+	}
+
+	for _, sub := range s.allOf {
+		if err := sub.validate(value, path); err != nil {
+			return err
+		}
+	}
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if sub.validate(value, path) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fail(path, "value does not match any of the allowed schemas")
+		}
+	}
+	if len(s.oneOf) > 0 {
+		count := 0
+		for _, sub := range s.oneOf {
+			if sub.validate(value, path) == nil {
+				count++
+			}
+		}
+		if count != 1 {
+			return fail(path, fmt.Sprintf("value must match exactly one schema, matched %d", count))
+		}
+	}
+	if s.not != nil && s.not.validate(value, path) == nil {
+		return fail(path, "value matches a disallowed schema")
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(obj map[string]interface{}, path string) error {
+	if s.minProperties != nil && len(obj) < *s.minProperties {
+		return fail(path, fmt.Sprintf("must have at least %d properties", *s.minProperties))
+	}
+	if s.maxProperties != nil && len(obj) > *s.maxProperties {
+		return fail(path, fmt.Sprintf("must have at most %d properties", *s.maxProperties))
+	}
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			return fail(path, fmt.Sprintf("missing required property %q", name))
+		}
+	}
+
+	// Visit properties in order so the reported location is stable.
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		childPath := path + "/" + escape(name)
+		if sub, ok := s.properties[name]; ok {
+			if err := sub.validate(obj[name], childPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if s.additionalProperties != nil {
+			if s.additionalProperties.always != nil && !*s.additionalProperties.always {
+				return fail(childPath, "additional property is not allowed")
+			}
+			if err := s.additionalProperties.validate(obj[name], childPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateArray(items []interface{}, path string) error {
+	if s.minItems != nil && len(items) < *s.minItems {
+		return fail(path, fmt.Sprintf("must have at least %d items", *s.minItems))
+	}
+	if s.maxItems != nil && len(items) > *s.maxItems {
+		return fail(path, fmt.Sprintf("must have at most %d items", *s.maxItems))
+	}
+	if s.uniqueItems {
+		for i := range items {
+			for j := 0; j < i; j++ {
+				if equal(items[i], items[j]) {
+					return fail(path, fmt.Sprintf("items %d and %d are equal", j, i))
+				}
+			}
+		}
+	}
+	if s.items != nil {
+		for i, item := range items {
+			if err := s.items.validate(item, path+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateString(value, path string) error {
+	length := utf8.RuneCountInString(value)
+	if s.minLength != nil && length < *s.minLength {
+		return fail(path, fmt.Sprintf("must be at least %d characters", *s.minLength))
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		return fail(path, fmt.Sprintf("must be at most %d characters", *s.maxLength))
+	}
+	if s.pattern != nil && !s.pattern.MatchString(value) {
+		return fail(path, fmt.Sprintf("does not match pattern %q", s.pattern.String()))
+	}
+	return nil
+}
+
+func (s *Schema) validateNumber(value json.Number, path string) error {
+	n, ok := toNumber(value)
+	if !ok {
+		return fail(path, "invalid number")
+	}
+	if s.minimum != nil && n.Cmp(s.minimum) < 0 {
+		return fail(path, "must be >= "+s.minimum.Text('g', -1))
+	}
+	if s.maximum != nil && n.Cmp(s.maximum) > 0 {
+		return fail(path, "must be <= "+s.maximum.Text('g', -1))
+	}
+	if s.exclusiveMinimum != nil && n.Cmp(s.exclusiveMinimum) <= 0 {
+		return fail(path, "must be > "+s.exclusiveMinimum.Text('g', -1))
+	}
+	if s.exclusiveMaximum != nil && n.Cmp(s.exclusiveMaximum) >= 0 {
+		return fail(path, "must be < "+s.exclusiveMaximum.Text('g', -1))
+	}
+	if s.multipleOf != nil {
+		quotient := new(big.Float).Quo(n, s.multipleOf)
+		if !quotient.IsInt() {
+			return fail(path, "must be a multiple of "+s.multipleOf.Text('g', -1))
+		}
+	}
+	return nil
+}
+
+func matchesAnyType(value interface{}, types []string) bool {
+	actual := typeOf(value)
+	for _, name := range types {
+		if name == actual {
+			return true
+		}
+		if actual == "integer" && name == "number" {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		if n, ok := toNumber(v); ok && n.IsInt() {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// equal compares decoded JSON values; numbers compare by value so 1 and 1.0 are equal.
+func equal(a, b interface{}) bool {
+	an, aNum := a.(json.Number)
+	bn, bNum := b.(json.Number)
+	if aNum || bNum {
+		if !aNum || !bNum {
+			return false
+		}
+		x, okX := toNumber(an)
+		y, okY := toNumber(bn)
+		return okX && okY && x.Cmp(y) == 0
+	}
+
+	switch av := a.(type) {
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toNumber(value interface{}) (*big.Float, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return nil, false
+	}
+	n, _, err := big.ParseFloat(number.String(), 10, 128, big.ToNearestEven)
+	if err != nil {
+		return nil, false
+	}
+	return n, true
+}
+
+func toInt(value interface{}) (int, bool) {
+	n, ok := toNumber(value)
+	if !ok || !n.IsInt() {
+		return 0, false
+	}
+	i, accuracy := n.Int64()
+	if accuracy != big.Exact || i > math.MaxInt32 {
+		return 0, false
+	}
+	return int(i), true
+}
+
+func fail(path, message string) error {
+	return &ValidationError{Path: path, Message: message}
+}
+
+func pointer(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+func escape(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,129 @@
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mustCompile(t *testing.T, schema string) *Schema {
+	t.Helper()
+	compiled, err := Compile([]byte(schema))
+	if err != nil {
+		t.Fatalf("compile %s: %v", schema, err)
+	}
+	return compiled
+}
+
+func TestValidateObject(t *testing.T) {
+	schema := mustCompile(t, `{
+		"type": "object",
+		"required": ["name", "port"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+			"mode": {"enum": ["fast", "safe"]}
+		},
+		"additionalProperties": false
+	}`)
+
+	cases := []struct {
+		name string
+		doc  string
+		path string
+	}{
+		{name: "valid", doc: `{"name": "api", "port": 8080, "tags": ["a", "b"], "mode": "safe"}`},
+		{name: "integral float is an integer", doc: `{"name": "api", "port": 80.0}`},
+		{name: "missing required", doc: `{"name": "api"}`, path: "/"},
+		{name: "wrong type", doc: `{"name": 1, "port": 80}`, path: "/name"},
+		{name: "fraction is not an integer", doc: `{"name": "api", "port": 80.5}`, path: "/port"},
+		{name: "above maximum", doc: `{"name": "api", "port": 70000}`, path: "/port"},
+		{name: "duplicate items", doc: `{"name": "api", "port": 80, "tags": ["a", "a"]}`, path: "/tags"},
+		{name: "nested item type", doc: `{"name": "api", "port": 80, "tags": ["a", 2]}`, path: "/tags/1"},
+		{name: "enum", doc: `{"name": "api", "port": 80, "mode": "slow"}`, path: "/mode"},
+		{name: "additional property", doc: `{"name": "api", "port": 80, "extra": true}`, path: "/extra"},
+		{name: "empty string", doc: `{"name": "", "port": 80}`, path: "/name"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tc.doc))
+			if tc.path == "" {
+				if err != nil {
+					t.Fatalf("expected valid document, got %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if verr.Path != tc.path && !(tc.path == "/" && verr.Path == "") {
+				t.Fatalf("expected error at %s, got %s", tc.path, verr.Error())
+			}
+		})
+	}
+}
+
+func TestValidateCombinators(t *testing.T) {
+	schema := mustCompile(t, `{
+		"oneOf": [
+			{"type": "string", "pattern": "^[a-z]+$"},
+			{"type": "number", "multipleOf": 0.5}
+		],
+		"not": {"const": "admin"}
+	}`)
+
+	for _, doc := range []string{`"abc"`, `1.5`, `2`} {
+		if err := schema.Validate([]byte(doc)); err != nil {
+			t.Fatalf("expected %s to be valid, got %v", doc, err)
+		}
+	}
+	for _, doc := range []string{`"ABC"`, `1.2`, `"admin"`, `null`} {
+		if err := schema.Validate([]byte(doc)); err == nil {
+			t.Fatalf("expected %s to be rejected", doc)
+		}
+	}
+}
+
+func TestValidateRejectsMalformedJSON(t *testing.T) {
+	schema := mustCompile(t, `true`)
+	if err := schema.Validate([]byte(`{"a": 1`)); err == nil {
+		t.Fatal("expected malformed document to be rejected")
+	}
+	if err := schema.Validate([]byte(`{} {}`)); err == nil {
+		t.Fatal("expected trailing data to be rejected")
+	}
+}
+
+func TestCompileRejectsInvalidSchemas(t *testing.T) {
+	for _, schema := range []string{
+		`"object"`,
+		`{"type": "text"}`,
+		`{"minLength": -1}`,
+		`{"pattern": "("}`,
+		`{"enum": []}`,
+		`{"properties": {"a": {"$ref": "#/definitions/a"}}}`,
+		`{"multipleOf": 0}`,
+	} {
+		if _, err := Compile([]byte(schema)); !errors.Is(err, ErrInvalidSchema) {
+			t.Fatalf("expected %s to be rejected, got %v", schema, err)
+		}
+	}
+}
+
+func TestCompileRejectsUnknownKeywords(t *testing.T) {
+	for _, schema := range []string{
+		`{"maxLenght": 3}`,
+		`{"type": "string", "format": "email"}`,
+		`{"items": {"contains": {"const": 1}}}`,
+		`{"properties": {"a": {"patternProperties": {}}}}`,
+	} {
+		_, err := Compile([]byte(schema))
+		if !errors.Is(err, ErrInvalidSchema) || !strings.Contains(err.Error(), "is not supported") {
+			t.Fatalf("expected %s to be rejected as unsupported, got %v", schema, err)
+		}
+	}
+
+	mustCompile(t, `{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "SMTP", "description": "mail server", "default": {}, "type": "object"}`)
+}
@@ -16,6 +16,8 @@ type JWTMaker struct {
 type Claims struct {
 	ID        uint   `json:"id"`
 	SessionID string `json:"sid"`
+	// TenantID 当前访问的租户，旧令牌缺省为 0
+	TenantID int64 `json:"tid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -24,10 +26,11 @@ func NewJWTMaker() *JWTMaker {
 }
 
 // CreateToken 使用用户特定的密钥创建token
-func (maker *JWTMaker) CreateToken(userID uint, sessionID string, secretKey string, duration time.Duration) (string, error) {
+func (maker *JWTMaker) CreateToken(userID uint, sessionID string, tenantID int64, secretKey string, duration time.Duration) (string, error) {
 	claims := &Claims{
 		ID:        userID,
 		SessionID: strings.TrimSpace(sessionID),
+		TenantID:  tenantID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
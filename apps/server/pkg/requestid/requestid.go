@@ -0,0 +1,39 @@
+// Package requestid carries a per-request correlation ID through contexts.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header is the HTTP header used to accept and echo request IDs.
+const Header = "X-Request-ID"
+
+type contextKey struct{}
+
+// New returns a random 32-character hex request ID.
+func New() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// WithID returns a context carrying the request ID.
+func WithID(ctx context.Context, id string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID bound to ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -63,6 +63,10 @@ func NoContent(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+func NotModified(ctx *gin.Context) {
+	ctx.Status(http.StatusNotModified)
+}
+
 func BadRequest(ctx *gin.Context, opts ...Option) {
 	respond(ctx, http.StatusBadRequest, http.StatusText(http.StatusBadRequest), opts...)
 }
@@ -87,6 +91,14 @@ func MethodNotAllowed(ctx *gin.Context, opts ...Option) {
 	respond(ctx, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed), opts...)
 }
 
+func PreconditionFailed(ctx *gin.Context, opts ...Option) {
+	respond(ctx, http.StatusPreconditionFailed, http.StatusText(http.StatusPreconditionFailed), opts...)
+}
+
+func PreconditionRequired(ctx *gin.Context, opts ...Option) {
+	respond(ctx, http.StatusPreconditionRequired, http.StatusText(http.StatusPreconditionRequired), opts...)
+}
+
 func PaymentRequired(ctx *gin.Context, opts ...Option) {
 	respond(ctx, http.StatusPaymentRequired, http.StatusText(http.StatusPaymentRequired), opts...)
 }
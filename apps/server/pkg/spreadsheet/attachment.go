@@ -0,0 +1,63 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Attachment streams rows to an HTTP response as a file download. Response
+// headers are only sent when the first row is written, so a failure before
+// any output can still be reported as a regular error response.
+type Attachment struct {
+	w         http.ResponseWriter
+	format    Format
+	filename  string
+	sheetName string
+	writer    Writer
+}
+
+// NewAttachment prepares a download named filename (without extension).
+func NewAttachment(w http.ResponseWriter, format Format, filename, sheetName string) *Attachment {
+	return &Attachment{w: w, format: format, filename: filename, sheetName: sheetName}
+}
+
+// Started reports whether any output has been sent to the client.
+func (a *Attachment) Started() bool {
+	return a.writer != nil
+}
+
+func (a *Attachment) WriteRow(values []string) error {
+	if err := a.start(); err != nil {
+		return err
+	}
+	return a.writer.WriteRow(values)
+}
+
+// Close finishes the file; an attachment without rows is still sent as an empty file.
+func (a *Attachment) Close() error {
+	if err := a.start(); err != nil {
+		return err
+	}
+	return a.writer.Close()
+}
+
+func (a *Attachment) start() error {
+	if a.writer != nil {
+		return nil
+	}
+
+	name := a.filename + a.format.Extension()
+	header := a.w.Header()
+	header.Set("Content-Type", a.format.ContentType())
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, name, url.PathEscape(name)))
+	header.Set("Cache-Control", "no-store")
+	a.w.WriteHeader(http.StatusOK)
+
+	writer, err := NewWriter(a.format, a.w, a.sheetName)
+	if err != nil {
+		return err
+	}
+	a.writer = writer
+	return nil
+}
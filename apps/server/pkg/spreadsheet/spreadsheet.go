@@ -0,0 +1,167 @@
+// Package spreadsheet reads and writes tabular data as CSV or XLSX using only the standard library.
+package spreadsheet
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported spreadsheet format")
+	ErrWriterClosed      = errors.New("spreadsheet writer is closed")
+	ErrTooManyRows       = errors.New("spreadsheet exceeds the row limit")
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// ParseFormat normalizes a format name; empty input falls back to CSV.
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), ".")) {
+	case "", "csv":
+		return FormatCSV, nil
+	case "xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// FormatFromFilename detects the format from a file extension.
+func FormatFromFilename(name string) (Format, error) {
+	ext := filepath.Ext(strings.TrimSpace(name))
+	if ext == "" {
+		return "", ErrUnsupportedFormat
+	}
+	return ParseFormat(ext)
+}
+
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// ReadAll returns every row of the first sheet. Rows keep their original
+// positions so that row numbers in reports match what users see. A sheet with
+// more than maxRows rows, counting blank rows before the last one, fails with
+// ErrTooManyRows; maxRows <= 0 disables the check.
+func ReadAll(format Format, r io.Reader, maxRows int) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r, maxRows)
+	case FormatXLSX:
+		return readXLSX(r, maxRows)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Writer streams rows to the underlying output.
+type Writer interface {
+	WriteRow(values []string) error
+	// Close flushes pending data; it does not close the underlying io.Writer.
+	Close() error
+}
+
+// NewWriter creates a streaming writer. sheetName is only used by XLSX.
+func NewWriter(format Format, w io.Writer, sheetName string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w, sheetName)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+const utf8BOM = "\uFEFF"
+
+func readCSV(r io.Reader, maxRows int) ([][]string, error) {
+	buffered := bufio.NewReader(r)
+	// Excel 导出的 CSV 常带 BOM，需要跳过
+	if prefix, err := buffered.Peek(len(utf8BOM)); err == nil && string(prefix) == utf8BOM {
+		_, _ = buffered.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if maxRows > 0 && len(rows) >= maxRows {
+			return nil, ErrTooManyRows
+		}
+		rows = append(rows, record)
+	}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	closed bool
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	// 写入 BOM，便于 Excel 正确识别 UTF-8 中文
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: csv.NewWriter(w)}, nil
+}
+
+func (w *csvWriter) WriteRow(values []string) error {
+	if w.closed {
+		return ErrWriterClosed
+	}
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = escapeFormula(value)
+	}
+	return w.writer.Write(escaped)
+}
+
+func (w *csvWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// escapeFormula prevents spreadsheet applications from evaluating cell content as a formula.
+func escapeFormula(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	default:
+		return value
+	}
+}
@@ -0,0 +1,147 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildWorkbook packs a bare worksheet into an archive; without workbook.xml
+// the reader falls back to the default sheet path.
+func buildWorkbook(t *testing.T, sheet string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	entry, err := archive.Create(defaultSheetPath)
+	if err != nil {
+		t.Fatalf("failed to create sheet: %v", err)
+	}
+	if _, err := entry.Write([]byte(sheet)); err != nil {
+		t.Fatalf("failed to write sheet: %v", err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("failed to close archive: %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestXLSXRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"用户名称", "邮箱", "备注"},
+		{"alice", "", "a < b & c"},
+		{"bob", "bob@example.com"},
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatXLSX, &buf, "用户")
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatalf("failed to write row: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+
+	got, err := ReadAll(FormatXLSX, &buf, 0)
+	if err != nil {
+		t.Fatalf("failed to read workbook: %v", err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Fatalf("unexpected rows: %#v", got)
+	}
+}
+
+func TestCSVEscapesFormulasAndSkipsBOM(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &buf, "")
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+	if err := writer.WriteRow([]string{"=SUM(A1)", "plain"}); err != nil {
+		t.Fatalf("failed to write row: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+
+	got, err := ReadAll(FormatCSV, &buf, 0)
+	if err != nil {
+		t.Fatalf("failed to read csv: %v", err)
+	}
+	want := [][]string{{"'=SUM(A1)", "plain"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected rows: %#v", got)
+	}
+}
+
+func TestXLSXRejectsHostileInput(t *testing.T) {
+	cases := []struct {
+		name  string
+		sheet string
+		want  error
+	}{
+		{
+			name:  "huge row index",
+			sheet: `<worksheet><sheetData><row r="2000000000"><c r="A2000000000" t="inlineStr"><is><t>x</t></is></c></row></sheetData></worksheet>`,
+			want:  ErrTooManyRows,
+		},
+		{
+			name:  "row index past limit",
+			sheet: `<worksheet><sheetData><row r="1"><c r="A1"><v>1</v></c></row><row r="12"><c r="A12"><v>2</v></c></row></sheetData></worksheet>`,
+			want:  ErrTooManyRows,
+		},
+		{
+			name:  "too many rows without index",
+			sheet: `<worksheet><sheetData>` + strings.Repeat(`<row><c><v>1</v></c></row>`, 11) + `</sheetData></worksheet>`,
+			want:  ErrTooManyRows,
+		},
+		{
+			name:  "huge column reference",
+			sheet: `<worksheet><sheetData><row r="1"><c r="ZZZZZZZ1" t="inlineStr"><is><t>x</t></is></c></row></sheetData></worksheet>`,
+			want:  ErrInvalidWorkbook,
+		},
+		{
+			name:  "column past XFD",
+			sheet: `<worksheet><sheetData><row r="1"><c r="XFE1"><v>1</v></c></row></sheetData></worksheet>`,
+			want:  ErrInvalidWorkbook,
+		},
+	}
+	for _, tc := range cases {
+		if _, err := ReadAll(FormatXLSX, buildWorkbook(t, tc.sheet), 10); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	got, err := ReadAll(FormatXLSX, buildWorkbook(t, `<worksheet><sheetData><row r="1"><c r="A1"><v>a</v></c></row><row r="3"><c r="XFD3"><v>b</v></c></row></sheetData></worksheet>`), 3)
+	if err != nil {
+		t.Fatalf("expected sheet within limits to be read, got %v", err)
+	}
+	if len(got) != 3 || len(got[2]) != MaxColumns || got[2][MaxColumns-1] != "b" {
+		t.Fatalf("unexpected rows: %d", len(got))
+	}
+}
+
+func TestXLSXRejectsOversizedPart(t *testing.T) {
+	sheet := `<worksheet><sheetData><row r="1"><c r="A1"><v>` + strings.Repeat("0", maxPartSize) + `</v></c></row></sheetData></worksheet>`
+	if _, err := ReadAll(FormatXLSX, buildWorkbook(t, sheet), 10); !errors.Is(err, ErrInvalidWorkbook) {
+		t.Fatalf("expected oversized part to be rejected, got %v", err)
+	}
+}
+
+func TestCSVRowLimit(t *testing.T) {
+	input := strings.Repeat("a,b\n", 4)
+	if _, err := ReadAll(FormatCSV, strings.NewReader(input), 3); !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("expected row limit to apply, got %v", err)
+	}
+	rows, err := ReadAll(FormatCSV, strings.NewReader(input), 4)
+	if err != nil || len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %d (%v)", len(rows), err)
+	}
+}
@@ -0,0 +1,350 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidWorkbook = errors.New("invalid xlsx workbook")
+
+const (
+	// MaxColumns is the widest sheet Excel supports (column XFD).
+	MaxColumns = 16384
+
+	defaultSheetPath = "xl/worksheets/sheet1.xml"
+	sheetNameLimit   = 31
+	// maxPartSize caps the decompressed size of each workbook part so a small
+	// archive cannot expand into an unbounded amount of XML.
+	maxPartSize = 64 << 20
+)
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var builder strings.Builder
+	builder.WriteString(t.Text)
+	for _, run := range t.Runs {
+		builder.WriteString(run.Text)
+	}
+	return builder.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(r io.Reader, maxRows int) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidWorkbook
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var shared xlsxSharedStrings
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(file, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetFile, ok := files[resolveFirstSheet(files)]
+	if !ok {
+		return nil, ErrInvalidWorkbook
+	}
+
+	var sheet xlsxWorksheet
+	if err := decodeZipXML(sheetFile, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		// 行号与列号来自文件本身，先校验上限再补齐，避免按伪造的行号分配内存
+		if maxRows > 0 && (row.Index > maxRows || len(rows) >= maxRows) {
+			return nil, ErrTooManyRows
+		}
+		// 补齐空行，保证行号与表格一致
+		for row.Index > len(rows)+1 {
+			rows = append(rows, []string{})
+		}
+
+		values := make([]string, 0, len(row.Cells))
+		for _, cell := range row.Cells {
+			column := len(values)
+			if cell.Ref != "" {
+				if idx, ok := columnIndex(cell.Ref); ok {
+					column = idx
+				}
+			}
+			if column >= MaxColumns {
+				return nil, ErrInvalidWorkbook
+			}
+			for len(values) < column {
+				values = append(values, "")
+			}
+
+			var value string
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+				if err != nil || idx < 0 || idx >= len(shared.Items) {
+					return nil, ErrInvalidWorkbook
+				}
+				value = shared.Items[idx].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			default:
+				value = cell.Value
+			}
+			if column < len(values) {
+				values[column] = value
+			} else {
+				values = append(values, value)
+			}
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+func resolveFirstSheet(files map[string]*zip.File) string {
+	var workbook xlsxWorkbook
+	wbFile, ok := files["xl/workbook.xml"]
+	if !ok || decodeZipXML(wbFile, &workbook) != nil || len(workbook.Sheets) == 0 {
+		return defaultSheetPath
+	}
+
+	var rels xlsxRelationships
+	relFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok || decodeZipXML(relFile, &rels) != nil {
+		return defaultSheetPath
+	}
+
+	for _, rel := range rels.Items {
+		if rel.ID != workbook.Sheets[0].RID {
+			continue
+		}
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			return strings.TrimPrefix(target, "/")
+		}
+		return path.Join("xl", target)
+	}
+	return defaultSheetPath
+}
+
+func decodeZipXML(file *zip.File, target interface{}) error {
+	if file.UncompressedSize64 > maxPartSize {
+		return fmt.Errorf("%w: %s is too large", ErrInvalidWorkbook, file.Name)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	// 压缩包头中的大小可以伪造，解压时仍需限制实际读取的字节数
+	if err := xml.NewDecoder(io.LimitReader(reader, maxPartSize)).Decode(target); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidWorkbook, file.Name)
+	}
+	return nil
+}
+
+// columnIndex converts a cell reference such as "AB12" into a zero-based column
+// index. Indexes past MaxColumns saturate at MaxColumns instead of overflowing.
+func columnIndex(ref string) (int, bool) {
+	index := 0
+	letters := 0
+	for _, ch := range ref {
+		if ch >= 'a' && ch <= 'z' {
+			ch -= 'a' - 'A'
+		}
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		if index <= MaxColumns {
+			index = index*26 + int(ch-'A'+1)
+		}
+		letters++
+	}
+	if index > MaxColumns {
+		index = MaxColumns + 1
+	}
+	if letters == 0 {
+		return 0, false
+	}
+	return index - 1, true
+}
+
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts><fills count="1"><fill><patternFill patternType="none"/></fill></fills><borders count="1"><border/></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/></cellXfs></styleSheet>`
+	xlsxWorkbookTemplate = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+// xlsxWriter streams rows into a single-sheet workbook using inline strings,
+// so no shared string table has to be held in memory.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	row     int
+	closed  bool
+}
+
+func newXLSXWriter(w io.Writer, sheetName string) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+
+	name := sanitizeSheetName(sheetName)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbookTemplate, escapeXML(name))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := archive.Create(defaultSheetPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, xlsxSheetHeader); err != nil {
+		return nil, err
+	}
+
+	return &xlsxWriter{archive: archive, sheet: sheet}, nil
+}
+
+func (w *xlsxWriter) WriteRow(values []string) error {
+	if w.closed {
+		return ErrWriterClosed
+	}
+	w.row++
+
+	var builder strings.Builder
+	builder.WriteString(`<row r="`)
+	builder.WriteString(strconv.Itoa(w.row))
+	builder.WriteString(`">`)
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+		builder.WriteString(`<c r="`)
+		builder.WriteString(columnName(i))
+		builder.WriteString(strconv.Itoa(w.row))
+		builder.WriteString(`" t="inlineStr"><is><t xml:space="preserve">`)
+		builder.WriteString(escapeXML(value))
+		builder.WriteString(`</t></is></c>`)
+	}
+	builder.WriteString(`</row>`)
+
+	_, err := io.WriteString(w.sheet, builder.String())
+	return err
+}
+
+func (w *xlsxWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if _, err := io.WriteString(w.sheet, xlsxSheetFooter); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
+
+func sanitizeSheetName(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.NewReplacer(":", "", "\\", "", "/", "", "?", "", "*", "", "[", "", "]", "").Replace(name)
+	if name == "" {
+		return "Sheet1"
+	}
+	if runes := []rune(name); len(runes) > sheetNameLimit {
+		name = string(runes[:sheetNameLimit])
+	}
+	return name
+}
+
+func escapeXML(value string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(value))
+	return buf.String()
+}
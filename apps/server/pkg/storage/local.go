@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalDriver stores objects as files below a root directory.
+type LocalDriver struct {
+	root string
+}
+
+func NewLocalDriver(root string) (*LocalDriver, error) {
+	if root == "" {
+		return nil, errors.New("local storage root is required")
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalDriver{root: abs}, nil
+}
+
+func (d *LocalDriver) Name() string {
+	return DriverLocal
+}
+
+func (d *LocalDriver) Put(ctx context.Context, key string, r io.Reader, _ int64, _ string) error {
+	target, err := d.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	// 先写入临时文件再重命名，避免读取到写了一半的对象
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := io.Copy(tmp, contextReader{ctx: ctx, r: r}); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, target)
+}
+
+func (d *LocalDriver) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	target, err := d.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(target)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+func (d *LocalDriver) Delete(_ context.Context, key string) error {
+	target, err := d.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (d *LocalDriver) PresignGet(context.Context, string, time.Duration, string) (string, error) {
+	return "", ErrPresignUnsupported
+}
+
+func (d *LocalDriver) resolve(key string) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d.root, filepath.FromSlash(cleaned)), nil
+}
+
+// contextReader stops copying once the context is cancelled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalDriverRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	driver, err := NewLocalDriver(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create driver: %v", err)
+	}
+
+	if err := driver.Put(ctx, "files/2024/a.txt", strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("failed to put object: %v", err)
+	}
+
+	reader, err := driver.Open(ctx, "files/2024/a.txt")
+	if err != nil {
+		t.Fatalf("failed to open object: %v", err)
+	}
+	content, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(content) != "hello" {
+		t.Fatalf("unexpected content: %q", content)
+	}
+
+	if err := driver.Delete(ctx, "files/2024/a.txt"); err != nil {
+		t.Fatalf("failed to delete object: %v", err)
+	}
+	if _, err := driver.Open(ctx, "files/2024/a.txt"); !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("expected ErrObjectNotFound, got %v", err)
+	}
+}
+
+func TestCleanKeyRejectsTraversal(t *testing.T) {
+	for _, key := range []string{"", "../etc/passwd", "a/../../b", "a/./b"} {
+		if _, err := CleanKey(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey for %q, got %v", key, err)
+		}
+	}
+	if key, err := CleanKey("/avatar/1.png"); err != nil || key != "avatar/1.png" {
+		t.Errorf("unexpected result: %q, %v", key, err)
+	}
+}
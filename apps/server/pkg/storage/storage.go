@@ -0,0 +1,53 @@
+// Package storage provides pluggable object storage drivers for uploaded files.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	ErrObjectNotFound     = errors.New("storage object not found")
+	ErrInvalidKey         = errors.New("invalid storage key")
+	ErrPresignUnsupported = errors.New("storage driver does not support presigned urls")
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Driver stores objects addressed by slash-separated keys.
+type Driver interface {
+	// Name returns the driver identifier persisted alongside file metadata.
+	Name() string
+	// Put stores the content of r under key. size may be -1 when unknown.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns a reader for the object; callers must close it.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object; deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// PresignGet returns a time-limited download URL, or ErrPresignUnsupported.
+	PresignGet(ctx context.Context, key string, ttl time.Duration, filename string) (string, error)
+}
+
+// CleanKey normalizes an object key and rejects keys escaping the storage root.
+func CleanKey(key string) (string, error) {
+	key = strings.TrimSpace(strings.ReplaceAll(key, "\\", "/"))
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean("/" + key)
+	cleaned = strings.TrimPrefix(cleaned, "/")
+	if cleaned == "" || cleaned == "." || strings.HasPrefix(cleaned, "..") {
+		return "", ErrInvalidKey
+	}
+	if cleaned != strings.TrimPrefix(key, "/") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,141 @@
+// Package tenant carries the current tenant through request contexts and
+// scopes GORM statements to it.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultID is the platform tenant that owns pre-existing data and hosts
+// platform administrators.
+const DefaultID int64 = 1
+
+// Column is the column every tenant-scoped table carries.
+const Column = "tenant_id"
+
+type contextKey struct{}
+
+type scope struct {
+	id      int64
+	skipped bool
+}
+
+// WithID returns a context whose database statements are limited to the tenant.
+func WithID(ctx context.Context, id int64) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, contextKey{}, scope{id: id})
+}
+
+// WithoutScope returns a context that bypasses tenant filtering, for lookups
+// keyed by globally unique identifiers and for platform-level maintenance.
+func WithoutScope(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, contextKey{}, scope{skipped: true})
+}
+
+// FromContext reports the tenant bound to ctx. It returns false when no tenant
+// is bound or filtering was explicitly disabled.
+func FromContext(ctx context.Context) (int64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	value, ok := ctx.Value(contextKey{}).(scope)
+	if !ok || value.skipped || value.id <= 0 {
+		return 0, false
+	}
+	return value.id, true
+}
+
+// Register installs callbacks that add a tenant_id condition to queries,
+// updates and deletes and fill tenant_id on create for the given models.
+// Statements without a tenant in their context are left untouched, which keeps
+// background jobs, migrations and CLI maintenance working across tenants.
+// Raw SQL is never rewritten.
+func Register(db *gorm.DB, models ...interface{}) error {
+	if db == nil {
+		return errors.New("gorm db is nil")
+	}
+
+	tables := make(map[string]struct{}, len(models))
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		if stmt.Schema.LookUpField(Column) == nil {
+			return errors.New("tenant: " + stmt.Schema.Table + " has no " + Column + " column")
+		}
+		tables[stmt.Schema.Table] = struct{}{}
+	}
+
+	where := func(tx *gorm.DB) {
+		id, ok := scopedTenant(tx, tables)
+		if !ok {
+			return
+		}
+		tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: Column}, Value: id},
+		}})
+	}
+	fill := func(tx *gorm.DB) {
+		id, ok := scopedTenant(tx, tables)
+		if !ok || tx.Statement.Schema == nil {
+			return
+		}
+		field := tx.Statement.Schema.LookUpField(Column)
+		if field == nil {
+			return
+		}
+		setTenant(tx.Statement.Context, field, tx.Statement.ReflectValue, id)
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", where); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", where); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", where); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", where); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant:create", fill)
+}
+
+func scopedTenant(tx *gorm.DB, tables map[string]struct{}) (int64, bool) {
+	if tx.Error != nil || tx.Statement == nil {
+		return 0, false
+	}
+	id, ok := FromContext(tx.Statement.Context)
+	if !ok {
+		return 0, false
+	}
+	_, scoped := tables[tx.Statement.Table]
+	return id, scoped
+}
+
+func setTenant(ctx context.Context, field *schema.Field, value reflect.Value, id int64) {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			setTenant(ctx, field, reflect.Indirect(value.Index(i)), id)
+		}
+	case reflect.Struct:
+		if _, zero := field.ValueOf(ctx, value); zero {
+			_ = field.Set(ctx, value, id)
+		}
+	}
+}
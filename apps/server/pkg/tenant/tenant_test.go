@@ -0,0 +1,149 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type scopedItem struct {
+	ID       uint `gorm:"primaryKey"`
+	TenantID int64
+	Name     string
+}
+
+type globalItem struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if err := db.AutoMigrate(&scopedItem{}, &globalItem{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if err := Register(db, &scopedItem{}); err != nil {
+		t.Fatalf("failed to register tenant scope: %v", err)
+	}
+	return db
+}
+
+func TestRegisterScopesStatements(t *testing.T) {
+	db := openTestDB(t)
+	tenantA := WithID(context.Background(), 1)
+	tenantB := WithID(context.Background(), 2)
+
+	if err := db.WithContext(tenantA).Create(&scopedItem{Name: "a"}).Error; err != nil {
+		t.Fatalf("failed to create item: %v", err)
+	}
+	if err := db.WithContext(tenantB).Create([]scopedItem{{Name: "b1"}, {Name: "b2"}}).Error; err != nil {
+		t.Fatalf("failed to create items: %v", err)
+	}
+
+	var items []scopedItem
+	if err := db.WithContext(tenantB).Find(&items).Error; err != nil {
+		t.Fatalf("failed to list items: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items for tenant 2, got %d", len(items))
+	}
+	for _, item := range items {
+		if item.TenantID != 2 {
+			t.Fatalf("expected tenant_id 2 on created item, got %d", item.TenantID)
+		}
+	}
+
+	var count int64
+	if err := db.WithContext(tenantA).Model(&scopedItem{}).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count items: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 item for tenant 1, got %d", count)
+	}
+
+	result := db.WithContext(tenantA).Model(&scopedItem{}).Where("name = ?", "b1").Update("name", "hijacked")
+	if result.Error != nil {
+		t.Fatalf("failed to update: %v", result.Error)
+	}
+	if result.RowsAffected != 0 {
+		t.Fatalf("expected cross-tenant update to affect no rows, got %d", result.RowsAffected)
+	}
+
+	result = db.WithContext(tenantA).Where("name LIKE ?", "b%").Delete(&scopedItem{})
+	if result.Error != nil {
+		t.Fatalf("failed to delete: %v", result.Error)
+	}
+	if result.RowsAffected != 0 {
+		t.Fatalf("expected cross-tenant delete to affect no rows, got %d", result.RowsAffected)
+	}
+
+	if err := db.WithContext(context.Background()).Model(&scopedItem{}).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count items: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected unscoped context to see 3 items, got %d", count)
+	}
+
+	if err := db.WithContext(WithoutScope(tenantA)).Model(&scopedItem{}).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count items: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected WithoutScope to see 3 items, got %d", count)
+	}
+}
+
+func TestRegisterKeepsExplicitTenantAndIgnoresOtherTables(t *testing.T) {
+	db := openTestDB(t)
+	ctx := WithID(context.Background(), 1)
+
+	item := scopedItem{TenantID: 5, Name: "seeded"}
+	if err := db.WithContext(ctx).Create(&item).Error; err != nil {
+		t.Fatalf("failed to create item: %v", err)
+	}
+	if item.TenantID != 5 {
+		t.Fatalf("expected explicit tenant_id to be kept, got %d", item.TenantID)
+	}
+
+	if err := db.Create(&globalItem{Name: "shared"}).Error; err != nil {
+		t.Fatalf("failed to create global item: %v", err)
+	}
+	var globals []globalItem
+	if err := db.WithContext(ctx).Find(&globals).Error; err != nil {
+		t.Fatalf("failed to list global items: %v", err)
+	}
+	if len(globals) != 1 {
+		t.Fatalf("expected unregistered table to be unscoped, got %d rows", len(globals))
+	}
+}
+
+func TestRegisterRejectsModelWithoutTenantColumn(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if err := Register(db, &globalItem{}); err == nil {
+		t.Fatalf("expected error for model without %s", Column)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatalf("expected no tenant on empty context")
+	}
+	if id, ok := FromContext(WithID(context.Background(), 3)); !ok || id != 3 {
+		t.Fatalf("expected tenant 3, got %d %v", id, ok)
+	}
+	if _, ok := FromContext(WithoutScope(WithID(context.Background(), 3))); ok {
+		t.Fatalf("expected WithoutScope to clear tenant")
+	}
+	if _, ok := FromContext(WithID(context.Background(), 0)); ok {
+		t.Fatalf("expected non-positive id to be ignored")
+	}
+}
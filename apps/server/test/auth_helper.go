@@ -52,6 +52,28 @@ func CreateUser(t *testing.T, a *app.App, username, password string) *model.SysU
 
 // Login performs login and returns access token
 func Login(t *testing.T, a *app.App, mr *miniredis.Miniredis, username, password string) string {
+	return LoginTenant(t, a, mr, "", username, password)
+}
+
+// LoginTenant logs into the tenant identified by code and returns access token
+func LoginTenant(t *testing.T, a *app.App, mr *miniredis.Miniredis, tenantCode, username, password string) string {
+	w := loginRequest(a, mr, tenantCode, username, password)
+	if w.Code != http.StatusOK {
+		t.Fatalf("login failed: %d %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			AccessToken string `json:"access_token"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal login response: %v", err)
+	}
+	return resp.Data.AccessToken
+}
+
+func loginRequest(a *app.App, mr *miniredis.Miniredis, tenantCode, username, password string) *httptest.ResponseRecorder {
 	// 1. Generate Captcha
 	reqCaptcha := httptest.NewRequest(http.MethodGet, "/api/v1/auth/captcha", nil)
 	wCaptcha := httptest.NewRecorder()
@@ -86,6 +108,9 @@ func Login(t *testing.T, a *app.App, mr *miniredis.Miniredis, username, password
 		"code":       code,
 		"captcha_id": captchaID,
 	}
+	if tenantCode != "" {
+		payload["tenant"] = tenantCode
+	}
 
 	body, _ := json.Marshal(payload)
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
@@ -93,18 +118,5 @@ func Login(t *testing.T, a *app.App, mr *miniredis.Miniredis, username, password
 	w := httptest.NewRecorder()
 
 	a.Handler().ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Fatalf("login failed: %d %s", w.Code, w.Body.String())
-	}
-
-	var resp struct {
-		Data struct {
-			AccessToken string `json:"access_token"`
-		} `json:"data"`
-	}
-	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
-		t.Fatalf("failed to unmarshal login response: %v", err)
-	}
-	return resp.Data.AccessToken
+	return w
 }
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/system/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvatarUpload(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "avatar_user", "admin123")
+	token := Login(t, app, mr, "avatar_user", "admin123")
+
+	pngImage := func(t *testing.T, width, height int) []byte {
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+		for x := 0; x < width; x++ {
+			for y := 0; y < height; y++ {
+				img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+			}
+		}
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, img))
+		return buf.Bytes()
+	}
+
+	upload := func(t *testing.T, path, name string, content []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", name)
+		assert.NoError(t, err)
+		_, _ = part.Write(content)
+		assert.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, path, &body)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	fetch := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	var first user.AvatarResult
+
+	t.Run("Upload Profile Avatar", func(t *testing.T) {
+		w := upload(t, "/api/v1/profile/avatar", "photo.png", pngImage(t, 400, 300))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var res struct {
+			Data user.AvatarResult `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		first = res.Data
+		assert.Contains(t, first.Avatar, "/api/v1/public/files/")
+		if !assert.Len(t, first.Variants, 3) {
+			return
+		}
+
+		// 头像地址无需登录即可访问，且已裁剪为正方形
+		w = fetch(first.Avatar)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/jpeg", w.Header().Get("Content-Type"))
+		img, err := jpeg.Decode(bytes.NewReader(w.Body.Bytes()))
+		if assert.NoError(t, err) {
+			assert.Equal(t, 256, img.Bounds().Dx())
+			assert.Equal(t, 256, img.Bounds().Dy())
+		}
+
+		w = fetch(first.Variants[2].URL)
+		assert.Equal(t, http.StatusOK, w.Code)
+		img, err = jpeg.Decode(bytes.NewReader(w.Body.Bytes()))
+		if assert.NoError(t, err) {
+			assert.Equal(t, 48, img.Bounds().Dx())
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/profile", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w = httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		assert.Contains(t, w.Body.String(), first.Avatar)
+	})
+
+	t.Run("Replace Avatar Removes Previous Files", func(t *testing.T) {
+		w := upload(t, "/api/v1/profile/avatar", "photo.png", pngImage(t, 120, 120))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var res struct {
+			Data user.AvatarResult `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.NotEqual(t, first.Avatar, res.Data.Avatar)
+		// 原图不足主图尺寸时不放大
+		if assert.Len(t, res.Data.Variants, 3) {
+			w = fetch(res.Data.Avatar)
+			img, err := jpeg.Decode(bytes.NewReader(w.Body.Bytes()))
+			if assert.NoError(t, err) {
+				assert.Equal(t, 120, img.Bounds().Dx())
+			}
+		}
+
+		for _, variant := range first.Variants {
+			assert.Equal(t, http.StatusNotFound, fetch(variant.URL).Code)
+		}
+	})
+
+	t.Run("Admin Uploads Avatar For User", func(t *testing.T) {
+		w := upload(t, "/api/v1/system/users/1/avatar", "admin.png", pngImage(t, 128, 128))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = upload(t, "/api/v1/system/users/999999/avatar", "admin.png", pngImage(t, 128, 128))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Reject Invalid Images", func(t *testing.T) {
+		w := upload(t, "/api/v1/profile/avatar", "tiny.png", pngImage(t, 32, 32))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = upload(t, "/api/v1/profile/avatar", "fake.png", []byte("<html>not an image</html>"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("Unknown Public File", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, fetch("/api/v1/public/files/999999").Code)
+	})
+}
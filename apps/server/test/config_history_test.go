@@ -0,0 +1,140 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/config"
+	"github.com/starter-kit-fe/admin/internal/system/history"
+)
+
+func TestConfigRollback(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "rollback_admin", "admin123")
+	token := Login(t, app, mr, "rollback_admin", "admin123")
+
+	call := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	decodeConfig := func(t *testing.T, w *httptest.ResponseRecorder) config.Config {
+		var res struct {
+			Data config.Config `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data
+	}
+	listHistory := func(t *testing.T, path string) []history.ChangeLog {
+		w := call(http.MethodGet, path+"/history", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data history.ListResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data.List
+	}
+	rollback := func(path string, revision int64) *httptest.ResponseRecorder {
+		return call(http.MethodPost, path+"/history/"+strconv.FormatInt(revision, 10)+"/rollback", nil)
+	}
+
+	t.Run("Restores An Earlier Revision", func(t *testing.T) {
+		w := call(http.MethodPost, "/api/v1/system/configs", map[string]interface{}{
+			"configName": "会话超时", "configKey": "test.session.timeout", "valueType": "duration", "configValue": "30m",
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		path := "/api/v1/system/configs/" + strconv.FormatInt(decodeConfig(t, w).ConfigID, 10)
+
+		require.Equal(t, http.StatusOK, call(http.MethodPut, path, map[string]interface{}{"configValue": "45m", "remark": "延长"}).Code)
+		require.Equal(t, http.StatusOK, call(http.MethodPut, path, map[string]interface{}{"configValue": "1", "valueType": "int", "isPublic": true}).Code)
+
+		logs := listHistory(t, path)
+		require.Len(t, logs, 3)
+		created := logs[2]
+		require.Equal(t, history.ActionCreate, created.Action)
+
+		w = rollback(path, created.ID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		restored := decodeConfig(t, w)
+		assert.Equal(t, "30m", restored.ConfigValue)
+		assert.Equal(t, "duration", restored.ValueType)
+		assert.False(t, restored.IsPublic)
+		assert.Nil(t, restored.Remark)
+
+		logs = listHistory(t, path)
+		require.Len(t, logs, 4)
+		assert.Equal(t, history.ActionRollback, logs[0].Action)
+		require.NotNil(t, logs[0].RollbackTo)
+		assert.Equal(t, created.ID, *logs[0].RollbackTo)
+		assert.NotEmpty(t, logs[0].Changes)
+
+		// 回滚到回滚前的版本可以撤销回滚
+		w = rollback(path, logs[1].ID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "1", decodeConfig(t, w).ConfigValue)
+
+		// 回滚请求写入操作日志
+		require.Eventually(t, func() bool {
+			var count int64
+			app.DB().Model(&model.SysOperLog{}).Where("oper_url LIKE ?", path+"/history/%/rollback").Count(&count)
+			return count == 2
+		}, 2*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("Revision Must Belong To The Config", func(t *testing.T) {
+		w := call(http.MethodPost, "/api/v1/system/configs", map[string]interface{}{
+			"configName": "其他参数", "configKey": "test.other", "configValue": "a",
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		other := listHistory(t, "/api/v1/system/configs/"+strconv.FormatInt(decodeConfig(t, w).ConfigID, 10))
+		require.NotEmpty(t, other)
+
+		assert.Equal(t, http.StatusNotFound, rollback("/api/v1/system/configs/2", other[0].ID).Code)
+		assert.Equal(t, http.StatusBadRequest, rollback("/api/v1/system/configs/2", 0).Code)
+	})
+
+	t.Run("Secret Values Are Fingerprinted", func(t *testing.T) {
+		w := call(http.MethodPost, "/api/v1/system/configs", map[string]interface{}{
+			"configName": "Webhook 密钥", "configKey": "test.webhook.secret", "valueType": "secret", "configValue": "first-secret",
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		path := "/api/v1/system/configs/" + strconv.FormatInt(decodeConfig(t, w).ConfigID, 10)
+		require.Equal(t, http.StatusOK, call(http.MethodPut, path, map[string]interface{}{"configValue": "second-secret"}).Code)
+		require.Equal(t, http.StatusOK, call(http.MethodPut, path, map[string]interface{}{"remark": "轮换"}).Code)
+
+		logs := listHistory(t, path)
+		require.Len(t, logs, 3)
+		valueChange := logs[1].Changes
+		require.Len(t, valueChange, 1)
+		assert.Equal(t, "configValue", valueChange[0].Field)
+		for _, value := range []interface{}{valueChange[0].Before, valueChange[0].After} {
+			text, _ := value.(string)
+			assert.True(t, strings.HasPrefix(text, config.SecretMask), text)
+			assert.NotContains(t, text, "secret")
+		}
+
+		// 只撤销备注时无需恢复密文
+		require.Equal(t, http.StatusOK, rollback(path, logs[1].ID).Code)
+		w = rollback(path, logs[2].ID)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "not kept in history")
+	})
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/config"
+	"github.com/starter-kit-fe/admin/internal/system/settings"
+	"github.com/starter-kit-fe/admin/pkg/envelope"
+)
+
+func TestSecretConfigs(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "secret_admin", "admin123")
+	token := Login(t, app, mr, "secret_admin", "admin123")
+
+	call := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	storedValue := func(t *testing.T, id int64) string {
+		var record model.SysConfig
+		require.NoError(t, app.DB().First(&record, id).Error)
+		return record.ConfigValue
+	}
+	lookup := func(t *testing.T, keyring *envelope.Keyring) string {
+		svc := settings.NewService(settings.NewRepository(app.DB()), nil, settings.Options{Keyring: keyring})
+		value, found, err := svc.Lookup(context.Background(), "mail.smtp.password")
+		require.NoError(t, err)
+		require.True(t, found)
+		return value
+	}
+
+	w := call(http.MethodPost, "/api/v1/system/configs", map[string]interface{}{
+		"configName":  "SMTP 密码",
+		"configKey":   "mail.smtp.password",
+		"valueType":   "secret",
+		"configValue": "s3cr3t-smtp",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "s3cr3t-smtp")
+	var created struct {
+		Data config.Config `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	id := created.Data.ConfigID
+	path := "/api/v1/system/configs/" + strconv.FormatInt(id, 10)
+
+	t.Run("Values Are Encrypted And Masked", func(t *testing.T) {
+		assert.Equal(t, config.SecretMask, created.Data.ConfigValue)
+		stored := storedValue(t, id)
+		assert.True(t, envelope.IsSealed(stored))
+		assert.NotContains(t, stored, "s3cr3t-smtp")
+
+		w := call(http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "s3cr3t-smtp")
+
+		keyring, err := envelope.ParseKeyring([]string{testConfigKey})
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t-smtp", lookup(t, keyring))
+
+		_, _, err = settings.NewService(settings.NewRepository(app.DB()), nil, settings.Options{}).
+			Lookup(context.Background(), "mail.smtp.password")
+		assert.ErrorIs(t, err, settings.ErrSecretsUnavailable)
+	})
+
+	t.Run("Plaintext Is Not Written To Operation Logs", func(t *testing.T) {
+		var logs []model.SysOperLog
+		require.Eventually(t, func() bool {
+			logs = nil
+			app.DB().Where("oper_url LIKE ?", "/api/v1/system/configs%").Find(&logs)
+			return len(logs) > 0
+		}, 2*time.Second, 20*time.Millisecond)
+		for _, log := range logs {
+			assert.NotContains(t, log.OperParam, "s3cr3t-smtp")
+		}
+	})
+
+	t.Run("Mask Keeps The Current Value", func(t *testing.T) {
+		before := storedValue(t, id)
+		w := call(http.MethodPut, path, map[string]string{"configValue": config.SecretMask, "remark": "用于发送通知邮件"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, before, storedValue(t, id))
+
+		w = call(http.MethodPut, path, map[string]string{"valueType": "string"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = call(http.MethodPut, path, map[string]bool{"isPublic": true})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "cannot be public")
+	})
+
+	t.Run("Rotation Rewraps Values With The New Key", func(t *testing.T) {
+		newKey, err := envelope.GenerateKey()
+		require.NoError(t, err)
+		rotating, err := envelope.ParseKeyring([]string{"k2:" + newKey, testConfigKey})
+		require.NoError(t, err)
+
+		svc := config.NewService(config.NewRepository(app.DB()), nil, nil, nil, rotating)
+		rotated, err := svc.RotateSecrets(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, rotated)
+		assert.True(t, strings.HasPrefix(storedValue(t, id), "enc:v1:k2:"))
+
+		rotated, err = svc.RotateSecrets(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, rotated)
+
+		onlyNew, err := envelope.ParseKeyring([]string{"k2:" + newKey})
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t-smtp", lookup(t, onlyNew))
+	})
+}
@@ -0,0 +1,152 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starter-kit-fe/admin/internal/system/config"
+)
+
+func TestTypedConfigs(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "config_admin", "admin123")
+	token := Login(t, app, mr, "config_admin", "admin123")
+
+	call := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		unconditional(req)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	create := func(t *testing.T, payload map[string]interface{}) config.Config {
+		w := call(http.MethodPost, "/api/v1/system/configs", payload)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var created struct {
+			Data config.Config `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return created.Data
+	}
+	publicConfigs := func(t *testing.T, query string) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/public/configs"+query, nil)
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var result struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result.Data
+	}
+
+	t.Run("Values Are Validated Against Their Type", func(t *testing.T) {
+		cases := []struct {
+			name    string
+			payload map[string]interface{}
+			message string
+		}{
+			{"int", map[string]interface{}{"valueType": "int", "configValue": "ten"}, "not an integer"},
+			{"bool", map[string]interface{}{"valueType": "bool", "configValue": "maybe"}, "not a boolean"},
+			{"duration", map[string]interface{}{"valueType": "duration", "configValue": "soon"}, "not a duration"},
+			{"url", map[string]interface{}{"valueType": "url", "configValue": "ftp://example.com"}, "not an http(s) URL"},
+			{"email", map[string]interface{}{"valueType": "email", "configValue": "Ops <ops@example.com>"}, "not an email address"},
+			{"enum without options", map[string]interface{}{"valueType": "enum", "configValue": "a"}, "invalid config value schema"},
+			{"enum value", map[string]interface{}{"valueType": "enum", "valueSchema": `["light","dark"]`, "configValue": "blue"}, "not one of light, dark"},
+			{"malformed json", map[string]interface{}{"valueType": "json", "configValue": "{"}, "malformed JSON"},
+			{"json schema", map[string]interface{}{
+				"valueType":   "json",
+				"valueSchema": `{"type":"object","required":["host"],"properties":{"port":{"type":"integer"}}}`,
+				"configValue": `{"host":"smtp.example.com","port":"25"}`,
+			}, "/port"},
+			{"unknown type", map[string]interface{}{"valueType": "date", "configValue": "2024-01-01"}, "invalid config value type"},
+			{"schema on plain type", map[string]interface{}{"valueType": "int", "valueSchema": `["1"]`, "configValue": "1"}, "only enum and json"},
+			{"default value", map[string]interface{}{"valueType": "int", "defaultValue": "x", "configValue": "1"}, "invalid config default value"},
+			{"missing value without default", map[string]interface{}{"valueType": "int", "configValue": ""}, "config value is required"},
+		}
+		for i, tc := range cases {
+			payload := map[string]interface{}{"configName": "校验参数", "configKey": "test.invalid." + strconv.Itoa(i)}
+			for k, v := range tc.payload {
+				payload[k] = v
+			}
+			w := call(http.MethodPost, "/api/v1/system/configs", payload)
+			assert.Equal(t, http.StatusBadRequest, w.Code, tc.name)
+			assert.Contains(t, w.Body.String(), tc.message, tc.name)
+		}
+	})
+
+	t.Run("Valid Values Are Normalized", func(t *testing.T) {
+		created := create(t, map[string]interface{}{
+			"configName": "开关", "configKey": "test.flag", "valueType": "BOOL", "configValue": "yes",
+		})
+		assert.Equal(t, "bool", created.ValueType)
+		assert.Equal(t, "true", created.ConfigValue)
+
+		created = create(t, map[string]interface{}{
+			"configName":  "邮件服务器",
+			"configKey":   "test.smtp",
+			"valueType":   "json",
+			"valueSchema": `{"type":"object","required":["host"]}`,
+			"configValue": `{"host":"smtp.example.com"}`,
+		})
+		require.NotNil(t, created.ValueSchema)
+
+		// 改为整数类型时现有值必须同样有效
+		path := "/api/v1/system/configs/" + strconv.FormatInt(created.ConfigID, 10)
+		w := call(http.MethodPut, path, map[string]string{"valueType": "int"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = call(http.MethodPut, path, map[string]string{"configValue": `{"port":25}`})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `missing required property`)
+	})
+
+	t.Run("Public Configs Are Served Without Login", func(t *testing.T) {
+		created := create(t, map[string]interface{}{
+			"configName":   "每页条数",
+			"configKey":    "test.pageSize",
+			"valueType":    "int",
+			"defaultValue": "20",
+			"isPublic":     true,
+		})
+		assert.Empty(t, created.ConfigValue)
+
+		items := publicConfigs(t, "")
+		assert.Equal(t, true, items["sys.account.captchaEnabled"])
+		assert.Equal(t, "Admin Template", items["sys.site.name"])
+		assert.Equal(t, "/pwa-192x192.png", items["sys.site.logo"], "empty value falls back to the default")
+		assert.Equal(t, float64(20), items["test.pageSize"])
+		assert.NotContains(t, items, "sys.user.initPassword")
+		assert.NotContains(t, items, "test.flag")
+
+		// 修改公开参数后缓存随之失效
+		path := "/api/v1/system/configs/" + strconv.FormatInt(created.ConfigID, 10)
+		w := call(http.MethodPut, path, map[string]string{"configValue": "50"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, float64(50), publicConfigs(t, "")["test.pageSize"])
+
+		w = call(http.MethodPut, path, map[string]bool{"isPublic": false})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.NotContains(t, publicConfigs(t, ""), "test.pageSize")
+
+		assert.Equal(t, "Admin Template", publicConfigs(t, "?tenant=default")["sys.site.name"])
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/public/configs?tenant=missing", nil)
+		w = httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
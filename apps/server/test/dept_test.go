@@ -0,0 +1,138 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/dept"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepartmentMoveAndMerge(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "dept_admin", "admin123")
+	token := Login(t, app, mr, "dept_admin", "admin123")
+
+	createDept := func(t *testing.T, name string, parent *model.SysDept) *model.SysDept {
+		record := &model.SysDept{DeptName: name, Ancestors: "0", Status: "0"}
+		if parent != nil {
+			record.ParentID = int64(parent.ID)
+			record.Ancestors = parent.Ancestors + "," + strconv.FormatUint(uint64(parent.ID), 10)
+		}
+		if err := app.DB().Create(record).Error; err != nil {
+			t.Fatalf("failed to create department: %v", err)
+		}
+		return record
+	}
+
+	reload := func(t *testing.T, id uint) model.SysDept {
+		var record model.SysDept
+		if err := app.DB().Unscoped().First(&record, id).Error; err != nil {
+			t.Fatalf("failed to reload department: %v", err)
+		}
+		return record
+	}
+
+	call := func(t *testing.T, method, path string, payload any) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			assert.NoError(t, json.NewEncoder(&body).Encode(payload))
+		}
+		req := httptest.NewRequest(method, path, &body)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	deptPath := func(record *model.SysDept) string {
+		return "/api/v1/system/departments/" + strconv.FormatUint(uint64(record.ID), 10)
+	}
+
+	root := createDept(t, "迁移根", nil)
+	sales := createDept(t, "销售部", root)
+	east := createDept(t, "华东区", sales)
+	shanghai := createDept(t, "上海组", east)
+	market := createDept(t, "市场部", root)
+
+	t.Run("Move Subtree Rewrites Ancestors", func(t *testing.T) {
+		w := call(t, http.MethodPut, deptPath(east), map[string]any{"parentId": market.ID})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		moved := reload(t, east.ID)
+		assert.Equal(t, int64(market.ID), moved.ParentID)
+		assert.Equal(t, market.Ancestors+","+strconv.FormatUint(uint64(market.ID), 10), moved.Ancestors)
+		assert.Equal(t, moved.Ancestors+","+strconv.FormatUint(uint64(east.ID), 10), reload(t, shanghai.ID).Ancestors)
+
+		w = call(t, http.MethodPut, deptPath(market), map[string]any{"parentId": shanghai.ID})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Preview And Merge", func(t *testing.T) {
+		// 源部门：市场部（下辖华东区/上海组），目标部门：销售部
+		member := CreateUser(t, app, "market_member", "admin123")
+		assert.NoError(t, app.DB().Model(member).Update("dept_id", market.ID).Error)
+		assert.NoError(t, app.DB().Create(&model.SysRoleDept{RoleID: 1, DeptID: int64(market.ID)}).Error)
+		assert.NoError(t, app.DB().Create(&model.SysRoleDept{RoleID: 2, DeptID: int64(market.ID)}).Error)
+		assert.NoError(t, app.DB().Create(&model.SysRoleDept{RoleID: 2, DeptID: int64(sales.ID)}).Error)
+
+		w := call(t, http.MethodGet, deptPath(market)+"/merge/preview?targetId="+strconv.FormatUint(uint64(sales.ID), 10), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res struct {
+			Data dept.MergePreview `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, int64(1), res.Data.Users)
+		assert.Equal(t, int64(2), res.Data.RoleGrants)
+		assert.Equal(t, int64(1), res.Data.Children)
+		assert.Equal(t, int64(2), res.Data.Descendants)
+		assert.Empty(t, res.Data.Conflicts)
+
+		w = call(t, http.MethodPost, deptPath(market)+"/merge", map[string]any{"targetId": sales.ID})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var user model.SysUser
+		assert.NoError(t, app.DB().First(&user, member.ID).Error)
+		if assert.NotNil(t, user.DeptID) {
+			assert.Equal(t, int64(sales.ID), *user.DeptID)
+		}
+
+		var grants []model.SysRoleDept
+		assert.NoError(t, app.DB().Where("dept_id IN ?", []uint{market.ID, sales.ID}).Order("role_id").Find(&grants).Error)
+		assert.Equal(t, []model.SysRoleDept{
+			{RoleID: 1, DeptID: int64(sales.ID)},
+			{RoleID: 2, DeptID: int64(sales.ID)},
+		}, grants)
+
+		salesPath := sales.Ancestors + "," + strconv.FormatUint(uint64(sales.ID), 10)
+		assert.Equal(t, int64(sales.ID), reload(t, east.ID).ParentID)
+		assert.Equal(t, salesPath, reload(t, east.ID).Ancestors)
+		assert.Equal(t, salesPath+","+strconv.FormatUint(uint64(east.ID), 10), reload(t, shanghai.ID).Ancestors)
+		assert.True(t, reload(t, market.ID).DeletedAt.Valid)
+	})
+
+	t.Run("Reject Invalid Merge", func(t *testing.T) {
+		w := call(t, http.MethodPost, deptPath(sales)+"/merge", map[string]any{"targetId": shanghai.ID})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = call(t, http.MethodPost, deptPath(sales)+"/merge", map[string]any{"targetId": sales.ID})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// 重名的下级部门会导致合并冲突
+		other := createDept(t, "其他部", root)
+		createDept(t, "华东区", other)
+		w = call(t, http.MethodGet, deptPath(other)+"/merge/preview?targetId="+strconv.FormatUint(uint64(sales.ID), 10), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "华东区")
+
+		w = call(t, http.MethodPost, deptPath(other)+"/merge", map[string]any{"targetId": sales.ID})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+)
+
+func TestDictDrivenEnums(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "enum_admin", "admin123")
+	token := Login(t, app, mr, "enum_admin", "admin123")
+
+	call := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	dictPath := func(dictType string) string {
+		var record model.SysDictType
+		require.NoError(t, app.DB().Where("dict_type = ?", dictType).First(&record).Error)
+		return "/api/v1/system/dicts/" + strconv.FormatUint(uint64(record.ID), 10) + "/data"
+	}
+
+	t.Run("Notice Type From Dictionary", func(t *testing.T) {
+		notice := map[string]string{"noticeTitle": "系统维护", "noticeType": "3", "noticeContent": "今晚维护"}
+		w := call(http.MethodPost, "/api/v1/system/notices", notice)
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+		// 新增通知类型只需要新增字典数据
+		w = call(http.MethodPost, dictPath("sys_notice_type"), map[string]string{"dictLabel": "维护", "dictValue": "3"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		w = call(http.MethodPost, "/api/v1/system/notices", notice)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var created model.SysNotice
+		require.NoError(t, app.DB().Where("notice_title = ?", "系统维护").First(&created).Error)
+		assert.Equal(t, "3", created.NoticeType)
+		assert.Equal(t, "0", created.Status, "empty status takes the dictionary default")
+	})
+
+	t.Run("Disabled Status Is Rejected", func(t *testing.T) {
+		w := call(http.MethodPost, "/api/v1/system/posts", map[string]string{"postCode": "enum_a", "postName": "字典岗位A", "status": "1"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		// 停用“停用”这一状态后，该取值不再被接受
+		var disabled model.SysDictData
+		require.NoError(t, app.DB().Where("dict_type = ? AND dict_value = ?", "sys_normal_disable", "1").First(&disabled).Error)
+		itemPath := dictPath("sys_normal_disable") + "/" + strconv.FormatUint(uint64(disabled.ID), 10)
+		require.Equal(t, http.StatusOK, call(http.MethodPut, itemPath, map[string]string{"status": "1"}).Code)
+
+		w = call(http.MethodPost, "/api/v1/system/posts", map[string]string{"postCode": "enum_b", "postName": "字典岗位B", "status": "1"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		w = call(http.MethodPost, "/api/v1/system/departments", map[string]interface{}{"deptName": "字典部门", "parentId": 100, "status": "1"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	t.Run("Disabled Default Is Not Applied", func(t *testing.T) {
+		w := call(http.MethodPost, dictPath("sys_normal_disable"), map[string]string{"dictLabel": "冻结", "dictValue": "2"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		// 停用默认项“正常”后，空状态不能再退回代码中的默认值
+		var normal model.SysDictData
+		require.NoError(t, app.DB().Where("dict_type = ? AND dict_value = ?", "sys_normal_disable", "0").First(&normal).Error)
+		itemPath := dictPath("sys_normal_disable") + "/" + strconv.FormatUint(uint64(normal.ID), 10)
+		require.Equal(t, http.StatusOK, call(http.MethodPut, itemPath, map[string]string{"status": "1"}).Code)
+
+		w = call(http.MethodPost, "/api/v1/system/posts", map[string]string{"postCode": "enum_c", "postName": "字典岗位C"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		w = call(http.MethodPost, "/api/v1/system/posts", map[string]string{"postCode": "enum_c", "postName": "字典岗位C", "status": "2"})
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		require.Equal(t, http.StatusOK, call(http.MethodPut, itemPath, map[string]string{"status": "0"}).Code)
+	})
+
+	t.Run("Data Scope Stays Within Known Values", func(t *testing.T) {
+		w := call(http.MethodPost, dictPath("sys_data_scope"), map[string]string{"dictLabel": "未知范围", "dictValue": "9"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		w = call(http.MethodPost, "/api/v1/system/roles", map[string]interface{}{"roleName": "范围角色", "roleKey": "scope_role", "dataScope": "9"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+		w = call(http.MethodPost, "/api/v1/system/roles", map[string]interface{}{"roleName": "范围角色", "roleKey": "scope_role", "dataScope": "4"})
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	})
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+)
+
+func TestDictOptions(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "dict_admin", "admin123")
+	adminToken := Login(t, app, mr, "dict_admin", "admin123")
+
+	// 没有任何角色的用户也能读取字典选项
+	viewer := CreateUser(t, app, "dict_viewer", "viewer123")
+	require.NoError(t, app.DB().Where("user_id = ?", viewer.ID).Delete(&model.SysUserRole{}).Error)
+	viewerToken := Login(t, app, mr, "dict_viewer", "viewer123")
+
+	call := func(token, method, path string, payload interface{}, headers map[string]string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		unconditional(req)
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	decodeOptions := func(t *testing.T, w *httptest.ResponseRecorder) []dict.DictOption {
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data []dict.DictOption `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data
+	}
+
+	t.Run("Single Type", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, call(viewerToken, http.MethodGet, "/api/v1/system/dicts", nil, nil).Code)
+
+		options := decodeOptions(t, call(viewerToken, http.MethodGet, "/api/v1/dicts/types/sys_user_sex", nil, nil))
+		require.Len(t, options, 3)
+		assert.Equal(t, "男", options[0].DictLabel)
+		assert.Equal(t, "Y", options[0].IsDefault)
+		assert.Equal(t, "2", options[2].DictValue)
+
+		w := call(viewerToken, http.MethodGet, "/api/v1/dicts/types/no_such_type", nil, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Bulk Lookup", func(t *testing.T) {
+		w := call(viewerToken, http.MethodGet, "/api/v1/dicts/types?types=sys_user_sex,sys_normal_disable,no_such_type", nil, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data map[string][]dict.DictOption `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Len(t, res.Data, 2)
+		assert.Len(t, res.Data["sys_user_sex"], 3)
+		require.Len(t, res.Data["sys_normal_disable"], 2)
+		assert.Equal(t, "danger", *res.Data["sys_normal_disable"][1].ListClass)
+
+		assert.Equal(t, http.StatusBadRequest, call(viewerToken, http.MethodGet, "/api/v1/dicts/types?types=,", nil, nil).Code)
+	})
+
+	t.Run("ETag And Invalidation", func(t *testing.T) {
+		path := "/api/v1/dicts/types/sys_user_sex"
+		w := call(viewerToken, http.MethodGet, path, nil, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		tag := w.Header().Get("ETag")
+		require.NotEmpty(t, tag)
+		assert.NotEmpty(t, mr.Keys(), "options are cached in redis")
+
+		w = call(viewerToken, http.MethodGet, path, nil, map[string]string{"If-None-Match": tag})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+
+		// 停用一项后缓存失效，ETag 随之变化
+		var sexType model.SysDictType
+		require.NoError(t, app.DB().Where("dict_type = ?", "sys_user_sex").First(&sexType).Error)
+		itemPath := "/api/v1/system/dicts/" + strconv.FormatUint(uint64(sexType.ID), 10) + "/data/3"
+		require.Equal(t, http.StatusOK, call(adminToken, http.MethodPut, itemPath, map[string]string{"status": "1"}, nil).Code)
+
+		w = call(viewerToken, http.MethodGet, path, nil, map[string]string{"If-None-Match": tag})
+		options := decodeOptions(t, w)
+		assert.Len(t, options, 2)
+		assert.NotEqual(t, tag, w.Header().Get("ETag"))
+
+		// 新增的类型不会被之前缓存的“不存在”结果挡住
+		assert.Equal(t, http.StatusNotFound, call(viewerToken, http.MethodGet, "/api/v1/dicts/types/later_type", nil, nil).Code)
+		w = call(adminToken, http.MethodPost, "/api/v1/system/dicts", map[string]string{"dictName": "后建字典", "dictType": "later_type"}, nil)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		assert.Empty(t, decodeOptions(t, call(viewerToken, http.MethodGet, "/api/v1/dicts/types/later_type", nil, nil)))
+	})
+}
@@ -0,0 +1,191 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+)
+
+func TestDictTree(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "tree_admin", "admin123")
+	token := Login(t, app, mr, "tree_admin", "admin123")
+
+	call := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	idOf := func(id int64) string {
+		return strconv.FormatInt(id, 10)
+	}
+
+	w := call(http.MethodPost, "/api/v1/system/dicts", map[string]string{"dictName": "地区", "dictType": "sys_region"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data dict.DictType `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	dataPath := "/api/v1/system/dicts/" + idOf(created.Data.ID) + "/data"
+
+	createItem := func(label, value string, parentID int64) int64 {
+		w := call(http.MethodPost, dataPath, map[string]interface{}{"dictLabel": label, "dictValue": value, "parentId": parentID})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var res struct {
+			Data dict.DictData `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data.ID
+	}
+
+	zj := createItem("浙江", "33", 0)
+	hz := createItem("杭州", "3301", zj)
+	createItem("市辖区", "330101", hz)
+	js := createItem("江苏", "32", 0)
+	nj := createItem("南京", "3201", js)
+
+	t.Run("Parent Validation", func(t *testing.T) {
+		// 不同上级下允许同名，同一上级下不允许
+		createItem("市辖区", "320101", nj)
+		w := call(http.MethodPost, dataPath, map[string]interface{}{"dictLabel": "市辖区", "dictValue": "320102", "parentId": nj})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = call(http.MethodPost, dataPath, map[string]interface{}{"dictLabel": "未知", "dictValue": "99", "parentId": 1})
+		assert.Equal(t, http.StatusBadRequest, w.Code, "parent from another dictionary type")
+
+		w = call(http.MethodPut, dataPath+"/"+idOf(zj), map[string]interface{}{"parentId": hz})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "cycle")
+	})
+
+	t.Run("Tree And Children", func(t *testing.T) {
+		w := call(http.MethodGet, "/api/v1/dicts/types/sys_region/tree", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var tree struct {
+			Data []dict.DictTreeNode `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tree))
+		require.Len(t, tree.Data, 2)
+		assert.Equal(t, "33", tree.Data[0].DictValue)
+		require.Len(t, tree.Data[0].Children, 1)
+		assert.Equal(t, "330101", tree.Data[0].Children[0].Children[0].DictValue)
+
+		w = call(http.MethodGet, "/api/v1/dicts/types/sys_region/children?parent=32", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var children struct {
+			Data []dict.DictOption `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &children))
+		require.Len(t, children.Data, 1)
+		assert.Equal(t, "南京", children.Data[0].DictLabel)
+		assert.Equal(t, "32", children.Data[0].ParentValue)
+
+		// 停用上级后整棵子树不再出现在选项中
+		require.Equal(t, http.StatusOK, call(http.MethodPut, dataPath+"/"+idOf(js), map[string]string{"status": "1"}).Code)
+		w = call(http.MethodGet, "/api/v1/dicts/types/sys_region", nil)
+		var options struct {
+			Data []dict.DictOption `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &options))
+		assert.Len(t, options.Data, 3)
+		require.Equal(t, http.StatusOK, call(http.MethodPut, dataPath+"/"+idOf(js), map[string]string{"status": "0"}).Code)
+	})
+
+	t.Run("Cascade Delete And Restore", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, call(http.MethodDelete, dataPath+"/"+idOf(zj), nil).Code)
+		assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, dataPath+"/"+idOf(zj)+"?cascade=true", nil).Code)
+
+		var remaining int64
+		require.NoError(t, app.DB().Model(&model.SysDictData{}).Where("dict_type = ?", "sys_region").Count(&remaining).Error)
+		assert.Equal(t, int64(3), remaining)
+
+		w := call(http.MethodPost, "/api/v1/system/recycle-bin/dict_data/"+idOf(hz)+"/restore", nil)
+		assert.Equal(t, http.StatusConflict, w.Code, "parent is still deleted")
+
+		w = call(http.MethodPost, "/api/v1/system/recycle-bin/dict_data/"+idOf(zj)+"/restore", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, app.DB().Model(&model.SysDictData{}).Where("dict_type = ?", "sys_region").Count(&remaining).Error)
+		assert.Equal(t, int64(6), remaining)
+	})
+
+	t.Run("Import Hierarchy From CSV", func(t *testing.T) {
+		importCSV := func(content string, dryRun bool) *httptest.ResponseRecorder {
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, err := form.CreateFormFile("file", "regions.csv")
+			require.NoError(t, err)
+			_, err = part.Write([]byte(content))
+			require.NoError(t, err)
+			require.NoError(t, form.WriteField("dryRun", strconv.FormatBool(dryRun)))
+			require.NoError(t, form.Close())
+
+			req := httptest.NewRequest(http.MethodPost, dataPath+"/import", &body)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			w := httptest.NewRecorder()
+			app.Handler().ServeHTTP(w, req)
+			return w
+		}
+		decode := func(w *httptest.ResponseRecorder) dict.ImportResult {
+			var res struct {
+				Data dict.ImportResult `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			return res.Data
+		}
+
+		// 下级可以排在上级之前，也可以引用已有数据
+		content := "字典标签*,字典键值*,上级键值,排序\n" +
+			"宁波,3302,33,2\n" +
+			"海曙区,330203,3302,1\n" +
+			"苏州,3205,32,3\n" +
+			"不存在,9901,99,\n"
+		w := importCSV(content, true)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		result := decode(w)
+		assert.Equal(t, 4, result.Total)
+		assert.Equal(t, 1, result.Invalid)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, 5, result.Errors[0].Row)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, importCSV(content, false).Code)
+
+		content = "字典键值,字典标签,上级键值\n" +
+			"330203,海曙区,3302\n" +
+			"3302,宁波,33\n" +
+			"3201,南京市,32\n"
+		w = importCSV(content, false)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		result = decode(w)
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, 1, result.Updated)
+
+		w = call(http.MethodGet, "/api/v1/dicts/types/sys_region/children?parent=3302", nil)
+		assert.Contains(t, w.Body.String(), "海曙区")
+		w = call(http.MethodGet, "/api/v1/dicts/types/sys_region/children?parent=32", nil)
+		assert.Contains(t, w.Body.String(), "南京市")
+
+		w = importCSV("字典标签,字典键值,上级键值\n浙江,33,330203\n", true)
+		result = decode(w)
+		require.Len(t, result.Errors, 1)
+		assert.Contains(t, result.Errors[0].Errors[0], "cycle")
+	})
+}
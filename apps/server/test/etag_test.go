@@ -0,0 +1,157 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/system/post"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimisticConcurrency(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "etag_admin", "admin123")
+	token := Login(t, app, mr, "etag_admin", "admin123")
+
+	call := func(method, path, ifMatch string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	w := call(http.MethodPost, "/api/v1/system/posts", "", map[string]string{"postCode": "etag", "postName": "版本岗位"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data post.Post `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	path := "/api/v1/system/posts/" + strconv.FormatInt(created.Data.PostID, 10)
+
+	t.Run("Stale If-Match Is Rejected", func(t *testing.T) {
+		w := call(http.MethodGet, path, "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		first := w.Header().Get("ETag")
+		require.NotEmpty(t, first)
+
+		w = call(http.MethodPut, path, first, map[string]string{"postName": "第一次修改"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		// 另一个管理员仍持有旧版本
+		w = call(http.MethodPut, path, first, map[string]string{"postName": "覆盖修改"})
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		assert.Equal(t, http.StatusPreconditionFailed, call(http.MethodDelete, path, first, nil).Code)
+
+		w = call(http.MethodGet, path, "", nil)
+		var current struct {
+			Data post.Post `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &current))
+		assert.Equal(t, "第一次修改", current.Data.PostName)
+		second := w.Header().Get("ETag")
+		assert.NotEqual(t, first, second)
+
+		assert.Equal(t, http.StatusOK, call(http.MethodPut, path, "*", map[string]int{"postSort": 3}).Code, "wildcard matches any version")
+	})
+
+	t.Run("Missing If-Match Is Required", func(t *testing.T) {
+		etag := call(http.MethodGet, path, "", nil).Header().Get("ETag")
+		assert.Equal(t, http.StatusPreconditionRequired, call(http.MethodPut, path, "", map[string]string{"postName": "无条件修改"}).Code)
+		assert.Equal(t, http.StatusPreconditionRequired, call(http.MethodDelete, path, "", nil).Code)
+		assert.Equal(t, etag, call(http.MethodGet, path, "", nil).Header().Get("ETag"), "rejected writes leave the record untouched")
+
+		assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, path, etag, nil).Code)
+		assert.Equal(t, http.StatusNotFound, call(http.MethodPut, path, etag, map[string]string{"postName": "已删除"}).Code)
+	})
+
+	t.Run("Grant-Only Edit Changes ETag", func(t *testing.T) {
+		w := call(http.MethodPost, "/api/v1/system/roles", "", map[string]interface{}{"roleName": "版本角色", "roleKey": "etag_role"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var role struct {
+			Data struct {
+				RoleID int64 `json:"roleId"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &role))
+		rolePath := "/api/v1/system/roles/" + strconv.FormatInt(role.Data.RoleID, 10)
+
+		first := call(http.MethodGet, rolePath, "", nil).Header().Get("ETag")
+		require.NotEmpty(t, first)
+
+		// 仅调整菜单授权，不修改任何角色字段
+		w = call(http.MethodPut, rolePath, first, map[string]interface{}{"menuIds": []int64{1}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		second := call(http.MethodGet, rolePath, "", nil).Header().Get("ETag")
+		assert.NotEqual(t, first, second)
+		assert.Equal(t, http.StatusPreconditionFailed, call(http.MethodPut, rolePath, first, map[string]interface{}{"menuIds": []int64{}}).Code)
+	})
+
+	t.Run("Concurrent Writes With One ETag", func(t *testing.T) {
+		w := call(http.MethodPost, "/api/v1/system/posts", "", map[string]string{"postCode": "etag_race", "postName": "并发岗位"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var raced struct {
+			Data post.Post `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &raced))
+		racePath := "/api/v1/system/posts/" + strconv.FormatInt(raced.Data.PostID, 10)
+		version := call(http.MethodGet, racePath, "", nil).Header().Get("ETag")
+
+		// 两个管理员基于同一版本同时提交，只能有一个生效
+		const writers = 2
+		codes := make(chan int, writers)
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				codes <- call(http.MethodPut, racePath, version, map[string]string{"postName": "并发修改" + strconv.Itoa(i)}).Code
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+		close(codes)
+
+		counts := map[int]int{}
+		for code := range codes {
+			counts[code]++
+		}
+		assert.Equal(t, map[int]int{http.StatusOK: 1, http.StatusPreconditionFailed: 1}, counts)
+	})
+
+	t.Run("Other Modules Expose ETag", func(t *testing.T) {
+		for _, p := range []string{
+			"/api/v1/system/roles/1",
+			"/api/v1/system/menus/1",
+			"/api/v1/system/departments/100",
+			"/api/v1/system/configs/2",
+			"/api/v1/system/dicts/1",
+		} {
+			w := call(http.MethodGet, p, "", nil)
+			require.Equal(t, http.StatusOK, w.Code, p)
+			assert.NotEmpty(t, w.Header().Get("ETag"), p)
+		}
+
+		w := call(http.MethodPut, "/api/v1/system/roles/1", `"stale"`, map[string]string{"remark": "x"})
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		w = call(http.MethodPut, "/api/v1/system/roles/1", "", map[string]string{"remark": "x"})
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	})
+}
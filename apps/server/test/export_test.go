@@ -0,0 +1,121 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/pkg/spreadsheet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportModules(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "export_admin", "admin123")
+	token := Login(t, app, mr, "export_admin", "admin123")
+
+	download := func(t *testing.T, path string, format spreadsheet.Format) [][]string {
+		req := httptest.NewRequest(http.MethodGet, path+"?format="+string(format), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		app.Handler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), format.Extension())
+		rows, err := spreadsheet.ReadAll(format, bytes.NewReader(w.Body.Bytes()), 0)
+		assert.NoError(t, err)
+		return rows
+	}
+
+	t.Run("Export Users With Relations", func(t *testing.T) {
+		rows := download(t, "/api/v1/system/users/export", spreadsheet.FormatXLSX)
+		// 种子管理员加上测试创建的用户
+		if assert.Len(t, rows, 3) {
+			assert.Equal(t, "用户名称", rows[0][1])
+			assert.Equal(t, "export_admin", rows[2][1])
+			// 角色与岗位以名称导出
+			assert.Equal(t, "超级管理员", rows[2][8])
+			assert.Equal(t, "管理员", rows[2][10])
+		}
+	})
+
+	t.Run("Export Labels Follow Dictionary And Language", func(t *testing.T) {
+		send := func(method, path, acceptLanguage string, payload interface{}) *httptest.ResponseRecorder {
+			var body bytes.Buffer
+			if payload != nil {
+				_ = json.NewEncoder(&body).Encode(payload)
+			}
+			req := httptest.NewRequest(method, path, &body)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			if acceptLanguage != "" {
+				req.Header.Set("Accept-Language", acceptLanguage)
+			}
+			unconditional(req)
+			w := httptest.NewRecorder()
+			app.Handler().ServeHTTP(w, req)
+			return w
+		}
+		exported := func(acceptLanguage string) []string {
+			w := send(http.MethodGet, "/api/v1/system/users/export?format=csv", acceptLanguage, nil)
+			require.Equal(t, http.StatusOK, w.Code)
+			rows, err := spreadsheet.ReadAll(spreadsheet.FormatCSV, bytes.NewReader(w.Body.Bytes()), 0)
+			require.NoError(t, err)
+			require.Len(t, rows, 3)
+			return rows[2]
+		}
+
+		row := exported("")
+		assert.Equal(t, "男", row[6])
+		assert.Equal(t, "正常", row[7])
+
+		// 修改字典标签后导出随之变化
+		w := send(http.MethodPut, "/api/v1/system/dicts/3/data/6", "", map[string]string{"dictLabel": "启用"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "启用", exported("")[7])
+
+		w = send(http.MethodPut, "/api/v1/system/translations", "", map[string]string{
+			"resource": "dict", "scope": "sys_user_sex", "itemKey": "0", "locale": "en-US", "text": "Male",
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		row = exported("en-US")
+		assert.Equal(t, "Male", row[6])
+		assert.Equal(t, "启用", row[7], "untranslated labels keep the dictionary label")
+		assert.Equal(t, "男", exported("")[6])
+	})
+
+	t.Run("Export Roles", func(t *testing.T) {
+		rows := download(t, "/api/v1/system/roles/export", spreadsheet.FormatCSV)
+		assert.Len(t, rows, 3)
+	})
+
+	t.Run("Export Departments With Parent Names", func(t *testing.T) {
+		rows := download(t, "/api/v1/system/departments/export", spreadsheet.FormatCSV)
+		if assert.Greater(t, len(rows), 1) {
+			for _, row := range rows[1:] {
+				if row[1] == "研发部门" {
+					assert.Equal(t, "总部", row[2])
+				}
+			}
+		}
+	})
+
+	t.Run("Export Posts", func(t *testing.T) {
+		rows := download(t, "/api/v1/system/posts/export", spreadsheet.FormatCSV)
+		assert.Len(t, rows, 5)
+	})
+
+	t.Run("Reject Unknown Format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/system/posts/export?format=pdf", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		app.Handler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
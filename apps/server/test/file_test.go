@@ -0,0 +1,127 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileModule(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "file_admin", "admin123")
+	token := Login(t, app, mr, "file_admin", "admin123")
+
+	upload := func(t *testing.T, name, module string, content []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", name)
+		assert.NoError(t, err)
+		_, _ = part.Write(content)
+		assert.NoError(t, form.WriteField("module", module))
+		assert.NoError(t, form.WriteField("refId", "42"))
+		assert.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/system/files", &body)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	var uploaded file.File
+
+	t.Run("Upload Text File", func(t *testing.T) {
+		w := upload(t, "notes.txt", "docs", []byte("hello storage"))
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var res struct {
+			Data file.File `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		uploaded = res.Data
+		assert.Equal(t, "notes.txt", uploaded.OriginalName)
+		assert.Equal(t, int64(13), uploaded.Size)
+		assert.Equal(t, "text/plain", uploaded.ContentType)
+		assert.Equal(t, "docs", uploaded.Module)
+		assert.Equal(t, "42", uploaded.RefID)
+		assert.Len(t, uploaded.Checksum, 64)
+	})
+
+	t.Run("Reject Disallowed Type", func(t *testing.T) {
+		w := upload(t, "page.png", "docs", []byte("<html><script>alert(1)</script></html>"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("Public Module Is Reserved", func(t *testing.T) {
+		for _, module := range []string{"avatar", " Avatar "} {
+			w := upload(t, "report.pdf", module, []byte("%PDF-1.4 private report"))
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		}
+
+		var count int64
+		assert.NoError(t, app.DB().Model(&model.SysFile{}).Where("module = ?", "avatar").Count(&count).Error)
+		assert.Zero(t, count)
+
+		// 通用上传的文件不能通过公开地址访问
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/public/files/"+strconv.FormatInt(uploaded.FileID, 10), nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Download File", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/system/files/"+strconv.FormatInt(uploaded.FileID, 10)+"/download", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "hello storage", w.Body.String())
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "notes.txt")
+	})
+
+	t.Run("Presigned Download", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/system/files/"+strconv.FormatInt(uploaded.FileID, 10)+"/presign", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var res struct {
+			Data file.PresignedURL `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+
+		// 签名链接无需登录即可访问
+		w = httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, res.Data.URL, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "hello storage", w.Body.String())
+
+		w = httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, res.Data.URL+"0", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Delete File", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/system/files/"+strconv.FormatInt(uploaded.FileID, 10), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/system/files/"+strconv.FormatInt(uploaded.FileID, 10), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w = httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
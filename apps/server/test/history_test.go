@@ -0,0 +1,147 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	sysconfig "github.com/starter-kit-fe/admin/internal/system/config"
+	"github.com/starter-kit-fe/admin/internal/system/history"
+	"github.com/starter-kit-fe/admin/internal/system/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeHistory(t *testing.T) {
+	app, mr := SetupApp(t)
+	admin := CreateUser(t, app, "history_admin", "admin123")
+	token := Login(t, app, mr, "history_admin", "admin123")
+	operator := strconv.FormatUint(uint64(admin.ID), 10)
+
+	call := func(method, path, requestID string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		if requestID != "" {
+			req.Header.Set("X-Request-ID", requestID)
+		}
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	listHistory := func(path string) history.ListResult {
+		w := call(http.MethodGet, path, "", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data history.ListResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data
+	}
+	findChange := func(changes []history.FieldChange, field string) *history.FieldChange {
+		for i := range changes {
+			if changes[i].Field == field {
+				return &changes[i]
+			}
+		}
+		return nil
+	}
+
+	t.Run("Role Menu Grants", func(t *testing.T) {
+		w := call(http.MethodPost, "/api/v1/system/roles", "", map[string]interface{}{
+			"roleName": "历史角色",
+			"roleKey":  "history_role",
+			"menuIds":  []int64{1, 2},
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var created struct {
+			Data role.Role `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		path := "/api/v1/system/roles/" + strconv.FormatInt(created.Data.RoleID, 10)
+
+		w = call(http.MethodPut, path, "req-role-grants", map[string]interface{}{
+			"menuIds": []int64{2, 3},
+			"remark":  "调整授权",
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "req-role-grants", w.Header().Get("X-Request-ID"))
+
+		// 没有实际变化的修改不产生记录
+		require.Equal(t, http.StatusOK, call(http.MethodPut, path, "", map[string]interface{}{"menuIds": []int64{3, 2}}).Code)
+
+		result := listHistory(path + "/history")
+		require.Equal(t, int64(2), result.Total)
+		latest := result.List[0]
+		assert.Equal(t, history.ActionUpdate, latest.Action)
+		assert.Equal(t, operator, latest.Operator)
+		assert.Equal(t, "req-role-grants", latest.RequestID)
+
+		menus := findChange(latest.Changes, "menuIds")
+		require.NotNil(t, menus, "menu grant change recorded")
+		assert.Equal(t, []interface{}{float64(3)}, menus.Added)
+		assert.Equal(t, []interface{}{float64(1)}, menus.Removed)
+		remark := findChange(latest.Changes, "remark")
+		require.NotNil(t, remark)
+		assert.Nil(t, remark.Before)
+		assert.Equal(t, "调整授权", remark.After)
+		assert.Nil(t, findChange(latest.Changes, "updatedAt"), "audit columns are not diffed")
+
+		assert.Equal(t, history.ActionCreate, result.List[1].Action)
+		assert.NotEmpty(t, result.List[1].RequestID, "generated request id is stored")
+	})
+
+	t.Run("Config Value", func(t *testing.T) {
+		path := "/api/v1/system/configs/2"
+		w := call(http.MethodGet, path, "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var current struct {
+			Data sysconfig.Config `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &current))
+
+		require.Equal(t, http.StatusOK, call(http.MethodPut, path, "", map[string]string{"configValue": "history-value"}).Code)
+
+		result := listHistory(path + "/history?pageSize=1")
+		require.Len(t, result.List, 1)
+		assert.Equal(t, 1, result.PageSize)
+		require.Len(t, result.List[0].Changes, 1)
+		change := result.List[0].Changes[0]
+		assert.Equal(t, "configValue", change.Field)
+		assert.Equal(t, current.Data.ConfigValue, change.Before)
+		assert.Equal(t, "history-value", change.After)
+	})
+
+	t.Run("Delete Keeps Last Snapshot", func(t *testing.T) {
+		w := call(http.MethodPost, "/api/v1/system/dicts", "", map[string]string{"dictName": "历史字典", "dictType": "history_probe"})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var created struct {
+			Data struct {
+				ID int64 `json:"id"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		path := "/api/v1/system/dicts/" + strconv.FormatInt(created.Data.ID, 10)
+		require.Equal(t, http.StatusNoContent, call(http.MethodDelete, path, "", nil).Code)
+
+		result := listHistory(path + "/history")
+		require.Equal(t, int64(2), result.Total)
+		assert.Equal(t, history.ActionDelete, result.List[0].Action)
+		name := findChange(result.List[0].Changes, "dictName")
+		require.NotNil(t, name)
+		assert.Equal(t, "历史字典", name.Before)
+		assert.Nil(t, name.After)
+	})
+
+	t.Run("Invalid Id", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, call(http.MethodGet, "/api/v1/system/users/abc/history", "", nil).Code)
+	})
+}
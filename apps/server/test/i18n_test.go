@@ -0,0 +1,194 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+	"github.com/starter-kit-fe/admin/internal/system/i18n"
+)
+
+func TestTranslations(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "i18n_admin", "admin123")
+	token := Login(t, app, mr, "i18n_admin", "admin123")
+
+	call := func(method, path, acceptLanguage string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if raw, ok := payload.(string); ok {
+			body.WriteString(raw)
+		} else if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	sexLabels := func(acceptLanguage string) []string {
+		w := call(http.MethodGet, "/api/v1/dicts/types/sys_user_sex", acceptLanguage, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data []dict.DictOption `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		labels := make([]string, 0, len(res.Data))
+		for _, option := range res.Data {
+			labels = append(labels, option.DictLabel)
+		}
+		return labels
+	}
+	menuTitles := func(acceptLanguage string) []string {
+		w := call(http.MethodGet, "/api/v1/auth/menus", acceptLanguage, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data []struct {
+				Meta struct {
+					Title string `json:"title"`
+				} `json:"meta"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		titles := make([]string, 0, len(res.Data))
+		for _, node := range res.Data {
+			titles = append(titles, node.Meta.Title)
+		}
+		return titles
+	}
+
+	t.Run("Save Validation", func(t *testing.T) {
+		w := call(http.MethodPut, "/api/v1/system/translations", "", map[string]string{
+			"resource": "dict", "scope": "sys_user_sex", "itemKey": "0", "locale": "zh-CN", "text": "男性",
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code, "default locale keeps original labels")
+
+		w = call(http.MethodPut, "/api/v1/system/translations", "", map[string]string{
+			"resource": "dict", "scope": "sys_user_sex", "itemKey": "9", "locale": "en-US", "text": "Other",
+		})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = call(http.MethodPut, "/api/v1/system/translations", "", map[string]string{
+			"resource": "menu", "itemKey": "1", "locale": "not a locale", "text": "System",
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Localized Lookups", func(t *testing.T) {
+		w := call(http.MethodPut, "/api/v1/system/translations", "", map[string]string{
+			"resource": "dict", "scope": "sys_user_sex", "itemKey": "0", "locale": "en-us", "text": "Male",
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var saved struct {
+			Data i18n.Translation `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &saved))
+		assert.Equal(t, "en-US", saved.Data.Locale)
+		assert.Equal(t, "男", saved.Data.Source)
+
+		w = call(http.MethodPut, "/api/v1/system/translations", "", map[string]string{
+			"resource": "menu", "itemKey": "1", "locale": "en-US", "text": "System",
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		// 未翻译的条目回退为原始名称
+		assert.Equal(t, []string{"Male", "女", "未知"}, sexLabels("en"))
+		assert.Equal(t, []string{"男", "女", "未知"}, sexLabels(""))
+		assert.Equal(t, []string{"男", "女", "未知"}, sexLabels("fr-FR"))
+		assert.Contains(t, menuTitles("en-GB,en;q=0.9"), "System")
+		assert.Contains(t, menuTitles("zh-CN"), "系统管理")
+
+		w = call(http.MethodGet, "/api/v1/dicts/types/sys_user_sex/tree", "en-US", nil)
+		assert.Contains(t, w.Body.String(), "Male")
+
+		w = call(http.MethodGet, "/api/v1/i18n/locales", "en", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var locales struct {
+			Data struct {
+				Current   string   `json:"current"`
+				Available []string `json:"available"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &locales))
+		assert.Equal(t, "en-US", locales.Data.Current)
+		assert.Equal(t, []string{"zh-CN", "en-US"}, locales.Data.Available)
+	})
+
+	t.Run("User Preference Overrides Header", func(t *testing.T) {
+		w := call(http.MethodPut, "/api/v1/profile", "", map[string]string{"nickName": "i18n_admin", "locale": "zh-CN"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []string{"男", "女", "未知"}, sexLabels("en-US"))
+
+		w = call(http.MethodPut, "/api/v1/profile", "", map[string]string{"nickName": "i18n_admin", "locale": "en_US!"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = call(http.MethodPut, "/api/v1/profile", "", map[string]string{"nickName": "i18n_admin", "locale": ""})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []string{"Male", "女", "未知"}, sexLabels("en-US"))
+	})
+
+	t.Run("Bundle Export And Import", func(t *testing.T) {
+		w := call(http.MethodGet, "/api/v1/system/translations/export?locale=en-US", "", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var bundle i18n.Bundle
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bundle))
+		assert.Equal(t, "Male", bundle.Dicts["sys_user_sex"]["0"])
+		assert.Equal(t, "System", bundle.Menus["1"])
+
+		bundle.Dicts["sys_user_sex"]["1"] = "Female"
+		bundle.Menus["999999"] = "Missing"
+		w = call(http.MethodPost, "/api/v1/system/translations/import", "", bundle)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		var failed struct {
+			Data i18n.BundleImportResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &failed))
+		require.Len(t, failed.Data.Errors, 1)
+		assert.Equal(t, "999999", failed.Data.Errors[0].ItemKey)
+		assert.Equal(t, []string{"Male", "女", "未知"}, sexLabels("en-US"), "failed import writes nothing")
+
+		delete(bundle.Menus, "999999")
+		w = call(http.MethodPost, "/api/v1/system/translations/import?dryRun=true", "", bundle)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []string{"Male", "女", "未知"}, sexLabels("en-US"))
+
+		w = call(http.MethodPost, "/api/v1/system/translations/import", "", bundle)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var imported struct {
+			Data i18n.BundleImportResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &imported))
+		assert.Equal(t, 1, imported.Data.Created)
+		assert.Equal(t, 2, imported.Data.Updated)
+		assert.Equal(t, []string{"Male", "Female", "未知"}, sexLabels("en-US"))
+
+		w = call(http.MethodPost, "/api/v1/system/translations/import", "", `{"locale":"en-US"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Delete Restores Original Label", func(t *testing.T) {
+		w := call(http.MethodGet, "/api/v1/system/translations?resource=dict&scope=sys_user_sex&locale=en-US&keyword=Female", "", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var list struct {
+			Data []i18n.Translation `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.Len(t, list.Data, 1)
+		assert.Equal(t, "女", list.Data[0].Source)
+
+		w = call(http.MethodDelete, "/api/v1/system/translations/"+strconv.FormatInt(list.Data[0].ID, 10), "", nil)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+		assert.Equal(t, []string{"Male", "女", "未知"}, sexLabels("en-US"))
+	})
+}
@@ -16,7 +16,7 @@ func TestJobModule(t *testing.T) {
 	CreateUser(t, app, "job_admin", "admin123")
 	token := Login(t, app, mr, "job_admin", "admin123")
 
-	t.Run("List Jobs Empty", func(t *testing.T) {
+	t.Run("List Seeded Jobs", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/monitor/jobs", nil)
 		req.Header.Set("Authorization", "Bearer "+token)
 		w := httptest.NewRecorder()
@@ -30,7 +30,12 @@ func TestJobModule(t *testing.T) {
 		}
 		err := json.Unmarshal(w.Body.Bytes(), &res)
 		assert.NoError(t, err)
-		assert.Equal(t, int64(0), res.Data.Total)
+		// 种子数据包含每分钟执行的公告定时发布任务
+		if assert.Equal(t, int64(1), res.Data.Total) {
+			assert.Equal(t, "notice.schedule", res.Data.List[0].InvokeTarget)
+			assert.Equal(t, "* * * * *", res.Data.List[0].CronExpression)
+			assert.Equal(t, "0", res.Data.List[0].Status)
+		}
 	})
 
 	t.Run("Create Job", func(t *testing.T) {
@@ -68,8 +73,12 @@ func TestJobModule(t *testing.T) {
 			Data types.ListResult `json:"data"`
 		}
 		_ = json.Unmarshal(w.Body.Bytes(), &res)
-		assert.Equal(t, int64(1), res.Data.Total)
-		assert.Equal(t, "Test Job", res.Data.List[0].JobName)
+		assert.Equal(t, int64(2), res.Data.Total)
+		names := make([]string, 0, len(res.Data.List))
+		for _, job := range res.Data.List {
+			names = append(names, job.JobName)
+		}
+		assert.Contains(t, names, "Test Job")
 		// jobID = res.Data.List[0].JobID
 	})
 
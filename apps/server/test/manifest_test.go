@@ -0,0 +1,305 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+	"github.com/starter-kit-fe/admin/internal/system/history"
+	"github.com/starter-kit-fe/admin/internal/system/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifestSync(t *testing.T) {
+	app, mr := SetupApp(t)
+	admin := CreateUser(t, app, "manifest_admin", "admin123")
+	token := Login(t, app, mr, "manifest_admin", "admin123")
+
+	call := func(t *testing.T, method, path, contentType string, body []byte) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	sync := func(t *testing.T, query, contentType string, body []byte) *manifest.Plan {
+		w := call(t, http.MethodPost, "/api/v1/system/manifest/sync"+query, contentType, body)
+		if !assert.Equal(t, http.StatusOK, w.Code, w.Body.String()) {
+			t.FailNow()
+		}
+		var res struct {
+			Data manifest.Plan `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return &res.Data
+	}
+
+	w := call(t, http.MethodGet, "/api/v1/system/manifest/export?format=yaml", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	exported := w.Body.Bytes()
+
+	current, err := manifest.Decode(exported, manifest.FormatYAML)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, manifest.Version, current.Version)
+	assert.NotEmpty(t, current.Menus)
+	assert.NotEmpty(t, current.Roles)
+	assert.NotEmpty(t, current.Dicts)
+	assert.NotEmpty(t, current.Configs)
+
+	t.Run("Exported Manifest Is Already In Sync", func(t *testing.T) {
+		plan := sync(t, "", "application/yaml", exported)
+		assert.True(t, plan.Empty(), "unexpected changes: %+v", plan.Changes)
+
+		// JSON 格式的清单同样不产生变更
+		data, err := manifest.Encode(current, manifest.FormatJSON)
+		assert.NoError(t, err)
+		plan = sync(t, "?format=json", "", data)
+		assert.True(t, plan.Empty(), "unexpected changes: %+v", plan.Changes)
+	})
+
+	t.Run("Dry Run Then Apply", func(t *testing.T) {
+		desired, err := manifest.Decode(exported, "")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		var system *manifest.Menu
+		for i := range desired.Menus {
+			if desired.Menus[i].Path == "system" {
+				system = &desired.Menus[i]
+			}
+		}
+		if !assert.NotNil(t, system) {
+			return
+		}
+		system.Children = append(system.Children, manifest.Menu{
+			Name:    "清单测试",
+			Type:    "C",
+			Path:    "manifest-demo",
+			Perms:   "system:demo:list",
+			Icon:    "Box",
+			Order:   99,
+			Visible: "0",
+			Status:  "0",
+			Children: []manifest.Menu{
+				{Name: "清单测试导出", Type: "F", Perms: "system:demo:export", Order: 1, Visible: "0", Status: "0"},
+			},
+		})
+		desired.Roles = append(desired.Roles, manifest.Role{
+			Key:       "manifest_demo",
+			Name:      "清单测试角色",
+			Sort:      9,
+			Parent:    "admin",
+			DataScope: "5",
+			Status:    "0",
+			Menus:     []string{"/system", "/system/manifest-demo", "/system/manifest-demo#system:demo:export"},
+		})
+		desired.Dicts[0].Items = append(desired.Dicts[0].Items, manifest.DictItem{
+			Value: "manifest", Label: "清单", Sort: 99, IsDefault: "N", Status: "0",
+		})
+		desired.Configs = append(desired.Configs, manifest.Config{
+			Key: "sys.manifest.demo", Name: "清单测试参数", Value: "on", Type: "N",
+		})
+		for i := range desired.Configs {
+			if desired.Configs[i].Key == "sys.user.initPassword" {
+				desired.Configs[i].Value = "654321"
+			}
+		}
+
+		data, err := manifest.Encode(desired, manifest.FormatYAML)
+		assert.NoError(t, err)
+
+		plan := sync(t, "?dryRun=true", "", data)
+		assert.False(t, plan.Applied)
+		assert.Contains(t, plan.Changes, manifest.Change{Kind: manifest.KindMenu, Key: "/system/manifest-demo", Action: manifest.ActionCreate})
+		assert.Contains(t, plan.Changes, manifest.Change{Kind: manifest.KindRole, Key: "manifest_demo", Action: manifest.ActionCreate})
+		assert.Contains(t, plan.Changes, manifest.Change{Kind: manifest.KindConfig, Key: "sys.manifest.demo", Action: manifest.ActionCreate})
+		assert.Contains(t, plan.Changes, manifest.Change{Kind: manifest.KindConfig, Key: "sys.user.initPassword", Action: manifest.ActionUpdate, Fields: []string{"config_value"}})
+		assert.Contains(t, plan.Changes, manifest.Change{Kind: manifest.KindDictData, Key: desired.Dicts[0].Type + "/manifest", Action: manifest.ActionCreate})
+
+		// 预览不会写入数据库
+		var count int64
+		assert.NoError(t, app.DB().Model(&model.SysConfig{}).Where("config_key = ?", "sys.manifest.demo").Count(&count).Error)
+		assert.Zero(t, count)
+		assert.NoError(t, app.DB().Model(&model.SysMenu{}).Where("perms = ?", "system:demo:list").Count(&count).Error)
+		assert.Zero(t, count)
+
+		applied := sync(t, "", "", data)
+		assert.True(t, applied.Applied)
+		assert.Equal(t, plan.Changes, applied.Changes)
+
+		var role model.SysRole
+		if assert.NoError(t, app.DB().Where("role_key = ?", "manifest_demo").First(&role).Error) {
+			assert.Equal(t, "5", role.DataScope)
+			assert.Equal(t, int64(1), role.ParentID)
+			var grants int64
+			assert.NoError(t, app.DB().Model(&model.SysRoleMenu{}).Where("role_id = ?", role.ID).Count(&grants).Error)
+			assert.Equal(t, int64(3), grants)
+		}
+		var button model.SysMenu
+		if assert.NoError(t, app.DB().Where("perms = ?", "system:demo:export").First(&button).Error) {
+			assert.Equal(t, "F", button.MenuType)
+		}
+		var config model.SysConfig
+		assert.NoError(t, app.DB().Where("config_key = ?", "sys.user.initPassword").First(&config).Error)
+		assert.Equal(t, "654321", config.ConfigValue)
+
+		// 重复同步同一清单不产生变更
+		again := sync(t, "", "", data)
+		assert.True(t, again.Empty(), "unexpected changes: %+v", again.Changes)
+	})
+
+	t.Run("Prune Only Touches Sections In The Manifest", func(t *testing.T) {
+		var configs []model.SysConfig
+		assert.NoError(t, app.DB().Where("config_key <> ?", "sys.manifest.demo").Find(&configs).Error)
+		// 仅包含参数配置部分，菜单、角色与字典不受 prune 影响
+		partial := manifest.Manifest{Version: manifest.Version, Configs: make([]manifest.Config, 0, len(configs))}
+		for _, config := range configs {
+			item := manifest.Config{Key: config.ConfigKey, Name: config.ConfigName, Value: config.ConfigValue, Type: config.ConfigType}
+			if config.Remark != nil {
+				item.Remark = *config.Remark
+			}
+			partial.Configs = append(partial.Configs, item)
+		}
+		data, err := json.Marshal(partial)
+		assert.NoError(t, err)
+
+		plan := sync(t, "?prune=true", "application/json", data)
+		assert.Equal(t, []manifest.Change{{Kind: manifest.KindConfig, Key: "sys.manifest.demo", Action: manifest.ActionDelete}}, plan.Changes)
+
+		var count int64
+		assert.NoError(t, app.DB().Model(&model.SysConfig{}).Where("config_key = ?", "sys.manifest.demo").Count(&count).Error)
+		assert.Zero(t, count)
+		assert.NoError(t, app.DB().Model(&model.SysRole{}).Where("role_key = ?", "manifest_demo").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Sync Refreshes Caches And Records History", func(t *testing.T) {
+		loginWithoutCaptcha := func() int {
+			body, _ := json.Marshal(map[string]string{"username": "manifest_admin", "password": "admin123"})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			app.Handler().ServeHTTP(w, req)
+			return w.Code
+		}
+		sexLabels := func() []string {
+			w := call(t, http.MethodGet, "/api/v1/dicts/types/sys_user_sex", "", nil)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var res struct {
+				Data []dict.DictOption `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			labels := make([]string, 0, len(res.Data))
+			for _, option := range res.Data {
+				labels = append(labels, option.DictLabel)
+			}
+			return labels
+		}
+		listHistory := func(path string) history.ListResult {
+			w := call(t, http.MethodGet, path, "", nil)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var res struct {
+				Data history.ListResult `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			return res.Data
+		}
+
+		// 先读取一次，使参数与字典选项进入缓存
+		require.NotEqual(t, http.StatusOK, loginWithoutCaptcha(), "captcha is enabled by seed data")
+		require.Contains(t, sexLabels(), "未知")
+
+		desired := manifest.Manifest{
+			Version: manifest.Version,
+			Dicts: []manifest.Dict{{
+				Type: "sys_user_sex", Name: "用户性别", Status: "0", Remark: "用户性别列表",
+				Items: []manifest.DictItem{
+					{Value: "0", Label: "男", Sort: 1, IsDefault: "Y", Status: "0", Remark: "性别男"},
+					{Value: "1", Label: "女", Sort: 2, IsDefault: "N", Status: "0", Remark: "性别女"},
+					{Value: "2", Label: "保密", Sort: 3, IsDefault: "N", Status: "0", Remark: "性别未知"},
+				},
+			}},
+			Configs: []manifest.Config{{
+				Key: "sys.account.captchaEnabled", Name: "账号自助-验证码开关", Value: "false", Type: "Y",
+				Remark: "是否开启验证码功能（true开启，false关闭）",
+			}},
+		}
+		data, err := json.Marshal(desired)
+		require.NoError(t, err)
+		plan := sync(t, "", "application/json", data)
+		require.True(t, plan.Applied)
+
+		assert.Equal(t, http.StatusOK, loginWithoutCaptcha(), "settings cache is invalidated after sync")
+		labels := sexLabels()
+		assert.Contains(t, labels, "保密", "dict options cache is invalidated after sync")
+		assert.NotContains(t, labels, "未知")
+
+		configHistory := listHistory("/api/v1/system/configs/4/history")
+		if assert.NotEmpty(t, configHistory.List) {
+			assert.Equal(t, history.ActionUpdate, configHistory.List[0].Action)
+			assert.Equal(t, strconv.FormatUint(uint64(admin.ID), 10), configHistory.List[0].Operator)
+		}
+		itemHistory := listHistory("/api/v1/system/dicts/1/data/3/history")
+		if assert.NotEmpty(t, itemHistory.List) {
+			assert.Equal(t, history.ActionUpdate, itemHistory.List[0].Action)
+			require.Len(t, itemHistory.List[0].Changes, 1)
+			assert.Equal(t, "dictLabel", itemHistory.List[0].Changes[0].Field)
+		}
+	})
+
+	t.Run("Reject Invalid Manifest", func(t *testing.T) {
+		w := call(t, http.MethodPost, "/api/v1/system/manifest/sync", "application/json", []byte(`{"version": 2, "configs": []}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unsupported version")
+
+		w = call(t, http.MethodPost, "/api/v1/system/manifest/sync", "application/yaml", []byte("version: 1\nroles:\n  - key: demo\n    nmae: typo\n"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = call(t, http.MethodPost, "/api/v1/system/manifest/sync", "application/yaml", []byte("version: 1\nroles:\n  - key: demo\n    name: a\n  - key: demo\n    name: b\n"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "duplicate role")
+
+		assert.Equal(t, http.StatusBadRequest, call(t, http.MethodGet, "/api/v1/system/manifest/export?format=xml", "", nil).Code)
+	})
+
+	t.Run("Reject Config Values Failing Their Type", func(t *testing.T) {
+		before := call(t, http.MethodGet, "/api/v1/system/configs/4", "", nil)
+		require.Equal(t, http.StatusOK, before.Code)
+
+		for _, body := range []string{
+			`{"version": 1, "configs": [{"key": "test.manifest.int", "name": "整数", "type": "N", "valueType": "int", "value": "abc"}]}`,
+			`{"version": 1, "configs": [{"key": "test.manifest.enum", "name": "枚举", "type": "N", "valueType": "enum", "value": "a"}]}`,
+			`{"version": 1, "configs": [{"key": "test.manifest.secret", "name": "密钥", "type": "N", "valueType": "secret", "public": true}]}`,
+			// 未声明值类型时按现有类型校验
+			`{"version": 1, "configs": [{"key": "sys.account.captchaEnabled", "name": "账号自助-验证码开关", "type": "Y", "value": "maybe"}]}`,
+		} {
+			w := call(t, http.MethodPost, "/api/v1/system/manifest/sync", "application/json", []byte(body))
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+			assert.Contains(t, w.Body.String(), "invalid manifest", body)
+		}
+
+		assert.Equal(t, before.Body.String(), call(t, http.MethodGet, "/api/v1/system/configs/4", "", nil).Body.String())
+		w := call(t, http.MethodGet, "/api/v1/system/configs", "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "test.manifest.")
+	})
+}
@@ -0,0 +1,253 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/notice"
+	"github.com/starter-kit-fe/admin/internal/system/role"
+)
+
+func TestNoticeInbox(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "inbox_admin", "admin123")
+	adminToken := Login(t, app, mr, "inbox_admin", "admin123")
+
+	call := func(token, method, path string, payload any) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	parent := &model.SysDept{DeptName: "公告总部", Ancestors: "0", Status: "0"}
+	require.NoError(t, app.DB().Create(parent).Error)
+	child := &model.SysDept{
+		DeptName:  "公告分部",
+		ParentID:  int64(parent.ID),
+		Ancestors: "0," + strconv.FormatUint(uint64(parent.ID), 10),
+		Status:    "0",
+	}
+	require.NoError(t, app.DB().Create(child).Error)
+	post := &model.SysPost{PostCode: "notice_post", PostName: "公告岗位", Status: "0"}
+	require.NoError(t, app.DB().Create(post).Error)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("member123"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	newMember := func(name string, deptID *int64) *model.SysUser {
+		member := &model.SysUser{UserName: name, NickName: name, Password: string(hash), Status: "0", DeptID: deptID}
+		require.NoError(t, app.DB().Create(member).Error)
+		return member
+	}
+	childID := int64(child.ID)
+	alice := newMember("inbox_alice", &childID)
+	bob := newMember("inbox_bob", nil)
+	carol := newMember("inbox_carol", nil)
+	require.NoError(t, app.DB().Create(&model.SysUserPost{UserID: int64(carol.ID), PostID: int64(post.ID)}).Error)
+
+	w := call(adminToken, http.MethodPost, "/api/v1/system/roles", map[string]any{
+		"roleName": "公告岗位角色",
+		"roleKey":  "notice_post_role",
+		"postIds":  []int64{int64(post.ID)},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var createdRole struct {
+		Data role.Role `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &createdRole))
+
+	createNotice := func(title, status, audienceType string, targetIDs []int64) notice.Notice {
+		w := call(adminToken, http.MethodPost, "/api/v1/system/notices", map[string]any{
+			"noticeTitle":   title,
+			"noticeType":    "1",
+			"noticeContent": title + " content",
+			"status":        status,
+			"audienceType":  audienceType,
+			"targetIds":     targetIDs,
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var res struct {
+			Data notice.Notice `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data
+	}
+	inbox := func(token, query string) notice.Inbox {
+		w := call(token, http.MethodGet, "/api/v1/notices/inbox"+query, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data notice.Inbox `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data
+	}
+	unreadCount := func(token string) int64 {
+		w := call(token, http.MethodGet, "/api/v1/notices/unread-count", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data struct {
+				UnreadCount int64 `json:"unreadCount"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data.UnreadCount
+	}
+	titles := func(box notice.Inbox) []string {
+		result := make([]string, 0, len(box.Items))
+		for _, item := range box.Items {
+			result = append(result, item.NoticeTitle)
+		}
+		return result
+	}
+	readStats := func(id int64) notice.ReadStats {
+		w := call(adminToken, http.MethodGet, fmt.Sprintf("/api/v1/system/notices/%d/reads", id), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data notice.ReadStats `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data
+	}
+
+	all := createNotice("全员公告", "0", "", nil)
+	toBob := createNotice("指定用户", "0", "users", []int64{int64(bob.ID)})
+	toDept := createNotice("部门公告", "0", "depts", []int64{int64(parent.ID)})
+	toRole := createNotice("角色公告", "0", "roles", []int64{createdRole.Data.RoleID})
+	createNotice("已关闭", "1", "all", nil)
+	assert.Equal(t, notice.AudienceAll, all.AudienceType)
+	assert.Equal(t, []int64{int64(parent.ID)}, toDept.TargetIDs)
+
+	aliceToken := Login(t, app, mr, "inbox_alice", "member123")
+	bobToken := Login(t, app, mr, "inbox_bob", "member123")
+	carolToken := Login(t, app, mr, "inbox_carol", "member123")
+
+	t.Run("Invalid Audience", func(t *testing.T) {
+		cases := []map[string]any{
+			{"audienceType": "roles"},
+			{"audienceType": "users", "targetIds": []int64{999999}},
+			{"audienceType": "everyone"},
+		}
+		for _, payload := range cases {
+			payload["noticeTitle"] = "invalid"
+			payload["noticeType"] = "1"
+			payload["noticeContent"] = "invalid"
+			w := call(adminToken, http.MethodPost, "/api/v1/system/notices", payload)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Audience Targeting", func(t *testing.T) {
+		box := inbox(aliceToken, "")
+		assert.Equal(t, []string{"部门公告", "全员公告"}, titles(box), "sub-department members receive department notices")
+		assert.Equal(t, int64(2), box.Total)
+		assert.Equal(t, int64(2), unreadCount(aliceToken))
+		assert.Equal(t, []string{"指定用户", "全员公告"}, titles(inbox(bobToken, "")))
+		assert.Equal(t, []string{"角色公告", "全员公告"}, titles(inbox(carolToken, "")), "roles inherited through posts count")
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		page := inbox(aliceToken, "?pageNum=2&pageSize=1")
+		assert.Equal(t, []string{"全员公告"}, titles(page))
+		assert.Equal(t, int64(2), page.Total)
+		assert.Equal(t, 2, page.PageNum)
+		assert.Equal(t, 1, page.PageSize)
+
+		page = inbox(aliceToken, "")
+		assert.Equal(t, 1, page.PageNum)
+		assert.Equal(t, 10, page.PageSize, "default page size")
+		assert.Equal(t, 100, inbox(aliceToken, "?pageSize=1000").PageSize, "page size is capped")
+	})
+
+	t.Run("Mark Read", func(t *testing.T) {
+		w := call(aliceToken, http.MethodPost, fmt.Sprintf("/api/v1/notices/%d/read", toBob.NoticeID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, "notices outside the audience cannot be read")
+
+		w = call(aliceToken, http.MethodPost, fmt.Sprintf("/api/v1/notices/%d/read", toDept.NoticeID), nil)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+		w = call(aliceToken, http.MethodPost, fmt.Sprintf("/api/v1/notices/%d/read", toDept.NoticeID), nil)
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+		box := inbox(aliceToken, "")
+		assert.Equal(t, int64(1), unreadCount(aliceToken))
+		require.Len(t, box.Items, 2)
+		assert.True(t, box.Items[0].Read)
+		assert.NotNil(t, box.Items[0].ReadAt)
+		assert.False(t, box.Items[1].Read)
+
+		unread := inbox(aliceToken, "?unreadOnly=true")
+		assert.Equal(t, []string{"全员公告"}, titles(unread))
+		assert.Equal(t, int64(1), unread.Total)
+
+		w = call(aliceToken, http.MethodPost, "/api/v1/notices/read-all", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var marked struct {
+			Data struct {
+				Marked int `json:"marked"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &marked))
+		assert.Equal(t, 1, marked.Data.Marked)
+		assert.Equal(t, int64(0), unreadCount(aliceToken))
+	})
+
+	t.Run("Read Statistics", func(t *testing.T) {
+		stats := readStats(toDept.NoticeID)
+		assert.Equal(t, notice.AudienceDepts, stats.AudienceType)
+		assert.Equal(t, 1, stats.Total)
+		assert.Equal(t, 1, stats.ReadCount)
+		require.Len(t, stats.Readers, 1)
+		assert.Equal(t, int64(alice.ID), stats.Readers[0].UserID)
+
+		stats = readStats(toRole.NoticeID)
+		assert.Equal(t, 1, stats.Total)
+		assert.Equal(t, 0, stats.ReadCount)
+		require.Len(t, stats.UnreadUsers, 1)
+		assert.Equal(t, int64(carol.ID), stats.UnreadUsers[0].UserID)
+
+		stats = readStats(all.NoticeID)
+		assert.Equal(t, 1, stats.ReadCount)
+		assert.Equal(t, stats.Total-1, stats.UnreadCount)
+
+		w := call(aliceToken, http.MethodGet, fmt.Sprintf("/api/v1/system/notices/%d/reads", all.NoticeID), nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Change Audience", func(t *testing.T) {
+		w := call(adminToken, http.MethodPut, fmt.Sprintf("/api/v1/system/notices/%d", toBob.NoticeID), map[string]any{
+			"audienceType": "all",
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data notice.Notice `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, notice.AudienceAll, res.Data.AudienceType)
+		assert.Empty(t, res.Data.TargetIDs)
+
+		box := inbox(aliceToken, "")
+		assert.Contains(t, titles(box), "指定用户")
+		assert.Equal(t, int64(1), unreadCount(aliceToken))
+
+		w = call(adminToken, http.MethodPut, fmt.Sprintf("/api/v1/system/notices/%d", toBob.NoticeID), map[string]any{
+			"audienceType": "users",
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code, "switching to targeted audience requires targets")
+	})
+}
@@ -0,0 +1,148 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/job/executor"
+	"github.com/starter-kit-fe/admin/internal/system/job/types"
+	"github.com/starter-kit-fe/admin/internal/system/notice"
+)
+
+func TestNoticeSchedule(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "schedule_admin", "admin123")
+	token := Login(t, app, mr, "schedule_admin", "admin123")
+
+	call := func(method, path string, payload any) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	createNotice := func(payload map[string]any) notice.Notice {
+		payload["noticeType"] = "2"
+		payload["noticeContent"] = payload["noticeTitle"]
+		w := call(http.MethodPost, "/api/v1/system/notices", payload)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var res struct {
+			Data notice.Notice `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data
+	}
+	announcements := func(query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/public/notices"+query, nil)
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data []notice.Announcement `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		titles := make([]string, 0, len(res.Data))
+		for _, item := range res.Data {
+			titles = append(titles, item.NoticeTitle)
+		}
+		return titles
+	}
+	status := func(id int64) string {
+		var record model.SysNotice
+		require.NoError(t, app.DB().First(&record, id).Error)
+		return record.Status
+	}
+	format := func(ts time.Time) string {
+		return ts.Format(time.RFC3339)
+	}
+
+	now := time.Now()
+	scheduled := createNotice(map[string]any{"noticeTitle": "定时公告", "publishAt": format(now.Add(time.Hour))})
+	plain := createNotice(map[string]any{"noticeTitle": "普通公告", "expireAt": format(now.Add(time.Hour))})
+	createNotice(map[string]any{"noticeTitle": "高优先级", "priority": 5})
+	createNotice(map[string]any{"noticeTitle": "置顶公告", "pinned": true})
+	expired := createNotice(map[string]any{"noticeTitle": "过期公告", "expireAt": format(now.Add(-time.Minute))})
+	createNotice(map[string]any{"noticeTitle": "关闭公告", "status": "1"})
+	createNotice(map[string]any{"noticeTitle": "定向公告", "audienceType": "users", "targetIds": []int64{1}})
+
+	assert.Equal(t, "2", scheduled.Status)
+	assert.Equal(t, "0", plain.Status)
+	assert.Equal(t, "3", expired.Status)
+
+	t.Run("Invalid Schedule", func(t *testing.T) {
+		w := call(http.MethodPost, "/api/v1/system/notices", map[string]any{
+			"noticeTitle": "invalid", "noticeType": "1", "noticeContent": "invalid",
+			"publishAt": format(now.Add(time.Hour)), "expireAt": format(now),
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		w = call(http.MethodPost, "/api/v1/system/notices", map[string]any{
+			"noticeTitle": "invalid", "noticeType": "1", "noticeContent": "invalid", "publishAt": "tomorrow",
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+
+	t.Run("Active Announcements", func(t *testing.T) {
+		assert.Equal(t, []string{"置顶公告", "高优先级", "普通公告"}, announcements(""))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/public/notices?tenant=missing", nil)
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = call(http.MethodGet, "/api/v1/system/notices?status=all", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var list struct {
+			Data []notice.Notice `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.NotEmpty(t, list.Data)
+		assert.Equal(t, "置顶公告", list.Data[0].NoticeTitle)
+	})
+
+	t.Run("Window Applies Before Executor Runs", func(t *testing.T) {
+		require.NoError(t, app.DB().Model(&model.SysNotice{}).Where("id = ?", scheduled.NoticeID).
+			Update("publish_at", now.Add(-time.Minute)).Error)
+		require.NoError(t, app.DB().Model(&model.SysNotice{}).Where("id = ?", plain.NoticeID).
+			Update("expire_at", now.Add(-time.Second)).Error)
+
+		assert.Equal(t, []string{"置顶公告", "高优先级", "定时公告"}, announcements(""))
+		assert.Equal(t, "2", status(scheduled.NoticeID))
+		assert.Equal(t, "0", status(plain.NoticeID))
+
+		exec := executor.NewNoticeScheduleExecutor(notice.NewService(notice.NewRepository(app.DB()), nil, nil))
+		require.NoError(t, exec(context.Background(), types.ExecutionPayload{}))
+		assert.Equal(t, "0", status(scheduled.NoticeID))
+		assert.Equal(t, "3", status(plain.NoticeID))
+		assert.Equal(t, "3", status(expired.NoticeID))
+	})
+
+	t.Run("Extending Expiry Republishes", func(t *testing.T) {
+		w := call(http.MethodPut, fmt.Sprintf("/api/v1/system/notices/%d", expired.NoticeID), map[string]any{
+			"expireAt": "",
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data notice.Notice `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, "0", res.Data.Status)
+		assert.Nil(t, res.Data.ExpireAt)
+		assert.Contains(t, announcements(""), "过期公告")
+	})
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/system/permission"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionModule(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "perm_admin", "admin123")
+	token := Login(t, app, mr, "perm_admin", "admin123")
+
+	t.Run("List Routes By Permission", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/system/permissions/routes?permission=system:user:list", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		app.Handler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var res struct {
+			Data []permission.Route `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &res)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.Data)
+		assert.Contains(t, res.Data, permission.Route{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/system/users",
+			Permissions: []string{"system:user:list"},
+			Description: "list users",
+		})
+	})
+
+	t.Run("Audit Seeded Menus", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/system/permissions/audit", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		app.Handler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var res struct {
+			Data permission.AuditReport `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &res)
+		assert.NoError(t, err)
+		assert.Greater(t, res.Data.RouteCount, 0)
+		// 种子菜单应覆盖所有路由所需权限
+		assert.Empty(t, res.Data.MissingInMenus)
+	})
+}
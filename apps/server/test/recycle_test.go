@@ -0,0 +1,220 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/dict"
+	"github.com/starter-kit-fe/admin/internal/system/job/executor"
+	"github.com/starter-kit-fe/admin/internal/system/job/types"
+	"github.com/starter-kit-fe/admin/internal/system/recycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecycleBin(t *testing.T) {
+	app, mr := SetupApp(t)
+	operator := CreateUser(t, app, "recycle_admin", "admin123")
+	token := Login(t, app, mr, "recycle_admin", "admin123")
+
+	call := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	idOf := func(id uint) string {
+		return strconv.FormatUint(uint64(id), 10)
+	}
+
+	t.Run("List And Restore Deleted User", func(t *testing.T) {
+		victim := CreateUser(t, app, "recycle_victim", "admin123")
+		assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/api/v1/system/users/"+idOf(victim.ID)).Code)
+
+		w := call(http.MethodGet, "/api/v1/system/recycle-bin/user")
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res struct {
+			Data recycle.ListResult `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		if assert.Len(t, res.Data.List, 1) {
+			assert.Equal(t, "recycle_victim", res.Data.List[0].Name)
+			assert.Equal(t, idOf(operator.ID), res.Data.List[0].DeletedBy)
+			assert.False(t, res.Data.List[0].DeletedAt.IsZero())
+		}
+
+		// 同名用户已存在时拒绝恢复
+		taken := CreateUser(t, app, "recycle_victim", "admin123")
+		w = call(http.MethodPost, "/api/v1/system/recycle-bin/user/"+idOf(victim.ID)+"/restore")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "recycle_victim")
+
+		assert.NoError(t, app.DB().Unscoped().Delete(taken).Error)
+		w = call(http.MethodPost, "/api/v1/system/recycle-bin/user/"+idOf(victim.ID)+"/restore")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var restored model.SysUser
+		assert.NoError(t, app.DB().First(&restored, victim.ID).Error)
+		assert.Equal(t, http.StatusNotFound, call(http.MethodPost, "/api/v1/system/recycle-bin/user/"+idOf(victim.ID)+"/restore").Code)
+	})
+
+	t.Run("Purge Deleted User With Its Relations", func(t *testing.T) {
+		victim := CreateUser(t, app, "recycle_purged", "admin123")
+		userID := int64(victim.ID)
+		assert.NoError(t, app.DB().Create(&model.SysUserAttr{UserID: userID, AttrKey: "employee_no", AttrValue: "E001"}).Error)
+		assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/api/v1/system/users/"+idOf(victim.ID)).Code)
+
+		assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/api/v1/system/recycle-bin/user/"+idOf(victim.ID)).Code)
+
+		var remaining int64
+		assert.NoError(t, app.DB().Unscoped().Model(&model.SysUser{}).Where("id = ?", userID).Count(&remaining).Error)
+		assert.Zero(t, remaining)
+		for _, relation := range []interface{}{&model.SysUserRole{}, &model.SysUserPost{}, &model.SysUserAttr{}} {
+			assert.NoError(t, app.DB().Model(relation).Where("user_id = ?", userID).Count(&remaining).Error)
+			assert.Zero(t, remaining, "%T", relation)
+		}
+	})
+
+	t.Run("Restore Dictionary Type With Its Data", func(t *testing.T) {
+		dictType := &model.SysDictType{DictName: "回收测试", DictType: "recycle_test", Status: "0"}
+		assert.NoError(t, app.DB().Create(dictType).Error)
+		for i, value := range []string{"a", "b"} {
+			assert.NoError(t, app.DB().Create(&model.SysDictData{DictSort: i, DictLabel: value, DictValue: value, DictType: "recycle_test", Status: "0"}).Error)
+		}
+		assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/api/v1/system/dicts/"+idOf(dictType.ID)).Code)
+
+		// 删除后读取一次，使“类型不存在”进入缓存
+		optionsPath := "/api/v1/dicts/types/recycle_test"
+		require.Equal(t, http.StatusNotFound, call(http.MethodGet, optionsPath).Code)
+
+		var data model.SysDictData
+		assert.NoError(t, app.DB().Unscoped().Where("dict_type = ?", "recycle_test").First(&data).Error)
+		// 字典类型已删除时不能单独恢复字典数据
+		assert.Equal(t, http.StatusConflict, call(http.MethodPost, "/api/v1/system/recycle-bin/dict_data/"+idOf(data.ID)+"/restore").Code)
+
+		assert.Equal(t, http.StatusOK, call(http.MethodPost, "/api/v1/system/recycle-bin/dict_type/"+idOf(dictType.ID)+"/restore").Code)
+		var active int64
+		assert.NoError(t, app.DB().Model(&model.SysDictData{}).Where("dict_type = ?", "recycle_test").Count(&active).Error)
+		assert.Equal(t, int64(2), active)
+		w := call(http.MethodGet, optionsPath)
+		require.Equal(t, http.StatusOK, w.Code, "restored options are visible without waiting for cache expiry")
+		var res struct {
+			Data []dict.DictOption `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Len(t, res.Data, 2)
+	})
+
+	t.Run("Purge Dictionary Removes Label Translations", func(t *testing.T) {
+		dictType := &model.SysDictType{DictName: "译文回收", DictType: "recycle_i18n", Status: "0"}
+		require.NoError(t, app.DB().Create(dictType).Error)
+		items := make(map[string]*model.SysDictData)
+		for i, value := range []string{"a", "b", "c"} {
+			items[value] = &model.SysDictData{DictSort: i, DictLabel: value, DictValue: value, DictType: "recycle_i18n", Status: "0"}
+			require.NoError(t, app.DB().Create(items[value]).Error)
+			require.NoError(t, app.DB().Create(&model.SysTranslation{Resource: "dict", Scope: "recycle_i18n", ItemKey: value, Locale: "en-US", Text: "EN " + value}).Error)
+		}
+		translated := func() []string {
+			var keys []string
+			require.NoError(t, app.DB().Unscoped().Model(&model.SysTranslation{}).
+				Where("resource = ? AND scope = ?", "dict", "recycle_i18n").Order("item_key").Pluck("item_key", &keys).Error)
+			return keys
+		}
+		dataPath := "/api/v1/system/dicts/" + idOf(dictType.ID) + "/data/"
+
+		// b 删除后重建，新数据沿用原译文
+		for _, value := range []string{"a", "b"} {
+			require.Equal(t, http.StatusNoContent, call(http.MethodDelete, dataPath+idOf(items[value].ID)).Code)
+		}
+		require.NoError(t, app.DB().Create(&model.SysDictData{DictSort: 3, DictLabel: "b", DictValue: "b", DictType: "recycle_i18n", Status: "0"}).Error)
+		for _, value := range []string{"a", "b"} {
+			assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/api/v1/system/recycle-bin/dict_data/"+idOf(items[value].ID)).Code)
+		}
+		assert.Equal(t, []string{"b", "c"}, translated())
+
+		require.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/api/v1/system/dicts/"+idOf(dictType.ID)).Code)
+		assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/api/v1/system/recycle-bin/dict_type/"+idOf(dictType.ID)).Code)
+		assert.Empty(t, translated())
+	})
+
+	t.Run("Purge Deleted Post", func(t *testing.T) {
+		post := &model.SysPost{PostCode: "recycle", PostName: "回收岗位", Status: "0"}
+		assert.NoError(t, app.DB().Create(post).Error)
+		assert.NoError(t, app.DB().Create(&model.SysUserPost{UserID: int64(operator.ID), PostID: int64(post.ID)}).Error)
+		assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/api/v1/system/posts/"+idOf(post.ID)).Code)
+
+		assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/api/v1/system/recycle-bin/post/"+idOf(post.ID)).Code)
+
+		var remaining int64
+		assert.NoError(t, app.DB().Unscoped().Model(&model.SysPost{}).Where("id = ?", post.ID).Count(&remaining).Error)
+		assert.Zero(t, remaining)
+		assert.NoError(t, app.DB().Model(&model.SysUserPost{}).Where("post_id = ?", post.ID).Count(&remaining).Error)
+		assert.Zero(t, remaining)
+
+		// 未删除的记录不能被彻底删除
+		assert.Equal(t, http.StatusNotFound, call(http.MethodDelete, "/api/v1/system/recycle-bin/post/1").Code)
+	})
+
+	t.Run("Restore Config Refreshes Settings", func(t *testing.T) {
+		loginWithoutCaptcha := func() int {
+			body, _ := json.Marshal(map[string]string{"username": "recycle_admin", "password": "admin123"})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			app.Handler().ServeHTTP(w, req)
+			return w.Code
+		}
+
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/system/configs/4", bytes.NewReader([]byte(`{"configValue":"false"}`)))
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Equal(t, http.StatusOK, loginWithoutCaptcha())
+
+		// 删除后参数不存在，验证码回到默认开启，且“不存在”被缓存
+		require.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/api/v1/system/configs/4").Code)
+		require.NotEqual(t, http.StatusOK, loginWithoutCaptcha())
+
+		assert.Equal(t, http.StatusOK, call(http.MethodPost, "/api/v1/system/recycle-bin/config/4/restore").Code)
+		assert.Equal(t, http.StatusOK, loginWithoutCaptcha(), "restored config is visible without waiting for cache expiry")
+	})
+
+	t.Run("Summary And Unknown Resource", func(t *testing.T) {
+		w := call(http.MethodGet, "/api/v1/system/recycle-bin")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "dict_data")
+
+		assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "/api/v1/system/recycle-bin/menu").Code)
+	})
+
+	t.Run("Retention Executor Purges Expired Records", func(t *testing.T) {
+		expired := &model.SysNotice{NoticeTitle: "过期公告", NoticeType: "1", Status: "0"}
+		recent := &model.SysNotice{NoticeTitle: "近期公告", NoticeType: "1", Status: "0"}
+		assert.NoError(t, app.DB().Create(expired).Error)
+		assert.NoError(t, app.DB().Create(recent).Error)
+		assert.NoError(t, app.DB().Model(expired).Update("deleted_at", time.Now().AddDate(0, 0, -10)).Error)
+		assert.NoError(t, app.DB().Delete(recent).Error)
+
+		exec := executor.NewRecyclePurgeExecutor(recycle.NewService(recycle.NewRepository(app.DB())))
+		err := exec(context.Background(), types.ExecutionPayload{Params: json.RawMessage(`{"retentionDays":7,"resources":["notice"]}`)})
+		assert.NoError(t, err)
+
+		var count int64
+		assert.NoError(t, app.DB().Unscoped().Model(&model.SysNotice{}).Where("id = ?", expired.ID).Count(&count).Error)
+		assert.Zero(t, count)
+		assert.NoError(t, app.DB().Unscoped().Model(&model.SysNotice{}).Where("id = ?", recent.ID).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
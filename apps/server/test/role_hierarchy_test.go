@@ -0,0 +1,186 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/role"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRoleHierarchy(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "hierarchy_admin", "admin123")
+	adminToken := Login(t, app, mr, "hierarchy_admin", "admin123")
+
+	call := func(t *testing.T, token, method, path string, payload any) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			assert.NoError(t, json.NewEncoder(&body).Encode(payload))
+		}
+		req := httptest.NewRequest(method, path, &body)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	menuID := func(t *testing.T, perms string) int64 {
+		var menu model.SysMenu
+		if err := app.DB().Where("perms = ?", perms).First(&menu).Error; err != nil {
+			t.Fatalf("failed to load menu %s: %v", perms, err)
+		}
+		return int64(menu.ID)
+	}
+
+	decodeRole := func(t *testing.T, w *httptest.ResponseRecorder) role.Role {
+		var res struct {
+			Data role.Role `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data
+	}
+
+	rolePath := func(id int64) string {
+		return "/api/v1/system/roles/" + strconv.FormatInt(id, 10)
+	}
+
+	noticeMenu := menuID(t, "system:notice:list")
+	postMenu := menuID(t, "system:post:list")
+
+	w := call(t, adminToken, http.MethodPost, "/api/v1/system/roles", map[string]any{
+		"roleName": "审计员",
+		"roleKey":  "auditor",
+		"menuIds":  []int64{noticeMenu},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	auditor := decodeRole(t, w)
+
+	w = call(t, adminToken, http.MethodPost, "/api/v1/system/roles", map[string]any{
+		"roleName": "高级审计员",
+		"roleKey":  "senior_auditor",
+		"parentId": auditor.RoleID,
+		"menuIds":  []int64{postMenu},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	senior := decodeRole(t, w)
+	assert.Equal(t, auditor.RoleID, senior.ParentID)
+
+	t.Run("Role Detail Separates Own And Inherited Menus", func(t *testing.T) {
+		w := call(t, adminToken, http.MethodGet, rolePath(senior.RoleID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		detail := decodeRole(t, w)
+		assert.Equal(t, []int64{postMenu}, detail.MenuIDs)
+		assert.Equal(t, []int64{noticeMenu}, detail.InheritedMenuIDs)
+
+		var rows int64
+		assert.NoError(t, app.DB().Model(&model.SysRoleMenu{}).Where("role_id = ?", senior.RoleID).Count(&rows).Error)
+		assert.Equal(t, int64(1), rows)
+	})
+
+	t.Run("Reject Invalid Parents", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, call(t, adminToken, http.MethodPut, rolePath(auditor.RoleID), map[string]any{"parentId": senior.RoleID}).Code)
+		assert.Equal(t, http.StatusBadRequest, call(t, adminToken, http.MethodPut, rolePath(auditor.RoleID), map[string]any{"parentId": auditor.RoleID}).Code)
+		assert.Equal(t, http.StatusBadRequest, call(t, adminToken, http.MethodPut, rolePath(auditor.RoleID), map[string]any{"parentId": 999999}).Code)
+
+		// 间接循环：审计员 <- 高级审计员 <- 首席审计员，再把首席审计员设为审计员的上级
+		w := call(t, adminToken, http.MethodPost, "/api/v1/system/roles", map[string]any{
+			"roleName": "首席审计员",
+			"roleKey":  "chief_auditor",
+			"parentId": senior.RoleID,
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		chief := decodeRole(t, w)
+		w = call(t, adminToken, http.MethodPut, rolePath(auditor.RoleID), map[string]any{"parentId": chief.RoleID})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), role.ErrRoleCycle.Error())
+		assert.Equal(t, http.StatusNoContent, call(t, adminToken, http.MethodDelete, rolePath(chief.RoleID), nil).Code)
+	})
+
+	t.Run("Child Role Receives Parent Permissions", func(t *testing.T) {
+		hash, err := bcrypt.GenerateFromPassword([]byte("senior123"), bcrypt.DefaultCost)
+		assert.NoError(t, err)
+		member := &model.SysUser{UserName: "senior_member", NickName: "senior", Password: string(hash), Status: "0"}
+		assert.NoError(t, app.DB().Create(member).Error)
+		assert.NoError(t, app.DB().Create(&model.SysUserRole{UserID: int64(member.ID), RoleID: senior.RoleID}).Error)
+
+		token := Login(t, app, mr, "senior_member", "senior123")
+		w := call(t, token, http.MethodGet, "/api/v1/auth/me", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res struct {
+			Data struct {
+				Permissions []string `json:"permissions"`
+				Roles       []string `json:"roles"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, []string{"senior_auditor"}, res.Data.Roles)
+		assert.ElementsMatch(t, []string{"system:notice:list", "system:post:list"}, res.Data.Permissions)
+
+		w = call(t, token, http.MethodGet, "/api/v1/auth/menus", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "通知公告")
+		assert.Contains(t, w.Body.String(), "岗位管理")
+
+		assert.Equal(t, http.StatusOK, call(t, token, http.MethodGet, "/api/v1/system/notices", nil).Code)
+	})
+
+	t.Run("Concurrent Parent Changes Cannot Form A Cycle", func(t *testing.T) {
+		var pair [2]role.Role
+		for i := range pair {
+			w := call(t, adminToken, http.MethodPost, "/api/v1/system/roles", map[string]any{
+				"roleName": "并发角色" + strconv.Itoa(i),
+				"roleKey":  "race_role_" + strconv.Itoa(i),
+			})
+			assert.Equal(t, http.StatusCreated, w.Code)
+			pair[i] = decodeRole(t, w)
+		}
+
+		// 两个管理员同时把对方设为上级，最多只能有一个成功
+		codes := make(chan int, len(pair))
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := range pair {
+			wg.Add(1)
+			go func(self, parent role.Role) {
+				defer wg.Done()
+				<-start
+				codes <- call(t, adminToken, http.MethodPut, rolePath(self.RoleID), map[string]any{"parentId": parent.RoleID}).Code
+			}(pair[i], pair[1-i])
+		}
+		close(start)
+		wg.Wait()
+		close(codes)
+
+		succeeded := 0
+		for code := range codes {
+			if code == http.StatusOK {
+				succeeded++
+			}
+		}
+		assert.LessOrEqual(t, succeeded, 1)
+
+		var parents []int64
+		assert.NoError(t, app.DB().Model(&model.SysRole{}).Where("id IN ?", []int64{pair[0].RoleID, pair[1].RoleID}).Order("id").Pluck("parent_id", &parents).Error)
+		assert.False(t, parents[0] == pair[1].RoleID && parents[1] == pair[0].RoleID, "roles must not be each other's parent")
+	})
+
+	t.Run("Parent With Children Cannot Be Deleted", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, call(t, adminToken, http.MethodDelete, rolePath(auditor.RoleID), nil).Code)
+		assert.Equal(t, http.StatusNoContent, call(t, adminToken, http.MethodDelete, rolePath(senior.RoleID), nil).Code)
+		assert.Equal(t, http.StatusNoContent, call(t, adminToken, http.MethodDelete, rolePath(auditor.RoleID), nil).Code)
+
+		// 上级角色已删除时不能恢复下级角色
+		w := call(t, adminToken, http.MethodPost, "/api/v1/system/recycle-bin/role/"+strconv.FormatInt(senior.RoleID, 10)+"/restore", nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,198 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/auth"
+	"github.com/starter-kit-fe/admin/internal/system/role"
+	"github.com/starter-kit-fe/admin/internal/system/user"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRoleInheritance(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "inherit_admin", "admin123")
+	adminToken := Login(t, app, mr, "inherit_admin", "admin123")
+
+	call := func(t *testing.T, token, method, path string, payload any) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			assert.NoError(t, json.NewEncoder(&body).Encode(payload))
+		}
+		req := httptest.NewRequest(method, path, &body)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		unconditional(req)
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	menuID := func(t *testing.T, perms string) int64 {
+		var menu model.SysMenu
+		if err := app.DB().Where("perms = ?", perms).First(&menu).Error; err != nil {
+			t.Fatalf("failed to load menu %s: %v", perms, err)
+		}
+		return int64(menu.ID)
+	}
+
+	createRole := func(t *testing.T, payload map[string]any) role.Role {
+		w := call(t, adminToken, http.MethodPost, "/api/v1/system/roles", payload)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("failed to create role: %d %s", w.Code, w.Body.String())
+		}
+		var res struct {
+			Data role.Role `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data
+	}
+
+	parent := &model.SysDept{DeptName: "继承总部", Ancestors: "0", Status: "0"}
+	assert.NoError(t, app.DB().Create(parent).Error)
+	child := &model.SysDept{
+		DeptName:  "继承分部",
+		ParentID:  int64(parent.ID),
+		Ancestors: "0," + strconv.FormatUint(uint64(parent.ID), 10),
+		Status:    "0",
+	}
+	assert.NoError(t, app.DB().Create(child).Error)
+	post := &model.SysPost{PostCode: "inherit", PostName: "继承岗位", Status: "0"}
+	assert.NoError(t, app.DB().Create(post).Error)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("member123"), bcrypt.DefaultCost)
+	assert.NoError(t, err)
+	deptID := int64(child.ID)
+	member := &model.SysUser{UserName: "inherit_member", NickName: "member", Password: string(hash), Status: "0", DeptID: &deptID}
+	assert.NoError(t, app.DB().Create(member).Error)
+	assert.NoError(t, app.DB().Create(&model.SysUserPost{UserID: int64(member.ID), PostID: int64(post.ID)}).Error)
+
+	// 上级部门绑定但未包含下级，不应被继承
+	createRole(t, map[string]any{
+		"roleName":     "总部专属",
+		"roleKey":      "hq_only",
+		"menuIds":      []int64{menuID(t, "system:config:list")},
+		"deptBindings": []map[string]any{{"deptId": parent.ID, "includeChildren": false}},
+	})
+	deptRole := createRole(t, map[string]any{
+		"roleName":     "总部成员",
+		"roleKey":      "hq_member",
+		"menuIds":      []int64{menuID(t, "system:notice:list")},
+		"deptBindings": []map[string]any{{"deptId": parent.ID, "includeChildren": true}},
+	})
+	postRole := createRole(t, map[string]any{
+		"roleName": "岗位角色",
+		"roleKey":  "post_role",
+		"menuIds":  []int64{menuID(t, "system:post:list")},
+		"postIds":  []int64{int64(post.ID)},
+	})
+	assert.Equal(t, []role.DeptBinding{{DeptID: int64(parent.ID), IncludeChildren: true}}, deptRole.DeptBindings)
+	assert.Equal(t, []int64{int64(post.ID)}, postRole.PostIDs)
+
+	t.Run("Invalid Binding", func(t *testing.T) {
+		w := call(t, adminToken, http.MethodPost, "/api/v1/system/roles", map[string]any{
+			"roleName":     "无效绑定",
+			"roleKey":      "invalid_binding",
+			"deptBindings": []map[string]any{{"deptId": 999999}},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = call(t, adminToken, http.MethodPost, "/api/v1/system/roles", map[string]any{
+			"roleName": "无效绑定",
+			"roleKey":  "invalid_binding",
+			"postIds":  []int64{999999},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Inherited Roles Grant Permissions", func(t *testing.T) {
+		token := Login(t, app, mr, "inherit_member", "member123")
+		w := call(t, token, http.MethodGet, "/api/v1/auth/me", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var res struct {
+			Data struct {
+				Permissions []string `json:"permissions"`
+				Roles       []string `json:"roles"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.ElementsMatch(t, []string{"hq_member", "post_role"}, res.Data.Roles)
+		assert.Contains(t, res.Data.Permissions, "system:notice:list")
+		assert.Contains(t, res.Data.Permissions, "system:post:list")
+		assert.NotContains(t, res.Data.Permissions, "system:config:list")
+
+		assert.Equal(t, http.StatusOK, call(t, token, http.MethodGet, "/api/v1/system/notices", nil).Code)
+		assert.Equal(t, http.StatusForbidden, call(t, token, http.MethodGet, "/api/v1/system/configs", nil).Code)
+	})
+
+	t.Run("User Detail Shows Role Sources", func(t *testing.T) {
+		assert.NoError(t, app.DB().Create(&model.SysUserRole{UserID: int64(member.ID), RoleID: postRole.RoleID}).Error)
+
+		w := call(t, adminToken, http.MethodGet, "/api/v1/system/users/"+strconv.FormatUint(uint64(member.ID), 10), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res struct {
+			Data user.User `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+
+		sources := make(map[string][]auth.RoleSource)
+		for _, item := range res.Data.EffectiveRoles {
+			sources[item.RoleKey] = item.Sources
+		}
+		assert.Len(t, sources, 2)
+		assert.Equal(t, []auth.RoleSource{{Type: auth.RoleSourceDept, ID: int64(parent.ID), Name: "继承总部"}}, sources["hq_member"])
+		assert.Equal(t, []auth.RoleSource{
+			{Type: auth.RoleSourceUser},
+			{Type: auth.RoleSourcePost, ID: int64(post.ID), Name: "继承岗位"},
+		}, sources["post_role"])
+	})
+
+	t.Run("Disabled Post Stops Granting", func(t *testing.T) {
+		assert.NoError(t, app.DB().Where("user_id = ?", member.ID).Delete(&model.SysUserRole{}).Error)
+		assert.NoError(t, app.DB().Model(post).Update("status", "1").Error)
+
+		token := Login(t, app, mr, "inherit_member", "member123")
+		w := call(t, token, http.MethodGet, "/api/v1/auth/me", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "post_role")
+		assert.Contains(t, w.Body.String(), "hq_member")
+	})
+
+	t.Run("Delete Clears Bindings In One Transaction", func(t *testing.T) {
+		doomed := createRole(t, map[string]any{
+			"roleName":     "待删除角色",
+			"roleKey":      "doomed_role",
+			"menuIds":      []int64{menuID(t, "system:post:list")},
+			"deptBindings": []map[string]any{{"deptId": parent.ID, "includeChildren": true}},
+			"postIds":      []int64{int64(post.ID)},
+		})
+		path := "/api/v1/system/roles/" + strconv.FormatInt(doomed.RoleID, 10)
+		bindings := func() (depts, posts, menus int64) {
+			app.DB().Model(&model.SysDeptRole{}).Where("role_id = ?", doomed.RoleID).Count(&depts)
+			app.DB().Model(&model.SysPostRole{}).Where("role_id = ?", doomed.RoleID).Count(&posts)
+			app.DB().Model(&model.SysRoleMenu{}).Where("role_id = ?", doomed.RoleID).Count(&menus)
+			return
+		}
+
+		// 清除菜单授权时失败，角色与其余绑定都应保持原样
+		table := model.SysRoleMenu{}.TableName()
+		assert.NoError(t, app.DB().Exec("CREATE TRIGGER role_menu_failure BEFORE DELETE ON "+table+" BEGIN SELECT RAISE(ABORT, 'disk I/O error'); END").Error)
+		assert.Equal(t, http.StatusInternalServerError, call(t, adminToken, http.MethodDelete, path, nil).Code)
+		assert.NoError(t, app.DB().Exec("DROP TRIGGER role_menu_failure").Error)
+
+		assert.Equal(t, http.StatusOK, call(t, adminToken, http.MethodGet, path, nil).Code)
+		depts, posts, menus := bindings()
+		assert.Equal(t, []int64{1, 1, 1}, []int64{depts, posts, menus})
+
+		assert.Equal(t, http.StatusNoContent, call(t, adminToken, http.MethodDelete, path, nil).Code)
+		depts, posts, menus = bindings()
+		assert.Equal(t, []int64{0, 0, 0}, []int64{depts, posts, menus})
+	})
+}
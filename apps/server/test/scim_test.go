@@ -0,0 +1,213 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/scim"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSCIMProvisioning(t *testing.T) {
+	app, mr := SetupApp(t)
+
+	call := func(t *testing.T, method, path, token string, body any) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			reader = bytes.NewReader(data)
+		}
+		req := httptest.NewRequest(method, scim.BasePath+path, reader)
+		req.Header.Set("Content-Type", scim.ContentType)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	do := func(t *testing.T, method, path string, body any, status int, out any) {
+		w := call(t, method, path, testSCIMToken, body)
+		if !assert.Equal(t, status, w.Code, w.Body.String()) {
+			t.FailNow()
+		}
+		if out != nil {
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), out))
+		}
+	}
+
+	t.Run("Reject Missing Or Wrong Token", func(t *testing.T) {
+		w := call(t, http.MethodGet, "/Users", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), scim.ContentType)
+		assert.Contains(t, w.Body.String(), scim.SchemaError)
+
+		assert.Equal(t, http.StatusUnauthorized, call(t, http.MethodGet, "/Users", "wrong-token", nil).Code)
+
+		// 用户登录令牌不能访问 SCIM 接口
+		CreateUser(t, app, "scim_admin", "admin123")
+		jwt := Login(t, app, mr, "scim_admin", "admin123")
+		assert.Equal(t, http.StatusUnauthorized, call(t, http.MethodGet, "/Users", jwt, nil).Code)
+	})
+
+	var created scim.User
+	t.Run("Joiner", func(t *testing.T) {
+		active := true
+		do(t, http.MethodPost, "/Users", scim.User{
+			Schemas:     []string{scim.SchemaUser, scim.SchemaEnterpriseUser},
+			ExternalID:  "idp-0001",
+			UserName:    "alice",
+			DisplayName: "Alice",
+			Active:      &active,
+			Password:    "secret123",
+			Emails:      []scim.MultiValue{{Value: "alice@corp.example", Type: "work", Primary: true}},
+			Enterprise:  &scim.EnterpriseUser{Department: "研发部门"},
+		}, http.StatusCreated, &created)
+		assert.NotEmpty(t, created.ID)
+		assert.Equal(t, "idp-0001", created.ExternalID)
+		assert.Equal(t, "研发部门", created.Enterprise.Department)
+		assert.Contains(t, created.Groups, scim.MultiValue{Value: "dept-103", Display: "研发部门", Type: scim.GroupTypeDept, Ref: scim.BasePath + "/Groups/dept-103"})
+		assert.Equal(t, scim.BasePath+"/Users/"+created.ID, created.Meta.Location)
+
+		var stored model.SysUser
+		assert.NoError(t, app.DB().Where("user_name = ?", "alice").First(&stored).Error)
+		if assert.NotNil(t, stored.DeptID) {
+			assert.Equal(t, int64(103), *stored.DeptID)
+		}
+		assert.Equal(t, "alice@corp.example", stored.Email)
+
+		// 用户名重复返回 uniqueness
+		w := call(t, http.MethodPost, "/Users", testSCIMToken, scim.User{Schemas: []string{scim.SchemaUser}, UserName: "alice"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "uniqueness")
+	})
+
+	t.Run("Filter And Paginate Users", func(t *testing.T) {
+		var list scim.ListResponse
+		do(t, http.MethodGet, "/Users?filter="+url.QueryEscape(`userName eq "ALICE"`), nil, http.StatusOK, &list)
+		assert.Equal(t, int64(1), list.TotalResults)
+
+		do(t, http.MethodGet, "/Users?filter="+url.QueryEscape(`externalId eq "idp-0001" and active eq true`), nil, http.StatusOK, &list)
+		assert.Equal(t, int64(1), list.TotalResults)
+
+		do(t, http.MethodGet, "/Users?count=0", nil, http.StatusOK, &list)
+		assert.Positive(t, list.TotalResults)
+		assert.Equal(t, 0, list.ItemsPerPage)
+
+		w := call(t, http.MethodGet, "/Users?filter="+url.QueryEscape(`userName eq "a" or userName eq "b"`), testSCIMToken, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalidFilter")
+	})
+
+	t.Run("Mover", func(t *testing.T) {
+		var patched scim.User
+		do(t, http.MethodPatch, "/Users/"+created.ID, scim.PatchRequest{
+			Schemas: []string{scim.SchemaPatchOp},
+			Operations: []scim.PatchOperation{
+				{Op: "Replace", Path: "displayName", Value: "Alice Smith"},
+				{Op: "replace", Path: `emails[type eq "work"].value`, Value: "alice.smith@corp.example"},
+				{Op: "replace", Path: scim.SchemaEnterpriseUser + ":department", Value: "市场部门"},
+			},
+		}, http.StatusOK, &patched)
+		assert.Equal(t, "Alice Smith", patched.DisplayName)
+		assert.Equal(t, "alice.smith@corp.example", patched.Emails[0].Value)
+		assert.Equal(t, "市场部门", patched.Enterprise.Department)
+
+		w := call(t, http.MethodPatch, "/Users/"+created.ID, testSCIMToken, scim.PatchRequest{
+			Operations: []scim.PatchOperation{{Op: "replace", Path: scim.SchemaEnterpriseUser + ":department", Value: "不存在的部门"}},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Group Membership", func(t *testing.T) {
+		var list scim.ListResponse
+		do(t, http.MethodGet, "/Groups?excludedAttributes=members&filter="+url.QueryEscape(`displayName eq "研发部门"`), nil, http.StatusOK, &list)
+		assert.Equal(t, int64(1), list.TotalResults)
+
+		var group scim.Group
+		do(t, http.MethodPost, "/Groups", scim.Group{
+			Schemas:     []string{scim.SchemaGroup},
+			DisplayName: "SCIM 审计员",
+			ExternalID:  "scim_auditor",
+			Members:     []scim.MultiValue{{Value: created.ID}},
+		}, http.StatusCreated, &group)
+		assert.Equal(t, "scim_auditor", group.ExternalID)
+		assert.Len(t, group.Members, 1)
+
+		var role model.SysRole
+		assert.NoError(t, app.DB().Where("role_key = ?", "scim_auditor").First(&role).Error)
+		var count int64
+		assert.NoError(t, app.DB().Model(&model.SysUserRole{}).Where("role_id = ? AND user_id = ?", role.ID, created.ID).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+
+		do(t, http.MethodPatch, "/Groups/"+group.ID, scim.PatchRequest{
+			Operations: []scim.PatchOperation{
+				{Op: "remove", Path: `members[value eq "` + created.ID + `"]`},
+				{Op: "replace", Path: "displayName", Value: "SCIM 审计"},
+			},
+		}, http.StatusOK, &group)
+		assert.Empty(t, group.Members)
+		assert.Equal(t, "SCIM 审计", group.DisplayName)
+
+		// 部门组只能调整成员
+		var dept scim.Group
+		do(t, http.MethodPatch, "/Groups/dept-105", scim.PatchRequest{
+			Operations: []scim.PatchOperation{{Op: "add", Path: "members", Value: []map[string]string{{"value": created.ID}}}},
+		}, http.StatusOK, &dept)
+		assert.Contains(t, dept.Members, scim.MultiValue{Value: created.ID, Display: "alice", Ref: scim.BasePath + "/Users/" + created.ID})
+		var stored model.SysUser
+		assert.NoError(t, app.DB().Where("user_name = ?", "alice").First(&stored).Error)
+		if assert.NotNil(t, stored.DeptID) {
+			assert.Equal(t, int64(105), *stored.DeptID)
+		}
+
+		w := call(t, http.MethodPatch, "/Groups/dept-105", testSCIMToken, scim.PatchRequest{
+			Operations: []scim.PatchOperation{{Op: "replace", Path: "displayName", Value: "改名"}},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "mutability")
+
+		w = call(t, http.MethodPatch, "/Groups/dept-105", testSCIMToken, scim.PatchRequest{
+			Operations: []scim.PatchOperation{{Op: "add", Path: "members", Value: []map[string]string{{"value": "999999"}}}},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		do(t, http.MethodDelete, "/Groups/"+group.ID, nil, http.StatusNoContent, nil)
+		assert.Equal(t, http.StatusNotFound, call(t, http.MethodGet, "/Groups/"+group.ID, testSCIMToken, nil).Code)
+		assert.Equal(t, http.StatusBadRequest, call(t, http.MethodDelete, "/Groups/dept-105", testSCIMToken, nil).Code)
+	})
+
+	t.Run("Leaver", func(t *testing.T) {
+		jwt := Login(t, app, mr, "alice", "secret123")
+		me := func() int {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+			req.Header.Set("Authorization", "Bearer "+jwt)
+			w := httptest.NewRecorder()
+			app.Handler().ServeHTTP(w, req)
+			return w.Code
+		}
+		assert.Equal(t, http.StatusOK, me())
+
+		var patched scim.User
+		do(t, http.MethodPatch, "/Users/"+created.ID, scim.PatchRequest{
+			Operations: []scim.PatchOperation{{Op: "replace", Value: map[string]any{"active": "False"}}},
+		}, http.StatusOK, &patched)
+		if assert.NotNil(t, patched.Active) {
+			assert.False(t, *patched.Active)
+		}
+		// 停用后已签发的会话立即失效
+		assert.Equal(t, http.StatusUnauthorized, me())
+
+		do(t, http.MethodDelete, "/Users/"+created.ID, nil, http.StatusNoContent, nil)
+		assert.Equal(t, http.StatusNotFound, call(t, http.MethodGet, "/Users/"+created.ID, testSCIMToken, nil).Code)
+	})
+}
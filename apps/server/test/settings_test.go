@@ -0,0 +1,78 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/starter-kit-fe/admin/internal/system/settings"
+)
+
+func TestRuntimeSettings(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "settings_admin", "admin123")
+	token := Login(t, app, mr, "settings_admin", "admin123")
+
+	updateConfig := func(t *testing.T, id, value string) {
+		body, _ := json.Marshal(map[string]string{"configValue": value})
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/system/configs/"+id, bytes.NewReader(body))
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	loginWithoutCaptcha := func() int {
+		body, _ := json.Marshal(map[string]string{"username": "settings_admin", "password": "admin123"})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("Captcha Toggle Applies Without Restart", func(t *testing.T) {
+		assert.NotEqual(t, http.StatusOK, loginWithoutCaptcha(), "captcha is enabled by seed data")
+
+		updateConfig(t, "4", "false")
+		assert.Equal(t, http.StatusOK, loginWithoutCaptcha())
+
+		updateConfig(t, "4", "true")
+		assert.NotEqual(t, http.StatusOK, loginWithoutCaptcha())
+	})
+
+	t.Run("Changes Reach Other Replicas", func(t *testing.T) {
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+
+		replica := settings.NewService(settings.NewRepository(app.DB()), client, settings.Options{})
+		require.NoError(t, replica.Start(context.Background()))
+		t.Cleanup(replica.Stop)
+
+		var notified atomic.Int32
+		replica.Subscribe("sys.account.passwordValidateDays", func(ctx context.Context, change settings.Change) {
+			notified.Add(1)
+		})
+
+		ctx := context.Background()
+		require.Equal(t, 0, replica.GetInt(ctx, "sys.account.passwordValidateDays", -1))
+
+		updateConfig(t, "8", "90")
+		require.Eventually(t, func() bool {
+			return notified.Load() == 1
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.Equal(t, 90, replica.GetInt(ctx, "sys.account.passwordValidateDays", -1))
+		assert.Equal(t, -1, replica.GetInt(ctx, "sys.missing.key", -1))
+	})
+}
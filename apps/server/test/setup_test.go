@@ -2,6 +2,8 @@ package test
 
 import (
 	"context"
+	"net/http"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -9,9 +11,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/starter-kit-fe/admin/internal/app"
 	"github.com/starter-kit-fe/admin/internal/config"
+	"github.com/starter-kit-fe/admin/pkg/etag"
 )
 
-// SetupApp initializes the application for testing with in-memory SQLite and Miniredis
+// testSCIMToken 测试环境中身份源使用的 SCIM 令牌
+const testSCIMToken = "test-scim-token"
+
+// testConfigKey 用于加密 secret 类型参数的测试密钥
+const testConfigKey = "k1:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+// SetupApp initializes the application for testing with SQLite and Miniredis
 func SetupApp(t *testing.T) (*app.App, *miniredis.Miniredis) {
 	gin.SetMode(gin.TestMode)
 
@@ -37,9 +46,11 @@ func SetupApp(t *testing.T) (*app.App, *miniredis.Miniredis) {
 		Log: config.LogConfig{
 			Level: "error", // Reduce noise during tests
 		},
+		// 使用临时文件而非 :memory:，连接池中的每个连接都会看到同一个数据库，
+		// 定时任务等后台读写不会落到新的空库上
 		Database: config.DatabaseConfig{
 			Driver: "sqlite",
-			DSN:    ":memory:",
+			DSN:    "file:" + filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=5000&_journal_mode=WAL",
 		},
 		Redis: config.RedisConfig{
 			URL: "redis://" + mr.Addr(),
@@ -52,7 +63,14 @@ func SetupApp(t *testing.T) (*app.App, *miniredis.Miniredis) {
 			CookieName:      "auth_token",
 			RefreshCookie:   "refresh_token",
 		},
+		Storage: config.StorageConfig{
+			LocalDir: t.TempDir(),
+		},
+		SCIM: config.SCIMConfig{
+			Tokens: []string{testSCIMToken},
+		},
 		Security: config.SecurityConfig{
+			ConfigKeys: []string{testConfigKey},
 			RateLimit: config.RateLimitConfig{
 				Requests: 100,
 				Burst:    100,
@@ -70,3 +88,14 @@ func SetupApp(t *testing.T) (*app.App, *miniredis.Miniredis) {
 
 	return application, mr
 }
+
+// unconditional 为未携带 If-Match 的写请求补上 "If-Match: *"，
+// 供不关注并发覆盖的用例使用
+func unconditional(req *http.Request) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return
+	}
+	if req.Header.Get(etag.HeaderIfMatch) == "" {
+		req.Header.Set(etag.HeaderIfMatch, "*")
+	}
+}
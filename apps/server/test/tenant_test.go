@@ -0,0 +1,198 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/file"
+	"github.com/starter-kit-fe/admin/internal/system/post"
+	"github.com/starter-kit-fe/admin/internal/system/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiTenant(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "platform_admin", "admin123")
+	platformToken := Login(t, app, mr, "platform_admin", "admin123")
+
+	call := func(token, method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body *bytes.Reader
+		if payload != nil {
+			raw, _ := json.Marshal(payload)
+			body = bytes.NewReader(raw)
+		} else {
+			body = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, body)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	listPosts := func(token string) []post.Post {
+		w := call(token, http.MethodGet, "/api/v1/system/posts?pageSize=100", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data post.ListResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data.List
+	}
+
+	w := call(platformToken, http.MethodPost, "/api/v1/system/tenants", map[string]string{
+		"tenantCode":    "acme",
+		"tenantName":    "Acme",
+		"adminPassword": "acme123",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data tenant.CreateResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.Data.Tenant)
+	assert.Equal(t, "admin", created.Data.AdminUserName)
+	assert.Equal(t, "acme123", created.Data.AdminPassword)
+	acmeID := created.Data.Tenant.TenantID
+	acmePath := "/api/v1/system/tenants/" + strconv.FormatInt(acmeID, 10)
+
+	t.Run("Duplicate Code Rejected", func(t *testing.T) {
+		w := call(platformToken, http.MethodPost, "/api/v1/system/tenants", map[string]string{
+			"tenantCode": "acme",
+			"tenantName": "Acme Again",
+		})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	acmeToken := LoginTenant(t, app, mr, "acme", "admin", "acme123")
+
+	t.Run("Seeded Defaults Are Isolated", func(t *testing.T) {
+		acmePosts := listPosts(acmeToken)
+		platformPosts := listPosts(platformToken)
+		assert.NotEmpty(t, acmePosts)
+		assert.Len(t, acmePosts, len(platformPosts))
+
+		w := call(acmeToken, http.MethodPost, "/api/v1/system/posts", map[string]interface{}{
+			"postCode": "acme_only",
+			"postName": "Acme 专属岗位",
+			"postSort": 9,
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		assert.Len(t, listPosts(acmeToken), len(platformPosts)+1)
+		assert.Len(t, listPosts(platformToken), len(platformPosts))
+
+		var stored model.SysPost
+		require.NoError(t, app.DB().Where("post_code = ?", "acme_only").First(&stored).Error)
+		assert.Equal(t, acmeID, stored.TenantID)
+	})
+
+	t.Run("Dictionary Types Are Unique Per Tenant", func(t *testing.T) {
+		payload := map[string]string{"dictName": "探针", "dictType": "tenant_probe"}
+		assert.Equal(t, http.StatusCreated, call(platformToken, http.MethodPost, "/api/v1/system/dicts", payload).Code)
+		assert.Equal(t, http.StatusCreated, call(acmeToken, http.MethodPost, "/api/v1/system/dicts", payload).Code)
+		assert.Equal(t, http.StatusConflict, call(acmeToken, http.MethodPost, "/api/v1/system/dicts", payload).Code)
+	})
+
+	t.Run("Files Are Isolated", func(t *testing.T) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "acme.txt")
+		require.NoError(t, err)
+		_, _ = part.Write([]byte("acme secret"))
+		require.NoError(t, form.WriteField("module", "docs"))
+		require.NoError(t, form.Close())
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/system/files", &body)
+		req.Header.Set("Authorization", "Bearer "+acmeToken)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var uploaded struct {
+			Data file.File `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &uploaded))
+		filePath := "/api/v1/system/files/" + strconv.FormatInt(uploaded.Data.FileID, 10)
+
+		var stored model.SysFile
+		require.NoError(t, app.DB().First(&stored, uploaded.Data.FileID).Error)
+		assert.Equal(t, acmeID, stored.TenantID)
+
+		listFiles := func(token string) []file.File {
+			w := call(token, http.MethodGet, "/api/v1/system/files?pageSize=100", nil)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var res struct {
+				Data file.ListResult `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			return res.Data.List
+		}
+		assert.Len(t, listFiles(acmeToken), 1)
+		for _, item := range listFiles(platformToken) {
+			assert.NotEqual(t, uploaded.Data.FileID, item.FileID)
+		}
+
+		assert.Equal(t, http.StatusNotFound, call(platformToken, http.MethodGet, filePath, nil).Code)
+		assert.Equal(t, http.StatusNotFound, call(platformToken, http.MethodGet, filePath+"/download", nil).Code)
+		assert.Equal(t, http.StatusNotFound, call(platformToken, http.MethodDelete, filePath, nil).Code)
+		assert.Equal(t, http.StatusOK, call(acmeToken, http.MethodGet, filePath+"/download", nil).Code)
+	})
+
+	t.Run("Tenant Admin Cannot Manage Tenants", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, call(acmeToken, http.MethodGet, "/api/v1/system/tenants", nil).Code)
+		assert.Equal(t, http.StatusForbidden, call(acmeToken, http.MethodPost, acmePath+"/switch", nil).Code)
+	})
+
+	t.Run("Platform Admin Switches Tenant", func(t *testing.T) {
+		w := call(platformToken, http.MethodPost, acmePath+"/switch", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var res struct {
+			Data struct {
+				TenantID    int64  `json:"tenant_id"`
+				AccessToken string `json:"access_token"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, acmeID, res.Data.TenantID)
+		require.NotEmpty(t, res.Data.AccessToken)
+
+		// 原令牌签发给默认租户，切换后失效
+		assert.Equal(t, http.StatusUnauthorized, call(platformToken, http.MethodGet, "/api/v1/system/posts", nil).Code)
+
+		switched := res.Data.AccessToken
+		assert.Len(t, listPosts(switched), len(listPosts(acmeToken)))
+
+		w = call(switched, http.MethodPost, "/api/v1/system/tenants/1/switch", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		platformToken = res.Data.AccessToken
+		assert.Equal(t, http.StatusOK, call(platformToken, http.MethodGet, "/api/v1/system/tenants", nil).Code)
+	})
+
+	t.Run("Default Tenant Is Protected", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, call(platformToken, http.MethodDelete, "/api/v1/system/tenants/1", nil).Code)
+		assert.Equal(t, http.StatusBadRequest, call(platformToken, http.MethodPut, "/api/v1/system/tenants/1", map[string]string{"status": "1"}).Code)
+	})
+
+	t.Run("Disabled And Unknown Tenants Cannot Log In", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, loginRequest(app, mr, "missing", "admin", "acme123").Code)
+
+		w := call(platformToken, http.MethodPut, acmePath, map[string]string{"status": "1"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, http.StatusForbidden, loginRequest(app, mr, "acme", "admin", "acme123").Code)
+
+		require.Equal(t, http.StatusOK, call(platformToken, http.MethodPut, acmePath, map[string]string{"status": "0"}).Code)
+		assert.Equal(t, http.StatusNoContent, call(platformToken, http.MethodDelete, acmePath, nil).Code)
+		assert.Equal(t, http.StatusForbidden, loginRequest(app, mr, "acme", "admin", "acme123").Code)
+	})
+}
@@ -0,0 +1,204 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/system/user"
+	"github.com/starter-kit-fe/admin/internal/system/userattr"
+	"github.com/starter-kit-fe/admin/pkg/spreadsheet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserAttributes(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "attr_admin", "admin123")
+	token := Login(t, app, mr, "attr_admin", "admin123")
+
+	call := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		unconditional(req)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		return w
+	}
+	decodeUser := func(w *httptest.ResponseRecorder) user.User {
+		var res struct {
+			Data user.User `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Data
+	}
+
+	w := call(http.MethodPost, "/api/v1/system/user-attributes", map[string]interface{}{
+		"attrKey":   "employeeNo",
+		"attrLabel": "工号",
+		"required":  true,
+		"pattern":   `^E\d{4}$`,
+		"sortOrder": 1,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = call(http.MethodPost, "/api/v1/system/user-attributes", map[string]interface{}{
+		"attrKey":   "gender",
+		"attrLabel": "登记性别",
+		"attrType":  "select",
+		"dictType":  "sys_user_sex",
+		"sortOrder": 2,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var level struct {
+		Data userattr.Definition `json:"data"`
+	}
+	w = call(http.MethodPost, "/api/v1/system/user-attributes", map[string]interface{}{
+		"attrKey":   "level",
+		"attrLabel": "职级",
+		"attrType":  "number",
+		"minValue":  1,
+		"maxValue":  10,
+		"sortOrder": 3,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &level))
+
+	t.Run("Definition Validation", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, call(http.MethodPost, "/api/v1/system/user-attributes", map[string]interface{}{
+			"attrKey": "employeeNo", "attrLabel": "重复",
+		}).Code)
+		assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, "/api/v1/system/user-attributes", map[string]interface{}{
+			"attrKey": "badPattern", "attrLabel": "错误", "pattern": "(",
+		}).Code)
+		assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, "/api/v1/system/user-attributes", map[string]interface{}{
+			"attrKey": "missingDict", "attrLabel": "错误", "attrType": "select", "dictType": "no_such_dict",
+		}).Code)
+
+		w := call(http.MethodGet, "/api/v1/system/user-attributes", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var res struct {
+			Data []userattr.Definition `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		require.Len(t, res.Data, 3)
+		assert.Equal(t, "employeeNo", res.Data[0].AttrKey)
+		assert.NotEmpty(t, res.Data[1].Options, "select attributes carry dictionary options")
+	})
+
+	var aliceID int64
+	t.Run("Create And Update User Attributes", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"userName": "attr_alice",
+			"nickName": "Alice",
+			"password": "secret123",
+		}
+		w := call(http.MethodPost, "/api/v1/system/users", payload)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "required attribute missing")
+
+		payload["attributes"] = map[string]string{"employeeNo": "X1"}
+		w = call(http.MethodPost, "/api/v1/system/users", payload)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "pattern mismatch")
+
+		payload["attributes"] = map[string]string{"employeeNo": "E0001", "unknown": "x"}
+		w = call(http.MethodPost, "/api/v1/system/users", payload)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "unknown attribute")
+
+		payload["attributes"] = map[string]string{"employeeNo": "E0001", "gender": "女", "level": "3.0"}
+		w = call(http.MethodPost, "/api/v1/system/users", payload)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		created := decodeUser(w)
+		aliceID = created.UserID
+		assert.Equal(t, map[string]string{"employeeNo": "E0001", "gender": "1", "level": "3"}, created.Attributes)
+
+		path := "/api/v1/system/users/" + strconv.FormatInt(aliceID, 10)
+		assert.Equal(t, http.StatusBadRequest, call(http.MethodPut, path, map[string]interface{}{
+			"attributes": map[string]string{"level": "11"},
+		}).Code)
+		assert.Equal(t, http.StatusBadRequest, call(http.MethodPut, path, map[string]interface{}{
+			"attributes": map[string]string{"employeeNo": ""},
+		}).Code, "required attribute cannot be cleared")
+
+		w = call(http.MethodPut, path, map[string]interface{}{
+			"attributes": map[string]string{"level": "5", "gender": ""},
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, map[string]string{"employeeNo": "E0001", "level": "5"}, decodeUser(w).Attributes)
+	})
+
+	t.Run("Filter List By Attribute", func(t *testing.T) {
+		w := call(http.MethodPost, "/api/v1/system/users", map[string]interface{}{
+			"userName":   "attr_bob",
+			"nickName":   "Bob",
+			"password":   "secret123",
+			"attributes": map[string]string{"employeeNo": "E0002", "level": "5"},
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		list := func(query url.Values) user.ListResult {
+			w := call(http.MethodGet, "/api/v1/system/users?"+query.Encode(), nil)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var res struct {
+				Data user.ListResult `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			return res.Data
+		}
+
+		assert.Equal(t, int64(2), list(url.Values{"attrs[level]": {"5"}}).Total)
+		result := list(url.Values{"attrs[level]": {"5.0"}, "attrs[employeeNo]": {"E0001"}})
+		require.Equal(t, int64(1), result.Total)
+		assert.Equal(t, aliceID, result.List[0].UserID)
+
+		w = call(http.MethodGet, "/api/v1/system/users?attrs%5Bmissing%5D=1", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Export And Import Attribute Columns", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/system/users/export?format=csv&attrs%5BemployeeNo%5D=E0001", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		app.Handler().ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		rows, err := spreadsheet.ReadAll(spreadsheet.FormatCSV, bytes.NewReader(w.Body.Bytes()), 0)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		header := rows[0]
+		assert.Equal(t, []string{"工号", "登记性别", "职级"}, header[len(header)-3:])
+		assert.Equal(t, []string{"E0001", "", "5"}, rows[1][len(rows[1])-3:])
+
+		content := "用户名称*,用户昵称*,工号,登记性别\n" +
+			"attr_carol,Carol,E0003,男\n" +
+			"attr_dave,Dave,,女\n"
+		w = postImport(t, app, token, content, map[string]string{"defaultPassword": "secret123", "dryRun": "false", "mode": "batch"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		result := decodeImportResult(t, w)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, user.ImportRowSkipped, result.Rows[1].Status)
+
+		w = call(http.MethodGet, "/api/v1/system/users/"+strconv.FormatInt(result.Rows[0].UserID, 10), nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, map[string]string{"employeeNo": "E0003", "gender": "0"}, decodeUser(w).Attributes)
+
+		w = postImport(t, app, token, "用户名称*,用户昵称*\nattr_erin,Erin\n", map[string]string{"defaultPassword": "secret123"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, "required attribute column missing")
+	})
+
+	t.Run("Delete Definition Removes Values", func(t *testing.T) {
+		path := "/api/v1/system/user-attributes/" + strconv.FormatInt(level.Data.ID, 10)
+		require.Equal(t, http.StatusNoContent, call(http.MethodDelete, path, nil).Code)
+		assert.Equal(t, http.StatusNotFound, call(http.MethodGet, path, nil).Code)
+
+		w := call(http.MethodGet, "/api/v1/system/users/"+strconv.FormatInt(aliceID, 10), nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, map[string]string{"employeeNo": "E0001"}, decodeUser(w).Attributes)
+	})
+}
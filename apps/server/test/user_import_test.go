@@ -0,0 +1,133 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/starter-kit-fe/admin/internal/app"
+	"github.com/starter-kit-fe/admin/internal/model"
+	"github.com/starter-kit-fe/admin/internal/system/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserImport(t *testing.T) {
+	app, mr := SetupApp(t)
+	CreateUser(t, app, "import_admin", "admin123")
+	token := Login(t, app, mr, "import_admin", "admin123")
+
+	content := "用户名称*,用户昵称*,部门,角色标识,岗位编码,性别,状态\n" +
+		"import_alice,Alice,总部/研发部门,admin,ceo,女,正常\n" +
+		"import_bob,Bob,研发部门,common,user,男,停用\n" +
+		"import_alice,Alice Again,,,,,\n" +
+		"import_carol,Carol,不存在的部门,unknown,,,\n"
+
+	t.Run("Dry Run Reports Row Errors", func(t *testing.T) {
+		w := postImport(t, app, token, content, map[string]string{"defaultPassword": "secret123"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		result := decodeImportResult(t, w)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 4, result.Total)
+		assert.Equal(t, 2, result.Valid)
+		assert.Equal(t, 2, result.Invalid)
+		assert.Equal(t, 0, result.Created)
+		assert.Equal(t, user.ImportRowValid, result.Rows[0].Status)
+		assert.Equal(t, 4, result.Rows[2].Row)
+		assert.Equal(t, user.ImportRowInvalid, result.Rows[2].Status)
+		assert.Len(t, result.Rows[3].Errors, 2)
+	})
+
+	t.Run("Atomic Import Rejects Invalid File", func(t *testing.T) {
+		w := postImport(t, app, token, content, map[string]string{"dryRun": "false", "defaultPassword": "secret123"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		result := decodeImportResult(t, w)
+		assert.Equal(t, 0, result.Created)
+	})
+
+	t.Run("Batch Import Skips Invalid Rows", func(t *testing.T) {
+		w := postImport(t, app, token, content, map[string]string{"dryRun": "false", "mode": "batch", "defaultPassword": "secret123"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		result := decodeImportResult(t, w)
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, user.ImportRowCreated, result.Rows[1].Status)
+		assert.NotZero(t, result.Rows[1].UserID)
+		assert.Equal(t, user.ImportRowSkipped, result.Rows[3].Status)
+
+		// 已导入的用户可以使用默认密码登录
+		assert.NotEmpty(t, Login(t, app, mr, "import_alice", "secret123"))
+
+		// 相同的默认密码只计算一次哈希
+		var hashes []string
+		require.NoError(t, app.DB().Model(&model.SysUser{}).Where("user_name IN ?", []string{"import_alice", "import_bob"}).Pluck("password", &hashes).Error)
+		require.Len(t, hashes, 2)
+		assert.Equal(t, hashes[0], hashes[1])
+	})
+
+	t.Run("Import Surfaces Database Errors", func(t *testing.T) {
+		// 写入岗位关联时模拟数据库故障，这不是某一行数据的问题
+		table := model.SysUserPost{}.TableName()
+		require.NoError(t, app.DB().Exec("CREATE TRIGGER import_post_failure BEFORE INSERT ON "+table+" BEGIN SELECT RAISE(ABORT, 'disk I/O error'); END").Error)
+		defer app.DB().Exec("DROP TRIGGER import_post_failure")
+
+		content := "用户名称*,用户昵称*,部门,角色标识,岗位编码,性别,状态\n" +
+			"import_dave,Dave,研发部门,common,user,男,正常\n"
+		w := postImport(t, app, token, content, map[string]string{"dryRun": "false", "defaultPassword": "secret123"})
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		// 分批导入也不把数据库故障记为行错误
+		w = postImport(t, app, token, content, map[string]string{"dryRun": "false", "mode": "batch", "defaultPassword": "secret123"})
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var count int64
+		app.DB().Model(&model.SysUser{}).Where("user_name = ?", "import_dave").Count(&count)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Download Template", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/system/users/import/template?format=xlsx", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		app.Handler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "user_import_template.xlsx")
+		assert.NotZero(t, w.Body.Len())
+	})
+}
+
+func postImport(t *testing.T, a *app.App, token, content string, fields map[string]string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "users.csv")
+	assert.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	assert.NoError(t, err)
+	for key, value := range fields {
+		assert.NoError(t, form.WriteField(key, value))
+	}
+	assert.NoError(t, form.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/system/users/import", &body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	a.Handler().ServeHTTP(w, req)
+	return w
+}
+
+func decodeImportResult(t *testing.T, w *httptest.ResponseRecorder) user.ImportResult {
+	var res struct {
+		Data user.ImportResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return res.Data
+}